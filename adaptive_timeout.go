@@ -0,0 +1,129 @@
+package legotoolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/propagation"
+	"lego-toolbox/yamlconfig"
+)
+
+const (
+	defaultAdaptiveTimeoutFactor     = 1.5
+	defaultAdaptiveTimeoutMinSamples = 10
+)
+
+// adaptiveTimeoutConfig is the part of the provider configs deriving the propagation timeout from the measured latencies.
+//
+//	adaptiveTimeout:
+//	  min: 30s
+//	  max: 10m
+type adaptiveTimeoutConfig struct {
+	AdaptiveTimeout *AdaptiveTimeout `yaml:"adaptiveTimeout"`
+}
+
+// AdaptiveTimeout configures WithAdaptiveTimeout.
+type AdaptiveTimeout struct {
+	// Min is the lower bound of the timeout, the polling interval of the provider when zero.
+	Min time.Duration `yaml:"min"`
+	// Max is the upper bound of the timeout, the propagation timeout of the provider when zero.
+	Max time.Duration `yaml:"max"`
+	// Factor is the margin applied to the p95 latency, 1.5 when zero.
+	Factor float64 `yaml:"factor"`
+	// MinSamples is the number of latencies measured before the timeout adapts, 10 when zero.
+	// The propagation timeout of the provider is used until then.
+	MinSamples int `yaml:"minSamples"`
+}
+
+// WithAdaptiveTimeout derives the propagation timeout of the provider from its recent p95 propagation latency,
+// measured by the collector for name (see WithLatency), times a margin and bounded by the min and max of the config.
+// The propagation timeout of the provider is used until enough latencies are measured.
+// Across a large fleet, it reduces the worst-case issuance latency of the providers propagating faster than their static timeout.
+// The polling interval and sequential behavior of the provider are preserved.
+func WithAdaptiveTimeout(provider challenge.Provider, name string, latency *propagation.Latency, config AdaptiveTimeout) challenge.Provider {
+	timeout, interval := providerTimeout(provider)
+
+	if config.Min <= 0 {
+		config.Min = interval
+	}
+
+	if config.Max <= 0 {
+		config.Max = timeout
+	}
+
+	if config.Factor <= 0 {
+		config.Factor = defaultAdaptiveTimeoutFactor
+	}
+
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultAdaptiveTimeoutMinSamples
+	}
+
+	return decorate(&adaptiveTimeoutProvider{Provider: provider, name: name, latency: latency, config: config})
+}
+
+// withAdaptiveTimeout wraps the provider with WithAdaptiveTimeout when `adaptiveTimeout` is set in the provider config.
+// The latencies are measured by the collector set with SetLatencyRecorder.
+func withAdaptiveTimeout(rawConfig []byte, name string, provider challenge.Provider) (challenge.Provider, error) {
+	var config adaptiveTimeoutConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("adaptiveTimeout: %w", err)
+	}
+
+	if config.AdaptiveTimeout == nil {
+		return provider, nil
+	}
+
+	if config.AdaptiveTimeout.Max > 0 && config.AdaptiveTimeout.Min > config.AdaptiveTimeout.Max {
+		return nil, fmt.Errorf("adaptiveTimeout: min (%s) greater than max (%s)", config.AdaptiveTimeout.Min, config.AdaptiveTimeout.Max)
+	}
+
+	latency := LatencyRecorder()
+	if latency == nil {
+		return nil, errors.New("adaptiveTimeout: no latency recorder, see SetLatencyRecorder")
+	}
+
+	return WithAdaptiveTimeout(provider, name, latency, *config.AdaptiveTimeout), nil
+}
+
+type adaptiveTimeoutProvider struct {
+	challenge.Provider
+
+	name    string
+	latency *propagation.Latency
+	config  AdaptiveTimeout
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *adaptiveTimeoutProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *adaptiveTimeoutProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout derived from the p95 propagation latency, and the interval of the provider.
+func (p *adaptiveTimeoutProvider) Timeout() (timeout, interval time.Duration) {
+	timeout, interval = providerTimeout(p.Provider)
+
+	stats, ok := p.latency.Snapshot()[p.name]
+	if !ok || stats.Count < uint64(p.config.MinSamples) {
+		return timeout, interval
+	}
+
+	timeout = time.Duration(float64(stats.P95) * p.config.Factor)
+
+	return min(max(timeout, p.config.Min), p.config.Max), interval
+}
+
+// Unwrap returns the wrapped provider.
+func (p *adaptiveTimeoutProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
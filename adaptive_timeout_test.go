@@ -0,0 +1,102 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/propagation"
+)
+
+func TestWithAdaptiveTimeout(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    AdaptiveTimeout
+		latencies []time.Duration
+		expected  time.Duration
+	}{
+		{
+			desc:      "not enough samples",
+			config:    AdaptiveTimeout{MinSamples: 3},
+			latencies: []time.Duration{time.Second, time.Second},
+			expected:  5 * time.Minute,
+		},
+		{
+			desc:      "p95 with margin",
+			config:    AdaptiveTimeout{MinSamples: 3},
+			latencies: []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second},
+			expected:  time.Minute,
+		},
+		{
+			desc:      "factor",
+			config:    AdaptiveTimeout{MinSamples: 3, Factor: 2},
+			latencies: []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second},
+			expected:  80 * time.Second,
+		},
+		{
+			desc:      "min",
+			config:    AdaptiveTimeout{Min: 30 * time.Second, MinSamples: 3},
+			latencies: []time.Duration{time.Second, time.Second, time.Second},
+			expected:  30 * time.Second,
+		},
+		{
+			desc:      "default min: polling interval",
+			config:    AdaptiveTimeout{MinSamples: 3},
+			latencies: []time.Duration{time.Second, time.Second, time.Second},
+			expected:  10 * time.Second,
+		},
+		{
+			desc:      "default max: provider timeout",
+			config:    AdaptiveTimeout{MinSamples: 3},
+			latencies: []time.Duration{time.Hour, time.Hour, time.Hour},
+			expected:  5 * time.Minute,
+		},
+		{
+			desc:      "max",
+			config:    AdaptiveTimeout{Max: 2 * time.Minute, MinSamples: 3},
+			latencies: []time.Duration{time.Hour, time.Hour, time.Hour},
+			expected:  2 * time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			inner, err := NewDNSChallengeProviderByName("fake", []byte("propagationTimeout: 5m\npollingInterval: 10s\n"))
+			require.NoError(t, err)
+
+			latency := propagation.NewLatency(0)
+			for _, l := range test.latencies {
+				latency.Observe("fake", l)
+			}
+
+			provider := WithAdaptiveTimeout(inner, "fake", latency, test.config)
+
+			timeout, interval := providerTimeout(provider)
+			assert.Equal(t, test.expected, timeout)
+			assert.Equal(t, 10*time.Second, interval)
+		})
+	}
+}
+
+func TestNewDNSChallengeProviderByName_adaptiveTimeout(t *testing.T) {
+	rawConfig := []byte("propagationTimeout: 10m\nadaptiveTimeout:\n  min: 1m\n  minSamples: 1\n")
+
+	_, err := NewDNSChallengeProviderByName("fake", rawConfig)
+	require.EqualError(t, err, "adaptiveTimeout: no latency recorder, see SetLatencyRecorder")
+
+	latency := propagation.NewLatency(0)
+	latency.Observe("fake@adaptive", 10*time.Second)
+
+	SetLatencyRecorder(latency)
+	t.Cleanup(func() { SetLatencyRecorder(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("fake@adaptive", rawConfig)
+	require.NoError(t, err)
+
+	timeout, _ := providerTimeout(provider)
+	assert.Equal(t, time.Minute, timeout)
+
+	_, err = NewDNSChallengeProviderByName("fake", []byte("adaptiveTimeout:\n  min: 5m\n  max: 1m\n"))
+	require.EqualError(t, err, "adaptiveTimeout: min (5m0s) greater than max (1m0s)")
+}
@@ -0,0 +1,116 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/log"
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/events"
+)
+
+// azureDNSOnlyKeys are the config keys of azuredns unknown to the legacy azure provider.
+// A legacy azure config using one of them has been written for azuredns.
+var azureDNSOnlyKeys = []string{
+	"environmentName",
+	"OIDCToken",
+	"OIDCTokenFilePath",
+	"OIDCRequestURL",
+	"OIDCRequestToken",
+	"authMethod",
+	"authMSITimeout",
+	"serviceDiscoveryFilter",
+	"zoneResourceGroups",
+}
+
+// azureEnvironments maps the resource manager endpoints of the legacy azure provider to the azuredns environment names.
+var azureEnvironments = map[string]string{
+	"https://management.azure.com":         "public",
+	"https://management.chinacloudapi.cn":  "china",
+	"https://management.usgovcloudapi.net": "usgovernment",
+}
+
+// migrateAzure returns the provider name to use for the config:
+// azuredns when the legacy azure provider is given a config with azuredns fields, the name unchanged otherwise.
+// The migration is logged and published to the event bus, if any, as a ProviderDeprecated event.
+func migrateAzure(id, providerName string, rawConfig []byte) string {
+	if providerName != "azure" || !hasAzureDNSKeys(rawConfig) {
+		return providerName
+	}
+
+	log.Warnf("azure: the config has azuredns fields, the azuredns provider is used (see ConvertAzureConfig)")
+
+	EventBus().Publish(events.Event{Type: events.ProviderDeprecated, Provider: id, Replacement: "azuredns"})
+
+	return "azuredns"
+}
+
+// hasAzureDNSKeys reports whether the YAML config has one of the azuredns only keys.
+func hasAzureDNSKeys(rawConfig []byte) bool {
+	var config map[string]any
+
+	// an invalid config is reported by the provider.
+	if err := yaml.Unmarshal(rawConfig, &config); err != nil {
+		return false
+	}
+
+	for _, key := range azureDNSOnlyKeys {
+		if _, ok := config[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConvertAzureConfig converts a YAML config of the deprecated azure provider to an azuredns config.
+// The order of the keys and the comments are preserved, the other keys (e.g. the common keys) are kept unchanged.
+// The resourceManagerEndpoint is converted to the environmentName,
+// the metadataEndpoint and the activeDirectoryEndpoint are dropped: azuredns derives them from the environment.
+// The endpoints without azuredns environment (e.g. the German cloud) are rejected.
+func ConvertAzureConfig(old []byte) ([]byte, error) {
+	var doc yaml.Node
+
+	err := yaml.Unmarshal(old, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("azure: %w", err)
+	}
+
+	if doc.Kind == 0 {
+		return []byte{}, nil
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("azure: the config must be a mapping")
+	}
+
+	root := doc.Content[0]
+
+	var content []*yaml.Node
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		switch key.Value {
+		case "metadataEndpoint", "activeDirectoryEndpoint":
+			continue
+
+		case "resourceManagerEndpoint":
+			environment, ok := azureEnvironments[strings.TrimSuffix(value.Value, "/")]
+			if !ok {
+				return nil, fmt.Errorf("azure: no azuredns environment for the resource manager endpoint %q", value.Value)
+			}
+
+			key.Value = "environmentName"
+			value.Value = environment
+			value.Tag = "!!str"
+		}
+
+		content = append(content, key, value)
+	}
+
+	root.Content = content
+
+	return yaml.Marshal(&doc)
+}
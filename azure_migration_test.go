@@ -0,0 +1,143 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/events"
+	"lego-toolbox/providers/dns/azuredns"
+)
+
+func TestNewDNSChallengeProviderByName_azureMigration(t *testing.T) {
+	ch := make(chan events.Event, 10)
+
+	SetEventBus(events.NewBus(events.NewChannelSink(ch)))
+	t.Cleanup(func() { SetEventBus(nil) })
+
+	// without credentials: the error shows the provider created.
+	rawConfig := []byte(`
+environmentName: public
+authMethod: env
+subscriptionID: subscription
+resourceGroup: group
+`)
+
+	_, err := NewDNSChallengeProviderByName("azure@legacy", rawConfig)
+	require.ErrorContains(t, err, "azuredns: ")
+
+	require.Len(t, ch, 1)
+
+	event := <-ch
+	assert.Equal(t, events.ProviderDeprecated, event.Type)
+	assert.Equal(t, "azure@legacy", event.Provider)
+	assert.Equal(t, "azuredns", event.Replacement)
+}
+
+func Test_migrateAzure(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		name      string
+		rawConfig string
+		expected  string
+	}{
+		{
+			desc:      "azuredns fields",
+			name:      "azure",
+			rawConfig: "authMethod: msi\n",
+			expected:  "azuredns",
+		},
+		{
+			desc:      "azure fields",
+			name:      "azure",
+			rawConfig: "clientID: id\nresourceManagerEndpoint: https://management.azure.com/\n",
+			expected:  "azure",
+		},
+		{
+			desc:      "other provider",
+			name:      "azuredns",
+			rawConfig: "authMethod: msi\n",
+			expected:  "azuredns",
+		},
+		{
+			desc:      "invalid config",
+			name:      "azure",
+			rawConfig: "authMethod: [",
+			expected:  "azure",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, migrateAzure(test.name, test.name, []byte(test.rawConfig)))
+		})
+	}
+}
+
+func TestConvertAzureConfig(t *testing.T) {
+	old := []byte(`# legacy config
+zoneName: example.com
+clientID: id
+clientSecret: secret
+metadataEndpoint: http://169.254.169.254
+resourceManagerEndpoint: https://management.chinacloudapi.cn/ # China
+activeDirectoryEndpoint: https://login.chinacloudapi.cn/
+propagationTimeout: 2m
+domainAliases:
+  example.com: alias.example.net
+`)
+
+	expected := `# legacy config
+zoneName: example.com
+clientID: id
+clientSecret: secret
+environmentName: china # China
+propagationTimeout: 2m
+domainAliases:
+    example.com: alias.example.net
+`
+
+	converted, err := ConvertAzureConfig(old)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, string(converted))
+
+	config, err := azuredns.ParseConfig(converted)
+	require.NoError(t, err)
+
+	assert.Equal(t, "china", config.EnvironmentName)
+	assert.Equal(t, "example.com", config.ZoneName)
+}
+
+func TestConvertAzureConfig_empty(t *testing.T) {
+	converted, err := ConvertAzureConfig(nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, converted)
+}
+
+func TestConvertAzureConfig_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		old      string
+		expected string
+	}{
+		{
+			desc:     "german cloud",
+			old:      "resourceManagerEndpoint: https://management.microsoftazure.de/\n",
+			expected: `azure: no azuredns environment for the resource manager endpoint "https://management.microsoftazure.de/"`,
+		},
+		{
+			desc:     "not a mapping",
+			old:      "- a\n- b\n",
+			expected: "azure: the config must be a mapping",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ConvertAzureConfig([]byte(test.old))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Package batch plans and runs the issuance of many certificates:
+// the jobs of independent providers run in parallel,
+// the jobs of a provider run with a bounded concurrency, one at a time for the sequential providers.
+//
+// The rate limits of the providers are enforced by the quota tracker (see legotoolbox.SetQuotaTracker),
+// the calls exceeding a delayed limit wait instead of failing.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	legotoolbox "lego-toolbox"
+)
+
+// DefaultConcurrency is the number of jobs run in parallel per provider when Config.Concurrency is zero.
+const DefaultConcurrency = 4
+
+// Job is the issuance of a certificate.
+type Job struct {
+	// ID identifies the job in the results, e.g. the name of the certificate.
+	ID string
+	// Domains are the domains of the certificate.
+	Domains []string
+	// Provider is the identifier of the provider solving the challenges (`cloudflare@personal`),
+	// an empty identifier selects the default provider.
+	Provider string
+}
+
+// IssueFunc issues the certificate of the job, solving the challenges with the provider.
+type IssueFunc func(ctx context.Context, job Job, provider challenge.Provider) error
+
+// ProviderFunc returns the provider of an identifier.
+type ProviderFunc func(id string) (challenge.Provider, error)
+
+// Config is used to configure the planning of the jobs.
+type Config struct {
+	// Concurrency is the number of jobs run in parallel per provider, DefaultConcurrency when zero.
+	// The sequential providers run one job at a time.
+	Concurrency int
+	// ProviderConcurrency overrides Concurrency per provider identifier.
+	ProviderConcurrency map[string]int
+	// MaxParallel bounds the number of jobs run in parallel across all providers, unbounded when zero.
+	MaxParallel int
+	// Providers returns the providers of the jobs, legotoolbox.GetProviderInstance when nil.
+	Providers ProviderFunc
+}
+
+// Group is the jobs of a provider.
+type Group struct {
+	// Provider is the identifier of the provider.
+	Provider string
+	// Concurrency is the number of jobs of the group run in parallel.
+	Concurrency int
+	// Sequence is the interval between the jobs of a sequential provider, zero for the other providers.
+	Sequence time.Duration
+	// Jobs are the jobs of the group, in the order of the plan.
+	Jobs []Job
+
+	provider challenge.Provider
+}
+
+// Plan is the execution plan of the jobs, a group per provider.
+type Plan struct {
+	// Groups are the groups of jobs, sorted by provider identifier. The groups run in parallel.
+	Groups []Group
+
+	maxParallel int
+	size        int
+}
+
+// Result is the outcome of a job.
+type Result struct {
+	Job Job
+	// Err is the error of the issuance, or of the context when the job did not run.
+	Err error
+	// Duration is the duration of the issuance.
+	Duration time.Duration
+}
+
+// sequential is implemented by the providers resolving their challenges one at a time.
+type sequential interface {
+	Sequential() time.Duration
+}
+
+// NewPlan groups the jobs by provider, and sets the concurrency of each group.
+func NewPlan(jobs []Job, config Config) (*Plan, error) {
+	providers := config.Providers
+	if providers == nil {
+		providers = legotoolbox.GetProviderInstance
+	}
+
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	groups := map[string]*Group{}
+
+	for _, job := range jobs {
+		if len(job.Domains) == 0 {
+			return nil, fmt.Errorf("batch: job %q: no domains", job.ID)
+		}
+
+		id := job.Provider
+		if id == "" {
+			id = legotoolbox.DefaultProvider()
+		}
+
+		group, ok := groups[id]
+		if !ok {
+			provider, err := providers(id)
+			if err != nil {
+				return nil, fmt.Errorf("batch: job %q: %w", job.ID, err)
+			}
+
+			group = &Group{Provider: id, Concurrency: concurrency, provider: provider}
+
+			if c, ok := config.ProviderConcurrency[id]; ok && c > 0 {
+				group.Concurrency = c
+			}
+
+			if s, ok := provider.(sequential); ok {
+				group.Concurrency = 1
+				group.Sequence = s.Sequential()
+			}
+
+			groups[id] = group
+		}
+
+		group.Jobs = append(group.Jobs, job)
+	}
+
+	plan := &Plan{maxParallel: config.MaxParallel, size: len(jobs)}
+
+	for _, group := range groups {
+		plan.Groups = append(plan.Groups, *group)
+	}
+
+	sort.Slice(plan.Groups, func(i, j int) bool { return plan.Groups[i].Provider < plan.Groups[j].Provider })
+
+	return plan, nil
+}
+
+// Run runs the jobs of the plan with issue, and returns their results.
+// The results are ordered by group, then by job.
+// The jobs not started when the context is canceled fail with the error of the context.
+func (p *Plan) Run(ctx context.Context, issue IssueFunc) []Result {
+	var global chan struct{}
+	if p.maxParallel > 0 {
+		global = make(chan struct{}, p.maxParallel)
+	}
+
+	results := make([][]Result, len(p.Groups))
+
+	var wg sync.WaitGroup
+
+	for i, group := range p.Groups {
+		results[i] = make([]Result, len(group.Jobs))
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			group.run(ctx, issue, global, results[i])
+		}()
+	}
+
+	wg.Wait()
+
+	all := make([]Result, 0, p.size)
+	for _, r := range results {
+		all = append(all, r...)
+	}
+
+	return all
+}
+
+// run runs the jobs of the group, writing the result of each job at its index.
+func (g Group) run(ctx context.Context, issue IssueFunc, global chan struct{}, results []Result) {
+	local := make(chan struct{}, g.Concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, job := range g.Jobs {
+		results[i].Job = job
+
+		if i > 0 && g.Sequence > 0 {
+			// the previous job is done: the group of a sequential provider runs one job at a time.
+			wg.Wait()
+
+			if err := sleep(ctx, g.Sequence); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+
+		if err := acquire(ctx, local); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if err := acquire(ctx, global); err != nil {
+			<-local
+			results[i].Err = err
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			defer func() {
+				release(global)
+				<-local
+			}()
+
+			start := time.Now()
+			results[i].Err = issue(ctx, job, g.provider)
+			results[i].Duration = time.Since(start)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Err returns the errors of the failed jobs, joined, or nil.
+func Err(results []Result) error {
+	var errs []error
+
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Job.ID, r.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil || sem == nil {
+		return err
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,203 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct{}
+
+func (fakeProvider) Present(_, _, _ string) error { return nil }
+func (fakeProvider) CleanUp(_, _, _ string) error { return nil }
+
+type sequentialProvider struct {
+	fakeProvider
+
+	interval time.Duration
+}
+
+func (p sequentialProvider) Sequential() time.Duration { return p.interval }
+
+func providers(m map[string]challenge.Provider) ProviderFunc {
+	return func(id string) (challenge.Provider, error) {
+		p, ok := m[id]
+		if !ok {
+			return nil, fmt.Errorf("provider instance %q not registered", id)
+		}
+
+		return p, nil
+	}
+}
+
+func jobs(provider string, n int) []Job {
+	var js []Job
+	for i := range n {
+		id := fmt.Sprintf("%s-%d", provider, i)
+		js = append(js, Job{ID: id, Domains: []string{id + ".example.com"}, Provider: provider})
+	}
+
+	return js
+}
+
+func TestNewPlan(t *testing.T) {
+	config := Config{
+		ProviderConcurrency: map[string]int{"b": 8},
+		Providers: providers(map[string]challenge.Provider{
+			"a": fakeProvider{},
+			"b": fakeProvider{},
+			"c": sequentialProvider{interval: time.Minute},
+		}),
+	}
+
+	plan, err := NewPlan(append(append(jobs("c", 2), jobs("a", 3)...), jobs("b", 1)...), config)
+	require.NoError(t, err)
+	require.Len(t, plan.Groups, 3)
+
+	assert.Equal(t, "a", plan.Groups[0].Provider)
+	assert.Equal(t, DefaultConcurrency, plan.Groups[0].Concurrency)
+	assert.Zero(t, plan.Groups[0].Sequence)
+	assert.Len(t, plan.Groups[0].Jobs, 3)
+
+	assert.Equal(t, "b", plan.Groups[1].Provider)
+	assert.Equal(t, 8, plan.Groups[1].Concurrency)
+
+	assert.Equal(t, "c", plan.Groups[2].Provider)
+	assert.Equal(t, 1, plan.Groups[2].Concurrency)
+	assert.Equal(t, time.Minute, plan.Groups[2].Sequence)
+	assert.Equal(t, []string{"c-0", "c-1"}, []string{plan.Groups[2].Jobs[0].ID, plan.Groups[2].Jobs[1].ID})
+}
+
+func TestNewPlan_errors(t *testing.T) {
+	config := Config{Providers: providers(map[string]challenge.Provider{"a": fakeProvider{}})}
+
+	_, err := NewPlan(jobs("b", 1), config)
+	require.EqualError(t, err, `batch: job "b-0": provider instance "b" not registered`)
+
+	_, err = NewPlan([]Job{{ID: "empty", Provider: "a"}}, config)
+	require.EqualError(t, err, `batch: job "empty": no domains`)
+}
+
+func TestPlan_Run(t *testing.T) {
+	config := Config{
+		Concurrency: 2,
+		Providers: providers(map[string]challenge.Provider{
+			"a": fakeProvider{},
+			"b": fakeProvider{},
+			"c": sequentialProvider{interval: 10 * time.Millisecond},
+		}),
+	}
+
+	plan, err := NewPlan(append(append(jobs("a", 6), jobs("b", 6)...), jobs("c", 3)...), config)
+	require.NoError(t, err)
+
+	var (
+		mu       sync.Mutex
+		running  = map[string]int{}
+		maxByPrv = map[string]int{}
+		total    int32
+		maxTotal int32
+	)
+
+	results := plan.Run(context.Background(), func(_ context.Context, job Job, _ challenge.Provider) error {
+		mu.Lock()
+		running[job.Provider]++
+		maxByPrv[job.Provider] = max(maxByPrv[job.Provider], running[job.Provider])
+		mu.Unlock()
+
+		n := atomic.AddInt32(&total, 1)
+		for {
+			m := atomic.LoadInt32(&maxTotal)
+			if n <= m || atomic.CompareAndSwapInt32(&maxTotal, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		atomic.AddInt32(&total, -1)
+
+		mu.Lock()
+		running[job.Provider]--
+		mu.Unlock()
+
+		if job.ID == "b-3" {
+			return errors.New("boom")
+		}
+
+		return nil
+	})
+
+	require.Len(t, results, 15)
+	assert.Equal(t, "a-0", results[0].Job.ID)
+	assert.Equal(t, "c-2", results[14].Job.ID)
+
+	assert.Equal(t, 2, maxByPrv["a"])
+	assert.Equal(t, 2, maxByPrv["b"])
+	assert.Equal(t, 1, maxByPrv["c"])
+	// the providers run in parallel.
+	assert.Greater(t, maxTotal, int32(2))
+
+	require.EqualError(t, Err(results), "b-3: boom")
+}
+
+func TestPlan_Run_maxParallel(t *testing.T) {
+	config := Config{
+		MaxParallel: 1,
+		Providers: providers(map[string]challenge.Provider{
+			"a": fakeProvider{},
+			"b": fakeProvider{},
+		}),
+	}
+
+	plan, err := NewPlan(append(jobs("a", 3), jobs("b", 3)...), config)
+	require.NoError(t, err)
+
+	var running, maxRunning int32
+
+	results := plan.Run(context.Background(), func(_ context.Context, _ Job, _ challenge.Provider) error {
+		n := atomic.AddInt32(&running, 1)
+		if n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		return nil
+	})
+
+	require.NoError(t, Err(results))
+	assert.Equal(t, int32(1), maxRunning)
+}
+
+func TestPlan_Run_canceled(t *testing.T) {
+	config := Config{
+		Providers: providers(map[string]challenge.Provider{
+			"c": sequentialProvider{interval: time.Hour},
+		}),
+	}
+
+	plan, err := NewPlan(jobs("c", 3), config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := plan.Run(ctx, func(_ context.Context, _ Job, _ challenge.Provider) error {
+		cancel()
+		return nil
+	})
+
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, context.Canceled)
+	require.ErrorIs(t, results[2].Err, context.Canceled)
+}
@@ -0,0 +1,287 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/propagation"
+	"lego-toolbox/yamlconfig"
+)
+
+// Reserved keys of a bundle entry, they are not passed to the provider config.
+const (
+	// bundleTypeKey is the key holding the provider name.
+	bundleTypeKey = "type"
+	// bundleTimingKey is the key holding the timing overrides.
+	bundleTimingKey = "timing"
+	// bundlePropagationKey is the key holding the propagation check config.
+	bundlePropagationKey = "propagation"
+	// bundleExtendsKey is the key holding the name of the profile (or provider) the entry inherits from.
+	bundleExtendsKey = "extends"
+)
+
+// Bundle is the top-level YAML document declaring many named provider configs.
+// With `strict: true`, the unknown keys of the provider configs are rejected (see ParseConfigStrict).
+//
+// An entry inherits the keys of the profile (or provider) named by `extends`, its own keys take precedence,
+// the nested mappings (e.g. timing) are merged key by key. The profiles are not constructed.
+//
+//	strict: true
+//	profiles:
+//	  base-cloudflare:
+//	    type: cloudflare
+//	    propagationTimeout: 5m
+//	    timing:
+//	      sequenceInterval: 30s
+//	providers:
+//	  cloudflare-team-a:
+//	    extends: base-cloudflare
+//	    authToken: token-a
+//	  prod-route53:
+//	    type: route53
+//	    region: eu-west-1
+//	  corp-infoblox:
+//	    type: infoblox
+//	    host: grid.example.com
+//	    timing:
+//	      propagationTimeout: 10m
+//	      sequenceInterval: 30s
+//	    propagation:
+//	      servers: [cloudflare, google]
+type Bundle struct {
+	Strict    bool                 `yaml:"strict"`
+	Profiles  map[string]yaml.Node `yaml:"profiles"`
+	Providers map[string]yaml.Node `yaml:"providers"`
+}
+
+// BundleEntry is a single named provider config extracted from a Bundle.
+type BundleEntry struct {
+	// Name is the key of the entry in the bundle.
+	Name string
+	// Type is the DNS provider name understood by NewDNSChallengeProviderByName.
+	Type string
+	// RawConfig is the provider YAML config without the reserved keys.
+	RawConfig []byte
+	// Timing overrides the timing of the provider, see ApplyTiming.
+	Timing Timing
+	// Propagation configures the propagation check of the provider, nil to keep the lego check.
+	// See propagation.New and propagation.Checker.PreCheck.
+	Propagation *propagation.Config
+}
+
+// LoadBundle reads the YAML bundle at path and constructs every provider it declares.
+func LoadBundle(path string) (map[string]challenge.Provider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+
+	return ParseBundle(raw)
+}
+
+// ParseBundle parses a YAML bundle and constructs every provider it declares.
+func ParseBundle(raw []byte) (map[string]challenge.Provider, error) {
+	entries, err := ParseBundleEntries(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]challenge.Provider, len(entries))
+	for _, entry := range entries {
+		provider, err := NewDNSChallengeProviderByName(entry.Type, entry.RawConfig)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: provider %q: %w", entry.Name, err)
+		}
+
+		providers[entry.Name] = ApplyTiming(provider, entry.Timing)
+	}
+
+	return providers, nil
+}
+
+// ParseBundleEntries parses a YAML bundle without constructing the providers.
+// Entries are sorted by name.
+// The provider configs are checked with ParseConfigStrict if the bundle is strict.
+func ParseBundleEntries(raw []byte) ([]BundleEntry, error) {
+	var bundle Bundle
+	err := yaml.Unmarshal(raw, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+
+	if len(bundle.Providers) == 0 {
+		return nil, errors.New("bundle: no providers declared")
+	}
+
+	for name := range bundle.Profiles {
+		if _, exists := bundle.Providers[name]; exists {
+			return nil, fmt.Errorf("bundle: %q is declared both as a profile and as a provider", name)
+		}
+	}
+
+	names := make([]string, 0, len(bundle.Providers))
+	for name := range bundle.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]BundleEntry, 0, len(names))
+	for _, name := range names {
+		node, err := bundle.resolve(name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: provider %q: %w", name, err)
+		}
+
+		entry, err := parseBundleEntry(name, node)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: provider %q: %w", name, err)
+		}
+
+		if bundle.Strict {
+			_, err = ParseConfigStrict(entry.Type, entry.RawConfig)
+			if err != nil {
+				return nil, fmt.Errorf("bundle: provider %q: %w", name, err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseBundleEntry(name string, node *yaml.Node) (BundleEntry, error) {
+	if node.Kind != yaml.MappingNode {
+		return BundleEntry{}, errors.New("entry must be a mapping")
+	}
+
+	entry := BundleEntry{Name: name}
+
+	config := &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch key.Value {
+		case bundleTypeKey:
+			entry.Type = value.Value
+			continue
+
+		case bundleTimingKey:
+			err := yamlconfig.Decode(value, &entry.Timing)
+			if err != nil {
+				return BundleEntry{}, fmt.Errorf("timing: %w", err)
+			}
+			continue
+
+		case bundlePropagationKey:
+			entry.Propagation = propagation.DefaultConfig()
+			err := yamlconfig.Decode(value, entry.Propagation)
+			if err != nil {
+				return BundleEntry{}, fmt.Errorf("propagation: %w", err)
+			}
+			continue
+		}
+
+		config.Content = append(config.Content, key, value)
+	}
+
+	if entry.Type == "" {
+		return BundleEntry{}, errors.New("missing provider type")
+	}
+
+	rawConfig, err := yaml.Marshal(config)
+	if err != nil {
+		return BundleEntry{}, err
+	}
+
+	entry.RawConfig = rawConfig
+
+	return entry, nil
+}
+
+// resolve returns the entry (profile or provider) with the keys inherited through `extends`.
+// chain holds the names of the entries being resolved, to detect the cycles.
+func (b *Bundle) resolve(name string, chain []string) (*yaml.Node, error) {
+	for _, n := range chain {
+		if n == name {
+			return nil, fmt.Errorf("extends: cycle %s", strings.Join(append(chain, name), " -> "))
+		}
+	}
+
+	node, ok := b.Profiles[name]
+	if !ok {
+		node, ok = b.Providers[name]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("extends: unknown profile %q", name)
+	}
+
+	if node.Kind != yaml.MappingNode {
+		if len(chain) > 0 {
+			return nil, fmt.Errorf("extends: profile %q must be a mapping", name)
+		}
+
+		return nil, errors.New("entry must be a mapping")
+	}
+
+	own := &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+
+	var parent string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.Value == bundleExtendsKey {
+			parent = value.Value
+			continue
+		}
+
+		own.Content = append(own.Content, key, value)
+	}
+
+	if parent == "" {
+		return own, nil
+	}
+
+	base, err := b.resolve(parent, append(chain, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeMappings(base, own), nil
+}
+
+// mergeMappings returns a mapping with the keys of base and override, the values of override take precedence.
+// The mappings present in both are merged recursively, the other values (sequences included) are replaced.
+func mergeMappings(base, override *yaml.Node) *yaml.Node {
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: override.Tag}
+	merged.Content = append(merged.Content, base.Content...)
+
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+
+		index := -1
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				index = j
+				break
+			}
+		}
+
+		switch {
+		case index < 0:
+			merged.Content = append(merged.Content, key, value)
+		case merged.Content[index+1].Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			merged.Content[index+1] = mergeMappings(merged.Content[index+1], value)
+		default:
+			merged.Content[index+1] = value
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,186 @@
+package legotoolbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/providers/dns/exec"
+)
+
+const testBundle = `
+providers:
+  exec-a:
+    type: exec
+    program: /usr/bin/a
+    propagationTimeout: 90s
+  exec-b:
+    type: exec
+    program: /usr/bin/b
+    propagation:
+      servers: [cloudflare, 192.0.2.53]
+      requireAll: true
+`
+
+func TestLoadBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testBundle), 0o600))
+
+	providers, err := LoadBundle(path)
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+
+	require.IsType(t, &exec.DNSProvider{}, providers["exec-a"])
+	require.IsType(t, &exec.DNSProvider{}, providers["exec-b"])
+
+	timeout, _ := providers["exec-a"].(*exec.DNSProvider).Timeout()
+	assert.Equal(t, 90*time.Second, timeout)
+}
+
+func TestParseBundleEntries(t *testing.T) {
+	entries, err := ParseBundleEntries([]byte(testBundle))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "exec-a", entries[0].Name)
+	assert.Equal(t, "exec", entries[0].Type)
+	assert.NotContains(t, string(entries[0].RawConfig), "type:")
+	assert.Contains(t, string(entries[0].RawConfig), "program: /usr/bin/a")
+	assert.Nil(t, entries[0].Propagation)
+
+	require.NotNil(t, entries[1].Propagation)
+	assert.Equal(t, []string{"cloudflare", "192.0.2.53"}, entries[1].Propagation.Servers)
+	assert.True(t, entries[1].Propagation.RequireAll)
+	assert.Equal(t, 10*time.Second, entries[1].Propagation.Timeout)
+	assert.NotContains(t, string(entries[1].RawConfig), "propagation:")
+}
+
+func TestParseBundle_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{
+			desc:     "no providers",
+			raw:      `providers: {}`,
+			expected: "bundle: no providers declared",
+		},
+		{
+			desc:     "missing type",
+			raw:      "providers:\n  foo:\n    program: a\n",
+			expected: `bundle: provider "foo": missing provider type`,
+		},
+		{
+			desc:     "not a mapping",
+			raw:      "providers:\n  foo: bar\n",
+			expected: `bundle: provider "foo": entry must be a mapping`,
+		},
+		{
+			desc:     "unknown provider",
+			raw:      "providers:\n  foo:\n    type: foobar\n",
+			expected: `bundle: provider "foo": unrecognized DNS provider: foobar`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseBundle([]byte(test.raw))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestParseBundleEntries_extends(t *testing.T) {
+	raw := `
+profiles:
+  base:
+    type: exec
+    propagationTimeout: 90s
+    timing:
+      pollingInterval: 5s
+      sequenceInterval: 30s
+  base-b:
+    extends: base
+    program: /usr/bin/base
+providers:
+  exec-a:
+    extends: base
+    program: /usr/bin/a
+    timing:
+      sequenceInterval: 1m
+  exec-b:
+    extends: base-b
+  exec-c:
+    extends: exec-b
+    propagationTimeout: 2m
+`
+
+	entries, err := ParseBundleEntries([]byte(raw))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "exec", entries[0].Type)
+	assert.Equal(t, "program: /usr/bin/a\npropagationTimeout: 90s\n", sortedYAML(t, entries[0].RawConfig))
+	assert.Equal(t, Timing{PollingInterval: 5 * time.Second, SequenceInterval: time.Minute}, entries[0].Timing)
+
+	assert.Equal(t, "exec", entries[1].Type)
+	assert.Equal(t, "program: /usr/bin/base\npropagationTimeout: 90s\n", sortedYAML(t, entries[1].RawConfig))
+	assert.Equal(t, Timing{PollingInterval: 5 * time.Second, SequenceInterval: 30 * time.Second}, entries[1].Timing)
+
+	assert.Equal(t, "program: /usr/bin/base\npropagationTimeout: 2m\n", sortedYAML(t, entries[2].RawConfig))
+	assert.NotContains(t, string(entries[2].RawConfig), "extends")
+}
+
+func TestParseBundleEntries_extends_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{
+			desc:     "unknown profile",
+			raw:      "providers:\n  foo:\n    extends: bar\n",
+			expected: `bundle: provider "foo": extends: unknown profile "bar"`,
+		},
+		{
+			desc:     "cycle",
+			raw:      "profiles:\n  a:\n    extends: b\n  b:\n    extends: a\nproviders:\n  foo:\n    extends: a\n",
+			expected: `bundle: provider "foo": extends: cycle foo -> a -> b -> a`,
+		},
+		{
+			desc:     "profile not a mapping",
+			raw:      "profiles:\n  a: b\nproviders:\n  foo:\n    extends: a\n",
+			expected: `bundle: provider "foo": extends: profile "a" must be a mapping`,
+		},
+		{
+			desc:     "duplicated name",
+			raw:      "profiles:\n  foo:\n    type: exec\nproviders:\n  foo:\n    type: exec\n",
+			expected: `bundle: "foo" is declared both as a profile and as a provider`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseBundleEntries([]byte(test.raw))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+// sortedYAML re-encodes the YAML mapping with its keys sorted.
+func sortedYAML(t *testing.T, raw []byte) string {
+	t.Helper()
+
+	var m map[string]any
+	require.NoError(t, yaml.Unmarshal(raw, &m))
+
+	out, err := yaml.Marshal(m)
+	require.NoError(t, err)
+
+	return string(out)
+}
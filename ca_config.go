@@ -0,0 +1,103 @@
+package legotoolbox
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-acme/lego/v4/lego"
+	"lego-toolbox/eab"
+	"lego-toolbox/yamlconfig"
+)
+
+// CAConfig configures the ACME certificate authority used by LegoUser,
+// e.g. a private CA like smallstep step-ca or Vault PKI ACME.
+// The zero value is Let's Encrypt production.
+//
+//	directoryURL: https://ca.internal:9000/acme/acme/directory
+//	caFile: /etc/step/certs/root_ca.crt
+//	eab:
+//	  kid: my-key-id
+//	  hmacKey: base64url-encoded-key
+type CAConfig struct {
+	// DirectoryURL is the URL of the ACME directory, defaults to Let's Encrypt production.
+	DirectoryURL string `json:"directoryURL,omitempty" yaml:"directoryURL"`
+	// CAFile is the PEM file of the root CA of the ACME server, the system roots are used when empty.
+	CAFile string `json:"caFile,omitempty" yaml:"caFile"`
+	// EAB is the external account binding required by some CAs to register an account.
+	EAB *eab.Credentials `json:"eab,omitempty" yaml:"eab"`
+	// MustStaple requests the OCSP Must-Staple extension in the certificates.
+	// Disabled by default: the private CAs usually have no OCSP responder.
+	MustStaple bool `json:"mustStaple,omitempty" yaml:"mustStaple"`
+}
+
+// ParseCAConfig parses the YAML configuration of the ACME certificate authority.
+func ParseCAConfig(rawConfig []byte) (*CAConfig, error) {
+	config := &CAConfig{}
+
+	err := yamlconfig.Unmarshal(rawConfig, config)
+	if err != nil {
+		return nil, fmt.Errorf("ca: %w", err)
+	}
+
+	err = config.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (c *CAConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.EAB != nil {
+		err := c.EAB.Validate()
+		if err != nil {
+			return fmt.Errorf("ca: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// directoryURL returns the URL of the ACME directory.
+func (c *CAConfig) directoryURL() string {
+	if c == nil || c.DirectoryURL == "" {
+		return lego.LEDirectoryProduction
+	}
+
+	return c.DirectoryURL
+}
+
+// httpClient returns the HTTP client trusting the root CA of CAFile,
+// nil when the default client of lego is used.
+func (c *CAConfig) httpClient() (*http.Client, error) {
+	if c == nil || c.CAFile == "" {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("ca: read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("ca: no certificate found in CA file %s", c.CAFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return &http.Client{Timeout: 2 * time.Minute, Transport: transport}, nil
+}
@@ -0,0 +1,116 @@
+package legotoolbox
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/eab"
+)
+
+func TestParseCAConfig(t *testing.T) {
+	raw := `
+directoryURL: https://ca.internal:9000/acme/acme/directory
+caFile: /etc/step/certs/root_ca.crt
+eab:
+  kid: kid
+  hmacKey: c2l4dGVlbi1ieXRlcy1rZXk
+`
+
+	config, err := ParseCAConfig([]byte(raw))
+	require.NoError(t, err)
+
+	expected := &CAConfig{
+		DirectoryURL: "https://ca.internal:9000/acme/acme/directory",
+		CAFile:       "/etc/step/certs/root_ca.crt",
+		EAB:          &eab.Credentials{KID: "kid", HMACKey: "c2l4dGVlbi1ieXRlcy1rZXk"},
+	}
+	assert.Equal(t, expected, config)
+}
+
+func TestParseCAConfig_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{
+			desc:     "missing EAB HMAC key",
+			raw:      "eab:\n  kid: kid\n",
+			expected: "ca: eab: missing HMAC key (hmacKey)",
+		},
+		{
+			desc:     "missing EAB key ID",
+			raw:      "eab:\n  hmacKey: c2l4dGVlbi1ieXRlcy1rZXk\n",
+			expected: "ca: eab: missing key identifier (kid)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseCAConfig([]byte(test.raw))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestCAConfig_directoryURL(t *testing.T) {
+	var config *CAConfig
+	assert.Equal(t, lego.LEDirectoryProduction, config.directoryURL())
+
+	config = &CAConfig{}
+	assert.Equal(t, lego.LEDirectoryProduction, config.directoryURL())
+
+	config = &CAConfig{DirectoryURL: "https://ca.internal/directory"}
+	assert.Equal(t, "https://ca.internal/directory", config.directoryURL())
+}
+
+func TestCAConfig_httpClient_invalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "root_ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	config := &CAConfig{CAFile: caFile}
+
+	_, err := config.httpClient()
+	require.ErrorContains(t, err, "ca: no certificate found in CA file")
+}
+
+func TestLegoUser_NewClient_privateCA(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /directory", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{
+  "newNonce": "` + server.URL + `/new-nonce",
+  "newAccount": "` + server.URL + `/new-account",
+  "newOrder": "` + server.URL + `/new-order",
+  "revokeCert": "` + server.URL + `/revoke-cert",
+  "keyChange": "` + server.URL + `/key-change"
+}`))
+	})
+
+	caFile := filepath.Join(t.TempDir(), "root_ca.crt")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+
+	user := NewUserFromAccount(&LegoAccount{Email: "test@example.com"})
+	require.NoError(t, user.GeneratePrivateKey())
+
+	user.CA = &CAConfig{DirectoryURL: server.URL + "/directory"}
+
+	err := user.NewClient(EC256)
+	require.ErrorContains(t, err, "certificate signed by unknown authority")
+
+	user.CA.CAFile = caFile
+
+	err = user.NewClient(EC256)
+	require.NoError(t, err)
+	require.NotNil(t, user.Client)
+}
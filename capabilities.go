@@ -0,0 +1,131 @@
+package legotoolbox
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/internal/provideralias"
+)
+
+// Capabilities describes the behavior of a provider that matters to the orchestration of the challenges.
+type Capabilities struct {
+	// MultipleValues reports whether the provider can hold several TXT values at the same name,
+	// needed to solve `example.com` and `*.example.com` in the same order.
+	MultipleValues bool `json:"multipleValues" yaml:"multipleValues"`
+	// WildcardSafe reports whether CleanUp removes only its own value,
+	// so the cleanup of a challenge doesn't remove the record of another challenge at the same name.
+	WildcardSafe bool `json:"wildcardSafe" yaml:"wildcardSafe"`
+	// CNAMEFollow reports whether the provider writes the record at the target of the `_acme-challenge` CNAME.
+	CNAMEFollow bool `json:"cnameFollow" yaml:"cnameFollow"`
+}
+
+// Capability is a capability of the providers, see Capabilities.
+type Capability string
+
+// The capabilities, named after the JSON keys of Capabilities.
+const (
+	MultipleValues Capability = "multipleValues"
+	WildcardSafe   Capability = "wildcardSafe"
+	CNAMEFollow    Capability = "cnameFollow"
+)
+
+// Has reports whether the capability is set.
+func (c Capabilities) Has(capability Capability) bool {
+	switch capability {
+	case MultipleValues:
+		return c.MultipleValues
+	case WildcardSafe:
+		return c.WildcardSafe
+	case CNAMEFollow:
+		return c.CNAMEFollow
+	default:
+		return false
+	}
+}
+
+// CapabilitiesProvider is implemented by the providers reporting their own capabilities,
+// it takes precedence over the capability table.
+type CapabilitiesProvider interface {
+	Capabilities() Capabilities
+}
+
+// defaultCapabilities are the capabilities of the providers missing from providerCapabilities.
+var defaultCapabilities = Capabilities{MultipleValues: true, WildcardSafe: true, CNAMEFollow: true}
+
+// providerCapabilities lists the providers lacking some capabilities,
+// keyed by the package name of the provider (the provider name without dashes).
+var providerCapabilities = map[string]Capabilities{
+	// the records are at the name registered in acme-dns, the CNAME is set up once by the user.
+	// acme-dns keeps the two last values, and never removes them.
+	"acmedns": {MultipleValues: true, WildcardSafe: true},
+	// the dynamic DNS APIs hold a single TXT value per name.
+	"dode":      {CNAMEFollow: true},
+	"duckdns":   {CNAMEFollow: true},
+	"freemyip":  {CNAMEFollow: true},
+	"hurricane": {CNAMEFollow: true},
+	// the records are written at `_acme-challenge.<domain>`.
+	"iij":     {MultipleValues: true, WildcardSafe: true},
+	"mydnsjp": {MultipleValues: true, WildcardSafe: true},
+}
+
+// ProviderCapabilities returns the capabilities of the provider.
+// The name may be a provider instance identifier (`cloudflare@personal`), an empty name selects the default provider.
+// The registered instances report their own capabilities when they implement CapabilitiesProvider.
+func ProviderCapabilities(name string) (Capabilities, error) {
+	if name == "" {
+		name = DefaultProvider()
+	}
+
+	if provider, err := GetProviderInstance(name); err == nil {
+		return CapabilitiesOf(provider), nil
+	}
+
+	providerName, _ := SplitProviderID(name)
+	providerName = provideralias.Resolve(providerName)
+
+	if !isDNSProvider(providerName) {
+		return Capabilities{}, fmt.Errorf("unrecognized DNS provider: %s", providerName)
+	}
+
+	return capabilitiesByName(providerName), nil
+}
+
+// CapabilitiesOf returns the capabilities of a provider created by the factory, possibly decorated.
+// The first provider implementing CapabilitiesProvider (decorator or wrapped provider) reports the capabilities,
+// otherwise the capability table is consulted with the package of the wrapped provider.
+func CapabilitiesOf(provider challenge.Provider) Capabilities {
+	for {
+		if p, ok := provider.(CapabilitiesProvider); ok {
+			return p.Capabilities()
+		}
+
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			break
+		}
+
+		provider = u.Unwrap()
+	}
+
+	t := reflect.TypeOf(provider)
+	if t == nil {
+		return defaultCapabilities
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return capabilitiesByName(path.Base(t.PkgPath()))
+}
+
+func capabilitiesByName(name string) Capabilities {
+	if capabilities, ok := providerCapabilities[strings.ReplaceAll(name, "-", "")]; ok {
+		return capabilities
+	}
+
+	return defaultCapabilities
+}
@@ -0,0 +1,70 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/duckdns"
+	"lego-toolbox/providers/dns/exec"
+)
+
+type capableProvider struct {
+	*exec.DNSProvider
+}
+
+func (p *capableProvider) Capabilities() Capabilities {
+	return Capabilities{MultipleValues: true}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Capabilities
+	}{
+		{name: "cloudflare", expected: Capabilities{MultipleValues: true, WildcardSafe: true, CNAMEFollow: true}},
+		{name: "duckdns", expected: Capabilities{CNAMEFollow: true}},
+		{name: "duckdns@home", expected: Capabilities{CNAMEFollow: true}},
+		{name: "acme-dns", expected: Capabilities{MultipleValues: true, WildcardSafe: true}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			capabilities, err := ProviderCapabilities(test.name)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, capabilities)
+		})
+	}
+}
+
+func TestProviderCapabilities_unknown(t *testing.T) {
+	_, err := ProviderCapabilities("unknown")
+	require.EqualError(t, err, "unrecognized DNS provider: unknown")
+}
+
+func TestProviderCapabilities_instance(t *testing.T) {
+	t.Cleanup(func() { UnregisterProviderInstance("exec@capable") })
+
+	provider, err := exec.NewDNSProviderConfig(exec.DefaultConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, RegisterProviderInstance("exec@capable", WithTimeouts(&capableProvider{DNSProvider: provider}, time.Minute, 0)))
+
+	capabilities, err := ProviderCapabilities("exec@capable")
+	require.NoError(t, err)
+
+	assert.Equal(t, Capabilities{MultipleValues: true}, capabilities)
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	config := duckdns.DefaultConfig()
+	config.Token = "secret"
+
+	provider, err := duckdns.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, Capabilities{CNAMEFollow: true}, CapabilitiesOf(provider))
+	assert.Equal(t, Capabilities{CNAMEFollow: true}, CapabilitiesOf(WithSequential(provider, time.Second)))
+}
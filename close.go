@@ -0,0 +1,25 @@
+package legotoolbox
+
+import (
+	"io"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// CloseProvider releases the provider, possibly decorated, when it implements io.Closer
+// (e.g. the connection of remote, the batched publishes of dyn).
+// The providers created for a single run (a CLI command) must be closed before exiting.
+func CloseProvider(provider challenge.Provider) error {
+	for {
+		if c, ok := provider.(io.Closer); ok {
+			return c.Close()
+		}
+
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return nil
+		}
+
+		provider = u.Unwrap()
+	}
+}
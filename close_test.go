@@ -0,0 +1,37 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closingProvider struct {
+	closed bool
+}
+
+func (p *closingProvider) Present(_, _, _ string) error { return nil }
+
+func (p *closingProvider) CleanUp(_, _, _ string) error { return nil }
+
+func (p *closingProvider) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestCloseProvider(t *testing.T) {
+	provider := &closingProvider{}
+
+	require.NoError(t, CloseProvider(WithTimeouts(provider, time.Minute, time.Second)))
+
+	assert.True(t, provider.closed)
+}
+
+func TestCloseProvider_notCloser(t *testing.T) {
+	provider, err := NewDNSChallengeProviderByName("fake", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, CloseProvider(provider))
+}
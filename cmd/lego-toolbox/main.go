@@ -0,0 +1,471 @@
+// Command lego-toolbox exercises the DNS providers outside the renewal daemon,
+// with the same YAML configs.
+//
+//	lego-toolbox present      -provider cloudflare -config cloudflare.yaml -domain example.com -key-auth token.thumbprint
+//	lego-toolbox cleanup      -provider cloudflare -config cloudflare.yaml -domain example.com -key-auth token.thumbprint
+//	lego-toolbox check-config -bundle providers.yaml
+//	lego-toolbox template     cloudflare
+//	lego-toolbox purge-stale  -older-than 1h
+//
+// The providers keeping a state of the presented challenges (e.g. the IDs of the created records)
+// persist it next to the state file of the CLI (`challenges/<provider>.json`), unless their config sets `stateFile`,
+// so the records can be cleaned up by a later run.
+// The state file of the CLI references the configs of the presented records, purge-stale reads them again:
+// it holds no credentials.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	legotoolbox "lego-toolbox"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+const usage = `Usage: lego-toolbox <command> [flags]
+
+Commands:
+  present       create the TXT record of a challenge
+  cleanup       remove the TXT record of a challenge
+  check-config  validate a provider config or a bundle
+  template      print the config template of a provider
+  purge-stale   clean up the records presented and never cleaned up
+
+Run 'lego-toolbox <command> -h' for the flags of a command.
+`
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "present":
+		return runChallenge(args[0], args[1:], stdout, stderr)
+	case "cleanup":
+		return runChallenge(args[0], args[1:], stdout, stderr)
+	case "check-config":
+		return runCheckConfig(args[1:], stdout, stderr)
+	case "template":
+		return runTemplate(args[1:], stdout, stderr)
+	case "purge-stale":
+		return runPurgeStale(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		_, _ = fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// providerFlags selects a provider config: a provider name and a config file, or an entry of a bundle.
+type providerFlags struct {
+	provider string
+	config   string
+	bundle   string
+	entry    string
+}
+
+func (f *providerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.provider, "provider", "", "provider name (e.g. cloudflare, cloudflare@personal)")
+	fs.StringVar(&f.config, "config", "", "provider YAML config file")
+	fs.StringVar(&f.bundle, "bundle", "", "bundle YAML file, instead of -provider and -config")
+	fs.StringVar(&f.entry, "entry", "", "entry of the bundle")
+}
+
+// load returns the provider type and raw config.
+func (f *providerFlags) load() (name string, rawConfig []byte, err error) {
+	if f.bundle != "" {
+		raw, err := os.ReadFile(f.bundle)
+		if err != nil {
+			return "", nil, err
+		}
+
+		entries, err := legotoolbox.ParseBundleEntries(raw)
+		if err != nil {
+			return "", nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Name == f.entry {
+				return entry.Type, entry.RawConfig, nil
+			}
+		}
+
+		return "", nil, fmt.Errorf("bundle %s: entry %q not found", f.bundle, f.entry)
+	}
+
+	if f.provider == "" {
+		return "", nil, errors.New("missing -provider or -bundle")
+	}
+
+	if f.config != "" {
+		rawConfig, err = os.ReadFile(f.config)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return f.provider, rawConfig, nil
+}
+
+// reference returns the flags with absolute paths, to load the config again from another directory.
+func (f *providerFlags) reference() (providerFlags, error) {
+	ref := *f
+
+	for _, p := range []*string{&ref.config, &ref.bundle} {
+		if *p == "" {
+			continue
+		}
+
+		abs, err := filepath.Abs(*p)
+		if err != nil {
+			return providerFlags{}, err
+		}
+
+		*p = abs
+	}
+
+	return ref, nil
+}
+
+// newProvider creates the provider of a command,
+// the state of the challenges of the stateful providers is persisted to challengeState.
+func newProvider(name string, rawConfig []byte, challengeState string) (challenge.Provider, error) {
+	provider, err := legotoolbox.NewDNSChallengeProviderByName(name, rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if challengeState == "" || !legotoolbox.IsStateful(provider) {
+		return provider, nil
+	}
+
+	var config struct {
+		StateFile string `yaml:"stateFile"`
+	}
+
+	err = yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		_ = legotoolbox.CloseProvider(provider)
+
+		return nil, err
+	}
+
+	// the state file of the config is shared with the other users of the config.
+	if config.StateFile != "" {
+		return provider, nil
+	}
+
+	err = legotoolbox.SetStateFile(provider, challengeState)
+	if err != nil {
+		_ = legotoolbox.CloseProvider(provider)
+
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return provider, nil
+}
+
+// challengeStatePath returns the state file of the challenges of a provider, next to the state file of the CLI.
+func challengeStatePath(statePath, name string) string {
+	name = strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(name)
+
+	return filepath.Join(filepath.Dir(statePath), "challenges", name+".json")
+}
+
+func runChallenge(command string, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var pf providerFlags
+	pf.register(fs)
+
+	domain := fs.String("domain", "", "domain of the challenge")
+	token := fs.String("token", "", "challenge token")
+	keyAuth := fs.String("key-auth", "", "key authorization (token.thumbprint)")
+	value := fs.String("value", "", "TXT record value, instead of -key-auth")
+	fqdn := fs.String("fqdn", "", "TXT record name, with -value (default: _acme-challenge.<domain>)")
+	statePath := fs.String("state", defaultStatePath(), "state file tracking the presented records")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *domain == "" {
+		return errors.New("missing -domain")
+	}
+
+	if *value != "" {
+		*keyAuth = rawrecord.KeyAuth(*fqdn, *value)
+	}
+
+	if *keyAuth == "" {
+		return errors.New("missing -key-auth or -value")
+	}
+
+	name, rawConfig, err := pf.load()
+	if err != nil {
+		return err
+	}
+
+	ref, err := pf.reference()
+	if err != nil {
+		return err
+	}
+
+	challengeState := challengeStatePath(*statePath, name)
+
+	provider, err := newProvider(name, rawConfig, challengeState)
+	if err != nil {
+		return err
+	}
+
+	rec := stateRecord{
+		Provider:       name,
+		Config:         ref.config,
+		Bundle:         ref.bundle,
+		Entry:          ref.entry,
+		ChallengeState: challengeState,
+		Domain:         *domain,
+		Token:          *token,
+		KeyAuth:        *keyAuth,
+		Presented:      time.Now(),
+	}
+
+	if command == "present" {
+		// closing the provider completes its pending changes (e.g. the batched publishes of dyn).
+		err = errors.Join(provider.Present(*domain, *token, *keyAuth), legotoolbox.CloseProvider(provider))
+		if err != nil {
+			return err
+		}
+
+		err = updateState(*statePath, func(s *state) { s.add(rec) })
+		if err != nil {
+			return fmt.Errorf("record presented, but the state was not saved: %w", err)
+		}
+
+		_, _ = fmt.Fprintf(stdout, "presented %s\n", rawrecord.GetChallengeInfo(*domain, *keyAuth).EffectiveFQDN)
+
+		return nil
+	}
+
+	err = errors.Join(provider.CleanUp(*domain, *token, *keyAuth), legotoolbox.CloseProvider(provider))
+	if err != nil {
+		return err
+	}
+
+	err = updateState(*statePath, func(s *state) { s.remove(rec) })
+	if err != nil {
+		return fmt.Errorf("record cleaned up, but the state was not saved: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(stdout, "cleaned up %s\n", rawrecord.GetChallengeInfo(*domain, *keyAuth).EffectiveFQDN)
+
+	return nil
+}
+
+func runCheckConfig(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("check-config", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var pf providerFlags
+	pf.register(fs)
+
+	strict := fs.Bool("strict", true, "reject the unknown keys")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if pf.bundle != "" && pf.entry == "" {
+		raw, err := os.ReadFile(pf.bundle)
+		if err != nil {
+			return err
+		}
+
+		entries, err := legotoolbox.ParseBundleEntries(raw)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, entry := range entries {
+			err := checkConfig(entry.Type, entry.RawConfig, *strict)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", entry.Name, err))
+				continue
+			}
+
+			_, _ = fmt.Fprintf(stdout, "%s (%s): ok\n", entry.Name, entry.Type)
+		}
+
+		return errors.Join(errs...)
+	}
+
+	name, rawConfig, err := pf.load()
+	if err != nil {
+		return err
+	}
+
+	err = checkConfig(name, rawConfig, *strict)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s: ok\n", name)
+
+	return nil
+}
+
+func checkConfig(name string, rawConfig []byte, strict bool) error {
+	if strict {
+		_, err := legotoolbox.ParseConfigStrict(name, rawConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	provider, err := legotoolbox.NewDNSChallengeProviderByName(name, rawConfig)
+	if err != nil {
+		return err
+	}
+
+	return legotoolbox.CloseProvider(provider)
+}
+
+func runTemplate(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("template", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	list := fs.Bool("list", false, "list the providers")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		for _, info := range legotoolbox.ListProviders() {
+			_, _ = fmt.Fprintln(stdout, info.Name)
+		}
+
+		return nil
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: lego-toolbox template <provider>")
+	}
+
+	raw, err := legotoolbox.GetDNSChallengeProviderConfigTemple(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	_, err = stdout.Write(raw)
+
+	return err
+}
+
+func runPurgeStale(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("purge-stale", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	olderThan := fs.Duration("older-than", time.Hour, "minimum age of the records to clean up")
+	dryRun := fs.Bool("dry-run", false, "only list the stale records")
+	statePath := fs.String("state", defaultStatePath(), "state file tracking the presented records")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	s, err := loadState(*statePath)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-*olderThan)
+
+	var errs []error
+
+	for _, rec := range s.Records {
+		if rec.Presented.After(deadline) {
+			continue
+		}
+
+		fqdn := rawrecord.GetChallengeInfo(rec.Domain, rec.KeyAuth).EffectiveFQDN
+
+		if *dryRun {
+			_, _ = fmt.Fprintf(stdout, "stale %s (%s, presented %s)\n", fqdn, rec.Provider, rec.Presented.Format(time.RFC3339))
+			continue
+		}
+
+		err := purge(rec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fqdn, err))
+			continue
+		}
+
+		err = updateState(*statePath, func(s *state) { s.remove(rec) })
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(stdout, "cleaned up %s\n", fqdn)
+	}
+
+	return errors.Join(errs...)
+}
+
+func purge(rec stateRecord) error {
+	pf := providerFlags{provider: rec.Provider, config: rec.Config, bundle: rec.Bundle, entry: rec.Entry}
+
+	name, rawConfig, err := pf.load()
+	if err != nil {
+		return err
+	}
+
+	provider, err := newProvider(name, rawConfig, rec.ChallengeState)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(provider.CleanUp(rec.Domain, rec.Token, rec.KeyAuth), legotoolbox.CloseProvider(provider))
+}
+
+func defaultStatePath() string {
+	if path := os.Getenv("LEGO_TOOLBOX_STATE"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "lego-toolbox", "state.json")
+}
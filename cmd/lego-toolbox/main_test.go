@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_presentCleanup(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	stdout := &bytes.Buffer{}
+
+	err := run([]string{"present", "-provider", "fake", "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "presented _acme-challenge.example.com.\n", stdout.String())
+
+	s, err := loadState(statePath)
+	require.NoError(t, err)
+	require.Len(t, s.Records, 1)
+	assert.Equal(t, "fake", s.Records[0].Provider)
+
+	stdout.Reset()
+
+	err = run([]string{"cleanup", "-provider", "fake", "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "cleaned up _acme-challenge.example.com.\n", stdout.String())
+
+	s, err = loadState(statePath)
+	require.NoError(t, err)
+	assert.Empty(t, s.Records)
+}
+
+func TestRun_purgeStale(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	err := updateState(statePath, func(s *state) {
+		s.add(stateRecord{Provider: "fake", Domain: "old.example.com", KeyAuth: "a", Presented: time.Now().Add(-2 * time.Hour)})
+		s.add(stateRecord{Provider: "fake", Domain: "new.example.com", KeyAuth: "b", Presented: time.Now()})
+	})
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+
+	err = run([]string{"purge-stale", "-older-than", "1h", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "cleaned up _acme-challenge.old.example.com.\n", stdout.String())
+
+	s, err := loadState(statePath)
+	require.NoError(t, err)
+	require.Len(t, s.Records, 1)
+	assert.Equal(t, "new.example.com", s.Records[0].Domain)
+}
+
+// newOPNsenseServer returns the base URL of a fake OPNsense API, and the UUIDs of the deleted host overrides.
+func newOPNsenseServer(t *testing.T) (string, func() []string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/unbound/settings/addHostOverride", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"result":"saved","uuid":"0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08"}`)
+	})
+
+	mux.HandleFunc("POST /api/unbound/settings/delHostOverride/{uuid}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		deleted = append(deleted, req.PathValue("uuid"))
+		mu.Unlock()
+
+		_, _ = fmt.Fprint(rw, `{"result":"deleted"}`)
+	})
+
+	mux.HandleFunc("POST /api/unbound/service/reconfigure", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"status":"ok"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return deleted
+	}
+}
+
+func TestRun_presentCleanup_recordIDs(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+
+	baseURL, deleted := newOPNsenseServer(t)
+
+	config := filepath.Join(dir, "opnsense.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(fmt.Sprintf("baseURL: %s\napiKey: key\napiSecret: secret\n", baseURL)), 0o600))
+
+	stdout := &bytes.Buffer{}
+
+	// each run creates its own provider, as separate processes do.
+	err := run([]string{"present", "-provider", "opnsense", "-config", config, "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, "challenges", "opnsense.json"))
+
+	err = run([]string{"cleanup", "-provider", "opnsense", "-config", config, "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08"}, deleted())
+}
+
+func TestRun_purgeStale_recordIDs(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+
+	baseURL, deleted := newOPNsenseServer(t)
+
+	config := filepath.Join(dir, "opnsense.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(fmt.Sprintf("baseURL: %s\napiKey: key\napiSecret: secret\n", baseURL)), 0o600))
+
+	stdout := &bytes.Buffer{}
+
+	err := run([]string{"present", "-provider", "opnsense", "-config", config, "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+
+	stdout.Reset()
+
+	err = run([]string{"purge-stale", "-older-than", "0s", "-state", statePath}, stdout, stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "cleaned up _acme-challenge.example.com.\n", stdout.String())
+
+	assert.Equal(t, []string{"0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08"}, deleted())
+
+	s, err := loadState(statePath)
+	require.NoError(t, err)
+	assert.Empty(t, s.Records)
+}
+
+func TestRun_present_configReference(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+
+	baseURL, _ := newOPNsenseServer(t)
+
+	config := filepath.Join(dir, "opnsense.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(fmt.Sprintf("baseURL: %s\napiKey: key\napiSecret: secret\n", baseURL)), 0o600))
+
+	err := run([]string{"present", "-provider", "opnsense", "-config", config, "-domain", "example.com", "-key-auth", "token.thumbprint", "-state", statePath}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+
+	s, err := loadState(statePath)
+	require.NoError(t, err)
+	require.Len(t, s.Records, 1)
+	assert.Equal(t, config, s.Records[0].Config)
+}
+
+func TestUpdateState_concurrent(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	var wg sync.WaitGroup
+
+	for i := range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := updateState(statePath, func(s *state) {
+				s.add(stateRecord{Provider: "fake", Domain: fmt.Sprintf("%d.example.com", i), KeyAuth: "a"})
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	s, err := loadState(statePath)
+	require.NoError(t, err)
+	assert.Len(t, s.Records, 20)
+}
+
+func TestRun_checkConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	bundle := filepath.Join(dir, "bundle.yaml")
+	require.NoError(t, os.WriteFile(bundle, []byte(`
+providers:
+  good:
+    type: fake
+    propagationTimeout: 1m
+  typo:
+    type: fake
+    propagationTimout: 1m
+`), 0o600))
+
+	stdout := &bytes.Buffer{}
+
+	err := run([]string{"check-config", "-bundle", bundle}, stdout, stdout)
+	require.ErrorContains(t, err, "typo: fake: yaml: unmarshal errors:")
+	assert.Equal(t, "good (fake): ok\n", stdout.String())
+}
+
+func TestRun_template(t *testing.T) {
+	stdout := &bytes.Buffer{}
+
+	err := run([]string{"template", "fake"}, stdout, stdout)
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "propagationTimeout")
+
+	err = run([]string{"template", "unknown"}, stdout, stdout)
+	require.EqualError(t, err, `dns provider "unknown" not supported`)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"lego-toolbox/internal/lockedfile"
+)
+
+// state tracks the records presented by the CLI and not cleaned up yet.
+type state struct {
+	Records []stateRecord `json:"records"`
+}
+
+type stateRecord struct {
+	Provider string `json:"provider"`
+	// Config, Bundle and Entry reference the config of the provider, read again to clean up the record:
+	// the state file holds no credentials.
+	Config string `json:"config,omitempty"`
+	Bundle string `json:"bundle,omitempty"`
+	Entry  string `json:"entry,omitempty"`
+	// ChallengeState is the state file of the challenges of the provider (e.g. the IDs of the created records).
+	ChallengeState string    `json:"challengeState,omitempty"`
+	Domain         string    `json:"domain"`
+	Token          string    `json:"token,omitempty"`
+	KeyAuth        string    `json:"keyAuth"`
+	Presented      time.Time `json:"presented"`
+}
+
+func (r stateRecord) same(o stateRecord) bool {
+	return r.Provider == o.Provider && r.Domain == o.Domain && r.Token == o.Token && r.KeyAuth == o.KeyAuth
+}
+
+func (s *state) add(rec stateRecord) {
+	s.remove(rec)
+	s.Records = append(s.Records, rec)
+}
+
+func (s *state) remove(rec stateRecord) {
+	s.Records = slices.DeleteFunc(s.Records, rec.same)
+}
+
+func loadState(path string) (*state, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &state{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &state{}
+
+	err = json.Unmarshal(raw, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// updateState updates the state file under its lock, so the concurrent runs don't lose their records.
+func updateState(path string, update func(s *state)) error {
+	unlock, err := lockedfile.Lock(path)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	s, err := loadState(path)
+	if err != nil {
+		return err
+	}
+
+	update(s)
+
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return lockedfile.WriteFile(path, raw, 0o600)
+}
@@ -0,0 +1,175 @@
+// Package cmwebhook exposes the toolbox providers behind the cert-manager DNS01 webhook solver API.
+//
+// The solver name of the Issuer is the provider name (or a provider instance ID),
+// the solver config is the provider config:
+//
+//	solvers:
+//	  - dns01:
+//	      webhook:
+//	        groupName: acme.example.com
+//	        solverName: cloudflare
+//	        config:
+//	          authToken: ...
+//
+// The server must be registered as an APIService (Kubernetes API aggregation) for the group.
+package cmwebhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	legotoolbox "lego-toolbox"
+	"lego-toolbox/rawrecord"
+)
+
+const (
+	apiVersion = "v1alpha1"
+	// maxBodySize is the maximum size of a ChallengeReview.
+	maxBodySize = 1 << 20
+)
+
+// ProviderFunc returns the provider solving the challenges of the solver.
+type ProviderFunc func(solver string, req *ChallengeRequest) (challenge.Provider, error)
+
+// Server serves the cert-manager webhook solver API.
+type Server struct {
+	groupName string
+	provider  ProviderFunc
+	mux       *http.ServeMux
+}
+
+// NewServer creates a server for the API group (the groupName of the Issuers).
+// A nil provider func uses DefaultProvider.
+func NewServer(groupName string, provider ProviderFunc) *Server {
+	if provider == nil {
+		provider = DefaultProvider
+	}
+
+	s := &Server{
+		groupName: groupName,
+		provider:  provider,
+		mux:       http.NewServeMux(),
+	}
+
+	prefix := "/apis/" + groupName + "/" + apiVersion
+
+	s.mux.HandleFunc("GET /healthz", func(rw http.ResponseWriter, _ *http.Request) { _, _ = rw.Write([]byte("ok")) })
+	s.mux.HandleFunc("GET "+prefix, s.discovery)
+	s.mux.HandleFunc("POST "+prefix+"/{solver}", s.solve)
+
+	return s
+}
+
+// DefaultProvider uses the provider instance registered with the solver name (see legotoolbox.RegisterProviderInstance),
+// or creates a provider named by the solver from the solver config.
+func DefaultProvider(solver string, req *ChallengeRequest) (challenge.Provider, error) {
+	if provider, err := legotoolbox.GetProviderInstance(solver); err == nil {
+		return provider, nil
+	}
+
+	return legotoolbox.NewDNSChallengeProviderByName(solver, req.Config)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(rw, req)
+}
+
+// ListenAndServeTLS serves the API on addr, the API aggregation requires TLS.
+func (s *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	return (&http.Server{Addr: addr, Handler: s}).ListenAndServeTLS(certFile, keyFile)
+}
+
+func (s *Server) discovery(rw http.ResponseWriter, _ *http.Request) {
+	var resources []map[string]any
+
+	for _, info := range legotoolbox.ListProviders() {
+		for _, name := range append([]string{info.Name}, info.Aliases...) {
+			resources = append(resources, map[string]any{
+				"name":       name,
+				"namespaced": false,
+				"kind":       "ChallengePayload",
+				"verbs":      []string{"create"},
+			})
+		}
+	}
+
+	writeJSON(rw, http.StatusOK, map[string]any{
+		"kind":         "APIResourceList",
+		"apiVersion":   "v1",
+		"groupVersion": s.groupName + "/" + apiVersion,
+		"resources":    resources,
+	})
+}
+
+func (s *Server) solve(rw http.ResponseWriter, req *http.Request) {
+	var review ChallengeReview
+
+	err := json.NewDecoder(io.LimitReader(req.Body, maxBodySize)).Decode(&review)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid ChallengeReview: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if review.Request == nil {
+		http.Error(rw, "invalid ChallengeReview: missing request", http.StatusBadRequest)
+		return
+	}
+
+	response := &ChallengeResponse{UID: review.Request.UID, Success: true}
+
+	err = s.handle(req.PathValue("solver"), review.Request)
+	if err != nil {
+		log.Warnf("cmwebhook: %s %s: %v", review.Request.Action, review.Request.DNSName, err)
+
+		response.Success = false
+		response.Result = &Status{Status: "Failure", Message: err.Error(), Reason: "InternalError", Code: http.StatusInternalServerError}
+	}
+
+	writeJSON(rw, http.StatusOK, ChallengeReview{
+		APIVersion: APIVersion,
+		Kind:       "ChallengeReview",
+		Request:    review.Request,
+		Response:   response,
+	})
+}
+
+func (s *Server) handle(solver string, req *ChallengeRequest) error {
+	if req.Type != "" && req.Type != "dns-01" {
+		return fmt.Errorf("unsupported challenge type %q", req.Type)
+	}
+
+	provider, err := s.provider(solver, req)
+	if err != nil {
+		return err
+	}
+
+	domain := strings.TrimPrefix(strings.TrimSuffix(req.DNSName, "."), "*.")
+	keyAuth := rawrecord.KeyAuth(req.ResolvedFQDN, req.Key)
+
+	// cert-manager sends different UIDs to Present and CleanUp,
+	// the token must identify the record for the providers tracking their records by token.
+	token := req.ResolvedFQDN + "/" + req.Key
+
+	switch req.Action {
+	case ActionPresent:
+		return provider.Present(domain, token, keyAuth)
+	case ActionCleanUp:
+		return provider.CleanUp(domain, token, keyAuth)
+	default:
+		return errors.New("unsupported action: " + string(req.Action))
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(v)
+}
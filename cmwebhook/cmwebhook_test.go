@@ -0,0 +1,126 @@
+package cmwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+)
+
+func setupServer(t *testing.T, provider challenge.Provider) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(NewServer("acme.example.com", func(solver string, _ *ChallengeRequest) (challenge.Provider, error) {
+		assert.Equal(t, "fake", solver)
+		return provider, nil
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func post(t *testing.T, server *httptest.Server, request *ChallengeRequest) *ChallengeResponse {
+	t.Helper()
+
+	body, err := json.Marshal(ChallengeReview{APIVersion: APIVersion, Kind: "ChallengeReview", Request: request})
+	require.NoError(t, err)
+
+	resp, err := http.Post(server.URL+"/apis/acme.example.com/v1alpha1/fake", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var review ChallengeReview
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	require.NotNil(t, review.Response)
+
+	return review.Response
+}
+
+func TestServer_solve(t *testing.T) {
+	provider, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	server := setupServer(t, provider)
+
+	request := &ChallengeRequest{
+		UID:          "1",
+		Action:       ActionPresent,
+		Type:         "dns-01",
+		DNSName:      "*.example.com",
+		Key:          "txt-value",
+		ResolvedFQDN: "_acme-challenge.delegated.example.net.",
+		ResolvedZone: "example.net.",
+	}
+
+	response := post(t, server, request)
+	assert.True(t, response.Success)
+	assert.Equal(t, "1", response.UID)
+
+	assert.Equal(t, []string{"txt-value"}, provider.TXT("_acme-challenge.delegated.example.net."))
+
+	calls := provider.CallsOf(fake.CallPresent)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "example.com", calls[0].Domain)
+
+	request.UID = "2"
+	request.Action = ActionCleanUp
+
+	response = post(t, server, request)
+	assert.True(t, response.Success)
+
+	provider.AssertNoRecords(t)
+	assert.Equal(t, calls[0].Token, provider.CallsOf(fake.CallCleanUp)[0].Token)
+}
+
+func TestServer_solve_failure(t *testing.T) {
+	config := fake.DefaultConfig()
+	config.FailPresentOn = []int{1}
+
+	provider, err := fake.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	server := setupServer(t, provider)
+
+	response := post(t, server, &ChallengeRequest{
+		UID:          "1",
+		Action:       ActionPresent,
+		Type:         "dns-01",
+		DNSName:      "example.com",
+		Key:          "txt-value",
+		ResolvedFQDN: "_acme-challenge.example.com.",
+	})
+
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Result)
+	assert.Contains(t, response.Result.Message, "fake: present _acme-challenge.example.com.")
+}
+
+func TestServer_discovery(t *testing.T) {
+	server := httptest.NewServer(NewServer("acme.example.com", nil))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/apis/acme.example.com/v1alpha1")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var list struct {
+		GroupVersion string `json:"groupVersion"`
+		Resources    []struct {
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+
+	assert.Equal(t, "acme.example.com/v1alpha1", list.GroupVersion)
+	assert.NotEmpty(t, list.Resources)
+}
@@ -0,0 +1,56 @@
+package cmwebhook
+
+import "encoding/json"
+
+// APIVersion is the version of the cert-manager webhook solver API.
+const APIVersion = "webhook.acme.cert-manager.io/v1alpha1"
+
+// ChallengeAction is the action requested by cert-manager.
+type ChallengeAction string
+
+// Actions.
+const (
+	ActionPresent ChallengeAction = "Present"
+	ActionCleanUp ChallengeAction = "CleanUp"
+)
+
+// ChallengeReview is the payload exchanged with cert-manager.
+type ChallengeReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *ChallengeRequest  `json:"request,omitempty"`
+	Response   *ChallengeResponse `json:"response,omitempty"`
+}
+
+// ChallengeRequest is a DNS-01 challenge sent by cert-manager.
+type ChallengeRequest struct {
+	UID    string          `json:"uid"`
+	Action ChallengeAction `json:"action"`
+	Type   string          `json:"type"`
+	// DNSName is the name of the domain being validated.
+	DNSName string `json:"dnsName"`
+	// Key is the TXT record value (not the key authorization).
+	Key               string `json:"key"`
+	ResourceNamespace string `json:"resourceNamespace"`
+	// ResolvedFQDN is the record name, CNAMEs already followed by cert-manager.
+	ResolvedFQDN            string `json:"resolvedFQDN"`
+	ResolvedZone            string `json:"resolvedZone"`
+	AllowAmbientCredentials bool   `json:"allowAmbientCredentials"`
+	// Config is the solver config of the Issuer.
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// ChallengeResponse is the result of a challenge request.
+type ChallengeResponse struct {
+	UID     string  `json:"uid"`
+	Success bool    `json:"success"`
+	Result  *Status `json:"status,omitempty"`
+}
+
+// Status describes a failure, like the Kubernetes metav1.Status.
+type Status struct {
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Code    int32  `json:"code,omitempty"`
+}
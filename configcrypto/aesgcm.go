@@ -0,0 +1,112 @@
+package configcrypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// aesGCMPrefix starts the AES-GCM encrypted configs, followed by base64(nonce || ciphertext).
+const aesGCMPrefix = "aesgcm:v1:"
+
+// AESGCM encrypts and decrypts the configs with AES-GCM.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM creates an AES-GCM decrypter, the key must be 16, 24 or 32 bytes long.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: %w", err)
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+// NewAESGCMFromBase64 creates an AES-GCM decrypter from a base64 encoded key.
+func NewAESGCMFromBase64(key string) (*AESGCM, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: invalid key: %w", err)
+	}
+
+	return NewAESGCM(raw)
+}
+
+// KMS decrypts data keys with a key management service (AWS KMS, Cloud KMS, Vault transit, ...).
+type KMS interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// NewAESGCMFromKMS decrypts the wrapped data key with the KMS (envelope encryption)
+// and creates an AES-GCM decrypter using it.
+func NewAESGCMFromKMS(ctx context.Context, kms KMS, wrappedKey []byte) (*AESGCM, error) {
+	if kms == nil {
+		return nil, errors.New("configcrypto: aes-gcm: KMS is nil")
+	}
+
+	key, err := kms.Decrypt(ctx, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: KMS: %w", err)
+	}
+
+	defer clear(key)
+
+	return NewAESGCM(key)
+}
+
+// Supports reports whether the format is FormatAESGCM.
+func (a *AESGCM) Supports(format Format) bool {
+	return format == FormatAESGCM
+}
+
+// Decrypt decrypts the AES-GCM encrypted blob.
+func (a *AESGCM) Decrypt(blob []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(blob)
+	if !bytes.HasPrefix(trimmed, []byte(aesGCMPrefix)) {
+		return nil, errors.New("configcrypto: aes-gcm: missing prefix " + aesGCMPrefix)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(trimmed[len(aesGCMPrefix):]))
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: %w", err)
+	}
+
+	size := a.aead.NonceSize()
+	if len(raw) < size {
+		return nil, errors.New("configcrypto: aes-gcm: ciphertext too short")
+	}
+
+	plaintext, err := a.aead.Open(nil, raw[:size], raw[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Encrypt encrypts the config.
+func (a *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: aes-gcm: %w", err)
+	}
+
+	sealed := a.aead.Seal(nonce, nonce, plaintext, nil)
+
+	return []byte(aesGCMPrefix + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
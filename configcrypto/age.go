@@ -0,0 +1,93 @@
+package configcrypto
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Age decrypts the configs encrypted with age (binary or armored).
+type Age struct {
+	identities []age.Identity
+}
+
+// NewAge creates an age decrypter.
+func NewAge(identities ...age.Identity) *Age {
+	return &Age{identities: identities}
+}
+
+// ParseAgeIdentities creates an age decrypter from identities (AGE-SECRET-KEY-...), one per line.
+func ParseAgeIdentities(identities string) (*Age, error) {
+	ids, err := age.ParseIdentities(strings.NewReader(identities))
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	return NewAge(ids...), nil
+}
+
+// Supports reports whether the format is FormatAge.
+func (a *Age) Supports(format Format) bool {
+	return format == FormatAge
+}
+
+// Decrypt decrypts the age encrypted blob.
+func (a *Age) Decrypt(blob []byte) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(blob)
+
+	if bytes.HasPrefix(bytes.TrimSpace(blob), []byte(ageArmorHeader)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(blob)))
+	}
+
+	r, err := age.Decrypt(src, a.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// EncryptAge encrypts the config for the recipients (age1...), as an armored age file.
+func EncryptAge(plaintext []byte, recipients ...string) ([]byte, error) {
+	rs := make([]age.Recipient, 0, len(recipients))
+
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("configcrypto: age: %w", err)
+		}
+
+		rs = append(rs, r)
+	}
+
+	buf := &bytes.Buffer{}
+	armored := armor.NewWriter(buf)
+
+	w, err := age.Encrypt(armored, rs...)
+	if err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	if _, err = w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	if err = armored.Close(); err != nil {
+		return nil, fmt.Errorf("configcrypto: age: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,143 @@
+// Package configcrypto decrypts the provider YAML configs encrypted at rest, with age or AES-GCM.
+//
+// The keys come from the environment (see FromEnv) or from a key management service (see NewAESGCMFromKMS).
+package configcrypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+)
+
+// Environment variables holding the keys.
+// The keys can also be read from a file, by suffixing the variable with `_FILE`.
+const (
+	// EnvAgeIdentity is the age identities (AGE-SECRET-KEY-...), one per line.
+	EnvAgeIdentity = "LEGO_TOOLBOX_AGE_IDENTITY"
+	// EnvAESKey is the base64 encoded AES key (16, 24 or 32 bytes).
+	EnvAESKey = "LEGO_TOOLBOX_AES_KEY"
+)
+
+// Format is the format of a config blob.
+type Format int
+
+// Formats.
+const (
+	FormatPlain Format = iota
+	FormatAge
+	FormatAESGCM
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatAge:
+		return "age"
+	case FormatAESGCM:
+		return "aes-gcm"
+	default:
+		return "plain"
+	}
+}
+
+var (
+	// ErrNotEncrypted is returned when decrypting a plaintext config.
+	ErrNotEncrypted = errors.New("configcrypto: the config is not encrypted")
+	// ErrNoKey is returned when no key can decrypt the format of the config.
+	ErrNoKey = errors.New("configcrypto: no key for the config format")
+)
+
+const (
+	ageHeader      = "age-encryption.org/v1"
+	ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+)
+
+// Detect returns the format of the blob.
+func Detect(blob []byte) Format {
+	trimmed := bytes.TrimSpace(blob)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(ageHeader)), bytes.HasPrefix(trimmed, []byte(ageArmorHeader)):
+		return FormatAge
+	case bytes.HasPrefix(trimmed, []byte(aesGCMPrefix)):
+		return FormatAESGCM
+	default:
+		return FormatPlain
+	}
+}
+
+// Decrypter decrypts the config blobs.
+type Decrypter interface {
+	// Supports reports whether the decrypter handles the format.
+	Supports(format Format) bool
+	// Decrypt returns the plaintext config.
+	Decrypt(blob []byte) ([]byte, error)
+}
+
+// Decrypt decrypts the blob with the decrypter handling its format.
+// Plaintext blobs are rejected with ErrNotEncrypted.
+func Decrypt(blob []byte, decrypters ...Decrypter) ([]byte, error) {
+	format := Detect(blob)
+	if format == FormatPlain {
+		return nil, ErrNotEncrypted
+	}
+
+	for _, d := range decrypters {
+		if d != nil && d.Supports(format) {
+			return d.Decrypt(blob)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoKey, format)
+}
+
+// Chain is a Decrypter dispatching to the decrypter handling the format of the blob.
+type Chain []Decrypter
+
+// Supports reports whether one of the decrypters handles the format.
+func (c Chain) Supports(format Format) bool {
+	for _, d := range c {
+		if d != nil && d.Supports(format) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Decrypt decrypts the blob with the decrypter handling its format.
+func (c Chain) Decrypt(blob []byte) ([]byte, error) {
+	return Decrypt(blob, c...)
+}
+
+// FromEnv returns the decrypters configured by the environment variables
+// (EnvAgeIdentity, EnvAESKey).
+func FromEnv() (Chain, error) {
+	var chain Chain
+
+	if identities := env.GetOrFile(EnvAgeIdentity); strings.TrimSpace(identities) != "" {
+		d, err := ParseAgeIdentities(identities)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, d)
+	}
+
+	if key := env.GetOrFile(EnvAESKey); key != "" {
+		d, err := NewAESGCMFromBase64(key)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, d)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("configcrypto: no key: set %s or %s", EnvAgeIdentity, EnvAESKey)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,148 @@
+package configcrypto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const plaintext = "authToken: secret\nttl: 120\n"
+
+func TestAge(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	blob, err := EncryptAge([]byte(plaintext), identity.Recipient().String())
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatAge, Detect(blob))
+	assert.NotContains(t, string(blob), "secret")
+
+	d, err := ParseAgeIdentities(identity.String())
+	require.NoError(t, err)
+
+	decrypted, err := Decrypt(blob, d)
+	require.NoError(t, err)
+
+	assert.Equal(t, plaintext, string(decrypted))
+}
+
+func TestAESGCM(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	a, err := NewAESGCM(key)
+	require.NoError(t, err)
+
+	blob, err := a.Encrypt([]byte(plaintext))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatAESGCM, Detect(blob))
+
+	decrypted, err := Decrypt(blob, a)
+	require.NoError(t, err)
+
+	assert.Equal(t, plaintext, string(decrypted))
+
+	other, err := NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(blob)
+	require.EqualError(t, err, "configcrypto: aes-gcm: cipher: message authentication failed")
+}
+
+func TestNewAESGCM_invalidKey(t *testing.T) {
+	_, err := NewAESGCM([]byte("short"))
+	require.EqualError(t, err, "configcrypto: aes-gcm: crypto/aes: invalid key size 5")
+}
+
+type fakeKMS map[string][]byte
+
+func (f fakeKMS) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	key, ok := f[string(ciphertext)]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+
+	return append([]byte(nil), key...), nil
+}
+
+func TestNewAESGCMFromKMS(t *testing.T) {
+	key := make([]byte, 16)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	kms := fakeKMS{"wrapped": key}
+
+	enc, err := NewAESGCM(key)
+	require.NoError(t, err)
+
+	blob, err := enc.Encrypt([]byte(plaintext))
+	require.NoError(t, err)
+
+	d, err := NewAESGCMFromKMS(context.Background(), kms, []byte("wrapped"))
+	require.NoError(t, err)
+
+	decrypted, err := d.Decrypt(blob)
+	require.NoError(t, err)
+
+	assert.Equal(t, plaintext, string(decrypted))
+
+	_, err = NewAESGCMFromKMS(context.Background(), kms, []byte("other"))
+	require.EqualError(t, err, "configcrypto: aes-gcm: KMS: unknown key")
+}
+
+func TestDecrypt_errors(t *testing.T) {
+	_, err := Decrypt([]byte(plaintext))
+	require.ErrorIs(t, err, ErrNotEncrypted)
+
+	_, err = Decrypt([]byte("aesgcm:v1:AAAA"), NewAge())
+	require.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestFromEnv(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	require.NoError(t, err)
+
+	t.Setenv(EnvAgeIdentity, identity.String())
+	t.Setenv(EnvAESKey, base64.StdEncoding.EncodeToString(key))
+
+	chain, err := FromEnv()
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+
+	ageBlob, err := EncryptAge([]byte(plaintext), identity.Recipient().String())
+	require.NoError(t, err)
+
+	enc, err := NewAESGCM(key)
+	require.NoError(t, err)
+
+	aesBlob, err := enc.Encrypt([]byte(plaintext))
+	require.NoError(t, err)
+
+	for _, blob := range [][]byte{ageBlob, aesBlob} {
+		decrypted, err := chain.Decrypt(blob)
+		require.NoError(t, err)
+
+		assert.Equal(t, plaintext, string(decrypted))
+	}
+}
+
+func TestFromEnv_noKey(t *testing.T) {
+	t.Setenv(EnvAgeIdentity, "")
+	t.Setenv(EnvAESKey, "")
+
+	_, err := FromEnv()
+	require.EqualError(t, err, "configcrypto: no key: set LEGO_TOOLBOX_AGE_IDENTITY or LEGO_TOOLBOX_AES_KEY")
+}
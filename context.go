@@ -0,0 +1,101 @@
+package legotoolbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// ContextProvider is implemented by the providers whose Present and CleanUp can be canceled,
+// typically the providers waiting for a vendor-side job (zone sync, change propagation).
+// Present and CleanUp are the calls with context.Background().
+type ContextProvider interface {
+	challenge.Provider
+	PresentContext(ctx context.Context, domain, token, keyAuth string) error
+	CleanUpContext(ctx context.Context, domain, token, keyAuth string) error
+}
+
+// PresentContext calls the PresentContext method of the provider, or Present if the provider does not implement ContextProvider.
+func PresentContext(ctx context.Context, provider challenge.Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ContextProvider); ok {
+		return p.PresentContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.Present(domain, token, keyAuth)
+}
+
+// CleanUpContext calls the CleanUpContext method of the provider, or CleanUp if the provider does not implement ContextProvider.
+func CleanUpContext(ctx context.Context, provider challenge.Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ContextProvider); ok {
+		return p.CleanUpContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.CleanUp(domain, token, keyAuth)
+}
+
+// WithContext binds the provider to ctx: its Present and CleanUp calls are canceled with ctx,
+// e.g. to abort the waits of the provider when the order is canceled:
+//
+//	client.Challenge.SetDNS01Provider(legotoolbox.WithContext(ctx, provider))
+//
+// The timing and sequential behavior of the provider are preserved.
+func WithContext(ctx context.Context, provider challenge.Provider) challenge.Provider {
+	return decorate(&contextProvider{Provider: provider, ctx: ctx})
+}
+
+type contextProvider struct {
+	challenge.Provider
+
+	ctx context.Context
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *contextProvider) Present(domain, token, keyAuth string) error {
+	return PresentContext(p.ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *contextProvider) CleanUp(domain, token, keyAuth string) error {
+	return CleanUpContext(p.ctx, p.Provider, domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record, canceled with ctx or with the bound context.
+func (p *contextProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	ctx, cancel := mergeContexts(ctx, p.ctx)
+	defer cancel()
+
+	return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record, canceled with ctx or with the bound context.
+func (p *contextProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	ctx, cancel := mergeContexts(ctx, p.ctx)
+	defer cancel()
+
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *contextProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *contextProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
+
+// mergeContexts returns a context canceled when ctx or other is done.
+func mergeContexts(ctx, other context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancelCause(ctx)
+
+	stop := context.AfterFunc(other, func() {
+		cancel(context.Cause(other))
+	})
+
+	return merged, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,61 @@
+package legotoolbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/events"
+)
+
+// blockingProvider waits for the context in PresentContext and CleanUpContext.
+type blockingProvider struct {
+	basicProvider
+}
+
+func (blockingProvider) PresentContext(ctx context.Context, _, _, _ string) error {
+	<-ctx.Done()
+	return context.Cause(ctx)
+}
+
+func (blockingProvider) CleanUpContext(ctx context.Context, _, _, _ string) error {
+	<-ctx.Done()
+	return context.Cause(ctx)
+}
+
+func TestPresentContext_fallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, PresentContext(ctx, basicProvider{}, "example.com", "token", "keyAuth"))
+	require.NoError(t, CleanUpContext(ctx, basicProvider{}, "example.com", "token", "keyAuth"))
+}
+
+func TestWithContext(t *testing.T) {
+	errCanceled := errors.New("order canceled")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	provider := WithContext(ctx, WithEvents(WithTimeouts(blockingProvider{}, time.Minute, time.Second), "blocking", events.NewBus()))
+
+	time.AfterFunc(10*time.Millisecond, func() { cancel(errCanceled) })
+
+	require.ErrorIs(t, provider.Present("example.com", "token", "keyAuth"), errCanceled)
+	require.ErrorIs(t, provider.CleanUp("example.com", "token", "keyAuth"), errCanceled)
+
+	timeout, interval := providerTimeout(provider)
+	assert.Equal(t, time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+}
+
+func TestWithContext_callContext(t *testing.T) {
+	provider := WithContext(context.Background(), WithDomainAliases(blockingProvider{}, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, PresentContext(ctx, provider, "example.com", "token", "keyAuth"), context.DeadlineExceeded)
+}
@@ -0,0 +1,170 @@
+// Package delegation delegates the ACME challenges of a zone to another zone with a CNAME.
+//
+// The CNAME `_acme-challenge.<domain>. → <domain>.<target zone>.` is created once in the source zone,
+// the challenges are then presented in the target zone only:
+// the credentials of the source zone are only needed to set up the delegation,
+// and the credentials of the target zone can only change the challenge records.
+//
+// The source and target providers may be different providers.
+// The source provider must create CNAME records (implement CNAMESetter), like rfc2136.
+package delegation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	legotoolbox "lego-toolbox"
+	"lego-toolbox/rawrecord"
+)
+
+// CNAMESetter is implemented by the providers creating CNAME records.
+type CNAMESetter interface {
+	// SetCNAME creates the CNAME record from fqdn to target, replacing the existing one.
+	SetCNAME(fqdn, target string) error
+}
+
+// LookupFunc returns the canonical name of the FQDN (the FQDN itself when it is not an alias).
+type LookupFunc func(ctx context.Context, fqdn string) (string, error)
+
+// Config is used to configure the creation of the Delegator.
+type Config struct {
+	// TargetZone is the zone receiving the challenges, e.g. `acme.example.net`.
+	TargetZone string
+	// Lookup checks the existing delegation before creating the CNAME.
+	// Defaults to net.DefaultResolver.LookupCNAME.
+	Lookup LookupFunc
+	// LookupTimeout bounds the check of the existing delegation.
+	LookupTimeout time.Duration
+}
+
+// Delegator implements the challenge.Provider interface:
+// it sets up the delegation of the domain on the first challenge, then presents the challenges in the target zone.
+type Delegator struct {
+	source CNAMESetter
+	target challenge.Provider
+	config Config
+
+	mu        sync.Mutex
+	delegated map[string]bool
+}
+
+// New returns a Delegator creating the CNAME records with source and presenting the challenges with target.
+// source must implement CNAMESetter, possibly behind the decorators of legotoolbox.
+func New(source, target challenge.Provider, config Config) (*Delegator, error) {
+	setter, ok := legotoolbox.Unwrap(source).(CNAMESetter)
+	if !ok {
+		return nil, fmt.Errorf("delegation: the source provider %T cannot create CNAME records", legotoolbox.Unwrap(source))
+	}
+
+	if target == nil {
+		return nil, errors.New("delegation: the target provider is nil")
+	}
+
+	if config.TargetZone == "" {
+		return nil, errors.New("delegation: the target zone is required")
+	}
+
+	config.TargetZone = dns01.ToFqdn(strings.ToLower(config.TargetZone))
+
+	if config.Lookup == nil {
+		config.Lookup = net.DefaultResolver.LookupCNAME
+	}
+
+	if config.LookupTimeout <= 0 {
+		config.LookupTimeout = 10 * time.Second
+	}
+
+	return &Delegator{
+		source:    setter,
+		target:    target,
+		config:    config,
+		delegated: make(map[string]bool),
+	}, nil
+}
+
+// NewByName returns a Delegator with the providers created by legotoolbox.NewDNSChallengeProviderByName.
+func NewByName(sourceName string, sourceConfig []byte, targetName string, targetConfig []byte, config Config) (*Delegator, error) {
+	source, err := legotoolbox.NewDNSChallengeProviderByName(sourceName, sourceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("delegation: source provider: %w", err)
+	}
+
+	target, err := legotoolbox.NewDNSChallengeProviderByName(targetName, targetConfig)
+	if err != nil {
+		return nil, fmt.Errorf("delegation: target provider: %w", err)
+	}
+
+	return New(source, target, config)
+}
+
+// Target returns the name of the challenge record of the domain in the target zone.
+func (d *Delegator) Target(domain string) string {
+	return dns01.ToFqdn(strings.ToLower(dns01.UnFqdn(domain))) + d.config.TargetZone
+}
+
+// Delegate creates the CNAME of the domain, unless it already points to the target zone.
+func (d *Delegator) Delegate(domain string) error {
+	domain = strings.ToLower(dns01.UnFqdn(domain))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.delegated[domain] {
+		return nil
+	}
+
+	fqdn := "_acme-challenge." + dns01.ToFqdn(domain)
+	target := d.Target(domain)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.LookupTimeout)
+	defer cancel()
+
+	// a lookup failure (no record yet) means the CNAME must be created.
+	current, err := d.config.Lookup(ctx, fqdn)
+	if err != nil || !strings.EqualFold(dns01.ToFqdn(current), target) {
+		err = d.source.SetCNAME(fqdn, target)
+		if err != nil {
+			return fmt.Errorf("delegation: create CNAME %s → %s: %w", fqdn, target, err)
+		}
+	}
+
+	d.delegated[domain] = true
+
+	return nil
+}
+
+// Present sets up the delegation of the domain if needed, and creates the TXT record in the target zone.
+func (d *Delegator) Present(domain, token, keyAuth string) error {
+	err := d.Delegate(domain)
+	if err != nil {
+		return err
+	}
+
+	return d.target.Present(domain, token, d.keyAuth(domain, keyAuth))
+}
+
+// CleanUp removes the TXT record from the target zone, the CNAME is kept.
+func (d *Delegator) CleanUp(domain, token, keyAuth string) error {
+	return d.target.CleanUp(domain, token, d.keyAuth(domain, keyAuth))
+}
+
+// Timeout returns the timeout and interval of the target provider.
+func (d *Delegator) Timeout() (timeout, interval time.Duration) {
+	if p, ok := d.target.(challenge.ProviderTimeout); ok {
+		return p.Timeout()
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+// keyAuth returns the key authorization writing the record of the challenge in the target zone.
+func (d *Delegator) keyAuth(domain, keyAuth string) string {
+	return rawrecord.KeyAuth(d.Target(domain), rawrecord.GetChallengeInfo(domain, keyAuth).Value)
+}
@@ -0,0 +1,99 @@
+package delegation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/exec"
+	"lego-toolbox/providers/dns/fake"
+)
+
+func newFake(t *testing.T) *fake.DNSProvider {
+	t.Helper()
+
+	provider, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	return provider
+}
+
+// lookupFrom resolves the CNAME records of the fake provider.
+func lookupFrom(provider *fake.DNSProvider) LookupFunc {
+	return func(_ context.Context, fqdn string) (string, error) {
+		if target := provider.CNAME(fqdn); target != "" {
+			return target, nil
+		}
+
+		return "", errors.New("no such host")
+	}
+}
+
+func TestDelegator(t *testing.T) {
+	source := newFake(t)
+	target := newFake(t)
+
+	delegator, err := New(source, target, Config{TargetZone: "ACME.example.net", Lookup: lookupFrom(source)})
+	require.NoError(t, err)
+
+	err = delegator.Present("www.example.com", "token1", "keyAuth1")
+	require.NoError(t, err)
+
+	err = delegator.Present("www.example.com", "token2", "keyAuth2")
+	require.NoError(t, err)
+
+	assert.Equal(t, "www.example.com.acme.example.net.", source.CNAME("_acme-challenge.www.example.com."))
+	source.AssertCallCount(t, fake.CallSetCNAME, 1)
+	source.AssertCallCount(t, fake.CallPresent, 0)
+
+	expected := []string{
+		dns01.GetChallengeInfo("www.example.com", "keyAuth1").Value,
+		dns01.GetChallengeInfo("www.example.com", "keyAuth2").Value,
+	}
+	assert.Equal(t, expected, target.TXT("www.example.com.acme.example.net."))
+
+	require.NoError(t, delegator.CleanUp("www.example.com", "token1", "keyAuth1"))
+	require.NoError(t, delegator.CleanUp("www.example.com", "token2", "keyAuth2"))
+
+	target.AssertNoRecords(t)
+	assert.NotEmpty(t, source.CNAME("_acme-challenge.www.example.com."))
+}
+
+func TestDelegator_existingDelegation(t *testing.T) {
+	source := newFake(t)
+	target := newFake(t)
+
+	lookup := func(_ context.Context, fqdn string) (string, error) {
+		assert.Equal(t, "_acme-challenge.example.com.", fqdn)
+		return "example.com.acme.example.net", nil
+	}
+
+	delegator, err := New(source, target, Config{TargetZone: "acme.example.net.", Lookup: lookup})
+	require.NoError(t, err)
+
+	require.NoError(t, delegator.Present("example.com", "token", "keyAuth"))
+
+	source.AssertCallCount(t, fake.CallSetCNAME, 0)
+	assert.Len(t, target.TXT("example.com.acme.example.net."), 1)
+}
+
+func TestNew_errors(t *testing.T) {
+	source, err := exec.NewDNSProviderConfig(exec.DefaultConfig())
+	require.NoError(t, err)
+
+	_, err = New(source, newFake(t), Config{TargetZone: "acme.example.net"})
+	require.EqualError(t, err, "delegation: the source provider *exec.DNSProvider cannot create CNAME records")
+
+	_, err = New(newFake(t), newFake(t), Config{})
+	require.EqualError(t, err, "delegation: the target zone is required")
+}
+
+func TestNewByName(t *testing.T) {
+	delegator, err := NewByName("fake", nil, "fake", nil, Config{TargetZone: "acme.example.net"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.acme.example.net.", delegator.Target("Example.com."))
+}
@@ -1,9 +1,13 @@
 package legotoolbox
 
 import (
+	"errors"
 	"fmt"
 	"lego-toolbox/providers/dns/alidns"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestName(t *testing.T) {
@@ -14,3 +18,21 @@ func TestName(t *testing.T) {
 	}
 	fmt.Println(config)
 }
+
+func TestGetDNSChallengeProviderConfigTemple(t *testing.T) {
+	for _, info := range ListProviders() {
+		name := info.Name
+
+		t.Run(name, func(t *testing.T) {
+			raw, err := GetDNSChallengeProviderConfigTemple(name)
+			if errors.Is(err, errEnvOnlyProvider) {
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, raw)
+
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal(raw, &node))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package legotoolbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// domainAliasesConfig is the part of the provider configs publishing the challenges of some domains at another name,
+// like the domain alias mode of acme.sh (`--domain-alias`).
+//
+//	domainAliases:
+//	  example.com: _acme-challenge.validation.example.net
+//	  "*.example.org": _acme-challenge.validation.example.net
+type domainAliasesConfig struct {
+	DomainAliases map[string]string `yaml:"domainAliases"`
+}
+
+// WithDomainAliases publishes the TXT record of the challenges of the domains at their alias,
+// the name the `_acme-challenge.<domain>` CNAME points to (the CNAME must already exist).
+// The aliases are keyed by domain, a wildcard domain (`*.example.com`) is the same as its base domain.
+// The other domains are published as usual.
+// The providers lacking the CNAMEFollow capability (e.g. acme-dns) ignore the alias.
+// The timing and sequential behavior of the provider are preserved.
+func WithDomainAliases(provider challenge.Provider, aliases map[string]string) challenge.Provider {
+	normalized := make(map[string]string, len(aliases))
+	for domain, alias := range aliases {
+		normalized[normalizeAliasDomain(domain)] = dns01.ToFqdn(strings.ToLower(alias))
+	}
+
+	return decorate(&aliasProvider{Provider: provider, aliases: normalized})
+}
+
+// withDomainAliases wraps the provider with WithDomainAliases when `domainAliases` is set in the provider config.
+func withDomainAliases(rawConfig []byte, provider challenge.Provider) (challenge.Provider, error) {
+	var config domainAliasesConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("domainAliases: %w", err)
+	}
+
+	if len(config.DomainAliases) == 0 {
+		return provider, nil
+	}
+
+	for domain, alias := range config.DomainAliases {
+		if strings.TrimSpace(alias) == "" {
+			return nil, fmt.Errorf("domainAliases: empty alias for %q", domain)
+		}
+	}
+
+	return WithDomainAliases(provider, config.DomainAliases), nil
+}
+
+func normalizeAliasDomain(domain string) string {
+	return strings.TrimPrefix(strings.ToLower(dns01.UnFqdn(domain)), "*.")
+}
+
+type aliasProvider struct {
+	challenge.Provider
+
+	aliases map[string]string
+}
+
+// Present creates the TXT record at the alias of the domain.
+func (p *aliasProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record at the alias of the domain.
+func (p *aliasProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates the TXT record at the alias of the domain.
+func (p *aliasProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	return PresentContext(ctx, p.Provider, domain, token, p.keyAuth(domain, keyAuth))
+}
+
+// CleanUpContext removes the TXT record at the alias of the domain.
+func (p *aliasProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return CleanUpContext(ctx, p.Provider, domain, token, p.keyAuth(domain, keyAuth))
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *aliasProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *aliasProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
+
+// keyAuth returns the key authorization writing the record of the challenge at the alias of the domain,
+// or the key authorization itself when the domain has no alias.
+func (p *aliasProvider) keyAuth(domain, keyAuth string) string {
+	alias, ok := p.aliases[normalizeAliasDomain(domain)]
+	if !ok {
+		return keyAuth
+	}
+
+	return rawrecord.KeyAuth(alias, rawrecord.GetChallengeInfo(domain, keyAuth).Value)
+}
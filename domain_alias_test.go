@@ -0,0 +1,79 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/rawrecord"
+)
+
+// recordingProvider records the TXT records of the challenges.
+type recordingProvider struct {
+	presented []string
+	cleaned   []string
+}
+
+func (p *recordingProvider) Present(domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	p.presented = append(p.presented, info.EffectiveFQDN+" "+info.Value)
+
+	return nil
+}
+
+func (p *recordingProvider) CleanUp(domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	p.cleaned = append(p.cleaned, info.EffectiveFQDN+" "+info.Value)
+
+	return nil
+}
+
+func TestWithDomainAliases(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	inner := &recordingProvider{}
+
+	provider := WithDomainAliases(inner, map[string]string{
+		"Example.com":   "_acme-challenge.validation.example.net",
+		"*.example.org": "_acme-challenge.validation.example.net.",
+	})
+
+	require.NoError(t, provider.Present("example.com", "token", rawrecord.KeyAuth("", "a")))
+	require.NoError(t, provider.Present("example.org", "token", rawrecord.KeyAuth("", "b")))
+	require.NoError(t, provider.Present("example.io", "token", rawrecord.KeyAuth("", "c")))
+	require.NoError(t, provider.CleanUp("example.com", "token", rawrecord.KeyAuth("", "a")))
+
+	assert.Equal(t, []string{
+		"_acme-challenge.validation.example.net. a",
+		"_acme-challenge.validation.example.net. b",
+		"_acme-challenge.example.io. c",
+	}, inner.presented)
+	assert.Equal(t, []string{"_acme-challenge.validation.example.net. a"}, inner.cleaned)
+
+	assert.Same(t, inner, Unwrap(provider))
+}
+
+func TestWithDomainAliases_config(t *testing.T) {
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	provider, err := withDomainAliases([]byte("propagationTimeout: 10s\n"), inner)
+	require.NoError(t, err)
+	assert.Same(t, inner, provider)
+
+	provider, err = withDomainAliases([]byte("domainAliases:\n  example.com: _acme-challenge.validation.example.net\n"), inner)
+	require.NoError(t, err)
+	assert.Same(t, inner, Unwrap(provider))
+
+	_, err = withDomainAliases([]byte("domainAliases:\n  example.com: \"\"\n"), inner)
+	require.EqualError(t, err, `domainAliases: empty alias for "example.com"`)
+
+	_, err = withDomainAliases([]byte("domainAliases: true\n"), inner)
+	require.Error(t, err)
+}
+
+func TestParseConfigStrict_domainAliases(t *testing.T) {
+	_, err := ParseConfigStrict("fake", []byte("domainAliases:\n  example.com: _acme-challenge.validation.example.net\n"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,124 @@
+package legotoolbox
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"lego-toolbox/yamlconfig"
+)
+
+// minHTTPTimeout is the shortest HTTP timeout accepted in the provider configs.
+const minHTTPTimeout = time.Second
+
+// integerSeconds is the bound of the durations read as a number of seconds:
+// `HTTPTimeout: 30` in a Go config is 30ns, while 30s was meant.
+const integerSeconds = time.Microsecond
+
+// durationIssue is a suspicious duration of a provider config.
+type durationIssue struct {
+	// Key is the YAML key of the field, e.g. `httpTimeout`.
+	Key   string
+	Value time.Duration
+	// Normalized is the value read as seconds, zero when the value is kept.
+	Normalized time.Duration
+}
+
+func (i durationIssue) String() string {
+	if i.Normalized != 0 {
+		return fmt.Sprintf("%s: %s is a number of seconds, read as %s", i.Key, i.Value, i.Normalized)
+	}
+
+	return fmt.Sprintf("%s: the HTTP timeout %s is shorter than %s", i.Key, i.Value, minHTTPTimeout)
+}
+
+// normalizeDurations checks the timeouts and intervals of the provider config (a pointer to a struct):
+// the values shorter than 1µs are integer seconds assigned to a time.Duration (e.g. `HTTPTimeout: 30` in DefaultConfig),
+// and are normalized as seconds;
+// the HTTP timeouts shorter than 1s are reported, and kept.
+func normalizeDurations(cfg any) []durationIssue {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return normalizeStructDurations(v.Elem(), "")
+}
+
+func normalizeStructDurations(v reflect.Value, prefix string) []durationIssue {
+	var issues []durationIssue
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+
+		name, inline, skip := yamlconfig.FieldName(field)
+
+		switch {
+		case field.Name == "HTTPClient" && field.Type == reflect.TypeOf(&http.Client{}):
+			client, _ := value.Interface().(*http.Client)
+			if client == nil {
+				continue
+			}
+
+			if issue, ok := checkDuration(&client.Timeout, prefix+"httpClient.timeout", true); ok {
+				issues = append(issues, issue)
+			}
+
+		case skip || !value.CanSet():
+			continue
+
+		case field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}):
+			if !inline {
+				name = prefix + name + "."
+			} else {
+				name = prefix
+			}
+
+			issues = append(issues, normalizeStructDurations(value, name)...)
+
+		case field.Type == reflect.TypeOf(time.Duration(0)) && isTimingField(field.Name):
+			d := time.Duration(value.Int())
+
+			if issue, ok := checkDuration(&d, prefix+name, strings.Contains(field.Name, "HTTP")); ok {
+				value.SetInt(int64(d))
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	return issues
+}
+
+// isTimingField reports whether the field is a timeout or an interval (not a latency, a TTL, ...).
+func isTimingField(name string) bool {
+	return strings.HasSuffix(name, "Timeout") || strings.HasSuffix(name, "Interval")
+}
+
+// checkDuration normalizes d when it is an integer number of seconds,
+// and reports the HTTP timeouts shorter than minHTTPTimeout.
+func checkDuration(d *time.Duration, key string, httpTimeout bool) (durationIssue, bool) {
+	switch {
+	case *d > 0 && *d < integerSeconds:
+		issue := durationIssue{Key: key, Value: *d, Normalized: *d * time.Second}
+		*d = issue.Normalized
+
+		return issue, true
+
+	case httpTimeout && *d > 0 && *d < minHTTPTimeout:
+		return durationIssue{Key: key, Value: *d}, true
+
+	default:
+		return durationIssue{}, false
+	}
+}
+
+// withNormalizedDurations normalizes the durations of the provider config, and logs the issues.
+func withNormalizedDurations(name string, cfg any) {
+	for _, issue := range normalizeDurations(cfg) {
+		log.Warnf("%s: %s", name, issue)
+	}
+}
@@ -0,0 +1,71 @@
+package legotoolbox
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/registry"
+)
+
+func TestNormalizeDurations(t *testing.T) {
+	type nested struct {
+		PollingInterval time.Duration `yaml:"pollingInterval"`
+	}
+
+	cfg := &struct {
+		PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+		HTTPTimeout        time.Duration `yaml:"httpTimeout"`
+		PresentLatency     time.Duration `yaml:"presentLatency"`
+		Nested             nested        `yaml:"nested"`
+		HTTPClient         *http.Client  `yaml:"-"`
+	}{
+		PropagationTimeout: 60,
+		HTTPTimeout:        500 * time.Millisecond,
+		PresentLatency:     10,
+		Nested:             nested{PollingInterval: 2 * time.Second},
+		HTTPClient:         &http.Client{Timeout: 30},
+	}
+
+	issues := normalizeDurations(cfg)
+
+	assert.Equal(t, []durationIssue{
+		{Key: "propagationTimeout", Value: 60, Normalized: 60 * time.Second},
+		{Key: "httpTimeout", Value: 500 * time.Millisecond},
+		{Key: "httpClient.timeout", Value: 30, Normalized: 30 * time.Second},
+	}, issues)
+
+	assert.Equal(t, 60*time.Second, cfg.PropagationTimeout)
+	assert.Equal(t, 500*time.Millisecond, cfg.HTTPTimeout)
+	assert.Equal(t, time.Duration(10), cfg.PresentLatency)
+	assert.Equal(t, 2*time.Second, cfg.Nested.PollingInterval)
+	assert.Equal(t, 30*time.Second, cfg.HTTPClient.Timeout)
+
+	assert.Equal(t, "propagationTimeout: 60ns is a number of seconds, read as 1m0s", issues[0].String())
+	assert.Equal(t, "httpTimeout: the HTTP timeout 500ms is shorter than 1s", issues[1].String())
+}
+
+// The default configs must not rely on the normalization.
+func TestNormalizeDurations_defaultConfigs(t *testing.T) {
+	for _, name := range registry.Names() {
+		p, ok := registry.Get(name)
+		require.True(t, ok)
+
+		if !p.YAMLConfig() {
+			continue
+		}
+
+		assert.Empty(t, normalizeDurations(p.DefaultConfig()), name)
+	}
+}
+
+func TestParseConfigStrict_httpTimeout(t *testing.T) {
+	_, err := ParseConfigStrict("linode", []byte("token: secret\nhttpTimeout: 200ms\n"))
+	require.EqualError(t, err, "linode: httpTimeout: the HTTP timeout 200ms is shorter than 1s")
+
+	cfg, err := ParseConfigStrict("linode", []byte("token: secret\nhttpTimeout: 30\n"))
+	require.NoError(t, err)
+	assert.Empty(t, normalizeDurations(cfg))
+}
@@ -0,0 +1,141 @@
+// Package eab loads and validates the External Account Binding credentials (RFC 8555 section 7.3.4)
+// required by some ACME CAs (ZeroSSL, Buypass, Google Trust Services, step-ca, ...) to register an account.
+//
+// The credentials come from the YAML config (see Parse), the environment (see FromEnv)
+// or a secret store (see FromStore).
+package eab
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/registration"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables holding the credentials, the same as the lego CLI.
+// The credentials can also be read from a file, by suffixing the variable with `_FILE`.
+const (
+	EnvKID  = "LEGO_EAB_KID"
+	EnvHMAC = "LEGO_EAB_HMAC"
+)
+
+// MinHMACKeySize is the minimum size of the decoded HMAC key, in bytes.
+const MinHMACKeySize = 16
+
+// Credentials is an external account binding key pair, provided by the CA.
+type Credentials struct {
+	// KID is the key identifier.
+	KID string `json:"kid" yaml:"kid"`
+	// HMACKey is the base64url encoded HMAC key.
+	HMACKey string `json:"hmacKey" yaml:"hmacKey"`
+}
+
+// Parse parses the YAML credentials (`kid` and `hmacKey`), and validates them.
+func Parse(raw []byte) (*Credentials, error) {
+	creds := &Credentials{}
+
+	err := yamlconfig.Unmarshal(raw, creds)
+	if err != nil {
+		return nil, fmt.Errorf("eab: %w", err)
+	}
+
+	return creds.normalized()
+}
+
+// FromEnv reads the credentials from the environment variables (EnvKID, EnvHMAC), and validates them.
+func FromEnv() (*Credentials, error) {
+	values, err := env.Get(EnvKID, EnvHMAC)
+	if err != nil {
+		return nil, fmt.Errorf("eab: %w", err)
+	}
+
+	creds := &Credentials{KID: values[EnvKID], HMACKey: values[EnvHMAC]}
+
+	return creds.normalized()
+}
+
+// SecretStore reads secrets from a secret manager (Vault, AWS Secrets Manager, Kubernetes Secrets, ...).
+type SecretStore interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+// FromStore reads the credentials from the secrets kidName and hmacName of the store, and validates them.
+func FromStore(ctx context.Context, store SecretStore, kidName, hmacName string) (*Credentials, error) {
+	if store == nil {
+		return nil, errors.New("eab: secret store is nil")
+	}
+
+	kid, err := store.GetSecret(ctx, kidName)
+	if err != nil {
+		return nil, fmt.Errorf("eab: secret %q: %w", kidName, err)
+	}
+
+	hmacKey, err := store.GetSecret(ctx, hmacName)
+	if err != nil {
+		return nil, fmt.Errorf("eab: secret %q: %w", hmacName, err)
+	}
+
+	creds := &Credentials{KID: kid, HMACKey: hmacKey}
+
+	return creds.normalized()
+}
+
+// Validate checks the key identifier is set, and the HMAC key is a base64url encoded key of MinHMACKeySize bytes or more.
+// The padding of the HMAC key is accepted.
+func (c *Credentials) Validate() error {
+	_, err := c.normalized()
+
+	return err
+}
+
+// RegisterOptions returns the lego registration options binding the account to the credentials.
+func (c *Credentials) RegisterOptions() registration.RegisterEABOptions {
+	return registration.RegisterEABOptions{
+		TermsOfServiceAgreed: true,
+		Kid:                  strings.TrimSpace(c.KID),
+		HmacEncoded:          normalizeHMACKey(c.HMACKey),
+	}
+}
+
+// normalized returns the credentials trimmed, the HMAC key without padding (as expected by lego), or the validation error.
+func (c *Credentials) normalized() (*Credentials, error) {
+	if c == nil {
+		return nil, errors.New("eab: credentials are nil")
+	}
+
+	creds := &Credentials{KID: strings.TrimSpace(c.KID), HMACKey: normalizeHMACKey(c.HMACKey)}
+
+	if creds.KID == "" {
+		return nil, errors.New("eab: missing key identifier (kid)")
+	}
+
+	if creds.HMACKey == "" {
+		return nil, errors.New("eab: missing HMAC key (hmacKey)")
+	}
+
+	if strings.ContainsAny(creds.HMACKey, "+/") {
+		return nil, errors.New("eab: the HMAC key must be base64url encoded ('-' and '_' instead of '+' and '/')")
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(creds.HMACKey)
+	if err != nil {
+		return nil, fmt.Errorf("eab: invalid HMAC key: %w", err)
+	}
+
+	defer clear(key)
+
+	if len(key) < MinHMACKeySize {
+		return nil, fmt.Errorf("eab: HMAC key too short: %d bytes, %d bytes or more expected", len(key), MinHMACKeySize)
+	}
+
+	return creds, nil
+}
+
+func normalizeHMACKey(key string) string {
+	return strings.TrimRight(strings.TrimSpace(key), "=")
+}
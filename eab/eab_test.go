@@ -0,0 +1,132 @@
+package eab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHMACKey is "a-thirty-two-bytes-long-hmac-key" base64url encoded.
+const testHMACKey = "YS10aGlydHktdHdvLWJ5dGVzLWxvbmctaG1hYy1rZXk"
+
+var envTest = tester.NewEnvTest(EnvKID, EnvHMAC)
+
+func TestParse(t *testing.T) {
+	creds, err := Parse([]byte("kid: \" kid \"\nhmacKey: " + testHMACKey + "=\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, &Credentials{KID: "kid", HMACKey: testHMACKey}, creds)
+}
+
+func TestCredentials_Validate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		creds    *Credentials
+		expected string
+	}{
+		{
+			desc:  "valid",
+			creds: &Credentials{KID: "kid", HMACKey: testHMACKey},
+		},
+		{
+			desc:  "padding",
+			creds: &Credentials{KID: "kid", HMACKey: testHMACKey + "="},
+		},
+		{
+			desc:     "nil",
+			expected: "eab: credentials are nil",
+		},
+		{
+			desc:     "missing key identifier",
+			creds:    &Credentials{HMACKey: testHMACKey},
+			expected: "eab: missing key identifier (kid)",
+		},
+		{
+			desc:     "missing HMAC key",
+			creds:    &Credentials{KID: "kid"},
+			expected: "eab: missing HMAC key (hmacKey)",
+		},
+		{
+			desc:     "standard base64",
+			creds:    &Credentials{KID: "kid", HMACKey: "a+b/" + testHMACKey},
+			expected: "eab: the HMAC key must be base64url encoded ('-' and '_' instead of '+' and '/')",
+		},
+		{
+			desc:     "invalid base64url",
+			creds:    &Credentials{KID: "kid", HMACKey: testHMACKey + "!"},
+			expected: "eab: invalid HMAC key: illegal base64 data at input byte 43",
+		},
+		{
+			desc:     "short key",
+			creds:    &Credentials{KID: "kid", HMACKey: "c2hvcnQta2V5"},
+			expected: "eab: HMAC key too short: 9 bytes, 16 bytes or more expected",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.creds.Validate()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestCredentials_RegisterOptions(t *testing.T) {
+	creds := &Credentials{KID: "kid", HMACKey: testHMACKey + "="}
+
+	opts := creds.RegisterOptions()
+
+	assert.True(t, opts.TermsOfServiceAgreed)
+	assert.Equal(t, "kid", opts.Kid)
+	assert.Equal(t, testHMACKey, opts.HmacEncoded)
+}
+
+func TestFromEnv(t *testing.T) {
+	defer envTest.RestoreEnv()
+	envTest.ClearEnv()
+
+	_, err := FromEnv()
+	require.EqualError(t, err, "eab: some credentials information are missing: LEGO_EAB_KID,LEGO_EAB_HMAC")
+
+	envTest.Apply(map[string]string{EnvKID: "kid", EnvHMAC: testHMACKey})
+
+	creds, err := FromEnv()
+	require.NoError(t, err)
+
+	assert.Equal(t, &Credentials{KID: "kid", HMACKey: testHMACKey}, creds)
+}
+
+type mapStore map[string]string
+
+func (m mapStore) GetSecret(_ context.Context, name string) (string, error) {
+	secret, ok := m[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+
+	return secret, nil
+}
+
+func TestFromStore(t *testing.T) {
+	store := mapStore{"zerossl/kid": "kid", "zerossl/hmac": testHMACKey}
+
+	creds, err := FromStore(context.Background(), store, "zerossl/kid", "zerossl/hmac")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Credentials{KID: "kid", HMACKey: testHMACKey}, creds)
+
+	_, err = FromStore(context.Background(), store, "zerossl/kid", "buypass/hmac")
+	require.EqualError(t, err, `eab: secret "buypass/hmac": not found`)
+
+	_, err = FromStore(context.Background(), nil, "zerossl/kid", "zerossl/hmac")
+	require.EqualError(t, err, "eab: secret store is nil")
+}
@@ -0,0 +1,30 @@
+package legotoolbox
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/configcrypto"
+)
+
+// NewDNSChallengeProviderByNameEncrypted Factory for DNS providers, like NewDNSChallengeProviderByName,
+// with a YAML config encrypted with age or AES-GCM (see configcrypto).
+// A nil decrypter uses the keys from the environment (configcrypto.FromEnv).
+// Plaintext configs are rejected.
+func NewDNSChallengeProviderByNameEncrypted(name string, encryptedConfig []byte, decrypter configcrypto.Decrypter) (challenge.Provider, error) {
+	if decrypter == nil {
+		chain, err := configcrypto.FromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		decrypter = chain
+	}
+
+	rawConfig, err := configcrypto.Decrypt(encryptedConfig, decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	defer clear(rawConfig)
+
+	return NewDNSChallengeProviderByName(name, rawConfig)
+}
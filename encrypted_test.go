@@ -0,0 +1,25 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/configcrypto"
+)
+
+func TestNewDNSChallengeProviderByNameEncrypted(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	aesGCM, err := configcrypto.NewAESGCM(key)
+	require.NoError(t, err)
+
+	blob, err := aesGCM.Encrypt([]byte("propagationTimeout: 1m\n"))
+	require.NoError(t, err)
+
+	provider, err := NewDNSChallengeProviderByNameEncrypted("fake", blob, aesGCM)
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+
+	_, err = NewDNSChallengeProviderByNameEncrypted("fake", []byte("propagationTimeout: 1m\n"), aesGCM)
+	require.ErrorIs(t, err, configcrypto.ErrNotEncrypted)
+}
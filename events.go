@@ -0,0 +1,97 @@
+package legotoolbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/events"
+)
+
+var (
+	eventBusMu sync.RWMutex
+	eventBus   *events.Bus
+)
+
+// SetEventBus sets the bus receiving the lifecycle events of the providers created by NewDNSChallengeProviderByName.
+// A nil bus disables the events.
+func SetEventBus(bus *events.Bus) {
+	eventBusMu.Lock()
+	defer eventBusMu.Unlock()
+
+	eventBus = bus
+}
+
+// EventBus returns the bus set with SetEventBus.
+func EventBus() *events.Bus {
+	eventBusMu.RLock()
+	defer eventBusMu.RUnlock()
+
+	return eventBus
+}
+
+// WithEvents publishes the lifecycle of the Present and CleanUp calls of the provider to the bus.
+// The name identifies the provider in the events.
+// The timing and sequential behavior of the provider are preserved.
+func WithEvents(provider challenge.Provider, name string, bus *events.Bus) challenge.Provider {
+	return decorate(&eventProvider{Provider: provider, name: name, bus: bus})
+}
+
+type eventProvider struct {
+	challenge.Provider
+
+	name string
+	bus  *events.Bus
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *eventProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *eventProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *eventProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	return p.observe(events.PresentStart, events.PresentSuccess, events.PresentFailure, domain, token, func() error {
+		return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+	})
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *eventProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return p.observe(events.CleanUpStart, events.CleanUpSuccess, events.CleanUpFailure, domain, token, func() error {
+		return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+	})
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *eventProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *eventProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
+
+func (p *eventProvider) observe(start, success, failure events.Type, domain, token string, call func() error) error {
+	p.bus.Publish(events.Event{Type: start, Provider: p.name, Domain: domain, Token: token})
+
+	begin := time.Now()
+	err := call()
+
+	event := events.Event{Type: success, Provider: p.name, Domain: domain, Token: token, Duration: time.Since(begin)}
+	if err != nil {
+		event.Type = failure
+		event.Error = err.Error()
+	}
+
+	p.bus.Publish(event)
+
+	return err
+}
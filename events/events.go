@@ -0,0 +1,153 @@
+// Package events publishes the lifecycle of DNS-01 challenges (present and cleanup),
+// and of certificate renewals, to pluggable sinks.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type is the type of a lifecycle event.
+type Type string
+
+// Event types.
+const (
+	PresentStart   Type = "present.start"
+	PresentSuccess Type = "present.success"
+	PresentFailure Type = "present.failure"
+	CleanUpStart   Type = "cleanup.start"
+	CleanUpSuccess Type = "cleanup.success"
+	CleanUpFailure Type = "cleanup.failure"
+
+	// The renewal events are published by the renewal package, the Domain is the first domain of the certificate.
+	RenewalDue     Type = "renewal.due"
+	RenewalSuccess Type = "renewal.success"
+	RenewalFailure Type = "renewal.failure"
+
+	// ProviderDeprecated is published by the factory when a deprecated provider is migrated to its replacement.
+	ProviderDeprecated Type = "provider.deprecated"
+)
+
+// Event is a challenge or renewal lifecycle event.
+type Event struct {
+	Type Type `json:"type"`
+	// Provider is the provider identifier, as given to the factory.
+	Provider string `json:"provider"`
+	Domain   string `json:"domain"`
+	Token    string `json:"token,omitempty"`
+	// Replacement is the provider used instead of the deprecated one, set on ProviderDeprecated events.
+	Replacement string `json:"replacement,omitempty"`
+	// Error is set on failure events.
+	Error string `json:"error,omitempty"`
+	// Duration is the duration of the call, set on success and failure events.
+	Duration time.Duration `json:"duration,omitempty"`
+	Time     time.Time     `json:"time"`
+}
+
+// IsFailure reports whether the event is a failure event.
+func (e Event) IsFailure() bool {
+	return e.Type == PresentFailure || e.Type == CleanUpFailure || e.Type == RenewalFailure
+}
+
+// Sink receives the published events.
+// Handle is called synchronously by the bus and must not block for long.
+type Sink interface {
+	Handle(event Event)
+}
+
+// SinkFunc is a function implementing Sink.
+type SinkFunc func(event Event)
+
+// Handle calls f(event).
+func (f SinkFunc) Handle(event Event) {
+	f(event)
+}
+
+// Bus dispatches the events to its sinks.
+type Bus struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// NewBus creates a bus publishing to the sinks.
+func NewBus(sinks ...Sink) *Bus {
+	return &Bus{sinks: sinks}
+}
+
+// Subscribe adds a sink.
+func (b *Bus) Subscribe(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sinks = append(b.sinks, sink)
+}
+
+// On adds a sink called only for the events of the given types.
+func (b *Bus) On(fn func(Event), types ...Type) {
+	b.Subscribe(Filter(SinkFunc(fn), types...))
+}
+
+// OnPresentStart calls fn before each Present call.
+func (b *Bus) OnPresentStart(fn func(Event)) { b.On(fn, PresentStart) }
+
+// OnPresentSuccess calls fn after each successful Present call.
+func (b *Bus) OnPresentSuccess(fn func(Event)) { b.On(fn, PresentSuccess) }
+
+// OnPresentFailure calls fn after each failed Present call.
+func (b *Bus) OnPresentFailure(fn func(Event)) { b.On(fn, PresentFailure) }
+
+// OnCleanUpStart calls fn before each CleanUp call.
+func (b *Bus) OnCleanUpStart(fn func(Event)) { b.On(fn, CleanUpStart) }
+
+// OnCleanUpSuccess calls fn after each successful CleanUp call.
+func (b *Bus) OnCleanUpSuccess(fn func(Event)) { b.On(fn, CleanUpSuccess) }
+
+// OnCleanUpFailure calls fn after each failed CleanUp call.
+func (b *Bus) OnCleanUpFailure(fn func(Event)) { b.On(fn, CleanUpFailure) }
+
+// Publish sends the event to every sink.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	sinks := b.sinks
+	b.mu.RUnlock()
+
+	for _, sink := range sinks {
+		sink.Handle(event)
+	}
+}
+
+// Filter returns a sink forwarding only the events of the given types.
+// All the events are forwarded when no type is given.
+func Filter(sink Sink, types ...Type) Sink {
+	if len(types) == 0 {
+		return sink
+	}
+
+	return SinkFunc(func(event Event) {
+		for _, t := range types {
+			if event.Type == t {
+				sink.Handle(event)
+				return
+			}
+		}
+	})
+}
+
+// NewChannelSink returns a sink sending the events to the channel.
+// Events are dropped when the channel is full, so a slow reader never blocks a challenge.
+func NewChannelSink(ch chan<- Event) Sink {
+	return SinkFunc(func(event Event) {
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_On(t *testing.T) {
+	bus := NewBus()
+
+	var presents, failures int
+	bus.OnPresentSuccess(func(Event) { presents++ })
+	bus.OnPresentFailure(func(Event) { failures++ })
+	bus.OnCleanUpFailure(func(Event) { failures++ })
+
+	bus.Publish(Event{Type: PresentStart})
+	bus.Publish(Event{Type: PresentSuccess})
+	bus.Publish(Event{Type: PresentFailure})
+	bus.Publish(Event{Type: CleanUpFailure})
+	bus.Publish(Event{Type: CleanUpSuccess})
+
+	assert.Equal(t, 1, presents)
+	assert.Equal(t, 2, failures)
+}
+
+func TestBus_Publish_nil(t *testing.T) {
+	var bus *Bus
+
+	assert.NotPanics(t, func() { bus.Publish(Event{Type: PresentStart}) })
+}
+
+func TestNewChannelSink(t *testing.T) {
+	ch := make(chan Event, 1)
+
+	bus := NewBus(NewChannelSink(ch))
+	bus.Publish(Event{Type: PresentStart, Domain: "example.com"})
+	bus.Publish(Event{Type: PresentSuccess, Domain: "example.com"})
+
+	require.Len(t, ch, 1)
+
+	event := <-ch
+	assert.Equal(t, PresentStart, event.Type)
+	assert.False(t, event.Time.IsZero())
+}
+
+func TestWebhookSink_Send(t *testing.T) {
+	var received Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	sink := NewWebhookSink(server.URL)
+	sink.Headers["Authorization"] = "Bearer secret"
+
+	err := sink.Send(context.Background(), Event{Type: CleanUpFailure, Provider: "fake", Domain: "example.com", Error: "boom"})
+	require.NoError(t, err)
+
+	assert.Equal(t, CleanUpFailure, received.Type)
+	assert.Equal(t, "fake", received.Provider)
+	assert.Equal(t, "boom", received.Error)
+}
+
+func TestWebhookSink_Send_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, "nope", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	err := NewWebhookSink(server.URL).Send(context.Background(), Event{Type: PresentStart})
+	require.EqualError(t, err, "unexpected status code: [status code: 500] body: nope\n")
+}
+
+func TestWebhookSink_Handle(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Type
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		var event Event
+		_ = json.NewDecoder(req.Body).Decode(&event)
+
+		mu.Lock()
+		received = append(received, event.Type)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	sink := NewWebhookSink(server.URL)
+
+	bus := NewBus(sink)
+	bus.Publish(Event{Type: PresentStart})
+	bus.Publish(Event{Type: PresentSuccess})
+
+	require.NoError(t, sink.Close(context.Background()))
+
+	assert.Equal(t, []Type{PresentStart, PresentSuccess}, received)
+
+	// the events published after Close are dropped.
+	bus.Publish(Event{Type: CleanUpStart})
+	assert.Len(t, received, 2)
+}
+
+func TestWebhookSink_Handle_queueFull(t *testing.T) {
+	var received atomic.Int32
+
+	arrived := make(chan struct{}, 3)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		received.Add(1)
+		arrived <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(server.Close)
+
+	sink := NewWebhookSink(server.URL)
+	sink.QueueSize = 1
+
+	sink.Handle(Event{Type: PresentStart})
+	<-arrived
+
+	// the first event is being posted: the second one is queued, the third one is dropped, without blocking.
+	sink.Handle(Event{Type: PresentSuccess})
+	sink.Handle(Event{Type: CleanUpStart})
+
+	close(release)
+
+	require.NoError(t, sink.Close(context.Background()))
+
+	assert.Equal(t, int32(2), received.Load())
+}
+
+func TestWebhookSink_Close_timeout(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	sink := NewWebhookSink(server.URL)
+	sink.Handle(Event{Type: PresentStart})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, sink.Close(ctx), context.DeadlineExceeded)
+}
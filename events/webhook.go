@@ -0,0 +1,148 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+const (
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultWebhookQueueSize = 100
+)
+
+// WebhookSink posts the events as JSON to an HTTP endpoint.
+// The events are queued by Handle and posted by a worker goroutine, Close posts the queued events.
+type WebhookSink struct {
+	// URL is the endpoint receiving the events.
+	URL string
+	// Headers are added to every request (e.g. Authorization).
+	Headers map[string]string
+	// HTTPClient is used to send the requests.
+	HTTPClient *http.Client
+	// QueueSize is the number of events waiting to be posted,
+	// the events are dropped when the queue is full (default: 100).
+	QueueSize int
+
+	start  sync.Once
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Event
+	done   chan struct{}
+}
+
+// NewWebhookSink creates a webhook sink posting to the URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:        url,
+		Headers:    map[string]string{},
+		HTTPClient: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+}
+
+// Handle queues the event, it never blocks:
+// the event is dropped when the queue is full or the sink is closed.
+// Failures are logged and never reach the challenge.
+func (w *WebhookSink) Handle(event Event) {
+	w.start.Do(w.run)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.queue <- event:
+	default:
+		log.Warnf("events: webhook: queue full, %s event of %s dropped", event.Type, event.Domain)
+	}
+}
+
+// Close stops accepting the events, and waits for the queued events to be posted,
+// or until the context is done.
+func (w *WebhookSink) Close(ctx context.Context) error {
+	w.start.Do(w.run)
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run starts the worker posting the queued events.
+func (w *WebhookSink) run() {
+	size := w.QueueSize
+	if size <= 0 {
+		size = defaultWebhookQueueSize
+	}
+
+	w.queue = make(chan Event, size)
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		for event := range w.queue {
+			err := w.Send(context.Background(), event)
+			if err != nil {
+				log.Warnf("events: webhook: %v", err)
+			}
+		}
+	}()
+}
+
+// Send posts the event.
+func (w *WebhookSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to create request JSON body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := w.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to communicate with the webhook: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	return nil
+}
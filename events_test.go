@@ -0,0 +1,69 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/events"
+)
+
+func TestWithEvents(t *testing.T) {
+	inner, err := NewDNSChallengeProviderByName("fake", []byte("failCleanUpOn: [1]\nsequenceInterval: 5s\n"))
+	require.NoError(t, err)
+
+	var received []events.Event
+	bus := events.NewBus(events.SinkFunc(func(event events.Event) {
+		received = append(received, event)
+	}))
+
+	provider := WithEvents(inner, "fake@test", bus)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.Error(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	require.Len(t, received, 4)
+
+	expected := []events.Type{events.PresentStart, events.PresentSuccess, events.CleanUpStart, events.CleanUpFailure}
+	for i, event := range received {
+		assert.Equal(t, expected[i], event.Type)
+		assert.Equal(t, "fake@test", event.Provider)
+		assert.Equal(t, "example.com", event.Domain)
+		assert.Equal(t, "token", event.Token)
+		assert.False(t, event.Time.IsZero())
+	}
+
+	assert.NotEmpty(t, received[3].Error)
+
+	s, ok := provider.(sequential)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, s.Sequential())
+
+	_, ok = provider.(challenge.ProviderTimeout)
+	assert.True(t, ok)
+
+	assert.Same(t, inner, Unwrap(provider))
+}
+
+func TestNewDNSChallengeProviderByName_eventBus(t *testing.T) {
+	ch := make(chan events.Event, 10)
+
+	SetEventBus(events.NewBus(events.NewChannelSink(ch)))
+	t.Cleanup(func() { SetEventBus(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("fake@events", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+
+	require.Len(t, ch, 2)
+
+	event := <-ch
+	assert.Equal(t, events.PresentStart, event.Type)
+	assert.Equal(t, "fake@events", event.Provider)
+
+	event = <-ch
+	assert.Equal(t, events.PresentSuccess, event.Type)
+}
@@ -0,0 +1,138 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-acme/lego/v4/log"
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/events"
+)
+
+// gandiLegacyKeyLength is the length of the API keys of the legacy Gandi XML-RPC API,
+// the personal access tokens of gandiv5 are longer.
+const gandiLegacyKeyLength = 24
+
+// gandiXMLRPCHost is the host of the legacy Gandi XML-RPC API.
+const gandiXMLRPCHost = "rpc.gandi.net"
+
+// migrateGandi returns the provider name and the config to use:
+// gandiv5 and the converted config (see MigrateGandiConfig) when the legacy gandi provider is given a personal access token,
+// the name and the config unchanged otherwise.
+// The migration is logged and published to the event bus, if any, as a ProviderDeprecated event.
+func migrateGandi(id, providerName string, rawConfig []byte) (string, []byte) {
+	if providerName != "gandi" || !hasGandiPAT(rawConfig) {
+		return providerName, rawConfig
+	}
+
+	converted, err := MigrateGandiConfig(rawConfig)
+	if err != nil {
+		// reported by the gandi provider.
+		return providerName, rawConfig
+	}
+
+	log.Warnf("gandi: the XML-RPC API is shut down, the config has a personal access token: the gandiv5 provider is used (see MigrateGandiConfig)")
+
+	EventBus().Publish(events.Event{Type: events.ProviderDeprecated, Provider: id, Replacement: "gandiv5"})
+
+	return "gandiv5", converted
+}
+
+// hasGandiPAT reports whether the YAML config has a personal access token,
+// as personalAccessToken or as an apiKey longer than the legacy API keys.
+func hasGandiPAT(rawConfig []byte) bool {
+	var config struct {
+		APIKey              string `yaml:"apiKey"`
+		PersonalAccessToken string `yaml:"personalAccessToken"`
+	}
+
+	// an invalid config is reported by the provider.
+	if err := yaml.Unmarshal(rawConfig, &config); err != nil {
+		return false
+	}
+
+	return config.PersonalAccessToken != "" || looksLikeGandiPAT(config.APIKey)
+}
+
+func looksLikeGandiPAT(key string) bool {
+	return len(strings.TrimSpace(key)) > gandiLegacyKeyLength
+}
+
+// MigrateGandiConfig converts a YAML config of the deprecated gandi provider (XML-RPC API) to a gandiv5 config.
+// The order of the keys and the comments are preserved, the other keys (e.g. the common keys) are kept unchanged.
+// The apiKey holding a personal access token is renamed personalAccessToken,
+// the baseURL of the XML-RPC API is dropped: gandiv5 uses the LiveDNS API.
+// A legacy API key is rejected: it is not valid for the LiveDNS API, a personal access token must be created.
+func MigrateGandiConfig(old []byte) ([]byte, error) {
+	var doc yaml.Node
+
+	err := yaml.Unmarshal(old, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("gandi: %w", err)
+	}
+
+	if doc.Kind == 0 {
+		return []byte{}, nil
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("gandi: the config must be a mapping")
+	}
+
+	root := doc.Content[0]
+
+	hasPAT := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "personalAccessToken" && root.Content[i+1].Value != "" {
+			hasPAT = true
+		}
+	}
+
+	var content []*yaml.Node
+
+	// the head comment of a dropped key (e.g. the comment of the document) is moved to the next key.
+	var comment string
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		switch key.Value {
+		case "apiKey":
+			if hasPAT {
+				comment = joinComments(comment, key.HeadComment)
+				continue
+			}
+
+			if !looksLikeGandiPAT(value.Value) {
+				return nil, errors.New("gandi: the API key of the XML-RPC API is not valid for gandiv5, create a personal access token")
+			}
+
+			key.Value = "personalAccessToken"
+
+		case "baseURL":
+			if endpoint, err := url.Parse(value.Value); err == nil && endpoint.Hostname() == gandiXMLRPCHost {
+				comment = joinComments(comment, key.HeadComment)
+				continue
+			}
+		}
+
+		key.HeadComment = joinComments(comment, key.HeadComment)
+		comment = ""
+
+		content = append(content, key, value)
+	}
+
+	root.Content = content
+
+	return yaml.Marshal(&doc)
+}
+
+func joinComments(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+
+	return a + "\n" + b
+}
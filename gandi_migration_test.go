@@ -0,0 +1,146 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/events"
+	"lego-toolbox/providers/dns/gandiv5"
+)
+
+const testGandiPAT = "0123456789abcdef0123456789abcdef01234567"
+
+func TestNewDNSChallengeProviderByName_gandiMigration(t *testing.T) {
+	ch := make(chan events.Event, 10)
+
+	SetEventBus(events.NewBus(events.NewChannelSink(ch)))
+	t.Cleanup(func() { SetEventBus(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("gandi@legacy", []byte("apiKey: "+testGandiPAT+"\n"))
+	require.NoError(t, err)
+
+	p, ok := Unwrap(provider).(*gandiv5.DNSProvider)
+	require.True(t, ok)
+	require.NotNil(t, p)
+
+	event := <-ch
+	assert.Equal(t, events.ProviderDeprecated, event.Type)
+	assert.Equal(t, "gandi@legacy", event.Provider)
+	assert.Equal(t, "gandiv5", event.Replacement)
+}
+
+func Test_migrateGandi(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		name      string
+		rawConfig string
+		expected  string
+	}{
+		{
+			desc:      "personal access token",
+			name:      "gandi",
+			rawConfig: "personalAccessToken: token\n",
+			expected:  "gandiv5",
+		},
+		{
+			desc:      "personal access token as API key",
+			name:      "gandi",
+			rawConfig: "apiKey: " + testGandiPAT + "\n",
+			expected:  "gandiv5",
+		},
+		{
+			desc:      "legacy API key",
+			name:      "gandi",
+			rawConfig: "apiKey: abcdefghijklmnopqrstuvwx\n",
+			expected:  "gandi",
+		},
+		{
+			desc:      "other provider",
+			name:      "gandiv5",
+			rawConfig: "personalAccessToken: token\n",
+			expected:  "gandiv5",
+		},
+		{
+			desc:      "invalid config",
+			name:      "gandi",
+			rawConfig: "apiKey: [",
+			expected:  "gandi",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			name, _ := migrateGandi(test.name, test.name, []byte(test.rawConfig))
+			assert.Equal(t, test.expected, name)
+		})
+	}
+}
+
+func TestMigrateGandiConfig(t *testing.T) {
+	old := []byte(`# legacy config
+baseURL: https://rpc.gandi.net/xmlrpc/
+apiKey: ` + testGandiPAT + ` # PAT
+ttl: 300
+domainAliases:
+  example.com: alias.example.net
+`)
+
+	expected := `# legacy config
+personalAccessToken: ` + testGandiPAT + ` # PAT
+ttl: 300
+domainAliases:
+    example.com: alias.example.net
+`
+
+	converted, err := MigrateGandiConfig(old)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, string(converted))
+
+	config, err := gandiv5.ParseConfig(converted)
+	require.NoError(t, err)
+
+	assert.Equal(t, testGandiPAT, config.PersonalAccessToken)
+	assert.Empty(t, config.APIKey)
+}
+
+func TestMigrateGandiConfig_personalAccessToken(t *testing.T) {
+	converted, err := MigrateGandiConfig([]byte("apiKey: abcdefghijklmnopqrstuvwx\npersonalAccessToken: token\nbaseURL: https://api.example.com\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "personalAccessToken: token\nbaseURL: https://api.example.com\n", string(converted))
+}
+
+func TestMigrateGandiConfig_empty(t *testing.T) {
+	converted, err := MigrateGandiConfig(nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, converted)
+}
+
+func TestMigrateGandiConfig_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		old      string
+		expected string
+	}{
+		{
+			desc:     "legacy API key",
+			old:      "apiKey: abcdefghijklmnopqrstuvwx\n",
+			expected: "gandi: the API key of the XML-RPC API is not valid for gandiv5, create a personal access token",
+		},
+		{
+			desc:     "not a mapping",
+			old:      "- a\n- b\n",
+			expected: "gandi: the config must be a mapping",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := MigrateGandiConfig([]byte(test.old))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package grpcremote
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Client calls the DNSProvider service.
+type Client struct {
+	client DNSProviderClient
+}
+
+// NewClient returns a Client using the connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: NewDNSProviderClient(conn)}
+}
+
+// Present creates the TXT record of the challenge with the named provider of the server.
+func (c *Client) Present(ctx context.Context, req *ChallengeRequest) error {
+	_, err := c.client.Present(ctx, req)
+
+	return err
+}
+
+// CleanUp removes the TXT record of the challenge with the named provider of the server.
+func (c *Client) CleanUp(ctx context.Context, req *ChallengeRequest) error {
+	_, err := c.client.CleanUp(ctx, req)
+
+	return err
+}
+
+// Timeout returns the propagation timeout and polling interval of the named provider of the server.
+func (c *Client) Timeout(ctx context.Context, provider string) (timeout, interval time.Duration, err error) {
+	resp, err := c.client.Timeout(ctx, &TimeoutRequest{Provider: provider})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return time.Duration(resp.TimeoutMs) * time.Millisecond, time.Duration(resp.IntervalMs) * time.Millisecond, nil
+}
@@ -0,0 +1,122 @@
+package grpcremote
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"lego-toolbox/providers/dns/fake"
+)
+
+func setupClient(t *testing.T, providers map[string]challenge.Provider) *Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	NewServer(Providers(providers)).Register(server)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return NewClient(conn)
+}
+
+func TestClient(t *testing.T) {
+	config := fake.DefaultConfig()
+	config.PropagationTimeout = 90 * time.Second
+	config.PollingInterval = 1500 * time.Millisecond
+
+	provider, err := fake.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client := setupClient(t, map[string]challenge.Provider{"prod": provider})
+
+	req := &ChallengeRequest{Provider: "prod", Domain: "example.com", Token: "token", KeyAuth: "keyAuth"}
+
+	err = client.Present(context.Background(), req)
+	require.NoError(t, err)
+
+	calls := provider.CallsOf(fake.CallPresent)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "example.com", calls[0].Domain)
+	assert.Equal(t, "token", calls[0].Token)
+	assert.Equal(t, "keyAuth", calls[0].KeyAuth)
+
+	err = client.CleanUp(context.Background(), req)
+	require.NoError(t, err)
+
+	provider.AssertCallCount(t, fake.CallCleanUp, 1)
+
+	timeout, interval, err := client.Timeout(context.Background(), "prod")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+	assert.Equal(t, 1500*time.Millisecond, interval)
+}
+
+func TestClient_errors(t *testing.T) {
+	config := fake.DefaultConfig()
+	config.FailPresentOn = []int{1}
+
+	provider, err := fake.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client := setupClient(t, map[string]challenge.Provider{"": provider})
+
+	err = client.Present(context.Background(), &ChallengeRequest{Domain: "example.com", KeyAuth: "keyAuth"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unknown, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "fake: present _acme-challenge.example.com.")
+
+	err = client.Present(context.Background(), &ChallengeRequest{Provider: "missing", Domain: "example.com"})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestChallengeRequest_unmarshal(t *testing.T) {
+	req := &ChallengeRequest{Provider: "prod", Domain: "example.com", Token: "token", KeyAuth: "keyAuth"}
+
+	raw, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	// An unknown field (number 9, varint) is skipped.
+	raw = append(raw, 0x48, 0x01)
+
+	got := &ChallengeRequest{}
+	require.NoError(t, proto.Unmarshal(raw, got))
+	assert.Equal(t, "prod", got.GetProvider())
+	assert.Equal(t, "example.com", got.GetDomain())
+	assert.Equal(t, "token", got.GetToken())
+	assert.Equal(t, "keyAuth", got.GetKeyAuth())
+
+	require.Error(t, proto.Unmarshal([]byte{0x0a, 0x05, 'a'}, got))
+}
+
+func TestTimeoutResponse_unmarshal(t *testing.T) {
+	resp := &TimeoutResponse{TimeoutMs: 60000, IntervalMs: 2000}
+
+	raw, err := proto.Marshal(resp)
+	require.NoError(t, err)
+
+	got := &TimeoutResponse{}
+	require.NoError(t, proto.Unmarshal(raw, got))
+	assert.True(t, proto.Equal(resp, got))
+}
@@ -0,0 +1,192 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: remote.proto
+
+// The DNS provider service of lego-toolbox.
+// A server holds the provider credentials, the clients (renewal agents) call it remotely.
+
+package grpcremote
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DNSProvider_Present_FullMethodName = "/legotoolbox.remote.v1.DNSProvider/Present"
+	DNSProvider_CleanUp_FullMethodName = "/legotoolbox.remote.v1.DNSProvider/CleanUp"
+	DNSProvider_Timeout_FullMethodName = "/legotoolbox.remote.v1.DNSProvider/Timeout"
+)
+
+// DNSProviderClient is the client API for DNSProvider service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DNSProviderClient interface {
+	// Present creates the TXT record of the challenge.
+	Present(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*Empty, error)
+	// CleanUp removes the TXT record of the challenge.
+	CleanUp(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Timeout returns the propagation timeout and polling interval of the provider.
+	Timeout(ctx context.Context, in *TimeoutRequest, opts ...grpc.CallOption) (*TimeoutResponse, error)
+}
+
+type dNSProviderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDNSProviderClient(cc grpc.ClientConnInterface) DNSProviderClient {
+	return &dNSProviderClient{cc}
+}
+
+func (c *dNSProviderClient) Present(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DNSProvider_Present_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSProviderClient) CleanUp(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DNSProvider_CleanUp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSProviderClient) Timeout(ctx context.Context, in *TimeoutRequest, opts ...grpc.CallOption) (*TimeoutResponse, error) {
+	out := new(TimeoutResponse)
+	err := c.cc.Invoke(ctx, DNSProvider_Timeout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSProviderServer is the server API for DNSProvider service.
+// All implementations must embed UnimplementedDNSProviderServer
+// for forward compatibility
+type DNSProviderServer interface {
+	// Present creates the TXT record of the challenge.
+	Present(context.Context, *ChallengeRequest) (*Empty, error)
+	// CleanUp removes the TXT record of the challenge.
+	CleanUp(context.Context, *ChallengeRequest) (*Empty, error)
+	// Timeout returns the propagation timeout and polling interval of the provider.
+	Timeout(context.Context, *TimeoutRequest) (*TimeoutResponse, error)
+	mustEmbedUnimplementedDNSProviderServer()
+}
+
+// UnimplementedDNSProviderServer must be embedded to have forward compatible implementations.
+type UnimplementedDNSProviderServer struct {
+}
+
+func (UnimplementedDNSProviderServer) Present(context.Context, *ChallengeRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Present not implemented")
+}
+func (UnimplementedDNSProviderServer) CleanUp(context.Context, *ChallengeRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanUp not implemented")
+}
+func (UnimplementedDNSProviderServer) Timeout(context.Context, *TimeoutRequest) (*TimeoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Timeout not implemented")
+}
+func (UnimplementedDNSProviderServer) mustEmbedUnimplementedDNSProviderServer() {}
+
+// UnsafeDNSProviderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DNSProviderServer will
+// result in compilation errors.
+type UnsafeDNSProviderServer interface {
+	mustEmbedUnimplementedDNSProviderServer()
+}
+
+func RegisterDNSProviderServer(s grpc.ServiceRegistrar, srv DNSProviderServer) {
+	s.RegisterService(&DNSProvider_ServiceDesc, srv)
+}
+
+func _DNSProvider_Present_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSProviderServer).Present(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSProvider_Present_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSProviderServer).Present(ctx, req.(*ChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSProvider_CleanUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSProviderServer).CleanUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSProvider_CleanUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSProviderServer).CleanUp(ctx, req.(*ChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSProvider_Timeout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSProviderServer).Timeout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSProvider_Timeout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSProviderServer).Timeout(ctx, req.(*TimeoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DNSProvider_ServiceDesc is the grpc.ServiceDesc for DNSProvider service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DNSProvider_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "legotoolbox.remote.v1.DNSProvider",
+	HandlerType: (*DNSProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Present",
+			Handler:    _DNSProvider_Present_Handler,
+		},
+		{
+			MethodName: "CleanUp",
+			Handler:    _DNSProvider_CleanUp_Handler,
+		},
+		{
+			MethodName: "Timeout",
+			Handler:    _DNSProvider_Timeout_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "remote.proto",
+}
@@ -0,0 +1,121 @@
+// Package grpcremote implements a gRPC service exposing DNS providers (see remote.proto).
+// The credentials stay on the host running the server,
+// the renewal agents resolve their challenges through the client.
+//
+// remote.pb.go and remote_grpc.pb.go are generated from remote.proto
+// with protoc, protoc-gen-go v1.33.0 and protoc-gen-go-grpc v1.3.0.
+package grpcremote
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative remote.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName is the full name of the service.
+const ServiceName = "legotoolbox.remote.v1.DNSProvider"
+
+var _ DNSProviderServer = (*Server)(nil)
+
+// Resolver returns the provider registered under the name, the name is empty for the default provider.
+// legotoolbox.GetProviderInstance is a suitable Resolver.
+type Resolver func(name string) (challenge.Provider, error)
+
+// ErrProviderNotFound is returned by a Resolver when no provider is registered under the name.
+// It is reported to the clients with the NotFound code.
+var ErrProviderNotFound = errors.New("provider not found")
+
+// Providers returns a Resolver for a fixed set of providers.
+func Providers(providers map[string]challenge.Provider) Resolver {
+	return func(name string) (challenge.Provider, error) {
+		provider, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrProviderNotFound, name)
+		}
+
+		return provider, nil
+	}
+}
+
+// Server implements the DNSProvider service.
+type Server struct {
+	UnimplementedDNSProviderServer
+
+	resolve Resolver
+}
+
+// NewServer returns a Server resolving the providers with resolve.
+func NewServer(resolve Resolver) *Server {
+	return &Server{resolve: resolve}
+}
+
+// Register registers the service on gs.
+func (s *Server) Register(gs grpc.ServiceRegistrar) {
+	RegisterDNSProviderServer(gs, s)
+}
+
+// Present creates the TXT record of the challenge.
+func (s *Server) Present(_ context.Context, req *ChallengeRequest) (*Empty, error) {
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = provider.Present(req.Domain, req.Token, req.KeyAuth)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+// CleanUp removes the TXT record of the challenge.
+func (s *Server) CleanUp(_ context.Context, req *ChallengeRequest) (*Empty, error) {
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = provider.CleanUp(req.Domain, req.Token, req.KeyAuth)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+// Timeout returns the propagation timeout and polling interval of the provider.
+func (s *Server) Timeout(_ context.Context, req *TimeoutRequest) (*TimeoutResponse, error) {
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, interval := dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+	if p, ok := provider.(challenge.ProviderTimeout); ok {
+		timeout, interval = p.Timeout()
+	}
+
+	return &TimeoutResponse{TimeoutMs: timeout.Milliseconds(), IntervalMs: interval.Milliseconds()}, nil
+}
+
+func (s *Server) provider(name string) (challenge.Provider, error) {
+	provider, err := s.resolve(name)
+	if errors.Is(err, ErrProviderNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return provider, nil
+}
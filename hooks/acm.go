@@ -0,0 +1,196 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"lego-toolbox/yamlconfig"
+)
+
+// AWSACMConfig configures the awsACM hook, importing the certificate into AWS Certificate Manager.
+// The credentials come from the default chain of the AWS SDK (environment, shared config, instance role, ...).
+type AWSACMConfig struct {
+	// Region is the region of ACM, the region of the shared config when empty.
+	// The certificates of CloudFront are imported in us-east-1.
+	Region string `yaml:"region"`
+	// CertificateARN is the ARN of the certificate to re-import, a template of the Certificate.
+	// The certificate is imported as a new ACM certificate when empty,
+	// and re-imported at the same ARN by the following deployments of the same process.
+	CertificateARN string `yaml:"certificateArn"`
+	// Endpoint overrides the endpoint of ACM, e.g. for a VPC endpoint.
+	Endpoint string `yaml:"endpoint"`
+	// Timeout bounds the request, 30s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type acmImportRequest struct {
+	Certificate      []byte `json:"Certificate"`
+	CertificateChain []byte `json:"CertificateChain,omitempty"`
+	PrivateKey       []byte `json:"PrivateKey"`
+	CertificateArn   string `json:"CertificateArn,omitempty"`
+}
+
+type acmImportResponse struct {
+	CertificateArn string `json:"CertificateArn"`
+}
+
+type acmHook struct {
+	credentials aws.CredentialsProvider
+	region      string
+	endpoint    string
+	arn         *template.Template
+	client      *http.Client
+	signer      *v4.Signer
+
+	mu   sync.Mutex
+	arns map[string]string
+}
+
+// NewAWSACMHook returns the hook importing the certificate into ACM with the credentials.
+func NewAWSACMHook(credentials aws.CredentialsProvider, config AWSACMConfig) (Hook, error) {
+	if credentials == nil {
+		return nil, errors.New("the AWS credentials are nil")
+	}
+
+	if config.Region == "" {
+		return nil, errors.New("missing region")
+	}
+
+	arn, err := parseTemplate("certificateArn", config.CertificateARN)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://acm.%s.amazonaws.com/", config.Region)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &acmHook{
+		credentials: credentials,
+		region:      config.Region,
+		endpoint:    endpoint,
+		arn:         arn,
+		client:      &http.Client{Timeout: timeout},
+		signer:      v4.NewSigner(),
+		arns:        map[string]string{},
+	}, nil
+}
+
+// newAWSACMHook creates the hook with the default config of the AWS SDK.
+func newAWSACMHook(rawConfig []byte) (Hook, error) {
+	var config AWSACMConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	var optFns []func(options *awsconfig.LoadOptions) error
+	if config.Region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(config.Region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Region = cfg.Region
+
+	return NewAWSACMHook(cfg.Credentials, config)
+}
+
+// Deploy imports the certificate (ImportCertificate).
+func (h *acmHook) Deploy(ctx context.Context, cert *Certificate) error {
+	arn, err := h.certificateARN(cert)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(acmImportRequest{
+		Certificate:      cert.Certificate,
+		CertificateChain: cert.IssuerCertificate,
+		PrivateKey:       cert.PrivateKey,
+		CertificateArn:   arn,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "CertificateManager.ImportCertificate")
+
+	creds, err := h.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("acm: credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+
+	err = h.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "acm", h.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("acm: sign: %w", err)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("acm: import: unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	var result acmImportResponse
+
+	err = json.Unmarshal(raw, &result)
+	if err != nil {
+		return fmt.Errorf("acm: import: %w", err)
+	}
+
+	h.mu.Lock()
+	h.arns[cert.ID] = result.CertificateArn
+	h.mu.Unlock()
+
+	return nil
+}
+
+// certificateARN returns the ARN of the configured certificate, or of the certificate imported before by the hook.
+func (h *acmHook) certificateARN(cert *Certificate) (string, error) {
+	if h.arn != nil {
+		return execute(h.arn, cert)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.arns[cert.ID], nil
+}
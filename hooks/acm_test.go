@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSACMHook(t *testing.T) {
+	var requests []acmImportRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Amz-Target") != "CertificateManager.ImportCertificate" {
+			http.Error(rw, "unknown target", http.StatusBadRequest)
+			return
+		}
+
+		if !strings.Contains(req.Header.Get("Authorization"), "Credential=AKID/") ||
+			!strings.Contains(req.Header.Get("Authorization"), "/eu-west-1/acm/aws4_request") {
+			http.Error(rw, "invalid signature", http.StatusForbidden)
+			return
+		}
+
+		var body acmImportRequest
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requests = append(requests, body)
+
+		_ = json.NewEncoder(rw).Encode(acmImportResponse{CertificateArn: "arn:aws:acm:eu-west-1:123456789012:certificate/abc"})
+	}))
+	t.Cleanup(server.Close)
+
+	hook, err := NewAWSACMHook(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""), AWSACMConfig{
+		Region:   "eu-west-1",
+		Endpoint: server.URL,
+	})
+	require.NoError(t, err)
+
+	cert := testCertificate()
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, acmImportRequest{
+		Certificate:      cert.Certificate,
+		CertificateChain: cert.IssuerCertificate,
+		PrivateKey:       cert.PrivateKey,
+	}, requests[0])
+
+	// The second deployment re-imports the certificate at the ARN of the first one.
+	assert.Equal(t, "arn:aws:acm:eu-west-1:123456789012:certificate/abc", requests[1].CertificateArn)
+}
+
+func TestAWSACMHook_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, `{"__type":"ValidationException"}`, http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	hook, err := NewAWSACMHook(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""), AWSACMConfig{
+		Region:         "eu-west-1",
+		CertificateARN: "arn:aws:acm:eu-west-1:123456789012:certificate/{{.ID}}",
+		Endpoint:       server.URL,
+	})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.EqualError(t, err, `acm: import: unexpected status code 400: {"__type":"ValidationException"}`)
+}
+
+func TestNewAWSACMHook_errors(t *testing.T) {
+	_, err := NewAWSACMHook(nil, AWSACMConfig{Region: "eu-west-1"})
+	require.EqualError(t, err, "the AWS credentials are nil")
+
+	_, err = NewAWSACMHook(aws.AnonymousCredentials{}, AWSACMConfig{})
+	require.EqualError(t, err, "missing region")
+}
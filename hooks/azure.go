@@ -0,0 +1,155 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"lego-toolbox/yamlconfig"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultScope      = "https://vault.azure.net/.default"
+)
+
+// AzureKeyVaultConfig configures the azureKeyVault hook, importing the certificate into Azure Key Vault.
+// The credentials are the default Azure credentials (environment, workload identity, managed identity, Azure CLI).
+type AzureKeyVaultConfig struct {
+	// VaultURL is the URL of the key vault, e.g. `https://my-vault.vault.azure.net`.
+	VaultURL string `yaml:"vaultUrl"`
+	// CertificateName is the name of the certificate, a template of the Certificate.
+	// The names of Key Vault only allow alphanumerics and dashes, e.g. `{{.ID}}`.
+	CertificateName string `yaml:"certificateName"`
+	// Timeout bounds the request, 30s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type azureImportRequest struct {
+	Value  string            `json:"value"`
+	Policy azureImportPolicy `json:"policy"`
+}
+
+type azureImportPolicy struct {
+	SecretProperties azureSecretProperties `json:"secret_props"`
+}
+
+type azureSecretProperties struct {
+	ContentType string `json:"contentType"`
+}
+
+type azureKeyVaultHook struct {
+	credential azcore.TokenCredential
+	vaultURL   string
+	name       *template.Template
+	client     *http.Client
+}
+
+// NewAzureKeyVaultHook returns the hook importing the certificate into the key vault with the credential.
+func NewAzureKeyVaultHook(credential azcore.TokenCredential, config AzureKeyVaultConfig) (Hook, error) {
+	if credential == nil {
+		return nil, errors.New("the Azure credential is nil")
+	}
+
+	if config.VaultURL == "" {
+		return nil, errors.New("missing vault URL")
+	}
+
+	if config.CertificateName == "" {
+		return nil, errors.New("missing certificate name")
+	}
+
+	name, err := parseTemplate("certificateName", config.CertificateName)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &azureKeyVaultHook{
+		credential: credential,
+		vaultURL:   strings.TrimSuffix(config.VaultURL, "/"),
+		name:       name,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func newAzureKeyVaultHook(rawConfig []byte) (Hook, error) {
+	var config AzureKeyVaultConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAzureKeyVaultHook(credential, config)
+}
+
+// Deploy imports the private key and the full chain as a new version of the certificate.
+func (h *azureKeyVaultHook) Deploy(ctx context.Context, cert *Certificate) error {
+	name, err := execute(h.name, cert)
+	if err != nil {
+		return err
+	}
+
+	var value bytes.Buffer
+	value.Write(cert.PrivateKey)
+	value.Write(cert.FullChain())
+
+	body, err := json.Marshal(azureImportRequest{
+		Value: value.String(),
+		Policy: azureImportPolicy{
+			SecretProperties: azureSecretProperties{ContentType: "application/x-pem-file"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := h.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureKeyVaultScope}})
+	if err != nil {
+		return fmt.Errorf("key vault: token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/certificates/%s/import?api-version=%s", h.vaultURL, name, azureKeyVaultAPIVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return fmt.Errorf("key vault: import %s: unexpected status code %d: %s", name, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	return nil
+}
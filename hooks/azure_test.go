@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokenCredential struct {
+	scopes []string
+}
+
+func (f *fakeTokenCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	f.scopes = options.Scopes
+
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestAzureKeyVaultHook(t *testing.T) {
+	var received azureImportRequest
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("POST /certificates/{name}/import", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if req.PathValue("name") != "example" || req.URL.Query().Get("api-version") != azureKeyVaultAPIVersion {
+			http.NotFound(rw, req)
+			return
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&received)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"id":"https://vault.example.com/certificates/example/1"}`))
+	})
+
+	credential := &fakeTokenCredential{}
+
+	hook, err := NewAzureKeyVaultHook(credential, AzureKeyVaultConfig{
+		VaultURL:        server.URL + "/",
+		CertificateName: "{{.ID}}",
+	})
+	require.NoError(t, err)
+
+	cert := testCertificate()
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{azureKeyVaultScope}, credential.scopes)
+
+	expected := azureImportRequest{
+		Value: string(cert.PrivateKey) + string(cert.FullChain()),
+		Policy: azureImportPolicy{
+			SecretProperties: azureSecretProperties{ContentType: "application/x-pem-file"},
+		},
+	}
+	assert.Equal(t, expected, received)
+}
+
+func TestAzureKeyVaultHook_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, `{"error":{"code":"Forbidden"}}`, http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	hook, err := NewAzureKeyVaultHook(&fakeTokenCredential{}, AzureKeyVaultConfig{
+		VaultURL:        server.URL,
+		CertificateName: "{{.ID}}",
+	})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.EqualError(t, err, `key vault: import example: unexpected status code 403: {"error":{"code":"Forbidden"}}`)
+}
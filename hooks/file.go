@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"lego-toolbox/yamlconfig"
+)
+
+// FileConfig configures the file hook, writing the PEM files.
+// The paths are templates of the Certificate, e.g. `/etc/nginx/certs/{{.ID}}.crt`.
+type FileConfig struct {
+	// CertFile receives the certificate followed by the chain of the issuer.
+	CertFile string `yaml:"certFile"`
+	// ChainFile receives the chain of the issuer.
+	ChainFile string `yaml:"chainFile"`
+	// KeyFile receives the private key.
+	KeyFile string `yaml:"keyFile"`
+	// BundleFile receives the private key, the certificate and the chain of the issuer in one file (e.g. HAProxy).
+	BundleFile string `yaml:"bundleFile"`
+}
+
+type fileHook struct {
+	cert, chain, key, bundle *template.Template
+}
+
+// NewFileHook returns the hook writing the PEM files of the config.
+// The files are replaced atomically, the files holding the private key are only readable by their owner.
+func NewFileHook(config FileConfig) (Hook, error) {
+	if config.CertFile == "" && config.ChainFile == "" && config.KeyFile == "" && config.BundleFile == "" {
+		return nil, errors.New("no file to write")
+	}
+
+	h := &fileHook{}
+
+	for _, t := range []struct {
+		name  string
+		value string
+		dst   **template.Template
+	}{
+		{name: "certFile", value: config.CertFile, dst: &h.cert},
+		{name: "chainFile", value: config.ChainFile, dst: &h.chain},
+		{name: "keyFile", value: config.KeyFile, dst: &h.key},
+		{name: "bundleFile", value: config.BundleFile, dst: &h.bundle},
+	} {
+		tmpl, err := parseTemplate(t.name, t.value)
+		if err != nil {
+			return nil, err
+		}
+
+		*t.dst = tmpl
+	}
+
+	return h, nil
+}
+
+func newFileHook(rawConfig []byte) (Hook, error) {
+	var config FileConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileHook(config)
+}
+
+// Deploy writes the PEM files.
+func (h *fileHook) Deploy(_ context.Context, cert *Certificate) error {
+	files := []struct {
+		tmpl    *template.Template
+		content []byte
+		perm    os.FileMode
+	}{
+		{tmpl: h.cert, content: cert.FullChain(), perm: 0o644},
+		{tmpl: h.chain, content: cert.IssuerCertificate, perm: 0o644},
+		{tmpl: h.key, content: cert.PrivateKey, perm: 0o600},
+		{tmpl: h.bundle, content: append(append([]byte{}, cert.PrivateKey...), cert.FullChain()...), perm: 0o600},
+	}
+
+	for _, f := range files {
+		if f.tmpl == nil {
+			continue
+		}
+
+		path, err := execute(f.tmpl, cert)
+		if err != nil {
+			return err
+		}
+
+		err = writeFileAtomic(path, f.content, f.perm)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes the file through a temporary file renamed over the destination,
+// so the readers never see a partial file.
+func writeFileAtomic(path string, content []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	err = tmp.Chmod(perm)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,50 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHook(t *testing.T) {
+	dir := t.TempDir()
+
+	hook, err := NewFileHook(FileConfig{
+		CertFile:   filepath.Join(dir, "{{.ID}}", "fullchain.pem"),
+		ChainFile:  filepath.Join(dir, "{{.ID}}", "chain.pem"),
+		KeyFile:    filepath.Join(dir, "{{.ID}}", "privkey.pem"),
+		BundleFile: filepath.Join(dir, "{{.Domain}}.pem"),
+	})
+	require.NoError(t, err)
+
+	cert := testCertificate()
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	assertFile(t, filepath.Join(dir, "example", "fullchain.pem"), string(cert.FullChain()), 0o644)
+	assertFile(t, filepath.Join(dir, "example", "chain.pem"), string(cert.IssuerCertificate), 0o644)
+	assertFile(t, filepath.Join(dir, "example", "privkey.pem"), string(cert.PrivateKey), 0o600)
+	assertFile(t, filepath.Join(dir, "example.com.pem"), string(cert.PrivateKey)+string(cert.FullChain()), 0o600)
+
+	// no temporary file left.
+	entries, err := os.ReadDir(filepath.Join(dir, "example"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func assertFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(raw))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, perm, info.Mode().Perm())
+}
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"google.golang.org/api/certificatemanager/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"lego-toolbox/yamlconfig"
+)
+
+// GCPCertificateManagerConfig configures the gcpCertificateManager hook,
+// updating a self-managed certificate of Google Cloud Certificate Manager (created when missing).
+// The credentials are the application default credentials.
+type GCPCertificateManagerConfig struct {
+	// Project is the ID of the Google Cloud project.
+	Project string `yaml:"project"`
+	// Location is the location of the certificate, `global` when empty.
+	Location string `yaml:"location"`
+	// CertificateID is the ID of the certificate, a template of the Certificate, e.g. `{{.ID}}`.
+	CertificateID string `yaml:"certificateId"`
+}
+
+type gcpCertificateManagerHook struct {
+	service       *certificatemanager.Service
+	parent        string
+	certificateID *template.Template
+}
+
+// NewGCPCertificateManagerHook returns the hook updating the certificate with the client options.
+func NewGCPCertificateManagerHook(ctx context.Context, config GCPCertificateManagerConfig, opts ...option.ClientOption) (Hook, error) {
+	if config.Project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	if config.CertificateID == "" {
+		return nil, errors.New("missing certificate ID")
+	}
+
+	certificateID, err := parseTemplate("certificateId", config.CertificateID)
+	if err != nil {
+		return nil, err
+	}
+
+	location := config.Location
+	if location == "" {
+		location = "global"
+	}
+
+	service, err := certificatemanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcpCertificateManagerHook{
+		service:       service,
+		parent:        fmt.Sprintf("projects/%s/locations/%s", config.Project, location),
+		certificateID: certificateID,
+	}, nil
+}
+
+func newGCPCertificateManagerHook(rawConfig []byte) (Hook, error) {
+	var config GCPCertificateManagerConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGCPCertificateManagerHook(context.Background(), config)
+}
+
+// Deploy updates the PEM of the certificate, or creates the certificate when it does not exist.
+// The update is a long-running operation of Certificate Manager, the hook does not wait for its completion.
+func (h *gcpCertificateManagerHook) Deploy(ctx context.Context, cert *Certificate) error {
+	id, err := execute(h.certificateID, cert)
+	if err != nil {
+		return err
+	}
+
+	certificate := &certificatemanager.Certificate{
+		SelfManaged: &certificatemanager.SelfManagedCertificate{
+			PemCertificate: string(cert.FullChain()),
+			PemPrivateKey:  string(cert.PrivateKey),
+		},
+	}
+
+	op, err := h.service.Projects.Locations.Certificates.
+		Patch(h.parent+"/certificates/"+id, certificate).
+		UpdateMask("selfManaged").
+		Context(ctx).
+		Do()
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		op, err = h.service.Projects.Locations.Certificates.
+			Create(h.parent, certificate).
+			CertificateId(id).
+			Context(ctx).
+			Do()
+	}
+
+	if err != nil {
+		return fmt.Errorf("certificate manager: %s: %w", id, err)
+	}
+
+	if op.Done && op.Error != nil {
+		return fmt.Errorf("certificate manager: %s: %s", id, op.Error.Message)
+	}
+
+	return nil
+}
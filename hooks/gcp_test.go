@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/certificatemanager/v1"
+	"google.golang.org/api/option"
+)
+
+func TestGCPCertificateManagerHook(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		exists   bool
+		expected string
+	}{
+		{
+			desc:     "update",
+			exists:   true,
+			expected: "patch",
+		},
+		{
+			desc:     "create",
+			expected: "create",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var (
+				calls    []string
+				received certificatemanager.Certificate
+			)
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			decode := func(rw http.ResponseWriter, req *http.Request) bool {
+				err := json.NewDecoder(req.Body).Decode(&received)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return false
+				}
+
+				return true
+			}
+
+			mux.HandleFunc("PATCH /v1/projects/my-project/locations/global/certificates/example", func(rw http.ResponseWriter, req *http.Request) {
+				calls = append(calls, "patch")
+
+				if !test.exists {
+					http.Error(rw, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+					return
+				}
+
+				if req.URL.Query().Get("updateMask") != "selfManaged" || !decode(rw, req) {
+					http.Error(rw, "invalid request", http.StatusBadRequest)
+					return
+				}
+
+				_, _ = rw.Write([]byte(`{"name":"operations/patch"}`))
+			})
+
+			mux.HandleFunc("POST /v1/projects/my-project/locations/global/certificates", func(rw http.ResponseWriter, req *http.Request) {
+				calls = append(calls, "create")
+
+				if req.URL.Query().Get("certificateId") != "example" || !decode(rw, req) {
+					http.Error(rw, "invalid request", http.StatusBadRequest)
+					return
+				}
+
+				_, _ = rw.Write([]byte(`{"name":"operations/create"}`))
+			})
+
+			hook, err := NewGCPCertificateManagerHook(context.Background(), GCPCertificateManagerConfig{
+				Project:       "my-project",
+				CertificateID: "{{.ID}}",
+			}, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+			require.NoError(t, err)
+
+			cert := testCertificate()
+
+			err = hook.Deploy(context.Background(), cert)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, calls[len(calls)-1])
+
+			require.NotNil(t, received.SelfManaged)
+			assert.Equal(t, string(cert.FullChain()), received.SelfManaged.PemCertificate)
+			assert.Equal(t, string(cert.PrivateKey), received.SelfManaged.PemPrivateKey)
+		})
+	}
+}
+
+func TestGCPCertificateManagerHook_operationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"name":"operations/patch","done":true,"error":{"code":3,"message":"invalid certificate"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	hook, err := NewGCPCertificateManagerHook(context.Background(), GCPCertificateManagerConfig{
+		Project:       "my-project",
+		Location:      "europe-west1",
+		CertificateID: "{{.ID}}",
+	}, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.EqualError(t, err, "certificate manager: example: invalid certificate")
+}
@@ -0,0 +1,249 @@
+// Package hooks deploys the certificates after their issuance:
+// write the PEM files, reload a server with a signal, push a Kubernetes Secret, upload to an HTTP API,
+// store in Vault KV, or import into AWS ACM, Google Cloud Certificate Manager and Azure Key Vault.
+//
+// The hooks are configured in YAML, in the order they run:
+//
+//	hooks:
+//	  - type: file
+//	    certFile: /etc/nginx/certs/{{.ID}}.crt
+//	    keyFile: /etc/nginx/certs/{{.ID}}.key
+//	  - type: signal
+//	    pidFile: /run/nginx.pid
+//	    signal: HUP
+//
+// The custom hooks implement Hook, and are made available to the YAML configs with Register.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const typeKey = "type"
+
+// Certificate is the issued certificate deployed by the hooks.
+type Certificate struct {
+	// ID identifies the certificate, e.g. the name of the certificate in the store.
+	ID      string
+	Domains []string
+	// Certificate is the PEM encoded certificate.
+	Certificate []byte
+	// IssuerCertificate is the PEM encoded chain of the issuer.
+	IssuerCertificate []byte
+	// PrivateKey is the PEM encoded private key.
+	PrivateKey []byte
+}
+
+// Domain returns the first domain of the certificate.
+func (c *Certificate) Domain() string {
+	if len(c.Domains) == 0 {
+		return ""
+	}
+
+	return c.Domains[0]
+}
+
+// FullChain returns the certificate followed by the chain of the issuer.
+func (c *Certificate) FullChain() []byte {
+	if len(c.IssuerCertificate) == 0 || bytes.Contains(c.Certificate, c.IssuerCertificate) {
+		return c.Certificate
+	}
+
+	chain := bytes.TrimRight(c.Certificate, "\n")
+
+	return append(append(chain[:len(chain):len(chain)], '\n'), c.IssuerCertificate...)
+}
+
+// Hook deploys an issued certificate.
+type Hook interface {
+	Deploy(ctx context.Context, cert *Certificate) error
+}
+
+// HookFunc is a function implementing Hook.
+type HookFunc func(ctx context.Context, cert *Certificate) error
+
+// Deploy calls f(ctx, cert).
+func (f HookFunc) Deploy(ctx context.Context, cert *Certificate) error {
+	return f(ctx, cert)
+}
+
+// Factory creates a hook from its YAML config (the entry without the `type` key).
+type Factory func(rawConfig []byte) (Hook, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		"file":                  newFileHook,
+		"signal":                newSignalHook,
+		"kubernetesSecret":      newKubernetesSecretHook,
+		"http":                  newHTTPHook,
+		"vault":                 newVaultHook,
+		"awsACM":                newAWSACMHook,
+		"gcpCertificateManager": newGCPCertificateManagerHook,
+		"azureKeyVault":         newAzureKeyVaultHook,
+	}
+)
+
+// Register makes the hook type available to the YAML configs, replacing the existing one.
+func Register(hookType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[hookType] = factory
+}
+
+// Types returns the hook types available to the YAML configs, sorted.
+func Types() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// Chain runs hooks in order.
+type Chain struct {
+	steps []step
+}
+
+type step struct {
+	name string
+	hook Hook
+}
+
+// NewChain returns a chain running the hooks in order.
+func NewChain(hooks ...Hook) *Chain {
+	chain := &Chain{}
+	for i, hook := range hooks {
+		chain.steps = append(chain.steps, step{name: fmt.Sprintf("#%d", i), hook: hook})
+	}
+
+	return chain
+}
+
+// Parse creates the chain of the hooks of the YAML config (`hooks` list).
+func Parse(raw []byte) (*Chain, error) {
+	var config struct {
+		Hooks []yaml.Node `yaml:"hooks"`
+	}
+
+	err := yaml.Unmarshal(raw, &config)
+	if err != nil {
+		return nil, fmt.Errorf("hooks: %w", err)
+	}
+
+	chain := &Chain{}
+
+	for i := range config.Hooks {
+		hookType, hook, err := parseHook(&config.Hooks[i])
+		if err != nil {
+			return nil, fmt.Errorf("hooks: #%d: %w", i, err)
+		}
+
+		chain.steps = append(chain.steps, step{name: fmt.Sprintf("#%d (%s)", i, hookType), hook: hook})
+	}
+
+	return chain, nil
+}
+
+func parseHook(node *yaml.Node) (string, Hook, error) {
+	if node.Kind != yaml.MappingNode {
+		return "", nil, errors.New("entry must be a mapping")
+	}
+
+	var hookType string
+
+	config := &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.Value == typeKey {
+			hookType = value.Value
+			continue
+		}
+
+		config.Content = append(config.Content, key, value)
+	}
+
+	if hookType == "" {
+		return "", nil, errors.New("missing hook type")
+	}
+
+	factoriesMu.RLock()
+	factory, ok := factories[hookType]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return "", nil, fmt.Errorf("unknown hook type %q", hookType)
+	}
+
+	rawConfig, err := yaml.Marshal(config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hook, err := factory(rawConfig)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", hookType, err)
+	}
+
+	return hookType, hook, nil
+}
+
+// Deploy runs the hooks in order, and stops at the first failure.
+func (c *Chain) Deploy(ctx context.Context, cert *Certificate) error {
+	for _, s := range c.steps {
+		err := s.hook.Deploy(ctx, cert)
+		if err != nil {
+			return fmt.Errorf("hooks: %s: %w", s.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of hooks of the chain.
+func (c *Chain) Len() int {
+	return len(c.steps)
+}
+
+// parseTemplate parses the template of a config value, e.g. `/etc/certs/{{.ID}}.crt`.
+// An empty value has a nil template.
+func parseTemplate(name, value string) (*template.Template, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// execute returns the value of the template for the certificate.
+func execute(tmpl *template.Template, cert *Certificate) (string, error) {
+	buf := &bytes.Buffer{}
+
+	err := tmpl.Execute(buf, cert)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,132 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"lego-toolbox/yamlconfig"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+// HTTPConfig configures the http hook, uploading the certificate to an HTTP API (load balancer, CDN, ...).
+//
+// The body is the JSON object:
+//
+//	{"id": "...", "domains": ["..."], "certificate": "<PEM>", "chain": "<PEM>", "privateKey": "<PEM>"}
+type HTTPConfig struct {
+	// URL is the endpoint, a template of the Certificate, e.g. `https://lb.internal/api/certificates/{{.ID}}`.
+	URL string `yaml:"url"`
+	// Method is the HTTP method, PUT when empty.
+	Method string `yaml:"method"`
+	// Headers are added to the request (e.g. Authorization).
+	Headers map[string]string `yaml:"headers"`
+	// Timeout bounds the request, 30s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type httpPayload struct {
+	ID          string   `json:"id"`
+	Domains     []string `json:"domains"`
+	Certificate string   `json:"certificate"`
+	Chain       string   `json:"chain"`
+	PrivateKey  string   `json:"privateKey"`
+}
+
+type httpHook struct {
+	url     *template.Template
+	method  string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPHook returns the hook uploading the certificate to the endpoint.
+func NewHTTPHook(config HTTPConfig) (Hook, error) {
+	if config.URL == "" {
+		return nil, errors.New("missing URL")
+	}
+
+	endpoint, err := parseTemplate("url", config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	method := config.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &httpHook{
+		url:     endpoint,
+		method:  method,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func newHTTPHook(rawConfig []byte) (Hook, error) {
+	var config HTTPConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHTTPHook(config)
+}
+
+// Deploy uploads the certificate, a response status code other than 2xx is an error.
+func (h *httpHook) Deploy(ctx context.Context, cert *Certificate) error {
+	endpoint, err := execute(h.url, cert)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(httpPayload{
+		ID:          cert.ID,
+		Domains:     cert.Domains,
+		Certificate: string(cert.Certificate),
+		Chain:       string(cert.IssuerCertificate),
+		PrivateKey:  string(cert.PrivateKey),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, h.method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected status code %d: %s", h.method, endpoint, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	return nil
+}
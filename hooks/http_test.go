@@ -0,0 +1,66 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHook(t *testing.T) {
+	var received httpPayload
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("PUT /certificates/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if req.PathValue("id") != "example" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&received)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	hook, err := NewHTTPHook(HTTPConfig{
+		URL:     server.URL + "/certificates/{{.ID}}",
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+
+	cert := testCertificate()
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	expected := httpPayload{
+		ID:          "example",
+		Domains:     []string{"example.com", "*.example.com"},
+		Certificate: string(cert.Certificate),
+		Chain:       string(cert.IssuerCertificate),
+		PrivateKey:  string(cert.PrivateKey),
+	}
+	assert.Equal(t, expected, received)
+
+	hook, err = NewHTTPHook(HTTPConfig{URL: server.URL + "/certificates/{{.ID}}"})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), cert)
+	require.EqualError(t, err, "PUT "+server.URL+"/certificates/example: unexpected status code 401: unauthorized")
+}
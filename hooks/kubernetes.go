@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"lego-toolbox/yamlconfig"
+)
+
+// KubernetesSecretConfig configures the kubernetesSecret hook, pushing a TLS Secret (kubernetes.io/tls).
+type KubernetesSecretConfig struct {
+	Namespace string `yaml:"namespace"`
+	// Name is the name of the Secret, a template of the Certificate, e.g. `{{.ID}}-tls`.
+	Name string `yaml:"name"`
+}
+
+type kubernetesSecretHook struct {
+	client    kubernetes.Interface
+	namespace string
+	name      *template.Template
+}
+
+// NewKubernetesSecretHook returns the hook creating or updating the TLS Secret with the client.
+func NewKubernetesSecretHook(client kubernetes.Interface, config KubernetesSecretConfig) (Hook, error) {
+	if client == nil {
+		return nil, errors.New("the Kubernetes client is nil")
+	}
+
+	if config.Namespace == "" || config.Name == "" {
+		return nil, errors.New("missing namespace or name")
+	}
+
+	name, err := parseTemplate("name", config.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubernetesSecretHook{client: client, namespace: config.Namespace, name: name}, nil
+}
+
+// newKubernetesSecretHook creates the hook with the in-cluster config.
+func newKubernetesSecretHook(rawConfig []byte) (Hook, error) {
+	var config KubernetesSecretConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKubernetesSecretHook(client, config)
+}
+
+// Deploy creates the Secret, or updates its certificate and key.
+func (h *kubernetesSecretHook) Deploy(ctx context.Context, cert *Certificate) error {
+	name, err := execute(h.name, cert)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{
+		corev1.TLSCertKey:       cert.FullChain(),
+		corev1.TLSPrivateKeyKey: cert.PrivateKey,
+	}
+
+	secrets := h.client.CoreV1().Secrets(h.namespace)
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: h.namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create Secret %s/%s: %w", h.namespace, name, err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get Secret %s/%s: %w", h.namespace, name, err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	for k, v := range data {
+		secret.Data[k] = v
+	}
+
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update Secret %s/%s: %w", h.namespace, name, err)
+	}
+
+	return nil
+}
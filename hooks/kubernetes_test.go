@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesSecretHook(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing-tls", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{"ca.crt": []byte("ca"), corev1.TLSCertKey: []byte("old")},
+	})
+
+	cert := testCertificate()
+
+	hook, err := NewKubernetesSecretHook(client, KubernetesSecretConfig{Namespace: "default", Name: "{{.ID}}-tls"})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	secret, err := client.CoreV1().Secrets("default").Get(context.Background(), "example-tls", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	assert.Equal(t, cert.FullChain(), secret.Data[corev1.TLSCertKey])
+	assert.Equal(t, cert.PrivateKey, secret.Data[corev1.TLSPrivateKeyKey])
+
+	hook, err = NewKubernetesSecretHook(client, KubernetesSecretConfig{Namespace: "default", Name: "existing-tls"})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), cert)
+	require.NoError(t, err)
+
+	secret, err = client.CoreV1().Secrets("default").Get(context.Background(), "existing-tls", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, cert.FullChain(), secret.Data[corev1.TLSCertKey])
+	assert.Equal(t, []byte("ca"), secret.Data["ca.crt"])
+	assert.Equal(t, map[string]string{"app": "web"}, secret.Labels)
+}
+
+func TestNewKubernetesSecretHook_errors(t *testing.T) {
+	_, err := NewKubernetesSecretHook(nil, KubernetesSecretConfig{Namespace: "default", Name: "tls"})
+	require.EqualError(t, err, "the Kubernetes client is nil")
+
+	_, err = NewKubernetesSecretHook(fake.NewSimpleClientset(), KubernetesSecretConfig{Name: "tls"})
+	require.EqualError(t, err, "missing namespace or name")
+}
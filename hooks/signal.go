@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"lego-toolbox/yamlconfig"
+)
+
+// SignalConfig configures the signal hook, reloading a server (e.g. nginx with HUP).
+type SignalConfig struct {
+	// PIDFile is the file holding the process ID of the server, e.g. /run/nginx.pid.
+	PIDFile string `yaml:"pidFile"`
+	// Signal is the name of the signal (HUP, USR1, USR2, ...), HUP when empty.
+	Signal string `yaml:"signal"`
+}
+
+type signalHook struct {
+	pidFile string
+	signal  os.Signal
+}
+
+// NewSignalHook returns the hook sending the signal to the process of the PID file.
+func NewSignalHook(config SignalConfig) (Hook, error) {
+	if config.PIDFile == "" {
+		return nil, errors.New("missing PID file")
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(config.Signal), "SIG")
+	if name == "" {
+		name = "HUP"
+	}
+
+	sig, ok := signals[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signal %q", config.Signal)
+	}
+
+	return &signalHook{pidFile: config.PIDFile, signal: sig}, nil
+}
+
+func newSignalHook(rawConfig []byte) (Hook, error) {
+	var config SignalConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSignalHook(config)
+}
+
+// Deploy sends the signal to the process.
+func (h *signalHook) Deploy(_ context.Context, _ *Certificate) error {
+	raw, err := os.ReadFile(h.pidFile)
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return fmt.Errorf("invalid PID file %s: %w", h.pidFile, err)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	err = process.Signal(h.signal)
+	if err != nil {
+		return fmt.Errorf("signal %v to %d: %w", h.signal, pid, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package hooks
+
+import "os"
+
+// signals is empty: the processes cannot receive signals other than kill.
+var signals = map[string]os.Signal{}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignalHook(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "test.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600))
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	t.Cleanup(func() { signal.Stop(ch) })
+
+	hook, err := NewSignalHook(SignalConfig{PIDFile: pidFile, Signal: "SIGUSR1"})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.NoError(t, err)
+
+	select {
+	case sig := <-ch:
+		require.Equal(t, syscall.SIGUSR1, sig)
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal not received")
+	}
+}
+
+func TestSignalHook_invalidPIDFile(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "test.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte("nginx"), 0o600))
+
+	hook, err := NewSignalHook(SignalConfig{PIDFile: pidFile})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.ErrorContains(t, err, "invalid PID file")
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package hooks
+
+import (
+	"os"
+	"syscall"
+)
+
+// signals are the signals of the signal hook, by name.
+var signals = map[string]os.Signal{
+	"HUP":  syscall.SIGHUP,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+	"QUIT": syscall.SIGQUIT,
+}
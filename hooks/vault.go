@@ -0,0 +1,185 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables of the Vault CLI, used when the config leaves the address or the token empty.
+const (
+	EnvVaultAddr      = "VAULT_ADDR"
+	EnvVaultToken     = "VAULT_TOKEN"
+	EnvVaultNamespace = "VAULT_NAMESPACE"
+)
+
+// VaultConfig configures the vault hook, writing the certificate to a KV secret of HashiCorp Vault.
+// The secret holds the keys `certificate`, `chain`, `fullchain` and `privateKey` (PEM).
+//
+// The PKI secrets engine issues its own certificates, and only imports CA issuers:
+// the certificates issued by the ACME CAs are stored in KV, where the Vault Agent templates and the consumers read them.
+type VaultConfig struct {
+	// Address is the address of Vault, VAULT_ADDR when empty.
+	Address string `yaml:"address"`
+	// Token is the Vault token, VAULT_TOKEN (or VAULT_TOKEN_FILE) when empty.
+	Token string `yaml:"token"`
+	// Namespace is the Vault Enterprise namespace, VAULT_NAMESPACE when empty.
+	Namespace string `yaml:"namespace"`
+	// Mount is the mount path of the KV secrets engine, `secret` when empty.
+	Mount string `yaml:"mount"`
+	// Path is the path of the secret in the mount, a template of the Certificate, e.g. `certs/{{.ID}}`.
+	Path string `yaml:"path"`
+	// KVVersion is the version of the KV secrets engine (1 or 2), 2 when zero.
+	KVVersion int `yaml:"kvVersion"`
+	// Timeout bounds the request, 30s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type vaultHook struct {
+	address   *url.URL
+	token     string
+	namespace string
+	mount     string
+	path      *template.Template
+	kvVersion int
+	client    *http.Client
+}
+
+// NewVaultHook returns the hook writing the certificate to the KV secret.
+func NewVaultHook(config VaultConfig) (Hook, error) {
+	address := config.Address
+	if address == "" {
+		address = env.GetOrFile(EnvVaultAddr)
+	}
+
+	token := config.Token
+	if token == "" {
+		token = env.GetOrFile(EnvVaultToken)
+	}
+
+	if address == "" || token == "" {
+		return nil, errors.New("missing address or token")
+	}
+
+	if config.Path == "" {
+		return nil, errors.New("missing path")
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	path, err := parseTemplate("path", config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &vaultHook{
+		address:   u,
+		token:     token,
+		namespace: config.Namespace,
+		mount:     strings.Trim(config.Mount, "/"),
+		path:      path,
+		kvVersion: config.KVVersion,
+		client:    &http.Client{Timeout: config.Timeout},
+	}
+
+	if h.namespace == "" {
+		h.namespace = env.GetOrFile(EnvVaultNamespace)
+	}
+
+	if h.mount == "" {
+		h.mount = "secret"
+	}
+
+	if h.kvVersion == 0 {
+		h.kvVersion = 2
+	}
+
+	if h.kvVersion != 1 && h.kvVersion != 2 {
+		return nil, fmt.Errorf("unsupported KV version %d", h.kvVersion)
+	}
+
+	if h.client.Timeout <= 0 {
+		h.client.Timeout = defaultHTTPTimeout
+	}
+
+	return h, nil
+}
+
+func newVaultHook(rawConfig []byte) (Hook, error) {
+	var config VaultConfig
+
+	err := yamlconfig.UnmarshalStrict(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVaultHook(config)
+}
+
+// Deploy writes the secret, a new version of the secret with KV version 2.
+func (h *vaultHook) Deploy(ctx context.Context, cert *Certificate) error {
+	path, err := execute(h.path, cert)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{
+		"certificate": string(cert.Certificate),
+		"chain":       string(cert.IssuerCertificate),
+		"fullchain":   string(cert.FullChain()),
+		"privateKey":  string(cert.PrivateKey),
+	}
+
+	var payload any = data
+
+	endpoint := h.address.JoinPath("v1", h.mount, strings.Trim(path, "/"))
+	if h.kvVersion == 2 {
+		endpoint = h.address.JoinPath("v1", h.mount, "data", strings.Trim(path, "/"))
+		payload = map[string]any{"data": data}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", h.token)
+
+	if h.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", h.namespace)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault: write %s/%s: unexpected status code %d: %s", h.mount, path, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultHook(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		kvVersion int
+		pattern   string
+		wrapped   bool
+	}{
+		{
+			desc:    "KV version 2",
+			pattern: "POST /v1/kv/data/certs/example",
+			wrapped: true,
+		},
+		{
+			desc:      "KV version 1",
+			kvVersion: 1,
+			pattern:   "POST /v1/kv/certs/example",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var received map[string]any
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc(test.pattern, func(rw http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("X-Vault-Token") != "token" || req.Header.Get("X-Vault-Namespace") != "team" {
+					http.Error(rw, "permission denied", http.StatusForbidden)
+					return
+				}
+
+				err := json.NewDecoder(req.Body).Decode(&received)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				rw.WriteHeader(http.StatusNoContent)
+			})
+
+			hook, err := NewVaultHook(VaultConfig{
+				Address:   server.URL,
+				Token:     "token",
+				Namespace: "team",
+				Mount:     "/kv/",
+				Path:      "certs/{{.ID}}",
+				KVVersion: test.kvVersion,
+			})
+			require.NoError(t, err)
+
+			cert := testCertificate()
+
+			err = hook.Deploy(context.Background(), cert)
+			require.NoError(t, err)
+
+			data := received
+			if test.wrapped {
+				data, _ = received["data"].(map[string]any)
+			}
+
+			assert.Equal(t, map[string]any{
+				"certificate": string(cert.Certificate),
+				"chain":       string(cert.IssuerCertificate),
+				"fullchain":   string(cert.FullChain()),
+				"privateKey":  string(cert.PrivateKey),
+			}, data)
+		})
+	}
+}
+
+func TestVaultHook_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, `{"errors":["permission denied"]}`, http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	hook, err := NewVaultHook(VaultConfig{Address: server.URL, Token: "token", Path: "certs/{{.ID}}"})
+	require.NoError(t, err)
+
+	err = hook.Deploy(context.Background(), testCertificate())
+	require.EqualError(t, err, `vault: write secret/certs/example: unexpected status code 403: {"errors":["permission denied"]}`)
+}
+
+func TestNewVaultHook_errors(t *testing.T) {
+	t.Setenv(EnvVaultAddr, "")
+	t.Setenv(EnvVaultToken, "")
+
+	_, err := NewVaultHook(VaultConfig{Path: "certs/{{.ID}}"})
+	require.EqualError(t, err, "missing address or token")
+
+	_, err = NewVaultHook(VaultConfig{Address: "https://vault.example.com", Token: "token"})
+	require.EqualError(t, err, "missing path")
+
+	_, err = NewVaultHook(VaultConfig{Address: "https://vault.example.com", Token: "token", Path: "certs", KVVersion: 3})
+	require.EqualError(t, err, "unsupported KV version 3")
+}
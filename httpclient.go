@@ -0,0 +1,84 @@
+package legotoolbox
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/go-acme/lego/v4/log"
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/internal/clientdebug"
+	"lego-toolbox/internal/ipfamily"
+)
+
+// httpClientConfig is the part of the provider configs customizing the HTTP client.
+//
+//	debugHTTP: true
+//	ipFamily: ipv6 # auto, ipv4, ipv6
+type httpClientConfig struct {
+	DebugHTTP bool            `yaml:"debugHTTP"`
+	IPFamily  ipfamily.Family `yaml:"ipFamily"`
+}
+
+// parseHTTPClientConfig parses the part of the provider config customizing the HTTP client.
+func parseHTTPClientConfig(rawConfig []byte) (httpClientConfig, error) {
+	var config httpClientConfig
+
+	err := yaml.Unmarshal(rawConfig, &config)
+
+	return config, err
+}
+
+// withHTTPClient customizes the HTTP client of the provider config:
+// the connections are restricted to the IP family set with `ipFamily`,
+// and the client is wrapped with the debug transport when `debugHTTP: true` is set.
+// The configs without an HTTPClient field (SDK based providers) are returned unchanged, with a warning.
+func withHTTPClient[T any](rawConfig []byte, cfg T) T {
+	config, err := parseHTTPClientConfig(rawConfig)
+	if err != nil || (!config.DebugHTTP && (config.IPFamily == "" || config.IPFamily == ipfamily.Auto)) {
+		return cfg
+	}
+
+	field, ok := httpClientField(cfg)
+	if !ok {
+		if config.IPFamily != "" && config.IPFamily != ipfamily.Auto {
+			log.Warnf("ipFamily: the HTTP client of the provider cannot be configured, %s ignored", config.IPFamily)
+		}
+
+		if config.DebugHTTP {
+			log.Warnf("debugHTTP: the HTTP client of the provider cannot be configured, ignored")
+		}
+
+		return cfg
+	}
+
+	client, _ := field.Interface().(*http.Client)
+
+	client, err = ipfamily.Wrap(client, config.IPFamily)
+	if err != nil {
+		log.Warnf("%v, %s ignored", err, config.IPFamily)
+		client, _ = field.Interface().(*http.Client)
+	}
+
+	if config.DebugHTTP {
+		client = clientdebug.Wrap(client)
+	}
+
+	field.Set(reflect.ValueOf(client))
+
+	return cfg
+}
+
+// httpClientField returns the settable HTTPClient field of the provider config.
+func httpClientField(cfg any) (reflect.Value, bool) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	field := v.Elem().FieldByName("HTTPClient")
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf(&http.Client{}) {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
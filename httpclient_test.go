@@ -0,0 +1,99 @@
+package legotoolbox
+
+import (
+	"bytes"
+	stdlog "log"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/internal/clientdebug"
+	"lego-toolbox/internal/ipfamily"
+	"lego-toolbox/providers/dns/httpreq"
+)
+
+func TestWithHTTPClient_debugHTTP(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		rawConfig string
+		expected  bool
+	}{
+		{
+			desc:      "enabled",
+			rawConfig: "endpoint: https://example.com\ndebugHTTP: true\n",
+			expected:  true,
+		},
+		{
+			desc:      "disabled",
+			rawConfig: "endpoint: https://example.com\ndebugHTTP: false\n",
+		},
+		{
+			desc:      "missing",
+			rawConfig: "endpoint: https://example.com\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			cfg, err := httpreq.ParseConfig([]byte(test.rawConfig))
+			require.NoError(t, err)
+
+			original := cfg.HTTPClient
+
+			cfg = withHTTPClient([]byte(test.rawConfig), cfg)
+
+			_, ok := cfg.HTTPClient.Transport.(*clientdebug.Transport)
+			assert.Equal(t, test.expected, ok)
+
+			if test.expected {
+				assert.Equal(t, original.Timeout, cfg.HTTPClient.Timeout)
+			}
+		})
+	}
+}
+
+func TestWithHTTPClient_noHTTPClient(t *testing.T) {
+	type config struct {
+		Client http.Client
+	}
+
+	backupLogger := log.Logger
+	t.Cleanup(func() { log.Logger = backupLogger })
+
+	output := &bytes.Buffer{}
+	log.Logger = stdlog.New(output, "", 0)
+
+	cfg := withHTTPClient([]byte("debugHTTP: true"), &config{})
+	assert.Nil(t, cfg.Client.Transport)
+
+	assert.Contains(t, output.String(), "debugHTTP: the HTTP client of the provider cannot be configured, ignored")
+}
+
+func TestWithHTTPClient_ipFamily(t *testing.T) {
+	rawConfig := []byte("endpoint: https://example.com\ndebugHTTP: true\nipFamily: ipv6\n")
+
+	cfg, err := httpreq.ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	cfg = withHTTPClient(rawConfig, cfg)
+
+	debug, ok := cfg.HTTPClient.Transport.(*clientdebug.Transport)
+	require.True(t, ok)
+
+	transport, ok := debug.Base.(*http.Transport)
+	require.True(t, ok)
+
+	assert.NotNil(t, transport.DialContext)
+}
+
+func TestParseHTTPClientConfig(t *testing.T) {
+	config, err := parseHTTPClientConfig([]byte("ipFamily: IPv4\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, ipfamily.IPv4, config.IPFamily)
+
+	_, err = parseHTTPClientConfig([]byte("ipFamily: ipv5\n"))
+	require.EqualError(t, err, `ipFamily: invalid IP family "ipv5": must be one of auto, ipv4, ipv6`)
+}
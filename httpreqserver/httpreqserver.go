@@ -0,0 +1,220 @@
+// Package httpreqserver implements the server side of the httpreq provider protocol,
+// so the agents using the httpreq provider resolve their challenges with the toolbox providers
+// while the credentials stay on the host running the server.
+//
+// The endpoint of the httpreq provider selects the provider:
+// `https://acme.example.com` uses the default provider, `https://acme.example.com/cloudflare@personal` uses the named one.
+//
+// Both modes of the httpreq provider are supported:
+// the default mode sends `{"fqdn", "value"}`, the RAW mode sends `{"domain", "token", "keyAuth"}`.
+package httpreqserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	"lego-toolbox/rawrecord"
+)
+
+// maxBodySize is the maximum size of a request.
+const maxBodySize = 1 << 16
+
+// Resolver returns the provider registered under the name, the name is empty for the default provider.
+// legotoolbox.GetProviderInstance is a suitable Resolver.
+type Resolver func(name string) (challenge.Provider, error)
+
+// ErrProviderNotFound is returned by a Resolver when no provider is registered under the name.
+// It is reported to the clients with the 404 status.
+var ErrProviderNotFound = errors.New("provider not found")
+
+// Providers returns a Resolver for a fixed set of providers.
+func Providers(providers map[string]challenge.Provider) Resolver {
+	return func(name string) (challenge.Provider, error) {
+		provider, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrProviderNotFound, name)
+		}
+
+		return provider, nil
+	}
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithBasicAuth requires the credentials set in the httpreq provider (HTTPREQ_USERNAME, HTTPREQ_PASSWORD).
+func WithBasicAuth(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// message is the body sent by the httpreq provider, in the default mode (FQDN, Value) or in the RAW mode.
+type message struct {
+	FQDN  string `json:"fqdn"`
+	Value string `json:"value"`
+
+	Domain  string `json:"domain"`
+	Token   string `json:"token"`
+	KeyAuth string `json:"keyAuth"`
+}
+
+// Server serves the httpreq protocol.
+type Server struct {
+	resolve  Resolver
+	username string
+	password string
+	mux      *http.ServeMux
+}
+
+// NewServer returns a Server resolving the providers with resolve.
+func NewServer(resolve Resolver, opts ...Option) *Server {
+	s := &Server{
+		resolve: resolve,
+		mux:     http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET /healthz", func(rw http.ResponseWriter, _ *http.Request) { _, _ = rw.Write([]byte("ok")) })
+	s.mux.HandleFunc("POST /present", s.handle(actionPresent))
+	s.mux.HandleFunc("POST /cleanup", s.handle(actionCleanUp))
+	s.mux.HandleFunc("POST /{provider}/present", s.handle(actionPresent))
+	s.mux.HandleFunc("POST /{provider}/cleanup", s.handle(actionCleanUp))
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(rw, req)
+}
+
+// ListenAndServe serves the protocol on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return (&http.Server{Addr: addr, Handler: s}).ListenAndServe()
+}
+
+// ListenAndServeTLS serves the protocol on addr with TLS, recommended with the basic authentication.
+func (s *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	return (&http.Server{Addr: addr, Handler: s}).ListenAndServeTLS(certFile, keyFile)
+}
+
+type action string
+
+const (
+	actionPresent action = "present"
+	actionCleanUp action = "cleanup"
+)
+
+func (s *Server) handle(act action) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !s.authorized(req) {
+			rw.Header().Set("WWW-Authenticate", `Basic realm="httpreq"`)
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var msg message
+
+		err := json.NewDecoder(io.LimitReader(req.Body, maxBodySize)).Decode(&msg)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		domain, token, keyAuth, err := challengeOf(&msg)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		name := req.PathValue("provider")
+
+		provider, err := s.resolve(name)
+		if errors.Is(err, ErrProviderNotFound) {
+			http.Error(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		switch act {
+		case actionPresent:
+			err = provider.Present(domain, token, keyAuth)
+		case actionCleanUp:
+			err = provider.CleanUp(domain, token, keyAuth)
+		}
+
+		if err != nil {
+			log.Warnf("httpreqserver: %s %s (%s): %v", act, domain, name, err)
+
+			http.Error(rw, err.Error(), http.StatusBadGateway)
+
+			return
+		}
+
+		writeJSON(rw, http.StatusOK, map[string]bool{"success": true})
+	}
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	if s.username == "" && s.password == "" {
+		return true
+	}
+
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	// both comparisons are made, so the timing doesn't tell which one failed.
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(s.username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) == 1
+
+	return userOK && passOK
+}
+
+// challengeOf returns the arguments of Present and CleanUp.
+// The record of the default mode is passed to the provider with rawrecord.KeyAuth.
+func challengeOf(msg *message) (domain, token, keyAuth string, err error) {
+	if msg.KeyAuth != "" {
+		if msg.Domain == "" {
+			return "", "", "", errors.New("missing domain")
+		}
+
+		return msg.Domain, msg.Token, msg.KeyAuth, nil
+	}
+
+	if msg.FQDN == "" || msg.Value == "" {
+		return "", "", "", errors.New("missing fqdn or value")
+	}
+
+	fqdn := dns01.ToFqdn(msg.FQDN)
+
+	domain = dns01.UnFqdn(strings.TrimPrefix(fqdn, "_acme-challenge."))
+
+	// the token must identify the record for the providers tracking their records by token.
+	return domain, fqdn + "/" + msg.Value, rawrecord.KeyAuth(fqdn, msg.Value), nil
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(v)
+}
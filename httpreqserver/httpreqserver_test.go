@@ -0,0 +1,146 @@
+package httpreqserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/providers/dns/httpreq"
+)
+
+func setupServer(t *testing.T, providers map[string]challenge.Provider, opts ...Option) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(NewServer(Providers(providers), opts...))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newClient(t *testing.T, endpoint, mode, username, password string) *httpreq.DNSProvider {
+	t.Helper()
+
+	config := httpreq.DefaultConfig()
+	config.Mode = mode
+	config.Username = username
+	config.Password = password
+
+	var err error
+	config.Endpoint, err = url.Parse(endpoint)
+	require.NoError(t, err)
+
+	client, err := httpreq.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	return client
+}
+
+func newFake(t *testing.T, config *fake.Config) *fake.DNSProvider {
+	t.Helper()
+
+	provider, err := fake.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestServer_default(t *testing.T) {
+	provider := newFake(t, fake.DefaultConfig())
+
+	server := setupServer(t, map[string]challenge.Provider{"": provider})
+
+	client := newClient(t, server.URL, "", "", "")
+
+	err := client.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	calls := provider.CallsOf(fake.CallPresent)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "example.com", calls[0].Domain)
+	assert.Len(t, provider.TXT("_acme-challenge.example.com."), 1)
+
+	err = client.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	provider.AssertNoRecords(t)
+}
+
+func TestServer_raw(t *testing.T) {
+	provider := newFake(t, fake.DefaultConfig())
+
+	server := setupServer(t, map[string]challenge.Provider{"cloudflare@personal": provider})
+
+	client := newClient(t, server.URL+"/cloudflare@personal", "RAW", "", "")
+
+	err := client.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	calls := provider.CallsOf(fake.CallPresent)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "example.com", calls[0].Domain)
+	assert.Equal(t, "token", calls[0].Token)
+
+	err = client.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	provider.AssertNoRecords(t)
+}
+
+func TestServer_basicAuth(t *testing.T) {
+	provider := newFake(t, fake.DefaultConfig())
+
+	server := setupServer(t, map[string]challenge.Provider{"": provider}, WithBasicAuth("user", "secret"))
+
+	err := newClient(t, server.URL, "", "user", "wrong").Present("example.com", "token", "keyAuth")
+	require.ErrorContains(t, err, "401")
+
+	err = newClient(t, server.URL, "", "user", "secret").Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	provider.AssertCallCount(t, fake.CallPresent, 1)
+}
+
+func TestServer_errors(t *testing.T) {
+	config := fake.DefaultConfig()
+	config.FailPresentOn = []int{1}
+
+	server := setupServer(t, map[string]challenge.Provider{"": newFake(t, config)})
+
+	testCases := []struct {
+		desc     string
+		path     string
+		body     string
+		expected int
+	}{
+		{desc: "invalid body", path: "/present", body: "{", expected: http.StatusBadRequest},
+		{desc: "missing value", path: "/present", body: `{"fqdn":"_acme-challenge.example.com."}`, expected: http.StatusBadRequest},
+		{desc: "unknown provider", path: "/route53/present", body: `{"fqdn":"_acme-challenge.example.com.","value":"v"}`, expected: http.StatusNotFound},
+		{desc: "provider failure", path: "/present", body: `{"fqdn":"_acme-challenge.example.com.","value":"v"}`, expected: http.StatusBadGateway},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := http.Post(server.URL+test.path, "application/json", strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expected, resp.StatusCode)
+		})
+	}
+}
+
+func Test_challengeOf(t *testing.T) {
+	domain, token, keyAuth, err := challengeOf(&message{FQDN: "_acme-challenge.www.example.com", Value: "v"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "www.example.com", domain)
+	assert.Equal(t, "_acme-challenge.www.example.com./v", token)
+	assert.NotEmpty(t, keyAuth)
+}
@@ -0,0 +1,91 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// instanceSeparator separates the provider name from the instance name in a provider identifier.
+const instanceSeparator = "@"
+
+var (
+	defaultProviderMu sync.RWMutex
+	defaultProviderID string
+)
+
+// instances holds the configured provider instances keyed by identifier.
+var instances sync.Map
+
+// SplitProviderID splits a `name@instance` identifier into the provider name and the instance name.
+// The instance is empty when the identifier has no instance suffix.
+func SplitProviderID(id string) (name, instance string) {
+	name, instance, _ = strings.Cut(id, instanceSeparator)
+	return name, instance
+}
+
+// JoinProviderID builds a `name@instance` identifier.
+func JoinProviderID(name, instance string) string {
+	if instance == "" {
+		return name
+	}
+
+	return name + instanceSeparator + instance
+}
+
+// SetDefaultProvider sets the provider identifier used when no identifier is given.
+func SetDefaultProvider(id string) {
+	defaultProviderMu.Lock()
+	defer defaultProviderMu.Unlock()
+
+	defaultProviderID = id
+}
+
+// DefaultProvider returns the provider identifier used when no identifier is given.
+func DefaultProvider() string {
+	defaultProviderMu.RLock()
+	defer defaultProviderMu.RUnlock()
+
+	return defaultProviderID
+}
+
+// RegisterProviderInstance stores a configured provider under its `name@instance` identifier.
+func RegisterProviderInstance(id string, provider challenge.Provider) error {
+	if id == "" {
+		return errors.New("provider identifier is empty")
+	}
+
+	if provider == nil {
+		return fmt.Errorf("provider %q is nil", id)
+	}
+
+	instances.Store(id, provider)
+
+	return nil
+}
+
+// UnregisterProviderInstance removes a configured provider.
+func UnregisterProviderInstance(id string) {
+	instances.Delete(id)
+}
+
+// GetProviderInstance returns the configured provider registered under the identifier.
+// An empty identifier selects the default provider.
+func GetProviderInstance(id string) (challenge.Provider, error) {
+	if id == "" {
+		id = DefaultProvider()
+		if id == "" {
+			return nil, errors.New("no default provider configured")
+		}
+	}
+
+	value, ok := instances.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("provider instance %q not registered", id)
+	}
+
+	return value.(challenge.Provider), nil
+}
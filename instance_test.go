@@ -0,0 +1,84 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/exec"
+)
+
+func TestSplitProviderID(t *testing.T) {
+	testCases := []struct {
+		id       string
+		name     string
+		instance string
+	}{
+		{id: "cloudflare", name: "cloudflare"},
+		{id: "cloudflare@personal", name: "cloudflare", instance: "personal"},
+		{id: "cloudflare@", name: "cloudflare"},
+		{id: "cloudflare@a@b", name: "cloudflare", instance: "a@b"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.id, func(t *testing.T) {
+			name, instance := SplitProviderID(test.id)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.instance, instance)
+		})
+	}
+}
+
+func TestNewDNSChallengeProviderByName_instance(t *testing.T) {
+	provider, err := NewDNSChallengeProviderByName("exec@second", []byte("program: /usr/bin/true"))
+	require.NoError(t, err)
+
+	assert.IsType(t, &exec.DNSProvider{}, provider)
+}
+
+func TestNewDNSChallengeProviderByName_default(t *testing.T) {
+	t.Cleanup(func() { SetDefaultProvider("") })
+
+	_, err := NewDNSChallengeProviderByName("", nil)
+	require.Error(t, err)
+
+	SetDefaultProvider("exec@main")
+
+	provider, err := NewDNSChallengeProviderByName("", []byte("program: /usr/bin/true"))
+	require.NoError(t, err)
+
+	assert.IsType(t, &exec.DNSProvider{}, provider)
+}
+
+func TestGetProviderInstance(t *testing.T) {
+	t.Cleanup(func() {
+		SetDefaultProvider("")
+		UnregisterProviderInstance("exec@a")
+		UnregisterProviderInstance("exec@b")
+	})
+
+	a, err := exec.NewDNSProviderConfig(exec.DefaultConfig())
+	require.NoError(t, err)
+
+	b, err := exec.NewDNSProviderConfig(exec.DefaultConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, RegisterProviderInstance("exec@a", a))
+	require.NoError(t, RegisterProviderInstance("exec@b", b))
+
+	_, err = GetProviderInstance("")
+	require.EqualError(t, err, "no default provider configured")
+
+	provider, err := GetProviderInstance("exec@b")
+	require.NoError(t, err)
+	assert.Same(t, b, provider)
+
+	SetDefaultProvider("exec@a")
+
+	provider, err = GetProviderInstance("")
+	require.NoError(t, err)
+	assert.Same(t, a, provider)
+
+	_, err = GetProviderInstance("exec@c")
+	require.EqualError(t, err, `provider instance "exec@c" not registered`)
+}
@@ -0,0 +1,230 @@
+// Package clientdebug provides an HTTP transport logging the API calls of the DNS providers, with the secrets redacted.
+package clientdebug
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+const redacted = "***"
+
+// sensitive are the fragments of the header and query parameter names holding secrets.
+var sensitive = []string{"auth", "key", "token", "secret", "password", "passwd", "signature", "cookie", "session", "credential"}
+
+// Transport logs the method, URL, status and timing of each request.
+// The values of the headers, query parameters and path segments holding secrets are redacted.
+type Transport struct {
+	// Base is the underlying transport, http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// Logf is the logging function, log.Infof when nil.
+	Logf func(format string, args ...any)
+}
+
+// Wrap returns a shallow copy of the client using a debug Transport.
+// A nil client is replaced by a copy of http.DefaultClient.
+func Wrap(client *http.Client) *http.Client {
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+
+	if _, ok := c.Transport.(*Transport); !ok {
+		c.Transport = &Transport{Base: c.Transport}
+	}
+
+	return &c
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+
+	resp, err := base.RoundTrip(req)
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		t.logf("[debugHTTP] %s %s -> error: %v (%s) request headers: %s",
+			req.Method, RedactURL(req.URL), err, elapsed, formatHeader(req.Header))
+		return nil, err
+	}
+
+	t.logf("[debugHTTP] %s %s -> %d (%s) request headers: %s response headers: %s",
+		req.Method, RedactURL(req.URL), resp.StatusCode, elapsed, formatHeader(req.Header), formatHeader(resp.Header))
+
+	return resp, nil
+}
+
+func (t *Transport) logf(format string, args ...any) {
+	if t.Logf != nil {
+		t.Logf(format, args...)
+		return
+	}
+
+	log.Infof(format, args...)
+}
+
+// RedactHeader returns a copy of the header with the secret values redacted.
+func RedactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+
+	for name := range clone {
+		if isSensitive(name) {
+			clone[name] = []string{redacted}
+		}
+	}
+
+	return clone
+}
+
+// RedactURL returns the URL with the user info, the secret query parameters, and the secret path segments redacted
+// (see redactPath).
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	c := *u
+
+	if c.User != nil {
+		c.User = url.User(redacted)
+	}
+
+	if path, ok := redactPath(c.Path); ok {
+		c.Path = path
+		c.RawPath = ""
+	}
+
+	query := c.Query()
+	if len(query) > 0 {
+		for name := range query {
+			if isSensitive(name) {
+				query[name] = []string{redacted}
+			}
+		}
+
+		c.RawQuery = query.Encode()
+	}
+
+	return c.String()
+}
+
+// redactPath redacts the secrets embedded in the path of a URL (e.g. `/api/<key>/records`, `/token/<token>/update`):
+//   - the segment following a secret name (`/token/<value>`),
+//   - the value of a segment holding a secret name and a value (`/key=<value>`, `/token:<value>`),
+//   - the long random segments (API keys, webhook secrets), but not the hexadecimal IDs and the UUIDs.
+//
+// It reports whether a segment was redacted.
+func redactPath(path string) (string, bool) {
+	segments := strings.Split(path, "/")
+
+	var changed bool
+
+	for i, segment := range segments {
+		switch {
+		case i > 0 && isSecretName(segments[i-1]) && segment != "":
+			segments[i] = redacted
+		case isSecretPair(segment):
+			segments[i] = segment[:strings.IndexAny(segment, "=:")+1] + redacted
+		case isRandom(segment):
+			segments[i] = redacted
+		default:
+			continue
+		}
+
+		changed = true
+	}
+
+	return strings.Join(segments, "/"), changed
+}
+
+// isSecretName reports whether a path segment is the name of a secret (e.g. `token`, `apikey`).
+func isSecretName(segment string) bool {
+	if segment == "" || len(segment) > 20 {
+		return false
+	}
+
+	for _, r := range segment {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return isSensitive(segment)
+}
+
+// isSecretPair reports whether a path segment is a secret name and its value (`key=<value>`, `token:<value>`).
+func isSecretPair(segment string) bool {
+	i := strings.IndexAny(segment, "=:")
+
+	return i > 0 && i < len(segment)-1 && isSecretName(segment[:i])
+}
+
+// minRandomLength is the minimal length of the random segments, shorter than the API keys.
+const minRandomLength = 32
+
+// isRandom reports whether a path segment looks like a random secret:
+// a long segment mixing upper case letters, lower case letters and digits.
+// The hexadecimal IDs and the UUIDs (lower case) are kept.
+func isRandom(segment string) bool {
+	if len(segment) < minRandomLength {
+		return false
+	}
+
+	var upper, lower, digit bool
+
+	for _, r := range segment {
+		switch {
+		case 'A' <= r && r <= 'Z':
+			upper = true
+		case 'a' <= r && r <= 'z':
+			lower = true
+		case '0' <= r && r <= '9':
+			digit = true
+		case r == '-' || r == '_' || r == '.' || r == '~':
+		default:
+			return false
+		}
+	}
+
+	return upper && lower && digit
+}
+
+func formatHeader(header http.Header) string {
+	header = RedactHeader(header)
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strings.Join(header[name], ","))
+	}
+
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, s := range sensitive {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+// Command genregistry generates the registration code of the DNS providers (providers/dns/registry).
+//
+// Every package of providers/dns described by a TOML file is registered under the Code of the description,
+// with the constructors it declares:
+//
+//	NewDNSProvider                                    configured from the environment
+//	ParseConfig, DefaultConfig, NewDNSProviderConfig  configured from YAML
+//	GetYamlTemple                                     hand-written YAML template
+//
+// Usage (from providers/dns/registry, see go:generate):
+//
+//	go run ../../../internal/cmd/genregistry -providers .. -out registry_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// importPrefix is the import path of the providers directory.
+const importPrefix = "lego-toolbox/providers/dns/"
+
+// skippedDirs are the directories of providers/dns holding no provider.
+var skippedDirs = map[string]bool{"internal": true, "registry": true}
+
+var codePattern = regexp.MustCompile(`(?m)^Code\s*=\s*"([^"]+)"`)
+
+// provider describes a provider package.
+type provider struct {
+	// Name is the Code of the TOML description.
+	Name string
+	// Package is the name of the package (the name of its directory).
+	Package string
+
+	FromEnv       bool
+	FromConfig    bool
+	DefaultConfig bool
+	Template      bool
+}
+
+func main() {
+	dir := flag.String("providers", "..", "the providers directory")
+	out := flag.String("out", "registry_gen.go", "the output file")
+	flag.Parse()
+
+	providers, err := scan(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(providers)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*out, src, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// scan returns the providers of the directory, sorted by name.
+func scan(dir string) ([]provider, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []provider
+
+	for _, entry := range entries {
+		if !entry.IsDir() || skippedDirs[entry.Name()] {
+			continue
+		}
+
+		p, ok, err := scanPackage(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		if ok {
+			providers = append(providers, p)
+		}
+	}
+
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+
+	for i := 1; i < len(providers); i++ {
+		if providers[i].Name == providers[i-1].Name {
+			return nil, fmt.Errorf("duplicated provider name %q", providers[i].Name)
+		}
+	}
+
+	return providers, nil
+}
+
+// scanPackage describes the provider package of the directory,
+// the directories without a TOML description are skipped.
+func scanPackage(dir string) (provider, bool, error) {
+	tomls, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil || len(tomls) == 0 {
+		return provider{}, false, err
+	}
+
+	raw, err := os.ReadFile(tomls[0])
+	if err != nil {
+		return provider{}, false, err
+	}
+
+	code := codePattern.FindSubmatch(raw)
+	if code == nil {
+		return provider{}, false, fmt.Errorf("no Code in %s", tomls[0])
+	}
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.SkipObjectResolution)
+	if err != nil {
+		return provider{}, false, err
+	}
+
+	pkg, ok := pkgs[filepath.Base(dir)]
+	if !ok {
+		return provider{}, false, fmt.Errorf("no package %s", filepath.Base(dir))
+	}
+
+	funcs := map[string]bool{}
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = true
+			}
+		}
+	}
+
+	p := provider{
+		Name:          string(code[1]),
+		Package:       pkg.Name,
+		FromEnv:       funcs["NewDNSProvider"],
+		FromConfig:    funcs["ParseConfig"] && funcs["NewDNSProviderConfig"],
+		DefaultConfig: funcs["DefaultConfig"],
+		Template:      funcs["GetYamlTemple"],
+	}
+
+	if p.FromConfig && !p.DefaultConfig {
+		return provider{}, false, fmt.Errorf("%s: ParseConfig without DefaultConfig", p.Name)
+	}
+
+	return p, true, nil
+}
+
+func generate(providers []provider) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	err := registryTemplate.Execute(buf, providers)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+var registryTemplate = template.Must(template.New("registry").Parse(`// Code generated by genregistry; DO NOT EDIT.
+
+package registry
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+{{- range .}}
+	"` + importPrefix + `{{.Package}}"
+{{- end}}
+)
+
+// generated are the providers of the provider packages.
+var generated = []Provider{
+{{- range .}}
+	{
+		Name: "{{.Name}}",
+		{{- if .FromEnv}}
+		NewFromEnv: func() (challenge.Provider, error) { return {{.Package}}.NewDNSProvider() },
+		{{- end}}
+		{{- if .FromConfig}}
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := {{.Package}}.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return {{.Package}}.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig: func(rawConfig []byte) (any, error) { return {{.Package}}.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return {{.Package}}.DefaultConfig() },
+		{{- end}}
+		{{- if .Template}}
+		Template: {{.Package}}.GetYamlTemple,
+		{{- end}}
+	},
+{{- end}}
+}
+`))
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate_upToDate fails when a provider package was added or changed without running go generate.
+func TestGenerate_upToDate(t *testing.T) {
+	providers, err := scan("../../../providers/dns")
+	require.NoError(t, err)
+
+	src, err := generate(providers)
+	require.NoError(t, err)
+
+	current, err := os.ReadFile("../../../providers/dns/registry/registry_gen.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(current), string(src), "run go generate ./providers/dns/registry")
+}
+
+func TestScan(t *testing.T) {
+	providers, err := scan("../../../providers/dns")
+	require.NoError(t, err)
+
+	byName := map[string]provider{}
+	for _, p := range providers {
+		byName[p.Name] = p
+	}
+
+	assert.Equal(t, provider{Name: "acme-dns", Package: "acmedns", FromEnv: true, FromConfig: true, DefaultConfig: true}, byName["acme-dns"])
+	assert.Equal(t, provider{Name: "directadmin", Package: "directadmin", FromEnv: true}, byName["directadmin"])
+	assert.Equal(t, provider{Name: "alidns", Package: "alidns", FromEnv: true, FromConfig: true, DefaultConfig: true, Template: true}, byName["alidns"])
+
+	assert.NotContains(t, byName, "internal")
+	assert.NotContains(t, byName, "registry")
+}
@@ -0,0 +1,119 @@
+// Package ipfamily restricts the connections of the HTTP clients of the DNS providers to an IP family,
+// e.g. for the IPv6-only hosts behind NAT64 when an API endpoint also resolves to IPv4 addresses.
+package ipfamily
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"lego-toolbox/internal/clientdebug"
+)
+
+// Family is the IP family of the connections.
+type Family string
+
+// The IP families.
+const (
+	Auto Family = "auto"
+	IPv4 Family = "ipv4"
+	IPv6 Family = "ipv6"
+)
+
+// Parse parses an IP family, the empty string is Auto.
+func Parse(value string) (Family, error) {
+	switch f := Family(strings.ToLower(value)); f {
+	case "", Auto:
+		return Auto, nil
+	case IPv4, IPv6:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid IP family %q: must be one of auto, ipv4, ipv6", value)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Family) UnmarshalText(text []byte) error {
+	family, err := Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("ipFamily: %w", err)
+	}
+
+	*f = family
+
+	return nil
+}
+
+// network returns the network restricted to the family (tcp4 or tcp6).
+func (f Family) network(network string) string {
+	switch f {
+	case IPv4:
+		return strings.TrimRight(network, "46") + "4"
+	case IPv6:
+		return strings.TrimRight(network, "46") + "6"
+	default:
+		return network
+	}
+}
+
+// Wrap returns a shallow copy of the client dialing only the addresses of the family.
+// The transport of the client is cloned, a nil transport is a clone of http.DefaultTransport,
+// the transport wrapped by a debug transport is cloned.
+// Auto returns the client unchanged.
+func Wrap(client *http.Client, family Family) (*http.Client, error) {
+	if family == "" || family == Auto {
+		return client, nil
+	}
+
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+
+	if debug, ok := c.Transport.(*clientdebug.Transport); ok {
+		base, err := wrapTransport(debug.Base, family)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Transport = &clientdebug.Transport{Base: base, Logf: debug.Logf}
+
+		return &c, nil
+	}
+
+	transport, err := wrapTransport(c.Transport, family)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Transport = transport
+
+	return &c, nil
+}
+
+func wrapTransport(rt http.RoundTripper, family Family) (*http.Transport, error) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("ipFamily: unsupported transport %T", rt)
+	}
+
+	transport := base.Clone()
+
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, family.network(network), addr)
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,114 @@
+package ipfamily
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/internal/clientdebug"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected Family
+	}{
+		{value: "", expected: Auto},
+		{value: "auto", expected: Auto},
+		{value: "ipv4", expected: IPv4},
+		{value: "IPv6", expected: IPv6},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			family, err := Parse(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, family)
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	_, err := Parse("ipv5")
+	require.EqualError(t, err, `invalid IP family "ipv5": must be one of auto, ipv4, ipv6`)
+}
+
+func TestFamily_network(t *testing.T) {
+	assert.Equal(t, "tcp", Auto.network("tcp"))
+	assert.Equal(t, "tcp4", IPv4.network("tcp"))
+	assert.Equal(t, "tcp6", IPv6.network("tcp"))
+	assert.Equal(t, "tcp6", IPv6.network("tcp4"))
+}
+
+func TestWrap_auto(t *testing.T) {
+	client := &http.Client{}
+
+	wrapped, err := Wrap(client, Auto)
+	require.NoError(t, err)
+
+	assert.Same(t, client, wrapped)
+}
+
+func TestWrap_debugTransport(t *testing.T) {
+	client := clientdebug.Wrap(&http.Client{})
+
+	wrapped, err := Wrap(client, IPv4)
+	require.NoError(t, err)
+
+	debug, ok := wrapped.Transport.(*clientdebug.Transport)
+	require.True(t, ok)
+
+	_, ok = debug.Base.(*http.Transport)
+	assert.True(t, ok)
+
+	assert.Nil(t, client.Transport.(*clientdebug.Transport).Base)
+}
+
+func TestWrap_unsupportedTransport(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+
+	_, err := Wrap(client, IPv6)
+	require.ErrorContains(t, err, "ipFamily: unsupported transport")
+}
+
+func TestWrap_dial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	ipv4, err := Wrap(&http.Client{}, IPv4)
+	require.NoError(t, err)
+
+	resp, err := ipv4.Get(server.URL)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	ipv6, err := Wrap(&http.Client{}, IPv6)
+	require.NoError(t, err)
+
+	// the test server only listens on 127.0.0.1.
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	_, err = ipv6.Do(req)
+
+	var opErr *net.OpError
+	require.ErrorAs(t, err, &opErr)
+
+	assert.Equal(t, "tcp6", opErr.Net)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
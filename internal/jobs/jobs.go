@@ -0,0 +1,231 @@
+// Package jobs polls the asynchronous jobs of the DNS APIs (zone sync, change status, ...)
+// until they are done, with backoff, jitter and a bounded number of attempts.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Errors returned by Poll, wrapped with the name of the job.
+var (
+	ErrTimeout     = errors.New("time limit exceeded")
+	ErrMaxAttempts = errors.New("too many attempts")
+	ErrFailed      = errors.New("job failed")
+)
+
+// Status is the status of a job, as reported by the vendor.
+type Status struct {
+	// Done is true when the job is complete.
+	Done bool
+	// Failed is true when the job ended without being applied, Poll stops with ErrFailed.
+	Failed bool
+	// State is the raw state of the vendor (e.g. `pending`), for the reports.
+	State string
+	// Progress describes the progress, e.g. `3/8 nameservers` (optional).
+	Progress string
+}
+
+func (s Status) String() string {
+	if s.Progress == "" {
+		return s.State
+	}
+
+	return s.State + " (" + s.Progress + ")"
+}
+
+// Attempt is a poll of the job, passed to Options.Report.
+type Attempt struct {
+	// Job is Options.Name.
+	Job string
+	// Number is the number of the attempt, from 1.
+	Number int
+	// Elapsed is the time since the start of Poll.
+	Elapsed time.Duration
+	// Status is the status returned by the fetch function.
+	Status Status
+	// Err is the error returned by the fetch function.
+	Err error
+	// Next is the delay until the next attempt, zero for the last attempt.
+	Next time.Duration
+}
+
+// Options configures Poll.
+type Options struct {
+	// Name describes the job in the reports and the errors.
+	Name string
+	// Timeout bounds the whole polling, unbounded when zero (ctx still applies).
+	Timeout time.Duration
+	// Interval is the delay before the second attempt, 2s when zero.
+	Interval time.Duration
+	// Multiplier is the growth of the delay after each attempt, 1 (constant delay) when lower than 1.
+	Multiplier float64
+	// MaxInterval caps the delay, uncapped when zero.
+	MaxInterval time.Duration
+	// Jitter randomizes each delay by ±Jitter (a fraction of the delay, 0 to 1).
+	Jitter float64
+	// MaxAttempts bounds the number of attempts, unbounded when zero.
+	MaxAttempts int
+	// Report is called after each attempt, the attempts are logged when nil.
+	Report func(Attempt)
+}
+
+// DefaultOptions returns the options used by the providers:
+// the delay starts at interval and grows by half after each attempt, up to 4 times interval, with a 20% jitter.
+func DefaultOptions(name string, timeout, interval time.Duration) Options {
+	return Options{
+		Name:        name,
+		Timeout:     timeout,
+		Interval:    interval,
+		Multiplier:  1.5,
+		MaxInterval: 4 * interval,
+		Jitter:      0.2,
+	}
+}
+
+// PermanentError is an error of the fetch function stopping Poll.
+// The other errors are retried, like a job not done yet.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err in a PermanentError.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+// Poll calls fetch until the job is done, fails, or until the timeout, the maximum number of attempts, or ctx is done.
+// It returns the last status of the job.
+func Poll(ctx context.Context, opts Options, fetch func(ctx context.Context) (Status, error)) (Status, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+
+	report := opts.Report
+	if report == nil {
+		report = logAttempt
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.Timeout, ErrTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	delay := opts.Interval
+
+	var (
+		status  Status
+		lastErr error
+	)
+
+	for attempt := 1; ; attempt++ {
+		var err error
+		status, err = fetch(ctx)
+
+		var permanent *PermanentError
+
+		switch {
+		case err == nil && status.Done:
+			report(Attempt{Job: opts.Name, Number: attempt, Elapsed: time.Since(start), Status: status})
+			return status, nil
+
+		case err == nil && status.Failed:
+			report(Attempt{Job: opts.Name, Number: attempt, Elapsed: time.Since(start), Status: status})
+			return status, fmt.Errorf("%s: %w: %s", opts.Name, ErrFailed, status)
+
+		case errors.As(err, &permanent):
+			report(Attempt{Job: opts.Name, Number: attempt, Elapsed: time.Since(start), Status: status, Err: err})
+			return status, fmt.Errorf("%s: %w", opts.Name, permanent.Err)
+		}
+
+		if err != nil {
+			lastErr = err
+		}
+
+		if opts.MaxAttempts > 0 && attempt >= opts.MaxAttempts {
+			report(Attempt{Job: opts.Name, Number: attempt, Elapsed: time.Since(start), Status: status, Err: err})
+			return status, stopError(opts.Name, ErrMaxAttempts, status, lastErr)
+		}
+
+		next := jitter(delay, opts.Jitter)
+
+		report(Attempt{Job: opts.Name, Number: attempt, Elapsed: time.Since(start), Status: status, Err: err, Next: next})
+
+		timer := time.NewTimer(next)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, stopError(opts.Name, context.Cause(ctx), status, lastErr)
+		case <-timer.C:
+		}
+
+		delay = backoff(delay, opts.Multiplier, opts.MaxInterval)
+	}
+}
+
+func stopError(name string, cause error, status Status, lastErr error) error {
+	state := status.String()
+
+	switch {
+	case lastErr != nil:
+		return fmt.Errorf("%s: %w: last error: %w", name, cause, lastErr)
+	case state != "":
+		return fmt.Errorf("%s: %w: last status: %s", name, cause, state)
+	default:
+		return fmt.Errorf("%s: %w", name, cause)
+	}
+}
+
+func backoff(delay time.Duration, multiplier float64, maxInterval time.Duration) time.Duration {
+	if multiplier > 1 {
+		delay = time.Duration(float64(delay) * multiplier)
+	}
+
+	if maxInterval > 0 && delay > maxInterval {
+		delay = maxInterval
+	}
+
+	return delay
+}
+
+func jitter(delay time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return delay
+	}
+
+	fraction = min(fraction, 1)
+
+	// uniform in [delay*(1-fraction), delay*(1+fraction)).
+	return time.Duration(float64(delay) * (1 - fraction + 2*fraction*rand.Float64()))
+}
+
+func logAttempt(a Attempt) {
+	switch {
+	case a.Err != nil:
+		log.Infof("[%s] attempt %d (%s): %v", a.Job, a.Number, a.Elapsed.Round(time.Millisecond), a.Err)
+	case a.Next > 0:
+		log.Infof("[%s] attempt %d (%s): %s, next attempt in %s", a.Job, a.Number, a.Elapsed.Round(time.Millisecond), a.Status, a.Next.Round(time.Millisecond))
+	default:
+		log.Infof("[%s] attempt %d (%s): %s", a.Job, a.Number, a.Elapsed.Round(time.Millisecond), a.Status)
+	}
+}
@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoll(t *testing.T) {
+	var attempts []Attempt
+
+	opts := Options{
+		Name:     "test job",
+		Interval: time.Millisecond,
+		Report:   func(a Attempt) { attempts = append(attempts, a) },
+	}
+
+	calls := 0
+
+	status, err := Poll(context.Background(), opts, func(_ context.Context) (Status, error) {
+		calls++
+
+		switch calls {
+		case 1:
+			return Status{State: "pending"}, nil
+		case 2:
+			return Status{}, errors.New("transient")
+		default:
+			return Status{Done: true, State: "done"}, nil
+		}
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Status{Done: true, State: "done"}, status)
+
+	require.Len(t, attempts, 3)
+	assert.Equal(t, "test job", attempts[0].Job)
+	assert.Equal(t, 1, attempts[0].Number)
+	assert.Equal(t, "pending", attempts[0].Status.State)
+	assert.Equal(t, time.Millisecond, attempts[0].Next)
+	assert.EqualError(t, attempts[1].Err, "transient")
+	assert.Zero(t, attempts[2].Next)
+}
+
+func TestPoll_failed(t *testing.T) {
+	opts := Options{Name: "test job", Interval: time.Millisecond, Report: func(Attempt) {}}
+
+	_, err := Poll(context.Background(), opts, func(_ context.Context) (Status, error) {
+		return Status{Failed: true, State: "error", Progress: "invalid record"}, nil
+	})
+	require.ErrorIs(t, err, ErrFailed)
+	require.EqualError(t, err, "test job: job failed: error (invalid record)")
+}
+
+func TestPoll_permanent(t *testing.T) {
+	opts := Options{Name: "test job", Interval: time.Millisecond, Report: func(Attempt) {}}
+
+	calls := 0
+
+	_, err := Poll(context.Background(), opts, func(_ context.Context) (Status, error) {
+		calls++
+		return Status{}, Permanent(errors.New("not found"))
+	})
+	require.EqualError(t, err, "test job: not found")
+	assert.Equal(t, 1, calls)
+}
+
+func TestPoll_maxAttempts(t *testing.T) {
+	opts := Options{Name: "test job", Interval: time.Millisecond, MaxAttempts: 3, Report: func(Attempt) {}}
+
+	calls := 0
+
+	_, err := Poll(context.Background(), opts, func(_ context.Context) (Status, error) {
+		calls++
+		return Status{State: "pending", Progress: "1/4"}, nil
+	})
+	require.ErrorIs(t, err, ErrMaxAttempts)
+	require.EqualError(t, err, "test job: too many attempts: last status: pending (1/4)")
+	assert.Equal(t, 3, calls)
+}
+
+func TestPoll_timeout(t *testing.T) {
+	opts := Options{Name: "test job", Timeout: 20 * time.Millisecond, Interval: 5 * time.Millisecond, Report: func(Attempt) {}}
+
+	_, err := Poll(context.Background(), opts, func(_ context.Context) (Status, error) {
+		return Status{}, errors.New("not yet")
+	})
+	require.ErrorIs(t, err, ErrTimeout)
+	require.EqualError(t, err, "test job: time limit exceeded: last error: not yet")
+}
+
+func TestPoll_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	opts := Options{Name: "test job", Interval: time.Hour, Report: func(Attempt) {}}
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := Poll(ctx, opts, func(_ context.Context) (Status, error) {
+		return Status{State: "pending"}, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_backoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, 0, 0))
+	assert.Equal(t, 1500*time.Millisecond, backoff(time.Second, 1.5, 0))
+	assert.Equal(t, 4*time.Second, backoff(3*time.Second, 1.5, 4*time.Second))
+}
+
+func Test_jitter(t *testing.T) {
+	assert.Equal(t, time.Second, jitter(time.Second, 0))
+
+	for range 100 {
+		d := jitter(time.Second, 0.2)
+		assert.GreaterOrEqual(t, d, 800*time.Millisecond)
+		assert.Less(t, d, 1200*time.Millisecond)
+	}
+}
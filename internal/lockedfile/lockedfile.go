@@ -0,0 +1,95 @@
+// Package lockedfile serializes the read-modify-write of the state files shared by several processes,
+// e.g. concurrent runs of the CLI.
+//
+// The lock is a lock file created with O_EXCL next to the state file, so it works on every platform.
+// A lock file older than staleAfter is considered left by a crashed process and is removed.
+package lockedfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// staleAfter is the age of a lock file left by a crashed process,
+	// the locks are held for the read-modify-write of a file only.
+	staleAfter = time.Minute
+
+	retryInterval = 10 * time.Millisecond
+)
+
+// Lock takes the lock of the file at path (path.lock), waiting while another process holds it.
+// The returned function releases the lock.
+func Lock(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+
+	err = os.MkdirAll(filepath.Dir(lockPath), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(staleAfter + time.Second)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if fi, err := os.Stat(lockPath); err == nil && time.Since(fi.ModTime()) > staleAfter {
+			_ = os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s: locked by another process", path)
+		}
+
+		time.Sleep(retryInterval)
+	}
+}
+
+// WriteFile writes data to the file at path through a temporary file renamed over it,
+// so the readers never see a partially written file.
+func WriteFile(path string, data []byte, perm fs.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Chmod(perm)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,81 @@
+package lockedfile
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			unlock, err := Lock(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			defer unlock()
+
+			raw, _ := os.ReadFile(path)
+			n, _ := strconv.Atoi(string(raw))
+
+			assert.NoError(t, WriteFile(path, []byte(strconv.Itoa(n+1)), 0o600))
+		}()
+	}
+
+	wg.Wait()
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "20", string(raw))
+
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestLock_stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	require.NoError(t, os.WriteFile(path+".lock", nil, 0o600))
+
+	old := time.Now().Add(-2 * staleAfter)
+	require.NoError(t, os.Chtimes(path+".lock", old, old))
+
+	unlock, err := Lock(path)
+	require.NoError(t, err)
+
+	unlock()
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "state.json")
+
+	require.NoError(t, WriteFile(path, []byte("a"), 0o600))
+	require.NoError(t, WriteFile(path, []byte("b"), 0o600))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(raw))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
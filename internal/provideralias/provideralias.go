@@ -0,0 +1,71 @@
+// Package provideralias holds the alias and deprecated DNS provider names,
+// shared by the YAML factory and the environment factory.
+package provideralias
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// aliases maps the alias names to the provider names.
+var aliases = map[string]string{
+	"domainnameshop": "domeneshop",
+	"fastdns":        "edgedns", // compatibility with v3, must be dropped in v5
+	"linodev4":       "linode",  // compatibility with v3, must be dropped in v5
+}
+
+// deprecated maps the deprecated names (providers or aliases) to their replacement.
+var deprecated = map[string]string{
+	"azure":    "azuredns",
+	"dnspod":   "tencentcloud",
+	"fastdns":  "edgedns",
+	"linodev4": "linode",
+}
+
+// warned holds the deprecated names already reported, the warning is logged once per name.
+var warned sync.Map
+
+// Resolve returns the provider name of the alias, other names are returned unchanged.
+// A deprecation warning is logged the first time a deprecated name is resolved.
+func Resolve(name string) string {
+	if replacement, ok := deprecated[name]; ok {
+		if _, loaded := warned.LoadOrStore(name, struct{}{}); !loaded {
+			log.Warnf("the DNS provider %q is deprecated, use %q instead", name, replacement)
+		}
+	}
+
+	if target, ok := aliases[name]; ok {
+		return target
+	}
+
+	return name
+}
+
+// IsAlias reports whether the name is an alias.
+func IsAlias(name string) bool {
+	_, ok := aliases[name]
+	return ok
+}
+
+// Aliases returns the sorted aliases of the provider.
+func Aliases(name string) []string {
+	var names []string
+
+	for alias, target := range aliases {
+		if target == name {
+			names = append(names, alias)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Replacement returns the replacement of the deprecated name (provider or alias).
+func Replacement(name string) (string, bool) {
+	replacement, ok := deprecated[name]
+	return replacement, ok
+}
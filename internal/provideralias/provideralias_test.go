@@ -0,0 +1,61 @@
+package provideralias
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "fastdns", expected: "edgedns"},
+		{name: "linodev4", expected: "linode"},
+		{name: "domainnameshop", expected: "domeneshop"},
+		{name: "edgedns", expected: "edgedns"},
+		{name: "azure", expected: "azure"},
+		{name: "unknown", expected: "unknown"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Resolve(test.name))
+		})
+	}
+}
+
+func TestResolve_warning(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	logger := log.Logger
+	log.Logger = stdlog.New(buf, "", 0)
+	t.Cleanup(func() { log.Logger = logger })
+
+	warned.Delete("linodev4")
+	t.Cleanup(func() { warned.Delete("linodev4") })
+
+	Resolve("linodev4")
+	Resolve("linodev4")
+	Resolve("domainnameshop")
+
+	assert.Equal(t, "[WARN] the DNS provider \"linodev4\" is deprecated, use \"linode\" instead\n", buf.String())
+}
+
+func TestAliases(t *testing.T) {
+	assert.Equal(t, []string{"fastdns"}, Aliases("edgedns"))
+	assert.Empty(t, Aliases("cloudflare"))
+}
+
+func TestReplacement(t *testing.T) {
+	replacement, ok := Replacement("azure")
+	assert.True(t, ok)
+	assert.Equal(t, "azuredns", replacement)
+
+	_, ok = Replacement("domainnameshop")
+	assert.False(t, ok)
+}
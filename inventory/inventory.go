@@ -0,0 +1,144 @@
+// Package inventory reports the expiry of the stored certificates, across many stores,
+// as structured data and as Prometheus gauges.
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"lego-toolbox/renewal"
+)
+
+// Entry is the expiry data of a stored certificate.
+type Entry struct {
+	// ID identifies the certificate in its store.
+	ID string `json:"id"`
+	// Domain is the main domain of the certificate (the common name, or the first SAN).
+	Domain string `json:"domain"`
+	// SANs are the DNS names of the certificate.
+	SANs []string `json:"sans"`
+	// Issuer is the common name of the issuer (e.g. `R11`).
+	Issuer string `json:"issuer"`
+	// Provider is the identifier of the provider solving the challenges of the certificate.
+	Provider  string    `json:"provider"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	// Error is set when the stored certificate cannot be parsed, the dates are then zero.
+	Error string `json:"error,omitempty"`
+}
+
+// Remaining returns the duration until the expiration of the certificate, negative once expired.
+func (e Entry) Remaining(now time.Time) time.Duration {
+	return e.NotAfter.Sub(now)
+}
+
+// Inventory is the expiry data of the certificates of the stores, sorted by expiration.
+type Inventory struct {
+	Entries []Entry `json:"entries"`
+	// ScannedAt is the time of the scan, the reference of Soon.
+	ScannedAt time.Time `json:"scannedAt"`
+}
+
+// Soon returns the entries expiring within threshold of the scan (the expired ones included), and the invalid entries.
+func (inv *Inventory) Soon(threshold time.Duration) []Entry {
+	var soon []Entry
+
+	for _, entry := range inv.Entries {
+		if entry.Error != "" || entry.Remaining(inv.ScannedAt) < threshold {
+			soon = append(soon, entry)
+		}
+	}
+
+	return soon
+}
+
+// Invalid returns the entries which cannot be parsed.
+func (inv *Inventory) Invalid() []Entry {
+	var invalid []Entry
+
+	for _, entry := range inv.Entries {
+		if entry.Error != "" {
+			invalid = append(invalid, entry)
+		}
+	}
+
+	return invalid
+}
+
+// Scanner scans the certificate stores.
+type Scanner struct {
+	stores []renewal.Store
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+}
+
+// NewScanner returns a Scanner of the stores.
+func NewScanner(stores ...renewal.Store) *Scanner {
+	return &Scanner{stores: stores}
+}
+
+// Scan lists the certificates of every store.
+// A certificate which cannot be parsed is reported with an Error, it does not fail the scan.
+func (s *Scanner) Scan(ctx context.Context) (*Inventory, error) {
+	if len(s.stores) == 0 {
+		return nil, errors.New("inventory: no store")
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	inv := &Inventory{ScannedAt: now()}
+
+	for i, store := range s.stores {
+		certs, err := store.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("inventory: store #%d: %w", i, err)
+		}
+
+		for _, cert := range certs {
+			inv.Entries = append(inv.Entries, newEntry(cert))
+		}
+	}
+
+	sort.SliceStable(inv.Entries, func(i, j int) bool {
+		return inv.Entries[i].NotAfter.Before(inv.Entries[j].NotAfter)
+	})
+
+	return inv, nil
+}
+
+func newEntry(cert renewal.Certificate) Entry {
+	entry := Entry{
+		ID:       cert.ID,
+		SANs:     cert.Domains,
+		Provider: cert.Provider,
+	}
+
+	if len(cert.Domains) > 0 {
+		entry.Domain = cert.Domains[0]
+	}
+
+	x509Cert, err := certcrypto.ParsePEMCertificate(cert.Certificate)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.SANs = x509Cert.DNSNames
+	entry.Issuer = x509Cert.Issuer.CommonName
+	entry.NotBefore = x509Cert.NotBefore
+	entry.NotAfter = x509Cert.NotAfter
+
+	entry.Domain = x509Cert.Subject.CommonName
+	if entry.Domain == "" && len(x509Cert.DNSNames) > 0 {
+		entry.Domain = x509Cert.DNSNames[0]
+	}
+
+	return entry
+}
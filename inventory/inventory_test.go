@@ -0,0 +1,119 @@
+package inventory
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/renewal"
+)
+
+var now = time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+type memoryStore []renewal.Certificate
+
+func (m memoryStore) List(_ context.Context) ([]renewal.Certificate, error) {
+	return m, nil
+}
+
+type failingStore struct{}
+
+func (failingStore) List(_ context.Context) ([]renewal.Certificate, error) {
+	return nil, errors.New("unavailable")
+}
+
+func newCertificate(t *testing.T, id, provider string, notAfter time.Time) renewal.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: id + ".example.com"},
+		Issuer:       pkix.Name{CommonName: "Test CA"},
+		DNSNames:     []string{id + ".example.com", "www." + id + ".example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	parent := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "Test CA"}}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return renewal.Certificate{
+		ID:          id,
+		Domains:     []string{id + ".example.com"},
+		Provider:    provider,
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func newScanner(stores ...renewal.Store) *Scanner {
+	s := NewScanner(stores...)
+	s.Now = func() time.Time { return now }
+
+	return s
+}
+
+func TestScanner_Scan(t *testing.T) {
+	late := newCertificate(t, "late", "cloudflare", now.Add(60*24*time.Hour))
+	early := newCertificate(t, "early", "route53", now.Add(10*24*time.Hour))
+	broken := renewal.Certificate{ID: "broken", Domains: []string{"broken.example.com"}, Provider: "cloudflare", Certificate: []byte("garbage")}
+
+	inv, err := newScanner(memoryStore{late}, memoryStore{early, broken}).Scan(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, now, inv.ScannedAt)
+
+	require.Len(t, inv.Entries, 3)
+
+	// The invalid entry has no expiration, it comes first.
+	assert.Equal(t, "broken", inv.Entries[0].ID)
+	assert.Equal(t, "broken.example.com", inv.Entries[0].Domain)
+	assert.NotEmpty(t, inv.Entries[0].Error)
+
+	assert.Equal(t, Entry{
+		ID:        "early",
+		Domain:    "early.example.com",
+		SANs:      []string{"early.example.com", "www.early.example.com"},
+		Issuer:    "Test CA",
+		Provider:  "route53",
+		NotBefore: now.Add(-80 * 24 * time.Hour),
+		NotAfter:  now.Add(10 * 24 * time.Hour),
+	}, inv.Entries[1])
+
+	assert.Equal(t, "late", inv.Entries[2].ID)
+
+	assert.Equal(t, []string{"broken", "early"}, ids(inv.Soon(30*24*time.Hour)))
+	assert.Equal(t, []string{"broken", "early", "late"}, ids(inv.Soon(90*24*time.Hour)))
+	assert.Equal(t, []string{"broken"}, ids(inv.Invalid()))
+}
+
+func TestScanner_Scan_errors(t *testing.T) {
+	_, err := newScanner().Scan(context.Background())
+	require.EqualError(t, err, "inventory: no store")
+
+	_, err = newScanner(memoryStore{}, failingStore{}).Scan(context.Background())
+	require.EqualError(t, err, "inventory: store #1: unavailable")
+}
+
+func ids(entries []Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.ID)
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+package inventory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Names of the Prometheus gauges.
+const (
+	MetricNotAfter     = "lego_toolbox_certificate_not_after_timestamp_seconds"
+	MetricExpiry       = "lego_toolbox_certificate_expiry_seconds"
+	MetricInvalid      = "lego_toolbox_certificate_invalid"
+	MetricCertificates = "lego_toolbox_certificates"
+)
+
+// prometheusMediaType is the media type of the Prometheus text format.
+const prometheusMediaType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes the gauges of the inventory in the Prometheus text format.
+// The gauges of a certificate are labeled with id, domain, issuer and provider.
+func (inv *Inventory) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, MetricCertificates, "The number of stored certificates.")
+	fmt.Fprintf(bw, "%s %d\n", MetricCertificates, len(inv.Entries))
+
+	writeHeader(bw, MetricNotAfter, "The expiration of the certificate, in seconds since the epoch.")
+	for _, entry := range inv.Entries {
+		if entry.Error == "" {
+			fmt.Fprintf(bw, "%s{%s} %d\n", MetricNotAfter, labels(entry), entry.NotAfter.Unix())
+		}
+	}
+
+	writeHeader(bw, MetricExpiry, "The seconds until the expiration of the certificate, negative once expired.")
+	for _, entry := range inv.Entries {
+		if entry.Error == "" {
+			fmt.Fprintf(bw, "%s{%s} %d\n", MetricExpiry, labels(entry), int64(entry.Remaining(inv.ScannedAt).Seconds()))
+		}
+	}
+
+	writeHeader(bw, MetricInvalid, "Whether the stored certificate cannot be parsed.")
+	for _, entry := range inv.Entries {
+		invalid := 0
+		if entry.Error != "" {
+			invalid = 1
+		}
+
+		fmt.Fprintf(bw, "%s{%s} %d\n", MetricInvalid, labels(entry), invalid)
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP scans the stores and writes the gauges, to be scraped by Prometheus.
+func (s *Scanner) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	inv, err := s.Scan(req.Context())
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", prometheusMediaType)
+
+	_ = inv.WritePrometheus(rw)
+}
+
+func writeHeader(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labels(entry Entry) string {
+	return fmt.Sprintf(`id="%s",domain="%s",issuer="%s",provider="%s"`,
+		labelEscaper.Replace(entry.ID),
+		labelEscaper.Replace(entry.Domain),
+		labelEscaper.Replace(entry.Issuer),
+		labelEscaper.Replace(entry.Provider))
+}
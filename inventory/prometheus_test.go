@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory_WritePrometheus(t *testing.T) {
+	inv := &Inventory{
+		ScannedAt: now,
+		Entries: []Entry{
+			{ID: "broken", Domain: "broken.example.com", Provider: `a"b`, Error: "invalid"},
+			{ID: "example", Domain: "example.com", Issuer: "R11", Provider: "cloudflare", NotAfter: now.Add(time.Hour)},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	err := inv.WritePrometheus(&buf)
+	require.NoError(t, err)
+
+	expected := `# HELP lego_toolbox_certificates The number of stored certificates.
+# TYPE lego_toolbox_certificates gauge
+lego_toolbox_certificates 2
+# HELP lego_toolbox_certificate_not_after_timestamp_seconds The expiration of the certificate, in seconds since the epoch.
+# TYPE lego_toolbox_certificate_not_after_timestamp_seconds gauge
+lego_toolbox_certificate_not_after_timestamp_seconds{id="example",domain="example.com",issuer="R11",provider="cloudflare"} 1717203600
+# HELP lego_toolbox_certificate_expiry_seconds The seconds until the expiration of the certificate, negative once expired.
+# TYPE lego_toolbox_certificate_expiry_seconds gauge
+lego_toolbox_certificate_expiry_seconds{id="example",domain="example.com",issuer="R11",provider="cloudflare"} 3600
+# HELP lego_toolbox_certificate_invalid Whether the stored certificate cannot be parsed.
+# TYPE lego_toolbox_certificate_invalid gauge
+lego_toolbox_certificate_invalid{id="broken",domain="broken.example.com",issuer="",provider="a\"b"} 1
+lego_toolbox_certificate_invalid{id="example",domain="example.com",issuer="R11",provider="cloudflare"} 0
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestScanner_ServeHTTP(t *testing.T) {
+	scanner := newScanner(memoryStore{newCertificate(t, "example", "cloudflare", now.Add(time.Hour))})
+
+	rec := httptest.NewRecorder()
+	scanner.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, prometheusMediaType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `lego_toolbox_certificate_expiry_seconds{id="example",domain="example.example.com",issuer="Test CA",provider="cloudflare"} 3600`)
+
+	rec = httptest.NewRecorder()
+	newScanner(failingStore{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,324 @@
+// Package k8ssource creates DNS providers from configs stored in Kubernetes Secrets and ConfigMaps,
+// and reloads them when the Secret or ConfigMap changes.
+//
+// The key of the config in the Secret (or ConfigMap) is the provider name,
+// optionally suffixed by `.yaml` or `.yml` (e.g. `cloudflare.yaml`).
+package k8ssource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	legotoolbox "lego-toolbox"
+)
+
+// Source is a DNS provider built from a Kubernetes Secret or ConfigMap, reloaded on changes.
+// It implements Sequential when the provider created from the initial config does,
+// like the decorators of legotoolbox.
+type Source interface {
+	challenge.ProviderTimeout
+
+	// Provider returns the current provider.
+	Provider() challenge.Provider
+	// Err returns the error of the last reload, nil if it succeeded.
+	Err() error
+	// Close stops watching the Secret or ConfigMap, and closes the current provider.
+	Close() error
+}
+
+// sequential is implemented by the providers resolving their challenges one at a time.
+type sequential interface {
+	Sequential() time.Duration
+}
+
+// Provider is a DNS provider built from a Kubernetes Secret or ConfigMap, reloaded on changes.
+// The previous provider is kept if a new config is invalid.
+type Provider struct {
+	id   string
+	name string
+
+	mu       sync.RWMutex
+	provider challenge.Provider
+	raw      []byte
+	err      error
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewProviderFromK8sSecret creates a provider from the key of the Secret, using the in-cluster config.
+func NewProviderFromK8sSecret(ns, name, key string) (Source, error) {
+	client, err := inClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProviderFromSecret(context.Background(), client, ns, name, key)
+}
+
+// NewProviderFromK8sConfigMap creates a provider from the key of the ConfigMap, using the in-cluster config.
+// Prefer Secrets for credentials.
+func NewProviderFromK8sConfigMap(ns, name, key string) (Source, error) {
+	client, err := inClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProviderFromConfigMap(context.Background(), client, ns, name, key)
+}
+
+// NewProviderFromSecret creates a provider from the key of the Secret and watches the Secret.
+func NewProviderFromSecret(ctx context.Context, client kubernetes.Interface, ns, name, key string) (Source, error) {
+	secret, err := client.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: secret %s/%s: %w", ns, name, err)
+	}
+
+	p, err := newProvider("secret "+ns+"/"+name+"/"+key, key, secret.Data[key])
+	if err != nil {
+		return nil, err
+	}
+
+	factory := newInformerFactory(client, ns, name)
+	informer := factory.Core().V1().Secrets().Informer()
+
+	_, err = informer.AddEventHandler(p.handler(name, func(obj any) ([]byte, bool) {
+		s, ok := obj.(*corev1.Secret)
+		if !ok {
+			return nil, false
+		}
+
+		data, ok := s.Data[key]
+
+		return data, ok && s.Name == name
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %w", err)
+	}
+
+	factory.Start(p.stop)
+
+	return newSource(p), nil
+}
+
+// NewProviderFromConfigMap creates a provider from the key of the ConfigMap and watches the ConfigMap.
+func NewProviderFromConfigMap(ctx context.Context, client kubernetes.Interface, ns, name, key string) (Source, error) {
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: configmap %s/%s: %w", ns, name, err)
+	}
+
+	p, err := newProvider("configmap "+ns+"/"+name+"/"+key, key, configMapData(cm, key))
+	if err != nil {
+		return nil, err
+	}
+
+	factory := newInformerFactory(client, ns, name)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+
+	_, err = informer.AddEventHandler(p.handler(name, func(obj any) ([]byte, bool) {
+		c, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return nil, false
+		}
+
+		data := configMapData(c, key)
+
+		return data, data != nil && c.Name == name
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %w", err)
+	}
+
+	factory.Start(p.stop)
+
+	return newSource(p), nil
+}
+
+// newSource returns the provider, made sequential when its current provider is sequential.
+func newSource(p *Provider) Source {
+	if _, ok := p.Provider().(sequential); ok {
+		return &sequentialProvider{Source: p}
+	}
+
+	return p
+}
+
+func newProvider(id, key string, raw []byte) (*Provider, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("k8ssource: %s: key not found", id)
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(key, ".yaml"), ".yml")
+
+	provider, err := legotoolbox.NewDNSChallengeProviderByName(name, raw)
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %s: %w", id, err)
+	}
+
+	return &Provider{
+		id:       id,
+		name:     name,
+		provider: provider,
+		raw:      bytes.Clone(raw),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *Provider) Present(domain, token, keyAuth string) error {
+	return p.Provider().Present(domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *Provider) CleanUp(domain, token, keyAuth string) error {
+	return p.Provider().CleanUp(domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *Provider) Timeout() (timeout, interval time.Duration) {
+	if t, ok := p.Provider().(challenge.ProviderTimeout); ok {
+		return t.Timeout()
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+// Provider returns the current provider.
+func (p *Provider) Provider() challenge.Provider {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.provider
+}
+
+// Err returns the error of the last reload, nil if it succeeded.
+func (p *Provider) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.err
+}
+
+// Close stops watching the Secret or ConfigMap, and closes the current provider (see legotoolbox.CloseProvider).
+func (p *Provider) Close() error {
+	var err error
+
+	p.once.Do(func() {
+		close(p.stop)
+		err = legotoolbox.CloseProvider(p.Provider())
+	})
+
+	return err
+}
+
+func (p *Provider) handler(name string, extract func(obj any) ([]byte, bool)) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if raw, ok := extract(obj); ok {
+				p.reload(raw)
+			}
+		},
+		UpdateFunc: func(_, obj any) {
+			if raw, ok := extract(obj); ok {
+				p.reload(raw)
+			}
+		},
+		DeleteFunc: func(_ any) {
+			log.Warnf("k8ssource: %s: %s deleted, keeping the current provider", p.id, name)
+		},
+	}
+}
+
+func (p *Provider) reload(raw []byte) {
+	p.mu.RLock()
+	unchanged := bytes.Equal(p.raw, raw)
+	p.mu.RUnlock()
+
+	if unchanged {
+		return
+	}
+
+	provider, err := legotoolbox.NewDNSChallengeProviderByName(p.name, raw)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.err = fmt.Errorf("k8ssource: %s: %w", p.id, err)
+		log.Warnf("%v, keeping the current provider", p.err)
+
+		return
+	}
+
+	_, wasSequential := p.provider.(sequential)
+	if _, ok := provider.(sequential); ok != wasSequential {
+		log.Warnf("k8ssource: %s: the sequential resolution of the challenges changed, it applies after a restart", p.id)
+	}
+
+	p.provider = provider
+	p.raw = bytes.Clone(raw)
+	p.err = nil
+
+	log.Infof("k8ssource: %s: provider reloaded", p.id)
+}
+
+// sequentialProvider forwards Sequential to the current provider.
+type sequentialProvider struct {
+	Source
+}
+
+// Sequential All DNS challenges for this provider will be resolved sequentially.
+// Returns the interval between each iteration, zero when the reloaded provider is not sequential.
+func (p *sequentialProvider) Sequential() time.Duration {
+	if s, ok := p.Provider().(sequential); ok {
+		return s.Sequential()
+	}
+
+	return 0
+}
+
+func newInformerFactory(client kubernetes.Interface, ns, name string) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(ns),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+}
+
+func configMapData(cm *corev1.ConfigMap, key string) []byte {
+	if data, ok := cm.Data[key]; ok {
+		return []byte(data)
+	}
+
+	return cm.BinaryData[key]
+}
+
+func inClusterClient() (kubernetes.Interface, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,127 @@
+package k8ssource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func timeoutOf(p challenge.Provider) time.Duration {
+	timeout, _ := p.(challenge.ProviderTimeout).Timeout()
+	return timeout
+}
+
+func TestNewProviderFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert", Name: "dns"},
+		Data:       map[string][]byte{"fake.yaml": []byte("propagationTimeout: 1m\n")},
+	}
+
+	client := fake.NewSimpleClientset(secret)
+
+	provider, err := NewProviderFromSecret(context.Background(), client, "cert", "dns", "fake.yaml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	assert.Equal(t, time.Minute, timeoutOf(provider))
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+
+	secret.Data["fake.yaml"] = []byte("propagationTimeout: 2m\n")
+	_, err = client.CoreV1().Secrets("cert").Update(context.Background(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return timeoutOf(provider) == 2*time.Minute }, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, provider.Err())
+
+	secret.Data["fake.yaml"] = []byte("propagationTimeout: soon\n")
+	_, err = client.CoreV1().Secrets("cert").Update(context.Background(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return provider.Err() != nil }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2*time.Minute, timeoutOf(provider))
+}
+
+func TestNewProviderFromSecret_errors(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert", Name: "dns"},
+		Data:       map[string][]byte{"fake": []byte("")},
+	}
+
+	client := fake.NewSimpleClientset(secret)
+
+	_, err := NewProviderFromSecret(context.Background(), client, "cert", "missing", "fake")
+	require.EqualError(t, err, `k8ssource: secret cert/missing: secrets "missing" not found`)
+
+	_, err = NewProviderFromSecret(context.Background(), client, "cert", "dns", "cloudflare")
+	require.EqualError(t, err, "k8ssource: secret cert/dns/cloudflare: key not found")
+}
+
+func TestNewProviderFromConfigMap(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert", Name: "dns"},
+		Data:       map[string]string{"fake.yml": "propagationTimeout: 3m\n"},
+	}
+
+	client := fake.NewSimpleClientset(cm)
+
+	provider, err := NewProviderFromConfigMap(context.Background(), client, "cert", "dns", "fake.yml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	assert.Equal(t, 3*time.Minute, timeoutOf(provider))
+
+	cm.Data["fake.yml"] = "propagationTimeout: 4m\n"
+	_, err = client.CoreV1().ConfigMaps("cert").Update(context.Background(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return timeoutOf(provider) == 4*time.Minute }, 5*time.Second, 10*time.Millisecond)
+}
+
+func sequenceOf(p challenge.Provider) time.Duration {
+	return p.(interface{ Sequential() time.Duration }).Sequential()
+}
+
+func TestNewProviderFromSecret_sequential(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert", Name: "dns"},
+		Data:       map[string][]byte{"fake.yaml": []byte("sequenceInterval: 10s\n")},
+	}
+
+	client := fake.NewSimpleClientset(secret)
+
+	provider, err := NewProviderFromSecret(context.Background(), client, "cert", "dns", "fake.yaml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	assert.Equal(t, 10*time.Second, sequenceOf(provider))
+
+	secret.Data["fake.yaml"] = []byte("sequenceInterval: 20s\n")
+	_, err = client.CoreV1().Secrets("cert").Update(context.Background(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return sequenceOf(provider) == 20*time.Second }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewProviderFromConfigMap_notSequential(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert", Name: "dns"},
+		Data:       map[string]string{"memdns.yaml": "listen: 127.0.0.1:0\n"},
+	}
+
+	client := fake.NewSimpleClientset(cm)
+
+	provider, err := NewProviderFromConfigMap(context.Background(), client, "cert", "dns", "memdns.yaml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	_, ok := provider.(interface{ Sequential() time.Duration })
+	assert.False(t, ok)
+}
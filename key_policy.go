@@ -0,0 +1,151 @@
+package legotoolbox
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"lego-toolbox/kmskey"
+	"lego-toolbox/yamlconfig"
+)
+
+// Key algorithms of KeyPolicy.
+const (
+	KeyAlgorithmECDSA = "ecdsa"
+	KeyAlgorithmRSA   = "rsa"
+)
+
+// KeyPolicy configures the private keys of the certificates obtained by LegoUser.
+// The nil policy generates a new key of CertificateConfig.EncType for each certificate.
+//
+//	algorithm: ecdsa
+//	size: 384
+//	reuse: true
+//
+// With a Signer (a key of AWS KMS or Cloud KMS, see the kmskey package),
+// the private key never leaves the key management service: the CSR is signed by the Signer,
+// and CertificateConfig.PrivateKey stays empty.
+// In YAML, the key is referenced by KMS:
+//
+//	kms:
+//	  aws:
+//	    keyId: alias/acme
+//	    region: eu-west-1
+type KeyPolicy struct {
+	// Algorithm is the key algorithm (ecdsa, rsa), CertificateConfig.EncType is used when empty.
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm"`
+	// Size is the curve size of ECDSA (256, 384) or the modulus size of RSA (2048, 3072, 4096, 8192).
+	// Defaults to 256 for ECDSA and 2048 for RSA.
+	Size int `json:"size,omitempty" yaml:"size"`
+	// Reuse keeps the private key of the previous certificate (CertificateConfig.PrivateKey) on renewal,
+	// a new key is generated for each certificate otherwise (rotation).
+	// The keys pinned by clients (HPKP, DANE TLSA 3 1 1) need the reuse.
+	Reuse bool `json:"reuse,omitempty" yaml:"reuse"`
+	// KMS references the key of a key management service, ParseKeyPolicy resolves it to the Signer.
+	KMS *kmskey.Config `json:"-" yaml:"kms"`
+	// Signer is the external private key, the Algorithm, Size and Reuse are ignored when set.
+	Signer crypto.Signer `json:"-" yaml:"-"`
+}
+
+// ParseKeyPolicy parses the YAML configuration of the key policy.
+// The key referenced by KMS is resolved to the Signer (its public key is fetched).
+func ParseKeyPolicy(rawConfig []byte) (*KeyPolicy, error) {
+	policy := &KeyPolicy{}
+
+	err := yamlconfig.Unmarshal(rawConfig, policy)
+	if err != nil {
+		return nil, fmt.Errorf("key policy: %w", err)
+	}
+
+	if policy.KMS != nil {
+		policy.Signer, err = kmskey.NewSigner(context.Background(), *policy.KMS)
+		if err != nil {
+			return nil, fmt.Errorf("key policy: %w", err)
+		}
+
+		return policy, nil
+	}
+
+	_, err = policy.encType("")
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// encType returns the key type of the policy, fallback when the policy has no algorithm.
+func (p *KeyPolicy) encType(fallback EncType) (EncType, error) {
+	if p == nil || p.Algorithm == "" {
+		if p != nil && p.Size != 0 {
+			return "", errors.New("key policy: size without algorithm")
+		}
+
+		return fallback, nil
+	}
+
+	switch strings.ToLower(p.Algorithm) {
+	case KeyAlgorithmECDSA:
+		switch p.Size {
+		case 0, 256:
+			return EC256, nil
+		case 384:
+			return EC384, nil
+		}
+
+	case KeyAlgorithmRSA:
+		switch p.Size {
+		case 0, 2048:
+			return RSA2048, nil
+		case 3072:
+			return RSA3072, nil
+		case 4096:
+			return RSA4096, nil
+		case 8192:
+			return RSA8192, nil
+		}
+
+	default:
+		return "", fmt.Errorf("key policy: unsupported algorithm %q", p.Algorithm)
+	}
+
+	return "", fmt.Errorf("key policy: unsupported %s size %d", p.Algorithm, p.Size)
+}
+
+// privateKey returns the private key to reuse, nil to generate a new one.
+func (p *KeyPolicy) privateKey(certCfg *CertificateConfig) (crypto.PrivateKey, error) {
+	if p == nil || !p.Reuse || len(certCfg.PrivateKey) == 0 {
+		return nil, nil
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(certCfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("key policy: reuse: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// csrRequest returns the request of a certificate for the CSR signed by the Signer.
+func (p *KeyPolicy) csrRequest(domains []string, mustStaple bool) (certificate.ObtainForCSRRequest, error) {
+	if len(domains) == 0 {
+		return certificate.ObtainForCSRRequest{}, errors.New("key policy: no domain")
+	}
+
+	raw, err := certcrypto.GenerateCSR(p.Signer, domains[0], domains, mustStaple)
+	if err != nil {
+		return certificate.ObtainForCSRRequest{}, fmt.Errorf("key policy: signer: %w", err)
+	}
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	if err != nil {
+		return certificate.ObtainForCSRRequest{}, fmt.Errorf("key policy: signer: %w", err)
+	}
+
+	return certificate.ObtainForCSRRequest{CSR: csr, Bundle: true}, nil
+}
@@ -0,0 +1,141 @@
+package legotoolbox
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyPolicy(t *testing.T) {
+	raw := `
+algorithm: ecdsa
+size: 384
+reuse: true
+`
+
+	policy, err := ParseKeyPolicy([]byte(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, &KeyPolicy{Algorithm: KeyAlgorithmECDSA, Size: 384, Reuse: true}, policy)
+}
+
+func TestParseKeyPolicy_kms(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Amz-Target") != "TrentService.GetPublicKey" {
+			http.Error(rw, `{"__type":"UnknownOperationException"}`, http.StatusBadRequest)
+			return
+		}
+
+		der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		_ = json.NewEncoder(rw).Encode(map[string][]byte{"PublicKey": der})
+	}))
+	t.Cleanup(server.Close)
+
+	raw := fmt.Sprintf(`
+kms:
+  aws:
+    keyId: alias/acme
+    region: eu-west-1
+    endpoint: %s
+`, server.URL)
+
+	policy, err := ParseKeyPolicy([]byte(raw))
+	require.NoError(t, err)
+
+	require.NotNil(t, policy.Signer)
+	assert.True(t, key.PublicKey.Equal(policy.Signer.Public()))
+
+	_, err = ParseKeyPolicy([]byte("kms: {}"))
+	require.EqualError(t, err, "key policy: kmskey: no key")
+}
+
+func TestKeyPolicy_encType(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		policy   *KeyPolicy
+		expected EncType
+		err      string
+	}{
+		{desc: "nil policy", expected: RSA3072},
+		{desc: "no algorithm", policy: &KeyPolicy{Reuse: true}, expected: RSA3072},
+		{desc: "ecdsa default size", policy: &KeyPolicy{Algorithm: "ecdsa"}, expected: EC256},
+		{desc: "ecdsa 384", policy: &KeyPolicy{Algorithm: "ECDSA", Size: 384}, expected: EC384},
+		{desc: "rsa default size", policy: &KeyPolicy{Algorithm: "rsa"}, expected: RSA2048},
+		{desc: "rsa 4096", policy: &KeyPolicy{Algorithm: "rsa", Size: 4096}, expected: RSA4096},
+		{desc: "unsupported algorithm", policy: &KeyPolicy{Algorithm: "ed25519"}, err: `key policy: unsupported algorithm "ed25519"`},
+		{desc: "unsupported size", policy: &KeyPolicy{Algorithm: "ecdsa", Size: 521}, err: "key policy: unsupported ecdsa size 521"},
+		{desc: "size without algorithm", policy: &KeyPolicy{Size: 2048}, err: "key policy: size without algorithm"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			encType, err := test.policy.encType(RSA3072)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, encType)
+		})
+	}
+}
+
+func TestKeyPolicy_privateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	certCfg := &CertificateConfig{PrivateKey: certcrypto.PEMEncode(key)}
+
+	// Rotation: a new key is generated.
+	privateKey, err := (&KeyPolicy{}).privateKey(certCfg)
+	require.NoError(t, err)
+	assert.Nil(t, privateKey)
+
+	privateKey, err = (&KeyPolicy{Reuse: true}).privateKey(certCfg)
+	require.NoError(t, err)
+	assert.Equal(t, key, privateKey)
+
+	// First issuance: no key to reuse.
+	privateKey, err = (&KeyPolicy{Reuse: true}).privateKey(&CertificateConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, privateKey)
+
+	_, err = (&KeyPolicy{Reuse: true}).privateKey(&CertificateConfig{PrivateKey: []byte("garbage")})
+	require.Error(t, err)
+}
+
+func TestKeyPolicy_csrRequest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	policy := &KeyPolicy{Signer: key}
+
+	request, err := policy.csrRequest([]string{"example.com", "*.example.com"}, false)
+	require.NoError(t, err)
+
+	assert.True(t, request.Bundle)
+	assert.Equal(t, "example.com", request.CSR.Subject.CommonName)
+	assert.Equal(t, []string{"example.com", "*.example.com"}, request.CSR.DNSNames)
+	assert.True(t, key.PublicKey.Equal(request.CSR.PublicKey))
+	require.NoError(t, request.CSR.CheckSignature())
+
+	_, err = policy.csrRequest(nil, false)
+	require.EqualError(t, err, "key policy: no domain")
+}
@@ -0,0 +1,231 @@
+package kmskey
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// AWSConfig configures a key of AWS KMS.
+// The key is an asymmetric key (ECC_NIST_P256, ECC_NIST_P384, RSA_2048, ...) with the usage SIGN_VERIFY.
+type AWSConfig struct {
+	// KeyID is the ID, the ARN, or the alias (`alias/acme`) of the key.
+	KeyID string `yaml:"keyId"`
+	// Region is the region of KMS, the region of the shared config when empty.
+	Region string `yaml:"region"`
+	// Endpoint overrides the endpoint of KMS, e.g. for a VPC endpoint.
+	Endpoint string `yaml:"endpoint"`
+	// Timeout bounds the requests, 30s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// AWSSigner signs with a key of AWS KMS.
+type AWSSigner struct {
+	credentials aws.CredentialsProvider
+	region      string
+	endpoint    string
+	keyID       string
+	client      *http.Client
+	signer      *v4.Signer
+	publicKey   crypto.PublicKey
+}
+
+// NewAWSSigner returns the signer of the key, with the default config of the AWS SDK.
+func NewAWSSigner(ctx context.Context, config AWSConfig) (*AWSSigner, error) {
+	var optFns []func(options *awsconfig.LoadOptions) error
+	if config.Region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(config.Region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: aws: %w", err)
+	}
+
+	config.Region = cfg.Region
+
+	return NewAWSSignerWithCredentials(ctx, cfg.Credentials, config)
+}
+
+// NewAWSSignerWithCredentials returns the signer of the key with the credentials.
+// The public key is fetched from KMS.
+func NewAWSSignerWithCredentials(ctx context.Context, credentials aws.CredentialsProvider, config AWSConfig) (*AWSSigner, error) {
+	if credentials == nil {
+		return nil, errors.New("kmskey: aws: the credentials are nil")
+	}
+
+	if config.KeyID == "" {
+		return nil, errors.New("kmskey: aws: missing key ID")
+	}
+
+	if config.Region == "" {
+		return nil, errors.New("kmskey: aws: missing region")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com/", config.Region)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	s := &AWSSigner{
+		credentials: credentials,
+		region:      config.Region,
+		endpoint:    endpoint,
+		keyID:       config.KeyID,
+		client:      &http.Client{Timeout: timeout},
+		signer:      v4.NewSigner(),
+	}
+
+	var result struct {
+		PublicKey []byte `json:"PublicKey"`
+	}
+
+	err := s.do(ctx, "GetPublicKey", map[string]string{"KeyId": s.keyID}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publicKey, err = parsePublicKey(result.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: aws: %w", err)
+	}
+
+	return s, nil
+}
+
+// Public returns the public key.
+func (s *AWSSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with KMS, the request is bounded by the timeout of the config.
+func (s *AWSSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.SignContext(context.Background(), digest, opts)
+}
+
+// SignContext signs the digest with KMS, the request is bounded by ctx.
+func (s *AWSSigner) SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	algorithm, err := awsSigningAlgorithm(s.publicKey, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	request := struct {
+		KeyID            string `json:"KeyId"`
+		Message          []byte `json:"Message"`
+		MessageType      string `json:"MessageType"`
+		SigningAlgorithm string `json:"SigningAlgorithm"`
+	}{
+		KeyID:            s.keyID,
+		Message:          digest,
+		MessageType:      "DIGEST",
+		SigningAlgorithm: algorithm,
+	}
+
+	var result struct {
+		Signature []byte `json:"Signature"`
+	}
+
+	err = s.do(ctx, "Sign", request, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Signature, nil
+}
+
+// do calls the action of the KMS JSON API.
+func (s *AWSSigner) do(ctx context.Context, action string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("kmskey: aws: credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+
+	err = s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "kms", s.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("kmskey: aws: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kmskey: aws: %s: %w", action, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("kmskey: aws: %s: unexpected status code %d: %s", action, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("kmskey: aws: %s: %w", action, err)
+	}
+
+	return nil
+}
+
+// awsSigningAlgorithm returns the KMS signing algorithm of the key and the hash.
+func awsSigningAlgorithm(publicKey crypto.PublicKey, opts crypto.SignerOpts) (string, error) {
+	hashes := map[crypto.Hash]string{
+		crypto.SHA256: "SHA_256",
+		crypto.SHA384: "SHA_384",
+		crypto.SHA512: "SHA_512",
+	}
+
+	hash, ok := hashes[opts.HashFunc()]
+	if !ok {
+		return "", fmt.Errorf("kmskey: aws: unsupported hash %v", opts.HashFunc())
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA_" + hash, nil
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			return "RSASSA_PSS_" + hash, nil
+		}
+
+		return "RSASSA_PKCS1_V1_5_" + hash, nil
+	default:
+		return "", fmt.Errorf("kmskey: aws: unsupported public key %T", publicKey)
+	}
+}
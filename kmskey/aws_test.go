@@ -0,0 +1,103 @@
+package kmskey
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAWSServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body struct {
+			KeyID            string `json:"KeyId"`
+			Message          []byte `json:"Message"`
+			MessageType      string `json:"MessageType"`
+			SigningAlgorithm string `json:"SigningAlgorithm"`
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil || body.KeyID != "alias/acme" {
+			http.Error(rw, `{"__type":"NotFoundException"}`, http.StatusBadRequest)
+			return
+		}
+
+		switch req.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			_ = json.NewEncoder(rw).Encode(map[string][]byte{"PublicKey": der})
+
+		case "TrentService.Sign":
+			if body.MessageType != "DIGEST" || body.SigningAlgorithm != "ECDSA_SHA_256" {
+				http.Error(rw, `{"__type":"ValidationException"}`, http.StatusBadRequest)
+				return
+			}
+
+			signature, _ := ecdsa.SignASN1(rand.Reader, key, body.Message)
+			_ = json.NewEncoder(rw).Encode(map[string][]byte{"Signature": signature})
+
+		default:
+			http.Error(rw, `{"__type":"UnknownOperationException"}`, http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAWSSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newAWSServer(t, key)
+
+	signer, err := NewAWSSignerWithCredentials(context.Background(), credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""), AWSConfig{
+		KeyID:    "alias/acme",
+		Region:   "eu-west-1",
+		Endpoint: server.URL,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, key.PublicKey.Equal(signer.Public()))
+
+	digest := sha256.Sum256([]byte("data"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signature))
+
+	_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA1)
+	require.EqualError(t, err, "kmskey: aws: unsupported hash SHA-1")
+}
+
+func TestNewAWSSignerWithCredentials_errors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newAWSServer(t, key)
+
+	creds := credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")
+
+	_, err = NewAWSSignerWithCredentials(context.Background(), creds, AWSConfig{Region: "eu-west-1"})
+	require.EqualError(t, err, "kmskey: aws: missing key ID")
+
+	_, err = NewAWSSignerWithCredentials(context.Background(), creds, AWSConfig{KeyID: "alias/acme"})
+	require.EqualError(t, err, "kmskey: aws: missing region")
+
+	_, err = NewAWSSignerWithCredentials(context.Background(), creds, AWSConfig{KeyID: "alias/unknown", Region: "eu-west-1", Endpoint: server.URL})
+	require.EqualError(t, err, `kmskey: aws: GetPublicKey: unexpected status code 400: {"__type":"NotFoundException"}`)
+}
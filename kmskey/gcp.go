@@ -0,0 +1,96 @@
+package kmskey
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// GCPConfig configures a key version of Cloud KMS, with the application default credentials.
+type GCPConfig struct {
+	// KeyVersion is the resource name of the key version (see NewGCPSigner).
+	KeyVersion string `yaml:"keyVersion"`
+}
+
+// GCPSigner signs with a key version of Google Cloud KMS.
+// The algorithm of the key version fixes the hash (e.g. EC_SIGN_P256_SHA256, RSA_SIGN_PKCS1_2048_SHA256).
+type GCPSigner struct {
+	service   *cloudkms.Service
+	name      string
+	publicKey crypto.PublicKey
+}
+
+// NewGCPSigner returns the signer of the key version, with the application default credentials when no option is given.
+// The name is the resource name of the key version:
+// `projects/{project}/locations/{location}/keyRings/{keyRing}/cryptoKeys/{key}/cryptoKeyVersions/{version}`.
+func NewGCPSigner(ctx context.Context, name string, opts ...option.ClientOption) (*GCPSigner, error) {
+	if name == "" {
+		return nil, errors.New("kmskey: gcp: missing key version name")
+	}
+
+	service, err := cloudkms.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: gcp: %w", err)
+	}
+
+	result, err := service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.GetPublicKey(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: gcp: GetPublicKey: %w", err)
+	}
+
+	publicKey, err := parsePEMPublicKey([]byte(result.Pem))
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: gcp: %w", err)
+	}
+
+	return &GCPSigner{service: service, name: name, publicKey: publicKey}, nil
+}
+
+// Public returns the public key.
+func (s *GCPSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with Cloud KMS, the request is bounded by a timeout of 30s.
+func (s *GCPSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	return s.SignContext(ctx, digest, opts)
+}
+
+// SignContext signs the digest with Cloud KMS, the request is bounded by ctx.
+func (s *GCPSigner) SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	encoded := base64.StdEncoding.EncodeToString(digest)
+
+	request := &cloudkms.AsymmetricSignRequest{Digest: &cloudkms.Digest{}}
+
+	switch opts.HashFunc() {
+	case crypto.SHA256:
+		request.Digest.Sha256 = encoded
+	case crypto.SHA384:
+		request.Digest.Sha384 = encoded
+	case crypto.SHA512:
+		request.Digest.Sha512 = encoded
+	default:
+		return nil, fmt.Errorf("kmskey: gcp: unsupported hash %v", opts.HashFunc())
+	}
+
+	result, err := s.service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.AsymmetricSign(s.name, request).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: gcp: AsymmetricSign: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(result.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("kmskey: gcp: %w", err)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,80 @@
+package kmskey
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+const keyVersion = "projects/p/locations/global/keyRings/acme/cryptoKeys/certs/cryptoKeyVersions/1"
+
+func TestGCPSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /v1/"+keyVersion+"/publicKey", func(rw http.ResponseWriter, _ *http.Request) {
+		der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		_ = json.NewEncoder(rw).Encode(cloudkms.PublicKey{
+			Pem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		})
+	})
+
+	mux.HandleFunc("POST /v1/"+keyVersion+":asymmetricSign", func(rw http.ResponseWriter, req *http.Request) {
+		var body cloudkms.AsymmetricSignRequest
+
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil || body.Digest == nil || body.Digest.Sha256 == "" {
+			http.Error(rw, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		digest, _ := base64.StdEncoding.DecodeString(body.Digest.Sha256)
+		signature, _ := ecdsa.SignASN1(rand.Reader, key, digest)
+
+		_ = json.NewEncoder(rw).Encode(cloudkms.AsymmetricSignResponse{
+			Signature: base64.StdEncoding.EncodeToString(signature),
+		})
+	})
+
+	signer, err := NewGCPSigner(context.Background(), keyVersion, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	assert.True(t, key.PublicKey.Equal(signer.Public()))
+
+	digest := sha256.Sum256([]byte("data"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signature))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = signer.SignContext(ctx, digest[:], crypto.SHA256)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewGCPSigner_errors(t *testing.T) {
+	_, err := NewGCPSigner(context.Background(), "")
+	require.EqualError(t, err, "kmskey: gcp: missing key version name")
+}
@@ -0,0 +1,63 @@
+// Package kmskey provides the private keys held by a key management service (AWS KMS, Cloud KMS), as crypto.Signer,
+// for the KeyPolicy of the certificates: the private keys never touch the disk.
+package kmskey
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Config references a key of a key management service in a YAML config, one of AWS and GCP is set.
+//
+//	aws:
+//	  keyId: alias/acme
+//	  region: eu-west-1
+type Config struct {
+	AWS *AWSConfig `yaml:"aws"`
+	GCP *GCPConfig `yaml:"gcp"`
+}
+
+// NewSigner returns the signer of the key referenced by the config.
+func NewSigner(ctx context.Context, config Config) (crypto.Signer, error) {
+	switch {
+	case config.AWS != nil && config.GCP != nil:
+		return nil, errors.New("kmskey: both aws and gcp keys")
+	case config.AWS != nil:
+		return NewAWSSigner(ctx, *config.AWS)
+	case config.GCP != nil:
+		return NewGCPSigner(ctx, config.GCP.KeyVersion)
+	default:
+		return nil, errors.New("kmskey: no key")
+	}
+}
+
+// parsePublicKey parses a DER (PKIX) public key, of ECDSA or RSA.
+func parsePublicKey(der []byte) (crypto.PublicKey, error) {
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key %T", publicKey)
+	}
+}
+
+// parsePEMPublicKey parses a PEM (PKIX) public key, of ECDSA or RSA.
+func parsePEMPublicKey(raw []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+
+	return parsePublicKey(block.Bytes)
+}
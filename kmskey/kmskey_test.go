@@ -0,0 +1,40 @@
+package kmskey
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSigner(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newAWSServer(t, key)
+
+	signer, err := NewSigner(context.Background(), Config{
+		AWS: &AWSConfig{KeyID: "alias/acme", Region: "eu-west-1", Endpoint: server.URL},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, key.PublicKey.Equal(signer.Public()))
+}
+
+func TestNewSigner_errors(t *testing.T) {
+	_, err := NewSigner(context.Background(), Config{})
+	require.EqualError(t, err, "kmskey: no key")
+
+	_, err = NewSigner(context.Background(), Config{AWS: &AWSConfig{}, GCP: &GCPConfig{}})
+	require.EqualError(t, err, "kmskey: both aws and gcp keys")
+
+	_, err = NewSigner(context.Background(), Config{GCP: &GCPConfig{}})
+	require.EqualError(t, err, "kmskey: gcp: missing key version name")
+}
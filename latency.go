@@ -0,0 +1,89 @@
+package legotoolbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/propagation"
+	"lego-toolbox/rawrecord"
+)
+
+var (
+	latencyRecorderMu sync.RWMutex
+	latencyRecorder   *propagation.Latency
+)
+
+// SetLatencyRecorder sets the collector of the propagation latencies of the providers created by NewDNSChallengeProviderByName.
+// The latencies are measured when the propagation checker reports to the same collector (see propagation.Checker.WithLatency).
+// A nil collector disables the measures.
+func SetLatencyRecorder(latency *propagation.Latency) {
+	latencyRecorderMu.Lock()
+	defer latencyRecorderMu.Unlock()
+
+	latencyRecorder = latency
+}
+
+// LatencyRecorder returns the collector set with SetLatencyRecorder.
+func LatencyRecorder() *propagation.Latency {
+	latencyRecorderMu.RLock()
+	defer latencyRecorderMu.RUnlock()
+
+	return latencyRecorder
+}
+
+// WithLatency reports the records presented by the provider to the collector, as records of name,
+// the latency is measured when the propagation checker detects them.
+// The timing and sequential behavior of the provider are preserved.
+func WithLatency(provider challenge.Provider, name string, latency *propagation.Latency) challenge.Provider {
+	return decorate(&latencyProvider{Provider: provider, name: name, latency: latency})
+}
+
+type latencyProvider struct {
+	challenge.Provider
+
+	name    string
+	latency *propagation.Latency
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *latencyProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *latencyProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *latencyProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := PresentContext(ctx, p.Provider, domain, token, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	p.latency.Presented(p.name, info.EffectiveFQDN, info.Value)
+
+	return nil
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *latencyProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	p.latency.Forget(info.EffectiveFQDN, info.Value)
+
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *latencyProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *latencyProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
@@ -0,0 +1,60 @@
+package legotoolbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/propagation"
+	"lego-toolbox/rawrecord"
+)
+
+type staticResolver []string
+
+func (r staticResolver) LookupTXT(context.Context, string) ([]string, error) {
+	return r, nil
+}
+
+func TestNewDNSChallengeProviderByName_latencyRecorder(t *testing.T) {
+	latency := propagation.NewLatency(0)
+
+	SetLatencyRecorder(latency)
+	t.Cleanup(func() { SetLatencyRecorder(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("fake@latency", nil)
+	require.NoError(t, err)
+
+	info := rawrecord.GetChallengeInfo("example.com", "keyAuth")
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+
+	checker := propagation.NewChecker(false, staticResolver{info.Value}).WithLatency(latency)
+
+	ok, err := checker.Check(context.Background(), info.EffectiveFQDN, info.Value)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, []string{"fake@latency"}, latency.Providers())
+	assert.Equal(t, uint64(1), latency.Snapshot()["fake@latency"].Count)
+}
+
+func TestWithLatency_cleanUp(t *testing.T) {
+	inner, err := NewDNSChallengeProviderByName("fake", nil)
+	require.NoError(t, err)
+
+	latency := propagation.NewLatency(0)
+	provider := WithLatency(inner, "fake", latency)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	// the record is removed before its detection.
+	info := rawrecord.GetChallengeInfo("example.com", "keyAuth")
+	latency.Detected(info.EffectiveFQDN, info.Value)
+
+	assert.Empty(t, latency.Providers())
+	assert.Same(t, inner, Unwrap(provider))
+}
@@ -0,0 +1,152 @@
+// Package libdnsadapter exposes the toolbox providers through the libdns interfaces,
+// for the libdns consumers (Caddy, certmagic, Traefik plugins, ...).
+//
+// The providers only manage TXT records, and use their own TTL.
+// GetRecords returns the records appended through the adapter, the providers cannot list the zones.
+package libdnsadapter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/libdns/libdns"
+	legotoolbox "lego-toolbox"
+	"lego-toolbox/rawrecord"
+)
+
+// Provider adapts a toolbox provider to the libdns interfaces.
+type Provider struct {
+	provider challenge.Provider
+
+	mu      sync.Mutex
+	records map[string][]libdns.Record
+}
+
+var (
+	_ libdns.RecordGetter   = (*Provider)(nil)
+	_ libdns.RecordAppender = (*Provider)(nil)
+	_ libdns.RecordDeleter  = (*Provider)(nil)
+)
+
+// New creates an adapter for the provider.
+func New(provider challenge.Provider) *Provider {
+	return &Provider{
+		provider: provider,
+		records:  map[string][]libdns.Record{},
+	}
+}
+
+// NewByName creates an adapter for the provider created by legotoolbox.NewDNSChallengeProviderByName.
+func NewByName(name string, rawConfig []byte) (*Provider, error) {
+	provider, err := legotoolbox.NewDNSChallengeProviderByName(name, rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(provider), nil
+}
+
+// GetRecords returns the records appended to the zone through the adapter.
+func (p *Provider) GetRecords(_ context.Context, zone string) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.records[dns01.ToFqdn(zone)]), nil
+}
+
+// AppendRecords creates the TXT records in the zone.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	var appended []libdns.Record
+
+	for _, rec := range recs {
+		if err := ctx.Err(); err != nil {
+			return appended, err
+		}
+
+		domain, token, keyAuth, err := toChallenge(zone, rec)
+		if err != nil {
+			return appended, err
+		}
+
+		err = p.provider.Present(domain, token, keyAuth)
+		if err != nil {
+			return appended, fmt.Errorf("libdnsadapter: append %s: %w", rec.Name, err)
+		}
+
+		rec.ID = token
+		p.track(zone, rec)
+
+		appended = append(appended, rec)
+	}
+
+	return appended, nil
+}
+
+// DeleteRecords deletes the TXT records from the zone.
+func (p *Provider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	var deleted []libdns.Record
+
+	for _, rec := range recs {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		domain, token, keyAuth, err := toChallenge(zone, rec)
+		if err != nil {
+			return deleted, err
+		}
+
+		err = p.provider.CleanUp(domain, token, keyAuth)
+		if err != nil {
+			return deleted, fmt.Errorf("libdnsadapter: delete %s: %w", rec.Name, err)
+		}
+
+		rec.ID = token
+		p.untrack(zone, rec)
+
+		deleted = append(deleted, rec)
+	}
+
+	return deleted, nil
+}
+
+func (p *Provider) track(zone string, rec libdns.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	zone = dns01.ToFqdn(zone)
+	p.records[zone] = append(p.records[zone], rec)
+}
+
+func (p *Provider) untrack(zone string, rec libdns.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	zone = dns01.ToFqdn(zone)
+
+	p.records[zone] = slices.DeleteFunc(p.records[zone], func(r libdns.Record) bool {
+		return r.ID == rec.ID
+	})
+
+	if len(p.records[zone]) == 0 {
+		delete(p.records, zone)
+	}
+}
+
+// toChallenge converts the record to the arguments of Present and CleanUp.
+func toChallenge(zone string, rec libdns.Record) (domain, token, keyAuth string, err error) {
+	if !strings.EqualFold(rec.Type, "TXT") {
+		return "", "", "", fmt.Errorf("libdnsadapter: unsupported record type %q, only TXT records are supported", rec.Type)
+	}
+
+	fqdn := dns01.ToFqdn(libdns.AbsoluteName(rec.Name, dns01.ToFqdn(zone)))
+
+	domain = dns01.UnFqdn(strings.TrimPrefix(fqdn, "_acme-challenge."))
+
+	return domain, fqdn + "/" + rec.Value, rawrecord.KeyAuth(fqdn, rec.Value), nil
+}
@@ -0,0 +1,63 @@
+package libdnsadapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+)
+
+func TestProvider(t *testing.T) {
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	provider := New(inner)
+
+	recs := []libdns.Record{
+		{Type: "TXT", Name: "_acme-challenge.www", Value: "a"},
+		{Type: "TXT", Name: "_acme-challenge.www", Value: "b"},
+	}
+
+	appended, err := provider.AppendRecords(context.Background(), "example.com.", recs)
+	require.NoError(t, err)
+	require.Len(t, appended, 2)
+	assert.NotEmpty(t, appended[0].ID)
+
+	assert.ElementsMatch(t, []string{"a", "b"}, inner.TXT("_acme-challenge.www.example.com."))
+	assert.Equal(t, "www.example.com", inner.CallsOf(fake.CallPresent)[0].Domain)
+
+	got, err := provider.GetRecords(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, appended, got)
+
+	deleted, err := provider.DeleteRecords(context.Background(), "example.com.", recs[:1])
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+
+	assert.Equal(t, []string{"b"}, inner.TXT("_acme-challenge.www.example.com."))
+
+	got, err = provider.GetRecords(context.Background(), "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, appended[1:], got)
+}
+
+func TestProvider_unsupportedType(t *testing.T) {
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	_, err = New(inner).AppendRecords(context.Background(), "example.com.", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+	require.EqualError(t, err, `libdnsadapter: unsupported record type "A", only TXT records are supported`)
+
+	inner.AssertCallCount(t, fake.CallPresent, 0)
+}
+
+func TestNewByName(t *testing.T) {
+	provider, err := NewByName("fake", nil)
+	require.NoError(t, err)
+
+	_, err = provider.AppendRecords(context.Background(), "example.com", []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "v"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package legotoolbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/propagation"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+const defaultNameserversQueryTimeout = 5 * time.Second
+
+// waitNameserversConfig is the part of the provider configs waiting for the authoritative nameservers.
+//
+//	waitNameservers:
+//	  fraction: 0.75
+//	  bootstrap: [1.1.1.1]
+type waitNameserversConfig struct {
+	WaitNameservers *NameserversWait `yaml:"waitNameservers"`
+}
+
+// NameserversWait configures WithNameserversWait.
+type NameserversWait struct {
+	// Fraction is the fraction of the authoritative nameservers which must serve the record, all of them when zero.
+	Fraction float64 `yaml:"fraction"`
+	// Bootstrap are the recursive DNS servers used to discover the nameservers, the system resolvers when empty.
+	Bootstrap []string `yaml:"bootstrap"`
+	// QueryTimeout bounds each query, 5s when zero.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+}
+
+// WithNameserversWait makes Present wait until the authoritative nameservers of the zone serve the record,
+// by querying each of them directly.
+// It generalizes the nameserver sync wait of some providers (e.g. ClouDNS),
+// so that the secondary validations of the CA do not hit a nameserver which is not up to date.
+// The wait is bounded by the propagation timeout of the provider.
+// The timing and sequential behavior of the provider are preserved.
+func WithNameserversWait(provider challenge.Provider, config NameserversWait) challenge.Provider {
+	timeout := config.QueryTimeout
+	if timeout <= 0 {
+		timeout = defaultNameserversQueryTimeout
+	}
+
+	return decorate(&nameserversProvider{
+		Provider: provider,
+		authoritative: &propagation.Authoritative{
+			Bootstrap: config.Bootstrap,
+			Fraction:  config.Fraction,
+			Timeout:   timeout,
+		},
+	})
+}
+
+// withWaitNameservers wraps the provider with WithNameserversWait when `waitNameservers` is set in the provider config.
+func withWaitNameservers(rawConfig []byte, provider challenge.Provider) (challenge.Provider, error) {
+	var config waitNameserversConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("waitNameservers: %w", err)
+	}
+
+	if config.WaitNameservers == nil {
+		return provider, nil
+	}
+
+	return WithNameserversWait(provider, *config.WaitNameservers), nil
+}
+
+type nameserversProvider struct {
+	challenge.Provider
+
+	authoritative *propagation.Authoritative
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge,
+// and waits for the authoritative nameservers to serve it.
+func (p *nameserversProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge,
+// and waits for the authoritative nameservers to serve it, until ctx is done.
+func (p *nameserversProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := PresentContext(ctx, p.Provider, domain, token, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	timeout, interval := providerTimeout(p.Provider)
+
+	_, err = jobs.Poll(ctx, jobs.DefaultOptions("authoritative nameservers of "+info.EffectiveFQDN, timeout, interval), func(ctx context.Context) (jobs.Status, error) {
+		ok, err := p.authoritative.Check(ctx, info.EffectiveFQDN, info.Value)
+
+		return jobs.Status{Done: ok}, err
+	})
+
+	return err
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *nameserversProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *nameserversProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *nameserversProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
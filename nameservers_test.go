@@ -0,0 +1,40 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+)
+
+func TestWithWaitNameservers(t *testing.T) {
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	provider, err := withWaitNameservers([]byte("propagationTimeout: 10s\n"), inner)
+	require.NoError(t, err)
+	assert.Same(t, inner, provider)
+
+	provider, err = withWaitNameservers([]byte("waitNameservers:\n  fraction: 0.5\n  bootstrap: [192.0.2.53]\n  queryTimeout: 2s\n"), inner)
+	require.NoError(t, err)
+
+	s, ok := provider.(*sequentialDecorator)
+	require.True(t, ok)
+
+	wrapped, ok := s.decorator.(*nameserversProvider)
+	require.True(t, ok)
+	assert.Equal(t, 0.5, wrapped.authoritative.Fraction)
+	assert.Equal(t, []string{"192.0.2.53"}, wrapped.authoritative.Bootstrap)
+	assert.Equal(t, 2*time.Second, wrapped.authoritative.Timeout)
+	assert.Same(t, inner, Unwrap(provider))
+
+	_, err = withWaitNameservers([]byte("waitNameservers: true\n"), inner)
+	require.Error(t, err)
+}
+
+func TestParseConfigStrict_waitNameservers(t *testing.T) {
+	_, err := ParseConfigStrict("fake", []byte("waitNameservers:\n  fraction: 1\n"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,178 @@
+// Package nscache caches the results of the name server and zone validations of the DNS providers
+// (the zone of a domain, whether the domain is delegated to the name servers of the provider),
+// so the successive challenges of a domain do not repeat the same API calls and DNS queries.
+//
+// The caches report their hits and misses, aggregated by cache name (see Snapshot and WritePrometheus).
+package nscache
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTTL is the TTL of the entries when the cache is created with a zero TTL.
+const DefaultTTL = 5 * time.Minute
+
+// Stats are the counters of the caches of a name.
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	// Errors counts the failed fetches, the errors are not cached.
+	Errors uint64 `json:"errors"`
+	// Expired counts the entries found past their TTL.
+	Expired uint64 `json:"expired"`
+}
+
+type counters struct {
+	hits, misses, errors, expired atomic.Uint64
+}
+
+func (c *counters) stats() Stats {
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Errors:  c.errors.Load(),
+		Expired: c.expired.Load(),
+	}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*counters)
+)
+
+// countersOf returns the counters shared by the caches of the name.
+func countersOf(name string) *counters {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	c, ok := registry[name]
+	if !ok {
+		c = &counters{}
+		registry[name] = c
+	}
+
+	return c
+}
+
+// Snapshot returns the counters of the caches, by name.
+func Snapshot() map[string]Stats {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	snapshot := make(map[string]Stats, len(registry))
+	for name, c := range registry {
+		snapshot[name] = c.stats()
+	}
+
+	return snapshot
+}
+
+// Names returns the names of the caches, sorted.
+func Names() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache caches values by key for a TTL.
+type Cache[V any] struct {
+	ttl      time.Duration
+	counters *counters
+
+	mu      sync.Mutex
+	entries map[string]entry[V]
+
+	now func() time.Time
+}
+
+// New returns a cache of the entries for ttl (DefaultTTL when zero, no caching when negative).
+// The name identifies the cache in the metrics, usually the provider name,
+// the caches sharing a name (the instances of a provider) share their counters.
+func New[V any](name string, ttl time.Duration) *Cache[V] {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Cache[V]{
+		ttl:      ttl,
+		counters: countersOf(name),
+		entries:  make(map[string]entry[V]),
+		now:      time.Now,
+	}
+}
+
+// Get returns the cached value of the key, or the value returned by fetch, cached on success.
+func (c *Cache[V]) Get(key string, fetch func() (V, error)) (V, error) {
+	if value, ok := c.lookup(key); ok {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		c.counters.errors.Add(1)
+		return value, err
+	}
+
+	if c.ttl > 0 {
+		c.mu.Lock()
+		c.entries[key] = entry[V]{value: value, expiresAt: c.now().Add(c.ttl)}
+		c.mu.Unlock()
+	}
+
+	return value, nil
+}
+
+func (c *Cache[V]) lookup(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		c.counters.expired.Add(1)
+
+		ok = false
+	}
+
+	if !ok {
+		c.counters.misses.Add(1)
+
+		var zero V
+		return zero, false
+	}
+
+	c.counters.hits.Add(1)
+
+	return e.value, true
+}
+
+// Delete removes the entry of the key.
+func (c *Cache[V]) Delete(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// Len returns the number of entries, the expired ones included.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
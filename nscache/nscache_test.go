@@ -0,0 +1,109 @@
+package nscache
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Get(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := New[string](t.Name(), time.Minute)
+	cache.now = func() time.Time { return now }
+
+	var fetches int
+	fetch := func() (string, error) {
+		fetches++
+		return "example.com.", nil
+	}
+
+	for range 3 {
+		zone, err := cache.Get("_acme-challenge.example.com.", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com.", zone)
+	}
+
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(time.Minute)
+
+	_, err := cache.Get("_acme-challenge.example.com.", fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fetches)
+
+	cache.Delete("_acme-challenge.example.com.")
+	assert.Equal(t, 0, cache.Len())
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 2, Expired: 1}, Snapshot()[t.Name()])
+}
+
+func TestCache_Get_error(t *testing.T) {
+	cache := New[int](t.Name(), 0)
+
+	var fetches int
+	fetch := func() (int, error) {
+		fetches++
+		return 0, errors.New("unavailable")
+	}
+
+	for range 2 {
+		_, err := cache.Get("example.com", fetch)
+		require.EqualError(t, err, "unavailable")
+	}
+
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, Stats{Misses: 2, Errors: 2}, Snapshot()[t.Name()])
+}
+
+func TestCache_Get_disabled(t *testing.T) {
+	cache := New[int](t.Name(), -1)
+
+	var fetches int
+	fetch := func() (int, error) {
+		fetches++
+		return 1, nil
+	}
+
+	for range 2 {
+		_, err := cache.Get("example.com", fetch)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestNew_sharedCounters(t *testing.T) {
+	first := New[int](t.Name(), time.Minute)
+	second := New[int](t.Name(), time.Minute)
+
+	fetch := func() (int, error) { return 1, nil }
+
+	_, _ = first.Get("a", fetch)
+	_, _ = second.Get("a", fetch)
+	_, _ = second.Get("a", fetch)
+
+	assert.Equal(t, Stats{Hits: 1, Misses: 2}, Snapshot()[t.Name()])
+}
+
+func TestWritePrometheus(t *testing.T) {
+	cache := New[int]("prometheus", time.Minute)
+
+	_, _ = cache.Get("a", func() (int, error) { return 1, nil })
+	_, _ = cache.Get("a", func() (int, error) { return 1, nil })
+
+	var buf bytes.Buffer
+
+	err := WritePrometheus(&buf)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "# TYPE lego_toolbox_nscache_hits_total counter\n")
+	assert.Contains(t, buf.String(), `lego_toolbox_nscache_hits_total{cache="prometheus"} 1`+"\n")
+	assert.Contains(t, buf.String(), `lego_toolbox_nscache_misses_total{cache="prometheus"} 1`+"\n")
+}
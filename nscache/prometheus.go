@@ -0,0 +1,47 @@
+package nscache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Names of the Prometheus counters.
+const (
+	MetricHits    = "lego_toolbox_nscache_hits_total"
+	MetricMisses  = "lego_toolbox_nscache_misses_total"
+	MetricErrors  = "lego_toolbox_nscache_errors_total"
+	MetricExpired = "lego_toolbox_nscache_expired_total"
+)
+
+// WritePrometheus writes the counters of the caches in the Prometheus text format, labeled with the cache name.
+func WritePrometheus(w io.Writer) error {
+	snapshot := Snapshot()
+	names := Names()
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(Stats) uint64
+	}{
+		{MetricHits, "The lookups answered by the cache.", func(s Stats) uint64 { return s.Hits }},
+		{MetricMisses, "The lookups not answered by the cache.", func(s Stats) uint64 { return s.Misses }},
+		{MetricErrors, "The failed fetches of the missing entries.", func(s Stats) uint64 { return s.Errors }},
+		{MetricExpired, "The entries found past their TTL.", func(s Stats) uint64 { return s.Expired }},
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, metric := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n", metric.name, metric.help, metric.name)
+
+		for _, name := range names {
+			fmt.Fprintf(bw, "%s{cache=\"%s\"} %d\n", metric.name, labelEscaper.Replace(name), metric.value(snapshot[name]))
+		}
+	}
+
+	return bw.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,286 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ErrCAAForbidden is returned when the CAA records do not authorize the issuer.
+var ErrCAAForbidden = errors.New("the CAA records do not authorize the issuer")
+
+// flagCritical is the issuer critical flag of the CAA records.
+const flagCritical = 128
+
+// CAAResolver looks up the CAA records of a name.
+type CAAResolver interface {
+	// LookupCAA returns the CAA records of fqdn, empty if the name has none.
+	LookupCAA(ctx context.Context, fqdn string) ([]*dns.CAA, error)
+}
+
+// DNSCAAResolver queries a DNS server over UDP, falling back to TCP for truncated answers.
+type DNSCAAResolver struct {
+	// Server is the address of the server, the port defaults to 53.
+	// The first system resolver is used when empty.
+	Server  string
+	Timeout time.Duration
+}
+
+// LookupCAA returns the CAA records of fqdn.
+func (r *DNSCAAResolver) LookupCAA(ctx context.Context, fqdn string) ([]*dns.CAA, error) {
+	server := r.Server
+	if server == "" {
+		server = systemNameserver()
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(fqdn), dns.TypeCAA)
+	query.SetEdns0(dns.DefaultMsgSize, false)
+	query.RecursionDesired = true
+
+	client := &dns.Client{Timeout: r.Timeout}
+
+	resp, _, err := client.ExchangeContext(ctx, query, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, query, server)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("dns %s: %w", server, err)
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("dns %s: unexpected response code %s", server, dns.RcodeToString[resp.Rcode])
+	}
+
+	var records []*dns.CAA
+	for _, rr := range resp.Answer {
+		if caa, ok := rr.(*dns.CAA); ok {
+			records = append(records, caa)
+		}
+	}
+
+	return records, nil
+}
+
+func systemNameserver() string {
+	config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(config.Servers) == 0 {
+		return "8.8.8.8:53"
+	}
+
+	return net.JoinHostPort(config.Servers[0], config.Port)
+}
+
+// caaError is a CAA check failure, with the CAA records at fault.
+type caaError struct {
+	// name is the name holding the relevant CAA records.
+	name   string
+	values []string
+	err    error
+}
+
+func (e *caaError) Error() string {
+	if len(e.values) == 0 {
+		return fmt.Sprintf("%s: %v", e.name, e.err)
+	}
+
+	return fmt.Sprintf("%v: %s allows %s", e.err, e.name, strings.Join(quote(e.values), ", "))
+}
+
+func (e *caaError) Unwrap() error {
+	return e.err
+}
+
+// checkCAA checks that the CAA records relevant to the domain authorize one of the issuers (RFC 8659 section 3).
+// The relevant records are those of the closest name (the domain, then its parents) having CAA records.
+func checkCAA(ctx context.Context, resolver CAAResolver, domain string, issuers []string, accountURI string) error {
+	wildcard := strings.HasPrefix(domain, "*.")
+	name := dns.Fqdn(strings.TrimPrefix(domain, "*."))
+
+	for {
+		records, err := resolver.LookupCAA(ctx, name)
+		if err != nil {
+			// The CAs must not issue when the CAA lookup fails.
+			return &caaError{name: name, err: fmt.Errorf("lookup: %w", err)}
+		}
+
+		if len(records) > 0 {
+			return evaluateCAA(name, records, wildcard, issuers, accountURI)
+		}
+
+		index, end := dns.NextLabel(name, 0)
+		if end || name[index:] == "." {
+			return nil
+		}
+
+		name = name[index:]
+	}
+}
+
+// evaluateCAA evaluates the CAA record set of the name.
+func evaluateCAA(name string, records []*dns.CAA, wildcard bool, issuers []string, accountURI string) error {
+	var issue, issueWild []string
+
+	for _, record := range records {
+		switch strings.ToLower(record.Tag) {
+		case "issue":
+			issue = append(issue, record.Value)
+		case "issuewild":
+			issueWild = append(issueWild, record.Value)
+		case "iodef", "issuemail", "issuevmc", "contactemail", "contactphone":
+		default:
+			if record.Flag&flagCritical != 0 {
+				return &caaError{name: name, err: fmt.Errorf("%w: unknown critical tag %q", ErrCAAForbidden, record.Tag)}
+			}
+		}
+	}
+
+	values := issue
+	if wildcard && len(issueWild) > 0 {
+		values = issueWild
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	for _, value := range values {
+		domain, params := parseCAAValue(value)
+
+		if !containsFold(issuers, domain) {
+			continue
+		}
+
+		if uri, ok := params["accounturi"]; ok && accountURI != "" && uri != accountURI {
+			continue
+		}
+
+		return nil
+	}
+
+	return &caaError{name: name, values: values, err: ErrCAAForbidden}
+}
+
+// parseCAAValue parses the value of an issue or issuewild property: `issuer-domain; key=value; ...`.
+func parseCAAValue(value string) (string, map[string]string) {
+	parts := strings.Split(value, ";")
+
+	params := map[string]string{}
+	for _, part := range parts[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if key != "" {
+			params[strings.ToLower(key)] = strings.TrimSpace(val)
+		}
+	}
+
+	return strings.TrimSpace(parts[0]), params
+}
+
+// newCAAIssue returns the issue of a failed CAA check, with the issuer domain close to a CAA value (typo) as hint.
+func newCAAIssue(domain string, err error, issuers []string) Issue {
+	issue := Issue{Check: CheckCAA, Domain: domain, Err: err}
+
+	var caaErr *caaError
+	if !errors.As(err, &caaErr) || !errors.Is(err, ErrCAAForbidden) {
+		return issue
+	}
+
+	forbidAll := true
+
+	for _, value := range caaErr.values {
+		found, _ := parseCAAValue(value)
+		if found != "" {
+			forbidAll = false
+		}
+
+		for _, issuer := range issuers {
+			if typo(found, issuer) {
+				issue.Hint = fmt.Sprintf("%q looks like a typo of %q", found, issuer)
+				return issue
+			}
+		}
+	}
+
+	if forbidAll {
+		issue.Hint = fmt.Sprintf("the CAA records of %s forbid any issuance", caaErr.name)
+		return issue
+	}
+
+	issue.Hint = fmt.Sprintf("add the CAA record `%s CAA 0 issue %q`", caaErr.name, issuers[0])
+
+	return issue
+}
+
+// typo reports whether a differs from b by at most 2 edits, without being equal.
+func typo(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == b || a == "" {
+		return false
+	}
+
+	return distance(a, b) <= 2
+}
+
+// distance is the Damerau-Levenshtein distance (optimal string alignment) of a and b.
+func distance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func quote(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+
+	return quoted
+}
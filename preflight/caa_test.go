@@ -0,0 +1,132 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver map[string][]*dns.CAA
+
+func (f fakeResolver) LookupCAA(_ context.Context, fqdn string) ([]*dns.CAA, error) {
+	if fqdn == "broken.example.com." {
+		return nil, errors.New("SERVFAIL")
+	}
+
+	return f[fqdn], nil
+}
+
+func caa(tag, value string) *dns.CAA {
+	return &dns.CAA{Tag: tag, Value: value}
+}
+
+func TestCheckCAA(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com.":         {caa("issue", "letsencrypt.org"), caa("iodef", "mailto:security@example.com")},
+		"other.example.com.":   {caa("issue", "sectigo.com")},
+		"wild.example.com.":    {caa("issue", "letsencrypt.org"), caa("issuewild", ";")},
+		"account.example.com.": {caa("issue", "letsencrypt.org; accounturi=https://acme.example/acct/1")},
+		"crit.example.com.":    {caa("issue", "letsencrypt.org"), {Flag: flagCritical, Tag: "future"}},
+		"iodef.example.com.":   {caa("iodef", "mailto:security@example.com")},
+	}
+
+	testCases := []struct {
+		desc       string
+		domain     string
+		accountURI string
+		expected   string
+	}{
+		{desc: "authorized", domain: "example.com"},
+		{desc: "inherited from the parent", domain: "www.example.com"},
+		{desc: "wildcard uses issue without issuewild", domain: "*.example.com"},
+		{desc: "no CAA record", domain: "example.org"},
+		{desc: "no issue property", domain: "iodef.example.com"},
+		{desc: "account URI not checked", domain: "account.example.com"},
+		{desc: "matching account URI", domain: "account.example.com", accountURI: "https://acme.example/acct/1"},
+		{
+			desc:     "other issuer",
+			domain:   "www.other.example.com",
+			expected: `the CAA records do not authorize the issuer: other.example.com. allows "sectigo.com"`,
+		},
+		{
+			desc:     "issuewild forbids the wildcards",
+			domain:   "*.wild.example.com",
+			expected: `the CAA records do not authorize the issuer: wild.example.com. allows ";"`,
+		},
+		{
+			desc:       "other account URI",
+			domain:     "account.example.com",
+			accountURI: "https://acme.example/acct/2",
+			expected:   `the CAA records do not authorize the issuer: account.example.com. allows "letsencrypt.org; accounturi=https://acme.example/acct/1"`,
+		},
+		{
+			desc:     "unknown critical tag",
+			domain:   "crit.example.com",
+			expected: `crit.example.com.: the CAA records do not authorize the issuer: unknown critical tag "future"`,
+		},
+		{
+			desc:     "lookup failure",
+			domain:   "broken.example.com",
+			expected: "broken.example.com.: lookup: SERVFAIL",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := checkCAA(context.Background(), resolver, test.domain, DefaultIssuerDomains, test.accountURI)
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestNewCAAIssue_hint(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		records  []*dns.CAA
+		expected string
+	}{
+		{
+			desc:     "typo",
+			records:  []*dns.CAA{caa("issue", "letsencrpyt.org")},
+			expected: `"letsencrpyt.org" looks like a typo of "letsencrypt.org"`,
+		},
+		{
+			desc:     "forbidden",
+			records:  []*dns.CAA{caa("issue", ";")},
+			expected: "the CAA records of example.com. forbid any issuance",
+		},
+		{
+			desc:     "other issuer",
+			records:  []*dns.CAA{caa("issue", "digicert.com")},
+			expected: "add the CAA record `example.com. CAA 0 issue \"letsencrypt.org\"`",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resolver := fakeResolver{"example.com.": test.records}
+
+			err := checkCAA(context.Background(), resolver, "example.com", DefaultIssuerDomains, "")
+			require.ErrorIs(t, err, ErrCAAForbidden)
+
+			issue := newCAAIssue("example.com", err, DefaultIssuerDomains)
+			assert.Equal(t, test.expected, issue.Hint)
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("letsencrypt.org", "letsencrypt.org"))
+	assert.Equal(t, 1, distance("letsencrpyt.org", "letsencrypt.org"))
+	assert.Equal(t, 1, distance("letsencript.org", "letsencrypt.org"))
+	assert.Equal(t, 3, distance("letsencrypt.com", "letsencrypt.org"))
+}
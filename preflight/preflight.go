@@ -0,0 +1,168 @@
+// Package preflight checks that the certificates can be issued before ordering them:
+// the CAA records of the domains authorize the CA, and the DNS provider can write to the zones.
+// An order failing on a CAA typo or on revoked credentials consumes the rate limits of the CA for nothing.
+package preflight
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// DefaultIssuerDomains are the CAA issuer domains of Let's Encrypt.
+var DefaultIssuerDomains = []string{"letsencrypt.org"}
+
+// Check is the name of a preflight check.
+type Check string
+
+// Checks.
+const (
+	CheckCAA  Check = "caa"
+	CheckZone Check = "zone"
+)
+
+// Config is used to configure the preflight checks.
+type Config struct {
+	// IssuerDomains are the CAA issuer domains of the CA (e.g. `letsencrypt.org`, `sectigo.com`), DefaultIssuerDomains when empty.
+	IssuerDomains []string `yaml:"issuerDomains"`
+	// AccountURI is the URI of the ACME account, checked against the `accounturi` CAA parameters (RFC 8657) when set.
+	AccountURI string `yaml:"accountUri"`
+	// Resolver looks up the CAA records, the system resolvers when nil.
+	Resolver CAAResolver `yaml:"-"`
+	// SkipZone skips the write check of the zones with the provider.
+	SkipZone bool `yaml:"skipZone"`
+	// Timeout bounds each DNS query, 10s when zero.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Issue is a failed preflight check.
+type Issue struct {
+	Check  Check
+	Domain string
+	Err    error
+	// Hint suggests a fix, e.g. the CAA typo.
+	Hint string
+}
+
+// Error returns the message of the issue, with the hint.
+func (i Issue) Error() string {
+	msg := fmt.Sprintf("%s: %s: %v", i.Check, i.Domain, i.Err)
+	if i.Hint != "" {
+		msg += " (" + i.Hint + ")"
+	}
+
+	return msg
+}
+
+// Unwrap returns the error of the issue.
+func (i Issue) Unwrap() error {
+	return i.Err
+}
+
+// Report is the result of the preflight checks.
+type Report struct {
+	Issues []Issue
+}
+
+// OK reports whether every check passed.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns the issues joined, nil when every check passed.
+func (r *Report) Err() error {
+	errs := make([]error, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		errs = append(errs, issue)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run checks the domains of a certificate.
+// The CAA records of every domain must authorize the issuer domains,
+// and the provider must be able to present and clean up a challenge record of every domain (unless SkipZone).
+func Run(ctx context.Context, domains []string, provider challenge.Provider, config Config) *Report {
+	issuers := config.IssuerDomains
+	if len(issuers) == 0 {
+		issuers = DefaultIssuerDomains
+	}
+
+	resolver := config.Resolver
+	if resolver == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+
+		resolver = &DNSCAAResolver{Timeout: timeout}
+	}
+
+	report := &Report{}
+
+	for _, domain := range domains {
+		err := checkCAA(ctx, resolver, domain, issuers, config.AccountURI)
+		if err != nil {
+			report.Issues = append(report.Issues, newCAAIssue(domain, err, issuers))
+		}
+	}
+
+	if config.SkipZone || provider == nil {
+		return report
+	}
+
+	checked := map[string]bool{}
+
+	for _, domain := range domains {
+		// The wildcard and its base domain share the challenge record.
+		base := strings.TrimPrefix(domain, "*.")
+		if checked[base] {
+			continue
+		}
+
+		checked[base] = true
+
+		err := checkZone(provider, base)
+		if err != nil {
+			report.Issues = append(report.Issues, Issue{
+				Check:  CheckZone,
+				Domain: base,
+				Err:    err,
+				Hint:   "check the credentials of the provider and their permissions on the zone",
+			})
+		}
+	}
+
+	return report
+}
+
+// checkZone presents and cleans up a challenge record with a random value.
+func checkZone(provider challenge.Provider, domain string) error {
+	random := make([]byte, 16)
+
+	_, err := rand.Read(random)
+	if err != nil {
+		return err
+	}
+
+	token := "preflight-" + hex.EncodeToString(random[:4])
+	keyAuth := token + "." + hex.EncodeToString(random)
+
+	err = provider.Present(domain, token, keyAuth)
+	if err != nil {
+		return fmt.Errorf("present: %w", err)
+	}
+
+	err = provider.CleanUp(domain, token, keyAuth)
+	if err != nil {
+		return fmt.Errorf("cleanup: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	presented []string
+	cleaned   []string
+	err       error
+}
+
+func (f *fakeProvider) Present(domain, _, _ string) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.presented = append(f.presented, domain)
+
+	return nil
+}
+
+func (f *fakeProvider) CleanUp(domain, _, _ string) error {
+	f.cleaned = append(f.cleaned, domain)
+
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	resolver := fakeResolver{"example.com.": {caa("issue", "letsencrypt.org")}}
+	provider := &fakeProvider{}
+
+	report := Run(context.Background(), []string{"example.com", "*.example.com", "www.example.com"}, provider, Config{Resolver: resolver})
+
+	require.True(t, report.OK(), report.Err())
+	require.NoError(t, report.Err())
+
+	// The wildcard shares the challenge record of its base domain.
+	assert.Equal(t, []string{"example.com", "www.example.com"}, provider.presented)
+	assert.Equal(t, provider.presented, provider.cleaned)
+}
+
+func TestRun_issues(t *testing.T) {
+	resolver := fakeResolver{"example.com.": {caa("issue", "letsencrpyt.org")}}
+	provider := &fakeProvider{err: errors.New("403 Forbidden")}
+
+	report := Run(context.Background(), []string{"example.com"}, provider, Config{Resolver: resolver})
+
+	require.False(t, report.OK())
+	require.Len(t, report.Issues, 2)
+
+	assert.Equal(t, CheckCAA, report.Issues[0].Check)
+	require.ErrorIs(t, report.Issues[0], ErrCAAForbidden)
+
+	assert.Equal(t, CheckZone, report.Issues[1].Check)
+
+	expected := `caa: example.com: the CAA records do not authorize the issuer: example.com. allows "letsencrpyt.org" ("letsencrpyt.org" looks like a typo of "letsencrypt.org")
+zone: example.com: present: 403 Forbidden (check the credentials of the provider and their permissions on the zone)`
+	require.EqualError(t, report.Err(), expected)
+}
+
+func TestRun_skipZone(t *testing.T) {
+	resolver := fakeResolver{"example.com.": {caa("issue", "sectigo.com")}}
+	provider := &fakeProvider{}
+
+	report := Run(context.Background(), []string{"example.com"}, provider, Config{
+		IssuerDomains: []string{"sectigo.com"},
+		Resolver:      resolver,
+		SkipZone:      true,
+	})
+
+	require.True(t, report.OK(), report.Err())
+	assert.Empty(t, provider.presented)
+}
@@ -0,0 +1,223 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// maxCNAMEs bounds the CNAME chain followed to find the zone of a record.
+const maxCNAMEs = 10
+
+// Authoritative queries the authoritative nameservers of the zone directly,
+// so the record is reported as propagated only once the secondary nameservers serve it.
+// Let's Encrypt validates from several vantage points, which may reach any of them.
+type Authoritative struct {
+	// Bootstrap are the recursive DNS servers used to discover the nameservers, the system resolvers when empty.
+	Bootstrap []string
+	// Fraction is the fraction of the nameservers which must serve the record, all of them when zero.
+	Fraction float64
+	// Timeout bounds each query.
+	Timeout time.Duration
+	// Port of the nameservers, 53 when empty.
+	Port string
+}
+
+// Nameservers returns the zone of fqdn and the addresses of its authoritative nameservers.
+// The CNAME records of fqdn are followed.
+func (a *Authoritative) Nameservers(ctx context.Context, fqdn string) (zone string, servers []string, err error) {
+	name, err := a.resolveCNAME(ctx, dns.Fqdn(fqdn))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return a.nameservers(ctx, name)
+}
+
+func (a *Authoritative) nameservers(ctx context.Context, name string) (zone string, servers []string, err error) {
+	for _, index := range dns.Split(name) {
+		candidate := name[index:]
+
+		resp, err := a.query(ctx, candidate, dns.TypeNS)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var hosts []string
+		for _, rr := range resp.Answer {
+			if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, candidate) {
+				hosts = append(hosts, ns.Ns)
+			}
+		}
+
+		if len(hosts) == 0 {
+			continue
+		}
+
+		servers, err = a.addresses(ctx, hosts)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return candidate, servers, nil
+	}
+
+	return "", nil, fmt.Errorf("no nameservers found for %s", name)
+}
+
+// Check reports whether enough authoritative nameservers serve value in the TXT records of fqdn.
+// The unreachable nameservers count as not serving the record.
+func (a *Authoritative) Check(ctx context.Context, fqdn, value string) (bool, error) {
+	name, err := a.resolveCNAME(ctx, dns.Fqdn(fqdn))
+	if err != nil {
+		return false, fmt.Errorf("propagation: %w", err)
+	}
+
+	_, servers, err := a.nameservers(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("propagation: %w", err)
+	}
+
+	var errs []error
+	found := 0
+
+	for _, server := range servers {
+		query := newQuery(name)
+		query.RecursionDesired = false
+
+		resp, err := exchange(ctx, server, query, a.Timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		values, err := txtValues(resp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		if slices.Contains(values, value) {
+			found++
+		}
+	}
+
+	fraction := a.Fraction
+	if fraction <= 0 || fraction > 1 {
+		fraction = 1
+	}
+
+	if float64(found) >= fraction*float64(len(servers)) {
+		return true, nil
+	}
+
+	return false, errors.Join(errs...)
+}
+
+func (a *Authoritative) resolveCNAME(ctx context.Context, name string) (string, error) {
+	for range maxCNAMEs {
+		resp, err := a.query(ctx, name, dns.TypeCNAME)
+		if err != nil {
+			return "", err
+		}
+
+		target := ""
+		for _, rr := range resp.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				target = cname.Target
+			}
+		}
+
+		if target == "" {
+			return name, nil
+		}
+
+		name = target
+	}
+
+	return "", fmt.Errorf("too many CNAME records for %s", name)
+}
+
+// addresses resolves the hosts of the nameservers.
+func (a *Authoritative) addresses(ctx context.Context, hosts []string) ([]string, error) {
+	port := a.Port
+	if port == "" {
+		port = "53"
+	}
+
+	var servers []string
+
+	for _, host := range hosts {
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			resp, err := a.query(ctx, host, qtype)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, rr := range resp.Answer {
+				var ip net.IP
+
+				switch rr := rr.(type) {
+				case *dns.A:
+					ip = rr.A
+				case *dns.AAAA:
+					ip = rr.AAAA
+				default:
+					continue
+				}
+
+				server := net.JoinHostPort(ip.String(), port)
+				if !slices.Contains(servers, server) {
+					servers = append(servers, server)
+				}
+			}
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no addresses found for the nameservers %s", strings.Join(hosts, ", "))
+	}
+
+	return servers, nil
+}
+
+// query sends the query to the first bootstrap server answering it.
+func (a *Authoritative) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	bootstrap := a.Bootstrap
+	if len(bootstrap) == 0 {
+		bootstrap = systemNameservers()
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion(name, qtype)
+	query.SetEdns0(dns.DefaultMsgSize, false)
+
+	var errs []error
+
+	for _, server := range bootstrap {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+
+		resp, err := exchange(ctx, server, query, a.Timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			errs = append(errs, fmt.Errorf("dns %s: unexpected response code %s", server, dns.RcodeToString[resp.Rcode]))
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
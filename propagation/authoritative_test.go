@@ -0,0 +1,127 @@
+package propagation
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startZoneServer starts a DNS server acting as the recursive and authoritative server of example.com.
+// ns1.example.com is the server itself, ns2.example.com is unreachable.
+func startZoneServer(t *testing.T) (addr, port string) {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	header := func(name string, rrtype uint16) dns.RR_Header {
+		return dns.RR_Header{Name: name, Rrtype: rrtype, Class: dns.ClassINET}
+	}
+
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		q := req.Question[0]
+		name := strings.ToLower(q.Name)
+
+		switch {
+		case q.Qtype == dns.TypeCNAME && name == "_acme-challenge.alias.org.":
+			m.Answer = append(m.Answer, &dns.CNAME{Hdr: header(q.Name, dns.TypeCNAME), Target: "_acme-challenge.example.com."})
+		case q.Qtype == dns.TypeNS && name == "example.com.":
+			m.Answer = append(m.Answer,
+				&dns.NS{Hdr: header(q.Name, dns.TypeNS), Ns: "ns1.example.com."},
+				&dns.NS{Hdr: header(q.Name, dns.TypeNS), Ns: "ns2.example.com."},
+			)
+		case q.Qtype == dns.TypeA && name == "ns1.example.com.":
+			m.Answer = append(m.Answer, &dns.A{Hdr: header(q.Name, dns.TypeA), A: net.ParseIP("127.0.0.1")})
+		case q.Qtype == dns.TypeA && name == "ns2.example.com.":
+			m.Answer = append(m.Answer, &dns.A{Hdr: header(q.Name, dns.TypeA), A: net.ParseIP("127.0.0.2")})
+		case q.Qtype == dns.TypeTXT && name == "_acme-challenge.example.com.":
+			m.Answer = append(m.Answer, &dns.TXT{Hdr: header(q.Name, dns.TypeTXT), Txt: []string{"value"}})
+		}
+
+		_ = w.WriteMsg(m)
+	})}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	go func() { _ = server.ActivateAndServe() }()
+	<-started
+
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	addr = pc.LocalAddr().String()
+	_, port, _ = net.SplitHostPort(addr)
+
+	return addr, port
+}
+
+func TestAuthoritative_Nameservers(t *testing.T) {
+	addr, port := startZoneServer(t)
+
+	a := &Authoritative{Bootstrap: []string{addr}, Timeout: time.Second, Port: port}
+
+	zone, servers, err := a.Nameservers(context.Background(), "_acme-challenge.alias.org")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", zone)
+	assert.Equal(t, []string{"127.0.0.1:" + port, "127.0.0.2:" + port}, servers)
+
+	_, _, err = a.Nameservers(context.Background(), "_acme-challenge.example.net.")
+	require.EqualError(t, err, "no nameservers found for _acme-challenge.example.net.")
+}
+
+func TestAuthoritative_Check(t *testing.T) {
+	addr, port := startZoneServer(t)
+
+	testCases := []struct {
+		desc     string
+		fraction float64
+		fqdn     string
+		value    string
+		expected bool
+	}{
+		{
+			desc:     "half of the nameservers",
+			fraction: 0.5,
+			fqdn:     "_acme-challenge.example.com.",
+			value:    "value",
+			expected: true,
+		},
+		{
+			desc:     "through a CNAME",
+			fraction: 0.5,
+			fqdn:     "_acme-challenge.alias.org.",
+			value:    "value",
+			expected: true,
+		},
+		{
+			desc:  "all the nameservers",
+			fqdn:  "_acme-challenge.example.com.",
+			value: "value",
+		},
+		{
+			desc:     "other value",
+			fraction: 0.5,
+			fqdn:     "_acme-challenge.example.com.",
+			value:    "other",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			a := &Authoritative{Bootstrap: []string{addr}, Fraction: test.fraction, Timeout: 200 * time.Millisecond, Port: port}
+
+			ok, _ := a.Check(context.Background(), test.fqdn, test.value)
+			assert.Equal(t, test.expected, ok)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package propagation
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultLatencyWindow is the number of latest samples of a provider the percentiles are computed from.
+const DefaultLatencyWindow = 256
+
+// pendingTTL bounds the wait of a presented record for its detection,
+// the records never checked (e.g. a provider without verifier) are forgotten after it.
+const pendingTTL = 24 * time.Hour
+
+// latencyBuckets are the upper bounds of the buckets of the histograms.
+var latencyBuckets = []time.Duration{
+	time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute,
+}
+
+// LatencyStats are the propagation latencies of a provider:
+// the time between the completion of Present and the detection of the record by the Checker.
+type LatencyStats struct {
+	// Count is the number of samples since the start, Sum their total.
+	Count uint64        `json:"count"`
+	Sum   time.Duration `json:"sum"`
+	// The percentiles and the maximum of the latest samples (see DefaultLatencyWindow).
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+	// Buckets are the cumulative counts of the samples lower or equal to each bound of LatencyBuckets.
+	Buckets []uint64 `json:"buckets"`
+}
+
+// LatencyBuckets returns the upper bounds of the buckets of LatencyStats.
+func LatencyBuckets() []time.Duration {
+	return slices.Clone(latencyBuckets)
+}
+
+type pendingRecord struct {
+	provider    string
+	presentedAt time.Time
+}
+
+type latencySamples struct {
+	window  []time.Duration
+	next    int
+	count   uint64
+	sum     time.Duration
+	buckets []uint64
+}
+
+// Latency collects the propagation latencies of the providers.
+// The providers report the records they present (Presented), the Checker reports the records it detects (Detected).
+type Latency struct {
+	mu      sync.Mutex
+	size    int
+	pending map[string]pendingRecord
+	samples map[string]*latencySamples
+
+	now func() time.Time
+}
+
+// NewLatency creates a Latency keeping the latest window samples of each provider, DefaultLatencyWindow when zero.
+func NewLatency(window int) *Latency {
+	if window <= 0 {
+		window = DefaultLatencyWindow
+	}
+
+	return &Latency{
+		size:    window,
+		pending: make(map[string]pendingRecord),
+		samples: make(map[string]*latencySamples),
+		now:     time.Now,
+	}
+}
+
+// Presented records the completion of the Present call of the provider for the record.
+func (l *Latency) Presented(provider, fqdn, value string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for key, record := range l.pending {
+		if now.Sub(record.presentedAt) > pendingTTL {
+			delete(l.pending, key)
+		}
+	}
+
+	l.pending[pendingKey(fqdn, value)] = pendingRecord{provider: provider, presentedAt: now}
+}
+
+// Detected records the detection of the record, the latency is added to the samples of the provider which presented it.
+// The records not presented, or already detected, are ignored.
+func (l *Latency) Detected(fqdn, value string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := pendingKey(fqdn, value)
+
+	record, ok := l.pending[key]
+	if !ok {
+		return
+	}
+
+	delete(l.pending, key)
+
+	l.add(record.provider, l.now().Sub(record.presentedAt))
+}
+
+// Forget forgets the record, e.g. after its cleanup.
+func (l *Latency) Forget(fqdn, value string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, pendingKey(fqdn, value))
+}
+
+// Observe adds a latency to the samples of the provider.
+func (l *Latency) Observe(provider string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.add(provider, latency)
+}
+
+func (l *Latency) add(provider string, latency time.Duration) {
+	s, ok := l.samples[provider]
+	if !ok {
+		s = &latencySamples{buckets: make([]uint64, len(latencyBuckets))}
+		l.samples[provider] = s
+	}
+
+	if len(s.window) < l.size {
+		s.window = append(s.window, latency)
+	} else {
+		s.window[s.next] = latency
+		s.next = (s.next + 1) % l.size
+	}
+
+	s.count++
+	s.sum += latency
+
+	for i, bound := range latencyBuckets {
+		if latency <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// Snapshot returns the latencies, by provider.
+func (l *Latency) Snapshot() map[string]LatencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	snapshot := make(map[string]LatencyStats, len(l.samples))
+
+	for provider, s := range l.samples {
+		sorted := slices.Clone(s.window)
+		slices.Sort(sorted)
+
+		snapshot[provider] = LatencyStats{
+			Count:   s.count,
+			Sum:     s.sum,
+			P50:     percentile(sorted, 0.5),
+			P90:     percentile(sorted, 0.9),
+			P95:     percentile(sorted, 0.95),
+			P99:     percentile(sorted, 0.99),
+			Max:     sorted[len(sorted)-1],
+			Buckets: slices.Clone(s.buckets),
+		}
+	}
+
+	return snapshot
+}
+
+// Providers returns the names of the providers with samples, sorted.
+func (l *Latency) Providers() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, 0, len(l.samples))
+	for name := range l.samples {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func pendingKey(fqdn, value string) string {
+	return strings.ToLower(dns.Fqdn(fqdn)) + " " + value
+}
@@ -0,0 +1,153 @@
+package propagation
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLatency(t *testing.T, window int) (*Latency, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := NewLatency(window)
+	l.now = func() time.Time { return now }
+
+	return l, &now
+}
+
+func TestLatency(t *testing.T) {
+	l, now := newTestLatency(t, 0)
+
+	l.Presented("fake", "_acme-challenge.example.com.", "v")
+	l.Presented("fake", "_acme-challenge.example.org.", "w")
+	l.Presented("other", "_acme-challenge.example.net.", "x")
+
+	*now = now.Add(3 * time.Second)
+
+	// the names are compared case-insensitively, with or without the trailing dot.
+	l.Detected("_ACME-challenge.example.com", "v")
+	// already detected.
+	l.Detected("_acme-challenge.example.com.", "v")
+	// not presented.
+	l.Detected("_acme-challenge.example.com.", "z")
+
+	l.Forget("_acme-challenge.example.net.", "x")
+
+	*now = now.Add(4 * time.Second)
+
+	l.Detected("_acme-challenge.example.org.", "w")
+	l.Detected("_acme-challenge.example.net.", "x")
+
+	assert.Equal(t, []string{"fake"}, l.Providers())
+
+	stats := l.Snapshot()["fake"]
+	assert.Equal(t, uint64(2), stats.Count)
+	assert.Equal(t, 10*time.Second, stats.Sum)
+	assert.Equal(t, 3*time.Second, stats.P50)
+	assert.Equal(t, 7*time.Second, stats.P90)
+	assert.Equal(t, 7*time.Second, stats.Max)
+	assert.Equal(t, []uint64{0, 0, 1, 2, 2, 2, 2, 2, 2, 2}, stats.Buckets)
+}
+
+func TestLatency_window(t *testing.T) {
+	l, _ := newTestLatency(t, 4)
+
+	for i := 1; i <= 10; i++ {
+		l.Observe("fake", time.Duration(i)*time.Minute)
+	}
+
+	stats := l.Snapshot()["fake"]
+
+	// the percentiles are computed from the latest samples, the count from all of them.
+	assert.Equal(t, uint64(10), stats.Count)
+	assert.Equal(t, 55*time.Minute, stats.Sum)
+	assert.Equal(t, 8*time.Minute, stats.P50)
+	assert.Equal(t, 10*time.Minute, stats.P99)
+	assert.Equal(t, 10*time.Minute, stats.Max)
+}
+
+func TestLatency_pendingTTL(t *testing.T) {
+	l, now := newTestLatency(t, 0)
+
+	l.Presented("fake", "_acme-challenge.example.com.", "v")
+
+	*now = now.Add(pendingTTL + time.Second)
+
+	l.Presented("fake", "_acme-challenge.example.org.", "w")
+	l.Detected("_acme-challenge.example.com.", "v")
+
+	assert.Empty(t, l.Snapshot())
+}
+
+func TestChecker_WithLatency(t *testing.T) {
+	l, now := newTestLatency(t, 0)
+
+	l.Presented("fake", "_acme-challenge.example.com.", "v")
+
+	*now = now.Add(2 * time.Second)
+
+	checker := NewChecker(false, stubResolver{values: []string{"v"}}).WithLatency(l)
+
+	ok, err := checker.Check(context.Background(), "_acme-challenge.example.com.", "v")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	assert.Equal(t, 2*time.Second, l.Snapshot()["fake"].Max)
+}
+
+func TestLatency_WritePrometheus(t *testing.T) {
+	l, _ := newTestLatency(t, 0)
+
+	l.Observe(`fake@"a"`, 1500*time.Millisecond)
+	l.Observe(`fake@"a"`, 90*time.Second)
+
+	buf := new(bytes.Buffer)
+
+	err := l.WritePrometheus(buf)
+	require.NoError(t, err)
+
+	expected := `# HELP lego_toolbox_propagation_latency_seconds The time between the creation of the record and its detection.
+# TYPE lego_toolbox_propagation_latency_seconds histogram
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="1"} 0
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="2"} 1
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="5"} 1
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="10"} 1
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="30"} 1
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="60"} 1
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="120"} 2
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="300"} 2
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="600"} 2
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="1800"} 2
+lego_toolbox_propagation_latency_seconds_bucket{provider="fake@\"a\"",le="+Inf"} 2
+lego_toolbox_propagation_latency_seconds_sum{provider="fake@\"a\""} 91.5
+lego_toolbox_propagation_latency_seconds_count{provider="fake@\"a\""} 2
+# HELP lego_toolbox_propagation_latency_quantile_seconds The percentiles of the latest propagation latencies.
+# TYPE lego_toolbox_propagation_latency_quantile_seconds gauge
+lego_toolbox_propagation_latency_quantile_seconds{provider="fake@\"a\"",quantile="0.5"} 1.5
+lego_toolbox_propagation_latency_quantile_seconds{provider="fake@\"a\"",quantile="0.9"} 90
+lego_toolbox_propagation_latency_quantile_seconds{provider="fake@\"a\"",quantile="0.95"} 90
+lego_toolbox_propagation_latency_quantile_seconds{provider="fake@\"a\"",quantile="0.99"} 90
+lego_toolbox_propagation_latency_quantile_seconds{provider="fake@\"a\"",quantile="1"} 90
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestLatency_ServeHTTP(t *testing.T) {
+	l, _ := newTestLatency(t, 0)
+
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, prometheusMediaType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "# TYPE "+MetricLatency+" histogram")
+}
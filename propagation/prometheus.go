@@ -0,0 +1,78 @@
+package propagation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Names of the Prometheus metrics.
+const (
+	MetricLatency         = "lego_toolbox_propagation_latency_seconds"
+	MetricLatencyQuantile = "lego_toolbox_propagation_latency_quantile_seconds"
+)
+
+// prometheusMediaType is the media type of the Prometheus text format.
+const prometheusMediaType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes the latencies in the Prometheus text format, labeled with the provider name:
+// a histogram of all the samples, and a gauge of the percentiles of the latest samples.
+func (l *Latency) WritePrometheus(w io.Writer) error {
+	snapshot := l.Snapshot()
+	providers := l.Providers()
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# HELP %s The time between the creation of the record and its detection.\n# TYPE %s histogram\n", MetricLatency, MetricLatency)
+
+	for _, provider := range providers {
+		stats := snapshot[provider]
+		label := labelEscaper.Replace(provider)
+
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(bw, "%s_bucket{provider=\"%s\",le=\"%s\"} %d\n", MetricLatency, label, formatSeconds(bound.Seconds()), stats.Buckets[i])
+		}
+
+		fmt.Fprintf(bw, "%s_bucket{provider=\"%s\",le=\"+Inf\"} %d\n", MetricLatency, label, stats.Count)
+		fmt.Fprintf(bw, "%s_sum{provider=\"%s\"} %s\n", MetricLatency, label, formatSeconds(stats.Sum.Seconds()))
+		fmt.Fprintf(bw, "%s_count{provider=\"%s\"} %d\n", MetricLatency, label, stats.Count)
+	}
+
+	fmt.Fprintf(bw, "# HELP %s The percentiles of the latest propagation latencies.\n# TYPE %s gauge\n", MetricLatencyQuantile, MetricLatencyQuantile)
+
+	for _, provider := range providers {
+		stats := snapshot[provider]
+		label := labelEscaper.Replace(provider)
+
+		for _, q := range []struct {
+			quantile string
+			seconds  float64
+		}{
+			{"0.5", stats.P50.Seconds()},
+			{"0.9", stats.P90.Seconds()},
+			{"0.95", stats.P95.Seconds()},
+			{"0.99", stats.P99.Seconds()},
+			{"1", stats.Max.Seconds()},
+		} {
+			fmt.Fprintf(bw, "%s{provider=\"%s\",quantile=\"%s\"} %s\n", MetricLatencyQuantile, label, q.quantile, formatSeconds(q.seconds))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP writes the latencies, to be scraped by Prometheus.
+func (l *Latency) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", prometheusMediaType)
+
+	_ = l.WritePrometheus(rw)
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
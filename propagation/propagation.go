@@ -0,0 +1,212 @@
+// Package propagation verifies the visibility of TXT records,
+// through plain DNS or DNS-over-HTTPS (RFC 8484) on networks blocking outbound DNS.
+//
+// A Checker is usable standalone (Check, Wait) or as the lego pre-check (PreCheck).
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"lego-toolbox/yamlconfig"
+)
+
+// Well-known DNS-over-HTTPS endpoints.
+const (
+	GoogleDoH     = "https://dns.google/dns-query"
+	CloudflareDoH = "https://cloudflare-dns.com/dns-query"
+)
+
+// Server aliases of the well-known DNS-over-HTTPS endpoints.
+var aliases = map[string]string{
+	"google":     GoogleDoH,
+	"cloudflare": CloudflareDoH,
+}
+
+// fallbackNameservers are used when the system resolvers are unknown.
+var fallbackNameservers = []string{"8.8.8.8:53", "8.8.4.4:53"}
+
+// Resolver looks up the TXT records of a name.
+type Resolver interface {
+	// LookupTXT returns the TXT values of fqdn, empty if the name does not exist.
+	LookupTXT(ctx context.Context, fqdn string) ([]string, error)
+}
+
+// Config is used to configure the creation of the Checker.
+type Config struct {
+	// Servers are the resolvers to query:
+	// "google" and "cloudflare" are DNS-over-HTTPS aliases, https:// URLs are DNS-over-HTTPS endpoints,
+	// anything else is a plain DNS server (host[:port]).
+	// The system resolvers are used when empty.
+	Servers []string `yaml:"servers"`
+	// RequireAll requires the record to be visible on every server, instead of any of them.
+	RequireAll bool `yaml:"requireAll"`
+	// Timeout bounds each query.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// DefaultConfig returns a default configuration for the Checker.
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout: 10 * time.Second,
+	}
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Checker checks the visibility of TXT records on a set of resolvers.
+type Checker struct {
+	resolvers  []Resolver
+	requireAll bool
+	latency    *Latency
+}
+
+// New returns a Checker configured for the servers of the config.
+func New(config *Config) (*Checker, error) {
+	if config == nil {
+		return nil, errors.New("propagation: the configuration is nil")
+	}
+
+	servers := config.Servers
+	if len(servers) == 0 {
+		servers = systemNameservers()
+	}
+
+	resolvers := make([]Resolver, 0, len(servers))
+	for _, server := range servers {
+		resolver, err := newResolver(server, config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("propagation: %w", err)
+		}
+
+		resolvers = append(resolvers, resolver)
+	}
+
+	return NewChecker(config.RequireAll, resolvers...), nil
+}
+
+// NewChecker returns a Checker querying the resolvers.
+func NewChecker(requireAll bool, resolvers ...Resolver) *Checker {
+	return &Checker{resolvers: resolvers, requireAll: requireAll}
+}
+
+// WithLatency reports the records found visible to the latency collector,
+// to measure the propagation latency of the providers reporting the records they present.
+func (c *Checker) WithLatency(latency *Latency) *Checker {
+	c.latency = latency
+
+	return c
+}
+
+// Check reports whether value is visible in the TXT records of fqdn.
+// The errors of the resolvers are returned when the record is not visible.
+func (c *Checker) Check(ctx context.Context, fqdn, value string) (bool, error) {
+	ok, err := c.check(ctx, fqdn, value)
+	if ok && c.latency != nil {
+		c.latency.Detected(fqdn, value)
+	}
+
+	return ok, err
+}
+
+func (c *Checker) check(ctx context.Context, fqdn, value string) (bool, error) {
+	var errs []error
+	found := 0
+
+	for _, resolver := range c.resolvers {
+		values, err := resolver.LookupTXT(ctx, fqdn)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if slices.Contains(values, value) {
+			found++
+		}
+	}
+
+	if c.requireAll {
+		return found == len(c.resolvers) && found > 0, errors.Join(errs...)
+	}
+
+	if found > 0 {
+		return true, nil
+	}
+
+	return false, errors.Join(errs...)
+}
+
+// Wait checks the record every interval until it is visible or ctx is done.
+func (c *Checker) Wait(ctx context.Context, fqdn, value string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := c.Check(ctx, fqdn, value)
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("propagation: %s: %w: last error: %w", fqdn, ctx.Err(), err)
+			}
+
+			return fmt.Errorf("propagation: %s: %w", fqdn, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// PreCheck returns the lego option replacing the propagation pre-check with the Checker.
+// lego retries the check until the timeout of the provider.
+func (c *Checker) PreCheck() dns01.ChallengeOption {
+	return dns01.WrapPreCheck(func(_, fqdn, value string, _ dns01.PreCheckFunc) (bool, error) {
+		return c.Check(context.Background(), fqdn, value)
+	})
+}
+
+func newResolver(server string, timeout time.Duration) (Resolver, error) {
+	if endpoint, ok := aliases[strings.ToLower(server)]; ok {
+		server = endpoint
+	}
+
+	if strings.HasPrefix(server, "https://") {
+		return &DoHResolver{Endpoint: server, Timeout: timeout}, nil
+	}
+
+	if strings.Contains(server, "://") {
+		return nil, fmt.Errorf("unsupported server %q", server)
+	}
+
+	return &DNSResolver{Server: server, Timeout: timeout}, nil
+}
+
+func systemNameservers() []string {
+	config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(config.Servers) == 0 {
+		return fallbackNameservers
+	}
+
+	servers := make([]string, 0, len(config.Servers))
+	for _, server := range config.Servers {
+		servers = append(servers, server+":"+config.Port)
+	}
+
+	return servers
+}
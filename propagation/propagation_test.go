@@ -0,0 +1,184 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/memdns"
+	"lego-toolbox/rawrecord"
+)
+
+type stubResolver struct {
+	values []string
+	err    error
+}
+
+func (r stubResolver) LookupTXT(context.Context, string) ([]string, error) {
+	return r.values, r.err
+}
+
+func TestChecker_Check(t *testing.T) {
+	errDown := errors.New("down")
+
+	testCases := []struct {
+		desc       string
+		requireAll bool
+		resolvers  []Resolver
+		expected   bool
+		expectErr  bool
+	}{
+		{
+			desc:      "any: visible on one",
+			resolvers: []Resolver{stubResolver{err: errDown}, stubResolver{values: []string{"a", "v"}}},
+			expected:  true,
+		},
+		{
+			desc:      "any: not visible",
+			resolvers: []Resolver{stubResolver{values: []string{"a"}}, stubResolver{}},
+		},
+		{
+			desc:      "any: errors",
+			resolvers: []Resolver{stubResolver{err: errDown}, stubResolver{}},
+			expectErr: true,
+		},
+		{
+			desc:       "all: visible on all",
+			requireAll: true,
+			resolvers:  []Resolver{stubResolver{values: []string{"v"}}, stubResolver{values: []string{"v"}}},
+			expected:   true,
+		},
+		{
+			desc:       "all: error on one",
+			requireAll: true,
+			resolvers:  []Resolver{stubResolver{values: []string{"v"}}, stubResolver{err: errDown}},
+			expectErr:  true,
+		},
+		{
+			desc:       "all: no resolver",
+			requireAll: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ok, err := NewChecker(test.requireAll, test.resolvers...).Check(context.Background(), "_acme-challenge.example.com.", "v")
+
+			assert.Equal(t, test.expected, ok)
+
+			if test.expectErr {
+				require.ErrorIs(t, err, errDown)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChecker_Wait(t *testing.T) {
+	checker := NewChecker(false, stubResolver{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := checker.Wait(ctx, "_acme-challenge.example.com.", "v", 10*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = NewChecker(false, stubResolver{values: []string{"v"}}).Wait(context.Background(), "_acme-challenge.example.com.", "v", time.Second)
+	require.NoError(t, err)
+}
+
+func TestDNSResolver(t *testing.T) {
+	provider, err := memdns.NewDNSProviderConfig(memdns.DefaultConfig())
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	require.NoError(t, provider.Present("example.com", "", rawrecord.KeyAuth("_acme-challenge.example.com.", "value")))
+
+	resolver := &DNSResolver{Server: provider.Addr(), Timeout: 2 * time.Second}
+
+	values, err := resolver.LookupTXT(context.Background(), "_acme-challenge.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value"}, values)
+}
+
+func TestDoHResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(rw, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+
+		query := new(dns.Msg)
+		if err := query.Unpack(body); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(query)
+
+		switch query.Question[0].Name {
+		case "_acme-challenge.example.com.":
+			resp.Answer = append(resp.Answer,
+				&dns.CNAME{Hdr: dns.RR_Header{Name: "_acme-challenge.example.com.", Rrtype: dns.TypeCNAME, Class: dns.ClassINET}, Target: "_acme-challenge.example.net."},
+				&dns.TXT{Hdr: dns.RR_Header{Name: "_acme-challenge.example.net.", Rrtype: dns.TypeTXT, Class: dns.ClassINET}, Txt: []string{"val", "ue"}},
+			)
+		case "_acme-challenge.example.org.":
+			resp.Rcode = dns.RcodeServerFailure
+		default:
+			resp.Rcode = dns.RcodeNameError
+		}
+
+		packed, _ := resp.Pack()
+
+		rw.Header().Set("Content-Type", "application/dns-message")
+		_, _ = rw.Write(packed)
+	}))
+	t.Cleanup(server.Close)
+
+	resolver := &DoHResolver{Endpoint: server.URL, HTTPClient: server.Client()}
+
+	values, err := resolver.LookupTXT(context.Background(), "_acme-challenge.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value"}, values)
+
+	values, err = resolver.LookupTXT(context.Background(), "_acme-challenge.missing.com.")
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = resolver.LookupTXT(context.Background(), "_acme-challenge.example.org.")
+	require.EqualError(t, err, "doh "+server.URL+": unexpected response code SERVFAIL")
+}
+
+func TestNew(t *testing.T) {
+	config, err := ParseConfig([]byte("servers: [google, https://doh.example.com/dns-query, 192.0.2.53]\nrequireAll: true\n"))
+	require.NoError(t, err)
+
+	checker, err := New(config)
+	require.NoError(t, err)
+
+	assert.True(t, checker.requireAll)
+	assert.Equal(t, []Resolver{
+		&DoHResolver{Endpoint: GoogleDoH, Timeout: 10 * time.Second},
+		&DoHResolver{Endpoint: "https://doh.example.com/dns-query", Timeout: 10 * time.Second},
+		&DNSResolver{Server: "192.0.2.53", Timeout: 10 * time.Second},
+	}, checker.resolvers)
+
+	_, err = New(&Config{Servers: []string{"tls://192.0.2.53"}})
+	require.EqualError(t, err, `propagation: unsupported server "tls://192.0.2.53"`)
+
+	checker, err = New(DefaultConfig())
+	require.NoError(t, err)
+	assert.NotEmpty(t, checker.resolvers)
+}
@@ -0,0 +1,147 @@
+package propagation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNSResolver queries a DNS server over UDP, falling back to TCP for truncated answers.
+type DNSResolver struct {
+	// Server is the address of the server, the port defaults to 53.
+	Server  string
+	Timeout time.Duration
+}
+
+// LookupTXT returns the TXT values of fqdn.
+func (r *DNSResolver) LookupTXT(ctx context.Context, fqdn string) ([]string, error) {
+	server := r.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	resp, err := exchange(ctx, server, newQuery(fqdn), r.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("dns %s: %w", server, err)
+	}
+
+	values, err := txtValues(resp)
+	if err != nil {
+		return nil, fmt.Errorf("dns %s: %w", server, err)
+	}
+
+	return values, nil
+}
+
+// DoHResolver queries a DNS-over-HTTPS endpoint (RFC 8484).
+type DoHResolver struct {
+	// Endpoint is the URL of the endpoint, e.g. GoogleDoH.
+	Endpoint string
+	Timeout  time.Duration
+	// HTTPClient defaults to a client with Timeout.
+	HTTPClient *http.Client
+}
+
+// LookupTXT returns the TXT values of fqdn.
+func (r *DoHResolver) LookupTXT(ctx context.Context, fqdn string) ([]string, error) {
+	query := newQuery(fqdn)
+	// RFC 8484 section 4.1: the ID should be 0 to improve the HTTP caching.
+	query.Id = 0
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := r.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: r.Timeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("doh %s: unexpected status code %d: %s", r.Endpoint, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	msg := new(dns.Msg)
+
+	err = msg.Unpack(body)
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	values, err := txtValues(msg)
+	if err != nil {
+		return nil, fmt.Errorf("doh %s: %w", r.Endpoint, err)
+	}
+
+	return values, nil
+}
+
+// exchange sends the query over UDP, and over TCP if the answer is truncated.
+func exchange(ctx context.Context, server string, query *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	client := &dns.Client{Timeout: timeout}
+
+	resp, _, err := client.ExchangeContext(ctx, query, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, query, server)
+	}
+
+	return resp, err
+}
+
+func newQuery(fqdn string) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(fqdn), dns.TypeTXT)
+	msg.SetEdns0(dns.DefaultMsgSize, false)
+	msg.RecursionDesired = true
+
+	return msg
+}
+
+// txtValues returns the TXT values of the answer, the CNAME records are followed by the resolver.
+func txtValues(msg *dns.Msg) ([]string, error) {
+	switch msg.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected response code %s", dns.RcodeToString[msg.Rcode])
+	}
+
+	var values []string
+	for _, rr := range msg.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, strings.Join(txt.Txt, ""))
+		}
+	}
+
+	return values, nil
+}
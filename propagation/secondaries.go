@@ -0,0 +1,169 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Secondaries queries static secondary nameservers directly,
+// for the primary/secondary setups whose secondaries are run by another vendor (or a hidden primary).
+// A secondary is up to date once its SOA serial reaches the serial of the primary,
+// i.e. the zone transfer (AXFR or IXFR) is done, and it serves the record.
+type Secondaries struct {
+	// Servers are the secondary nameservers (host[:port]).
+	Servers []string
+	// Primary is the primary nameserver (host[:port]), its SOA serial is the serial the secondaries must reach.
+	// The serials are not compared when empty.
+	Primary string
+	// Timeout bounds each query.
+	Timeout time.Duration
+}
+
+// Zone returns the zone of fqdn, from the SOA served by the primary, or by the first secondary without primary.
+func (s *Secondaries) Zone(ctx context.Context, fqdn string) (string, error) {
+	server := s.Primary
+	if server == "" {
+		if len(s.Servers) == 0 {
+			return "", errors.New("no secondary nameservers")
+		}
+
+		server = s.Servers[0]
+	}
+
+	soa, err := s.soa(ctx, server, dns.Fqdn(fqdn))
+	if err != nil {
+		return "", err
+	}
+
+	return soa.Hdr.Name, nil
+}
+
+// Notify sends a DNS NOTIFY of the zone (RFC 1996) to the secondaries,
+// so they transfer the zone without waiting for its refresh interval.
+// The secondaries accepting the NOTIFY of their primary only refuse it.
+func (s *Secondaries) Notify(ctx context.Context, zone string) error {
+	var errs []error
+
+	for _, server := range s.Servers {
+		server = withDefaultPort(server)
+
+		msg := new(dns.Msg)
+		msg.SetNotify(dns.Fqdn(zone))
+
+		resp, err := exchange(ctx, server, msg, s.Timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess {
+			errs = append(errs, fmt.Errorf("dns %s: NOTIFY %s", server, dns.RcodeToString[resp.Rcode]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check reports whether every secondary serves value in the TXT records of fqdn,
+// with the SOA serial of the primary when it is set.
+func (s *Secondaries) Check(ctx context.Context, zone, fqdn, value string) (bool, error) {
+	var serial uint32
+
+	if s.Primary != "" {
+		soa, err := s.soa(ctx, s.Primary, dns.Fqdn(zone))
+		if err != nil {
+			return false, fmt.Errorf("primary: %w", err)
+		}
+
+		serial = soa.Serial
+	}
+
+	var errs []error
+	ready := 0
+
+	for _, server := range s.Servers {
+		if s.Primary != "" {
+			soa, err := s.soa(ctx, server, dns.Fqdn(zone))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			if serialBefore(soa.Serial, serial) {
+				errs = append(errs, fmt.Errorf("dns %s: serial %d behind the serial %d of the primary", server, soa.Serial, serial))
+				continue
+			}
+		}
+
+		query := newQuery(fqdn)
+		query.RecursionDesired = false
+
+		resp, err := exchange(ctx, withDefaultPort(server), query, s.Timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		values, err := txtValues(resp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dns %s: %w", server, err))
+			continue
+		}
+
+		if !slices.Contains(values, value) {
+			errs = append(errs, fmt.Errorf("dns %s: record not found", server))
+			continue
+		}
+
+		ready++
+	}
+
+	return ready == len(s.Servers), errors.Join(errs...)
+}
+
+// soa returns the SOA of the zone of name served by the server:
+// the answer for the apex of the zone, the authority section otherwise.
+func (s *Secondaries) soa(ctx context.Context, server, name string) (*dns.SOA, error) {
+	server = withDefaultPort(server)
+
+	query := new(dns.Msg)
+	query.SetQuestion(name, dns.TypeSOA)
+	query.RecursionDesired = false
+
+	resp, err := exchange(ctx, server, query, s.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("dns %s: %w", server, err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("dns %s: unexpected response code %s", server, dns.RcodeToString[resp.Rcode])
+	}
+
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa, nil
+		}
+	}
+
+	return nil, fmt.Errorf("dns %s: no SOA for %s", server, name)
+}
+
+// serialBefore reports whether the serial a is before b, in serial number arithmetic (RFC 1982).
+func serialBefore(a, b uint32) bool {
+	return a != b && int32(a-b) < 0
+}
+
+func withDefaultPort(server string) string {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		return net.JoinHostPort(server, "53")
+	}
+
+	return server
+}
@@ -0,0 +1,189 @@
+package propagation
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type zoneState struct {
+	serial      uint32
+	values      []string
+	notifyRcode int
+	notified    atomic.Int32
+}
+
+// startSecondaryServer starts a DNS server authoritative for example.com, serving the state.
+func startSecondaryServer(t *testing.T, state *zoneState) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	header := func(name string, rrtype uint16) dns.RR_Header {
+		return dns.RR_Header{Name: name, Rrtype: rrtype, Class: dns.ClassINET}
+	}
+
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		if req.Opcode == dns.OpcodeNotify {
+			state.notified.Add(1)
+			m.Rcode = state.notifyRcode
+			_ = w.WriteMsg(m)
+
+			return
+		}
+
+		q := req.Question[0]
+		name := strings.ToLower(q.Name)
+
+		soa := &dns.SOA{Hdr: header("example.com.", dns.TypeSOA), Ns: "ns1.example.com.", Mbox: "admin.example.com.", Serial: state.serial}
+
+		switch {
+		case !dns.IsSubDomain("example.com.", name):
+			m.Rcode = dns.RcodeRefused
+		case q.Qtype == dns.TypeSOA && name == "example.com.":
+			m.Answer = append(m.Answer, soa)
+		case q.Qtype == dns.TypeTXT && name == "_acme-challenge.example.com." && len(state.values) > 0:
+			m.Answer = append(m.Answer, &dns.TXT{Hdr: header(q.Name, dns.TypeTXT), Txt: state.values})
+		default:
+			m.Ns = append(m.Ns, soa)
+		}
+
+		_ = w.WriteMsg(m)
+	})}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	go func() { _ = server.ActivateAndServe() }()
+	<-started
+
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestSecondaries_Zone(t *testing.T) {
+	primary := startSecondaryServer(t, &zoneState{serial: 10})
+	secondary := startSecondaryServer(t, &zoneState{serial: 10})
+
+	s := &Secondaries{Servers: []string{secondary}, Primary: primary, Timeout: time.Second}
+
+	zone, err := s.Zone(context.Background(), "_acme-challenge.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", zone)
+
+	s.Primary = ""
+
+	zone, err = s.Zone(context.Background(), "_acme-challenge.example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", zone)
+
+	_, err = s.Zone(context.Background(), "_acme-challenge.example.org.")
+	require.ErrorContains(t, err, "unexpected response code REFUSED")
+}
+
+func TestSecondaries_Check(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		primary     bool
+		serial      uint32
+		values      []string
+		expected    bool
+		expectedErr string
+	}{
+		{
+			desc:     "up to date",
+			primary:  true,
+			serial:   10,
+			values:   []string{"value"},
+			expected: true,
+		},
+		{
+			desc:     "serial after the primary",
+			primary:  true,
+			serial:   11,
+			values:   []string{"value"},
+			expected: true,
+		},
+		{
+			desc:        "serial behind the primary",
+			primary:     true,
+			serial:      9,
+			values:      []string{"value"},
+			expectedErr: "serial 9 behind the serial 10 of the primary",
+		},
+		{
+			desc:        "record not found",
+			primary:     true,
+			serial:      10,
+			values:      []string{"other"},
+			expectedErr: "record not found",
+		},
+		{
+			desc:     "without primary",
+			serial:   1,
+			values:   []string{"value"},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			secondary := startSecondaryServer(t, &zoneState{serial: test.serial, values: test.values})
+
+			s := &Secondaries{Servers: []string{secondary}, Timeout: time.Second}
+
+			if test.primary {
+				s.Primary = startSecondaryServer(t, &zoneState{serial: 10, values: []string{"value"}})
+			}
+
+			ok, err := s.Check(context.Background(), "example.com.", "_acme-challenge.example.com.", "value")
+
+			assert.Equal(t, test.expected, ok)
+
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecondaries_Notify(t *testing.T) {
+	accepting := &zoneState{notifyRcode: dns.RcodeSuccess}
+	refusing := &zoneState{notifyRcode: dns.RcodeRefused}
+
+	s := &Secondaries{
+		Servers: []string{startSecondaryServer(t, accepting), startSecondaryServer(t, refusing)},
+		Timeout: time.Second,
+	}
+
+	err := s.Notify(context.Background(), "example.com")
+	require.ErrorContains(t, err, "NOTIFY REFUSED")
+
+	assert.EqualValues(t, 1, accepting.notified.Load())
+	assert.EqualValues(t, 1, refusing.notified.Load())
+}
+
+func Test_serialBefore(t *testing.T) {
+	assert.True(t, serialBefore(9, 10))
+	assert.False(t, serialBefore(10, 10))
+	assert.False(t, serialBefore(11, 10))
+	// wrap around
+	assert.True(t, serialBefore(4294967295, 1))
+	assert.False(t, serialBefore(1, 4294967295))
+}
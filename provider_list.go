@@ -0,0 +1,99 @@
+package legotoolbox
+
+import (
+	"errors"
+	"slices"
+	"sort"
+
+	"lego-toolbox/internal/provideralias"
+	"lego-toolbox/providers/dns/registry"
+)
+
+// ProviderInfo describes a DNS provider of the factory.
+type ProviderInfo struct {
+	// Name is the provider name understood by NewDNSChallengeProviderByName.
+	Name string `json:"name" yaml:"name"`
+	// Aliases are the other names of the provider accepted by the factory.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Deprecated reports whether the provider is deprecated, Replacement is the provider to use instead.
+	Deprecated  bool   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	// YAMLConfig reports whether the provider is configured from YAML, the other providers are configured only from the environment.
+	YAMLConfig bool `json:"yamlConfig" yaml:"yamlConfig"`
+	// Capabilities are the capabilities of the provider, see ProviderCapabilities.
+	Capabilities Capabilities `json:"capabilities" yaml:"capabilities"`
+}
+
+// FilterOption selects the providers returned by ListProviders.
+type FilterOption func(info ProviderInfo) bool
+
+// WithYAMLConfigSupport selects the providers configured from YAML.
+func WithYAMLConfigSupport() FilterOption {
+	return func(info ProviderInfo) bool {
+		return info.YAMLConfig
+	}
+}
+
+// WithCapability selects the providers having the capability.
+func WithCapability(capability Capability) FilterOption {
+	return func(info ProviderInfo) bool {
+		return info.Capabilities.Has(capability)
+	}
+}
+
+// WithoutDeprecated selects the providers not deprecated.
+func WithoutDeprecated() FilterOption {
+	return func(info ProviderInfo) bool {
+		return !info.Deprecated
+	}
+}
+
+// ListProviders returns the DNS providers of the factory matching all the filters, sorted by name.
+//
+//	providers := ListProviders(WithYAMLConfigSupport(), WithCapability(MultipleValues))
+func ListProviders(opts ...FilterOption) []ProviderInfo {
+	var infos []ProviderInfo
+
+	for _, name := range registry.Names() {
+		info := providerInfo(name)
+
+		if !slices.ContainsFunc(opts, func(opt FilterOption) bool { return !opt(info) }) {
+			infos = append(infos, info)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	return infos
+}
+
+func providerInfo(name string) ProviderInfo {
+	info := ProviderInfo{
+		Name:         name,
+		Aliases:      provideralias.Aliases(name),
+		Capabilities: capabilitiesByName(name),
+	}
+
+	info.Replacement, info.Deprecated = provideralias.Replacement(name)
+
+	_, err := defaultDNSChallengeProviderConfig(name)
+	info.YAMLConfig = !errors.Is(err, errEnvOnlyProvider)
+
+	return info
+}
+
+// ResolveProviderName returns the provider name of an alias (e.g. `fastdns` is `edgedns`),
+// the other names are returned unchanged. The instance suffix (`fastdns@prod`) is kept.
+// A deprecation warning is logged, through the lego logger, the first time a deprecated name is resolved.
+func ResolveProviderName(name string) string {
+	providerName, instance := SplitProviderID(name)
+
+	return JoinProviderID(provideralias.Resolve(providerName), instance)
+}
+
+// isDNSProvider reports whether the name is a provider name or an alias of the factory.
+func isDNSProvider(name string) bool {
+	_, ok := registry.Get(name)
+
+	return ok || provideralias.IsAlias(name)
+}
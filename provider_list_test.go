@@ -0,0 +1,88 @@
+package legotoolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/registry"
+)
+
+func TestListProviders(t *testing.T) {
+	infos := ListProviders()
+	require.Len(t, infos, len(registry.Names()))
+
+	assert.IsNonDecreasing(t, providerNamesOf(infos))
+
+	index := providerIndex(infos, "edgedns")
+	require.GreaterOrEqual(t, index, 0)
+
+	assert.Equal(t, []string{"fastdns"}, infos[index].Aliases)
+	assert.True(t, infos[index].YAMLConfig)
+
+	index = providerIndex(infos, "azure")
+	require.GreaterOrEqual(t, index, 0)
+
+	assert.True(t, infos[index].Deprecated)
+	assert.Equal(t, "azuredns", infos[index].Replacement)
+
+	assert.Equal(t, -1, providerIndex(infos, "linodev4"))
+}
+
+func TestListProviders_filters(t *testing.T) {
+	infos := ListProviders(WithYAMLConfigSupport(), WithCapability(MultipleValues), WithoutDeprecated())
+	require.NotEmpty(t, infos)
+
+	for _, info := range infos {
+		assert.True(t, info.YAMLConfig, info.Name)
+		assert.True(t, info.Capabilities.MultipleValues, info.Name)
+		assert.False(t, info.Deprecated, info.Name)
+	}
+
+	names := providerNamesOf(infos)
+	assert.NotContains(t, names, "duckdns")
+	assert.NotContains(t, names, "azure")
+	assert.Contains(t, names, "cloudflare")
+}
+
+func TestCapabilities_Has(t *testing.T) {
+	capabilities := Capabilities{MultipleValues: true, CNAMEFollow: true}
+
+	assert.True(t, capabilities.Has(MultipleValues))
+	assert.False(t, capabilities.Has(WildcardSafe))
+	assert.True(t, capabilities.Has(CNAMEFollow))
+	assert.False(t, capabilities.Has("unknown"))
+}
+
+func TestGetDNSChallengeProviderList(t *testing.T) {
+	names := GetDNSChallengeProviderList("", nil)
+
+	assert.Len(t, names, len(registry.Names())+3)
+	assert.Contains(t, names, "linodev4")
+	assert.Contains(t, names, "linode")
+}
+
+func providerNamesOf(infos []ProviderInfo) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+
+	return names
+}
+
+func providerIndex(infos []ProviderInfo, name string) int {
+	for i, info := range infos {
+		if info.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func TestResolveProviderName(t *testing.T) {
+	assert.Equal(t, "edgedns", ResolveProviderName("fastdns"))
+	assert.Equal(t, "linode@prod", ResolveProviderName("linodev4@prod"))
+	assert.Equal(t, "cloudflare@personal", ResolveProviderName("cloudflare@personal"))
+}
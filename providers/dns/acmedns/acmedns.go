@@ -5,11 +5,11 @@ package acmedns
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 
 	"github.com/cpu/goacmedns"
-	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/rawrecord"
 )
 
 const (
@@ -63,10 +63,15 @@ func NewDNSProvider() (*DNSProvider, error) {
 	return NewDNSProviderClient(client, storage)
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
-	config := &Config{}
-	err := yaml.Unmarshal(rawConfig, &config)
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +135,7 @@ func (e ErrCNAMERequired) Error() string {
 // This will halt issuance and indicate to the user that a one-time manual setup is required for the domain.
 func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 	// Compute the challenge response FQDN and TXT value for the domain based on the keyAuth.
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// Check if credentials were previously saved for this domain.
 	account, err := d.storage.Fetch(domain)
@@ -4,7 +4,9 @@ package alidns
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
@@ -15,10 +17,25 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"golang.org/x/net/idna"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 const defaultRegionID = "cn-hangzhou"
 
+// Authentication modes.
+const (
+	// AuthModeAccessKey uses a long-lived AccessKey pair.
+	AuthModeAccessKey = "accessKey"
+	// AuthModeSTS uses STS temporary credentials (AccessKey pair and security token).
+	AuthModeSTS = "sts"
+	// AuthModeECSRAMRole uses the RAM role attached to the ECS instance, through the instance metadata.
+	AuthModeECSRAMRole = "ecsRamRole"
+)
+
+// metadataRAMRoleURL lists the RAM role attached to the ECS instance.
+var metadataRAMRoleURL = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+
 // Environment variables names.
 const (
 	envNamespace = "ALICLOUD_"
@@ -28,6 +45,7 @@ const (
 	EnvSecretKey     = envNamespace + "SECRET_KEY"
 	EnvSecurityToken = envNamespace + "SECURITY_TOKEN"
 	EnvRegionID      = envNamespace + "REGION_ID"
+	EnvAuthMode      = envNamespace + "AUTH_MODE"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -37,6 +55,9 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
+	// AuthMode selects the credentials to use: accessKey, sts or ecsRamRole.
+	// When empty, it is inferred from the fields which are set.
+	AuthMode           string        `yaml:"authMode"`
 	RamRole            string        `yaml:"RamRole"`
 	ApiKey             string        `yaml:"ApiKey"`
 	SecretKey          string        `yaml:"secretKey"`
@@ -68,6 +89,20 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# config.yaml
+authMode: ""                          # 认证方式：accessKey、sts、ecsRamRole，留空则根据已填写的字段自动选择
+RamRole: ""                           # ECS 实例 RAM 角色名称，authMode 为 ecsRamRole 时留空则从实例元数据自动获取
+ApiKey: "your_access_key_id"          # AccessKey ID
+secretKey: "your_access_key_secret"   # AccessKey Secret
+securityToken: ""                     # STS 临时凭证的 SecurityToken
+regionID: "cn-hangzhou"               # 区域 ID
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间
+TTL: 600                              # TXT 记录的 TTL
+HTTPTimeout: 10s                      # HTTP 请求超时时间`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -81,6 +116,12 @@ type DNSProvider struct {
 func NewDNSProvider() (*DNSProvider, error) {
 	config := NewDefaultConfig()
 	config.RegionID = env.GetOrFile(EnvRegionID)
+	config.AuthMode = env.GetOrFile(EnvAuthMode)
+
+	if config.AuthMode == AuthModeECSRAMRole {
+		config.RamRole = env.GetOrFile(EnvRAMRole)
+		return NewDNSProviderConfig(config)
+	}
 
 	values, err := env.Get(EnvRAMRole)
 	if err == nil {
@@ -103,7 +144,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -120,16 +161,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config.RegionID = defaultRegionID
 	}
 
-	var credential auth.Credential
-	switch {
-	case config.RamRole != "":
-		credential = credentials.NewEcsRamRoleCredential(config.RamRole)
-	case config.ApiKey != "" && config.SecretKey != "" && config.SecurityToken != "":
-		credential = credentials.NewStsTokenCredential(config.ApiKey, config.SecretKey, config.SecurityToken)
-	case config.ApiKey != "" && config.SecretKey != "":
-		credential = credentials.NewAccessKeyCredential(config.ApiKey, config.SecretKey)
-	default:
-		return nil, errors.New("alicloud: ram role or credentials missing")
+	credential, err := config.credential()
+	if err != nil {
+		return nil, fmt.Errorf("alicloud: %w", err)
 	}
 
 	conf := sdk.NewConfig().WithTimeout(config.HTTPTimeout)
@@ -142,6 +176,81 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{config: config, client: client}, nil
 }
 
+func (c *Config) credential() (auth.Credential, error) {
+	switch c.AuthMode {
+	case "":
+		switch {
+		case c.RamRole != "":
+			return credentials.NewEcsRamRoleCredential(c.RamRole), nil
+		case c.ApiKey != "" && c.SecretKey != "" && c.SecurityToken != "":
+			return credentials.NewStsTokenCredential(c.ApiKey, c.SecretKey, c.SecurityToken), nil
+		case c.ApiKey != "" && c.SecretKey != "":
+			return credentials.NewAccessKeyCredential(c.ApiKey, c.SecretKey), nil
+		default:
+			return nil, errors.New("ram role or credentials missing")
+		}
+
+	case AuthModeAccessKey:
+		if c.ApiKey == "" || c.SecretKey == "" {
+			return nil, errors.New("access key credentials missing")
+		}
+
+		return credentials.NewAccessKeyCredential(c.ApiKey, c.SecretKey), nil
+
+	case AuthModeSTS:
+		if c.ApiKey == "" || c.SecretKey == "" || c.SecurityToken == "" {
+			return nil, errors.New("STS credentials missing: access key, secret key and security token are required")
+		}
+
+		return credentials.NewStsTokenCredential(c.ApiKey, c.SecretKey, c.SecurityToken), nil
+
+	case AuthModeECSRAMRole:
+		roleName := c.RamRole
+		if roleName == "" {
+			var err error
+			roleName, err = discoverRAMRole(c.HTTPTimeout)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return credentials.NewEcsRamRoleCredential(roleName), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported auth mode: %q", c.AuthMode)
+	}
+}
+
+// discoverRAMRole gets the name of the RAM role attached to the ECS instance from the instance metadata.
+func discoverRAMRole(timeout time.Duration) (string, error) {
+	client := &http.Client{Timeout: timeout}
+
+	resp, err := client.Get(metadataRAMRoleURL)
+	if err != nil {
+		return "", fmt.Errorf("RAM role discovery: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("RAM role discovery: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("RAM role discovery: unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	roleName, _, _ := strings.Cut(strings.TrimSpace(string(body)), "\n")
+	roleName = strings.TrimSpace(roleName)
+
+	if roleName == "" {
+		return "", errors.New("RAM role discovery: no RAM role attached to the instance")
+	}
+
+	return roleName, nil
+}
+
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
 // Adjusting here to cope with spikes in propagation times.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -150,7 +259,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -171,7 +280,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	records, err := d.findTxtRecords(info.EffectiveFQDN)
 	if err != nil {
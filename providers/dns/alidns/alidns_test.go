@@ -1,11 +1,16 @@
 package alidns
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -13,7 +18,8 @@ const envDomain = envNamespace + "DOMAIN"
 var envTest = tester.NewEnvTest(
 	EnvAccessKey,
 	EnvSecretKey,
-	EnvRAMRole).
+	EnvRAMRole,
+	EnvAuthMode).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -136,6 +142,118 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_authMode(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   Config
+		expected string
+	}{
+		{
+			desc:   "access key",
+			config: Config{AuthMode: AuthModeAccessKey, ApiKey: "123", SecretKey: "456"},
+		},
+		{
+			desc:     "access key: missing secret key",
+			config:   Config{AuthMode: AuthModeAccessKey, ApiKey: "123"},
+			expected: "alicloud: access key credentials missing",
+		},
+		{
+			desc:   "sts",
+			config: Config{AuthMode: AuthModeSTS, ApiKey: "123", SecretKey: "456", SecurityToken: "789"},
+		},
+		{
+			desc:     "sts: missing security token",
+			config:   Config{AuthMode: AuthModeSTS, ApiKey: "123", SecretKey: "456"},
+			expected: "alicloud: STS credentials missing: access key, secret key and security token are required",
+		},
+		{
+			desc:   "ecs ram role",
+			config: Config{AuthMode: AuthModeECSRAMRole, RamRole: "LegoInstanceRole"},
+		},
+		{
+			desc:     "unsupported",
+			config:   Config{AuthMode: "foo"},
+			expected: `alicloud: unsupported auth mode: "foo"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := test.config
+			config.HTTPTimeout = 10 * time.Second
+
+			p, err := NewDNSProviderConfig(&config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_discoverRAMRole(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		status   int
+		body     string
+		expected string
+		err      string
+	}{
+		{
+			desc:     "success",
+			status:   http.StatusOK,
+			body:     "LegoInstanceRole\n",
+			expected: "LegoInstanceRole",
+		},
+		{
+			desc:   "no role",
+			status: http.StatusOK,
+			err:    "RAM role discovery: no RAM role attached to the instance",
+		},
+		{
+			desc:   "not found",
+			status: http.StatusNotFound,
+			body:   "Not Found",
+			err:    "RAM role discovery: unexpected status code 404: Not Found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(test.status)
+				_, _ = fmt.Fprint(rw, test.body)
+			}))
+			t.Cleanup(server.Close)
+
+			original := metadataRAMRoleURL
+			metadataRAMRoleURL = server.URL + "/"
+			t.Cleanup(func() { metadataRAMRoleURL = original })
+
+			roleName, err := discoverRAMRole(time.Second)
+
+			if test.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, test.expected, roleName)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("authMode: sts\nApiKey: \"123\"\nsecretKey: \"456\"\nsecurityToken: \"789\"\n"))
+	require.NoError(t, err)
+	require.Equal(t, AuthModeSTS, config.AuthMode)
+	require.Equal(t, "789", config.SecurityToken)
+	require.Equal(t, 600, config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -163,3 +281,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
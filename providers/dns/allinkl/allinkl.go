@@ -5,14 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/allinkl/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -66,8 +67,7 @@ type DNSProvider struct {
 	identifier *internal.Identifier
 	client     *internal.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for all-inkl.
@@ -88,7 +88,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +121,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config:     config,
 		identifier: identifier,
 		client:     client,
-		recordIDs:  make(map[string]string),
+		recordIDs:  challengestore.New[string](),
 	}, nil
 }
 
@@ -131,9 +131,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -166,16 +172,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("allinkl: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = recordID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, recordID)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	ctx := context.Background()
 
@@ -187,9 +191,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx = internal.WithContext(ctx, credential)
 
 	// gets the record's unique ID from when we created it
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("allinkl: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -199,5 +201,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("allinkl: %w", err)
 	}
 
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+
 	return nil
 }
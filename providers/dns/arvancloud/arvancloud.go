@@ -5,14 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/arvancloud/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 600
@@ -23,6 +25,8 @@ const (
 
 	EnvAPIKey = envNamespace + "API_KEY"
 
+	EnvPurgeCache = envNamespace + "PURGE_CACHE"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -31,7 +35,10 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	ApiKey             string        `yaml:"apiKey"`
+	ApiKey string `yaml:"apiKey"`
+	// PurgeCache purges the edge cache of the record after each change,
+	// the changes are visible faster to the resolvers of ArvanCloud.
+	PurgeCache         bool          `yaml:"purgeCache"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"TTL"`
@@ -41,6 +48,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		PurgeCache:         env.GetOrDefaultBool(EnvPurgeCache, false),
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
@@ -62,13 +70,21 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+apiKey: "your_api_key"               # API 密钥
+purgeCache: false                    # 记录变更后清除边缘缓存，使解析更快生效
+propagationTimeout: 120s             # 传播超时时间
+pollingInterval: 2s                  # 轮询间隔时间
+TTL: 600                             # TXT 记录的生存时间（秒），最小 600`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for ArvanCloud.
@@ -88,7 +104,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +134,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]string),
+		recordIDs: challengestore.New[string](),
 	}, nil
 }
 
@@ -128,9 +144,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -157,21 +179,23 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		},
 	}
 
-	newRecord, err := d.client.CreateRecord(context.Background(), authZone, record)
+	ctx := context.Background()
+
+	newRecord, err := d.client.CreateRecord(ctx, authZone, record)
 	if err != nil {
 		return fmt.Errorf("arvancloud: failed to add TXT record: fqdn=%s: %w", info.EffectiveFQDN, err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = newRecord.ID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, newRecord.ID)
+
+	d.purgeCache(ctx, authZone, info.EffectiveFQDN)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -181,21 +205,34 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	authZone = dns01.UnFqdn(authZone)
 
 	// gets the record's unique ID from when we created it
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("arvancloud: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
 
-	if err := d.client.DeleteRecord(context.Background(), authZone, recordID); err != nil {
+	ctx := context.Background()
+
+	if err := d.client.DeleteRecord(ctx, authZone, recordID); err != nil {
 		return fmt.Errorf("arvancloud: failed to delete TXT record: id=%s: %w", recordID, err)
 	}
 
 	// deletes record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+
+	d.purgeCache(ctx, authZone, info.EffectiveFQDN)
 
 	return nil
 }
+
+// purgeCache purges the cache of the record, when enabled (PurgeCache).
+// A failure only delays the visibility of the change: it is logged.
+func (d *DNSProvider) purgeCache(ctx context.Context, authZone, fqdn string) {
+	if !d.config.PurgeCache {
+		return
+	}
+
+	err := d.client.PurgeCache(ctx, authZone, []string{dns01.UnFqdn(fqdn)})
+	if err != nil {
+		log.Warnf("arvancloud: purge the cache of %s: %v", dns01.UnFqdn(fqdn), err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,6 +99,18 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("apiKey: A\npurgeCache: true\n"))
+	require.NoError(t, err)
+
+	assert.True(t, config.PurgeCache)
+
+	config, err = ParseConfig([]byte("apiKey: A\n"))
+	require.NoError(t, err)
+
+	assert.False(t, config.PurgeCache)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -115,6 +115,24 @@ func (c *Client) DeleteRecord(ctx context.Context, domain, id string) error {
 	return nil
 }
 
+// PurgeCache purges the cache of the hostnames of the domain.
+// https://www.arvancloud.ir/docs/api/cdn/4.0#operation/caching.purge
+func (c *Client) PurgeCache(ctx context.Context, domain string, hostnames []string) error {
+	endpoint := c.baseURL.JoinPath("cdn", "4.0", "domains", domain, "caching", "purge")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, PurgeRequest{Purge: "hostname", Hostnames: hostnames})
+	if err != nil {
+		return err
+	}
+
+	err = c.do(req, http.StatusOK, nil)
+	if err != nil {
+		return fmt.Errorf("could not purge the cache; Domain: %s: %w", domain, err)
+	}
+
+	return nil
+}
+
 func (c *Client) do(req *http.Request, expectedStatus int, result any) error {
 	req.Header.Set(authorizationHeader, c.apiKey)
 
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -150,3 +151,38 @@ func TestClient_DeleteRecord(t *testing.T) {
 	err := client.DeleteRecord(context.Background(), domain, recordID)
 	require.NoError(t, err)
 }
+
+func TestClient_PurgeCache(t *testing.T) {
+	const apiKey = "myKeyD"
+
+	client, mux := setupTest(t, apiKey)
+
+	const domain = "example.com"
+
+	mux.HandleFunc("/cdn/4.0/domains/"+domain+"/caching/purge", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(rw, fmt.Sprintf("unsupported method: %s", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		auth := req.Header.Get(authorizationHeader)
+		if auth != apiKey {
+			http.Error(rw, fmt.Sprintf("invalid API key: %s", auth), http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if string(bytes.TrimSpace(body)) != `{"purge":"hostname","hostnames":["_acme-challenge.example.com"]}` {
+			http.Error(rw, fmt.Sprintf("invalid request body: %s", body), http.StatusBadRequest)
+			return
+		}
+	})
+
+	err := client.PurgeCache(context.Background(), domain, []string{"_acme-challenge.example.com"})
+	require.NoError(t, err)
+}
@@ -27,3 +27,9 @@ type IPFilterMode struct {
 	Order     string `json:"order,omitempty"`
 	GeoFilter string `json:"geo_filter,omitempty"`
 }
+
+// PurgeRequest a cache purge request.
+type PurgeRequest struct {
+	Purge     string   `json:"purge"`
+	Hostnames []string `json:"hostnames,omitempty"`
+}
@@ -4,13 +4,14 @@ package auroradns
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"sync"
+	"lego-toolbox/yamlconfig"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nrdcg/auroradns"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 const defaultBaseURL = "https://api.auroradns.eu"
@@ -58,10 +59,9 @@ func DefaultConfig() *Config {
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
-	config      *Config
-	client      *auroradns.Client
+	recordIDs *challengestore.Store[string]
+	config    *Config
+	client    *auroradns.Client
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for AuroraDNS.
@@ -84,7 +84,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -118,13 +118,19 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]string),
+		recordIDs: challengestore.New[string](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -159,20 +165,16 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("aurora: could not create record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = newRecord.ID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, newRecord.ID)
 
 	return nil
 }
 
 // CleanUp removes a given record that was generated by Present.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 
 	if !ok {
 		return fmt.Errorf("aurora: unknown recordID for %q", info.EffectiveFQDN)
@@ -195,9 +197,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("aurora: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
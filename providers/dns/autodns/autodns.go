@@ -5,14 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
 	"time"
 
-	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/autodns/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -98,7 +98,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +140,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	records := []*internal.ResourceRecord{{
 		Name:  info.EffectiveFQDN,
@@ -159,7 +159,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record previously created.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	records := []*internal.ResourceRecord{{
 		Name:  info.EffectiveFQDN,
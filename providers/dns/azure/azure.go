@@ -5,8 +5,8 @@ package azure
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"io"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
 	"time"
@@ -138,7 +138,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
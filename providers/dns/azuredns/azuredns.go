@@ -6,7 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strings"
 	"time"
@@ -75,6 +75,16 @@ type Config struct {
 	TTL                    int           `yaml:"TTL"`
 	HTTPClient             *http.Client  `yaml:"-"`
 	ServiceDiscoveryFilter string        `yaml:"serviceDiscoveryFilter"`
+	// ZoneResourceGroups maps zone names to their resource group (and optionally subscription).
+	// When set, the zones are not discovered with Azure Resource Graph.
+	ZoneResourceGroups map[string]ZoneResourceGroup `yaml:"zoneResourceGroups"`
+}
+
+// ZoneResourceGroup locates a DNS zone.
+type ZoneResourceGroup struct {
+	ResourceGroup string `yaml:"resourceGroup"`
+	// SubscriptionID defaults to Config.SubscriptionID.
+	SubscriptionID string `yaml:"subscriptionID"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -154,7 +164,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
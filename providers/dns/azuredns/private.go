@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"lego-toolbox/rawrecord"
 )
 
 // DNSProviderPrivate implements the challenge.Provider interface for Azure Private Zone DNS.
@@ -24,9 +25,9 @@ type DNSProviderPrivate struct {
 
 // NewDNSProviderPrivate creates a DNSProviderPrivate structure.
 func NewDNSProviderPrivate(config *Config, credentials azcore.TokenCredential) (*DNSProviderPrivate, error) {
-	zones, err := discoverDNSZones(context.Background(), config, credentials)
+	zones, err := lookupDNSZones(context.Background(), config, credentials)
 	if err != nil {
-		return nil, fmt.Errorf("discover DNS zones: %w", err)
+		return nil, err
 	}
 
 	return &DNSProviderPrivate{
@@ -45,7 +46,7 @@ func (d *DNSProviderPrivate) Timeout() (timeout, interval time.Duration) {
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProviderPrivate) Present(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -98,7 +99,7 @@ func (d *DNSProviderPrivate) Present(domain, _, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProviderPrivate) CleanUp(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
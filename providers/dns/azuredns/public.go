@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"lego-toolbox/rawrecord"
 )
 
 // DNSProviderPublic implements the challenge.Provider interface for Azure Public Zone DNS.
@@ -24,9 +25,9 @@ type DNSProviderPublic struct {
 
 // NewDNSProviderPublic creates a DNSProviderPublic structure.
 func NewDNSProviderPublic(config *Config, credentials azcore.TokenCredential) (*DNSProviderPublic, error) {
-	zones, err := discoverDNSZones(context.Background(), config, credentials)
+	zones, err := lookupDNSZones(context.Background(), config, credentials)
 	if err != nil {
-		return nil, fmt.Errorf("discover DNS zones: %w", err)
+		return nil, err
 	}
 
 	return &DNSProviderPublic{
@@ -45,7 +46,7 @@ func (d *DNSProviderPublic) Timeout() (timeout, interval time.Duration) {
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProviderPublic) Present(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -97,7 +98,7 @@ func (d *DNSProviderPublic) Present(domain, _, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProviderPublic) CleanUp(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
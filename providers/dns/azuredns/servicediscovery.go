@@ -3,12 +3,15 @@ package azuredns
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/go-acme/lego/v4/challenge/dns01"
 )
 
 type ServiceDiscoveryZone struct {
@@ -24,6 +27,62 @@ const (
 
 const ResourceGraphQueryOptionsTop int32 = 1000
 
+// lookupDNSZones returns the zones from Config.ZoneResourceGroups if any, otherwise the discovered zones.
+func lookupDNSZones(ctx context.Context, config *Config, credentials azcore.TokenCredential) (map[string]ServiceDiscoveryZone, error) {
+	if len(config.ZoneResourceGroups) > 0 {
+		zones, err := configuredDNSZones(config)
+		if err != nil {
+			return nil, fmt.Errorf("zone resource groups: %w", err)
+		}
+
+		return zones, nil
+	}
+
+	zones, err := discoverDNSZones(ctx, config, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("discover DNS zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+// configuredDNSZones builds the zones from Config.ZoneResourceGroups.
+func configuredDNSZones(config *Config) (map[string]ServiceDiscoveryZone, error) {
+	zones := make(map[string]ServiceDiscoveryZone, len(config.ZoneResourceGroups))
+
+	for name, location := range config.ZoneResourceGroups {
+		zoneName := strings.ToLower(dns01.UnFqdn(name))
+		if zoneName == "" {
+			return nil, errors.New("empty zone name")
+		}
+
+		if location.ResourceGroup == "" {
+			return nil, fmt.Errorf("%s: missing resource group", zoneName)
+		}
+
+		subscriptionID := location.SubscriptionID
+		if subscriptionID == "" {
+			subscriptionID = config.SubscriptionID
+		}
+
+		if subscriptionID == "" {
+			return nil, fmt.Errorf("%s: missing subscription ID", zoneName)
+		}
+
+		if _, exists := zones[zoneName]; exists {
+			return nil, fmt.Errorf(`duplicate dns zone "%s"`, zoneName)
+		}
+
+		zones[zoneName] = ServiceDiscoveryZone{
+			Name:           zoneName,
+			ResourceGroup:  location.ResourceGroup,
+			SubscriptionID: subscriptionID,
+		}
+	}
+
+	return zones, nil
+}
+
 // discoverDNSZones finds all visible Azure DNS zones based on optional subscriptionID, resourceGroup and serviceDiscovery filter using Kusto query.
 func discoverDNSZones(ctx context.Context, config *Config, credentials azcore.TokenCredential) (map[string]ServiceDiscoveryZone, error) {
 	options := &arm.ClientOptions{
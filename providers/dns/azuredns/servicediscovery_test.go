@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_createGraphQuery(t *testing.T) {
@@ -127,3 +128,66 @@ resources
 		})
 	}
 }
+
+func Test_configuredDNSZones(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		cfg      *Config
+		expected map[string]ServiceDiscoveryZone
+		err      string
+	}{
+		{
+			desc: "default subscription",
+			cfg: &Config{
+				SubscriptionID: "sub1",
+				ZoneResourceGroups: map[string]ZoneResourceGroup{
+					"example.com.": {ResourceGroup: "rg1"},
+					"Example.org":  {ResourceGroup: "rg2", SubscriptionID: "sub2"},
+				},
+			},
+			expected: map[string]ServiceDiscoveryZone{
+				"example.com": {Name: "example.com", ResourceGroup: "rg1", SubscriptionID: "sub1"},
+				"example.org": {Name: "example.org", ResourceGroup: "rg2", SubscriptionID: "sub2"},
+			},
+		},
+		{
+			desc: "missing resource group",
+			cfg: &Config{
+				SubscriptionID:     "sub1",
+				ZoneResourceGroups: map[string]ZoneResourceGroup{"example.com": {}},
+			},
+			err: "example.com: missing resource group",
+		},
+		{
+			desc: "missing subscription",
+			cfg: &Config{
+				ZoneResourceGroups: map[string]ZoneResourceGroup{"example.com": {ResourceGroup: "rg1"}},
+			},
+			err: "example.com: missing subscription ID",
+		},
+		{
+			desc: "duplicate zone",
+			cfg: &Config{
+				SubscriptionID: "sub1",
+				ZoneResourceGroups: map[string]ZoneResourceGroup{
+					"example.com":  {ResourceGroup: "rg1"},
+					"example.com.": {ResourceGroup: "rg2"},
+				},
+			},
+			err: `duplicate dns zone "example.com"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			zones, err := configuredDNSZones(test.cfg)
+
+			if test.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, zones)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package bookmyname implements a DNS provider for solving the DNS-01 challenge using BookMyName.
+package bookmyname
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/bookmyname/internal"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "BOOKMYNAME_"
+
+	EnvUsername = envNamespace + "USERNAME"
+	EnvPassword = envNamespace + "PASSWORD"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                300,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+username: "your_username"     # BookMyName 账户用户名
+password: "your_password"     # BookMyName 账户密码
+propagationTimeout: 60s       # 传播超时时间
+pollingInterval: 2s           # 轮询间隔时间
+ttl: 300                      # TXT 记录的 TTL`
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for BookMyName.
+// Credentials must be passed in the environment variables:
+// BOOKMYNAME_USERNAME and BOOKMYNAME_PASSWORD.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvUsername, EnvPassword)
+	if err != nil {
+		return nil, fmt.Errorf("bookmyname: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Username = values[EnvUsername]
+	config.Password = values[EnvPassword]
+
+	return NewDNSProviderConfig(config)
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for BookMyName.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("bookmyname: the configuration of the DNS provider is nil")
+	}
+
+	if config.Username == "" || config.Password == "" {
+		return nil, errors.New("bookmyname: credentials missing")
+	}
+
+	client := internal.NewClient(config.Username, config.Password)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.client.AddRecord(context.Background(), d.newRecord(info))
+	if err != nil {
+		return fmt.Errorf("bookmyname: add record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+// The records are identified by their name and value, no record ID is kept between Present and CleanUp.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.client.RemoveRecord(context.Background(), d.newRecord(info))
+	if err != nil {
+		return fmt.Errorf("bookmyname: remove record: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) newRecord(info dns01.ChallengeInfo) internal.Record {
+	return internal.Record{
+		Hostname: dns01.UnFqdn(info.EffectiveFQDN),
+		Type:     "TXT",
+		TTL:      d.config.TTL,
+		Value:    info.Value,
+	}
+}
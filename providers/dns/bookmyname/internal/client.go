@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"lego-toolbox/providers/dns/internal/errutils"
+)
+
+const defaultBaseURL = "https://www.bookmyname.com/dyndns/"
+
+// The actions of the dyndns API.
+const (
+	actionAdd    = "add"
+	actionRemove = "remove"
+)
+
+// Client the BookMyName API client.
+type Client struct {
+	username string
+	password string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(username, password string) *Client {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	return &Client{
+		username:   username,
+		password:   password,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AddRecord adds a record.
+func (c *Client) AddRecord(ctx context.Context, record Record) error {
+	return c.do(ctx, record, actionAdd)
+}
+
+// RemoveRecord removes a record.
+func (c *Client) RemoveRecord(ctx context.Context, record Record) error {
+	return c.do(ctx, record, actionRemove)
+}
+
+func (c *Client) do(ctx context.Context, record Record, action string) error {
+	endpoint := *c.baseURL
+
+	query := endpoint.Query()
+	query.Set("hostname", record.Hostname)
+	query.Set("type", record.Type)
+	query.Set("ttl", strconv.Itoa(record.TTL))
+	query.Set("value", record.Value)
+	query.Set("do", action)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	// the API answers 200 with `good: <action> done` on success, and with a message (e.g. `notfqdn`) otherwise.
+	if !bytes.HasPrefix(raw, []byte("good: "+action+" done")) {
+		return errutils.NewAPIError(req, resp.StatusCode, raw, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T, action, response string) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(rw, fmt.Sprintf("unsupported method: %s", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			http.Error(rw, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		query := req.URL.Query()
+
+		expected := url.Values{
+			"hostname": {"_acme-challenge.example.com"},
+			"type":     {"TXT"},
+			"ttl":      {"300"},
+			"value":    {"txtTXTtxt"},
+			"do":       {action},
+		}
+
+		for key, value := range expected {
+			if query.Get(key) != value[0] {
+				http.Error(rw, fmt.Sprintf("invalid %s: %q", key, query.Get(key)), http.StatusBadRequest)
+				return
+			}
+		}
+
+		_, _ = rw.Write([]byte(response))
+	})
+
+	client := NewClient("user", "secret")
+	client.HTTPClient = server.Client()
+	client.baseURL, _ = url.Parse(server.URL + "/")
+
+	return client
+}
+
+func testRecord() Record {
+	return Record{
+		Hostname: "_acme-challenge.example.com",
+		Type:     "TXT",
+		TTL:      300,
+		Value:    "txtTXTtxt",
+	}
+}
+
+func TestClient_AddRecord(t *testing.T) {
+	client := setupTest(t, "add", "good: add done\n")
+
+	err := client.AddRecord(context.Background(), testRecord())
+	require.NoError(t, err)
+}
+
+func TestClient_AddRecord_error(t *testing.T) {
+	client := setupTest(t, "add", "notfqdn: Host _acme-challenge.example.com malformed / vhn\n")
+
+	err := client.AddRecord(context.Background(), testRecord())
+	require.EqualError(t, err, "[status code: 200] body: notfqdn: Host _acme-challenge.example.com malformed / vhn")
+}
+
+func TestClient_RemoveRecord(t *testing.T) {
+	client := setupTest(t, "remove", "good: remove done\n")
+
+	err := client.RemoveRecord(context.Background(), testRecord())
+	require.NoError(t, err)
+}
+
+func TestClient_RemoveRecord_unauthorized(t *testing.T) {
+	client := setupTest(t, "remove", "good: remove done\n")
+	client.password = "wrong"
+
+	err := client.RemoveRecord(context.Background(), testRecord())
+	require.EqualError(t, err, "unexpected status code: [status code: 401] body: invalid credentials")
+}
@@ -0,0 +1,9 @@
+package internal
+
+// Record is a DNS record of the BookMyName dyndns API.
+type Record struct {
+	Hostname string
+	Type     string
+	TTL      int
+	Value    string
+}
@@ -4,15 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/brandit/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -67,8 +68,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	records   map[string]string
-	recordsMu sync.Mutex
+	records *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for BrandIT.
@@ -89,7 +89,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +114,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:  config,
 		client:  client,
-		records: make(map[string]string),
+		records: challengestore.New[string](),
 	}, nil
 }
 
@@ -124,9 +124,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.records.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -164,16 +170,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("brandit: add record: %w", err)
 	}
 
-	d.recordsMu.Lock()
-	d.records[token] = result.Record
-	d.recordsMu.Unlock()
+	d.records.Set(token, info.EffectiveFQDN, result.Record)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -181,9 +185,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// gets the record's unique ID
-	d.recordsMu.Lock()
-	dnsRecord, ok := d.records[token]
-	d.recordsMu.Unlock()
+	dnsRecord, ok := d.records.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("brandit: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -214,9 +216,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// deletes record ID from map
-	d.recordsMu.Lock()
-	delete(d.records, token)
-	d.recordsMu.Unlock()
+	d.records.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
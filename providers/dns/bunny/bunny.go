@@ -5,12 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nrdcg/bunny-go"
+	"lego-toolbox/providers/dns/internal/credmap"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 60
@@ -19,7 +22,8 @@ const minTTL = 60
 const (
 	envNamespace = "BUNNY_"
 
-	EnvAPIKey = envNamespace + "API_KEY"
+	EnvAPIKey      = envNamespace + "API_KEY"
+	EnvZoneAPIKeys = envNamespace + "ZONE_API_KEYS"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -29,6 +33,7 @@ const (
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	ApiKey             string        `yaml:"apiKey"`
+	ZoneAPIKeys        credmap.Map   `yaml:"zoneAPIKeys"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
@@ -52,21 +57,49 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+apiKey: "your_api_key"               # API 密钥，未在 zoneAPIKeys 中列出的区域使用此密钥
+zoneAPIKeys:                         # 按区域指定的 API 密钥（可选）
+  example.com: "your_zone_api_key"
+propagationTimeout: 120s             # 传播超时时间
+pollingInterval: 2s                  # 轮询间隔时间
+ttl: 60                              # TXT 记录的生存时间（秒），最小 60`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// client uses ApiKey, nil without ApiKey.
 	client *bunny.Client
+	// clients are the clients of the API keys of the zones, by API key.
+	clients     map[string]*bunny.Client
+	zoneAPIKeys credmap.Map
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for bunny.
-// Credentials must be passed in the environment variable: BUNNY_API_KEY.
+// Credentials must be passed in the environment variable: BUNNY_API_KEY (or BUNNY_ZONE_API_KEYS).
 func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	if raw := env.GetOrFile(EnvZoneAPIKeys); raw != "" {
+		zoneAPIKeys, err := credmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("bunny: %w", err)
+		}
+
+		config.ZoneAPIKeys = zoneAPIKeys
+		config.ApiKey = env.GetOrFile(EnvAPIKey)
+
+		return NewDNSProviderConfig(config)
+	}
+
 	values, err := env.Get(EnvAPIKey)
 	if err != nil {
 		return nil, fmt.Errorf("bunny: %w", err)
 	}
 
-	config := NewDefaultConfig()
 	config.ApiKey = values[EnvAPIKey]
 
 	return NewDNSProviderConfig(config)
@@ -75,7 +108,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +121,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("bunny: the configuration of the DNS provider is nil")
 	}
 
-	if config.ApiKey == "" {
+	if config.ApiKey == "" && len(config.ZoneAPIKeys) == 0 {
 		return nil, errors.New("bunny: credentials missing")
 	}
 
@@ -96,9 +129,35 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("bunny: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
 	}
 
-	client := bunny.NewClient(config.ApiKey)
+	zoneAPIKeys, err := credmap.Validate(config.ZoneAPIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("bunny: %w", err)
+	}
+
+	provider := &DNSProvider{config: config, clients: make(map[string]*bunny.Client), zoneAPIKeys: zoneAPIKeys}
+
+	if config.ApiKey != "" {
+		provider.client = bunny.NewClient(config.ApiKey)
+	}
+
+	for _, apiKey := range zoneAPIKeys {
+		provider.clients[apiKey] = bunny.NewClient(apiKey)
+	}
+
+	return provider, nil
+}
+
+// clientFor returns the client using the API key of the zone, or ApiKey.
+func (d *DNSProvider) clientFor(zone string) (*bunny.Client, error) {
+	if apiKey, ok := d.zoneAPIKeys.Get(zone); ok {
+		return d.clients[apiKey], nil
+	}
+
+	if d.client == nil {
+		return nil, fmt.Errorf("no API key for the zone %s, check your zone API keys map", zone)
+	}
 
-	return &DNSProvider{config: config, client: client}, nil
+	return d.client, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -109,7 +168,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := getZoneName(info.EffectiveFQDN)
 	if err != nil {
@@ -118,7 +177,12 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	zone, err := d.findZone(ctx, authZone)
+	client, err := d.clientFor(authZone)
+	if err != nil {
+		return fmt.Errorf("bunny: %w", err)
+	}
+
+	zone, err := findZone(ctx, client, authZone)
 	if err != nil {
 		return fmt.Errorf("bunny: %w", err)
 	}
@@ -128,15 +192,17 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("bunny: %w", err)
 	}
 
+	// the records are created enabled: a disabled record is not served.
 	record := &bunny.AddOrUpdateDNSRecordOptions{
-		Type:  pointer(bunny.DNSRecordTypeTXT),
-		Name:  pointer(subDomain),
-		Value: pointer(info.Value),
-		TTL:   pointer(int32(d.config.TTL)),
+		Type:     pointer(bunny.DNSRecordTypeTXT),
+		Name:     pointer(subDomain),
+		Value:    pointer(info.Value),
+		TTL:      pointer(int32(d.config.TTL)),
+		Disabled: pointer(false),
 	}
 
-	if _, err := d.client.DNSZone.AddDNSRecord(ctx, deref(zone.ID), record); err != nil {
-		return fmt.Errorf("bunny: failed to add TXT record: fqdn=%s, zoneID=%d: %w", info.EffectiveFQDN, deref(zone.ID), err)
+	if _, err := client.DNSZone.AddDNSRecord(ctx, deref(zone.ID), record); err != nil {
+		return fmt.Errorf("bunny: failed to add TXT record: fqdn=%s, zoneID=%d: %w", info.EffectiveFQDN, deref(zone.ID), describeError(authZone, err))
 	}
 
 	return nil
@@ -144,7 +210,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := getZoneName(info.EffectiveFQDN)
 	if err != nil {
@@ -153,7 +219,12 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	zone, err := d.findZone(ctx, authZone)
+	client, err := d.clientFor(authZone)
+	if err != nil {
+		return fmt.Errorf("bunny: %w", err)
+	}
+
+	zone, err := findZone(ctx, client, authZone)
 	if err != nil {
 		return fmt.Errorf("bunny: %w", err)
 	}
@@ -165,7 +236,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	var record *bunny.DNSRecord
 	for _, r := range zone.Records {
-		if deref(r.Name) == subDomain && deref(r.Type) == bunny.DNSRecordTypeTXT {
+		if deref(r.Name) == subDomain && deref(r.Type) == bunny.DNSRecordTypeTXT && deref(r.Value) == info.Value {
 			r := r
 			record = &r
 			break
@@ -176,17 +247,17 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("bunny: could not find TXT record zone=%d, subdomain=%s", deref(zone.ID), subDomain)
 	}
 
-	if err := d.client.DNSZone.DeleteDNSRecord(ctx, deref(zone.ID), deref(record.ID)); err != nil {
-		return fmt.Errorf("bunny: failed to delete TXT record: id=%d, name=%s: %w", deref(record.ID), deref(record.Name), err)
+	if err := client.DNSZone.DeleteDNSRecord(ctx, deref(zone.ID), deref(record.ID)); err != nil {
+		return fmt.Errorf("bunny: failed to delete TXT record: id=%d, name=%s: %w", deref(record.ID), deref(record.Name), describeError(authZone, err))
 	}
 
 	return nil
 }
 
-func (d *DNSProvider) findZone(ctx context.Context, authZone string) (*bunny.DNSZone, error) {
-	zones, err := d.client.DNSZone.List(ctx, nil)
+func findZone(ctx context.Context, client *bunny.Client, authZone string) (*bunny.DNSZone, error) {
+	zones, err := client.DNSZone.List(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, describeError(authZone, err)
 	}
 
 	var zone *bunny.DNSZone
@@ -204,6 +275,22 @@ func (d *DNSProvider) findZone(ctx context.Context, authZone string) (*bunny.DNS
 	return zone, nil
 }
 
+// describeError explains the API errors about a zone in "Pending" DNS state:
+// the nameservers of the domain are not (yet) detected by bunny.net, the zone can't be updated.
+func describeError(zone string, err error) error {
+	var apiErr *bunny.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	if !strings.Contains(strings.ToLower(apiErr.ErrorKey+" "+apiErr.Message), "pending") {
+		return err
+	}
+
+	return fmt.Errorf("the zone %s is pending: the nameservers of the domain must be set to the bunny.net nameservers, "+
+		"and detected by bunny.net, before the records can be updated: %w", zone, err)
+}
+
 func getZoneName(fqdn string) (string, error) {
 	authZone, err := dns01.FindZoneByFqdn(fqdn)
 	if err != nil {
@@ -213,7 +300,7 @@ func getZoneName(fqdn string) (string, error) {
 	return dns01.UnFqdn(authZone), nil
 }
 
-func pointer[T string | int | int32 | int64](v T) *T { return &v }
+func pointer[T string | int | int32 | int64 | bool](v T) *T { return &v }
 
 func deref[T string | int | int32 | int64](v *T) T {
 	if v == nil {
@@ -1,16 +1,21 @@
 package bunny
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/nrdcg/bunny-go"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/credmap"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
 var envTest = tester.NewEnvTest(
-	EnvAPIKey).
+	EnvAPIKey,
+	EnvZoneAPIKeys).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -32,6 +37,19 @@ func TestNewDNSProvider(t *testing.T) {
 			},
 			expected: "bunny: some credentials information are missing: BUNNY_API_KEY",
 		},
+		{
+			desc: "zone API keys",
+			envVars: map[string]string{
+				EnvZoneAPIKeys: "example.com:123,example.org:456",
+			},
+		},
+		{
+			desc: "invalid zone API keys",
+			envVars: map[string]string{
+				EnvZoneAPIKeys: "example.com",
+			},
+			expected: `bunny: invalid credential pair: "example.com"`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -56,10 +74,11 @@ func TestNewDNSProvider(t *testing.T) {
 
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		apiKey   string
-		ttl      int
-		expected string
+		desc        string
+		apiKey      string
+		zoneAPIKeys credmap.Map
+		ttl         int
+		expected    string
 	}{
 		{
 			desc:   "success",
@@ -71,6 +90,17 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			ttl:      minTTL,
 			expected: "bunny: credentials missing",
 		},
+		{
+			desc:        "zone API keys only",
+			ttl:         minTTL,
+			zoneAPIKeys: credmap.Map{"example.com": "123"},
+		},
+		{
+			desc:        "wildcard zone",
+			ttl:         minTTL,
+			zoneAPIKeys: credmap.Map{"*.example.com": "123"},
+			expected:    `bunny: wildcard domain "*.example.com" in the credentials: use "example.com"`,
+		},
 		{
 			desc:     "invalid TTL",
 			apiKey:   "123",
@@ -83,6 +113,7 @@ func TestNewDNSProviderConfig(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			config := NewDefaultConfig()
 			config.ApiKey = test.apiKey
+			config.ZoneAPIKeys = test.zoneAPIKeys
 			config.TTL = test.ttl
 
 			p, err := NewDNSProviderConfig(config)
@@ -98,6 +129,57 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_clientFor(t *testing.T) {
+	config := NewDefaultConfig()
+	config.ZoneAPIKeys = credmap.Map{"Example.com.": "123"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err := p.clientFor("example.com")
+	require.NoError(t, err)
+	assert.Same(t, p.clients["123"], client)
+
+	_, err = p.clientFor("example.org")
+	require.EqualError(t, err, "no API key for the zone example.org, check your zone API keys map")
+
+	config.ApiKey = "456"
+
+	p, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err = p.clientFor("example.org")
+	require.NoError(t, err)
+	assert.Same(t, p.client, client)
+}
+
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+apiKey: A
+zoneAPIKeys:
+  example.com: B
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, credmap.Map{"example.com": "B"}, config.ZoneAPIKeys)
+}
+
+func Test_describeError(t *testing.T) {
+	pending := &bunny.APIError{ErrorKey: "dnszone.pending", Message: "The DNS zone is in a Pending state."}
+
+	err := describeError("example.com", pending)
+	require.ErrorIs(t, err, pending)
+	assert.ErrorContains(t, err, "the zone example.com is pending: the nameservers of the domain must be set to the bunny.net nameservers")
+
+	other := &bunny.APIError{ErrorKey: "dnszone.record.invalid", Message: "Invalid record."}
+	assert.Same(t, other, describeError("example.com", other))
+
+	plain := errors.New("boom")
+	assert.Same(t, plain, describeError("example.com", plain))
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
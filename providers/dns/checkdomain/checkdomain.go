@@ -5,14 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
 	"time"
 
-	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/nscache"
 	"lego-toolbox/providers/dns/checkdomain/internal"
+	"lego-toolbox/providers/dns/internal/oauthutil"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -26,6 +28,7 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvNSCacheTTL         = envNamespace + "NS_CACHE_TTL"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -36,7 +39,9 @@ type Config struct {
 	TTL                int           `yaml:"ttl"`
 	PropagationTimeout time.Duration `yaml:"PropagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
-	HTTPClient         *http.Client  `yaml:"-"`
+	// NSCacheTTL is the TTL of the cached domain IDs and name server checks, 5m when zero, no caching when negative.
+	NSCacheTTL time.Duration `yaml:"nsCacheTTL"`
+	HTTPClient *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -45,6 +50,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 5*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 7*time.Second),
+		NSCacheTTL:         env.GetOrDefaultSecond(EnvNSCacheTTL, nscache.DefaultTTL),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -57,6 +63,7 @@ func DefaultConfig() *Config {
 		TTL:                300,
 		PropagationTimeout: 5 * time.Minute,
 		PollingInterval:    7 * time.Second,
+		NSCacheTTL:         nscache.DefaultTTL,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -91,7 +98,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -120,12 +127,14 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("checkdomain: missing token")
 	}
 
-	client := internal.NewClient(internal.OAuthStaticAccessToken(config.HTTPClient, config.Token))
+	client := internal.NewClient(oauthutil.StaticAccessToken(config.HTTPClient, config.Token))
 
 	if config.Endpoint != nil {
 		client.BaseURL = config.Endpoint
 	}
 
+	client.SetCacheTTL(config.NSCacheTTL)
+
 	return &DNSProvider{config: config, client: client}, nil
 }
 
@@ -144,7 +153,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("checkdomain: %w", err)
 	}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err = d.client.CreateRecord(ctx, domainID, &internal.Record{
 		Name:  info.EffectiveFQDN,
@@ -174,7 +183,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("checkdomain: %w", err)
 	}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	defer d.client.CleanCache(info.EffectiveFQDN)
 
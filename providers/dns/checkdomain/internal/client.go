@@ -11,10 +11,9 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
-	"golang.org/x/oauth2"
+	"lego-toolbox/nscache"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -34,10 +33,13 @@ const maxLimit = 100
 // max integer value.
 const maxInt = int((^uint(0)) >> 1)
 
+// cacheName is the name of the caches in the nscache metrics.
+const cacheName = "checkdomain"
+
 // Client the Autodns API client.
 type Client struct {
-	domainIDMapping map[string]int
-	domainIDMu      sync.Mutex
+	domainIDs   *nscache.Cache[int]
+	nameservers *nscache.Cache[struct{}]
 
 	BaseURL    *url.URL
 	httpClient *http.Client
@@ -51,40 +53,40 @@ func NewClient(hc *http.Client) *Client {
 		hc = &http.Client{Timeout: 10 * time.Second}
 	}
 
-	return &Client{
-		BaseURL:         baseURL,
-		httpClient:      hc,
-		domainIDMapping: make(map[string]int),
+	client := &Client{
+		BaseURL:    baseURL,
+		httpClient: hc,
 	}
-}
 
-func (c *Client) GetDomainIDByName(ctx context.Context, name string) (int, error) {
-	// Load from cache if exists
-	c.domainIDMu.Lock()
-	id, ok := c.domainIDMapping[name]
-	c.domainIDMu.Unlock()
-	if ok {
-		return id, nil
-	}
+	client.SetCacheTTL(nscache.DefaultTTL)
 
-	// Find out by querying API
-	domains, err := c.listDomains(ctx)
-	if err != nil {
-		return domainNotFound, err
-	}
+	return client
+}
 
-	// Linear search over all registered domains
-	for _, domain := range domains {
-		if domain.Name == name || strings.HasSuffix(name, "."+domain.Name) {
-			c.domainIDMu.Lock()
-			c.domainIDMapping[name] = domain.ID
-			c.domainIDMu.Unlock()
+// SetCacheTTL sets the TTL of the cached domain IDs and name server checks, and empties the caches.
+// A negative TTL disables the caching.
+func (c *Client) SetCacheTTL(ttl time.Duration) {
+	c.domainIDs = nscache.New[int](cacheName, ttl)
+	c.nameservers = nscache.New[struct{}](cacheName, ttl)
+}
 
-			return domain.ID, nil
+func (c *Client) GetDomainIDByName(ctx context.Context, name string) (int, error) {
+	return c.domainIDs.Get(name, func() (int, error) {
+		// Find out by querying API
+		domains, err := c.listDomains(ctx)
+		if err != nil {
+			return domainNotFound, err
 		}
-	}
 
-	return domainNotFound, errors.New("domain not found")
+		// Linear search over all registered domains
+		for _, domain := range domains {
+			if domain.Name == name || strings.HasSuffix(name, "."+domain.Name) {
+				return domain.ID, nil
+			}
+		}
+
+		return domainNotFound, errors.New("domain not found")
+	})
 }
 
 func (c *Client) listDomains(ctx context.Context) ([]*Domain, error) {
@@ -144,7 +146,16 @@ func (c *Client) getNameserverInfo(ctx context.Context, domainID int) (*Nameserv
 	return res, nil
 }
 
+// CheckNameservers checks that the domain uses the checkdomain name servers, the successful checks are cached.
 func (c *Client) CheckNameservers(ctx context.Context, domainID int) error {
+	_, err := c.nameservers.Get(strconv.Itoa(domainID), func() (struct{}, error) {
+		return struct{}{}, c.checkNameservers(ctx, domainID)
+	})
+
+	return err
+}
+
+func (c *Client) checkNameservers(ctx context.Context, domainID int) error {
 	info, err := c.getNameserverInfo(ctx, domainID)
 	if err != nil {
 		return err
@@ -318,9 +329,7 @@ func (c *Client) do(req *http.Request, result any) error {
 }
 
 func (c *Client) CleanCache(fqdn string) {
-	c.domainIDMu.Lock()
-	delete(c.domainIDMapping, fqdn)
-	c.domainIDMu.Unlock()
+	c.domainIDs.Delete(fqdn)
 }
 
 func skipRecord(recordName, recordValue string, record *Record, nsInfo *NameserverResponse) bool {
@@ -368,16 +377,3 @@ func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, paylo
 
 	return req, nil
 }
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
-}
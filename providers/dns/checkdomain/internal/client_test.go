@@ -15,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 func setupTest(t *testing.T) (*Client, *http.ServeMux) {
@@ -24,7 +25,7 @@ func setupTest(t *testing.T) (*Client, *http.ServeMux) {
 	server := httptest.NewServer(mux)
 	t.Cleanup(server.Close)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), "secret"))
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), "secret"))
 	client.BaseURL, _ = url.Parse(server.URL)
 
 	return client, mux
@@ -259,3 +260,51 @@ func TestClient_DeleteTXTRecord(t *testing.T) {
 	err := client.DeleteTXTRecord(context.Background(), 1, info.EffectiveFQDN, recordValue)
 	require.NoError(t, err)
 }
+
+func TestClient_CheckNameservers_cached(t *testing.T) {
+	client, mux := setupTest(t)
+
+	var calls int
+
+	mux.HandleFunc("/v1/domains/1/nameservers", func(rw http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		err := json.NewEncoder(rw).Encode(NameserverResponse{Nameservers: []*Nameserver{{Name: ns1}, {Name: ns2}}})
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/v1/domains/2/nameservers", func(rw http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		err := json.NewEncoder(rw).Encode(NameserverResponse{Nameservers: []*Nameserver{{Name: "ns.fake.de"}}})
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	for range 3 {
+		err := client.CheckNameservers(context.Background(), 1)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, calls)
+
+	// The failed checks are not cached.
+	for range 2 {
+		err := client.CheckNameservers(context.Background(), 2)
+		require.EqualError(t, err, "not using checkdomain nameservers, can not update records")
+	}
+
+	assert.Equal(t, 3, calls)
+
+	client.SetCacheTTL(-1)
+
+	err := client.CheckNameservers(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, calls)
+}
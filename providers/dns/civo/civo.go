@@ -2,14 +2,17 @@
 package civo
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
+	"net/http"
 	"time"
 
-	"github.com/civo/civogo"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/civo/internal"
+	"lego-toolbox/rawrecord"
 )
 
 const (
@@ -23,27 +26,35 @@ const (
 	envNamespace = "CIVO_"
 
 	EnvAPIToken = envNamespace + "TOKEN"
+	EnvDomainID = envNamespace + "DOMAIN_ID"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	ProjectID          string        `yaml:"projectID"`
 	Token              string        `yaml:"token"`
+	DomainID           string        `yaml:"domainID"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		DomainID:           env.GetOrFile(EnvDomainID),
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, defaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, defaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
 	}
 }
 
@@ -53,13 +64,25 @@ func DefaultConfig() *Config {
 		TTL:                minTTL,
 		PropagationTimeout: defaultPropagationTimeout,
 		PollingInterval:    defaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+token: "your_token"                  # API 令牌
+domainID: ""                         # 域名 ID，设置后跳过域名查找（仅适用于单个域名）
+propagationTimeout: 300s             # 传播超时时间
+pollingInterval: 30s                 # 轮询间隔时间
+ttl: 600                             # TXT 记录的生存时间（秒），最小 600`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
-	client *civogo.Client
+	client *internal.Client
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for CIVO.
@@ -79,7 +102,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -100,10 +123,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config.TTL = minTTL
 	}
 
-	// Create a Civo client - DNS is region independent, we can use any region
-	client, err := civogo.NewClient(config.Token, "LON1")
-	if err != nil {
-		return nil, fmt.Errorf("civo: %w", err)
+	client := internal.NewClient(config.Token)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
 	}
 
 	return &DNSProvider{config: config, client: client}, nil
@@ -111,33 +134,22 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
-	if err != nil {
-		return fmt.Errorf("civo: could not find zone for domain %q: %w", domain, err)
-	}
-
-	zone := dns01.UnFqdn(authZone)
-
-	dnsDomain, err := d.client.GetDNSDomain(zone)
-	if err != nil {
-		return fmt.Errorf("civo: %w", err)
-	}
+	ctx := context.Background()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, zone)
+	domainID, subDomain, err := d.findDomain(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("civo: %w", err)
 	}
 
-	_, err = d.client.CreateDNSRecord(dnsDomain.ID, &civogo.DNSRecordConfig{
+	_, err = d.client.CreateRecord(ctx, domainID, internal.Record{
 		Name:  subDomain,
 		Value: info.Value,
-		Type:  civogo.DNSRecordTypeTXT,
+		Type:  "TXT",
 		TTL:   d.config.TTL,
 	})
 	if err != nil {
-		return fmt.Errorf("civo: %w", err)
+		return fmt.Errorf("civo: create record: %w", err)
 	}
 
 	return nil
@@ -145,44 +157,33 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	ctx := context.Background()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
-	if err != nil {
-		return fmt.Errorf("civo: could not find zone for domain %q: %w", domain, err)
-	}
-
-	zone := dns01.UnFqdn(authZone)
-
-	dnsDomain, err := d.client.GetDNSDomain(zone)
+	domainID, subDomain, err := d.findDomain(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("civo: %w", err)
 	}
 
-	dnsRecords, err := d.client.ListDNSRecords(dnsDomain.ID)
+	records, err := d.client.ListRecords(ctx, domainID)
 	if err != nil {
 		return fmt.Errorf("civo: %w", err)
 	}
 
-	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, zone)
-	if err != nil {
-		return fmt.Errorf("civo: %w", err)
-	}
+	for _, record := range records {
+		if record.Type != "TXT" || record.Name != subDomain || record.Value != info.Value {
+			continue
+		}
 
-	var dnsRecord civogo.DNSRecord
-	for _, entry := range dnsRecords {
-		if entry.Name == subDomain && entry.Value == info.Value {
-			dnsRecord = entry
-			break
+		err = d.client.DeleteRecord(ctx, domainID, record.ID)
+		if err != nil {
+			return fmt.Errorf("civo: delete record: %w", err)
 		}
-	}
 
-	_, err = d.client.DeleteDNSRecord(&dnsRecord)
-	if err != nil {
-		return fmt.Errorf("civo: %w", err)
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf("civo: no TXT record %s with the value %q in the domain %s", subDomain, info.Value, domainID)
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -190,3 +191,30 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+// findDomain returns the ID of the domain (zone) of the FQDN, and the record name in the domain.
+// With an explicit domain ID, the zone is found by a SOA query only, the domains are not listed.
+func (d *DNSProvider) findDomain(ctx context.Context, fqdn string) (string, string, error) {
+	authZone, err := dns01.FindZoneByFqdn(fqdn)
+	if err != nil {
+		return "", "", fmt.Errorf("could not find zone for %q: %w", fqdn, err)
+	}
+
+	zone := dns01.UnFqdn(authZone)
+
+	subDomain, err := dns01.ExtractSubDomain(fqdn, zone)
+	if err != nil {
+		return "", "", err
+	}
+
+	if d.config.DomainID != "" {
+		return d.config.DomainID, subDomain, nil
+	}
+
+	dnsDomain, err := d.client.GetDomain(ctx, zone)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dnsDomain.ID, subDomain, nil
+}
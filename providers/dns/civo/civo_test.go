@@ -97,6 +97,24 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+token: A
+domainID: B
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "B", config.DomainID)
+	assert.Equal(t, minTTL, config.TTL)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Same(t, config.HTTPClient, p.client.HTTPClient)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
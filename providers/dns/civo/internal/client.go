@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"lego-toolbox/providers/dns/internal/errutils"
+)
+
+const defaultBaseURL = "https://api.civo.com"
+
+// perPage is the page size of the listings.
+const perPage = 100
+
+// maxPages bounds the listings, in case the API ignores the page parameter.
+const maxPages = 1000
+
+// Client the Civo API client.
+type Client struct {
+	token string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(token string) *Client {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	return &Client{
+		token:      token,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetDomain returns the domain named name, an error if the account has no such domain.
+// https://www.civo.com/api/dns#list-domain-names
+func (c *Client) GetDomain(ctx context.Context, name string) (*Domain, error) {
+	domains, err := list[Domain](ctx, c, c.baseURL.JoinPath("v2", "dns"))
+	if err != nil {
+		return nil, fmt.Errorf("list domains: %w", err)
+	}
+
+	for _, domain := range domains {
+		if domain.Name == name {
+			return &domain, nil
+		}
+	}
+
+	return nil, fmt.Errorf("domain %q not found", name)
+}
+
+// ListRecords returns all the records of the domain.
+// https://www.civo.com/api/dns#list-dns-records
+func (c *Client) ListRecords(ctx context.Context, domainID string) ([]Record, error) {
+	records, err := list[Record](ctx, c, c.baseURL.JoinPath("v2", "dns", domainID, "records"))
+	if err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+
+	return records, nil
+}
+
+// CreateRecord creates a record in the domain.
+// https://www.civo.com/api/dns#create-a-new-dns-record
+func (c *Client) CreateRecord(ctx context.Context, domainID string, record Record) (*Record, error) {
+	endpoint := c.baseURL.JoinPath("v2", "dns", domainID, "records")
+
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, record)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Record
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteRecord deletes a record of the domain.
+// https://www.civo.com/api/dns#deleting-a-dns-record
+func (c *Client) DeleteRecord(ctx context.Context, domainID, recordID string) error {
+	endpoint := c.baseURL.JoinPath("v2", "dns", domainID, "records", recordID)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+// list fetches all the pages of a listing.
+// A paginated response gives the number of pages,
+// a bare array is the last page when it holds less than perPage items.
+func list[T any](ctx context.Context, c *Client, endpoint *url.URL) ([]T, error) {
+	var items []T
+
+	for current := 1; current <= maxPages; current++ {
+		query := endpoint.Query()
+		query.Set("page", strconv.Itoa(current))
+		query.Set("per_page", strconv.Itoa(perPage))
+		endpoint.RawQuery = query.Encode()
+
+		req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result page[T]
+
+		err = c.do(req, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, result.Items...)
+
+		if result.paginated && current >= result.Pages || !result.paginated && len(result.Items) < perPage {
+			return items, nil
+		}
+	}
+
+	return nil, fmt.Errorf("more than %d pages", maxPages)
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	req.Header.Set("Authorization", "bearer "+c.token)
+
+	return req, nil
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T) (*Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret")
+	client.baseURL, _ = url.Parse(server.URL)
+	client.HTTPClient = server.Client()
+
+	return client, mux
+}
+
+func writeJSON(t *testing.T, rw http.ResponseWriter, v any) {
+	t.Helper()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(v)
+	require.NoError(t, err)
+}
+
+func TestClient_GetDomain_paginated(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("GET /v2/dns", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, strconv.Itoa(perPage), req.URL.Query().Get("per_page"))
+
+		current, _ := strconv.Atoi(req.URL.Query().Get("page"))
+
+		writeJSON(t, rw, map[string]any{
+			"page":     current,
+			"per_page": perPage,
+			"pages":    3,
+			"items":    []Domain{{ID: fmt.Sprintf("id%d", current), Name: fmt.Sprintf("example%d.com", current)}},
+		})
+	})
+
+	domain, err := client.GetDomain(context.Background(), "example3.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Domain{ID: "id3", Name: "example3.com"}, domain)
+
+	_, err = client.GetDomain(context.Background(), "example4.com")
+	require.EqualError(t, err, `domain "example4.com" not found`)
+}
+
+func TestClient_ListRecords_array(t *testing.T) {
+	client, mux := setupTest(t)
+
+	var calls []string
+
+	mux.HandleFunc("GET /v2/dns/domainA/records", func(rw http.ResponseWriter, req *http.Request) {
+		current := req.URL.Query().Get("page")
+		calls = append(calls, current)
+
+		// a full first page, then a short one.
+		count := perPage
+		if current != "1" {
+			count = 2
+		}
+
+		records := make([]Record, count)
+		for i := range records {
+			records[i] = Record{ID: current + "-" + strconv.Itoa(i), Type: "TXT"}
+		}
+
+		writeJSON(t, rw, records)
+	})
+
+	records, err := client.ListRecords(context.Background(), "domainA")
+	require.NoError(t, err)
+
+	assert.Len(t, records, perPage+2)
+	assert.Equal(t, []string{"1", "2"}, calls)
+}
+
+func TestClient_CreateRecord(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("POST /v2/dns/domainA/records", func(rw http.ResponseWriter, req *http.Request) {
+		var record Record
+		err := json.NewDecoder(req.Body).Decode(&record)
+		require.NoError(t, err)
+
+		assert.Equal(t, Record{Name: "_acme-challenge", Value: "txt", Type: "TXT", TTL: 600}, record)
+
+		record.ID = "recordA"
+		record.DomainID = "domainA"
+
+		writeJSON(t, rw, record)
+	})
+
+	record, err := client.CreateRecord(context.Background(), "domainA", Record{Name: "_acme-challenge", Value: "txt", Type: "TXT", TTL: 600})
+	require.NoError(t, err)
+
+	assert.Equal(t, "recordA", record.ID)
+}
+
+func TestClient_DeleteRecord(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("DELETE /v2/dns/domainA/records/recordA", func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, rw, map[string]any{"result": "success"})
+	})
+
+	err := client.DeleteRecord(context.Background(), "domainA", "recordA")
+	require.NoError(t, err)
+
+	err = client.DeleteRecord(context.Background(), "domainA", "recordB")
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Domain a DNS domain (zone).
+type Domain struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Record a DNS record.
+type Record struct {
+	ID       string `json:"id,omitempty"`
+	DomainID string `json:"domain_id,omitempty"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	Priority int    `json:"priority,omitempty"`
+	TTL      int    `json:"ttl,omitempty"`
+}
+
+// page a page of a listing.
+// The API returns either a paginated object or a bare array (a single page or a page of perPage items).
+type page[T any] struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Pages   int `json:"pages"`
+	Items   []T `json:"items"`
+
+	// paginated is false when the listing is a bare array.
+	paginated bool
+}
+
+func (p *page[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err == nil {
+		*p = page[T]{Items: items}
+
+		return nil
+	}
+
+	type raw page[T]
+
+	var r raw
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("neither an array nor a page: %w", err)
+	}
+
+	*p = page[T](r)
+	p.paginated = true
+
+	return nil
+}
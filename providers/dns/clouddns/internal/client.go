@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"lego-toolbox/providers/dns/internal/errutils"
@@ -237,5 +238,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code %d] %w", resp.StatusCode, response.Error)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, response.Error).WithCode(strconv.Itoa(response.Error.Code))
 }
@@ -5,15 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/cloudflare/cloudflare-go"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 const (
@@ -61,8 +62,7 @@ type DNSProvider struct {
 	client *metaClient
 	config *Config
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Cloudflare.
@@ -106,7 +106,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +131,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		client:    client,
 		config:    config,
-		recordIDs: make(map[string]string),
+		recordIDs: challengestore.New[string](),
 	}, nil
 }
 
@@ -141,9 +141,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -167,9 +173,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("cloudflare: failed to create TXT record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = response.ID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, response.ID)
 
 	log.Infof("cloudflare: new record for %s, ID %s", domain, response.ID)
 
@@ -178,7 +182,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -191,9 +195,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// get the record's unique ID from when we created it
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("cloudflare: unknown record ID for '%s'", info.EffectiveFQDN)
 	}
@@ -204,9 +206,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// Delete record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
@@ -5,15 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
-	"github.com/go-acme/lego/v4/challenge/dns01"
-	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
-	"github.com/go-acme/lego/v4/platform/wait"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/nscache"
 	"lego-toolbox/providers/dns/cloudns/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -28,6 +28,7 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvNSCacheTTL         = envNamespace + "NS_CACHE_TTL"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -38,7 +39,9 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
-	HTTPClient         *http.Client  `yaml:"-"`
+	// NSCacheTTL is the TTL of the cached zones (name server lookup and zone check), 5m when zero, no caching when negative.
+	NSCacheTTL time.Duration `yaml:"nsCacheTTL"`
+	HTTPClient *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -47,6 +50,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 180*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
+		NSCacheTTL:         env.GetOrDefaultSecond(EnvNSCacheTTL, nscache.DefaultTTL),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -59,6 +63,7 @@ func DefaultConfig() *Config {
 		TTL:                60,
 		PropagationTimeout: 180 * time.Second,
 		PollingInterval:    10 * time.Second,
+		NSCacheTTL:         nscache.DefaultTTL,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -69,6 +74,7 @@ func DefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+	zones  *nscache.Cache[*internal.Zone]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for ClouDNS.
@@ -101,7 +107,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -121,16 +127,24 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client.HTTPClient = config.HTTPClient
 
-	return &DNSProvider{client: client, config: config}, nil
+	return &DNSProvider{
+		client: client,
+		config: config,
+		zones:  nscache.New[*internal.Zone]("cloudns", config.NSCacheTTL),
+	}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
-	ctx := context.Background()
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+// The wait for the nameserver sync is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	zone, err := d.client.GetZone(ctx, info.EffectiveFQDN)
+	zone, err := d.getZone(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("ClouDNS: %w", err)
 	}
@@ -145,11 +159,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT records matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
-	ctx := context.Background()
+// CleanUpContext removes the TXT records matching the specified parameters.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	zone, err := d.client.GetZone(ctx, info.EffectiveFQDN)
+	zone, err := d.getZone(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("ClouDNS: %w", err)
 	}
@@ -179,17 +196,30 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// getZone returns the zone of the FQDN, cached for the challenges of the same domain.
+func (d *DNSProvider) getZone(ctx context.Context, fqdn string) (*internal.Zone, error) {
+	return d.zones.Get(fqdn, func() (*internal.Zone, error) {
+		return d.client.GetZone(ctx, fqdn)
+	})
+}
+
 // waitNameservers At the time of writing 4 servers are found as authoritative, but 8 are reported during the sync.
 // If this is not done, the secondary verification done by Let's Encrypt server will fail quire a bit.
 func (d *DNSProvider) waitNameservers(ctx context.Context, domain string, zone *internal.Zone) error {
-	return wait.For("Nameserver sync on "+domain, d.config.PropagationTimeout, d.config.PollingInterval, func() (bool, error) {
+	opts := jobs.DefaultOptions("Nameserver sync on "+domain, d.config.PropagationTimeout, d.config.PollingInterval)
+
+	_, err := jobs.Poll(ctx, opts, func(ctx context.Context) (jobs.Status, error) {
 		syncProgress, err := d.client.GetUpdateStatus(ctx, zone.Name)
 		if err != nil {
-			return false, err
+			return jobs.Status{}, err
 		}
 
-		log.Infof("[%s] Sync %d/%d complete", domain, syncProgress.Updated, syncProgress.Total)
-
-		return syncProgress.Complete, nil
+		return jobs.Status{
+			Done:     syncProgress.Complete,
+			State:    "sync",
+			Progress: fmt.Sprintf("%d/%d complete", syncProgress.Updated, syncProgress.Total),
+		}, nil
 	})
+
+	return err
 }
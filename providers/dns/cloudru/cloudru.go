@@ -5,15 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/cloudru/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -70,10 +71,9 @@ func DefaultConfig() *Config {
 }
 
 type DNSProvider struct {
-	config    *Config
-	client    *internal.Client
-	records   map[string]*internal.Record
-	recordsMu sync.Mutex
+	config  *Config
+	client  *internal.Client
+	records *challengestore.Store[*internal.Record]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for cloud.ru.
@@ -96,7 +96,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -122,13 +122,19 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:  config,
 		client:  client,
-		records: make(map[string]*internal.Record),
+		records: challengestore.New[*internal.Record](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.records.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -159,20 +165,16 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("cloudru: could not create record: %w", err)
 	}
 
-	d.recordsMu.Lock()
-	d.records[token] = newRecord
-	d.recordsMu.Unlock()
+	d.records.Set(token, info.EffectiveFQDN, newRecord)
 
 	return nil
 }
 
 // CleanUp removes a given record that was generated by Present.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	d.recordsMu.Lock()
-	record, ok := d.records[token]
-	d.recordsMu.Unlock()
+	record, ok := d.records.Get(token, info.EffectiveFQDN)
 
 	if !ok {
 		return fmt.Errorf("cloudru: unknown recordID for %q", info.EffectiveFQDN)
@@ -188,9 +190,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("cloudru: %w", err)
 	}
 
-	d.recordsMu.Lock()
-	delete(d.records, token)
-	d.recordsMu.Unlock()
+	d.records.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
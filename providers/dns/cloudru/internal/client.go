@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 // Default API endpoints.
@@ -30,6 +31,9 @@ type Client struct {
 	AuthEndpoint *url.URL
 	HTTPClient   *http.Client
 
+	// TokenCache persists the token across the runs, disabled when nil.
+	TokenCache *tokencache.Cache
+
 	token   *Token
 	muToken sync.Mutex
 }
@@ -45,6 +49,7 @@ func NewClient(login, secret string) *Client {
 		APIEndpoint:  apiEndpoint,
 		AuthEndpoint: authEndpoint,
 		HTTPClient:   &http.Client{Timeout: 5 * time.Second},
+		TokenCache:   tokencache.Default(),
 	}
 }
 
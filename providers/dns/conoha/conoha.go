@@ -5,19 +5,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/conoha/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "CONOHA_"
 
+	EnvAPIVersion  = envNamespace + "API_VERSION"
 	EnvRegion      = envNamespace + "REGION"
 	EnvTenantID    = envNamespace + "TENANT_ID"
 	EnvAPIUsername = envNamespace + "API_USERNAME"
@@ -29,10 +31,21 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+// Default regions of the API versions.
+const (
+	defaultRegionV2 = "tyo1"
+	defaultRegionV3 = "c3j1"
+)
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Region             string        `yaml:"region"`
-	TenantID           string        `yaml:"tenantID"`
+	// APIVersion is the version of the identity API: v2 (former control panel) or v3 (new control panel).
+	APIVersion string `yaml:"apiVersion"`
+	// Region is the region of the endpoints, tyo1 with v2 and c3j1 with v3 when empty.
+	Region string `yaml:"region"`
+	// TenantID is the tenant ID (the project ID with v3).
+	TenantID string `yaml:"tenantID"`
+	// Username is the API username (the API user ID with v3).
 	Username           string        `yaml:"username"`
 	Password           string        `yaml:"password"`
 	TTL                int           `yaml:"ttl"`
@@ -44,7 +57,8 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		Region:             env.GetOrDefaultString(EnvRegion, "tyo1"),
+		APIVersion:         env.GetOrDefaultString(EnvAPIVersion, internal.VersionV2),
+		Region:             env.GetOrFile(EnvRegion),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -57,7 +71,7 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
-		Region:             "tyo1",
+		APIVersion:         internal.VersionV2,
 		TTL:                60,
 		PropagationTimeout: dns01.DefaultPropagationTimeout,
 		PollingInterval:    dns01.DefaultPollingInterval,
@@ -93,7 +107,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -110,33 +124,53 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("conoha: some credentials information are missing")
 	}
 
-	identifier, err := internal.NewIdentifier(config.Region)
+	region := config.Region
+
+	switch config.APIVersion {
+	case "", internal.VersionV2:
+		if region == "" {
+			region = defaultRegionV2
+		}
+	case internal.VersionV3:
+		if region == "" {
+			region = defaultRegionV3
+		}
+	default:
+		return nil, fmt.Errorf("conoha: unsupported API version %q, use %s or %s", config.APIVersion, internal.VersionV2, internal.VersionV3)
+	}
+
+	identifier, err := internal.NewIdentifier(region)
 	if err != nil {
 		return nil, fmt.Errorf("conoha: failed to create identity client: %w", err)
 	}
 
+	identifier.Version = config.APIVersion
+
 	if config.HTTPClient != nil {
 		identifier.HTTPClient = config.HTTPClient
 	}
 
-	auth := internal.Auth{
+	credentials := internal.Credentials{
 		TenantID: config.TenantID,
-		PasswordCredentials: internal.PasswordCredentials{
-			Username: config.Username,
-			Password: config.Password,
-		},
+		Username: config.Username,
+		Password: config.Password,
 	}
 
-	tokens, err := identifier.GetToken(context.TODO(), auth)
+	// logs in now to report the invalid credentials early, the token is renewed on expiry.
+	_, err = identifier.Token(context.TODO(), credentials)
 	if err != nil {
 		return nil, fmt.Errorf("conoha: failed to log in: %w", err)
 	}
 
-	client, err := internal.NewClient(config.Region, tokens.Access.Token.ID)
+	client, err := internal.NewClient(region, "")
 	if err != nil {
 		return nil, fmt.Errorf("conoha: failed to create client: %w", err)
 	}
 
+	client.TokenSource = func(ctx context.Context) (string, error) {
+		return identifier.Token(ctx, credentials)
+	}
+
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
 	}
@@ -146,7 +180,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -177,7 +211,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp clears ConoHa DNS TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
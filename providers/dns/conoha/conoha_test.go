@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,12 +92,21 @@ func TestNewDNSProvider(t *testing.T) {
 
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		expected string
-		tenant   string
-		username string
-		password string
+		desc       string
+		expected   string
+		apiVersion string
+		tenant     string
+		username   string
+		password   string
 	}{
+		{
+			desc:       "unsupported API version",
+			expected:   `conoha: unsupported API version "v1", use v2 or v3`,
+			apiVersion: "v1",
+			tenant:     "tenant_id",
+			username:   "api_username",
+			password:   "api_password",
+		},
 		{
 			desc:     "complete credentials, but login failed",
 			expected: `conoha: failed to log in: unexpected status code: [status code: 401] body: {"unauthorized":{"message":"Invalid user: api_username","code":401}}`,
@@ -131,6 +141,10 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
 			config := NewDefaultConfig()
+			if test.apiVersion != "" {
+				config.APIVersion = test.apiVersion
+			}
+
 			config.TenantID = test.tenant
 			config.Username = test.username
 			config.Password = test.password
@@ -149,6 +163,15 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("apiVersion: v3\ntenantID: T\nusername: U\npassword: P\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "v3", config.APIVersion)
+	assert.Empty(t, config.Region)
+	assert.Equal(t, 60, config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -20,6 +20,9 @@ const dnsServiceBaseURL = "https://dns-service.%s.conoha.io"
 type Client struct {
 	token string
 
+	// TokenSource returns the token of the requests, instead of the static token, e.g. Identifier.Token.
+	TokenSource func(ctx context.Context) (string, error)
+
 	baseURL    *url.URL
 	HTTPClient *http.Client
 }
@@ -47,7 +50,7 @@ func (c *Client) GetDomainID(ctx context.Context, domainName string) (string, er
 
 	for _, domain := range domainList.Domains {
 		if domain.Name == domainName {
-			return domain.ID, nil
+			return domain.identifier(), nil
 		}
 	}
 
@@ -82,7 +85,7 @@ func (c *Client) GetRecordID(ctx context.Context, domainID, recordName, recordTy
 
 	for _, record := range recordList.Records {
 		if record.Name == recordName && record.Type == recordType && record.Data == data {
-			return record.ID, nil
+			return record.identifier(), nil
 		}
 	}
 
@@ -146,8 +149,19 @@ func (c *Client) DeleteRecord(ctx context.Context, domainID, recordID string) er
 }
 
 func (c *Client) do(req *http.Request, result any) error {
-	if c.token != "" {
-		req.Header.Set("X-Auth-Token", c.token)
+	token := c.token
+
+	if c.TokenSource != nil {
+		var err error
+
+		token, err = c.TokenSource(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to log in: %w", err)
+		}
+	}
+
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
 	}
 
 	resp, err := c.HTTPClient.Do(req)
@@ -157,7 +171,8 @@ func (c *Client) do(req *http.Request, result any) error {
 
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
+	// the API of the new control panel answers 201 Created and 204 No Content.
+	if resp.StatusCode/100 != 2 {
 		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
 	}
 
@@ -210,3 +210,39 @@ func TestClient_DeleteRecord(t *testing.T) {
 	err := client.DeleteRecord(context.Background(), "89acac79-38e7-497d-807c-a011e1310438", "2e32e609-3a4f-45ba-bdef-e50eacd345ad")
 	require.NoError(t, err)
 }
+
+func TestClient_newControlPanel(t *testing.T) {
+	client, mux := setupTest(t)
+
+	client.TokenSource = func(context.Context) (string, error) {
+		return "v3token", nil
+	}
+
+	mux.HandleFunc("GET /v1/domains", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Auth-Token") != "v3token" {
+			http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = fmt.Fprint(rw, `{"domains":[{"uuid":"d1","name":"example.com."}]}`)
+	})
+
+	mux.HandleFunc("GET /v1/domains/d1/records", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"records":[{"uuid":"r1","name":"_acme-challenge.example.com.","type":"TXT","data":"value","ttl":60}]}`)
+	})
+
+	mux.HandleFunc("DELETE /v1/domains/d1/records/r1", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	domainID, err := client.GetDomainID(context.Background(), "example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "d1", domainID)
+
+	recordID, err := client.GetRecordID(context.Background(), domainID, "_acme-challenge.example.com.", "TXT", "value")
+	require.NoError(t, err)
+	assert.Equal(t, "r1", recordID)
+
+	err = client.DeleteRecord(context.Background(), domainID, recordID)
+	require.NoError(t, err)
+}
@@ -3,20 +3,55 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 const identityBaseURL = "https://identity.%s.conoha.io"
 
+// Versions of the identity API.
+const (
+	// VersionV2 is the identity API of the former control panel (regions tyo1, tyo2, sin1, sjc1).
+	VersionV2 = "v2"
+	// VersionV3 is the identity API of the new control panel (region c3j1).
+	VersionV3 = "v3"
+)
+
+// tokenExpiryMargin is the remaining validity under which a token is renewed,
+// so a token does not expire during a challenge.
+const tokenExpiryMargin = 5 * time.Minute
+
+// Credentials are the credentials of an API user.
+type Credentials struct {
+	// TenantID is the tenant ID (the project ID with v3).
+	TenantID string
+	// Username is the API username (the API user ID with v3).
+	Username string
+	Password string
+}
+
 type Identifier struct {
 	baseURL    *url.URL
 	HTTPClient *http.Client
+
+	// Version is the version of the identity API, VersionV2 when empty.
+	Version string
+
+	// TokenCache persists the token across the runs, disabled when nil.
+	TokenCache *tokencache.Cache
+
+	token          string
+	tokenExpiresAt time.Time
+	muToken        sync.Mutex
 }
 
 // NewIdentifier creates a new Identifier.
@@ -29,10 +64,86 @@ func NewIdentifier(region string) (*Identifier, error) {
 	return &Identifier{
 		baseURL:    baseURL,
 		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		TokenCache: tokencache.Default(),
 	}, nil
 }
 
-// GetToken gets valid token information.
+// Token returns a valid token for the credentials.
+// The token is cached until its expiry: Token does not authenticate again while it is valid,
+// nor in the next runs when the token cache is enabled (see tokencache.Default).
+func (c *Identifier) Token(ctx context.Context, credentials Credentials) (string, error) {
+	c.muToken.Lock()
+	defer c.muToken.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenExpiryMargin).Before(c.tokenExpiresAt) {
+		return c.token, nil
+	}
+
+	key := tokencache.Key("conoha", c.Version, c.baseURL.String(), credentials.TenantID, credentials.Username, credentials.Password)
+
+	// the token of a previous run.
+	var cached string
+	if expiresAt, ok := c.TokenCache.Get(key, &cached); ok {
+		c.token = cached
+		c.tokenExpiresAt = expiresAt
+
+		return cached, nil
+	}
+
+	var (
+		token   string
+		expires string
+	)
+
+	switch c.Version {
+	case "", VersionV2:
+		identity, err := c.GetToken(ctx, Auth{
+			TenantID: credentials.TenantID,
+			PasswordCredentials: PasswordCredentials{
+				Username: credentials.Username,
+				Password: credentials.Password,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		token, expires = identity.Access.Token.ID, identity.Access.Token.Expires
+
+	case VersionV3:
+		identity, err := c.GetTokenV3(ctx, AuthV3{
+			Identity: IdentityV3{
+				Methods: []string{"password"},
+				Password: PasswordV3{
+					User: UserV3{ID: credentials.Username, Password: credentials.Password},
+				},
+			},
+			Scope: ScopeV3{Project: ProjectV3{ID: credentials.TenantID}},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		token, expires = identity.Token.ID, identity.Token.ExpiresAt
+
+	default:
+		return "", fmt.Errorf("unsupported identity API version %q", c.Version)
+	}
+
+	c.token = token
+
+	// a token without a known expiry is not cached.
+	c.tokenExpiresAt, _ = time.Parse(time.RFC3339Nano, expires)
+
+	err := c.TokenCache.Set(key, token, c.tokenExpiresAt)
+	if err != nil {
+		log.Warnf("conoha: %v", err)
+	}
+
+	return token, nil
+}
+
+// GetToken gets valid token information, with the v2 identity API.
 // https://www.conoha.jp/docs/identity-post_tokens.php
 func (c *Identifier) GetToken(ctx context.Context, auth Auth) (*IdentityResponse, error) {
 	endpoint := c.baseURL.JoinPath("v2.0", "tokens")
@@ -52,31 +163,62 @@ func (c *Identifier) GetToken(ctx context.Context, auth Auth) (*IdentityResponse
 	return identity, nil
 }
 
+// GetTokenV3 gets valid token information, with the v3 identity API.
+// https://doc.conoha.jp/reference/api-vps3/api-identity-vps3/identity-post_tokens-v3/
+func (c *Identifier) GetTokenV3(ctx context.Context, auth AuthV3) (*IdentityV3Response, error) {
+	endpoint := c.baseURL.JoinPath("v3", "auth", "tokens")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, &IdentityV3Request{Auth: auth})
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &IdentityV3Response{}
+
+	resp, err := c.doResponse(req, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	identity.Token.ID = resp.Header.Get("X-Subject-Token")
+	if identity.Token.ID == "" {
+		return nil, errors.New("unable to get auth token")
+	}
+
+	return identity, nil
+}
+
 func (c *Identifier) do(req *http.Request, result any) error {
+	_, err := c.doResponse(req, result)
+
+	return err
+}
+
+func (c *Identifier) doResponse(req *http.Request, result any) (*http.Response, error) {
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return errutils.NewHTTPDoError(req, err)
+		return nil, errutils.NewHTTPDoError(req, err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+	if resp.StatusCode/100 != 2 {
+		return nil, errutils.NewUnexpectedResponseStatusCodeError(req, resp)
 	}
 
 	if result == nil {
-		return nil
+		return resp, nil
 	}
 
 	raw, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+		return nil, errutils.NewReadResponseError(req, resp.StatusCode, err)
 	}
 
 	err = json.Unmarshal(raw, result)
 	if err != nil {
-		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+		return nil, errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
 	}
 
-	return nil
+	return resp, nil
 }
@@ -1,14 +1,20 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 func TestNewClient(t *testing.T) {
@@ -35,7 +41,135 @@ func TestNewClient(t *testing.T) {
 	token, err := identifier.GetToken(context.Background(), auth)
 	require.NoError(t, err)
 
-	expected := &IdentityResponse{Access: Access{Token: Token{ID: "sample00d88246078f2bexample788f7"}}}
+	expected := &IdentityResponse{Access: Access{Token: Token{ID: "sample00d88246078f2bexample788f7", Expires: "2015-05-20T07:08:21Z"}}}
 
 	assert.Equal(t, expected, token)
 }
+
+func TestIdentifier_GetTokenV3(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	identifier, err := NewIdentifier("c3j1")
+	require.NoError(t, err)
+
+	identifier.HTTPClient = server.Client()
+	identifier.baseURL, _ = url.Parse(server.URL)
+
+	mux.HandleFunc("POST /v3/auth/tokens", func(rw http.ResponseWriter, req *http.Request) {
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expected := `{"auth":{"identity":{"methods":["password"],"password":{"user":{"id":"user_id","password":"secret"}}},"scope":{"project":{"id":"tenant_id"}}}}`
+		if string(bytes.TrimSpace(raw)) != expected {
+			http.Error(rw, fmt.Sprintf("invalid request body: %s", raw), http.StatusBadRequest)
+			return
+		}
+
+		rw.Header().Set("X-Subject-Token", "v3token")
+		rw.WriteHeader(http.StatusCreated)
+		writeFixture(rw, "tokens_v3_POST.json")
+	})
+
+	identity, err := identifier.GetTokenV3(context.Background(), AuthV3{
+		Identity: IdentityV3{
+			Methods:  []string{"password"},
+			Password: PasswordV3{User: UserV3{ID: "user_id", Password: "secret"}},
+		},
+		Scope: ScopeV3{Project: ProjectV3{ID: "tenant_id"}},
+	})
+	require.NoError(t, err)
+
+	expected := &IdentityV3Response{Token: TokenV3{ID: "v3token", ExpiresAt: "2099-01-01T00:00:00.000000Z"}}
+
+	assert.Equal(t, expected, identity)
+}
+
+func TestIdentifier_Token(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		version string
+		pattern string
+		handler http.HandlerFunc
+	}{
+		{
+			desc:    "v2",
+			version: VersionV2,
+			pattern: "POST /v2.0/tokens",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprint(rw, `{"access":{"token":{"id":"token","expires":"2099-01-01T00:00:00Z"}}}`)
+			},
+		},
+		{
+			desc:    "v3",
+			version: VersionV3,
+			pattern: "POST /v3/auth/tokens",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("X-Subject-Token", "token")
+				rw.WriteHeader(http.StatusCreated)
+				writeFixture(rw, "tokens_v3_POST.json")
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			var calls int
+
+			mux.HandleFunc(test.pattern, func(rw http.ResponseWriter, req *http.Request) {
+				calls++
+
+				test.handler(rw, req)
+			})
+
+			cache, err := tokencache.New(filepath.Join(t.TempDir(), "tokens"), bytes.Repeat([]byte{1}, 32))
+			require.NoError(t, err)
+
+			newIdentifier := func() *Identifier {
+				identifier, err := NewIdentifier("c3j1")
+				require.NoError(t, err)
+
+				identifier.HTTPClient = server.Client()
+				identifier.baseURL, _ = url.Parse(server.URL)
+				identifier.Version = test.version
+				identifier.TokenCache = cache
+
+				return identifier
+			}
+
+			credentials := Credentials{TenantID: "tenant_id", Username: "user_id", Password: "secret"}
+
+			identifier := newIdentifier()
+
+			for range 2 {
+				token, err := identifier.Token(context.Background(), credentials)
+				require.NoError(t, err)
+				assert.Equal(t, "token", token)
+			}
+
+			// the next run.
+			token, err := newIdentifier().Token(context.Background(), credentials)
+			require.NoError(t, err)
+			assert.Equal(t, "token", token)
+
+			assert.Equal(t, 1, calls)
+
+			// an expiring token is renewed.
+			identifier.tokenExpiresAt = time.Now()
+			require.NoError(t, cache.Delete(tokencache.Key("conoha", test.version, server.URL, "tenant_id", "user_id", "secret")))
+
+			_, err = identifier.Token(context.Background(), credentials)
+			require.NoError(t, err)
+
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
@@ -17,6 +17,56 @@ type PasswordCredentials struct {
 	Password string `json:"password"`
 }
 
+// IdentityV3Request is an authentication request body of the v3 identity API.
+type IdentityV3Request struct {
+	Auth AuthV3 `json:"auth"`
+}
+
+// AuthV3 is an authentication information of the v3 identity API.
+type AuthV3 struct {
+	Identity IdentityV3 `json:"identity"`
+	Scope    ScopeV3    `json:"scope"`
+}
+
+// IdentityV3 is the identity of the API user.
+type IdentityV3 struct {
+	Methods  []string   `json:"methods"`
+	Password PasswordV3 `json:"password"`
+}
+
+// PasswordV3 is the password method of the identity.
+type PasswordV3 struct {
+	User UserV3 `json:"user"`
+}
+
+// UserV3 is API-user's credentials.
+type UserV3 struct {
+	ID       string `json:"id"`
+	Password string `json:"password"`
+}
+
+// ScopeV3 is the scope of the token.
+type ScopeV3 struct {
+	Project ProjectV3 `json:"project"`
+}
+
+// ProjectV3 is the project (tenant) of the token.
+type ProjectV3 struct {
+	ID string `json:"id"`
+}
+
+// IdentityV3Response is an authentication response body of the v3 identity API.
+type IdentityV3Response struct {
+	Token TokenV3 `json:"token"`
+}
+
+// TokenV3 is an api access token of the v3 identity API.
+type TokenV3 struct {
+	// ID is the token, from the X-Subject-Token header.
+	ID        string `json:"-"`
+	ExpiresAt string `json:"expires_at"`
+}
+
 // IdentityResponse is an authentication response body.
 type IdentityResponse struct {
 	Access Access `json:"access"`
@@ -29,7 +79,8 @@ type Access struct {
 
 // Token is an api access token.
 type Token struct {
-	ID string `json:"id"`
+	ID      string `json:"id"`
+	Expires string `json:"expires,omitempty"`
 }
 
 // DomainListResponse is a response of a domain listing request.
@@ -38,21 +89,41 @@ type DomainListResponse struct {
 }
 
 // Domain is a hosted domain entry.
+// The v1 API of the former control panel identifies the domains by id, the API of the new control panel by uuid.
 type Domain struct {
-	ID   string `json:"id"`
+	ID   string `json:"id,omitempty"`
+	UUID string `json:"uuid,omitempty"`
 	Name string `json:"name"`
 }
 
+func (d Domain) identifier() string {
+	if d.ID != "" {
+		return d.ID
+	}
+
+	return d.UUID
+}
+
 // RecordListResponse is a response of record listing request.
 type RecordListResponse struct {
 	Records []Record `json:"records"`
 }
 
 // Record is a record entry.
+// Like the domains, the records are identified by id or uuid.
 type Record struct {
 	ID   string `json:"id,omitempty"`
+	UUID string `json:"uuid,omitempty"`
 	Name string `json:"name"`
 	Type string `json:"type"`
 	Data string `json:"data"`
 	TTL  int    `json:"ttl"`
 }
+
+func (r Record) identifier() string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return r.UUID
+}
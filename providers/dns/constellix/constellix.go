@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"slices"
 	"strconv"
@@ -15,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/hashicorp/go-retryablehttp"
 	"lego-toolbox/providers/dns/constellix/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -28,8 +29,12 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvNotFoundRetryDelay = envNamespace + "NOT_FOUND_RETRY_DELAY"
 )
 
+// defaultNotFoundRetryDelay is the delay before retrying a request on a record answered by a 404.
+const defaultNotFoundRetryDelay = 2 * time.Second
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	ApiKey             string        `yaml:"apiKey"`
@@ -37,6 +42,9 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	// NotFoundRetryDelay is the delay before retrying a read of a record answered by a 404
+	// (eventual consistency of the API after a creation), no retry when zero.
+	NotFoundRetryDelay time.Duration `yaml:"notFoundRetryDelay"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -46,6 +54,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
+		NotFoundRetryDelay: env.GetOrDefaultSecond(EnvNotFoundRetryDelay, defaultNotFoundRetryDelay),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -58,12 +67,23 @@ func DefaultConfig() *Config {
 		TTL:                60,
 		PropagationTimeout: dns01.DefaultPropagationTimeout,
 		PollingInterval:    10 * time.Second,
+		NotFoundRetryDelay: defaultNotFoundRetryDelay,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+apiKey: "your_api_key"               # API 密钥
+secretKey: "your_secret_key"         # 秘密密钥
+propagationTimeout: 60s              # 传播超时时间
+pollingInterval: 10s                 # 轮询间隔时间
+ttl: 60                              # TXT 记录的生存时间（秒）
+notFoundRetryDelay: 2s               # 记录创建后查询返回 404（最终一致性）时的重试间隔，最多重试 5 次；为 0 时不重试`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -89,7 +109,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +137,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	retryClient.Backoff = backoff
 
 	client := internal.NewClient(retryClient.StandardClient())
+	client.NotFoundDelay = config.NotFoundRetryDelay
 
 	return &DNSProvider{config: config, client: client}, nil
 }
@@ -129,7 +150,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -172,7 +193,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
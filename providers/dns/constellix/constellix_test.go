@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -123,6 +124,21 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("apiKey: A\nsecretKey: B\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultNotFoundRetryDelay, config.NotFoundRetryDelay)
+
+	config, err = ParseConfig([]byte("apiKey: A\nsecretKey: B\nnotFoundRetryDelay: 5s\n"))
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, p.client.NotFoundDelay)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,11 +17,19 @@ const (
 	defaultVersion = "v1"
 )
 
+// maxNotFoundRetries bounds the retries of a request answered by a 404, see Client.NotFoundDelay.
+const maxNotFoundRetries = 5
+
 // Client the Constellix client.
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 
+	// NotFoundDelay is the delay before retrying a request on a record answered by a 404 (at most maxNotFoundRetries times):
+	// the API is eventually consistent, a record is often not found right after its creation.
+	// The requests are not retried when zero.
+	NotFoundDelay time.Duration
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for communicating with the API
@@ -79,6 +88,31 @@ func (c *Client) do(req *http.Request, result any) error {
 	return nil
 }
 
+// doRetryNotFound sends an API request on a record, retrying it while the API answers 404 (see NotFoundDelay).
+func (c *Client) doRetryNotFound(req *http.Request, result any) error {
+	for attempt := 1; ; attempt++ {
+		err := c.do(req, result)
+
+		var nf *NotFound
+		if c.NotFoundDelay <= 0 || attempt > maxNotFoundRetries || !errors.As(err, &nf) {
+			return err
+		}
+
+		select {
+		case <-req.Context().Done():
+			return errors.Join(err, req.Context().Err())
+		case <-time.After(c.NotFoundDelay):
+		}
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return fmt.Errorf("unable to reset request body: %w", err)
+			}
+		}
+	}
+}
+
 func (c *Client) createEndpoint(fragment ...string) (string, error) {
 	return url.JoinPath(c.BaseURL, fragment...)
 }
@@ -89,22 +123,22 @@ func checkResponse(resp *http.Response) error {
 	}
 
 	raw, err := io.ReadAll(resp.Body)
-	if err == nil && raw != nil {
-		errAPI := &APIError{StatusCode: resp.StatusCode}
+	if err != nil {
+		return errutils.NewReadResponseError(resp.Request, resp.StatusCode, err)
+	}
 
-		if json.Unmarshal(raw, errAPI) != nil {
-			return fmt.Errorf("API error: status code: %d: %v", resp.StatusCode, string(raw))
-		}
+	errAPI := &APIError{StatusCode: resp.StatusCode}
 
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return &NotFound{APIError: errAPI}
-		case http.StatusBadRequest:
-			return &BadRequest{APIError: errAPI}
-		default:
-			return errAPI
-		}
+	if json.Unmarshal(raw, errAPI) != nil {
+		return errutils.NewUnexpectedStatusCodeError(resp.Request, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("API error, status code: %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return &NotFound{APIError: errAPI}
+	case http.StatusBadRequest:
+		return &BadRequest{APIError: errAPI}
+	default:
+		return errAPI
+	}
 }
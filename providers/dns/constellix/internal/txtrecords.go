@@ -11,6 +11,7 @@ import (
 )
 
 // TxtRecordService API access to Record.
+// The requests on a record (Get, Update, Delete) are retried on a 404, see Client.NotFoundDelay.
 type TxtRecordService service
 
 // Create a TXT record.
@@ -76,7 +77,7 @@ func (s *TxtRecordService) Get(ctx context.Context, domainID, recordID int64) (*
 	}
 
 	var records Record
-	err = s.client.do(req, &records)
+	err = s.client.doRetryNotFound(req, &records)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +104,7 @@ func (s *TxtRecordService) Update(ctx context.Context, domainID, recordID int64,
 	}
 
 	var msg SuccessMessage
-	err = s.client.do(req, &msg)
+	err = s.client.doRetryNotFound(req, &msg)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +126,7 @@ func (s *TxtRecordService) Delete(ctx context.Context, domainID, recordID int64)
 	}
 
 	var msg *SuccessMessage
-	err = s.client.do(req, &msg)
+	err = s.client.doRetryNotFound(req, &msg)
 	if err != nil {
 		return nil, err
 	}
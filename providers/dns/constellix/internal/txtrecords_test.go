@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -130,6 +131,75 @@ func TestTxtRecordService_Get(t *testing.T) {
 	assert.Equal(t, expected, record)
 }
 
+func TestTxtRecordService_Get_retryNotFound(t *testing.T) {
+	client, mux := setupTest(t)
+	client.NotFoundDelay = time.Millisecond
+
+	var calls int
+
+	mux.HandleFunc("/v1/domains/12345/records/txt/6789", func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		// eventual consistency: the record is found at the third attempt.
+		if calls < 3 {
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"errors":["Record not found"]}`))
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"id":6789,"name":"test"}`))
+	})
+
+	record, err := client.TxtRecords.Get(context.Background(), 12345, 6789)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(6789), record.ID)
+	assert.Equal(t, 3, calls)
+}
+
+func TestTxtRecordService_Update_retryNotFound_bounded(t *testing.T) {
+	client, mux := setupTest(t)
+	client.NotFoundDelay = time.Millisecond
+
+	var calls int
+
+	mux.HandleFunc("/v1/domains/12345/records/txt/6789", func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"test","ttl":60}`, string(body))
+
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte(`{"errors":["Record not found"]}`))
+	})
+
+	_, err := client.TxtRecords.Update(context.Background(), 12345, 6789, RecordRequest{Name: "test", TTL: 60})
+
+	var nf *NotFound
+	require.ErrorAs(t, err, &nf)
+
+	assert.Equal(t, maxNotFoundRetries+1, calls)
+}
+
+func TestTxtRecordService_Get_noRetry(t *testing.T) {
+	client, mux := setupTest(t)
+
+	var calls int
+
+	mux.HandleFunc("/v1/domains/12345/records/txt/6789", func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte(`{"errors":["Record not found"]}`))
+	})
+
+	_, err := client.TxtRecords.Get(context.Background(), 12345, 6789)
+	require.Error(t, err)
+
+	assert.Equal(t, 1, calls)
+}
+
 func TestTxtRecordService_Update(t *testing.T) {
 	client, mux := setupTest(t)
 
@@ -6,8 +6,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 	"time"
@@ -17,6 +17,8 @@ import (
 	"lego-toolbox/providers/dns/cpanel/internal/cpanel"
 	"lego-toolbox/providers/dns/cpanel/internal/shared"
 	"lego-toolbox/providers/dns/cpanel/internal/whm"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 // Environment variables names.
@@ -34,6 +36,26 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+// API modes.
+const (
+	// ModeCPanel uses the cPanel UAPI (`/execute`) with a cPanel account token, usually on port 2083.
+	ModeCPanel = "cpanel"
+	// ModeWHM uses the WHM API 1 (`/json-api`) with a WHM token (root or reseller), usually on port 2087.
+	ModeWHM = "whm"
+)
+
+// modePorts are the default ports of the cPanel and WHM services (TLS and plain).
+var modePorts = map[string][]string{
+	ModeCPanel: {"2083", "2082"},
+	ModeWHM:    {"2087", "2086"},
+}
+
+// modePaths are the API paths appended to the base URL.
+var modePaths = map[string]string{
+	ModeCPanel: "/execute",
+	ModeWHM:    "/json-api",
+}
+
 type apiClient interface {
 	FetchZoneInformation(ctx context.Context, domain string) ([]shared.ZoneRecord, error)
 	AddRecord(ctx context.Context, serial uint32, domain string, record shared.Record) (*shared.ZoneSerial, error)
@@ -43,6 +65,7 @@ type apiClient interface {
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
+	// Mode selects the API: ModeCPanel (UAPI token) or ModeWHM (WHM API token).
 	Mode               string        `yaml:"mode"`
 	Username           string        `yaml:"username"`
 	Token              string        `yaml:"token"`
@@ -56,7 +79,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		Mode:               env.GetOrDefaultString(EnvMode, "cpanel"),
+		Mode:               env.GetOrDefaultString(EnvMode, ModeCPanel),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -69,7 +92,7 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
-		Mode:               "cpanel",
+		Mode:               ModeCPanel,
 		TTL:                300,
 		PropagationTimeout: 2 * time.Minute,
 		PollingInterval:    dns01.DefaultPollingInterval,
@@ -79,6 +102,17 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# config.yaml
+mode: "cpanel"                        # API 模式：cpanel（cPanel UAPI 令牌，端口 2083）或 whm（WHM API 令牌，端口 2087）
+username: "your_username"             # cPanel 账号，WHM 模式下为 root 或代理商账号
+token: "your_api_token"               # API 令牌，需与模式对应
+baseURL: "https://example.com:2083"   # 服务器地址，不包含 API 路径
+ttl: 300                              # TXT 记录的 TTL
+propagationTimeout: 2m                # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -103,15 +137,66 @@ func NewDNSProvider() (*DNSProvider, error) {
 }
 
 // ParseConfig parse bytes to config
+// The mode, credentials and base URL are validated together.
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
+
+	err = config.validate()
+	if err != nil {
+		return nil, fmt.Errorf("cpanel: %w", err)
+	}
+
 	return config, nil
 }
 
+func (c *Config) validate() error {
+	mode := strings.ToLower(c.Mode)
+	if _, ok := modePaths[mode]; !ok {
+		return fmt.Errorf("unsupported mode: %q, must be %q or %q", c.Mode, ModeCPanel, ModeWHM)
+	}
+
+	if c.Username == "" || c.Token == "" {
+		return fmt.Errorf("mode %s: missing username and/or token", mode)
+	}
+
+	if c.BaseURL == "" {
+		return fmt.Errorf("mode %s: missing base URL", mode)
+	}
+
+	return checkBaseURL(mode, c.BaseURL)
+}
+
+// checkBaseURL rejects the base URLs pointing to the service of the other mode,
+// a UAPI token is refused by WHM and conversely.
+func checkBaseURL(mode, baseURL string) error {
+	endpoint, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("invalid base URL %q: the scheme must be http or https", baseURL)
+	}
+
+	for other, path := range modePaths {
+		if strings.HasSuffix(strings.TrimSuffix(endpoint.Path, "/"), path) {
+			return fmt.Errorf("invalid base URL %q: the API path %s is added by the %s mode", baseURL, path, other)
+		}
+	}
+
+	for other, ports := range modePorts {
+		if other != mode && slices.Contains(ports, endpoint.Port()) {
+			return fmt.Errorf("mode %s: the base URL %q uses the port of %s, set mode: %s or fix the port", mode, baseURL, other, other)
+		}
+	}
+
+	return nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for CPanel.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -126,6 +211,13 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("cpanel: server information are missing")
 	}
 
+	if _, ok := modePaths[strings.ToLower(config.Mode)]; ok {
+		err := checkBaseURL(strings.ToLower(config.Mode), config.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("cpanel: %w", err)
+		}
+	}
+
 	client, err := createClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("cpanel: create client error: %w", err)
@@ -146,11 +238,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("arvancloud: could not find zone for domain %q: %w", domain, err)
+		return fmt.Errorf("cpanel: could not find zone for domain %q: %w", domain, err)
 	}
 
 	zone := dns01.UnFqdn(authZone)
@@ -220,11 +312,11 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("arvancloud: could not find zone for domain %q: %w", domain, err)
+		return fmt.Errorf("cpanel: could not find zone for domain %q: %w", domain, err)
 	}
 
 	zone := dns01.UnFqdn(authZone)
@@ -325,7 +417,7 @@ func getZoneSerial(zoneFqdn string, zoneInfo []shared.ZoneRecord) (uint32, error
 
 func createClient(config *Config) (apiClient, error) {
 	switch strings.ToLower(config.Mode) {
-	case "cpanel":
+	case ModeCPanel:
 		client, err := cpanel.NewClient(config.BaseURL, config.Username, config.Token)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create cPanel API client: %w", err)
@@ -337,7 +429,7 @@ func createClient(config *Config) (apiClient, error) {
 
 		return client, nil
 
-	case "whm":
+	case ModeWHM:
 		client, err := whm.NewClient(config.BaseURL, config.Username, config.Token)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create WHM API client: %w", err)
@@ -172,6 +172,71 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{
+			desc: "cpanel",
+			raw:  "username: user\ntoken: secret\nbaseURL: https://example.com:2083\n",
+		},
+		{
+			desc: "whm",
+			raw:  "mode: whm\nusername: root\ntoken: secret\nbaseURL: https://example.com:2087\n",
+		},
+		{
+			desc:     "invalid mode",
+			raw:      "mode: uapi\nusername: user\ntoken: secret\nbaseURL: https://example.com:2083\n",
+			expected: `cpanel: unsupported mode: "uapi", must be "cpanel" or "whm"`,
+		},
+		{
+			desc:     "missing token",
+			raw:      "mode: whm\nusername: root\nbaseURL: https://example.com:2087\n",
+			expected: "cpanel: mode whm: missing username and/or token",
+		},
+		{
+			desc:     "missing base URL",
+			raw:      "username: user\ntoken: secret\n",
+			expected: "cpanel: mode cpanel: missing base URL",
+		},
+		{
+			desc:     "WHM port in cpanel mode",
+			raw:      "username: user\ntoken: secret\nbaseURL: https://example.com:2087\n",
+			expected: `cpanel: mode cpanel: the base URL "https://example.com:2087" uses the port of whm, set mode: whm or fix the port`,
+		},
+		{
+			desc:     "cPanel port in whm mode",
+			raw:      "mode: whm\nusername: root\ntoken: secret\nbaseURL: https://example.com:2083\n",
+			expected: `cpanel: mode whm: the base URL "https://example.com:2083" uses the port of cpanel, set mode: cpanel or fix the port`,
+		},
+		{
+			desc:     "API path in base URL",
+			raw:      "mode: whm\nusername: root\ntoken: secret\nbaseURL: https://example.com:2087/json-api/\n",
+			expected: `cpanel: invalid base URL "https://example.com:2087/json-api/": the API path /json-api is added by the whm mode`,
+		},
+		{
+			desc:     "invalid scheme",
+			raw:      "username: user\ntoken: secret\nbaseURL: example.com:2083\n",
+			expected: `cpanel: invalid base URL "example.com:2083": the scheme must be http or https`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config, err := ParseConfig([]byte(test.raw))
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, config)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
 func Test_getZoneSerial(t *testing.T) {
 	zones := []shared.ZoneRecord{
 		{
@@ -5,16 +5,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/miekg/dns"
 	"lego-toolbox/providers/dns/derak/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -24,6 +26,8 @@ const (
 	EnvAPIKey    = envNamespace + "API_KEY"
 	EnvWebsiteID = envNamespace + "WEBSITE_ID"
 
+	EnvPurgeCache = envNamespace + "PURGE_CACHE"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -32,8 +36,11 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	ApiKey             string        `yaml:"apiKey"`
-	WebsiteID          string        `yaml:"websiteID"`
+	ApiKey    string `yaml:"apiKey"`
+	WebsiteID string `yaml:"websiteID"`
+	// PurgeCache purges the edge cache of the record after each change,
+	// the changes are visible faster to the resolvers of Derak Cloud.
+	PurgeCache         bool          `yaml:"purgeCache"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
@@ -43,6 +50,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		PurgeCache:         env.GetOrDefaultBool(EnvPurgeCache, false),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
@@ -64,13 +72,22 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+apiKey: "your_api_key"               # API 密钥
+websiteID: ""                        # 网站（区域）ID，为空时自动查找
+purgeCache: false                    # 记录变更后清除边缘缓存，使解析更快生效
+propagationTimeout: 120s             # 传播超时时间
+pollingInterval: 5s                  # 轮询间隔时间
+ttl: 120                             # TXT 记录的生存时间（秒）`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Derak Cloud.
@@ -91,7 +108,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +134,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]string),
+		recordIDs: challengestore.New[string](),
 	}, nil
 }
 
@@ -127,10 +144,16 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -159,9 +182,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("derak: create record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = record.ID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, record.ID)
+
+	d.purgeCache(ctx, zoneID, info.EffectiveFQDN)
 
 	return nil
 }
@@ -169,7 +192,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneID, err := d.getZoneID(ctx, info)
 	if err != nil {
@@ -177,9 +200,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// gets the record's unique ID
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("derak: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -190,13 +211,26 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// deletes record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+
+	d.purgeCache(ctx, zoneID, info.EffectiveFQDN)
 
 	return nil
 }
 
+// purgeCache purges the cache of the record, when enabled (PurgeCache).
+// A failure only delays the visibility of the change: it is logged.
+func (d *DNSProvider) purgeCache(ctx context.Context, zoneID, fqdn string) {
+	if !d.config.PurgeCache {
+		return
+	}
+
+	err := d.client.PurgeCache(ctx, zoneID, []string{dns01.UnFqdn(fqdn)})
+	if err != nil {
+		log.Warnf("derak: purge the cache of %s: %v", dns01.UnFqdn(fqdn), err)
+	}
+}
+
 func (d *DNSProvider) getZoneID(ctx context.Context, info dns01.ChallengeInfo) (string, error) {
 	zoneID := d.config.WebsiteID
 	if zoneID != "" {
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	querystring "github.com/google/go-querystring/query"
@@ -137,6 +138,29 @@ func (c Client) DeleteRecord(ctx context.Context, zoneID string, recordID string
 	return nil
 }
 
+// PurgeCache purges the cache of the hostnames of the zone (website).
+func (c Client) PurgeCache(ctx context.Context, zoneID string, hostnames []string) error {
+	endpoint := c.baseURL.JoinPath("zones", zoneID, "cache", "purge")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, PurgeCacheRequest{Hostnames: hostnames})
+	if err != nil {
+		return err
+	}
+
+	response := &APIResponse[any]{}
+
+	err = c.do(req, response)
+	if err != nil {
+		return err
+	}
+
+	if !response.Success {
+		return fmt.Errorf("API error: %d %s", response.Error, codeText(response.Error))
+	}
+
+	return nil
+}
+
 // GetZones gets zones.
 // Note: it's not a part of the official API, there is no documentation about this.
 // The endpoint comes from UI calls analysis.
@@ -226,5 +250,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code %d] %d: %s", resp.StatusCode, response.Error, codeText(response.Error))
+	return errutils.NewAPIError(req, resp.StatusCode, raw, fmt.Errorf("%d: %s", response.Error, codeText(response.Error))).WithCode(strconv.Itoa(response.Error))
 }
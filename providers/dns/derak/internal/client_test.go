@@ -269,6 +269,26 @@ func TestDeleteRecord_error(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestPurgeCache(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("/zones/47c0ecf6c91243308c649ad1d2d618dd/cache/purge",
+		testHandler(http.MethodPost, http.StatusOK, "cache-purge.json"))
+
+	err := client.PurgeCache(context.Background(), "47c0ecf6c91243308c649ad1d2d618dd", []string{"_acme-challenge.example.com"})
+	require.NoError(t, err)
+}
+
+func TestPurgeCache_error(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("/zones/47c0ecf6c91243308c649ad1d2d618dd/cache/purge",
+		testHandler(http.MethodPost, http.StatusUnauthorized, "error.json"))
+
+	err := client.PurgeCache(context.Background(), "47c0ecf6c91243308c649ad1d2d618dd", []string{"_acme-challenge.example.com"})
+	require.Error(t, err)
+}
+
 func TestGetZones(t *testing.T) {
 	client, mux := setupTest(t)
 
@@ -32,6 +32,10 @@ type Record struct {
 	CustomSSLType    string `json:"customSSLType,omitempty"`
 }
 
+type PurgeCacheRequest struct {
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
 type APIResponse[T any] struct {
 	Success bool `json:"success"`
 	Result  T    `json:"result"`
@@ -166,9 +166,7 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	provider.recordIDsMu.Lock()
-	provider.recordIDs["token"] = 1234567
-	provider.recordIDsMu.Unlock()
+	provider.recordIDs.Set("token", "_acme-challenge.example.com.", 1234567)
 
 	err := provider.CleanUp("example.com", "token", "")
 	require.NoError(t, err, "fail to remove TXT record")
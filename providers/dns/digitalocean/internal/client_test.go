@@ -14,6 +14,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 func setupTest(t *testing.T, pattern string, handler http.HandlerFunc) *Client {
@@ -23,7 +24,7 @@ func setupTest(t *testing.T, pattern string, handler http.HandlerFunc) *Client {
 	server := httptest.NewServer(mux)
 	t.Cleanup(server.Close)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), "secret"))
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), "secret"))
 	client.BaseURL, _ = url.Parse(server.URL)
 
 	mux.HandleFunc(pattern, handler)
@@ -111,7 +111,7 @@ func TestClient_SetRecord_error(t *testing.T) {
 	}
 
 	err := client.SetRecord(context.Background(), "example.com", record)
-	require.EqualError(t, err, "[status code 500] Cannot View Dns Record: OOPS")
+	require.EqualError(t, err, "[status code: 500] Cannot View Dns Record: OOPS")
 }
 
 func TestClient_DeleteRecord(t *testing.T) {
@@ -151,5 +151,5 @@ func TestClient_DeleteRecord_error(t *testing.T) {
 	}
 
 	err := client.DeleteRecord(context.Background(), "example.com", record)
-	require.EqualError(t, err, "[status code 500] Cannot View Dns Record: OOPS")
+	require.EqualError(t, err, "[status code: 500] Cannot View Dns Record: OOPS")
 }
@@ -5,14 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/dnshomede/internal"
+	"lego-toolbox/providers/dns/internal/credmap"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -29,12 +30,12 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	CredentialsRaw     string            `yaml:"credentials"`
-	Credentials        map[string]string `yaml:"-"`
-	PropagationTimeout time.Duration     `yaml:"propagationTimeout"`
-	PollingInterval    time.Duration     `yaml:"pollingInterval"`
-	SequenceInterval   time.Duration     `yaml:"sequenceInterval"`
-	HTTPClient         *http.Client      `yaml:"-"`
+	// Credentials are the passwords keyed by domain (the TXT record name without the `_acme-challenge.` component).
+	Credentials        credmap.Map   `yaml:"credentials"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -61,6 +62,16 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+credentials:                       # 域名（TXT 记录名称，不含 _acme-challenge. 前缀）到密码的映射
+  my.example.org: "your_password"
+  demo.example.org: "your_other_password"
+propagationTimeout: 20m            # 传播超时时间
+pollingInterval: 2s                # 轮询间隔时间
+sequenceInterval: 2m               # 顺序请求之间的间隔时间`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -76,7 +87,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 		return nil, fmt.Errorf("dnshomede: %w", err)
 	}
 
-	credentials, err := parseCredentials(values[EnvCredentials])
+	credentials, err := credmap.Parse(values[EnvCredentials])
 	if err != nil {
 		return nil, fmt.Errorf("dnshomede: %w", err)
 	}
@@ -89,15 +100,10 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
-	credentials, err := parseCredentials(config.CredentialsRaw)
-	if err != nil {
-		return nil, fmt.Errorf("dnshomede: %w", err)
-	}
-	config.Credentials = credentials
 	return config, nil
 }
 
@@ -110,24 +116,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("dnshomede: missing credentials")
 	}
 
-	for domain, password := range config.Credentials {
-		if domain == "" {
-			return nil, fmt.Errorf(`dnshomede: missing domain: "%s:%s"`, domain, password)
-		}
-
-		if password == "" {
-			return nil, fmt.Errorf(`dnshomede: missing password: "%s:%s"`, domain, password)
-		}
+	credentials, err := credmap.Validate(config.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("dnshomede: %w", err)
 	}
 
-	client := internal.NewClient(config.Credentials)
+	client := internal.NewClient(credentials)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
 
 	return &DNSProvider{config: config, client: client}, nil
 }
 
 // Present updates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.client.Add(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
 	if err != nil {
@@ -139,7 +144,7 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 
 // CleanUp updates the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.client.Remove(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
 	if err != nil {
@@ -160,19 +165,3 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 func (d *DNSProvider) Sequential() time.Duration {
 	return d.config.SequenceInterval
 }
-
-func parseCredentials(raw string) (map[string]string, error) {
-	credentials := make(map[string]string)
-
-	credStrings := strings.Split(strings.TrimSuffix(raw, ","), ",")
-	for _, credPair := range credStrings {
-		data := strings.Split(credPair, ":")
-		if len(data) != 2 {
-			return nil, fmt.Errorf("invalid credential pair: %q", credPair)
-		}
-
-		credentials[strings.TrimSpace(data[0])] = strings.TrimSpace(data[1])
-	}
-
-	return credentials, nil
-}
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/credmap"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -41,14 +43,14 @@ func TestNewDNSProvider(t *testing.T) {
 			envVars: map[string]string{
 				EnvCredentials: "example.org:",
 			},
-			expected: `dnshomede: missing password: "example.org:"`,
+			expected: `dnshomede: missing token for "example.org"`,
 		},
 		{
 			desc: "missing domain",
 			envVars: map[string]string{
 				EnvCredentials: ":123",
 			},
-			expected: `dnshomede: missing domain: ":123"`,
+			expected: "dnshomede: empty domain in the credentials",
 		},
 		{
 			desc: "invalid credentials, partial",
@@ -111,12 +113,17 @@ func TestNewDNSProviderConfig(t *testing.T) {
 		{
 			desc:     "missing domain",
 			creds:    map[string]string{"": "123"},
-			expected: `dnshomede: missing domain: ":123"`,
+			expected: "dnshomede: empty domain in the credentials",
 		},
 		{
 			desc:     "missing password",
 			creds:    map[string]string{"example.org": ""},
-			expected: `dnshomede: missing password: "example.org:"`,
+			expected: `dnshomede: missing token for "example.org"`,
+		},
+		{
+			desc:     "wildcard domain",
+			creds:    map[string]string{"*.example.org": "123"},
+			expected: `dnshomede: wildcard domain "*.example.org" in the credentials: use "example.org"`,
 		},
 	}
 
@@ -138,6 +145,39 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		rawConfig string
+	}{
+		{
+			desc:      "mapping",
+			rawConfig: "credentials:\n  My.example.org: \"123\"\n  demo.example.org: \"456\"\n",
+		},
+		{
+			desc:      "legacy list",
+			rawConfig: "credentials: My.example.org:123,demo.example.org:456\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config, err := ParseConfig([]byte(test.rawConfig))
+			require.NoError(t, err)
+
+			p, err := NewDNSProviderConfig(config)
+			require.NoError(t, err)
+
+			assert.NotNil(t, p.client)
+
+			credentials, err := credmap.Validate(config.Credentials)
+			require.NoError(t, err)
+
+			assert.Equal(t, credmap.Map{"my.example.org": "123", "demo.example.org": "456"}, credentials)
+		})
+	}
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -44,7 +44,7 @@ func NewClient(credentials map[string]string) *Client {
 // Add adds a TXT record.
 // only one TXT record for ACME is allowed, so it will update the "current" TXT record.
 func (c *Client) Add(ctx context.Context, hostname, value string) error {
-	domain := strings.TrimPrefix(hostname, "_acme-challenge.")
+	domain := strings.TrimPrefix(strings.ToLower(hostname), "_acme-challenge.")
 
 	return c.doAction(ctx, domain, addAction, value)
 }
@@ -52,7 +52,7 @@ func (c *Client) Add(ctx context.Context, hostname, value string) error {
 // Remove removes a TXT record.
 // only one TXT record for ACME is allowed, so it will remove "all" the TXT records.
 func (c *Client) Remove(ctx context.Context, hostname, value string) error {
-	domain := strings.TrimPrefix(hostname, "_acme-challenge.")
+	domain := strings.TrimPrefix(strings.ToLower(hostname), "_acme-challenge.")
 
 	return c.doAction(ctx, domain, removeAction, value)
 }
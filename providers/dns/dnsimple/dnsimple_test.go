@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providertest"
 )
 
 const sandboxURL = "https://api.sandbox.fake.com"
@@ -158,3 +160,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
@@ -6,7 +6,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
 	"strings"
@@ -15,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/dnsmadeeasy/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -25,6 +26,8 @@ const (
 	EnvAPISecret = envNamespace + "API_SECRET"
 	EnvSandbox   = envNamespace + "SANDBOX"
 
+	EnvClockSkewRetry = envNamespace + "CLOCK_SKEW_RETRY"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -33,10 +36,12 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	BaseURL            string        `yaml:"baseURL"`
-	APIKey             string        `yaml:"apiKey"`
-	APISecret          string        `yaml:"apiSecret"`
-	Sandbox            bool          `yaml:"sandbox"`
+	BaseURL   string `yaml:"baseURL"`
+	APIKey    string `yaml:"apiKey"`
+	APISecret string `yaml:"apiSecret"`
+	Sandbox   bool   `yaml:"sandbox"`
+	// ClockSkewRetry re-signs the requests rejected because of the skew of the local clock with the server clock.
+	ClockSkewRetry     bool          `yaml:"clockSkewRetry"`
 	HTTPClient         *http.Client  `yaml:"-"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
@@ -46,6 +51,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		ClockSkewRetry:     env.GetOrDefaultBool(EnvClockSkewRetry, true),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -61,6 +67,7 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
+		ClockSkewRetry:     true,
 		TTL:                dns01.DefaultTTL,
 		PropagationTimeout: dns01.DefaultPropagationTimeout,
 		PollingInterval:    dns01.DefaultPollingInterval,
@@ -99,7 +106,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +136,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	client.HTTPClient = config.HTTPClient
+	client.ClockSkewRetry = config.ClockSkewRetry
 	client.BaseURL, err = url.Parse(baseURL)
 	if err != nil {
 		return nil, err
@@ -142,7 +150,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domainName, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domainName, keyAuth)
+	info := rawrecord.GetChallengeInfo(domainName, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -170,7 +178,7 @@ func (d *DNSProvider) Present(domainName, token, keyAuth string) error {
 
 // CleanUp removes the TXT records matching the specified parameters.
 func (d *DNSProvider) CleanUp(domainName, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domainName, keyAuth)
+	info := rawrecord.GetChallengeInfo(domainName, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
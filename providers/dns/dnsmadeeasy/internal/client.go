@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"lego-toolbox/providers/dns/internal/errutils"
@@ -24,6 +25,10 @@ const (
 	DefaultProdBaseURL    = "https://api.dnsmadeeasy.com/V2.0"
 )
 
+// maxClockSkew is the difference between the local clock and the server clock above which
+// a rejected request is considered rejected for its request date.
+const maxClockSkew = 30 * time.Second
+
 // Client DNSMadeEasy client.
 type Client struct {
 	apiKey    string
@@ -31,6 +36,13 @@ type Client struct {
 
 	BaseURL    *url.URL
 	HTTPClient *http.Client
+
+	// ClockSkewRetry re-signs the requests rejected because of the clock skew with the server clock,
+	// and keeps signing the next requests with the server clock.
+	ClockSkewRetry bool
+
+	offsetMu sync.Mutex
+	offset   time.Duration
 }
 
 // NewClient creates a DNSMadeEasy client.
@@ -125,20 +137,36 @@ func (c *Client) DeleteRecord(ctx context.Context, record Record) error {
 }
 
 func (c *Client) do(req *http.Request, result any) error {
-	err := c.sign(req, time.Now().UTC().Format(time.RFC1123))
+	resp, skew, err := c.send(req)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return errutils.NewHTTPDoError(req, err)
+	if skew != 0 && c.ClockSkewRetry && req.GetBody != nil {
+		_ = resp.Body.Close()
+
+		c.addOffset(skew)
+
+		req.Body, err = req.GetBody()
+		if err != nil {
+			return fmt.Errorf("unable to rewind the request body: %w", err)
+		}
+
+		resp, skew, err = c.send(req)
+		if err != nil {
+			return err
+		}
 	}
 
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode/100 != 2 {
-		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+		err = errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+		if skew != 0 {
+			return &ClockSkewError{Skew: skew, err: err}
+		}
+
+		return err
 	}
 
 	if result == nil {
@@ -157,6 +185,42 @@ func (c *Client) do(req *http.Request, result any) error {
 	return nil
 }
 
+// send signs and sends the request.
+// The skew is the difference between the server clock and the local clock when the request is rejected with a skewed clock, zero otherwise.
+func (c *Client) send(req *http.Request) (*http.Response, time.Duration, error) {
+	now := time.Now().Add(c.getOffset())
+
+	err := c.sign(req, now.UTC().Format(time.RFC1123))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, errutils.NewHTTPDoError(req, err)
+	}
+
+	if resp.StatusCode/100 == 2 {
+		return resp, 0, nil
+	}
+
+	return resp, clockSkew(resp.Header.Get("Date"), now), nil
+}
+
+func (c *Client) getOffset() time.Duration {
+	c.offsetMu.Lock()
+	defer c.offsetMu.Unlock()
+
+	return c.offset
+}
+
+func (c *Client) addOffset(skew time.Duration) {
+	c.offsetMu.Lock()
+	defer c.offsetMu.Unlock()
+
+	c.offset += skew
+}
+
 func (c *Client) sign(req *http.Request, timestamp string) error {
 	signature, err := computeHMAC(timestamp, c.apiSecret)
 	if err != nil {
@@ -170,6 +234,22 @@ func (c *Client) sign(req *http.Request, timestamp string) error {
 	return nil
 }
 
+// clockSkew returns the difference between the server clock (the Date header of the response) and the request date,
+// when it is above maxClockSkew, zero otherwise.
+func clockSkew(date string, requestDate time.Time) time.Duration {
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return 0
+	}
+
+	skew := serverTime.Sub(requestDate)
+	if skew.Abs() < maxClockSkew {
+		return 0
+	}
+
+	return skew.Round(time.Second)
+}
+
 func computeHMAC(message, secret string) (string, error) {
 	key := []byte(secret)
 	h := hmac.New(sha1.New, key)
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -26,3 +29,73 @@ func Test_sign(t *testing.T) {
 	assert.Equal(t, timestamp, req.Header.Get("x-dnsme-requestDate"))
 	assert.Equal(t, "6b6c8432119c31e1d3776eb4cd3abd92fae4a71c", req.Header.Get("x-dnsme-hmac"))
 }
+
+func setupClockSkewTest(t *testing.T, serverOffset time.Duration, retry bool) (*Client, *int) {
+	t.Helper()
+
+	var calls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		serverTime := time.Now().Add(serverOffset)
+		rw.Header().Set("Date", serverTime.UTC().Format(http.TimeFormat))
+
+		requestDate, err := time.Parse(time.RFC1123, req.Header.Get("x-dnsme-requestDate"))
+		if err != nil || serverTime.Sub(requestDate).Abs() > 30*time.Second {
+			http.Error(rw, `{"error": ["Request date is out of range"]}`, http.StatusForbidden)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"id": 1, "name": "example.com"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	client.HTTPClient = server.Client()
+	client.BaseURL, _ = url.Parse(server.URL)
+	client.ClockSkewRetry = retry
+
+	return client, &calls
+}
+
+func TestClient_GetDomain_clockSkewRetry(t *testing.T) {
+	client, calls := setupClockSkewTest(t, time.Hour, true)
+
+	domain, err := client.GetDomain(context.Background(), "example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Domain{ID: 1, Name: "example.com"}, domain)
+	assert.Equal(t, 2, *calls)
+
+	// the next requests are signed with the server clock.
+	_, err = client.GetDomain(context.Background(), "example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, *calls)
+}
+
+func TestClient_GetDomain_clockSkewError(t *testing.T) {
+	client, calls := setupClockSkewTest(t, -time.Hour, false)
+
+	_, err := client.GetDomain(context.Background(), "example.com.")
+	require.Error(t, err)
+
+	var skewErr *ClockSkewError
+	require.ErrorAs(t, err, &skewErr)
+
+	assert.InDelta(t, -time.Hour, skewErr.Skew, float64(2*time.Second))
+	assert.Contains(t, err.Error(), "synchronize it with NTP")
+	assert.Equal(t, 1, *calls)
+}
+
+func Test_clockSkew(t *testing.T) {
+	now := time.Date(2024, time.June, 2, 2, 36, 7, 0, time.UTC)
+
+	assert.Zero(t, clockSkew(now.Add(10*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, clockSkew("invalid", now))
+	assert.Equal(t, 5*time.Minute, clockSkew(now.Add(5*time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, -5*time.Minute, clockSkew(now.Add(-5*time.Minute).Format(http.TimeFormat), now))
+}
@@ -1,5 +1,10 @@
 package internal
 
+import (
+	"fmt"
+	"time"
+)
+
 // Domain holds the DNSMadeEasy API representation of a Domain.
 type Domain struct {
 	ID   int    `json:"id"`
@@ -19,3 +24,20 @@ type Record struct {
 type recordsResponse struct {
 	Records *[]Record `json:"data"`
 }
+
+// ClockSkewError is returned when a request is rejected and the local clock is skewed:
+// the HMAC signature of the requests includes the request date, checked by the server.
+type ClockSkewError struct {
+	// Skew is the difference between the server clock and the local clock.
+	Skew time.Duration
+
+	err error
+}
+
+func (e *ClockSkewError) Error() string {
+	return fmt.Sprintf("the local clock is off by %s from the server clock, synchronize it with NTP: %v", e.Skew, e.err)
+}
+
+func (e *ClockSkewError) Unwrap() error {
+	return e.err
+}
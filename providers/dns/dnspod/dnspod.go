@@ -4,7 +4,6 @@ package dnspod
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,6 +11,8 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nrdcg/dnspod-go"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 // Environment variables names.
@@ -19,6 +20,7 @@ const (
 	envNamespace = "DNSPOD_"
 
 	EnvAPIKey = envNamespace + "API_KEY"
+	EnvIntl   = envNamespace + "INTL"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -26,9 +28,14 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+// intlBaseURL is the base URL of the international DNSPod API (dnspod.com).
+const intlBaseURL = "https://api.dnspod.com/"
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	LoginToken         string        `yaml:"loginToken"`
+	LoginToken string `yaml:"loginToken"`
+	// Intl uses the international API (dnspod.com) instead of the Chinese one (dnspod.cn).
+	Intl               bool          `yaml:"intl"`
 	TTL                int           `yaml:"ttl"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
@@ -59,6 +66,15 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# config.yaml
+loginToken: "id,token"                # DNSPod Token，格式为 "ID,Token"
+intl: false                           # 是否使用国际版 API（dnspod.com）
+ttl: 600                              # TXT 记录的 TTL
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -75,6 +91,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 	config := NewDefaultConfig()
 	config.LoginToken = values[EnvAPIKey]
+	config.Intl = env.GetOrDefaultBool(EnvIntl, false)
 
 	return NewDNSProviderConfig(config)
 }
@@ -82,7 +99,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -104,12 +121,16 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client := dnspod.NewClient(params)
 	client.HTTPClient = config.HTTPClient
 
+	if config.Intl {
+		client.BaseURL = intlBaseURL
+	}
+
 	return &DNSProvider{client: client, config: config}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneID, zoneName, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -131,7 +152,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneID, zoneName, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
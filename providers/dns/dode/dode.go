@@ -5,20 +5,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/dode/internal"
+	"lego-toolbox/providers/dns/internal/credmap"
+	"lego-toolbox/providers/dns/internal/txtqueue"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "DODE_"
 
-	EnvToken = envNamespace + "TOKEN"
+	EnvToken  = envNamespace + "TOKEN"
+	EnvTokens = envNamespace + "TOKENS"
 
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -28,7 +32,10 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Token              string        `yaml:"token"`
+	Token string `yaml:"token"`
+	// Tokens are the API tokens keyed by domain, for the domains with their own token:
+	// a record uses the token of its closest domain, or Token.
+	Tokens             credmap.Map   `yaml:"tokens"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
@@ -59,21 +66,52 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+token: "your_api_token"            # API 令牌
+tokens:                            # 按域名区分的 API 令牌，记录使用最近域名的令牌，否则使用 token
+  example.org: "your_other_api_token"
+propagationTimeout: 60s            # 传播超时时间
+pollingInterval: 2s                # 轮询间隔时间
+sequenceInterval: 60s              # 顺序请求之间的间隔时间`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// client uses Token, nil without it.
 	client *internal.Client
+	// clients use the Tokens, keyed by domain.
+	clients map[string]*internal.Client
+	tokens  credmap.Map
+
+	// queue sequences the challenges of the same record: do.de holds one TXT value per record.
+	queue *txtqueue.Queue
 }
 
 // NewDNSProvider returns a new DNS provider using
 // environment variable DODE_TOKEN for adding and removing the DNS record.
 func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	if raw := env.GetOrFile(EnvTokens); raw != "" {
+		tokens, err := credmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("do.de: %w", err)
+		}
+
+		config.Tokens = tokens
+		config.Token = env.GetOrFile(EnvToken)
+
+		return NewDNSProviderConfig(config)
+	}
+
 	values, err := env.Get(EnvToken)
 	if err != nil {
 		return nil, fmt.Errorf("do.de: %w", err)
 	}
 
-	config := NewDefaultConfig()
 	config.Token = values[EnvToken]
 
 	return NewDNSProviderConfig(config)
@@ -82,7 +120,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -95,29 +133,107 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("do.de: the configuration of the DNS provider is nil")
 	}
 
-	if config.Token == "" {
+	if config.Token == "" && len(config.Tokens) == 0 {
 		return nil, errors.New("do.de: credentials missing")
 	}
 
-	client := internal.NewClient(config.Token)
+	tokens, err := credmap.Validate(config.Tokens)
+	if err != nil {
+		return nil, fmt.Errorf("do.de: %w", err)
+	}
+
+	provider := &DNSProvider{
+		config:  config,
+		clients: make(map[string]*internal.Client),
+		tokens:  tokens,
+		queue:   txtqueue.New(),
+	}
+
+	if config.Token != "" {
+		provider.client = newClient(config.HTTPClient, config.Token)
+	}
+
+	for domain, token := range tokens {
+		provider.clients[domain] = newClient(config.HTTPClient, token)
+	}
+
+	return provider, nil
+}
+
+func newClient(hc *http.Client, token string) *internal.Client {
+	client := internal.NewClient(token)
+
+	if hc != nil {
+		client.HTTPClient = hc
+	}
+
+	return client
+}
+
+// clientFor returns the client using the token of the closest domain of the record, or Token.
+func (d *DNSProvider) clientFor(fqdn string) (*internal.Client, error) {
+	if _, domain, ok := d.tokens.Match(fqdn); ok {
+		return d.clients[domain], nil
+	}
 
-	if config.HTTPClient != nil {
-		client.HTTPClient = config.HTTPClient
+	if d.client == nil {
+		return nil, fmt.Errorf("no token for %s, check your tokens map", dns01.UnFqdn(fqdn))
 	}
 
-	return &DNSProvider{config: config, client: client}, nil
+	return d.client, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-	return d.client.UpdateTxtRecord(context.Background(), info.EffectiveFQDN, info.Value, false)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+// do.de holds one TXT value per record: the value waits for the cleanup of the previous value of the record
+// (e.g. an apex and wildcard order), at most twice the propagation timeout, the wait is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("do.de: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*d.config.PropagationTimeout)
+	defer cancel()
+
+	err = d.queue.Present(ctx, info.EffectiveFQDN, info.Value, func() error {
+		return client.UpdateTxtRecord(ctx, info.EffectiveFQDN, info.Value, false)
+	})
+	if err != nil {
+		return fmt.Errorf("do.de: %w", err)
+	}
+
+	return nil
 }
 
 // CleanUp clears TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-	return d.client.UpdateTxtRecord(context.Background(), info.EffectiveFQDN, "", true)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext clears TXT record, unless it holds the value of another challenge.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("do.de: %w", err)
+	}
+
+	err = d.queue.CleanUp(info.EffectiveFQDN, info.Value, func() error {
+		return client.UpdateTxtRecord(ctx, info.EffectiveFQDN, "", true)
+	})
+	if err != nil {
+		return fmt.Errorf("do.de: %w", err)
+	}
+
+	return nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -5,12 +5,13 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvToken).WithDomain(envDomain)
+var envTest = tester.NewEnvTest(EnvToken, EnvTokens).WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
 	testCases := []struct {
@@ -24,6 +25,12 @@ func TestNewDNSProvider(t *testing.T) {
 				EnvToken: "123",
 			},
 		},
+		{
+			desc: "success tokens",
+			envVars: map[string]string{
+				EnvTokens: "example.org:123,example.com:456",
+			},
+		},
 		{
 			desc: "missing api key",
 			envVars: map[string]string{
@@ -31,6 +38,13 @@ func TestNewDNSProvider(t *testing.T) {
 			},
 			expected: "do.de: some credentials information are missing: DODE_TOKEN",
 		},
+		{
+			desc: "invalid tokens",
+			envVars: map[string]string{
+				EnvTokens: "example.org:123,:456",
+			},
+			expected: "do.de: empty domain in the credentials",
+		},
 	}
 
 	for _, test := range testCases {
@@ -57,22 +71,33 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
 		desc     string
 		token    string
+		tokens   map[string]string
 		expected string
 	}{
 		{
 			desc:  "success",
 			token: "123",
 		},
+		{
+			desc:   "success tokens",
+			tokens: map[string]string{"example.org": "123"},
+		},
 		{
 			desc:     "missing credentials",
 			expected: "do.de: credentials missing",
 		},
+		{
+			desc:     "wildcard domain",
+			tokens:   map[string]string{"*.example.org": "123"},
+			expected: `do.de: wildcard domain "*.example.org" in the credentials: use "example.org"`,
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
 			config := NewDefaultConfig()
 			config.Token = test.token
+			config.Tokens = test.tokens
 
 			p, err := NewDNSProviderConfig(config)
 
@@ -87,6 +112,28 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+tokens:
+  example.org: "123"
+  Example.com: "456"
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err := p.clientFor("_acme-challenge.www.example.com.")
+	require.NoError(t, err)
+
+	assert.Same(t, p.clients["example.com"], client)
+
+	_, err = p.clientFor("_acme-challenge.example.net.")
+	require.EqualError(t, err, "no token for _acme-challenge.example.net, check your tokens map")
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
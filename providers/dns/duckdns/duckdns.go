@@ -6,13 +6,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/duckdns/internal"
+	"lego-toolbox/providers/dns/internal/txtqueue"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -64,6 +66,9 @@ func DefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	// queue sequences the challenges of the same domain: DuckDNS holds one TXT value per domain.
+	queue *txtqueue.Queue
 }
 
 // NewDNSProvider returns a new DNS provider using
@@ -83,7 +88,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -106,19 +111,50 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{config: config, client: client, queue: txtqueue.New()}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-	return d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+// DuckDNS holds one TXT value per domain: the value waits for the cleanup of the previous value of the domain
+// (e.g. an apex and wildcard order), at most twice the propagation timeout, the wait is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	ctx, cancel := context.WithTimeout(ctx, 2*d.config.PropagationTimeout)
+	defer cancel()
+
+	err := d.queue.Present(ctx, internal.MainDomain(info.EffectiveFQDN), info.Value, func() error {
+		return d.client.AddTXTRecord(ctx, dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	})
+	if err != nil {
+		return fmt.Errorf("duckdns: %w", err)
+	}
+
+	return nil
 }
 
 // CleanUp clears DuckDNS TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-	return d.client.RemoveTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN))
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext clears DuckDNS TXT record, unless it holds the value of another challenge.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.queue.CleanUp(internal.MainDomain(info.EffectiveFQDN), info.Value, func() error {
+		return d.client.RemoveTXTRecord(ctx, dns01.UnFqdn(info.EffectiveFQDN))
+	})
+	if err != nil {
+		return fmt.Errorf("duckdns: %w", err)
+	}
+
+	return nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
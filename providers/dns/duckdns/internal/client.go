@@ -46,7 +46,7 @@ func (c Client) RemoveTXTRecord(ctx context.Context, domain string) error {
 func (c Client) UpdateTxtRecord(ctx context.Context, domain, txt string, clear bool) error {
 	endpoint, _ := url.Parse(defaultBaseURL)
 
-	mainDomain := getMainDomain(domain)
+	mainDomain := MainDomain(domain)
 	if mainDomain == "" {
 		return fmt.Errorf("unable to find the main domain for: %s", domain)
 	}
@@ -82,11 +82,12 @@ func (c Client) UpdateTxtRecord(ctx context.Context, domain, txt string, clear b
 	return nil
 }
 
+// MainDomain returns the DuckDNS domain holding the TXT record of the domain.
 // DuckDNS only lets you write to your subdomain.
 // It must be in format subdomain.duckdns.org,
 // not in format subsubdomain.subdomain.duckdns.org.
 // So strip off everything that is not top 3 levels.
-func getMainDomain(domain string) string {
+func MainDomain(domain string) string {
 	domain = dns01.UnFqdn(domain)
 
 	split := dns.Split(domain)
@@ -6,7 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_getMainDomain(t *testing.T) {
+func TestMainDomain(t *testing.T) {
 	testCases := []struct {
 		desc     string
 		domain   string
@@ -58,7 +58,7 @@ func Test_getMainDomain(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			wDomain := getMainDomain(test.domain)
+			wDomain := MainDomain(test.domain)
 			assert.Equal(t, test.expected, wDomain)
 		})
 	}
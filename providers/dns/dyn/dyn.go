@@ -5,13 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/dyn/internal"
+	"lego-toolbox/providers/dns/internal/zonelock"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -26,8 +28,12 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvPublishDelay       = envNamespace + "PUBLISH_DELAY"
 )
 
+// maxPublishDelayFactor caps the wait of the batched changes of a zone to a multiple of PublishDelay.
+const maxPublishDelayFactor = 5
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	CustomerName       string        `yaml:"customerName"`
@@ -37,6 +43,12 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	// PublishDelay batches the zone publishes: the changes of a zone are published once,
+	// after PublishDelay without another change of the zone, or 5 times PublishDelay after the first change.
+	// Present and CleanUp return before the publish, a failed publish fails the next change of the zone.
+	// Zero publishes each change.
+	// The pending changes are published by Close: a short-lived process must call it before exiting.
+	PublishDelay time.Duration `yaml:"publishDelay"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -45,6 +57,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PublishDelay:       env.GetOrDefaultSecond(EnvPublishDelay, 0),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
 		},
@@ -63,10 +76,24 @@ func DefaultConfig() *Config {
 	}
 }
 
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+customerName: "your_customer_name"   # 客户名称
+userName: "your_user_name"           # 用户名
+password: "your_password"            # 密码
+publishDelay: 0s                     # 区域发布的合并窗口，窗口内无新变更时统一发布一次；为 0 时每次变更后立即发布
+propagationTimeout: 60s              # 传播超时时间
+pollingInterval: 2s                  # 轮询间隔时间
+ttl: 120                             # TXT 记录的生存时间（秒）`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	// publisher batches the publishes, nil without PublishDelay.
+	publisher *publisher
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Dyn DNS.
@@ -89,7 +116,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -112,61 +139,115 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	return &DNSProvider{config: config, client: client}, nil
+	provider := &DNSProvider{config: config, client: client}
+
+	if config.PublishDelay > 0 {
+		provider.publisher = newPublisher(config.PublishDelay, maxPublishDelayFactor*config.PublishDelay, provider.publish)
+	}
+
+	return provider, nil
 }
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("dyn: could not find zone for domain %q: %w", domain, err)
 	}
 
-	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	err = d.change(authZone, "Added TXT record for ACME dns-01 challenge using lego client", func(ctx context.Context) error {
+		return d.client.AddTXTRecord(ctx, authZone, info.EffectiveFQDN, info.Value, d.config.TTL)
+	})
 	if err != nil {
 		return fmt.Errorf("dyn: %w", err)
 	}
 
-	err = d.client.AddTXTRecord(ctx, authZone, info.EffectiveFQDN, info.Value, d.config.TTL)
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("dyn: %w", err)
+		return fmt.Errorf("dyn: could not find zone for domain %q: %w", domain, err)
 	}
 
-	err = d.client.Publish(ctx, authZone, "Added TXT record for ACME dns-01 challenge using lego client")
+	err = d.change(authZone, "Removed TXT record for ACME dns-01 challenge using lego client", func(ctx context.Context) error {
+		return d.client.RemoveTXTRecord(ctx, authZone, info.EffectiveFQDN)
+	})
 	if err != nil {
 		return fmt.Errorf("dyn: %w", err)
 	}
 
-	return d.client.Logout(ctx)
+	return nil
 }
 
-// CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+// change applies the change to the zone in a session, and publishes the zone or schedules its publish.
+// The changes and the publishes of a zone are serialized, so a publish does not conflict with a change.
+func (d *DNSProvider) change(zone, notes string, apply func(ctx context.Context) error) error {
+	unlock := zonelock.Lock(zone)
+	defer unlock()
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
 	if err != nil {
-		return fmt.Errorf("dyn: could not find zone for domain %q: %w", domain, err)
+		return err
 	}
 
+	err = apply(ctx)
+	if err != nil {
+		return errors.Join(err, d.client.Logout(ctx))
+	}
+
+	if d.publisher != nil {
+		d.publisher.schedule(zone)
+
+		// the change is applied (and its publish retries the previous changes),
+		// but the records of the failed publish were not visible.
+		return errors.Join(d.publisher.failure(zone), d.client.Logout(ctx))
+	}
+
+	err = d.client.Publish(ctx, zone, notes)
+	if err != nil {
+		return errors.Join(err, d.client.Logout(ctx))
+	}
+
+	return d.client.Logout(ctx)
+}
+
+// publish publishes the batched changes of the zone in a session.
+func (d *DNSProvider) publish(zone string, changes int) error {
+	unlock := zonelock.Lock(zone)
+	defer unlock()
+
 	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
 	if err != nil {
-		return fmt.Errorf("dyn: %w", err)
+		return err
 	}
 
-	err = d.client.RemoveTXTRecord(ctx, authZone, info.EffectiveFQDN)
+	err = d.client.Publish(ctx, zone, fmt.Sprintf("Published %d TXT record changes for ACME dns-01 challenges using lego client", changes))
 	if err != nil {
-		return fmt.Errorf("dyn: %w", err)
+		return errors.Join(err, d.client.Logout(ctx))
+	}
+
+	return d.client.Logout(ctx)
+}
+
+// Close publishes the changes pending with PublishDelay.
+func (d *DNSProvider) Close() error {
+	if d.publisher == nil {
+		return nil
 	}
 
-	err = d.client.Publish(ctx, authZone, "Removed TXT record for ACME dns-01 challenge using lego client")
+	err := d.publisher.close()
 	if err != nil {
 		return fmt.Errorf("dyn: %w", err)
 	}
 
-	return d.client.Logout(ctx)
+	return nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
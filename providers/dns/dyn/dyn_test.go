@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,7 +14,8 @@ const envDomain = envNamespace + "DOMAIN"
 var envTest = tester.NewEnvTest(
 	EnvCustomerName,
 	EnvUserName,
-	EnvPassword).
+	EnvPassword,
+	EnvPublishDelay).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -149,6 +151,33 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+customerName: A
+userName: B
+password: C
+publishDelay: 5s
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, config.PublishDelay)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	require.NotNil(t, p.publisher)
+	assert.Equal(t, 5*time.Second, p.publisher.delay)
+
+	config.PublishDelay = 0
+
+	p, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Nil(t, p.publisher)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -0,0 +1,135 @@
+package dyn
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// publisher batches the zone publishes:
+// the changes of a zone are published once, when no other change of the zone happened during the delay (debounce),
+// or maxDelay after the first pending change, so a stream of changes does not postpone the publish forever.
+type publisher struct {
+	delay    time.Duration
+	maxDelay time.Duration
+	publish  func(zone string, changes int) error
+
+	mu      sync.Mutex
+	pending map[string]*pendingPublish
+	// failed are the errors of the publishes not reported yet, by zone.
+	failed map[string]error
+}
+
+type pendingPublish struct {
+	timer   *time.Timer
+	first   time.Time
+	changes int
+}
+
+func newPublisher(delay, maxDelay time.Duration, publish func(zone string, changes int) error) *publisher {
+	return &publisher{
+		delay:    delay,
+		maxDelay: maxDelay,
+		publish:  publish,
+		pending:  make(map[string]*pendingPublish),
+		failed:   make(map[string]error),
+	}
+}
+
+// schedule records a change of the zone, and (re)starts the delay before its publish.
+func (p *publisher) schedule(zone string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pending, ok := p.pending[zone]; ok {
+		pending.changes++
+		pending.timer.Reset(max(min(p.delay, p.maxDelay-time.Since(pending.first)), 0))
+
+		return
+	}
+
+	p.pending[zone] = &pendingPublish{
+		changes: 1,
+		first:   time.Now(),
+		timer:   time.AfterFunc(p.delay, func() { p.flush(zone) }),
+	}
+}
+
+// failure returns the error of the last publish of the zone, once.
+// The change following a failed publish reports it: its Present or CleanUp fails.
+func (p *publisher) failure(zone string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	err, ok := p.failed[zone]
+	if !ok {
+		return nil
+	}
+
+	delete(p.failed, zone)
+
+	return err
+}
+
+// flush publishes the pending changes of the zone.
+// The error is recorded for the next change of the zone (see failure):
+// the records are not visible, the propagation check of the challenges fails.
+func (p *publisher) flush(zone string) {
+	p.mu.Lock()
+
+	pending, ok := p.pending[zone]
+	if ok {
+		delete(p.pending, zone)
+	}
+
+	p.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	err := p.publish(zone, pending.changes)
+	if err != nil {
+		log.Warnf("dyn: publish of the zone %s (%d changes): %v", zone, pending.changes, err)
+
+		p.mu.Lock()
+		p.failed[zone] = fmt.Errorf("publish of the zone %s (%d changes): %w", zone, pending.changes, err)
+		p.mu.Unlock()
+	}
+}
+
+// close publishes the pending changes of all the zones, without waiting for their delay.
+// It returns the errors of these publishes, and of the failed publishes not reported yet.
+func (p *publisher) close() error {
+	p.mu.Lock()
+
+	pending := p.pending
+	p.pending = make(map[string]*pendingPublish)
+
+	for _, pp := range pending {
+		// a timer already fired finds no pending changes.
+		pp.timer.Stop()
+	}
+
+	var errs []error
+
+	for _, err := range p.failed {
+		errs = append(errs, err)
+	}
+
+	p.failed = make(map[string]error)
+
+	p.mu.Unlock()
+
+	for zone, pp := range pending {
+		err := p.publish(zone, pp.changes)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("publish of the zone %s (%d changes): %w", zone, pp.changes, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
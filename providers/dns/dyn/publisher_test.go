@@ -0,0 +1,151 @@
+package dyn
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher_schedule(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		published = make(map[string][]int)
+		wg        sync.WaitGroup
+	)
+
+	wg.Add(2)
+
+	p := newPublisher(50*time.Millisecond, time.Second, func(zone string, changes int) error {
+		defer wg.Done()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		published[zone] = append(published[zone], changes)
+
+		return nil
+	})
+
+	for range 3 {
+		p.schedule("example.com.")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.schedule("example.org.")
+
+	wg.Wait()
+
+	assert.Equal(t, map[string][]int{"example.com.": {3}, "example.org.": {1}}, published)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	assert.Empty(t, p.pending)
+}
+
+func TestPublisher_schedule_afterPublish(t *testing.T) {
+	published := make(chan int, 2)
+
+	p := newPublisher(10*time.Millisecond, time.Second, func(_ string, changes int) error {
+		published <- changes
+		return nil
+	})
+
+	p.schedule("example.com.")
+	assert.Equal(t, 1, <-published)
+
+	p.schedule("example.com.")
+	assert.Equal(t, 1, <-published)
+}
+
+func TestPublisher_schedule_maxDelay(t *testing.T) {
+	published := make(chan int, 10)
+
+	p := newPublisher(30*time.Millisecond, 60*time.Millisecond, func(_ string, changes int) error {
+		published <- changes
+		return nil
+	})
+
+	// a change every 10ms never lets the delay expire.
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		p.schedule("example.com.")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case changes := <-published:
+		assert.Positive(t, changes)
+	default:
+		require.Fail(t, "no publish before the maximum delay")
+	}
+
+	require.NoError(t, p.close())
+}
+
+func TestPublisher_failure(t *testing.T) {
+	done := make(chan struct{})
+
+	p := newPublisher(10*time.Millisecond, time.Second, func(_ string, _ int) error {
+		defer close(done)
+		return errors.New("boom")
+	})
+
+	p.schedule("example.com.")
+	<-done
+
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return p.failed["example.com."] != nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, p.failure("example.org."))
+	require.EqualError(t, p.failure("example.com."), "publish of the zone example.com. (1 changes): boom")
+
+	// reported once.
+	require.NoError(t, p.failure("example.com."))
+}
+
+func TestPublisher_close(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		published = make(map[string][]int)
+	)
+
+	p := newPublisher(time.Hour, time.Hour, func(zone string, changes int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		published[zone] = append(published[zone], changes)
+
+		return nil
+	})
+
+	p.schedule("example.com.")
+	p.schedule("example.com.")
+	p.schedule("example.org.")
+
+	require.NoError(t, p.close())
+
+	assert.Equal(t, map[string][]int{"example.com.": {2}, "example.org.": {1}}, published)
+
+	// nothing left to publish.
+	require.NoError(t, p.close())
+	assert.Equal(t, map[string][]int{"example.com.": {2}, "example.org.": {1}}, published)
+}
+
+func TestPublisher_close_error(t *testing.T) {
+	p := newPublisher(time.Hour, time.Hour, func(_ string, _ int) error {
+		return errors.New("boom")
+	})
+
+	p.schedule("example.com.")
+
+	require.EqualError(t, p.close(), "publish of the zone example.com. (1 changes): boom")
+}
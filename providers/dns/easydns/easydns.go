@@ -5,17 +5,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/easydns/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -26,6 +27,9 @@ const (
 	EnvToken    = envNamespace + "TOKEN"
 	EnvKey      = envNamespace + "KEY"
 
+	EnvSecondaryToken = envNamespace + "SECONDARY_TOKEN"
+	EnvSecondaryKey   = envNamespace + "SECONDARY_KEY"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -35,10 +39,14 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Endpoint           *url.URL      `yaml:"-"`
-	EndpointUrl        string        `yaml:"endpoint"`
-	Token              string        `yaml:"token"`
-	Key                string        `yaml:"key"`
+	Endpoint    *url.URL `yaml:"-"`
+	EndpointUrl string   `yaml:"endpoint"`
+	Token       string   `yaml:"token"`
+	Key         string   `yaml:"key"`
+	// SecondaryToken and SecondaryKey are tried when the requests are rejected with the token and key,
+	// to rotate the credentials without downtime.
+	SecondaryToken     string        `yaml:"secondaryToken"`
+	SecondaryKey       string        `yaml:"secondaryKey"`
 	TTL                int           `yaml:"ttl"`
 	HTTPClient         *http.Client  `yaml:"-"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
@@ -77,8 +85,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance.
@@ -98,6 +105,8 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 	config.Token = values[EnvToken]
 	config.Key = values[EnvKey]
+	config.SecondaryToken = env.GetOrFile(EnvSecondaryToken)
+	config.SecondaryKey = env.GetOrFile(EnvSecondaryKey)
 
 	return NewDNSProviderConfig(config)
 }
@@ -105,7 +114,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +148,16 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("easydns: the API key is missing")
 	}
 
+	if (config.SecondaryToken == "") != (config.SecondaryKey == "") {
+		return nil, errors.New("easydns: the secondary API token and key must be set together")
+	}
+
 	client := internal.NewClient(config.Token, config.Key)
 
+	if config.SecondaryToken != "" {
+		client.AddCredentials(config.SecondaryToken, config.SecondaryKey)
+	}
+
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
 	}
@@ -149,14 +166,20 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.BaseURL = config.Endpoint
 	}
 
-	return &DNSProvider{config: config, client: client, recordIDs: map[string]string{}}, nil
+	return &DNSProvider{config: config, client: client, recordIDs: challengestore.New[string]()}, nil
+}
+
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := d.findZone(ctx, dns01.UnFqdn(info.EffectiveFQDN))
 	if err != nil {
@@ -186,11 +209,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("easydns: error adding zone record: %w", err)
 	}
 
-	key := getMapKey(info.EffectiveFQDN, info.Value)
-
-	d.recordIDsMu.Lock()
-	d.recordIDs[key] = recordID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, recordID)
 
 	return nil
 }
@@ -199,14 +218,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	key := getMapKey(info.EffectiveFQDN, info.Value)
-
-	d.recordIDsMu.Lock()
-	recordID, exists := d.recordIDs[key]
-	d.recordIDsMu.Unlock()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
+	recordID, exists := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !exists {
 		return nil
 	}
@@ -222,9 +236,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	err = d.client.DeleteRecord(ctx, dns01.UnFqdn(authZone), recordID)
 
-	d.recordIDsMu.Lock()
-	defer delete(d.recordIDs, key)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	if err != nil {
 		return fmt.Errorf("easydns: %w", err)
@@ -245,10 +257,6 @@ func (d *DNSProvider) Sequential() time.Duration {
 	return d.config.SequenceInterval
 }
 
-func getMapKey(fqdn, value string) string {
-	return fqdn + "|" + value
-}
-
 func (d *DNSProvider) findZone(ctx context.Context, domain string) (string, error) {
 	var errAll error
 
@@ -21,7 +21,9 @@ const envDomain = envNamespace + "DOMAIN"
 var envTest = tester.NewEnvTest(
 	EnvEndpoint,
 	EnvToken,
-	EnvKey).
+	EnvKey,
+	EnvSecondaryToken,
+	EnvSecondaryKey).
 	WithDomain(envDomain)
 
 func setupTest(t *testing.T) (*DNSProvider, *http.ServeMux) {
@@ -127,6 +129,24 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			},
 			expected: "easydns: the API key is missing",
 		},
+		{
+			desc: "secondary credentials",
+			config: &Config{
+				Token:          "TOKEN",
+				Key:            "KEY",
+				SecondaryToken: "TOKEN2",
+				SecondaryKey:   "KEY2",
+			},
+		},
+		{
+			desc: "missing secondary key",
+			config: &Config{
+				Token:          "TOKEN",
+				Key:            "KEY",
+				SecondaryToken: "TOKEN2",
+			},
+			expected: "easydns: the secondary API token and key must be set together",
+		},
 	}
 
 	for _, test := range testCases {
@@ -220,7 +240,9 @@ func TestDNSProvider_Present(t *testing.T) {
 
 	err := provider.Present("example.com", "token", "keyAuth")
 	require.NoError(t, err)
-	require.Contains(t, provider.recordIDs, "_acme-challenge.example.com.|pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM")
+	recordID, ok := provider.recordIDs.Get("token", "_acme-challenge.example.com.")
+	require.True(t, ok)
+	require.NotEmpty(t, recordID)
 }
 
 func TestDNSProvider_Cleanup_WhenRecordIdNotSet_NoOp(t *testing.T) {
@@ -319,7 +341,7 @@ func TestDNSProvider_Cleanup_WhenRecordIdSet_DeletesTxtRecord(t *testing.T) {
 		}
 	})
 
-	provider.recordIDs["_acme-challenge.example.com.|pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"] = "123456"
+	provider.recordIDs.Set("token", "_acme-challenge.example.com.", "123456")
 	err := provider.CleanUp("example.com", "token", "keyAuth")
 	require.NoError(t, err)
 }
@@ -379,7 +401,7 @@ func TestDNSProvider_Cleanup_WhenHttpError_ReturnsError(t *testing.T) {
 		}
 	})
 
-	provider.recordIDs["_acme-challenge.example.com.|pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"] = "123456"
+	provider.recordIDs.Set("token", "_acme-challenge.example.com.", "123456")
 	err := provider.CleanUp("example.com", "token", "keyAuth")
 	expectedError := fmt.Sprintf("easydns: unexpected status code: [status code: 406] body: %v", errorMessage)
 	require.EqualError(t, err, expectedError)
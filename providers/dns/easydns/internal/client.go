@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,10 +17,15 @@ import (
 // DefaultBaseURL the default API endpoint.
 const DefaultBaseURL = "https://rest.easydns.net"
 
+// Credentials are an API token and key pair.
+type Credentials struct {
+	Token string
+	Key   string
+}
+
 // Client the EasyDNS API client.
 type Client struct {
-	token string
-	key   string
+	credentials []Credentials
 
 	BaseURL    *url.URL
 	HTTPClient *http.Client
@@ -30,13 +36,18 @@ func NewClient(token string, key string) *Client {
 	baseURL, _ := url.Parse(DefaultBaseURL)
 
 	return &Client{
-		token:      token,
-		key:        key,
-		BaseURL:    baseURL,
-		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		credentials: []Credentials{{Token: token, Key: key}},
+		BaseURL:     baseURL,
+		HTTPClient:  &http.Client{Timeout: 5 * time.Second},
 	}
 }
 
+// AddCredentials adds a token and key pair, tried in order when the requests are rejected with the previous ones.
+// It allows the rotation of the credentials without downtime.
+func (c *Client) AddCredentials(token string, key string) {
+	c.credentials = append(c.credentials, Credentials{Token: token, Key: key})
+}
+
 func (c *Client) ListZones(ctx context.Context, domain string) ([]ZoneRecord, error) {
 	endpoint := c.BaseURL.JoinPath("zones", "records", "all", domain)
 
@@ -95,11 +106,9 @@ func (c *Client) DeleteRecord(ctx context.Context, domain, recordID string) erro
 }
 
 func (c *Client) do(req *http.Request, result any) error {
-	req.SetBasicAuth(c.token, c.key)
-
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return errutils.NewHTTPDoError(req, err)
+		return err
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -125,6 +134,36 @@ func (c *Client) do(req *http.Request, result any) error {
 	return nil
 }
 
+// send sends the request with the credentials tried in order:
+// the next credentials are used only when the request is rejected as unauthorized.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for i, credentials := range c.credentials {
+		if i > 0 {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("unable to rewind the request body: %w", err)
+			}
+
+			req.Body = body
+		}
+
+		req.SetBasicAuth(credentials.Token, credentials.Key)
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, errutils.NewHTTPDoError(req, err)
+		}
+
+		if i == len(c.credentials)-1 || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+			return resp, nil
+		}
+
+		_ = resp.Body.Close()
+	}
+
+	return nil, errors.New("no credentials")
+}
+
 func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
 	buf := new(bytes.Buffer)
 
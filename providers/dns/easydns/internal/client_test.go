@@ -134,3 +134,34 @@ func TestClient_DeleteRecord(t *testing.T) {
 	err := client.DeleteRecord(context.Background(), "example.com", "xxx")
 	require.NoError(t, err)
 }
+
+func TestClient_AddRecord_secondaryCredentials(t *testing.T) {
+	client := setupTest(t, http.MethodPut, "/zones/records/add/example.com/TXT", http.StatusCreated, "add-record.json")
+
+	client.credentials = []Credentials{{Token: "revoked", Key: "revoked"}}
+	client.AddCredentials("tok", "k")
+
+	record := ZoneRecord{
+		Domain:   "example.com",
+		Host:     "test631",
+		Type:     "TXT",
+		Rdata:    "txt",
+		TTL:      "300",
+		Priority: "0",
+	}
+
+	recordID, err := client.AddRecord(context.Background(), "example.com", record)
+	require.NoError(t, err)
+
+	assert.Equal(t, "xxx", recordID)
+}
+
+func TestClient_DeleteRecord_allCredentialsRejected(t *testing.T) {
+	client := setupTest(t, http.MethodDelete, "/zones/records/example.com/xxx", http.StatusOK, "")
+
+	client.credentials = []Credentials{{Token: "revoked", Key: "revoked"}}
+	client.AddCredentials("invalid", "invalid")
+
+	err := client.DeleteRecord(context.Background(), "example.com", "xxx")
+	require.ErrorContains(t, err, "[status code: 401]")
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"gopkg.in/ini.v1"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"slices"
 	"strings"
 	"time"
@@ -15,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -72,9 +73,9 @@ func DefaultConfig() *Config {
 
 func GetYamlTemple() string {
 	return `# config.yaml
-propagationTimeout: 600000000000 # 10 minutes in nanoseconds
-pollingInterval: 30000000000     # 30 seconds in nanoseconds
-ttl: 3600                        # TTL (Time-To-Live) value in seconds
+propagationTimeout: 10m # 传播超时时间，时长字符串（10m、90s）或整数秒数
+pollingInterval: 30s    # 轮询间隔，时长字符串（10m、90s）或整数秒数
+ttl: 3600               # TTL (Time-To-Live) value in seconds
 
 config: |
   host = example.com
@@ -122,15 +123,15 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
 	config.Config.MaxBody = maxBody
 	iniData := strings.NewReader(config.RawConfig)
-	err = ini.MapTo(config.Config, iniData)
+	err = ini.MapTo(&config.Config, iniData)
 	if err != nil {
-		log.Fatalf("error: %v", err)
+		return nil, fmt.Errorf("edgedns: %w", err)
 	}
 	return config, nil
 }
@@ -154,7 +155,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := getZone(info.EffectiveFQDN)
 	if err != nil {
@@ -206,7 +207,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := getZone(info.EffectiveFQDN)
 	if err != nil {
@@ -229,3 +229,24 @@ func TestNewDefaultConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfig(t *testing.T) {
+	raw := `
+ttl: 3600
+config: |
+  host = akaa-xxx.luna.akamaiapis.net
+  client_token = ct
+  client_secret = cs
+  access_token = at
+`
+
+	config, err := ParseConfig([]byte(raw))
+	require.NoError(t, err)
+
+	require.Equal(t, 3600, config.TTL)
+	require.Equal(t, "akaa-xxx.luna.akamaiapis.net", config.Host)
+	require.Equal(t, "ct", config.ClientToken)
+	require.Equal(t, "cs", config.ClientSecret)
+	require.Equal(t, "at", config.AccessToken)
+	require.Equal(t, maxBody, config.MaxBody)
+}
@@ -205,5 +205,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code %d] %w", resp.StatusCode, response)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, response).WithCode(response.ErrorCode)
 }
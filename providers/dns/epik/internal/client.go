@@ -167,5 +167,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return &apiErr
+	return errutils.NewAPIError(req, resp.StatusCode, raw, &apiErr)
 }
@@ -6,7 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"os"
 	"os/exec"
 	"time"
@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -87,7 +88,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +142,7 @@ func (d *DNSProvider) run(ctx context.Context, command, domain, token, keyAuth s
 	if d.config.Mode == "RAW" {
 		args = []string{command, "--", domain, token, keyAuth}
 	} else {
-		info := dns01.GetChallengeInfo(domain, keyAuth)
+		info := rawrecord.GetChallengeInfo(domain, keyAuth)
 		args = []string{command, info.EffectiveFQDN, info.Value}
 	}
 
@@ -0,0 +1,158 @@
+package exoscale
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	egoscalev1 "github.com/exoscale/egoscale"
+	egoscale "github.com/exoscale/egoscale/v2"
+)
+
+// dnsClient is the DNS API used by the provider: the API v2, or the deprecated API v1 (useV1).
+type dnsClient interface {
+	// findZoneID returns the ID of the zone, empty if the zone could not be found.
+	findZoneID(ctx context.Context, zoneName string) (string, error)
+	createTXTRecord(ctx context.Context, zoneID, name, value string, ttl int64) error
+	// findTXTRecordID returns the ID of the TXT record, empty if the record could not be found.
+	findTXTRecordID(ctx context.Context, zoneID, name, value string) (string, error)
+	deleteRecord(ctx context.Context, zoneID, recordID string) error
+}
+
+// v2Client uses the API v2, the requests are sent to the endpoint of the API zone.
+type v2Client struct {
+	client  *egoscale.Client
+	apiZone string
+}
+
+func newV2Client(config *Config) (*v2Client, error) {
+	client, err := egoscale.NewClient(
+		config.APIKey,
+		config.APISecret,
+		egoscale.ClientOptWithAPIEndpoint(config.Endpoint),
+		egoscale.ClientOptWithTimeout(config.HTTPTimeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v2Client{client: client, apiZone: config.APIZone}, nil
+}
+
+func (c *v2Client) findZoneID(ctx context.Context, zoneName string) (string, error) {
+	zones, err := c.client.ListDNSDomains(ctx, c.apiZone)
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving DNS zones: %w", err)
+	}
+
+	for _, zone := range zones {
+		if deref(zone.UnicodeName) == zoneName {
+			return deref(zone.ID), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *v2Client) createTXTRecord(ctx context.Context, zoneID, name, value string, ttl int64) error {
+	record := egoscale.DNSDomainRecord{
+		Name:    pointer(name),
+		TTL:     pointer(ttl),
+		Content: pointer(value),
+		Type:    pointer("TXT"),
+	}
+
+	_, err := c.client.CreateDNSDomainRecord(ctx, c.apiZone, zoneID, &record)
+
+	return err
+}
+
+func (c *v2Client) findTXTRecordID(ctx context.Context, zoneID, name, value string) (string, error) {
+	records, err := c.client.ListDNSDomainRecords(ctx, c.apiZone, zoneID)
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving DNS records: %w", err)
+	}
+
+	for _, record := range records {
+		if deref(record.Name) == name && deref(record.Type) == "TXT" && deref(record.Content) == value {
+			return deref(record.ID), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *v2Client) deleteRecord(ctx context.Context, zoneID, recordID string) error {
+	return c.client.DeleteDNSDomainRecord(ctx, c.apiZone, zoneID, &egoscale.DNSDomainRecord{ID: &recordID})
+}
+
+// v1Client uses the deprecated DNS API v1, the zones are identified by their name.
+type v1Client struct {
+	client *egoscalev1.Client
+}
+
+func newV1Client(config *Config) *v1Client {
+	return &v1Client{
+		client: egoscalev1.NewClient(config.Endpoint, config.APIKey, config.APISecret,
+			egoscalev1.WithoutV2Client(),
+			egoscalev1.WithTimeout(config.HTTPTimeout),
+		),
+	}
+}
+
+func (c *v1Client) findZoneID(ctx context.Context, zoneName string) (string, error) {
+	domain, err := c.client.GetDomain(ctx, zoneName)
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving DNS zone: %w", err)
+	}
+
+	return domain.Name, nil
+}
+
+func (c *v1Client) createTXTRecord(ctx context.Context, zoneID, name, value string, ttl int64) error {
+	record := egoscalev1.DNSRecord{
+		Name:       name,
+		TTL:        int(ttl),
+		Content:    value,
+		RecordType: "TXT",
+	}
+
+	_, err := c.client.CreateRecord(ctx, zoneID, record)
+
+	return err
+}
+
+func (c *v1Client) findTXTRecordID(ctx context.Context, zoneID, name, value string) (string, error) {
+	records, err := c.client.GetRecordsWithFilters(ctx, zoneID, name, "TXT")
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving DNS records: %w", err)
+	}
+
+	for _, record := range records {
+		if record.Content == value {
+			return strconv.FormatInt(record.ID, 10), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *v1Client) deleteRecord(ctx context.Context, zoneID, recordID string) error {
+	id, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	return c.client.DeleteRecord(ctx, zoneID, id)
+}
+
+func pointer[T string | int | int32 | int64](v T) *T { return &v }
+
+func deref[T string | int | int32 | int64](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+
+	return *v
+}
@@ -0,0 +1,86 @@
+package exoscale
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV1Client(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	writeJSON := func(rw http.ResponseWriter, status int, body string) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(body))
+	}
+
+	mux.HandleFunc("GET /v1/domains/example.com", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "A:B", req.Header.Get("X-DNS-TOKEN"))
+
+		writeJSON(rw, http.StatusOK, `{"domain":{"id":1,"name":"example.com"}}`)
+	})
+
+	mux.HandleFunc("GET /v1/domains/example.org", func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(rw, http.StatusNotFound, `{"message":"not found"}`)
+	})
+
+	mux.HandleFunc("POST /v1/domains/example.com/records", func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		var payload map[string]map[string]any
+		require.NoError(t, json.Unmarshal(body, &payload))
+
+		assert.Equal(t, "_acme-challenge", payload["record"]["name"])
+		assert.Equal(t, "TXT", payload["record"]["record_type"])
+		assert.Equal(t, "value", payload["record"]["content"])
+		assert.EqualValues(t, 120, payload["record"]["ttl"])
+
+		writeJSON(rw, http.StatusOK, `{"record":{"id":12}}`)
+	})
+
+	mux.HandleFunc("GET /v1/domains/example.com/records", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "_acme-challenge", req.URL.Query().Get("name"))
+		assert.Equal(t, "TXT", req.URL.Query().Get("record_type"))
+
+		writeJSON(rw, http.StatusOK, `[{"record":{"id":11,"content":"other"}},{"record":{"id":12,"content":"value"}}]`)
+	})
+
+	mux.HandleFunc("DELETE /v1/domains/example.com/records/12", func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(rw, http.StatusOK, `{}`)
+	})
+
+	config := NewDefaultConfig()
+	config.APIKey = "A"
+	config.APISecret = "B"
+	config.Endpoint = server.URL
+
+	client := newV1Client(config)
+	ctx := context.Background()
+
+	zoneID, err := client.findZoneID(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", zoneID)
+
+	_, err = client.findZoneID(ctx, "example.org")
+	require.Error(t, err)
+
+	err = client.createTXTRecord(ctx, zoneID, "_acme-challenge", "value", 120)
+	require.NoError(t, err)
+
+	recordID, err := client.findTXTRecordID(ctx, zoneID, "_acme-challenge", "value")
+	require.NoError(t, err)
+	assert.Equal(t, "12", recordID)
+
+	err = client.deleteRecord(ctx, zoneID, recordID)
+	require.NoError(t, err)
+}
@@ -5,21 +5,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
+	"slices"
 	"time"
 
-	egoscale "github.com/exoscale/egoscale/v2"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/rawrecord"
 )
 
 // Default Exoscale API endpoint.
 const defaultBaseURL = "https://api.exoscale.com/v2"
 
+// Default endpoint of the deprecated Exoscale DNS API (v1).
+const defaultV1BaseURL = "https://api.exoscale.com/dns"
+
 // Default Exosacle API zone.
 // Each data center location hosts the API and API zone determines which one to connect to.
 const defaultAPIZone = "ch-gva-2"
 
+// apiZones are the API zones (data center locations) of Exoscale,
+// the requests are sent to the endpoint of the zone (`api-<zone>.exoscale.com`).
+var apiZones = []string{"ch-gva-2", "ch-dk-2", "de-fra-1", "de-muc-1", "at-vie-1", "at-vie-2", "bg-sof-1"}
+
 // Environment variables names.
 const (
 	envNamespace = "EXOSCALE_"
@@ -28,6 +37,7 @@ const (
 	EnvAPIKey    = envNamespace + "API_KEY"
 	EnvEndpoint  = envNamespace + "ENDPOINT"
 	EnvAPIZone   = envNamespace + "API_ZONE"
+	EnvUseV1     = envNamespace + "USE_V1"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -40,6 +50,8 @@ type Config struct {
 	APIKey             string        `yaml:"apiKey"`
 	APISecret          string        `yaml:"apiSecret"`
 	Endpoint           string        `yaml:"endpoint"`
+	APIZone            string        `yaml:"apiZone"`
+	UseV1              bool          `yaml:"useV1"`
 	HTTPTimeout        time.Duration `yaml:"httpTimeout"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
@@ -49,6 +61,8 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		APIZone:            env.GetOrDefaultString(EnvAPIZone, defaultAPIZone),
+		UseV1:              env.GetOrDefaultBool(EnvUseV1, false),
 		TTL:                int64(env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL)),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -59,6 +73,7 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
+		APIZone:            defaultAPIZone,
 		TTL:                int64(dns01.DefaultTTL),
 		PropagationTimeout: dns01.DefaultPropagationTimeout,
 		PollingInterval:    dns01.DefaultPollingInterval,
@@ -70,7 +85,9 @@ func GetYamlTemple() string {
 	return `# config.yaml
 apiKey: "your_api_key"                     # API 密钥
 apiSecret: "your_api_secret"               # API 秘密
-endpoint: "https://api.example.com"        # API 端点
+endpoint: ""                               # API 端点，默认为 https://api.exoscale.com/v2（useV1 时为 https://api.exoscale.com/dns）
+apiZone: "ch-gva-2"                        # API 区域：ch-gva-2, ch-dk-2, de-fra-1, de-muc-1, at-vie-1, at-vie-2, bg-sof-1
+useV1: false                               # 使用已弃用的 DNS API v1
 httpTimeout: 60s                           # HTTP 超时时间，单位为秒
 propagationTimeout: 60s                    # 传播超时时间，单位为秒
 pollingInterval: 2s                        # 轮询间隔时间，单位为秒
@@ -79,9 +96,8 @@ ttl: 120                                   # TTL 值，单位为秒`
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	config  *Config
-	client  *egoscale.Client
-	apiZone string
+	config *Config
+	client dnsClient
 }
 
 // NewDNSProvider Credentials must be passed in the environment variables:
@@ -103,7 +119,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -120,53 +136,55 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("exoscale: credentials missing")
 	}
 
+	if config.UseV1 {
+		log.Warnf("exoscale: the DNS API v1 is deprecated, remove useV1 to use the API v2")
+
+		if config.Endpoint == "" {
+			config.Endpoint = defaultV1BaseURL
+		}
+
+		return &DNSProvider{config: config, client: newV1Client(config)}, nil
+	}
+
+	if config.APIZone == "" {
+		config.APIZone = defaultAPIZone
+	}
+
+	if !slices.Contains(apiZones, config.APIZone) && config.Endpoint == "" {
+		return nil, fmt.Errorf("exoscale: unknown API zone %q, the endpoint must be set", config.APIZone)
+	}
+
 	if config.Endpoint == "" {
 		config.Endpoint = defaultBaseURL
 	}
 
-	client, err := egoscale.NewClient(
-		config.APIKey,
-		config.APISecret,
-		egoscale.ClientOptWithAPIEndpoint(config.Endpoint),
-		egoscale.ClientOptWithTimeout(config.HTTPTimeout),
-	)
+	client, err := newV2Client(config)
 	if err != nil {
 		return nil, fmt.Errorf("exoscale: initializing client: %w", err)
 	}
 
-	return &DNSProvider{
-		client:  client,
-		config:  config,
-		apiZone: env.GetOrDefaultString(EnvAPIZone, defaultAPIZone),
-	}, nil
+	return &DNSProvider{config: config, client: client}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, recordName, err := d.findZoneAndRecordName(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
 
-	zone, err := d.findExistingZone(zoneName)
+	zoneID, err := d.client.findZoneID(ctx, zoneName)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
-	if zone == nil {
+	if zoneID == "" {
 		return fmt.Errorf("exoscale: zone %q not found", zoneName)
 	}
 
-	record := egoscale.DNSDomainRecord{
-		Name:    pointer(recordName),
-		TTL:     pointer(d.config.TTL),
-		Content: pointer(info.Value),
-		Type:    pointer("TXT"),
-	}
-
-	_, err = d.client.CreateDNSDomainRecord(ctx, d.apiZone, deref(zone.ID), &record)
+	err = d.client.createTXTRecord(ctx, zoneID, recordName, info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("exoscale: error while creating DNS record: %w", err)
 	}
@@ -177,28 +195,28 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 // CleanUp removes the record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, recordName, err := d.findZoneAndRecordName(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
 
-	zone, err := d.findExistingZone(zoneName)
+	zoneID, err := d.client.findZoneID(ctx, zoneName)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
-	if zone == nil {
+	if zoneID == "" {
 		return fmt.Errorf("exoscale: zone %q not found", zoneName)
 	}
 
-	recordID, err := d.findExistingRecordID(deref(zone.ID), recordName, info.Value)
+	recordID, err := d.client.findTXTRecordID(ctx, zoneID, recordName, info.Value)
 	if err != nil {
-		return err
+		return fmt.Errorf("exoscale: %w", err)
 	}
 
 	if recordID != "" {
-		err = d.client.DeleteDNSDomainRecord(ctx, d.apiZone, deref(zone.ID), &egoscale.DNSDomainRecord{ID: &recordID})
+		err = d.client.deleteRecord(ctx, zoneID, recordID)
 		if err != nil {
 			return fmt.Errorf("exoscale: error while deleting DNS record: %w", err)
 		}
@@ -213,44 +231,6 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
-// findExistingZone Query Exoscale to find an existing zone for this name.
-// Returns nil result if no zone could be found.
-func (d *DNSProvider) findExistingZone(zoneName string) (*egoscale.DNSDomain, error) {
-	ctx := context.Background()
-
-	zones, err := d.client.ListDNSDomains(ctx, d.apiZone)
-	if err != nil {
-		return nil, fmt.Errorf("error while retrieving DNS zones: %w", err)
-	}
-
-	for _, zone := range zones {
-		if zone.UnicodeName != nil && deref(zone.UnicodeName) == zoneName {
-			return &zone, nil
-		}
-	}
-
-	return nil, nil
-}
-
-// findExistingRecordID Query Exoscale to find an existing record for this name.
-// Returns empty result if no record could be found.
-func (d *DNSProvider) findExistingRecordID(zoneID, recordName, value string) (string, error) {
-	ctx := context.Background()
-
-	records, err := d.client.ListDNSDomainRecords(ctx, d.apiZone, zoneID)
-	if err != nil {
-		return "", fmt.Errorf("error while retrieving DNS records: %w", err)
-	}
-
-	for _, record := range records {
-		if deref(record.Name) == recordName && deref(record.Type) == "TXT" && deref(record.Content) == value {
-			return deref(record.ID), nil
-		}
-	}
-
-	return "", nil
-}
-
 // findZoneAndRecordName Extract DNS zone and DNS entry name.
 func (d *DNSProvider) findZoneAndRecordName(fqdn string) (string, string, error) {
 	zone, err := dns01.FindZoneByFqdn(fqdn)
@@ -267,14 +247,3 @@ func (d *DNSProvider) findZoneAndRecordName(fqdn string) (string, string, error)
 
 	return zone, subDomain, nil
 }
-
-func pointer[T string | int | int32 | int64](v T) *T { return &v }
-
-func deref[T string | int | int32 | int64](v *T) T {
-	if v == nil {
-		var zero T
-		return zero
-	}
-
-	return *v
-}
@@ -4,9 +4,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -81,6 +83,8 @@ func TestNewDNSProviderConfig(t *testing.T) {
 		desc      string
 		apiKey    string
 		apiSecret string
+		apiZone   string
+		endpoint  string
 		expected  string
 	}{
 		{
@@ -88,6 +92,26 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			apiKey:    "123",
 			apiSecret: "456",
 		},
+		{
+			desc:      "API zone",
+			apiKey:    "123",
+			apiSecret: "456",
+			apiZone:   "de-fra-1",
+		},
+		{
+			desc:      "unknown API zone with endpoint",
+			apiKey:    "123",
+			apiSecret: "456",
+			apiZone:   "xx-new-1",
+			endpoint:  "https://api.example.com/v2",
+		},
+		{
+			desc:      "unknown API zone",
+			apiKey:    "123",
+			apiSecret: "456",
+			apiZone:   "xx-new-1",
+			expected:  `exoscale: unknown API zone "xx-new-1", the endpoint must be set`,
+		},
 		{
 			desc:     "missing credentials",
 			expected: "exoscale: credentials missing",
@@ -109,6 +133,10 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			config := NewDefaultConfig()
 			config.APIKey = test.apiKey
 			config.APISecret = test.apiSecret
+			if test.apiZone != "" {
+				config.APIZone = test.apiZone
+			}
+			config.Endpoint = test.endpoint
 
 			p, err := NewDNSProviderConfig(config)
 
@@ -124,6 +152,36 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+apiKey: A
+apiSecret: B
+apiZone: at-vie-1
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "at-vie-1", config.APIZone)
+	assert.False(t, config.UseV1)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	require.IsType(t, &v2Client{}, p.client)
+	assert.Equal(t, "at-vie-1", p.client.(*v2Client).apiZone)
+	assert.Equal(t, defaultBaseURL, config.Endpoint)
+
+	config, err = ParseConfig([]byte("apiKey: A\napiSecret: B\nuseV1: true\n"))
+	require.NoError(t, err)
+
+	p, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.IsType(t, &v1Client{}, p.client)
+	assert.Equal(t, defaultV1BaseURL, config.Endpoint)
+}
+
 func TestDNSProvider_FindZoneAndRecordName(t *testing.T) {
 	config := NewDefaultConfig()
 	config.APIKey = "example@example.com"
@@ -203,3 +261,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
@@ -0,0 +1,72 @@
+package fake
+
+import (
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertPresented checks that Present was called for the domain.
+func (d *DNSProvider) AssertPresented(t TestingT, domain string) bool {
+	t.Helper()
+
+	for _, call := range d.CallsOf(CallPresent) {
+		if call.Domain == domain {
+			return true
+		}
+	}
+
+	t.Errorf("fake: expected Present to be called for %q", domain)
+
+	return false
+}
+
+// AssertCleanedUp checks that CleanUp was called for the domain.
+func (d *DNSProvider) AssertCleanedUp(t TestingT, domain string) bool {
+	t.Helper()
+
+	for _, call := range d.CallsOf(CallCleanUp) {
+		if call.Domain == domain {
+			return true
+		}
+	}
+
+	t.Errorf("fake: expected CleanUp to be called for %q", domain)
+
+	return false
+}
+
+// AssertCallCount checks the number of calls of one kind (CallPresent or CallCleanUp).
+func (d *DNSProvider) AssertCallCount(t TestingT, kind string, expected int) bool {
+	t.Helper()
+
+	actual := len(d.CallsOf(kind))
+	if actual != expected {
+		t.Errorf("fake: expected %d %s calls, got %d", expected, kind, actual)
+		return false
+	}
+
+	return true
+}
+
+// AssertNoRecords checks that every presented record has been cleaned up.
+func (d *DNSProvider) AssertNoRecords(t TestingT) bool {
+	t.Helper()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.records) == 0 {
+		return true
+	}
+
+	for fqdn, records := range d.records {
+		t.Errorf("fake: %d record(s) left at %s", len(records), dns01.UnFqdn(fqdn))
+	}
+
+	return false
+}
@@ -0,0 +1,318 @@
+// Package fake implements a scriptable in-memory DNS provider for testing DNS-01 orchestration.
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "FAKE_"
+
+	EnvPresentLatency   = envNamespace + "PRESENT_LATENCY"
+	EnvCleanUpLatency   = envNamespace + "CLEANUP_LATENCY"
+	EnvPropagationDelay = envNamespace + "PROPAGATION_DELAY"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvSequenceInterval   = envNamespace + "SEQUENCE_INTERVAL"
+)
+
+// Call kinds.
+const (
+	CallPresent  = "present"
+	CallCleanUp  = "cleanup"
+	CallSetCNAME = "setcname"
+)
+
+// ErrInjected is returned by the calls configured to fail.
+var ErrInjected = errors.New("injected failure")
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// PresentLatency is added to every Present call.
+	PresentLatency time.Duration `yaml:"presentLatency"`
+	// CleanUpLatency is added to every CleanUp call.
+	CleanUpLatency time.Duration `yaml:"cleanUpLatency"`
+	// PropagationDelay is the time before a presented record becomes visible.
+	PropagationDelay time.Duration `yaml:"propagationDelay"`
+
+	// FailPresentOn lists the Present calls (1-based) that must fail.
+	FailPresentOn []int `yaml:"failPresentOn"`
+	// FailCleanUpOn lists the CleanUp calls (1-based) that must fail.
+	FailCleanUpOn []int `yaml:"failCleanUpOn"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		PresentLatency:     env.GetOrDefaultSecond(EnvPresentLatency, 0),
+		CleanUpLatency:     env.GetOrDefaultSecond(EnvCleanUpLatency, 0),
+		PropagationDelay:   env.GetOrDefaultSecond(EnvPropagationDelay, 0),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, 0),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+presentLatency: 0s                    # 每次 Present 调用的延迟
+cleanUpLatency: 0s                    # 每次 CleanUp 调用的延迟
+propagationDelay: 0s                  # 记录生效前的传播延迟
+failPresentOn: [ ]                    # 第 N 次 Present 调用失败（从 1 开始）
+failCleanUpOn: [ ]                    # 第 N 次 CleanUp 调用失败（从 1 开始）
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间
+sequenceInterval: 0s                  # 序列间隔时间，0 表示不按顺序执行`
+}
+
+// Call is a recorded Present, CleanUp or SetCNAME call.
+// The Value of a SetCNAME call is the target of the CNAME.
+type Call struct {
+	Kind    string
+	Domain  string
+	Token   string
+	KeyAuth string
+	FQDN    string
+	Value   string
+	Err     error
+	At      time.Time
+}
+
+type record struct {
+	value     string
+	visibleAt time.Time
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	mu      sync.Mutex
+	calls   []Call
+	records map[string][]record
+	cnames  map[string]string
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// NewDNSProvider returns a DNSProvider instance configured from the environment.
+func NewDNSProvider() (*DNSProvider, error) {
+	return NewDNSProviderConfig(NewDefaultConfig())
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for fake.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("fake: the configuration of the DNS provider is nil")
+	}
+
+	for _, n := range slices.Concat(config.FailPresentOn, config.FailCleanUpOn) {
+		if n < 1 {
+			return nil, fmt.Errorf("fake: invalid call number %d, calls are numbered from 1", n)
+		}
+	}
+
+	return &DNSProvider{
+		config:  config,
+		records: make(map[string][]record),
+		cnames:  make(map[string]string),
+		now:     time.Now,
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Sequential All DNS challenges for this provider will be resolved sequentially.
+// Returns the interval between each iteration.
+func (d *DNSProvider) Sequential() time.Duration {
+	return d.config.SequenceInterval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	time.Sleep(d.config.PresentLatency)
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	call := d.record(CallPresent, domain, token, keyAuth, info)
+
+	if slices.Contains(d.config.FailPresentOn, d.count(CallPresent)) {
+		call.Err = fmt.Errorf("fake: present %s: %w", info.EffectiveFQDN, ErrInjected)
+		return call.Err
+	}
+
+	d.records[info.EffectiveFQDN] = append(d.records[info.EffectiveFQDN], record{
+		value:     info.Value,
+		visibleAt: call.At.Add(d.config.PropagationDelay),
+	})
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	time.Sleep(d.config.CleanUpLatency)
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	call := d.record(CallCleanUp, domain, token, keyAuth, info)
+
+	if slices.Contains(d.config.FailCleanUpOn, d.count(CallCleanUp)) {
+		call.Err = fmt.Errorf("fake: cleanup %s: %w", info.EffectiveFQDN, ErrInjected)
+		return call.Err
+	}
+
+	records := slices.DeleteFunc(d.records[info.EffectiveFQDN], func(r record) bool {
+		return r.value == info.Value
+	})
+
+	if len(records) == 0 {
+		delete(d.records, info.EffectiveFQDN)
+	} else {
+		d.records[info.EffectiveFQDN] = records
+	}
+
+	return nil
+}
+
+// SetCNAME creates the CNAME record from fqdn to target, replacing the existing one.
+func (d *DNSProvider) SetCNAME(fqdn, target string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fqdn, target = dns01.ToFqdn(fqdn), dns01.ToFqdn(target)
+
+	d.record(CallSetCNAME, "", "", "", dns01.ChallengeInfo{EffectiveFQDN: fqdn, Value: target})
+
+	d.cnames[fqdn] = target
+
+	return nil
+}
+
+// CNAME returns the target of the CNAME record at the FQDN, or an empty string.
+func (d *DNSProvider) CNAME(fqdn string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.cnames[dns01.ToFqdn(fqdn)]
+}
+
+// Calls returns a copy of the recorded calls in order.
+func (d *DNSProvider) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.calls)
+}
+
+// CallsOf returns the recorded calls of one kind (CallPresent, CallCleanUp or CallSetCNAME).
+func (d *DNSProvider) CallsOf(kind string) []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var calls []Call
+	for _, call := range d.calls {
+		if call.Kind == kind {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// TXT returns the TXT values currently visible at the FQDN, taking the propagation delay into account.
+func (d *DNSProvider) TXT(fqdn string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	var values []string
+	for _, r := range d.records[dns01.ToFqdn(fqdn)] {
+		if !now.Before(r.visibleAt) {
+			values = append(values, r.value)
+		}
+	}
+
+	return values
+}
+
+// Reset forgets all the recorded calls and records.
+func (d *DNSProvider) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.calls = nil
+	d.records = make(map[string][]record)
+	d.cnames = make(map[string]string)
+}
+
+// record must be called with the lock held.
+func (d *DNSProvider) record(kind, domain, token, keyAuth string, info dns01.ChallengeInfo) *Call {
+	d.calls = append(d.calls, Call{
+		Kind:    kind,
+		Domain:  domain,
+		Token:   token,
+		KeyAuth: keyAuth,
+		FQDN:    info.EffectiveFQDN,
+		Value:   info.Value,
+		At:      d.now(),
+	})
+
+	return &d.calls[len(d.calls)-1]
+}
+
+// count must be called with the lock held.
+func (d *DNSProvider) count(kind string) int {
+	var n int
+	for _, call := range d.calls {
+		if call.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,127 @@
+package fake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorderT struct {
+	errors []string
+}
+
+func (r *recorderT) Helper() {}
+
+func (r *recorderT) Errorf(format string, _ ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func TestParseConfig(t *testing.T) {
+	raw := `
+presentLatency: 10ms
+propagationDelay: 1m
+failPresentOn: [2, 4]
+failCleanUpOn: [1]
+`
+	config, err := ParseConfig([]byte(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Millisecond, config.PresentLatency)
+	assert.Equal(t, time.Minute, config.PropagationDelay)
+	assert.Equal(t, []int{2, 4}, config.FailPresentOn)
+	assert.Equal(t, []int{1}, config.FailCleanUpOn)
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.FailPresentOn = []int{0}
+
+	_, err := NewDNSProviderConfig(config)
+	require.EqualError(t, err, "fake: invalid call number 0, calls are numbered from 1")
+
+	_, err = NewDNSProviderConfig(nil)
+	require.EqualError(t, err, "fake: the configuration of the DNS provider is nil")
+}
+
+func TestDNSProvider_Present_failOnNthCall(t *testing.T) {
+	config := DefaultConfig()
+	config.FailPresentOn = []int{2}
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Present("a.example.com", "token", "keyAuth"))
+	require.ErrorIs(t, provider.Present("b.example.com", "token", "keyAuth"), ErrInjected)
+	require.NoError(t, provider.Present("c.example.com", "token", "keyAuth"))
+
+	calls := provider.CallsOf(CallPresent)
+	require.Len(t, calls, 3)
+	assert.NoError(t, calls[0].Err)
+	assert.ErrorIs(t, calls[1].Err, ErrInjected)
+
+	assert.Empty(t, provider.TXT("_acme-challenge.b.example.com."))
+	assert.Len(t, provider.TXT("_acme-challenge.c.example.com."), 1)
+}
+
+func TestDNSProvider_propagationDelay(t *testing.T) {
+	config := DefaultConfig()
+	config.PropagationDelay = time.Minute
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	assert.Empty(t, provider.TXT("_acme-challenge.example.com"))
+
+	now = now.Add(time.Minute)
+	assert.Len(t, provider.TXT("_acme-challenge.example.com"), 1)
+}
+
+func TestDNSProvider_assertions(t *testing.T) {
+	provider, err := NewDNSProviderConfig(DefaultConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Present("example.com", "token", "a"))
+	require.NoError(t, provider.Present("example.com", "token", "b"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "a"))
+
+	rt := &recorderT{}
+
+	assert.True(t, provider.AssertPresented(rt, "example.com"))
+	assert.True(t, provider.AssertCleanedUp(rt, "example.com"))
+	assert.True(t, provider.AssertCallCount(rt, CallPresent, 2))
+	assert.Empty(t, rt.errors)
+
+	assert.False(t, provider.AssertPresented(rt, "other.com"))
+	assert.False(t, provider.AssertNoRecords(rt))
+	assert.Len(t, rt.errors, 2)
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "b"))
+	assert.True(t, provider.AssertNoRecords(rt))
+
+	provider.Reset()
+	assert.Empty(t, provider.Calls())
+}
+
+func TestDNSProvider_SetCNAME(t *testing.T) {
+	provider, err := NewDNSProviderConfig(DefaultConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SetCNAME("_acme-challenge.example.com", "example.com.acme.example.net"))
+
+	assert.Equal(t, "example.com.acme.example.net.", provider.CNAME("_acme-challenge.example.com."))
+
+	calls := provider.CallsOf(CallSetCNAME)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "_acme-challenge.example.com.", calls[0].FQDN)
+	assert.Equal(t, "example.com.acme.example.net.", calls[0].Value)
+
+	provider.Reset()
+
+	assert.Empty(t, provider.CNAME("_acme-challenge.example.com."))
+}
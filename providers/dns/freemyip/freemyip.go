@@ -5,13 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nrdcg/freemyip"
+	"lego-toolbox/providers/dns/internal/txtqueue"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -76,6 +78,9 @@ ttl: 3600                  # Time to live`
 type DNSProvider struct {
 	config *Config
 	client *freemyip.Client
+
+	// queue sequences the challenges of the same domain: freemyip.com holds one TXT value per domain.
+	queue *txtqueue.Queue
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for freemyip.com.
@@ -95,7 +100,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +126,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config: config,
 		client: client,
+		queue:  txtqueue.New(),
 	}, nil
 }
 
@@ -138,14 +144,27 @@ func (d *DNSProvider) Sequential() time.Duration {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record using the specified parameters.
+// freemyip.com holds one TXT value per domain: the value waits for the cleanup of the previous value of the domain
+// (e.g. an apex and wildcard order), at most twice the propagation timeout, the wait is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, freemyip.RootDomain)
 	if err != nil {
 		return fmt.Errorf("freemyip: %w", err)
 	}
 
-	_, err = d.client.EditTXTRecord(context.Background(), subDomain, info.Value)
+	ctx, cancel := context.WithTimeout(ctx, 2*d.config.PropagationTimeout)
+	defer cancel()
+
+	err = d.queue.Present(ctx, subDomain, info.Value, func() error {
+		_, err := d.client.EditTXTRecord(ctx, subDomain, info.Value)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("freemyip: %w", err)
 	}
@@ -155,14 +174,23 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters,
+// unless it holds the value of another challenge.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, freemyip.RootDomain)
 	if err != nil {
 		return fmt.Errorf("freemyip: %w", err)
 	}
 
-	_, err = d.client.DeleteTXTRecord(context.Background(), subDomain)
+	err = d.queue.CleanUp(subDomain, info.Value, func() error {
+		_, err := d.client.DeleteTXTRecord(ctx, subDomain)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("freemyip: %w", err)
 	}
@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"sync"
 	"time"
@@ -13,6 +13,8 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/gandi/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Gandi API reference:       http://doc.rpc.gandi.net/index.html
@@ -77,9 +79,9 @@ ttl: 300                                   # TTL 值，单位为秒`
 
 // inProgressInfo contains information about an in-progress challenge.
 type inProgressInfo struct {
-	zoneID    int    // zoneID of gandi zone to restore in CleanUp
-	newZoneID int    // zoneID of temporary gandi zone containing TXT record
-	authZone  string // the domain name registered at gandi with trailing "."
+	ZoneID    int    `json:"zoneID"`    // zoneID of gandi zone to restore in CleanUp
+	NewZoneID int    `json:"newZoneID"` // zoneID of temporary gandi zone containing TXT record
+	AuthZone  string `json:"authZone"`  // the domain name registered at gandi with trailing "."
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -87,7 +89,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	inProgressFQDNs     map[string]inProgressInfo
+	inProgressFQDNs     *challengestore.Store[inProgressInfo]
 	inProgressAuthZones map[string]struct{}
 	inProgressMu        sync.Mutex
 
@@ -114,7 +116,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -144,17 +146,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:              config,
 		client:              client,
-		inProgressFQDNs:     make(map[string]inProgressInfo),
+		inProgressFQDNs:     challengestore.New[inProgressInfo](),
 		inProgressAuthZones: make(map[string]struct{}),
 		findZoneByFqdn:      dns01.FindZoneByFqdn,
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.inProgressFQDNs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters. It
 // does this by creating and activating a new temporary Gandi DNS
 // zone. This new zone contains the TXT record.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	if d.config.TTL < minTTL {
 		d.config.TTL = minTTL // 300 is gandi minimum value for ttl
@@ -218,11 +226,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	// save data necessary for CleanUp
-	d.inProgressFQDNs[info.EffectiveFQDN] = inProgressInfo{
-		zoneID:    zoneID,
-		newZoneID: newZoneID,
-		authZone:  authZone,
-	}
+	d.inProgressFQDNs.Set(token, info.EffectiveFQDN, inProgressInfo{
+		ZoneID:    zoneID,
+		NewZoneID: newZoneID,
+		AuthZone:  authZone,
+	})
 	d.inProgressAuthZones[authZone] = struct{}{}
 
 	return nil
@@ -232,21 +240,22 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 // parameters. It does this by restoring the old Gandi DNS zone and
 // removing the temporary one created by Present.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// acquire lock and retrieve zoneID, newZoneID and authZone
 	d.inProgressMu.Lock()
 	defer d.inProgressMu.Unlock()
 
-	if _, ok := d.inProgressFQDNs[info.EffectiveFQDN]; !ok {
+	inProgress, ok := d.inProgressFQDNs.Get(token, info.EffectiveFQDN)
+	if !ok {
 		// if there is no cleanup information then just return
 		return nil
 	}
 
-	zoneID := d.inProgressFQDNs[info.EffectiveFQDN].zoneID
-	newZoneID := d.inProgressFQDNs[info.EffectiveFQDN].newZoneID
-	authZone := d.inProgressFQDNs[info.EffectiveFQDN].authZone
-	delete(d.inProgressFQDNs, info.EffectiveFQDN)
+	zoneID := inProgress.ZoneID
+	newZoneID := inProgress.NewZoneID
+	authZone := inProgress.AuthZone
+	d.inProgressFQDNs.Delete(token, info.EffectiveFQDN)
 	delete(d.inProgressAuthZones, authZone)
 
 	ctx := context.Background()
@@ -5,16 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/gandiv5/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Gandi API reference:       http://doc.livedns.gandi.net/
@@ -36,8 +37,8 @@ const (
 
 // inProgressInfo contains information about an in-progress challenge.
 type inProgressInfo struct {
-	fieldName string
-	authZone  string
+	FieldName string `json:"fieldName"`
+	AuthZone  string `json:"authZone"`
 }
 
 // Config is used to configure the creation of the DNSProvider.
@@ -90,8 +91,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	inProgressFQDNs map[string]inProgressInfo
-	inProgressMu    sync.Mutex
+	inProgressFQDNs *challengestore.Store[inProgressInfo]
 
 	// findZoneByFqdn determines the DNS zone of a FQDN.
 	// It is overridden during tests.
@@ -113,7 +113,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -155,14 +155,20 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:          config,
 		client:          client,
-		inProgressFQDNs: make(map[string]inProgressInfo),
+		inProgressFQDNs: challengestore.New[inProgressInfo](),
 		findZoneByFqdn:  dns01.FindZoneByFqdn,
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.inProgressFQDNs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// find authZone
 	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
@@ -176,11 +182,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("gandiv5: %w", err)
 	}
 
-	// acquire lock and check there is not a challenge already in
-	// progress for this value of authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
-
 	// add TXT record into authZone
 	err = d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(authZone), subDomain, info.Value, d.config.TTL)
 	if err != nil {
@@ -188,28 +189,27 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	// save data necessary for CleanUp
-	d.inProgressFQDNs[info.EffectiveFQDN] = inProgressInfo{
-		authZone:  authZone,
-		fieldName: subDomain,
-	}
+	d.inProgressFQDNs.Set(token, info.EffectiveFQDN, inProgressInfo{
+		AuthZone:  authZone,
+		FieldName: subDomain,
+	})
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	// acquire lock and retrieve authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
-	if _, ok := d.inProgressFQDNs[info.EffectiveFQDN]; !ok {
+	// retrieve authZone
+	inProgress, ok := d.inProgressFQDNs.Get(token, info.EffectiveFQDN)
+	if !ok {
 		// if there is no cleanup information then just return
 		return nil
 	}
 
-	fieldName := d.inProgressFQDNs[info.EffectiveFQDN].fieldName
-	authZone := d.inProgressFQDNs[info.EffectiveFQDN].authZone
-	delete(d.inProgressFQDNs, info.EffectiveFQDN)
+	fieldName := inProgress.FieldName
+	authZone := inProgress.AuthZone
+	d.inProgressFQDNs.Delete(token, info.EffectiveFQDN)
 
 	// delete TXT record from authZone
 	err := d.client.DeleteTXTRecord(context.Background(), dns01.UnFqdn(authZone), fieldName)
@@ -8,24 +8,34 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
-	"github.com/go-acme/lego/v4/platform/wait"
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/dns/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 const (
 	changeStatusDone = "done"
 )
 
+// Zone visibilities.
+const (
+	ZoneVisibilityPublic  = "public"
+	ZoneVisibilityPrivate = "private"
+)
+
 // Environment variables names.
 const (
 	envNamespace = "GCE_"
@@ -34,6 +44,7 @@ const (
 	EnvProject          = envNamespace + "PROJECT"
 	EnvZoneID           = envNamespace + "ZONE_ID"
 	EnvAllowPrivateZone = envNamespace + "ALLOW_PRIVATE_ZONE"
+	EnvZoneVisibility   = envNamespace + "ZONE_VISIBILITY"
 	EnvDebug            = envNamespace + "DEBUG"
 
 	EnvTTL                = envNamespace + "TTL"
@@ -43,14 +54,24 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Debug              bool
-	Project            string
-	ZoneID             string
-	AllowPrivateZone   bool
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	Debug   bool   `yaml:"debug"`
+	Project string `yaml:"project"`
+	// ZoneID is the name of the managed zone to use, it skips the zone auto-discovery.
+	ZoneID string `yaml:"managedZone"`
+	// ZoneVisibility restricts the auto-discovery to the public or private managed zones.
+	// It allows to target one side of a split-horizon domain.
+	ZoneVisibility   string `yaml:"zoneVisibility"`
+	AllowPrivateZone bool   `yaml:"allowPrivateZone"`
+	// ServiceAccount is the content of a service account key (JSON).
+	ServiceAccount     string `yaml:"serviceAccount"`
+	ServiceAccountFile string `yaml:"serviceAccountFile"`
+	// ApplicationDefaultCredentials uses the Application Default Credentials
+	// when no HTTP client or service account is provided.
+	ApplicationDefaultCredentials bool          `yaml:"applicationDefaultCredentials"`
+	PropagationTimeout            time.Duration `yaml:"propagationTimeout"`
+	PollingInterval               time.Duration `yaml:"pollingInterval"`
+	TTL                           int           `yaml:"ttl"`
+	HTTPClient                    *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -58,6 +79,7 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		Debug:              env.GetOrDefaultBool(EnvDebug, false),
 		ZoneID:             env.GetOrDefaultString(EnvZoneID, ""),
+		ZoneVisibility:     env.GetOrDefaultString(EnvZoneVisibility, ""),
 		AllowPrivateZone:   env.GetOrDefaultBool(EnvAllowPrivateZone, false),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 180*time.Second),
@@ -65,6 +87,41 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		ApplicationDefaultCredentials: true,
+		TTL:                           dns01.DefaultTTL,
+		PropagationTimeout:            180 * time.Second,
+		PollingInterval:               5 * time.Second,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+project: "gc-project-id"              # 项目 ID，留空则从服务账号或默认凭据中获取
+serviceAccountFile: ""                # 服务账号密钥文件路径
+serviceAccount: ""                    # 服务账号密钥内容（JSON）
+applicationDefaultCredentials: true   # 未配置服务账号时使用 Application Default Credentials
+managedZone: ""                       # 指定托管区域名称，跳过自动发现
+zoneVisibility: ""                    # 自动发现时选择的区域类型：public 或 private（同名公有/私有区域时使用）
+allowPrivateZone: false               # 是否允许使用私有区域
+ttl: 120                              # TXT 记录的 TTL
+propagationTimeout: 180s              # 传播超时时间
+pollingInterval: 5s                   # 轮询间隔时间
+debug: false                          # 是否输出调试日志`
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -161,8 +218,26 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
 		return nil, errors.New("googlecloud: the configuration of the DNS provider is nil")
 	}
+
+	switch config.ZoneVisibility {
+	case "", ZoneVisibilityPublic, ZoneVisibilityPrivate:
+	default:
+		return nil, fmt.Errorf("googlecloud: invalid zone visibility %q, must be %q or %q", config.ZoneVisibility, ZoneVisibilityPublic, ZoneVisibilityPrivate)
+	}
+
 	if config.HTTPClient == nil {
-		return nil, errors.New("googlecloud: unable to create Google Cloud DNS service: client is nil")
+		if config.ServiceAccount == "" && config.ServiceAccountFile == "" && !config.ApplicationDefaultCredentials {
+			return nil, errors.New("googlecloud: unable to create Google Cloud DNS service: client is nil")
+		}
+
+		err := config.authenticate()
+		if err != nil {
+			return nil, fmt.Errorf("googlecloud: %w", err)
+		}
+	}
+
+	if config.Project == "" {
+		return nil, errors.New("googlecloud: project name missing")
 	}
 
 	svc, err := dns.NewService(context.Background(), option.WithHTTPClient(config.HTTPClient))
@@ -173,9 +248,53 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{config: config, client: svc}, nil
 }
 
+// authenticate creates the HTTP client from the service account or the Application Default Credentials.
+// The project is taken from the credentials when not set.
+func (c *Config) authenticate() error {
+	ctx := context.Background()
+
+	saKey := []byte(c.ServiceAccount)
+	if len(saKey) == 0 && c.ServiceAccountFile != "" {
+		var err error
+		saKey, err = os.ReadFile(c.ServiceAccountFile)
+		if err != nil {
+			return fmt.Errorf("unable to read Service Account file: %w", err)
+		}
+	}
+
+	var creds *google.Credentials
+	var err error
+
+	if len(saKey) > 0 {
+		creds, err = google.CredentialsFromJSON(ctx, saKey, dns.NdevClouddnsReadwriteScope)
+		if err != nil {
+			return fmt.Errorf("unable to acquire config: %w", err)
+		}
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, dns.NdevClouddnsReadwriteScope)
+		if err != nil {
+			return fmt.Errorf("unable to get Google Cloud client: %w", err)
+		}
+	}
+
+	if c.Project == "" {
+		c.Project = creds.ProjectID
+	}
+
+	c.HTTPClient = oauth2.NewClient(ctx, creds.TokenSource)
+
+	return nil
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+// The wait for the change to be applied is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -204,7 +323,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	// Attempt to delete the existing records before adding the new one.
 	if len(existingRrSet) > 0 {
-		if err = d.applyChanges(zone, &dns.Change{Deletions: existingRrSet}); err != nil {
+		if err = d.applyChanges(ctx, zone, &dns.Change{Deletions: existingRrSet}); err != nil {
 			return fmt.Errorf("googlecloud: %w", err)
 		}
 	}
@@ -229,20 +348,20 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Additions: []*dns.ResourceRecordSet{rec},
 	}
 
-	if err = d.applyChanges(zone, change); err != nil {
+	if err = d.applyChanges(ctx, zone, change); err != nil {
 		return fmt.Errorf("googlecloud: %w", err)
 	}
 
 	return nil
 }
 
-func (d *DNSProvider) applyChanges(zone string, change *dns.Change) error {
+func (d *DNSProvider) applyChanges(ctx context.Context, zone string, change *dns.Change) error {
 	if d.config.Debug {
 		data, _ := json.Marshal(change)
 		log.Printf("change (Create): %s", string(data))
 	}
 
-	chg, err := d.client.Changes.Create(d.config.Project, zone, change).Do()
+	chg, err := d.client.Changes.Create(d.config.Project, zone, change).Context(ctx).Do()
 	if err != nil {
 		var v *googleapi.Error
 		if errors.As(err, &v) && v.Code == http.StatusNotFound {
@@ -260,29 +379,29 @@ func (d *DNSProvider) applyChanges(zone string, change *dns.Change) error {
 	chgID := chg.Id
 
 	// wait for change to be acknowledged
-	return wait.For("apply change", 30*time.Second, 3*time.Second, func() (bool, error) {
+	opts := jobs.DefaultOptions("googlecloud: apply change "+chgID, 30*time.Second, 3*time.Second)
+
+	_, err = jobs.Poll(ctx, opts, func(ctx context.Context) (jobs.Status, error) {
 		if d.config.Debug {
 			data, _ := json.Marshal(change)
 			log.Printf("change (Get): %s", string(data))
 		}
 
-		chg, err = d.client.Changes.Get(d.config.Project, zone, chgID).Do()
+		chg, err := d.client.Changes.Get(d.config.Project, zone, chgID).Context(ctx).Do()
 		if err != nil {
 			data, _ := json.Marshal(change)
-			return false, fmt.Errorf("failed to get changes [zone %s, change %s]: %w", zone, string(data), err)
+			return jobs.Status{}, fmt.Errorf("failed to get changes [zone %s, change %s]: %w", zone, string(data), err)
 		}
 
-		if chg.Status == changeStatusDone {
-			return true, nil
-		}
-
-		return false, fmt.Errorf("status: %s", chg.Status)
+		return jobs.Status{Done: chg.Status == changeStatusDone, State: chg.Status}, nil
 	})
+
+	return err
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -318,21 +437,52 @@ func (d *DNSProvider) getHostedZone(domain string) (string, error) {
 		return "", err
 	}
 
+	return d.selectManagedZone(authZone, zones)
+}
+
+// selectManagedZone picks the managed zone according to the zone visibility settings.
+// When both a public and a private zone exist for the same DNS name (split-horizon),
+// the public zone is preferred unless the private one is requested with ZoneVisibility.
+func (d *DNSProvider) selectManagedZone(authZone string, zones []*dns.ManagedZone) (string, error) {
 	if len(zones) == 0 {
 		return "", fmt.Errorf("no matching domain found for domain %s", authZone)
 	}
 
-	for _, z := range zones {
-		if z.Visibility == "public" || z.Visibility == "" || (z.Visibility == "private" && d.config.AllowPrivateZone) {
-			return z.Name, nil
+	var visibilities []string
+
+	switch d.config.ZoneVisibility {
+	case ZoneVisibilityPublic:
+		visibilities = []string{ZoneVisibilityPublic}
+	case ZoneVisibilityPrivate:
+		visibilities = []string{ZoneVisibilityPrivate}
+	default:
+		visibilities = []string{ZoneVisibilityPublic}
+		if d.config.AllowPrivateZone {
+			visibilities = append(visibilities, ZoneVisibilityPrivate)
+		}
+	}
+
+	for _, visibility := range visibilities {
+		for _, z := range zones {
+			if zoneVisibility(z) == visibility {
+				return z.Name, nil
+			}
 		}
 	}
 
-	if d.config.AllowPrivateZone {
+	if len(visibilities) > 1 {
 		return "", fmt.Errorf("no public or private zone found for domain %s", authZone)
 	}
 
-	return "", fmt.Errorf("no public zone found for domain %s", authZone)
+	return "", fmt.Errorf("no %s zone found for domain %s", visibilities[0], authZone)
+}
+
+func zoneVisibility(zone *dns.ManagedZone) string {
+	if zone.Visibility == "" {
+		return ZoneVisibilityPublic
+	}
+
+	return strings.ToLower(zone.Visibility)
 }
 
 // lookupHostedZoneID finds the managed zone ID in Google.
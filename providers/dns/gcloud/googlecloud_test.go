@@ -9,11 +9,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/dns/v1"
+	"lego-toolbox/providertest"
 )
 
 const (
@@ -142,6 +144,115 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_yaml(t *testing.T) {
+	defer envTest.RestoreEnv()
+	envTest.ClearEnv()
+
+	config, err := ParseConfig([]byte("serviceAccountFile: fixtures/gce_account_service_file.json\nzoneVisibility: private\nmanagedZone: internal\n"))
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+	require.NotNil(t, p.client)
+	require.Equal(t, "A", p.config.Project)
+	require.Equal(t, "internal", p.config.ZoneID)
+	require.Equal(t, ZoneVisibilityPrivate, p.config.ZoneVisibility)
+}
+
+func TestNewDNSProviderConfig_invalidZoneVisibility(t *testing.T) {
+	config := NewDefaultConfig()
+	config.HTTPClient = &http.Client{}
+	config.Project = "manhattan"
+	config.ZoneVisibility = "internal"
+
+	_, err := NewDNSProviderConfig(config)
+	require.EqualError(t, err, `googlecloud: invalid zone visibility "internal", must be "public" or "private"`)
+}
+
+func TestDNSProvider_selectManagedZone(t *testing.T) {
+	splitHorizon := []*dns.ManagedZone{
+		{Name: "internal", Visibility: "private"},
+		{Name: "external", Visibility: "public"},
+	}
+
+	testCases := []struct {
+		desc             string
+		zoneVisibility   string
+		allowPrivateZone bool
+		zones            []*dns.ManagedZone
+		expected         string
+		err              string
+	}{
+		{
+			desc:     "default: public preferred",
+			zones:    splitHorizon,
+			expected: "external",
+		},
+		{
+			desc:             "allow private zone: public preferred",
+			allowPrivateZone: true,
+			zones:            splitHorizon,
+			expected:         "external",
+		},
+		{
+			desc:           "private",
+			zoneVisibility: ZoneVisibilityPrivate,
+			zones:          splitHorizon,
+			expected:       "internal",
+		},
+		{
+			desc:           "public",
+			zoneVisibility: ZoneVisibilityPublic,
+			zones:          splitHorizon,
+			expected:       "external",
+		},
+		{
+			desc:     "empty visibility is public",
+			zones:    []*dns.ManagedZone{{Name: "legacy"}},
+			expected: "legacy",
+		},
+		{
+			desc:  "private only",
+			zones: []*dns.ManagedZone{{Name: "internal", Visibility: "private"}},
+			err:   "no public zone found for domain lego.wtf.",
+		},
+		{
+			desc:             "private only: allowed",
+			allowPrivateZone: true,
+			zones:            []*dns.ManagedZone{{Name: "internal", Visibility: "private"}},
+			expected:         "internal",
+		},
+		{
+			desc:           "private requested: public only",
+			zoneVisibility: ZoneVisibilityPrivate,
+			zones:          []*dns.ManagedZone{{Name: "external", Visibility: "public"}},
+			err:            "no private zone found for domain lego.wtf.",
+		},
+		{
+			desc: "no zones",
+			err:  "no matching domain found for domain lego.wtf.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			d := &DNSProvider{config: &Config{
+				ZoneVisibility:   test.zoneVisibility,
+				AllowPrivateZone: test.allowPrivateZone,
+			}}
+
+			zone, err := d.selectManagedZone("lego.wtf.", test.zones)
+
+			if test.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, test.expected, zone)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
 func TestPresentNoExistingRR(t *testing.T) {
 	mux := http.NewServeMux()
 	server := httptest.NewServer(mux)
@@ -431,3 +542,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProviderCredentials(envTest.GetValue(EnvProject))
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
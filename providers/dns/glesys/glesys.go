@@ -6,12 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/glesys/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 60
@@ -56,8 +57,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	activeRecords map[string]int
-	inProgressMu  sync.Mutex
+	activeRecords *challengestore.Store[int]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for GleSYS.
@@ -99,13 +99,19 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:        config,
 		client:        client,
-		activeRecords: make(map[string]int),
+		activeRecords: challengestore.New[int](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.activeRecords.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// find authZone
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -118,10 +124,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("glesys: %w", err)
 	}
 
-	// acquire lock and check there is not a challenge already in progress for this value of authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
-
 	// add TXT record into authZone
 	recordID, err := d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(authZone), subDomain, info.Value, d.config.TTL)
 	if err != nil {
@@ -129,24 +131,21 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	// save data necessary for CleanUp
-	d.activeRecords[info.EffectiveFQDN] = recordID
+	d.activeRecords.Set(token, info.EffectiveFQDN, recordID)
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	// acquire lock and retrieve authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
-	if _, ok := d.activeRecords[info.EffectiveFQDN]; !ok {
+	recordID, ok := d.activeRecords.Get(token, info.EffectiveFQDN)
+	if !ok {
 		// if there is no cleanup information then just return
 		return nil
 	}
 
-	recordID := d.activeRecords[info.EffectiveFQDN]
-	delete(d.activeRecords, info.EffectiveFQDN)
+	d.activeRecords.Delete(token, info.EffectiveFQDN)
 
 	// delete TXT record from authZone
 	return d.client.DeleteTXTRecord(context.Background(), recordID)
@@ -5,13 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/godaddy/internal"
+	"lego-toolbox/providers/dns/internal/zonelock"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 600
@@ -27,6 +29,7 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvDisableZoneLock    = envNamespace + "DISABLE_ZONE_LOCK"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -36,6 +39,7 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	DisableZoneLock    bool          `yaml:"disableZoneLock"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -45,6 +49,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
+		DisableZoneLock:    env.GetOrDefaultBool(EnvDisableZoneLock, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -69,7 +74,8 @@ apiKey: "your_api_key"       # API 密钥，用于认证和授权访问 DNS 服
 apiSecret: "your_api_secret" # API 密钥的秘密部分，用于认证
 propagationTimeout: 120s     # 传播超时时间，表示 DNS 记录更新后等待传播的最大时间，单位为秒
 pollingInterval: 2s          # 轮询间隔，表示检查 DNS 记录状态的时间间隔，单位为秒
-ttl: 600                     # DNS 记录的生存时间（TTL），单位为秒，表示记录在缓存中存活的时间`
+ttl: 600                     # DNS 记录的生存时间（TTL），单位为秒，表示记录在缓存中存活的时间
+disableZoneLock: false       # 关闭进程内同一区域更新的串行化（默认 false）`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -97,7 +103,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +141,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -149,6 +155,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("godaddy: %w", err)
 	}
 
+	// the TXT records are replaced as a whole.
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(authZone)()
+	}
+
 	ctx := context.Background()
 
 	records, err := d.client.GetRecords(ctx, authZone, "TXT", subDomain)
@@ -181,7 +192,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -195,6 +206,11 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("godaddy: %w", err)
 	}
 
+	// the TXT records are replaced as a whole.
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(authZone)()
+	}
+
 	ctx := context.Background()
 
 	records, err := d.client.GetRecords(ctx, authZone, "TXT", subDomain)
@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"google.golang.org/api/acmedns/v1"
 	"google.golang.org/api/option"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -82,7 +83,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +161,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 }
 
 func getAcmeTxtRecord(domain, keyAuth string) *acmedns.AcmeTxtRecord {
-	challengeInfo := dns01.GetChallengeInfo(domain, keyAuth)
+	challengeInfo := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	return &acmedns.AcmeTxtRecord{
 		Fqdn:   challengeInfo.EffectiveFQDN,
@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"time"
 
-	"golang.org/x/oauth2"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -209,16 +208,3 @@ func parseError(req *http.Request, resp *http.Response) error {
 
 	return errAPI
 }
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
-}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 const testAPIKey = "secret"
@@ -222,7 +223,7 @@ func setupTest(t *testing.T, path string, handler http.Handler) *Client {
 
 	mux.Handle(path, handler)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), testAPIKey))
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), testAPIKey))
 	client.baseURL, _ = url.Parse(server.URL)
 
 	return client
@@ -5,15 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/providers/dns/internal/challengestore"
 	"lego-toolbox/providers/dns/internal/hostingde"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -79,8 +81,8 @@ type DNSProvider struct {
 	config *Config
 	client *hostingde.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
+	// recordIDs is informative: CleanUp deletes the records by name and value.
+	recordIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for http.net.
@@ -101,7 +103,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +126,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]string),
+		recordIDs: challengestore.New[string](),
 	}, nil
 }
 
@@ -136,7 +138,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, err := d.getZoneName(info.EffectiveFQDN)
 	if err != nil {
@@ -176,24 +178,37 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("httpnet: %w", err)
 	}
 
-	for _, record := range response.Records {
-		if record.Name == dns01.UnFqdn(info.EffectiveFQDN) && record.Content == fmt.Sprintf(`%q`, info.Value) {
-			d.recordIDsMu.Lock()
-			d.recordIDs[info.EffectiveFQDN] = record.ID
-			d.recordIDsMu.Unlock()
+	if response == nil {
+		return fmt.Errorf("httpnet: empty response to the update of the zone %s", zoneName)
+	}
+
+	// the update is processed asynchronously.
+	if response.ZoneConfig.Status == hostingde.StatusPending {
+		opts := jobs.DefaultOptions("httpnet: update of the zone "+zoneName, d.config.PropagationTimeout, d.config.PollingInterval)
+
+		err = d.client.WaitJobs(ctx, zoneConfig.ID, opts)
+		if err != nil {
+			return fmt.Errorf("httpnet: %w", err)
 		}
 	}
 
-	if d.recordIDs[info.EffectiveFQDN] == "" {
-		return fmt.Errorf("httpnet: error getting ID of just created record, for domain %s", domain)
+	recordID, ok := hostingde.FindRecordID(response.Records, info.EffectiveFQDN, info.Value)
+	if !ok {
+		// the records are not always part of the response of an asynchronous update.
+		recordID, err = d.findRecordID(ctx, info.EffectiveFQDN, info.Value)
+		if err != nil {
+			return fmt.Errorf("httpnet: error getting ID of just created record, for domain %s: %w", domain, err)
+		}
 	}
 
+	d.recordIDs.Set(token, info.EffectiveFQDN, recordID)
+
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, err := d.getZoneName(info.EffectiveFQDN)
 	if err != nil {
@@ -227,9 +242,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// Delete record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, info.EffectiveFQDN)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	_, err = d.client.UpdateZone(ctx, req)
 	if err != nil {
@@ -238,6 +251,25 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
+// findRecordID finds the ID of the TXT record in the records of the name.
+func (d *DNSProvider) findRecordID(ctx context.Context, fqdn, value string) (string, error) {
+	response, err := d.client.ListRecords(ctx, hostingde.RecordsFindRequest{
+		Filter: hostingde.Filter{Field: "recordName", Value: dns01.UnFqdn(fqdn)},
+		Limit:  100,
+		Page:   1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	recordID, ok := hostingde.FindRecordID(response.Data, fqdn, value)
+	if !ok {
+		return "", errors.New("record not found")
+	}
+
+	return recordID, nil
+}
+
 func (d *DNSProvider) getZoneName(fqdn string) (string, error) {
 	if d.config.ZoneName != "" {
 		return d.config.ZoneName, nil
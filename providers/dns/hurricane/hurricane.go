@@ -4,21 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/propagation"
 	"lego-toolbox/providers/dns/hurricane/internal"
+	"lego-toolbox/rawrecord"
 )
 
+// defaultProbeNameservers are the nameservers of Hurricane Electric.
+var defaultProbeNameservers = []string{"ns1.he.net", "ns2.he.net", "ns3.he.net", "ns4.he.net", "ns5.he.net"}
+
+const probeQueryTimeout = 10 * time.Second
+
 // Environment variables names.
 const (
 	envNamespace = "HURRICANE_"
 
-	EnvTokens = envNamespace + "TOKENS"
+	EnvTokens           = envNamespace + "TOKENS"
+	EnvProbe            = envNamespace + "PROBE"
+	EnvProbeNameservers = envNamespace + "PROBE_NAMESERVERS"
 
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -28,11 +38,16 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Credentials        map[string]string `yaml:"credentials"`
-	PropagationTimeout time.Duration     `yaml:"propagationTimeout"`
-	PollingInterval    time.Duration     `yaml:"pollingInterval"`
-	SequenceInterval   time.Duration     `yaml:"sequenceInterval"`
-	HTTPClient         *http.Client      `yaml:"-"`
+	// Credentials are the tokens keyed by TXT record name, without the `_acme-challenge.` component.
+	Credentials map[string]string `yaml:"credentials"`
+	// Probe makes Present wait until the nameservers of Hurricane Electric (ProbeNameservers) serve the record,
+	// they are known to lag behind the dynamic DNS updates.
+	Probe              bool          `yaml:"probe"`
+	ProbeNameservers   []string      `yaml:"probeNameservers"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -41,6 +56,7 @@ func NewDefaultConfig() *Config {
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 300*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
 		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		Probe:              env.GetOrDefaultBool(EnvProbe, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -61,9 +77,11 @@ func DefaultConfig() *Config {
 
 func GetYamlTemple() string {
 	return `# Config is used to configure the creation of the DNSProvider.
-credentials:
-  apiKey: "your_api_key"           # API 密钥，用于身份验证
-  apiSecret: "your_api_secret"     # API 密钥，用于身份验证
+credentials:                       # TXT 记录名称（不含 _acme-challenge. 前缀）到令牌的映射，每个名称一个令牌
+  example.org: "your_token"
+  my.example.org: "your_other_token"
+probe: false                       # 是否在 Present 时等待 HE 的权威名称服务器返回记录（HE 的名称服务器同步较慢）
+probeNameservers: []               # 探测使用的名称服务器，为空时使用 ns1.he.net 至 ns5.he.net
 propagationTimeout: 300s           # DNS 记录传播超时时间，指定更新记录后等待传播的最大时间，单位为秒（s）
 pollingInterval: 2s                # 轮询间隔时间，指定系统检查 DNS 记录状态的频率，单位为秒（s）
 sequenceInterval: 60s              # 序列间隔时间，指定执行序列操作之间的等待时间，单位为秒（s）`
@@ -73,6 +91,7 @@ sequenceInterval: 60s              # 序列间隔时间，指定执行序列操
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+	probe  *propagation.Checker
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Hurricane Electric.
@@ -90,13 +109,17 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 	config.Credentials = credentials
 
+	if nameservers := env.GetOrFile(EnvProbeNameservers); nameservers != "" {
+		config.ProbeNameservers = strings.Split(nameservers, ",")
+	}
+
 	return NewDNSProviderConfig(config)
 }
 
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -112,16 +135,55 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("hurricane: credentials missing")
 	}
 
-	client := internal.NewClient(config.Credentials)
+	credentials, err := validateCredentials(config.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("hurricane: %w", err)
+	}
+
+	client := internal.NewClient(credentials)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	provider := &DNSProvider{config: config, client: client}
+
+	if config.Probe {
+		nameservers := config.ProbeNameservers
+		if len(nameservers) == 0 {
+			nameservers = defaultProbeNameservers
+		}
 
-	return &DNSProvider{config: config, client: client}, nil
+		provider.probe, err = propagation.New(&propagation.Config{
+			Servers:    nameservers,
+			RequireAll: true,
+			Timeout:    probeQueryTimeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("hurricane: %w", err)
+		}
+	}
+
+	return provider, nil
 }
 
 // Present updates a TXT record to fulfill the dns-01 challenge.
-func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext updates a TXT record to fulfill the dns-01 challenge.
+// With the probe, it waits until the nameservers of Hurricane Electric serve the record,
+// for the propagation timeout at most; the wait is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.client.UpdateTxtRecord(ctx, dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	if err != nil {
+		return fmt.Errorf("hurricane: %w", err)
+	}
 
-	err := d.client.UpdateTxtRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	err = d.waitProbe(ctx, info.EffectiveFQDN, info.Value)
 	if err != nil {
 		return fmt.Errorf("hurricane: %w", err)
 	}
@@ -130,10 +192,15 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 }
 
 // CleanUp updates the TXT record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext updates the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	err := d.client.UpdateTxtRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), ".")
+	err := d.client.UpdateTxtRecord(ctx, dns01.UnFqdn(info.EffectiveFQDN), ".")
 	if err != nil {
 		return fmt.Errorf("hurricane: %w", err)
 	}
@@ -141,6 +208,37 @@ func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 	return nil
 }
 
+// waitProbe waits until the nameservers of the probe serve the record, for the propagation timeout at most.
+func (d *DNSProvider) waitProbe(ctx context.Context, fqdn, value string) error {
+	if d.probe == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.PropagationTimeout)
+	defer cancel()
+
+	return d.probe.Wait(ctx, fqdn, value, d.config.PollingInterval)
+}
+
+// ValidateRecords checks that every TXT record has a token, before the order is created.
+func (d *DNSProvider) ValidateRecords(fqdns []string) error {
+	var missing []string
+
+	for _, fqdn := range fqdns {
+		name := internal.CredentialName(dns01.UnFqdn(fqdn))
+
+		if _, ok := d.client.Token(name); !ok && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("hurricane: no token for %s, check your credentials map", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
 // Adjusting here to cope with spikes in propagation times.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -168,3 +266,30 @@ func parseCredentials(raw string) (map[string]string, error) {
 
 	return credentials, nil
 }
+
+// validateCredentials returns the credentials keyed by credential name (see internal.CredentialName).
+// The record names may have a trailing dot or the `_acme-challenge.` component.
+func validateCredentials(credentials map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(credentials))
+
+	for name, token := range credentials {
+		key := internal.CredentialName(dns01.UnFqdn(strings.TrimSpace(name)))
+
+		switch {
+		case key == "":
+			return nil, errors.New("empty record name in the credentials")
+		case strings.HasPrefix(key, "*."):
+			return nil, fmt.Errorf("wildcard record name %s in the credentials: use %s", name, strings.TrimPrefix(key, "*."))
+		case strings.TrimSpace(token) == "":
+			return nil, fmt.Errorf("missing token for %s", name)
+		}
+
+		if existing, ok := normalized[key]; ok && existing != token {
+			return nil, fmt.Errorf("conflicting tokens for %s", key)
+		}
+
+		normalized[key] = token
+	}
+
+	return normalized, nil
+}
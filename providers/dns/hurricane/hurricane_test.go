@@ -1,15 +1,19 @@
 package hurricane
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/propagation"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvTokens).WithDomain(envDomain)
+var envTest = tester.NewEnvTest(EnvTokens, EnvProbe, EnvProbeNameservers).WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
 	testCases := []struct {
@@ -90,10 +94,34 @@ func TestNewDNSProviderConfig(t *testing.T) {
 				"example.net": "789",
 			},
 		},
+		{
+			desc:  "success challenge record names",
+			creds: map[string]string{"_acme-challenge.Example.org.": "123", "example.org": "123"},
+		},
 		{
 			desc:     "missing credentials",
 			expected: "hurricane: credentials missing",
 		},
+		{
+			desc:     "missing token",
+			creds:    map[string]string{"example.org": "123", "example.com": " "},
+			expected: "hurricane: missing token for example.com",
+		},
+		{
+			desc:     "empty record name",
+			creds:    map[string]string{".": "123"},
+			expected: "hurricane: empty record name in the credentials",
+		},
+		{
+			desc:     "wildcard record name",
+			creds:    map[string]string{"*.example.org": "123"},
+			expected: "hurricane: wildcard record name *.example.org in the credentials: use example.org",
+		},
+		{
+			desc:     "conflicting tokens",
+			creds:    map[string]string{"_acme-challenge.example.org": "123", "example.org": "456"},
+			expected: "hurricane: conflicting tokens for example.org",
+		},
 	}
 
 	for _, test := range testCases {
@@ -114,6 +142,78 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+credentials:
+  example.org: "123"
+  my.example.org: "456"
+probe: true
+probeNameservers: [ns1.he.net]
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"example.org": "123", "my.example.org": "456"}, config.Credentials)
+	assert.True(t, config.Probe)
+	assert.Equal(t, []string{"ns1.he.net"}, config.ProbeNameservers)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.NotNil(t, p.probe)
+}
+
+func TestDNSProvider_ValidateRecords(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Credentials = map[string]string{"example.org": "123", "my.example.org": "456"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.ValidateRecords([]string{"_acme-challenge.example.org.", "_acme-challenge.My.example.org."})
+	require.NoError(t, err)
+
+	err = p.ValidateRecords([]string{
+		"_acme-challenge.example.org.",
+		"_acme-challenge.demo.example.org.",
+		"_acme-challenge.demo.example.org.",
+		"_acme-challenge.example.com.",
+	})
+	require.EqualError(t, err, "hurricane: no token for demo.example.org, example.com, check your credentials map")
+}
+
+type fakeResolver struct {
+	values []string
+}
+
+func (r *fakeResolver) LookupTXT(_ context.Context, _ string) ([]string, error) {
+	return r.values, nil
+}
+
+func TestDNSProvider_waitProbe(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Credentials = map[string]string{"example.org": "123"}
+	config.PropagationTimeout = 50 * time.Millisecond
+	config.PollingInterval = 10 * time.Millisecond
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	// without probe.
+	require.NoError(t, p.waitProbe(context.Background(), "_acme-challenge.example.org.", "value"))
+
+	// a nameserver lags.
+	p.probe = propagation.NewChecker(true, &fakeResolver{values: []string{"value"}}, &fakeResolver{})
+
+	err = p.waitProbe(context.Background(), "_acme-challenge.example.org.", "value")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	p.probe = propagation.NewChecker(true, &fakeResolver{values: []string{"value"}}, &fakeResolver{values: []string{"value"}})
+
+	require.NoError(t, p.waitProbe(context.Background(), "_acme-challenge.example.org.", "value"))
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
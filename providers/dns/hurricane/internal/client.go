@@ -51,14 +51,27 @@ func NewClient(credentials map[string]string) *Client {
 	}
 }
 
-// UpdateTxtRecord updates a TXT record.
-func (c *Client) UpdateTxtRecord(ctx context.Context, hostname string, txt string) error {
-	domain := strings.TrimPrefix(hostname, "_acme-challenge.")
+// CredentialName returns the name of the token of a TXT record (hostname without the trailing dot):
+// the record name without the `_acme-challenge.` component, in lower case.
+func CredentialName(hostname string) string {
+	return strings.TrimPrefix(strings.ToLower(hostname), "_acme-challenge.")
+}
 
+// Token returns the token of the credential name.
+func (c *Client) Token(name string) (string, bool) {
 	c.credMu.Lock()
-	token, ok := c.credentials[domain]
-	c.credMu.Unlock()
+	defer c.credMu.Unlock()
+
+	token, ok := c.credentials[name]
+
+	return token, ok
+}
+
+// UpdateTxtRecord updates a TXT record.
+func (c *Client) UpdateTxtRecord(ctx context.Context, hostname string, txt string) error {
+	domain := CredentialName(hostname)
 
+	token, ok := c.Token(domain)
 	if !ok {
 		return fmt.Errorf("domain %s not found in credentials, check your credentials map", domain)
 	}
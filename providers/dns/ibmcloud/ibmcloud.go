@@ -4,13 +4,15 @@ package ibmcloud
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/softlayer/softlayer-go/session"
 	"lego-toolbox/providers/dns/ibmcloud/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -27,6 +29,11 @@ const (
 	// https://github.com/softlayer/softlayer-go/blob/534185047ea683dd1e29fd23e445598295d94be4/session/session.go#L182
 	EnvHTTPTimeout = envNamespace + "TIMEOUT"
 	EnvDebug       = envNamespace + "DEBUG"
+	// EnvEndpointURL the name must be the same as here:
+	// https://github.com/softlayer/softlayer-go/blob/534185047ea683dd1e29fd23e445598295d94be4/session/session.go#L179
+	EnvEndpointURL = envNamespace + "ENDPOINT_URL"
+	EnvPrivate     = envNamespace + "PRIVATE"
+	EnvDomains     = envNamespace + "DOMAINS"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -41,7 +48,13 @@ type Config struct {
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
 	HTTPTimeout        time.Duration `yaml:"httpTimeout"`
-	Debug              bool          `yaml:"-"`
+	// Endpoint overrides the API endpoint, Private is ignored when set.
+	Endpoint string `yaml:"endpoint"`
+	// Private uses the endpoint of the private network (internal.PrivateEndpoint) instead of the public one.
+	Private bool `yaml:"private"`
+	// Domains restricts the domains managed with the API key to an allowlist, all the domains when empty.
+	Domains []string `yaml:"domains"`
+	Debug   bool     `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -51,6 +64,9 @@ func NewDefaultConfig() *Config {
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPTimeout:        env.GetOrDefaultSecond(EnvHTTPTimeout, session.DefaultTimeout),
+		Endpoint:           env.GetOrFile(EnvEndpointURL),
+		Private:            env.GetOrDefaultBool(EnvPrivate, false),
+		Domains:            splitList(env.GetOrFile(EnvDomains)),
 	}
 }
 
@@ -77,7 +93,13 @@ pollingInterval: "2s"
 # TTL (Time To Live)，设置一个整数值
 ttl: 120
 # HTTP请求超时，设置一个时间段，例如：30s, 1m
-httpTimeout: "120s"`
+httpTimeout: "120s"
+# API地址（可选），设置后忽略 private
+endpoint: ""
+# 是否使用私有网络的API地址（https://api.service.softlayer.com/rest/v3.1），仅在IBM Cloud经典基础设施内可访问
+private: false
+# 允许使用该API密钥管理的域名列表（为空时不限制）
+domains: []`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -106,7 +128,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -127,12 +149,15 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("ibmcloud: API key is missing")
 	}
 
-	sess := session.New(config.Username, config.APIKey)
+	sess := session.New(config.Username, config.APIKey, endpoint(config))
 
 	sess.Timeout = config.HTTPTimeout
 	sess.Debug = config.Debug
 
-	return &DNSProvider{wrapper: internal.NewWrapper(sess), config: config}, nil
+	wrapper := internal.NewWrapper(sess)
+	wrapper.Domains = config.Domains
+
+	return &DNSProvider{wrapper: wrapper, config: config}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -143,7 +168,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	err := d.wrapper.AddTXTRecord(info.EffectiveFQDN, domain, info.Value, d.config.TTL)
@@ -156,7 +181,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	err := d.wrapper.CleanupTXTRecord(info.EffectiveFQDN, domain)
@@ -166,3 +191,26 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+func endpoint(config *Config) string {
+	switch {
+	case config.Endpoint != "":
+		return config.Endpoint
+	case config.Private:
+		return internal.PrivateEndpoint
+	default:
+		return internal.PublicEndpoint
+	}
+}
+
+func splitList(raw string) []string {
+	var values []string
+
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -5,12 +5,14 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/ibmcloud/internal"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvUsername, EnvAPIKey).
+var envTest = tester.NewEnvTest(EnvUsername, EnvAPIKey, EnvEndpointURL, EnvPrivate, EnvDomains).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -121,6 +123,41 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func Test_endpoint(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		endpoint string
+		private  bool
+		expected string
+	}{
+		{
+			desc:     "public",
+			expected: internal.PublicEndpoint,
+		},
+		{
+			desc:     "private",
+			private:  true,
+			expected: internal.PrivateEndpoint,
+		},
+		{
+			desc:     "explicit endpoint",
+			endpoint: "https://api.example.com/rest/v3.1",
+			private:  true,
+			expected: "https://api.example.com/rest/v3.1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Endpoint = test.endpoint
+			config.Private = test.private
+
+			assert.Equal(t, test.expected, endpoint(config))
+		})
+	}
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
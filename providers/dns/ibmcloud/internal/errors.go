@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// APIError is a fault returned by the SoftLayer API.
+// https://sldn.softlayer.com/article/rest/#error-handling
+type APIError struct {
+	// StatusCode is the HTTP status code of the response, zero when unknown.
+	StatusCode int
+	// Code is the exception of the fault (e.g. SoftLayer_Exception_ObjectNotFound).
+	Code string
+	// Message is the message of the fault.
+	Message string
+
+	err sl.Error
+}
+
+func (a *APIError) Error() string {
+	msg := a.Message
+	if a.Code != "" {
+		msg = a.Code + ": " + msg
+	}
+
+	if a.StatusCode != 0 {
+		msg = fmt.Sprintf("%s (HTTP %d)", msg, a.StatusCode)
+	}
+
+	return msg
+}
+
+func (a *APIError) Unwrap() error {
+	if a.err.Wrapped != nil {
+		return a.err.Wrapped
+	}
+
+	return a.err
+}
+
+// IsNotFound reports whether the fault is about an object which does not exist.
+func (a *APIError) IsNotFound() bool {
+	return a.Code == "SoftLayer_Exception_ObjectNotFound" || a.StatusCode == http.StatusNotFound
+}
+
+// wrapError converts the faults of the SoftLayer API to APIError, the other errors are returned as is.
+func wrapError(err error) error {
+	var slErr sl.Error
+	if !errors.As(err, &slErr) {
+		return err
+	}
+
+	apiErr := &APIError{
+		StatusCode: slErr.StatusCode,
+		Code:       slErr.Exception,
+		Message:    slErr.Message,
+		err:        slErr,
+	}
+
+	if apiErr.Code == "" && apiErr.Message == "" {
+		apiErr.Message = "unknown error"
+	}
+
+	return apiErr
+}
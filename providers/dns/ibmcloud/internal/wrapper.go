@@ -2,16 +2,30 @@ package internal
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
+
 	"github.com/softlayer/softlayer-go/datatypes"
 	"github.com/softlayer/softlayer-go/services"
 	"github.com/softlayer/softlayer-go/session"
 	"github.com/softlayer/softlayer-go/sl"
 )
 
+// Endpoints of the SoftLayer API.
+const (
+	// PublicEndpoint is the endpoint reachable from the Internet.
+	PublicEndpoint = session.DefaultEndpoint
+	// PrivateEndpoint is the endpoint of the private network, reachable from the IBM Cloud classic infrastructure only.
+	PrivateEndpoint = "https://api.service.softlayer.com/rest/v3.1"
+)
+
 type Wrapper struct {
 	session *session.Session
+
+	// Domains restricts the domains to an allowlist (domain names), all the domains when empty.
+	Domains []string
 }
 
 func NewWrapper(sess *session.Session) *Wrapper {
@@ -21,7 +35,7 @@ func NewWrapper(sess *session.Session) *Wrapper {
 func (w Wrapper) AddTXTRecord(fqdn, domain, value string, ttl int) error {
 	service := services.GetDnsDomainService(w.session)
 
-	domainID, err := getDomainID(service, domain)
+	domainID, err := w.getDomainID(service, domain)
 	if err != nil {
 		return fmt.Errorf("failed to get domain ID: %w", err)
 	}
@@ -29,7 +43,7 @@ func (w Wrapper) AddTXTRecord(fqdn, domain, value string, ttl int) error {
 	service.Options.Id = domainID
 
 	if _, err := service.CreateTxtRecord(sl.String(fqdn), sl.String(value), sl.Int(ttl)); err != nil {
-		return fmt.Errorf("failed to create TXT record: %w", err)
+		return fmt.Errorf("failed to create TXT record: %w", wrapError(err))
 	}
 
 	return nil
@@ -38,7 +52,7 @@ func (w Wrapper) AddTXTRecord(fqdn, domain, value string, ttl int) error {
 func (w Wrapper) CleanupTXTRecord(fqdn, domain string) error {
 	service := services.GetDnsDomainService(w.session)
 
-	domainID, err := getDomainID(service, domain)
+	domainID, err := w.getDomainID(service, domain)
 	if err != nil {
 		return fmt.Errorf("failed to get domain ID: %w", err)
 	}
@@ -47,16 +61,22 @@ func (w Wrapper) CleanupTXTRecord(fqdn, domain string) error {
 
 	records, err := findTxtRecords(service, fqdn)
 	if err != nil {
-		return fmt.Errorf("failed to find TXT records: %w", err)
+		return fmt.Errorf("failed to find TXT records: %w", wrapError(err))
 	}
 
 	return deleteResourceRecords(service, records)
 }
 
-func getDomainID(service services.Dns_Domain, domain string) (*int, error) {
+// getDomainID gets the ID of the domain, or of its closest parent.
+// The domains out of the allowlist (Domains) are not searched.
+func (w Wrapper) getDomainID(service services.Dns_Domain, domain string) (*int, error) {
+	if !w.isAllowed(domain) {
+		return w.getParentDomainID(service, domain)
+	}
+
 	res, err := service.GetByDomainName(sl.String(domain))
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
 	for _, r := range res {
@@ -67,16 +87,35 @@ func getDomainID(service services.Dns_Domain, domain string) (*int, error) {
 		return r.Id, nil
 	}
 
+	return w.getParentDomainID(service, domain)
+}
+
+func (w Wrapper) getParentDomainID(service services.Dns_Domain, domain string) (*int, error) {
 	// The domain was not found by name.
 	// For subdomains this is not unusual in softlayer.
 	// So in case a subdomain like `sub.toplevel.tld` was used try again using the parent domain
 	// (strip the first part in the domain string -> `toplevel.tld`).
 	_, parent, found := strings.Cut(domain, ".")
 	if !found || !strings.Contains(parent, ".") {
+		if len(w.Domains) > 0 {
+			return nil, fmt.Errorf("no data found for domain: %s (allowed domains: %s)", domain, strings.Join(w.Domains, ", "))
+		}
+
 		return nil, fmt.Errorf("no data found for domain: %s", domain)
 	}
 
-	return getDomainID(service, parent)
+	return w.getDomainID(service, parent)
+}
+
+// isAllowed reports whether the domain is in the allowlist (Domains), if any.
+func (w Wrapper) isAllowed(domain string) bool {
+	if len(w.Domains) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(w.Domains, func(allowed string) bool {
+		return strings.EqualFold(dns01.UnFqdn(strings.TrimSpace(allowed)), dns01.UnFqdn(domain))
+	})
 }
 
 func findTxtRecords(service services.Dns_Domain, fqdn string) ([]datatypes.Dns_Domain_ResourceRecord, error) {
@@ -110,7 +149,7 @@ func deleteResourceRecords(service services.Dns_Domain, records []datatypes.Dns_
 
 	_, err := resourceRecord.DeleteObject()
 	if err != nil {
-		return fmt.Errorf("no data found of fqdn: %w", err)
+		return fmt.Errorf("no data found of fqdn: %w", wrapError(err))
 	}
 
 	return nil
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T, handler http.HandlerFunc) *Wrapper {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewWrapper(session.New("user", "secret", server.URL))
+}
+
+func TestWrapper_AddTXTRecord_fault(t *testing.T) {
+	wrapper := setupTest(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte(`{"error":"Unable to find object with id of '123'.","code":"SoftLayer_Exception_ObjectNotFound"}`))
+	})
+
+	err := wrapper.AddTXTRecord("_acme-challenge.example.com", "example.com", "value", 120)
+	require.EqualError(t, err, "failed to get domain ID: SoftLayer_Exception_ObjectNotFound: Unable to find object with id of '123'. (HTTP 404)")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "SoftLayer_Exception_ObjectNotFound", apiErr.Code)
+	assert.True(t, apiErr.IsNotFound())
+}
+
+func TestWrapper_AddTXTRecord_domains(t *testing.T) {
+	var paths []string
+
+	wrapper := setupTest(t, func(rw http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+
+		_, _ = rw.Write([]byte(`[]`))
+	})
+
+	wrapper.Domains = []string{"example.com"}
+
+	err := wrapper.AddTXTRecord("_acme-challenge.sub.example.org", "sub.example.org", "value", 120)
+	require.EqualError(t, err, "failed to get domain ID: no data found for domain: example.org (allowed domains: example.com)")
+
+	assert.Empty(t, paths)
+
+	err = wrapper.AddTXTRecord("_acme-challenge.sub.example.com", "sub.example.com", "value", 120)
+	require.EqualError(t, err, "failed to get domain ID: no data found for domain: example.com (allowed domains: example.com)")
+
+	assert.Equal(t, []string{"/SoftLayer_Dns_Domain/getByDomainName.json"}, paths)
+}
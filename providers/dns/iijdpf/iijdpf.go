@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
@@ -14,6 +14,8 @@ import (
 	"github.com/miekg/dns"
 	dpfapi "github.com/mimuret/golang-iij-dpf/pkg/api"
 	dpfapiutils "github.com/mimuret/golang-iij-dpf/pkg/apiutils"
+	"lego-toolbox/providers/dns/internal/credmap"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -22,6 +24,8 @@ const (
 
 	EnvAPIToken    = envNamespace + "API_TOKEN"
 	EnvServiceCode = envNamespace + "DPM_SERVICE_CODE"
+	// EnvServiceCodes is a comma-separated list of `zone:serviceCode` pairs.
+	EnvServiceCodes = envNamespace + "DPM_SERVICE_CODES"
 
 	EnvAPIEndpoint        = envNamespace + "API_ENDPOINT"
 	EnvTTL                = envNamespace + "TTL"
@@ -31,8 +35,11 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Token              string        `yaml:"token"`
-	ServiceCode        string        `yaml:"serviceCode"`
+	Token       string `yaml:"token"`
+	ServiceCode string `yaml:"serviceCode"`
+	// ServiceCodes are the DPF service codes keyed by zone, for the accounts with several DPF services:
+	// a record uses the service code of its closest zone, or ServiceCode.
+	ServiceCodes       credmap.Map   `yaml:"serviceCodes"`
 	Endpoint           string        `yaml:"endpoint"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
@@ -65,6 +72,9 @@ func GetYamlTemple() string {
 token: "your_token"
 # 服务代码，用于特定的服务操作
 serviceCode: "your_service_code"
+# 按区域区分的服务代码，记录使用最近区域的服务代码，否则使用 serviceCode
+serviceCodes:
+  example.org: "your_other_service_code"
 # 服务端点，API的访问URL
 endpoint: "your_endpoint_url"
 # 传播超时，设置一个时间段，例如：10s, 1m
@@ -81,16 +91,38 @@ var _ challenge.Provider = &DNSProvider{}
 type DNSProvider struct {
 	client dpfapi.ClientInterface
 	config *Config
+
+	// serviceCodes are the service codes keyed by zone.
+	serviceCodes credmap.Map
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for IIJ DNS.
 func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	if raw := env.GetOrFile(EnvServiceCodes); raw != "" {
+		serviceCodes, err := credmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("iijdpf: %w", err)
+		}
+
+		values, err := env.Get(EnvAPIToken)
+		if err != nil {
+			return nil, fmt.Errorf("iijdpf: %w", err)
+		}
+
+		config.Token = values[EnvAPIToken]
+		config.ServiceCode = env.GetOrFile(EnvServiceCode)
+		config.ServiceCodes = serviceCodes
+
+		return NewDNSProviderConfig(config)
+	}
+
 	values, err := env.Get(EnvAPIToken, EnvServiceCode)
 	if err != nil {
 		return nil, fmt.Errorf("iijdpf: %w", err)
 	}
 
-	config := NewDefaultConfig()
 	config.Token = values[EnvAPIToken]
 	config.ServiceCode = values[EnvServiceCode]
 
@@ -100,7 +132,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -114,13 +146,19 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("iijdpf: API token missing")
 	}
 
-	if config.ServiceCode == "" {
+	if config.ServiceCode == "" && len(config.ServiceCodes) == 0 {
 		return nil, errors.New("iijdpf: Servicecode missing")
 	}
 
+	serviceCodes, err := credmap.Validate(config.ServiceCodes)
+	if err != nil {
+		return nil, fmt.Errorf("iijdpf: service codes: %w", err)
+	}
+
 	return &DNSProvider{
-		client: dpfapi.NewClient(config.Token, config.Endpoint, nil),
-		config: config,
+		client:       dpfapi.NewClient(config.Token, config.Endpoint, nil),
+		config:       config,
+		serviceCodes: serviceCodes,
 	}, nil
 }
 
@@ -132,13 +170,16 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+// PresentContext creates a TXT record using the specified parameters, the API calls are canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	zoneID, err := dpfapiutils.GetZoneIdFromServiceCode(ctx, d.client, d.config.ServiceCode)
+	zoneID, err := d.zoneID(ctx, info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("iijdpf: failed to get zone id: %w", err)
+		return fmt.Errorf("iijdpf: %w", err)
 	}
 
 	err = d.addTxtRecord(ctx, zoneID, dns.CanonicalName(info.EffectiveFQDN), `"`+info.Value+`"`)
@@ -156,13 +197,16 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+// CleanUpContext removes the TXT record matching the specified parameters, the API calls are canceled with ctx.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	zoneID, err := dpfapiutils.GetZoneIdFromServiceCode(ctx, d.client, d.config.ServiceCode)
+	zoneID, err := d.zoneID(ctx, info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("iijdpf: failed to get zone id: %w", err)
+		return fmt.Errorf("iijdpf: %w", err)
 	}
 
 	err = d.deleteTxtRecord(ctx, zoneID, dns.CanonicalName(info.EffectiveFQDN), `"`+info.Value+`"`)
@@ -177,3 +221,31 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// zoneID returns the ID of the zone of the DPF service of fqdn.
+func (d *DNSProvider) zoneID(ctx context.Context, fqdn string) (string, error) {
+	serviceCode, err := d.serviceCodeFor(fqdn)
+	if err != nil {
+		return "", err
+	}
+
+	zoneID, err := dpfapiutils.GetZoneIdFromServiceCode(ctx, d.client, serviceCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to get zone id of the service %s: %w", serviceCode, err)
+	}
+
+	return zoneID, nil
+}
+
+// serviceCodeFor returns the service code of the closest zone of fqdn, or the default service code.
+func (d *DNSProvider) serviceCodeFor(fqdn string) (string, error) {
+	if serviceCode, _, ok := d.serviceCodes.Match(dns01.UnFqdn(fqdn)); ok {
+		return serviceCode, nil
+	}
+
+	if d.config.ServiceCode == "" {
+		return "", fmt.Errorf("no service code for %s, check your service codes", fqdn)
+	}
+
+	return d.config.ServiceCode, nil
+}
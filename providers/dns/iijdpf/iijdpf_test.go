@@ -4,12 +4,13 @@ import (
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 const envDomain = envNamespace + "TESTDOMAIN"
 
-var envTest = tester.NewEnvTest(EnvAPIToken, EnvServiceCode).WithDomain(envDomain)
+var envTest = tester.NewEnvTest(EnvAPIToken, EnvServiceCode, EnvServiceCodes).WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
 	testCases := []struct {
@@ -24,6 +25,21 @@ func TestNewDNSProvider(t *testing.T) {
 				EnvServiceCode: "dpmXXXXXX",
 			},
 		},
+		{
+			desc: "service codes by zone",
+			envVars: map[string]string{
+				EnvAPIToken:     "A",
+				EnvServiceCodes: "example.com:dpm1,example.org:dpm2",
+			},
+		},
+		{
+			desc: "invalid service codes",
+			envVars: map[string]string{
+				EnvAPIToken:     "A",
+				EnvServiceCodes: "example.com",
+			},
+			expected: `iijdpf: invalid credential pair: "example.com"`,
+		},
 		{
 			desc: "missing credentials",
 			envVars: map[string]string{
@@ -109,6 +125,37 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_serviceCodeFor(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+token: A
+serviceCodes:
+  example.com: dpm1
+  sub.example.com: dpm2
+`))
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	serviceCode, err := p.serviceCodeFor("_acme-challenge.www.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "dpm1", serviceCode)
+
+	serviceCode, err = p.serviceCodeFor("_acme-challenge.sub.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "dpm2", serviceCode)
+
+	_, err = p.serviceCodeFor("_acme-challenge.example.org.")
+	require.EqualError(t, err, "no service code for _acme-challenge.example.org., check your service codes")
+
+	// the default service code.
+	p.config.ServiceCode = "dpm0"
+
+	serviceCode, err = p.serviceCodeFor("_acme-challenge.example.org.")
+	require.NoError(t, err)
+	assert.Equal(t, "dpm0", serviceCode)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -4,14 +4,15 @@ package infoblox
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	infoblox "github.com/infobloxopen/infoblox-go-client"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -124,8 +125,7 @@ type DNSProvider struct {
 	transportConfig infoblox.TransportConfig
 	ibConfig        infoblox.HostConfig
 
-	recordRefs   map[string]string
-	recordRefsMu sync.Mutex
+	recordRefs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Infoblox.
@@ -151,7 +151,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +182,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 			Username: config.Username,
 			Password: config.Password,
 		},
-		recordRefs: make(map[string]string),
+		recordRefs: challengestore.New[string](),
 	}, nil
 }
 
@@ -191,9 +191,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordRefs.Persist(path)
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	connector, err := infoblox.NewConnector(d.ibConfig, d.transportConfig, &infoblox.WapiRequestBuilder{}, &infoblox.WapiHttpRequestor{})
 	if err != nil {
@@ -209,16 +215,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("infoblox: could not create TXT record for %s: %w", domain, err)
 	}
 
-	d.recordRefsMu.Lock()
-	d.recordRefs[token] = record.Ref
-	d.recordRefsMu.Unlock()
+	d.recordRefs.Set(token, info.EffectiveFQDN, record.Ref)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	connector, err := infoblox.NewConnector(d.ibConfig, d.transportConfig, &infoblox.WapiRequestBuilder{}, &infoblox.WapiHttpRequestor{})
 	if err != nil {
@@ -230,9 +234,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	objectManager := infoblox.NewObjectManager(connector, defaultUserAgent, "")
 
 	// gets the record's unique ref from when we created it
-	d.recordRefsMu.Lock()
-	recordRef, ok := d.recordRefs[token]
-	d.recordRefsMu.Unlock()
+	recordRef, ok := d.recordRefs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("infoblox: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -242,10 +244,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("infoblox: could not delete TXT record for %s: %w", domain, err)
 	}
 
-	// Delete record ref from map
-	d.recordRefsMu.Lock()
-	delete(d.recordRefs, token)
-	d.recordRefsMu.Unlock()
+	d.recordRefs.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
@@ -1,11 +1,20 @@
 package infoblox
 
 import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -143,6 +152,66 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_CleanUp_sameTokenSeveralDomains(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		created int
+		deleted []string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wapi/v2.11/userprofile", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("POST /wapi/v2.11/record:txt", func(rw http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		created++
+		_, _ = fmt.Fprintf(rw, `"record:txt/%d:default"`, created)
+	})
+	mux.HandleFunc("DELETE /wapi/v2.11/record:txt/{ref}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ref := strings.TrimPrefix(req.URL.Path, "/wapi/v2.11/")
+		deleted = append(deleted, ref)
+		_, _ = fmt.Fprintf(rw, "%q", ref)
+	})
+	mux.HandleFunc("POST /wapi/v2.11/logout", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`""`))
+	})
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	host, port, err := net.SplitHostPort(serverURL.Host)
+	require.NoError(t, err)
+
+	config := NewDefaultConfig()
+	config.Host = host
+	config.Port = port
+	config.Username = "user"
+	config.Password = "secret"
+	config.SSLVerify = false
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, p.Present("example.org", "token", "keyAuth"))
+
+	require.NoError(t, p.CleanUp("example.org", "token", "keyAuth"))
+	require.Equal(t, []string{"record:txt/2:default"}, deleted)
+
+	require.NoError(t, p.CleanUp("example.com", "token", "keyAuth"))
+	require.Equal(t, []string{"record:txt/2:default", "record:txt/1:default"}, deleted)
+	require.Equal(t, 0, p.recordRefs.Len())
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -170,3 +239,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
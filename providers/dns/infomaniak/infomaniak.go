@@ -5,14 +5,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/infomaniak/internal"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/providers/dns/internal/oauthutil"
+	"lego-toolbox/rawrecord"
 )
 
 // Infomaniak API reference: https://api.infomaniak.com/doc
@@ -24,20 +28,26 @@ const (
 
 	EnvEndpoint    = envNamespace + "ENDPOINT"
 	EnvAccessToken = envNamespace + "ACCESS_TOKEN"
+	EnvAccountID   = envNamespace + "ACCOUNT_ID"
+	EnvDomains     = envNamespace + "DOMAINS"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvAggressiveCleanup  = envNamespace + "AGGRESSIVE_CLEANUP"
 )
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	APIEndpoint        string        `yaml:"endpoint"`
 	AccessToken        string        `yaml:"accessToken"`
+	AccountID          uint64        `yaml:"accountID"`
+	Domains            []string      `yaml:"domains"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	AggressiveCleanup  bool          `yaml:"aggressiveCleanup"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -45,9 +55,12 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		APIEndpoint:        env.GetOrDefaultString(EnvEndpoint, internal.DefaultBaseURL),
+		AccountID:          uint64(env.GetOrDefaultInt(EnvAccountID, 0)),
+		Domains:            splitList(env.GetOrFile(EnvDomains)),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 7200),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		AggressiveCleanup:  env.GetOrDefaultBool(EnvAggressiveCleanup, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -73,6 +86,10 @@ func GetYamlTemple() string {
 endpoint: "https://api.infomaniak.com"
 # Access token for authentication
 accessToken: "your_access_token_here"
+# Account (organization) ID of the domains, required when the token has access to several accounts owning the domain
+accountID: 0
+# Allowlist of the domains managed with the token (empty: all the domains)
+domains: []
 # Timeout duration for propagation (in nanoseconds, microseconds, milliseconds, etc.)
 # Example: "60s" for 60 seconds
 propagationTimeout: "60s"
@@ -80,7 +97,9 @@ propagationTimeout: "60s"
 # Example: "2s" for 2 seconds
 pollingInterval: "2s"
 # Time-to-live for cached data (in seconds)
-ttl: 7200`
+ttl: 7200
+# Search the domain for the TXT record to delete when its ID is unknown (e.g. after a restart)
+aggressiveCleanup: false`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -88,11 +107,8 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]string
-	recordIDsMu sync.Mutex
-
-	domainIDs   map[string]uint64
-	domainIDsMu sync.Mutex
+	recordIDs *challengestore.Store[string]
+	domainIDs *challengestore.Store[uint64]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Infomaniak.
@@ -112,7 +128,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -133,22 +149,36 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("infomaniak: missing access token")
 	}
 
-	client, err := internal.New(internal.OAuthStaticAccessToken(config.HTTPClient, config.AccessToken), config.APIEndpoint)
+	client, err := internal.New(oauthutil.StaticAccessToken(config.HTTPClient, config.AccessToken), config.APIEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("infomaniak: %w", err)
 	}
 
+	client.AccountID = config.AccountID
+	client.Domains = config.Domains
+
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]string),
-		domainIDs: make(map[string]uint64),
+		recordIDs: challengestore.New[string](),
+		domainIDs: challengestore.New[uint64](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	err := d.recordIDs.Persist(path)
+	if err != nil {
+		return err
+	}
+
+	return d.domainIDs.Persist(challengestore.Path(path, "domains"))
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	ctx := context.Background()
 
@@ -157,9 +187,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("infomaniak: could not get domain %q: %w", info.EffectiveFQDN, err)
 	}
 
-	d.domainIDsMu.Lock()
-	d.domainIDs[token] = ikDomain.ID
-	d.domainIDsMu.Unlock()
+	d.domainIDs.Set(token, info.EffectiveFQDN, ikDomain.ID)
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, ikDomain.CustomerName)
 	if err != nil {
@@ -178,29 +206,25 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("infomaniak: error when calling api to create DNS record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = recordID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, recordID)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
+		if d.config.AggressiveCleanup {
+			return d.cleanUpByValue(context.Background(), info)
+		}
+
 		return fmt.Errorf("infomaniak: unknown record ID for '%s'", info.EffectiveFQDN)
 	}
 
-	d.domainIDsMu.Lock()
-	domainID, ok := d.domainIDs[token]
-	d.domainIDsMu.Unlock()
-
+	domainID, ok := d.domainIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("infomaniak: unknown domain ID for '%s'", info.EffectiveFQDN)
 	}
@@ -210,15 +234,47 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("infomaniak: could not delete record %q: %w", dns01.UnFqdn(info.EffectiveFQDN), err)
 	}
 
-	// Delete record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+	d.domainIDs.Delete(token, info.EffectiveFQDN)
 
-	// Delete domain ID from map
-	d.domainIDsMu.Lock()
-	delete(d.domainIDs, token)
-	d.domainIDsMu.Unlock()
+	return nil
+}
+
+// cleanUpByValue deletes the TXT records of the domain matching the challenge, used when the record ID is unknown.
+func (d *DNSProvider) cleanUpByValue(ctx context.Context, info dns01.ChallengeInfo) error {
+	ikDomain, err := d.client.GetDomainByName(ctx, dns01.UnFqdn(info.EffectiveFQDN))
+	if err != nil {
+		return fmt.Errorf("infomaniak: could not get domain %q: %w", info.EffectiveFQDN, err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, ikDomain.CustomerName)
+	if err != nil {
+		return fmt.Errorf("infomaniak: %w", err)
+	}
+
+	records, err := d.client.ListDNSRecords(ctx, ikDomain.ID)
+	if err != nil {
+		return fmt.Errorf("infomaniak: could not list records of %q: %w", ikDomain.CustomerName, err)
+	}
+
+	var found bool
+
+	for _, record := range records {
+		if record.Type != "TXT" || record.Source != subDomain || record.Target != info.Value {
+			continue
+		}
+
+		found = true
+
+		err = d.client.DeleteDNSRecord(ctx, ikDomain.ID, record.ID)
+		if err != nil {
+			return fmt.Errorf("infomaniak: could not delete record %q: %w", dns01.UnFqdn(info.EffectiveFQDN), err)
+		}
+	}
+
+	if !found {
+		log.Infof("infomaniak: no TXT record found for %s", info.EffectiveFQDN)
+	}
 
 	return nil
 }
@@ -228,3 +284,15 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+func splitList(raw string) []string {
+	var values []string
+
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -1,11 +1,16 @@
 package infomaniak
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/rawrecord"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -95,6 +100,100 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_CleanUp_aggressiveCleanup(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		aggressiveCleanup bool
+		expectedDeleted   []string
+		expectedError     string
+	}{
+		{
+			desc:              "enabled",
+			aggressiveCleanup: true,
+			expectedDeleted:   []string{"2"},
+		},
+		{
+			desc:          "disabled",
+			expectedError: "infomaniak: unknown record ID for '_acme-challenge.example.com.'",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("GET /1/product", func(rw http.ResponseWriter, req *http.Request) {
+				name := req.URL.Query().Get("customer_name")
+				if name != "example.com" {
+					_, _ = fmt.Fprint(rw, `{"result":"success","data":[]}`)
+					return
+				}
+
+				_, _ = fmt.Fprint(rw, `{"result":"success","data":[{"id":123,"customer_name":"example.com"}]}`)
+			})
+
+			mux.HandleFunc("GET /1/domain/123/dns/record", func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = fmt.Fprint(rw, `{"result":"success","data":[
+					{"id":"1","source":"_acme-challenge","type":"TXT","target":"other"},
+					{"id":"2","source":"_acme-challenge","type":"TXT","target":"value"},
+					{"id":"3","source":"www","type":"TXT","target":"value"}
+				]}`)
+			})
+
+			var deleted []string
+
+			mux.HandleFunc("DELETE /1/domain/123/dns/record/{id}", func(rw http.ResponseWriter, req *http.Request) {
+				deleted = append(deleted, req.PathValue("id"))
+
+				_, _ = fmt.Fprint(rw, `{"result":"success"}`)
+			})
+
+			config := NewDefaultConfig()
+			config.APIEndpoint = server.URL
+			config.AccessToken = "token"
+			config.HTTPClient = server.Client()
+			config.AggressiveCleanup = test.aggressiveCleanup
+
+			p, err := NewDNSProviderConfig(config)
+			require.NoError(t, err)
+
+			err = p.CleanUp("example.com", "token", rawrecord.KeyAuth("", "value"))
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedDeleted, deleted)
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+accessToken: A
+accountID: 42
+domains:
+  - example.com
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(42), p.client.AccountID)
+	assert.Equal(t, []string{"example.com"}, p.client.Domains)
+}
+
+func Test_splitList(t *testing.T) {
+	assert.Equal(t, []string{"example.com", "example.org"}, splitList(" example.com, ,example.org,"))
+	assert.Empty(t, splitList(""))
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
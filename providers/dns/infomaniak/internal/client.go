@@ -8,13 +8,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
-	"golang.org/x/oauth2"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -25,6 +25,11 @@ const DefaultBaseURL = "https://api.infomaniak.com"
 type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
+
+	// AccountID restricts the domains to an account (organization), all the accounts of the token when zero.
+	AccountID uint64
+	// Domains restricts the domains to an allowlist (domain names), all the domains when empty.
+	Domains []string
 }
 
 // New Creates a new Infomaniak client.
@@ -69,7 +74,26 @@ func (c *Client) DeleteDNSRecord(ctx context.Context, domainID uint64, recordID
 	return c.do(req, &APIResponse[json.RawMessage]{})
 }
 
+// ListDNSRecords lists the DNS records of a domain.
+func (c *Client) ListDNSRecords(ctx context.Context, domainID uint64) ([]Record, error) {
+	endpoint := c.baseURL.JoinPath("1", "domain", strconv.FormatUint(domainID, 10), "dns", "record")
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	result := APIResponse[[]Record]{}
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
 // GetDomainByName gets a Domain object from its name.
+// The domains out of the allowlist (Domains) or of another account (AccountID) are ignored.
 func (c *Client) GetDomainByName(ctx context.Context, name string) (*DNSDomain, error) {
 	name = dns01.UnFqdn(name)
 
@@ -81,6 +105,11 @@ func (c *Client) GetDomainByName(ctx context.Context, name string) (*DNSDomain,
 			break
 		}
 
+		if !c.isAllowed(name) {
+			name = name[i+1:]
+			continue
+		}
+
 		domain, err := c.getDomainByName(ctx, name)
 		if err != nil {
 			return nil, err
@@ -104,6 +133,11 @@ func (c *Client) getDomainByName(ctx context.Context, name string) (*DNSDomain,
 	query := endpoint.Query()
 	query.Add("service_name", "domain")
 	query.Add("customer_name", name)
+
+	if c.AccountID != 0 {
+		query.Add("account_id", strconv.FormatUint(c.AccountID, 10))
+	}
+
 	endpoint.RawQuery = query.Encode()
 
 	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
@@ -117,13 +151,45 @@ func (c *Client) getDomainByName(ctx context.Context, name string) (*DNSDomain,
 		return nil, err
 	}
 
+	var matches []DNSDomain
+
 	for _, domain := range result.Data {
-		if domain.CustomerName == name {
-			return &domain, nil
+		if domain.CustomerName != name {
+			continue
+		}
+
+		if c.AccountID != 0 && domain.AccountID != c.AccountID {
+			continue
 		}
+
+		matches = append(matches, domain)
 	}
 
-	return nil, nil
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		// a token with access to several accounts: the domain must be picked deterministically.
+		accounts := make([]string, 0, len(matches))
+		for _, domain := range matches {
+			accounts = append(accounts, strconv.FormatUint(domain.AccountID, 10))
+		}
+
+		return nil, fmt.Errorf("domain %s found in several accounts (%s), set the account ID", name, strings.Join(accounts, ", "))
+	}
+}
+
+// isAllowed reports whether the domain is in the allowlist (Domains), if any.
+func (c *Client) isAllowed(name string) bool {
+	if len(c.Domains) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(c.Domains, func(domain string) bool {
+		return strings.EqualFold(dns01.UnFqdn(strings.TrimSpace(domain)), name)
+	})
 }
 
 func (c *Client) do(req *http.Request, result Response) error {
@@ -173,16 +239,3 @@ func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, paylo
 
 	return req, nil
 }
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
-}
@@ -7,10 +7,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 func setupTest(t *testing.T) (*Client, *http.ServeMux) {
@@ -20,7 +22,7 @@ func setupTest(t *testing.T) (*Client, *http.ServeMux) {
 	server := httptest.NewServer(mux)
 	t.Cleanup(server.Close)
 
-	client, err := New(OAuthStaticAccessToken(server.Client(), "token"), server.URL)
+	client, err := New(oauthutil.StaticAccessToken(server.Client(), "token"), server.URL)
 	require.NoError(t, err)
 
 	return client, mux
@@ -135,6 +137,82 @@ func TestClient_GetDomainByName(t *testing.T) {
 	assert.Equal(t, expected, domain)
 }
 
+func TestClient_GetDomainByName_accounts(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		accountID uint64
+		domains   []string
+		expected  *DNSDomain
+		expectErr string
+	}{
+		{
+			desc:      "several accounts",
+			expectErr: "domain three.example.com found in several accounts (1, 2), set the account ID",
+		},
+		{
+			desc:      "account ID",
+			accountID: 2,
+			expected:  &DNSDomain{ID: 456, AccountID: 2, CustomerName: "three.example.com"},
+		},
+		{
+			desc:     "allowlist",
+			domains:  []string{"Example.com."},
+			expected: &DNSDomain{ID: 789, AccountID: 1, CustomerName: "example.com"},
+		},
+		{
+			desc:      "out of the allowlist",
+			domains:   []string{"example.org"},
+			expectErr: "domain not found com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			client, mux := setupTest(t)
+			client.AccountID = test.accountID
+			client.Domains = test.domains
+
+			var requested []string
+
+			mux.HandleFunc("GET /1/product", func(rw http.ResponseWriter, req *http.Request) {
+				customerName := req.URL.Query().Get("customer_name")
+				requested = append(requested, customerName)
+
+				if test.accountID != 0 {
+					assert.Equal(t, strconv.FormatUint(test.accountID, 10), req.URL.Query().Get("account_id"))
+				}
+
+				switch customerName {
+				case "three.example.com":
+					_, _ = fmt.Fprint(rw, `{"result":"success","data":[
+						{"id":123,"account_id":1,"customer_name":"three.example.com"},
+						{"id":456,"account_id":2,"customer_name":"three.example.com"}
+					]}`)
+				case "example.com":
+					_, _ = fmt.Fprint(rw, `{"result":"success","data":[{"id":789,"account_id":1,"customer_name":"example.com"}]}`)
+				default:
+					_, _ = fmt.Fprint(rw, `{"result":"success","data":[]}`)
+				}
+			})
+
+			domain, err := client.GetDomainByName(context.Background(), "three.example.com.")
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, domain)
+			}
+
+			if len(test.domains) > 0 {
+				// the domains out of the allowlist are not requested.
+				for _, name := range requested {
+					assert.True(t, client.isAllowed(name), name)
+				}
+			}
+		})
+	}
+}
+
 func TestClient_DeleteDNSRecord(t *testing.T) {
 	client, mux := setupTest(t)
 
@@ -159,3 +237,31 @@ func TestClient_DeleteDNSRecord(t *testing.T) {
 	err := client.DeleteDNSRecord(context.Background(), 123, "456")
 	require.NoError(t, err)
 }
+
+func TestClient_ListDNSRecords(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("/1/domain/123/dns/record", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if req.Header.Get("Authorization") != "Bearer token" {
+			http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		_, err := rw.Write([]byte(`{"result":"success","data":[{"id":"456","source":"_acme-challenge","type":"TXT","ttl":300,"target":"txtxtxttxt"}]}`))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	records, err := client.ListDNSRecords(context.Background(), 123)
+	require.NoError(t, err)
+
+	expected := []Record{{ID: "456", Source: "_acme-challenge", Type: "TXT", TTL: 300, Target: "txtxtxttxt"}}
+	assert.Equal(t, expected, records)
+}
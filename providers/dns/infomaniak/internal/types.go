@@ -15,6 +15,7 @@ type Record struct {
 
 type DNSDomain struct {
 	ID           uint64 `json:"id,omitempty"`
+	AccountID    uint64 `json:"account_id,omitempty"`
 	CustomerName string `json:"customer_name,omitempty"`
 }
 
@@ -0,0 +1,249 @@
+// Package challengestore keeps the state of the presented challenges (record IDs, references) until their cleanup.
+//
+// The entries are keyed by the token and the effective FQDN of the challenge:
+// one order may present the same token for several domains (or a domain and its wildcard),
+// each of them must clean up its own record.
+package challengestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	"lego-toolbox/internal/lockedfile"
+)
+
+// Key identifies a challenge.
+type Key struct {
+	Token string `json:"token"`
+	FQDN  string `json:"fqdn"`
+}
+
+// KeyOf returns the key of the challenge, the FQDN is normalized (lower case, trailing dot).
+func KeyOf(token, fqdn string) Key {
+	return Key{Token: token, FQDN: dns01.ToFqdn(strings.ToLower(fqdn))}
+}
+
+type entry[T any] struct {
+	Key
+	Value T `json:"value"`
+}
+
+// Store holds a value per challenge, safe for concurrent use.
+type Store[T any] struct {
+	mu      sync.Mutex
+	entries map[Key]T
+
+	// path is the file persisting the entries, if any.
+	path string
+}
+
+// New returns an in-memory store.
+func New[T any]() *Store[T] {
+	return &Store[T]{entries: make(map[Key]T)}
+}
+
+// NewPersistent returns a store persisted in a JSON file,
+// so the challenges presented before a restart can still be cleaned up.
+// The entries of the file are loaded, a missing file is an empty store.
+func NewPersistent[T any](path string) (*Store[T], error) {
+	s := New[T]()
+
+	err := s.Persist(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Persist persists the store in a JSON file, e.g. for the providers created before the state file is known.
+// The entries of the file are added to the entries of the store.
+// The file is read before each access, so the challenges presented by another process can be cleaned up,
+// and its updates are serialized by a lock file (path.lock) between the processes.
+func (s *Store[T]) Persist(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockedfile.Lock(path)
+	if err != nil {
+		return fmt.Errorf("challengestore: %w", err)
+	}
+
+	defer unlock()
+
+	entries := s.entries
+
+	s.path = path
+
+	err = s.load()
+	if err != nil {
+		s.path = ""
+		s.entries = entries
+
+		return err
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for key, value := range entries {
+		s.entries[key] = value
+	}
+
+	s.save()
+
+	return nil
+}
+
+// Path returns the path of the file of a named store of a provider keeping several stores,
+// the name is added before the extension of path (e.g. state.json, state.domains.json).
+func Path(path, name string) string {
+	ext := filepath.Ext(path)
+
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// Set stores the value of the challenge, replacing the previous one.
+func (s *Store[T]) Set(token, fqdn string, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.update(func() bool {
+		s.entries[KeyOf(token, fqdn)] = value
+		return true
+	})
+}
+
+// Get returns the value of the challenge.
+func (s *Store[T]) Get(token, fqdn string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reload()
+
+	value, ok := s.entries[KeyOf(token, fqdn)]
+
+	return value, ok
+}
+
+// Delete removes the value of the challenge.
+func (s *Store[T]) Delete(token, fqdn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.update(func() bool {
+		key := KeyOf(token, fqdn)
+		if _, ok := s.entries[key]; !ok {
+			return false
+		}
+
+		delete(s.entries, key)
+
+		return true
+	})
+}
+
+// Len returns the number of challenges in the store.
+func (s *Store[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reload()
+
+	return len(s.entries)
+}
+
+// load reads the entries of the file, it must be called with the lock held.
+func (s *Store[T]) load() error {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.entries = make(map[Key]T)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("challengestore: %w", err)
+	}
+
+	var entries []entry[T]
+
+	err = json.Unmarshal(raw, &entries)
+	if err != nil {
+		return fmt.Errorf("challengestore: %s: %w", s.path, err)
+	}
+
+	s.entries = make(map[Key]T, len(entries))
+	for _, e := range entries {
+		s.entries[e.Key] = e.Value
+	}
+
+	return nil
+}
+
+// reload reads the entries of the file before an access, so the entries of the other processes are seen.
+// It must be called with the lock held.
+// A failure is only logged: the entries in memory are used.
+func (s *Store[T]) reload() {
+	if s.path == "" {
+		return
+	}
+
+	entries := s.entries
+
+	err := s.load()
+	if err != nil {
+		s.entries = entries
+		log.Warnf("%v", err)
+	}
+}
+
+// update reads the entries of the file, changes them, and writes them back if changed,
+// under the lock of the file, so the concurrent updates of the other processes are not lost.
+// It must be called with the lock held.
+func (s *Store[T]) update(change func() bool) {
+	if s.path != "" {
+		unlock, err := lockedfile.Lock(s.path)
+		if err != nil {
+			log.Warnf("challengestore: %v", err)
+		} else {
+			defer unlock()
+		}
+	}
+
+	s.reload()
+
+	if change() {
+		s.save()
+	}
+}
+
+// save writes the entries to the file, it must be called with the lock held.
+// A failure is only logged: the entries stay usable in memory.
+func (s *Store[T]) save() {
+	if s.path == "" {
+		return
+	}
+
+	entries := make([]entry[T], 0, len(s.entries))
+	for key, value := range s.entries {
+		entries = append(entries, entry[T]{Key: key, Value: value})
+	}
+
+	raw, err := json.Marshal(entries)
+	if err == nil {
+		err = lockedfile.WriteFile(s.path, raw, 0o600)
+	}
+
+	if err != nil {
+		log.Warnf("challengestore: %v", err)
+	}
+}
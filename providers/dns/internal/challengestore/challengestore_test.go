@@ -0,0 +1,132 @@
+package challengestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := New[int]()
+
+	// the same token for two domains of one order.
+	store.Set("token", "_acme-challenge.example.com.", 1)
+	store.Set("token", "_acme-challenge.example.org", 2)
+
+	value, ok := store.Get("token", "_acme-challenge.EXAMPLE.com")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, ok = store.Get("token", "_acme-challenge.example.org.")
+	require.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	_, ok = store.Get("other", "_acme-challenge.example.com.")
+	assert.False(t, ok)
+
+	store.Delete("token", "_acme-challenge.example.com.")
+
+	_, ok = store.Get("token", "_acme-challenge.example.com.")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestNewPersistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+
+	store, err := NewPersistent[string](path)
+	require.NoError(t, err)
+
+	store.Set("token", "_acme-challenge.example.com.", "ref/1")
+	store.Set("token", "_acme-challenge.example.org.", "ref/2")
+	store.Delete("token", "_acme-challenge.example.org.")
+
+	reloaded, err := NewPersistent[string](path)
+	require.NoError(t, err)
+
+	value, ok := reloaded.Get("token", "_acme-challenge.example.com.")
+	require.True(t, ok)
+	assert.Equal(t, "ref/1", value)
+	assert.Equal(t, 1, reloaded.Len())
+}
+
+func TestNewPersistent_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := NewPersistent[string](path)
+	require.ErrorContains(t, err, "challengestore: "+path)
+}
+
+func TestStore_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "records.json")
+
+	// a challenge presented before the state file is known.
+	store := New[int]()
+	store.Set("token", "_acme-challenge.example.com.", 1)
+
+	require.NoError(t, store.Persist(path))
+
+	// another process.
+	other, err := NewPersistent[int](path)
+	require.NoError(t, err)
+
+	value, ok := other.Get("token", "_acme-challenge.example.com.")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	other.Set("token", "_acme-challenge.example.org.", 2)
+
+	value, ok = store.Get("token", "_acme-challenge.example.org.")
+	require.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	store.Delete("token", "_acme-challenge.example.com.")
+
+	assert.Equal(t, 1, other.Len())
+}
+
+func TestStore_Persist_concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+
+	// one store per process.
+	stores := make([]*Store[int], 4)
+	for i := range stores {
+		store, err := NewPersistent[int](path)
+		require.NoError(t, err)
+
+		stores[i] = store
+	}
+
+	var wg sync.WaitGroup
+
+	for i, store := range stores {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 10 {
+				store.Set("token", fmt.Sprintf("_acme-challenge.%d-%d.example.com.", i, j), j)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	store, err := NewPersistent[int](path)
+	require.NoError(t, err)
+	assert.Equal(t, 40, store.Len())
+
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/var/lib/state.domains.json", Path("/var/lib/state.json", "domains"))
+	assert.Equal(t, "state.domains", Path("state", "domains"))
+}
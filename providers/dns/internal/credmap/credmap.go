@@ -0,0 +1,131 @@
+// Package credmap holds the per-domain credentials of the dynamic DNS providers (domain → token).
+//
+// In the YAML configs, the credentials are a mapping:
+//
+//	credentials:
+//	  my.example.org: token1
+//	  demo.example.org: token2
+//
+// The legacy comma-separated list of `domain:token` pairs (the environment variables) is still accepted.
+package credmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"gopkg.in/yaml.v3"
+)
+
+// Map maps the domains to their token.
+type Map map[string]string
+
+// Parse parses a comma-separated list of `domain:token` pairs, and validates it.
+func Parse(raw string) (Map, error) {
+	credentials := make(map[string]string)
+
+	for _, pair := range strings.Split(strings.TrimSuffix(raw, ","), ",") {
+		domain, token, ok := strings.Cut(pair, ":")
+		if !ok || strings.Contains(token, ":") {
+			return nil, fmt.Errorf("invalid credential pair: %q", pair)
+		}
+
+		domain = strings.TrimSpace(domain)
+
+		if _, exists := credentials[domain]; exists {
+			return nil, fmt.Errorf("duplicate domain %q in the credentials", domain)
+		}
+
+		credentials[domain] = strings.TrimSpace(token)
+	}
+
+	return Validate(credentials)
+}
+
+// Validate returns the credentials keyed by normalized domain (lower case, without trailing dot).
+// The domains must not be empty nor wildcards, the tokens must not be empty.
+func Validate(credentials map[string]string) (Map, error) {
+	normalized := make(Map, len(credentials))
+
+	for domain, token := range credentials {
+		key := normalize(domain)
+
+		switch {
+		case key == "":
+			return nil, errors.New("empty domain in the credentials")
+		case strings.HasPrefix(key, "*."):
+			return nil, fmt.Errorf("wildcard domain %q in the credentials: use %q", domain, strings.TrimPrefix(key, "*."))
+		case strings.TrimSpace(token) == "":
+			return nil, fmt.Errorf("missing token for %q", domain)
+		}
+
+		if existing, ok := normalized[key]; ok && existing != token {
+			return nil, fmt.Errorf("conflicting tokens for %q", key)
+		}
+
+		normalized[key] = token
+	}
+
+	return normalized, nil
+}
+
+// Get returns the token of the domain.
+func (m Map) Get(domain string) (string, bool) {
+	token, ok := m[normalize(domain)]
+
+	return token, ok
+}
+
+// Match returns the token of the domain or, failing that, of its closest parent domain,
+// and the domain of the token.
+func (m Map) Match(domain string) (token, match string, ok bool) {
+	name := normalize(domain)
+
+	for {
+		if token, ok := m[name]; ok {
+			return token, name, true
+		}
+
+		_, parent, found := strings.Cut(name, ".")
+		if !found {
+			return "", "", false
+		}
+
+		name = parent
+	}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler: the credentials are a mapping or a comma-separated list of pairs.
+// The mapping is not validated, see Validate.
+func (m *Map) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value == "" {
+			return nil
+		}
+
+		credentials, err := Parse(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", value.Line, err)
+		}
+
+		*m = credentials
+
+		return nil
+	}
+
+	var credentials map[string]string
+
+	err := value.Decode(&credentials)
+	if err != nil {
+		return err
+	}
+
+	*m = credentials
+
+	return nil
+}
+
+func normalize(domain string) string {
+	return strings.ToLower(dns01.UnFqdn(strings.TrimSpace(domain)))
+}
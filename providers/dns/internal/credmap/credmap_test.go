@@ -0,0 +1,104 @@
+package credmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParse(t *testing.T) {
+	credentials, err := Parse("my.example.org:123, Demo.example.org.:456,")
+	require.NoError(t, err)
+
+	assert.Equal(t, Map{"my.example.org": "123", "demo.example.org": "456"}, credentials)
+}
+
+func TestParse_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{desc: "empty", raw: ",", expected: `invalid credential pair: ""`},
+		{desc: "partial", raw: "example.org:123,example.net", expected: `invalid credential pair: "example.net"`},
+		{desc: "too many colons", raw: "example.org:1:2", expected: `invalid credential pair: "example.org:1:2"`},
+		{desc: "duplicate", raw: "example.org:1,example.org:2", expected: `duplicate domain "example.org" in the credentials`},
+		{desc: "missing token", raw: "example.org:", expected: `missing token for "example.org"`},
+		{desc: "missing domain", raw: ":123", expected: "empty domain in the credentials"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Parse(test.raw)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestValidate_error(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		credentials map[string]string
+		expected    string
+	}{
+		{desc: "wildcard", credentials: map[string]string{"*.example.org": "1"}, expected: `wildcard domain "*.example.org" in the credentials: use "example.org"`},
+		{desc: "conflict", credentials: map[string]string{"example.org": "1", "Example.org.": "2"}, expected: `conflicting tokens for "example.org"`},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Validate(test.credentials)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestMap_Get(t *testing.T) {
+	credentials := Map{"example.org": "1"}
+
+	token, ok := credentials.Get("Example.org.")
+	require.True(t, ok)
+	assert.Equal(t, "1", token)
+
+	_, ok = credentials.Get("sub.example.org")
+	assert.False(t, ok)
+}
+
+func TestMap_Match(t *testing.T) {
+	credentials := Map{"example.org": "1", "sub.example.org": "2"}
+
+	token, match, ok := credentials.Match("_acme-challenge.sub.example.org.")
+	require.True(t, ok)
+	assert.Equal(t, "2", token)
+	assert.Equal(t, "sub.example.org", match)
+
+	token, match, ok = credentials.Match("_acme-challenge.example.org")
+	require.True(t, ok)
+	assert.Equal(t, "1", token)
+	assert.Equal(t, "example.org", match)
+
+	_, _, ok = credentials.Match("example.com")
+	assert.False(t, ok)
+}
+
+func TestMap_UnmarshalYAML(t *testing.T) {
+	var config struct {
+		Mapping Map `yaml:"mapping"`
+		List    Map `yaml:"list"`
+		Empty   Map `yaml:"empty"`
+	}
+
+	err := yaml.Unmarshal([]byte(`
+mapping:
+  my.example.org: "123"
+list: "my.example.org:123,demo.example.org:456"
+empty: ""
+`), &config)
+	require.NoError(t, err)
+
+	assert.Equal(t, Map{"my.example.org": "123"}, config.Mapping)
+	assert.Equal(t, Map{"my.example.org": "123", "demo.example.org": "456"}, config.List)
+	assert.Empty(t, config.Empty)
+}
@@ -7,10 +7,16 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const legoDebugClientVerboseError = "LEGO_DEBUG_CLIENT_VERBOSE_ERROR"
 
+// MaxBodySize is the maximum size of the response body kept in the errors.
+const MaxBodySize = 1024
+
 // HTTPDoError uses with `(http.Client).Do` error.
 type HTTPDoError struct {
 	req *http.Request
@@ -82,7 +88,7 @@ type UnmarshalError struct {
 
 // NewUnmarshalError creates a new UnmarshalError.
 func NewUnmarshalError(req *http.Request, statusCode int, body []byte, err error) *UnmarshalError {
-	return &UnmarshalError{req: req, StatusCode: statusCode, Body: bytes.TrimSpace(body), err: err}
+	return &UnmarshalError{req: req, StatusCode: statusCode, Body: truncate(body), err: err}
 }
 
 func (u UnmarshalError) Error() string {
@@ -114,12 +120,12 @@ type UnexpectedStatusCodeError struct {
 
 // NewUnexpectedStatusCodeError creates a new UnexpectedStatusCodeError.
 func NewUnexpectedStatusCodeError(req *http.Request, statusCode int, body []byte) *UnexpectedStatusCodeError {
-	return &UnexpectedStatusCodeError{req: req, StatusCode: statusCode, Body: bytes.TrimSpace(body)}
+	return &UnexpectedStatusCodeError{req: req, StatusCode: statusCode, Body: truncate(body)}
 }
 
 func NewUnexpectedResponseStatusCodeError(req *http.Request, resp *http.Response) *UnexpectedStatusCodeError {
 	raw, _ := io.ReadAll(resp.Body)
-	return &UnexpectedStatusCodeError{req: req, StatusCode: resp.StatusCode, Body: bytes.TrimSpace(raw)}
+	return &UnexpectedStatusCodeError{req: req, StatusCode: resp.StatusCode, Body: truncate(raw)}
 }
 
 func (u UnexpectedStatusCodeError) Error() string {
@@ -131,3 +137,69 @@ func (u UnexpectedStatusCodeError) Error() string {
 
 	return msg + fmt.Sprintf(" [status code: %d] body: %s", u.StatusCode, string(u.Body))
 }
+
+// APIError use when the status of the response is unexpected and the body is decoded as an API error.
+// The API error is kept as the wrapped error, the response body is kept (truncated) in case the API error is empty.
+type APIError struct {
+	req        *http.Request
+	StatusCode int
+	// Code is the error code of the API, if any.
+	Code string
+	Body []byte
+	err  error
+}
+
+// NewAPIError creates a new APIError.
+func NewAPIError(req *http.Request, statusCode int, body []byte, err error) *APIError {
+	return &APIError{req: req, StatusCode: statusCode, Body: truncate(body), err: err}
+}
+
+// WithCode sets the error code of the API.
+func (a *APIError) WithCode(code string) *APIError {
+	a.Code = code
+	return a
+}
+
+func (a APIError) Error() string {
+	var msg string
+
+	if ok, _ := strconv.ParseBool(os.Getenv(legoDebugClientVerboseError)); ok {
+		msg += fmt.Sprintf("[request: %s %s] ", a.req.Method, a.req.URL)
+	}
+
+	msg += fmt.Sprintf("[status code: %d]", a.StatusCode)
+
+	// the API error may be decoded from an unexpected body, leaving only separators (e.g. ": ").
+	if a.err != nil && strings.ContainsFunc(a.err.Error(), isAlphanumeric) {
+		return msg + " " + a.err.Error()
+	}
+
+	if a.Code != "" {
+		msg += fmt.Sprintf(" [code: %s]", a.Code)
+	}
+
+	return msg + fmt.Sprintf(" body: %s", string(a.Body))
+}
+
+func (a APIError) Unwrap() error {
+	return a.err
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// truncate trims the body and truncates it to MaxBodySize (on a rune boundary).
+func truncate(body []byte) []byte {
+	body = bytes.TrimSpace(body)
+	if len(body) <= MaxBodySize {
+		return body
+	}
+
+	end := MaxBodySize
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return append(body[:end:end], "..."...)
+}
@@ -0,0 +1,79 @@
+package errutils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type vendorError struct {
+	Code    int
+	Message string
+}
+
+func (v vendorError) Error() string {
+	return v.Message
+}
+
+func TestAPIError(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/records", http.NoBody)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		err      *APIError
+		expected string
+	}{
+		{
+			desc:     "API error",
+			err:      NewAPIError(req, http.StatusBadRequest, []byte(`{"code":12,"message":"invalid name"}`), vendorError{Code: 12, Message: "invalid name"}),
+			expected: "[status code: 400] invalid name",
+		},
+		{
+			desc:     "empty API error",
+			err:      NewAPIError(req, http.StatusBadRequest, []byte(` {"code":12,"msg":"invalid name"} `), vendorError{Code: 12}).WithCode("12"),
+			expected: `[status code: 400] [code: 12] body: {"code":12,"msg":"invalid name"}`,
+		},
+		{
+			desc:     "API error without message",
+			err:      NewAPIError(req, http.StatusBadRequest, []byte(`{"error":"bad name"}`), errors.New(": ")),
+			expected: `[status code: 400] body: {"error":"bad name"}`,
+		},
+		{
+			desc:     "no API error",
+			err:      NewAPIError(req, http.StatusBadRequest, []byte(`{}`), nil),
+			expected: "[status code: 400] body: {}",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.EqualError(t, test.err, test.expected)
+		})
+	}
+}
+
+func TestAPIError_unwrap(t *testing.T) {
+	err := error(NewAPIError(nil, http.StatusNotFound, nil, vendorError{Code: 404, Message: "not found"}))
+
+	var target vendorError
+	require.True(t, errors.As(err, &target))
+	assert.Equal(t, 404, target.Code)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestNewUnexpectedStatusCodeError_truncated(t *testing.T) {
+	body := strings.Repeat("é", MaxBodySize)
+
+	err := NewUnexpectedStatusCodeError(nil, http.StatusInternalServerError, []byte(body))
+
+	assert.LessOrEqual(t, len(err.Body), MaxBodySize+len("..."))
+	assert.True(t, strings.HasSuffix(string(err.Body), "é..."))
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"lego-toolbox/internal/jobs"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -118,6 +119,101 @@ func (c Client) UpdateZone(ctx context.Context, req ZoneUpdateRequest) (*Zone, e
 	return response.Response, nil
 }
 
+// ListRecords lists the records.
+// https://www.hosting.de/api/?json#list-records
+func (c Client) ListRecords(ctx context.Context, req RecordsFindRequest) (*RecordsResponse, error) {
+	endpoint := c.BaseURL.JoinPath("recordsFind")
+
+	req.AuthToken = c.apiKey
+
+	response := &BaseResponse[*RecordsResponse]{}
+
+	rawResp, err := c.post(ctx, endpoint, req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		return nil, fmt.Errorf("unexpected status: %q, %s", response.Status, string(rawResp))
+	}
+
+	if response.Response == nil {
+		return &RecordsResponse{}, nil
+	}
+
+	return response.Response, nil
+}
+
+// ListJobs lists the jobs.
+// https://www.hosting.de/api/?json#listing-jobs
+func (c Client) ListJobs(ctx context.Context, req JobsFindRequest) (*JobsResponse, error) {
+	endpoint := c.BaseURL.JoinPath("jobsFind")
+
+	req.AuthToken = c.apiKey
+
+	response := &BaseResponse[*JobsResponse]{}
+
+	rawResp, err := c.post(ctx, endpoint, req, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		return nil, fmt.Errorf("unexpected status: %q, %s", response.Status, string(rawResp))
+	}
+
+	if response.Response == nil {
+		return &JobsResponse{}, nil
+	}
+
+	return response.Response, nil
+}
+
+// WaitJobs waits for the jobs of the object (e.g. the ID of a zone config) to be done.
+// A failed job is an error.
+func (c Client) WaitJobs(ctx context.Context, objectID string, opts jobs.Options) error {
+	req := JobsFindRequest{
+		Filter: Filter{Field: "jobObjectId", Value: objectID},
+		Limit:  10,
+		Page:   1,
+	}
+
+	_, err := jobs.Poll(ctx, opts, func(ctx context.Context) (jobs.Status, error) {
+		response, err := c.ListJobs(ctx, req)
+		if err != nil {
+			return jobs.Status{}, err
+		}
+
+		return jobsStatus(response.Data), nil
+	})
+
+	return err
+}
+
+// jobsStatus merges the states of the jobs: done when all the jobs are done, failed when one of them failed.
+func jobsStatus(list []Job) jobs.Status {
+	status := jobs.Status{Done: true, State: "successful"}
+
+	var pending int
+
+	for _, job := range list {
+		switch job.State {
+		case "successful":
+		case "failed", "canceled":
+			return jobs.Status{Failed: true, State: job.State, Progress: job.DisplayName + " " + job.SubState}
+		default:
+			pending++
+			status = jobs.Status{State: job.State}
+		}
+	}
+
+	if pending > 0 {
+		status.Progress = fmt.Sprintf("%d/%d jobs pending", pending, len(list))
+	}
+
+	return status
+}
+
 func (c Client) post(ctx context.Context, endpoint *url.URL, request, result any) ([]byte, error) {
 	body, err := json.Marshal(request)
 	if err != nil {
@@ -12,9 +12,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/internal/jobs"
 )
 
 func setupTest(t *testing.T, pattern string, handler http.HandlerFunc) *Client {
@@ -262,3 +264,87 @@ func TestClient_UpdateZone_error(t *testing.T) {
 	_, err := client.UpdateZone(context.Background(), request)
 	require.Error(t, err)
 }
+
+func TestClient_ListRecords(t *testing.T) {
+	client := setupTest(t, "/recordsFind", func(rw http.ResponseWriter, req *http.Request) {
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body := string(bytes.TrimSpace(raw))
+		if body != `{"authToken":"secret","filter":{"field":"recordName","value":"_acme-challenge.example.com"},"limit":100,"page":1}` {
+			http.Error(rw, fmt.Sprintf("unexpected body: got %s", body), http.StatusBadRequest)
+			return
+		}
+
+		writeFixture(rw, "recordsFind.json")
+	})
+
+	response, err := client.ListRecords(context.Background(), RecordsFindRequest{
+		Filter: Filter{Field: "recordName", Value: "_acme-challenge.example.com"},
+		Limit:  100,
+		Page:   1,
+	})
+	require.NoError(t, err)
+
+	expected := []DNSRecord{{
+		ID:             "123",
+		ZoneID:         "456",
+		Name:           "_acme-challenge.example.com",
+		Type:           "TXT",
+		Content:        `"txt"`,
+		TTL:            120,
+		LastChangeDate: "d",
+	}}
+
+	assert.Equal(t, expected, response.Data)
+}
+
+func TestClient_WaitJobs(t *testing.T) {
+	calls := 0
+
+	client := setupTest(t, "/jobsFind", func(rw http.ResponseWriter, req *http.Request) {
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body := string(bytes.TrimSpace(raw))
+		if body != `{"authToken":"secret","filter":{"field":"jobObjectId","value":"123"},"limit":10,"page":1}` {
+			http.Error(rw, fmt.Sprintf("unexpected body: got %s", body), http.StatusBadRequest)
+			return
+		}
+
+		calls++
+		if calls == 1 {
+			_, _ = fmt.Fprint(rw, `{"status":"success","response":{"data":[{"id":"j1","objectId":"123","state":"inProgress"}]}}`)
+			return
+		}
+
+		writeFixture(rw, "jobsFind.json")
+	})
+
+	opts := jobs.Options{Name: "zone update", Timeout: time.Second, Interval: time.Millisecond, Report: func(jobs.Attempt) {}}
+
+	err := client.WaitJobs(context.Background(), "123", opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+}
+
+func Test_jobsStatus(t *testing.T) {
+	assert.Equal(t, jobs.Status{Done: true, State: "successful"}, jobsStatus(nil))
+
+	assert.Equal(t, jobs.Status{State: "inProgress", Progress: "1/2 jobs pending"}, jobsStatus([]Job{
+		{State: "successful"},
+		{State: "inProgress"},
+	}))
+
+	assert.Equal(t, jobs.Status{Failed: true, State: "failed", Progress: "example.com invalidRecord"}, jobsStatus([]Job{
+		{State: "inProgress"},
+		{State: "failed", DisplayName: "example.com", SubState: "invalidRecord"},
+	}))
+}
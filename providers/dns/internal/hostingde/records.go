@@ -0,0 +1,66 @@
+package hostingde
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// StatusPending is the status of a zone config whose update is processed asynchronously, see Client.WaitJobs.
+const StatusPending = "pending"
+
+// FindRecordID returns the ID of the TXT record of the name in the records returned by the API (e.g. by UpdateZone).
+// The content of the records is compared unquoted: the API normalizes the TXT values (`"value"`).
+// When no content matches, the ID of the only TXT record of the name is returned.
+func FindRecordID(records []DNSRecord, name, value string) (string, bool) {
+	var candidates []DNSRecord
+
+	for _, record := range records {
+		if !strings.EqualFold(dns01.UnFqdn(record.Name), dns01.UnFqdn(name)) || !isTXT(record) || record.ID == "" {
+			continue
+		}
+
+		if unquoteTXT(record.Content) == value {
+			return record.ID, true
+		}
+
+		candidates = append(candidates, record)
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0].ID, true
+	}
+
+	return "", false
+}
+
+func isTXT(record DNSRecord) bool {
+	return record.Type == "" || strings.EqualFold(record.Type, "TXT")
+}
+
+// unquoteTXT returns the content of a TXT record without the quotes,
+// the character strings of the long values (`"abc" "def"`) are concatenated.
+func unquoteTXT(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+
+	var sb strings.Builder
+
+	for content != "" {
+		prefix, err := strconv.QuotedPrefix(content)
+		if err != nil {
+			sb.WriteString(strings.Trim(content, `"`))
+			break
+		}
+
+		value, _ := strconv.Unquote(prefix)
+		sb.WriteString(value)
+
+		content = strings.TrimSpace(content[len(prefix):])
+	}
+
+	return sb.String()
+}
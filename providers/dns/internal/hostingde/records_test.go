@@ -0,0 +1,74 @@
+package hostingde
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRecordID(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		records    []DNSRecord
+		expectedID string
+	}{
+		{
+			desc: "quoted content",
+			records: []DNSRecord{
+				{ID: "1", Name: "_acme-challenge.example.com", Type: "TXT", Content: `"other"`},
+				{ID: "2", Name: "_acme-challenge.example.com", Type: "TXT", Content: `"value"`},
+			},
+			expectedID: "2",
+		},
+		{
+			desc: "unquoted content",
+			records: []DNSRecord{
+				{ID: "1", Name: "_acme-challenge.example.com", Type: "TXT", Content: `"other"`},
+				{ID: "2", Name: "_acme-challenge.example.com", Type: "TXT", Content: "value"},
+			},
+			expectedID: "2",
+		},
+		{
+			desc: "split content, FQDN and case",
+			records: []DNSRecord{
+				{ID: "1", Name: "_ACME-challenge.example.com.", Type: "txt", Content: `"val" "ue"`},
+			},
+			expectedID: "1",
+		},
+		{
+			desc: "fallback on name and type",
+			records: []DNSRecord{
+				{ID: "1", Name: "_acme-challenge.example.com", Type: "CNAME", Content: "value.example.net"},
+				{ID: "2", Name: "_acme-challenge.example.com", Type: "TXT", Content: "normalized"},
+				{ID: "3", Name: "www.example.com", Type: "TXT", Content: "value"},
+			},
+			expectedID: "2",
+		},
+		{
+			desc: "ambiguous fallback",
+			records: []DNSRecord{
+				{ID: "1", Name: "_acme-challenge.example.com", Type: "TXT", Content: "a"},
+				{ID: "2", Name: "_acme-challenge.example.com", Type: "TXT", Content: "b"},
+			},
+		},
+		{
+			desc: "no record",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			id, ok := FindRecordID(test.records, "_acme-challenge.example.com.", "value")
+
+			assert.Equal(t, test.expectedID != "", ok)
+			assert.Equal(t, test.expectedID, id)
+		})
+	}
+}
+
+func Test_unquoteTXT(t *testing.T) {
+	assert.Equal(t, "value", unquoteTXT("value"))
+	assert.Equal(t, "value", unquoteTXT(` "value" `))
+	assert.Equal(t, "value", unquoteTXT(`"val" "ue"`))
+	assert.Equal(t, "value", unquoteTXT(`"value`))
+}
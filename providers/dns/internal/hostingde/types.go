@@ -112,6 +112,59 @@ type ZoneResponse struct {
 	Data         []ZoneConfig `json:"data"`
 }
 
+// RecordsFindRequest represents a API RecordsFind request.
+// https://www.hosting.de/api/?json#list-records
+type RecordsFindRequest struct {
+	BaseRequest
+	Filter Filter `json:"filter"`
+	Limit  int    `json:"limit"`
+	Page   int    `json:"page"`
+}
+
+type RecordsResponse struct {
+	Limit        int         `json:"limit"`
+	Page         int         `json:"page"`
+	TotalEntries int         `json:"totalEntries"`
+	TotalPages   int         `json:"totalPages"`
+	Type         string      `json:"type"`
+	Data         []DNSRecord `json:"data"`
+}
+
+// JobsFindRequest represents a API JobsFind request.
+// https://www.hosting.de/api/?json#listing-jobs
+type JobsFindRequest struct {
+	BaseRequest
+	Filter Filter `json:"filter"`
+	Limit  int    `json:"limit"`
+	Page   int    `json:"page"`
+	Sort   *Sort  `json:"sort,omitempty"`
+}
+
+// Job The Job object describes an asynchronous operation (e.g. the update of a zone).
+// https://www.hosting.de/api/?json#the-job-object
+type Job struct {
+	ID             string `json:"id"`
+	AccountID      string `json:"accountId"`
+	DisplayName    string `json:"displayName"`
+	Handle         string `json:"handle"`
+	ObjectID       string `json:"objectId"`
+	ObjectType     string `json:"objectType"`
+	Type           string `json:"type"`
+	State          string `json:"state"`
+	SubState       string `json:"subState"`
+	AddDate        string `json:"addDate"`
+	LastChangeDate string `json:"lastChangeDate"`
+}
+
+type JobsResponse struct {
+	Limit        int    `json:"limit"`
+	Page         int    `json:"page"`
+	TotalEntries int    `json:"totalEntries"`
+	TotalPages   int    `json:"totalPages"`
+	Type         string `json:"type"`
+	Data         []Job  `json:"data"`
+}
+
 // BaseResponse Common response struct.
 // base: https://www.hosting.de/api/?json#responses
 // ZoneConfigsFind: https://www.hosting.de/api/?json#list-zoneconfigs
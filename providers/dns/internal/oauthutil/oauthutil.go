@@ -0,0 +1,106 @@
+// Package oauthutil builds the HTTP clients of the providers authenticated with an OAuth2 bearer token.
+package oauthutil
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultTimeout is the timeout of the client created when no client is given.
+const DefaultTimeout = 5 * time.Second
+
+type options struct {
+	timeout   time.Duration
+	source    oauth2.TokenSource
+	onRefresh func(*oauth2.Token)
+}
+
+// Option configures the client.
+type Option func(*options)
+
+// WithTimeout sets the timeout of the client created when no client is given.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithTokenSource uses a token source instead of the static token, e.g. to refresh an expiring token.
+// The tokens are reused until they expire.
+func WithTokenSource(source oauth2.TokenSource) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// WithRefreshCallback calls fn each time the token source returns a new token (including the first one),
+// e.g. to persist it.
+func WithRefreshCallback(fn func(*oauth2.Token)) Option {
+	return func(o *options) {
+		o.onRefresh = fn
+	}
+}
+
+// StaticAccessToken returns a copy of client sending the access token as a bearer token.
+// The transport of client is kept as the base transport, client itself is not modified.
+// A nil client is replaced by a client with the DefaultTimeout (see WithTimeout).
+func StaticAccessToken(client *http.Client, accessToken string, opts ...Option) *http.Client {
+	o := &options{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	source := o.source
+	if source == nil {
+		source = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+	} else {
+		source = oauth2.ReuseTokenSource(nil, source)
+	}
+
+	if o.onRefresh != nil {
+		source = &notifyingSource{source: source, onRefresh: o.onRefresh}
+	}
+
+	var authClient http.Client
+	if client == nil {
+		authClient.Timeout = o.timeout
+	} else {
+		authClient = *client
+	}
+
+	authClient.Transport = &oauth2.Transport{
+		Source: source,
+		Base:   authClient.Transport,
+	}
+
+	return &authClient
+}
+
+// notifyingSource calls onRefresh when the access token changes.
+type notifyingSource struct {
+	source    oauth2.TokenSource
+	onRefresh func(*oauth2.Token)
+
+	mu   sync.Mutex
+	last string
+}
+
+func (s *notifyingSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.AccessToken != s.last {
+		s.last = token.AccessToken
+		s.onRefresh(token)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,90 @@
+package oauthutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func setupTest(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &authorizations
+}
+
+func get(t *testing.T, client *http.Client, url string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+}
+
+func TestStaticAccessToken(t *testing.T) {
+	server, authorizations := setupTest(t)
+
+	base := server.Client()
+	baseTransport := base.Transport
+
+	client := StaticAccessToken(base, "secret")
+
+	get(t, client, server.URL)
+
+	assert.Equal(t, []string{"Bearer secret"}, *authorizations)
+
+	// the given client is not modified.
+	assert.Same(t, baseTransport, base.Transport)
+
+	transport, ok := client.Transport.(*oauth2.Transport)
+	require.True(t, ok)
+	assert.Same(t, baseTransport, transport.Base)
+}
+
+func TestStaticAccessToken_nilClient(t *testing.T) {
+	assert.Equal(t, DefaultTimeout, StaticAccessToken(nil, "secret").Timeout)
+	assert.Equal(t, 15*time.Second, StaticAccessToken(nil, "secret", WithTimeout(15*time.Second)).Timeout)
+}
+
+type sequenceSource struct {
+	tokens []string
+	calls  int
+}
+
+func (s *sequenceSource) Token() (*oauth2.Token, error) {
+	token := s.tokens[min(s.calls, len(s.tokens)-1)]
+	s.calls++
+
+	// expired immediately, so ReuseTokenSource asks for a new token on each request.
+	return &oauth2.Token{AccessToken: token, Expiry: time.Now().Add(-time.Hour)}, nil
+}
+
+func TestStaticAccessToken_tokenSource(t *testing.T) {
+	server, authorizations := setupTest(t)
+
+	var refreshed []string
+
+	client := StaticAccessToken(server.Client(), "",
+		WithTokenSource(&sequenceSource{tokens: []string{"a", "a", "b"}}),
+		WithRefreshCallback(func(token *oauth2.Token) { refreshed = append(refreshed, token.AccessToken) }),
+	)
+
+	for range 3 {
+		get(t, client, server.URL)
+	}
+
+	assert.Equal(t, []string{"Bearer a", "Bearer a", "Bearer b"}, *authorizations)
+	assert.Equal(t, []string{"a", "b"}, refreshed)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	querystring "github.com/google/go-querystring/query"
@@ -21,6 +22,10 @@ const (
 	DefaultRimuHostingBaseURL = "https://rimuhosting.com/dns/dyndns.jsp"
 )
 
+// numberedKeys matches the numbered keys of the query (e.g. `action%5B%5D0=`),
+// the API expects `action%5B0%5D=`.
+var numberedKeys = regexp.MustCompile(`(%5B)(%5D)(\d+)=`)
+
 // Action names.
 const (
 	SetAction    = "SET"
@@ -64,6 +69,43 @@ func (c Client) FindTXTRecords(ctx context.Context, domain string) ([]Record, er
 	return resp.Actions.Action.Records, nil
 }
 
+// AddTXTRecord adds a value to the TXT records of the name.
+// A SET action replaces the values of the name:
+// the other values of the name are set in the same request, they are kept.
+func (c Client) AddTXTRecord(ctx context.Context, name, value string, ttl int) error {
+	records, err := c.FindTXTRecords(ctx, name)
+	if err != nil {
+		return fmt.Errorf("find records: %w", err)
+	}
+
+	actions := []ActionParameter{NewAddRecordAction(name, value, ttl)}
+
+	for _, record := range records {
+		if !strings.EqualFold(record.Name, name) || record.Content == value {
+			continue
+		}
+
+		actions = append(actions, NewAddRecordAction(record.Name, record.Content, ttl))
+	}
+
+	_, err = c.DoActions(ctx, actions...)
+	if err != nil {
+		return fmt.Errorf("set records: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTXTRecord removes a value from the TXT records of the name, the other values of the name are kept.
+func (c Client) DeleteTXTRecord(ctx context.Context, name, value string) error {
+	_, err := c.DoActions(ctx, NewDeleteRecordAction(name, value))
+	if err != nil {
+		return fmt.Errorf("delete record: %w", err)
+	}
+
+	return nil
+}
+
 // DoActions performs actions.
 func (c Client) DoActions(ctx context.Context, actions ...ActionParameter) (*DNSAPIResult, error) {
 	if len(actions) == 0 {
@@ -120,8 +162,7 @@ func (c Client) do(ctx context.Context, params, result any) error {
 		return err
 	}
 
-	exp := regexp.MustCompile(`(%5B)(%5D)(\d+)=`)
-	baseURL.RawQuery = exp.ReplaceAllString(v.Encode(), "${1}${3}${2}=")
+	baseURL.RawQuery = numberedKeys.ReplaceAllString(v.Encode(), "${1}${3}${2}=")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.String(), http.NoBody)
 	if err != nil {
@@ -165,7 +206,7 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errAPI
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errAPI)
 }
 
 // NewAddRecordAction helper to create an action to add a TXT record.
@@ -128,7 +128,7 @@ func TestClient_DoActions(t *testing.T) {
 			fixture: "./fixtures/add_record_error.xml",
 			expected: expected{
 				Query: "action=SET&api_key=apikeyvaluehere&name=example.com&type=TXT&value=txttxtx",
-				Error: "ERROR: No zone found for example.com",
+				Error: "[status code: 500] ERROR: No zone found for example.com",
 			},
 		},
 		{
@@ -207,7 +207,7 @@ func TestClient_DoActions(t *testing.T) {
 			fixture: "./fixtures/delete_record_error.xml",
 			expected: expected{
 				Query: "action=DELETE&api_key=apikeyvaluehere&name=example.com&type=TXT&value=txttxtx",
-				Error: "ERROR: No zone found for example.com",
+				Error: "[status code: 500] ERROR: No zone found for example.com",
 			},
 		},
 		{
@@ -304,6 +304,71 @@ func TestClient_DoActions(t *testing.T) {
 	}
 }
 
+func TestClient_AddTXTRecord(t *testing.T) {
+	client, mux := setupTest(t)
+
+	var queries []string
+
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		query, err := url.QueryUnescape(req.URL.RawQuery)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		queries = append(queries, query)
+
+		fixture := "./fixtures/add_record_same_domain.xml"
+		if req.URL.Query().Get("action") == QueryAction {
+			fixture = "./fixtures/find_records_multi.xml"
+		}
+
+		err = writeResponse(rw, fixture)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	err := client.AddTXTRecord(context.Background(), "_acme-challenge.example.org", "txttxtx", 120)
+	require.NoError(t, err)
+
+	expected := []string{
+		"action=QUERY&api_key=apikeyvaluehere&name=_acme-challenge.example.org&type=TXT",
+		"action[0]=SET&action[1]=SET&api_key=apikeyvaluehere" +
+			"&name[0]=_acme-challenge.example.org&name[1]=_acme-challenge.example.org" +
+			"&ttl[0]=120&ttl[1]=120&type[0]=TXT&type[1]=TXT&value[0]=txttxtx&value[1]=other",
+	}
+
+	assert.Equal(t, expected, queries)
+}
+
+func TestClient_DeleteTXTRecord(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		query, err := url.QueryUnescape(req.URL.RawQuery)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if query != "action=DELETE&api_key=apikeyvaluehere&name=example.org&type=TXT&value=txttxtx" {
+			http.Error(rw, fmt.Sprintf("invalid query: %s", query), http.StatusBadRequest)
+			return
+		}
+
+		err = writeResponse(rw, "./fixtures/delete_record.xml")
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	err := client.DeleteTXTRecord(context.Background(), "example.org", "txttxtx")
+	require.NoError(t, err)
+}
+
 func writeResponse(rw io.Writer, filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -0,0 +1,82 @@
+// Package tlsutil sets the TLS options of the HTTP clients of the providers talking to self-hosted appliances,
+// e.g. a self-signed certificate or a private CA.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"lego-toolbox/internal/clientdebug"
+)
+
+// SetTLSConfig sets the TLS options on the transport of the client:
+// insecureSkipVerify accepts any server certificate,
+// caFile is the PEM file of the CA verifying the server certificate (the system roots are used when empty).
+// A debug transport is preserved, the options are set on the transport it wraps.
+func SetTLSConfig(client *http.Client, insecureSkipVerify bool, caFile string) error {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	if caFile != "" {
+		raw, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return fmt.Errorf("no certificate found in the CA file %s", caFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if debug, ok := client.Transport.(*clientdebug.Transport); ok {
+		debug.Base = withTLSConfig(debug.Base, tlsConfig)
+		return nil
+	}
+
+	client.Transport = withTLSConfig(client.Transport, tlsConfig)
+
+	return nil
+}
+
+// withTLSConfig returns a clone of the transport with the TLS config,
+// the settings of the transport (e.g. the dialer restricted to an IP family) are preserved.
+func withTLSConfig(rt http.RoundTripper, tlsConfig *tls.Config) *http.Transport {
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := base.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return transport
+}
+
+// SetClientCertificate sets the client certificate (mTLS) on the transport of the client,
+// the other TLS options of the transport are preserved.
+// A debug transport is preserved, the certificate is set on the transport it wraps.
+func SetClientCertificate(client *http.Client, cert tls.Certificate) {
+	if debug, ok := client.Transport.(*clientdebug.Transport); ok {
+		debug.Base = withClientCertificate(debug.Base, cert)
+		return
+	}
+
+	client.Transport = withClientCertificate(client.Transport, cert)
+}
+
+func withClientCertificate(rt http.RoundTripper, cert tls.Certificate) *http.Transport {
+	tlsConfig := &tls.Config{}
+
+	if base, ok := rt.(*http.Transport); ok && base.TLSClientConfig != nil {
+		tlsConfig = base.TLSClientConfig.Clone()
+	}
+
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	return withTLSConfig(rt, tlsConfig)
+}
@@ -0,0 +1,74 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/internal/clientdebug"
+)
+
+func TestSetTLSConfig(t *testing.T) {
+	client := &http.Client{}
+
+	err := SetTLSConfig(client, true, "")
+	require.NoError(t, err)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestSetTLSConfig_preservesTransport(t *testing.T) {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, net.ErrClosed
+	}
+
+	client := clientdebug.Wrap(&http.Client{Transport: &http.Transport{DialContext: dial}})
+
+	err := SetTLSConfig(client, true, "")
+	require.NoError(t, err)
+
+	debug, ok := client.Transport.(*clientdebug.Transport)
+	require.True(t, ok)
+
+	transport, ok := debug.Base.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.NotNil(t, transport.DialContext)
+}
+
+func TestSetTLSConfig_caFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	err := SetTLSConfig(&http.Client{}, false, caFile)
+	require.EqualError(t, err, "no certificate found in the CA file "+caFile)
+
+	err = SetTLSConfig(&http.Client{}, false, filepath.Join(t.TempDir(), "missing.pem"))
+	require.ErrorContains(t, err, "read CA file")
+}
+
+func TestSetClientCertificate(t *testing.T) {
+	client := &http.Client{}
+
+	require.NoError(t, SetTLSConfig(client, true, ""))
+
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+	SetClientCertificate(client, cert)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.Equal(t, []tls.Certificate{cert}, transport.TLSClientConfig.Certificates)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
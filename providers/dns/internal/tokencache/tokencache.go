@@ -0,0 +1,299 @@
+// Package tokencache persists the auth tokens of the providers doing login flows across process restarts,
+// so a new run reuses the valid token of the previous one instead of authenticating again.
+//
+// The tokens are stored in a single file, encrypted with AES-GCM (see configcrypto.AESGCM),
+// keyed by a hash of the credentials: the credentials are never written.
+// The cache is optional, it is enabled by the environment (see Default).
+package tokencache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/configcrypto"
+)
+
+// Environment variables enabling the default cache.
+// The key can also be read from a file, by suffixing the variable with `_FILE`.
+const (
+	// EnvPath is the path of the cache file.
+	EnvPath = "LEGO_TOOLBOX_TOKEN_CACHE"
+	// EnvKey is the base64 encoded AES key (16, 24 or 32 bytes) encrypting the cache file,
+	// configcrypto.EnvAESKey when unset.
+	EnvKey = "LEGO_TOOLBOX_TOKEN_CACHE_KEY"
+)
+
+// ExpiryMargin is the remaining validity under which a cached token is not returned,
+// so a token does not expire during a challenge.
+const ExpiryMargin = 5 * time.Minute
+
+type entry struct {
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Cache is a file-backed encrypted token cache, safe for concurrent use.
+// The file is read again before each write, so the processes sharing the file keep the tokens of the others.
+type Cache struct {
+	mu   sync.Mutex
+	path string
+	aead *configcrypto.AESGCM
+
+	now func() time.Time
+}
+
+// New creates a cache stored in the file at path, encrypted with the AES key (16, 24 or 32 bytes).
+func New(path string, key []byte) (*Cache, error) {
+	if path == "" {
+		return nil, errors.New("tokencache: missing path")
+	}
+
+	aead, err := configcrypto.NewAESGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("tokencache: %w", err)
+	}
+
+	return &Cache{path: path, aead: aead, now: time.Now}, nil
+}
+
+// Key returns the cache key of the credentials: a hash of the provider name, the endpoint and the credentials.
+func Key(parts ...string) string {
+	h := sha256.New()
+
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get decodes the token of key into v, and returns its expiry.
+// A nil cache, a missing token, or a token expiring within ExpiryMargin, is reported as not found.
+func (c *Cache) Get(key string, v any) (expiresAt time.Time, ok bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		log.Warnf("tokencache: %v", err)
+		return time.Time{}, false
+	}
+
+	e, found := entries[key]
+	if !found || !c.now().Add(ExpiryMargin).Before(e.ExpiresAt) {
+		return time.Time{}, false
+	}
+
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		return time.Time{}, false
+	}
+
+	return e.ExpiresAt, true
+}
+
+// Set stores the token v of key until expiresAt, the expired tokens are removed.
+// A nil cache and a token without expiry are ignored.
+func (c *Cache) Set(key string, v any, expiresAt time.Time) error {
+	if c == nil || expiresAt.IsZero() {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		// an unreadable cache (e.g. after a key rotation) is replaced.
+		log.Warnf("tokencache: %v", err)
+
+		entries = make(map[string]entry)
+	}
+
+	entries[key] = entry{ExpiresAt: expiresAt, Value: raw}
+
+	return c.save(entries)
+}
+
+// Delete removes the token of key, e.g. once revoked.
+func (c *Cache) Delete(key string) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+
+	delete(entries, key)
+
+	return c.save(entries)
+}
+
+func (c *Cache) load() (map[string]entry, error) {
+	entries := make(map[string]entry)
+
+	blob, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.aead.Decrypt(blob)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.path, err)
+	}
+
+	defer clear(raw)
+
+	err = json.Unmarshal(raw, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.path, err)
+	}
+
+	return entries, nil
+}
+
+func (c *Cache) save(entries map[string]entry) error {
+	now := c.now()
+
+	for key, e := range entries {
+		if !now.Before(e.ExpiresAt) {
+			delete(entries, key)
+		}
+	}
+
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	defer clear(raw)
+
+	blob, err := c.aead.Encrypt(raw)
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0o700)
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	// written aside then renamed: the other processes never read a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(blob)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), c.path)
+	if err != nil {
+		return fmt.Errorf("tokencache: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultCache  *Cache
+	defaultLoaded bool
+)
+
+// Default returns the cache configured by the environment variables (EnvPath, EnvKey),
+// nil when EnvPath is not set: the methods of a nil cache do nothing.
+// An invalid configuration is logged, and disables the cache.
+func Default() *Cache {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultLoaded {
+		return defaultCache
+	}
+
+	defaultLoaded = true
+
+	cache, err := fromEnv()
+	if err != nil {
+		log.Warnf("tokencache: disabled: %v", err)
+	}
+
+	defaultCache = cache
+
+	return defaultCache
+}
+
+// SetDefault replaces the cache returned by Default, nil disables it.
+func SetDefault(cache *Cache) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultCache = cache
+	defaultLoaded = true
+}
+
+func fromEnv() (*Cache, error) {
+	path := env.GetOrFile(EnvPath)
+	if path == "" {
+		return nil, nil
+	}
+
+	key := env.GetOrFile(EnvKey)
+	if key == "" {
+		key = env.GetOrFile(configcrypto.EnvAESKey)
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("missing key: set %s or %s", EnvKey, configcrypto.EnvAESKey)
+	}
+
+	aead, err := configcrypto.NewAESGCMFromBase64(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{path: path, aead: aead, now: time.Now}, nil
+}
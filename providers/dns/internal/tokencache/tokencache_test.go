@@ -0,0 +1,143 @@
+package tokencache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/configcrypto"
+)
+
+type token struct {
+	Value string `json:"value"`
+}
+
+func newTestCache(t *testing.T, path string) *Cache {
+	t.Helper()
+
+	cache, err := New(path, bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	return cache
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+
+	cache := newTestCache(t, path)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	require.NoError(t, cache.Set(Key("example", "user", "secret"), token{Value: "abc"}, expiresAt))
+
+	// the credentials and the tokens are not written in clear.
+	blob, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, configcrypto.FormatAESGCM, configcrypto.Detect(blob))
+	assert.NotContains(t, string(blob), "abc")
+
+	// another process.
+	other := newTestCache(t, path)
+
+	var tok token
+
+	got, ok := other.Get(Key("example", "user", "secret"), &tok)
+	require.True(t, ok)
+	assert.Equal(t, token{Value: "abc"}, tok)
+	assert.True(t, expiresAt.Equal(got))
+
+	_, ok = other.Get(Key("example", "user", "other"), &tok)
+	assert.False(t, ok)
+
+	require.NoError(t, other.Delete(Key("example", "user", "secret")))
+
+	_, ok = cache.Get(Key("example", "user", "secret"), &tok)
+	assert.False(t, ok)
+}
+
+func TestCache_expiry(t *testing.T) {
+	cache := newTestCache(t, filepath.Join(t.TempDir(), "tokens"))
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.Set("a", token{Value: "a"}, now.Add(ExpiryMargin-time.Second)))
+	require.NoError(t, cache.Set("b", token{Value: "b"}, now.Add(time.Hour)))
+	// no expiry: not cached.
+	require.NoError(t, cache.Set("c", token{Value: "c"}, time.Time{}))
+
+	var tok token
+
+	_, ok := cache.Get("a", &tok)
+	assert.False(t, ok, "expires within the margin")
+
+	_, ok = cache.Get("b", &tok)
+	assert.True(t, ok)
+
+	_, ok = cache.Get("c", &tok)
+	assert.False(t, ok)
+
+	// the expired tokens are removed on write.
+	now = now.Add(ExpiryMargin)
+	require.NoError(t, cache.Set("b", token{Value: "b"}, now.Add(time.Hour)))
+
+	entries, err := cache.load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCache_wrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+
+	require.NoError(t, newTestCache(t, path).Set("a", token{Value: "a"}, time.Now().Add(time.Hour)))
+
+	cache, err := New(path, bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+
+	var tok token
+
+	_, ok := cache.Get("a", &tok)
+	assert.False(t, ok)
+
+	// the unreadable cache is replaced.
+	require.NoError(t, cache.Set("b", token{Value: "b"}, time.Now().Add(time.Hour)))
+
+	_, ok = cache.Get("b", &tok)
+	assert.True(t, ok)
+}
+
+func TestCache_nil(t *testing.T) {
+	var cache *Cache
+
+	require.NoError(t, cache.Set("a", token{}, time.Now().Add(time.Hour)))
+	require.NoError(t, cache.Delete("a"))
+
+	_, ok := cache.Get("a", &token{})
+	assert.False(t, ok)
+}
+
+func Test_fromEnv(t *testing.T) {
+	t.Setenv(EnvPath, "")
+
+	cache, err := fromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, cache)
+
+	t.Setenv(EnvPath, filepath.Join(t.TempDir(), "tokens"))
+	t.Setenv(EnvKey, "")
+	t.Setenv(configcrypto.EnvAESKey, "")
+
+	_, err = fromEnv()
+	require.EqualError(t, err, "missing key: set LEGO_TOOLBOX_TOKEN_CACHE_KEY or LEGO_TOOLBOX_AES_KEY")
+
+	t.Setenv(configcrypto.EnvAESKey, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16)))
+
+	cache, err = fromEnv()
+	require.NoError(t, err)
+	assert.NotNil(t, cache)
+}
@@ -0,0 +1,102 @@
+// Package txtqueue sequences the challenges sharing a TXT record,
+// for the dynamic DNS APIs holding a single TXT value per record (DuckDNS, freemyip, do.de).
+//
+// An apex and wildcard order (or two concurrent orders of the same name) needs two values on the same record:
+// the second Present waits until the first value is cleaned up, instead of overwriting it.
+package txtqueue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type holder struct {
+	value string
+	// done is closed when the record is freed.
+	done chan struct{}
+}
+
+// Queue holds the records with a presented value, safe for concurrent use.
+type Queue struct {
+	mu      sync.Mutex
+	holders map[string]*holder
+}
+
+// New returns an empty queue.
+func New() *Queue {
+	return &Queue{holders: make(map[string]*holder)}
+}
+
+// Present waits until the record is free, holds it for the value, and calls present.
+// The record is freed when present fails.
+// Presenting again the value holding the record does not wait (e.g. a retried Present).
+// The wait is canceled with ctx.
+func (q *Queue) Present(ctx context.Context, record, value string, present func() error) error {
+	for {
+		q.mu.Lock()
+
+		h, ok := q.holders[record]
+		if !ok {
+			h = &holder{value: value, done: make(chan struct{})}
+			q.holders[record] = h
+		}
+
+		q.mu.Unlock()
+
+		if h.value == value {
+			break
+		}
+
+		select {
+		case <-h.done:
+		case <-ctx.Done():
+			return fmt.Errorf("the TXT record %s is still used by another challenge: %w", record, context.Cause(ctx))
+		}
+	}
+
+	err := present()
+	if err != nil {
+		q.free(record, value)
+		return err
+	}
+
+	return nil
+}
+
+// CleanUp calls cleanup and frees the record held by the value.
+// The record held by another value is left unchanged: cleanup is not called.
+// The record not held (e.g. presented before a restart) is cleaned up.
+func (q *Queue) CleanUp(record, value string, cleanup func() error) error {
+	q.mu.Lock()
+
+	h, ok := q.holders[record]
+	if ok && h.value != value {
+		q.mu.Unlock()
+		return nil
+	}
+
+	if !ok {
+		// holds the record during the cleanup, so it is not presented meanwhile.
+		q.holders[record] = &holder{value: value, done: make(chan struct{})}
+	}
+
+	q.mu.Unlock()
+
+	defer q.free(record, value)
+
+	return cleanup()
+}
+
+func (q *Queue) free(record, value string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	h, ok := q.holders[record]
+	if !ok || h.value != value {
+		return
+	}
+
+	delete(q.holders, record)
+	close(h.done)
+}
@@ -0,0 +1,106 @@
+package txtqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noop() error { return nil }
+
+func TestQueue_Present_sequence(t *testing.T) {
+	q := New()
+
+	var txt string
+
+	err := q.Present(context.Background(), "example.com", "a", func() error { txt = "a"; return nil })
+	require.NoError(t, err)
+
+	presented := make(chan error)
+
+	go func() {
+		presented <- q.Present(context.Background(), "example.com", "b", func() error { txt = "b"; return nil })
+	}()
+
+	select {
+	case <-presented:
+		t.Fatal("the second value must wait for the cleanup of the first one")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, "a", txt)
+
+	err = q.CleanUp("example.com", "a", func() error { txt = ""; return nil })
+	require.NoError(t, err)
+
+	require.NoError(t, <-presented)
+
+	assert.Equal(t, "b", txt)
+}
+
+func TestQueue_Present_sameValue(t *testing.T) {
+	q := New()
+
+	require.NoError(t, q.Present(context.Background(), "example.com", "a", noop))
+	require.NoError(t, q.Present(context.Background(), "example.com", "a", noop))
+}
+
+func TestQueue_Present_otherRecord(t *testing.T) {
+	q := New()
+
+	require.NoError(t, q.Present(context.Background(), "a.example.com", "a", noop))
+	require.NoError(t, q.Present(context.Background(), "b.example.com", "b", noop))
+}
+
+func TestQueue_Present_canceled(t *testing.T) {
+	q := New()
+
+	require.NoError(t, q.Present(context.Background(), "example.com", "a", noop))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := q.Present(ctx, "example.com", "b", noop)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "the TXT record example.com is still used by another challenge")
+}
+
+func TestQueue_Present_error(t *testing.T) {
+	q := New()
+
+	err := q.Present(context.Background(), "example.com", "a", func() error { return errors.New("boom") })
+	require.EqualError(t, err, "boom")
+
+	// the record is freed.
+	require.NoError(t, q.Present(context.Background(), "example.com", "b", noop))
+}
+
+func TestQueue_CleanUp_heldByOther(t *testing.T) {
+	q := New()
+
+	require.NoError(t, q.Present(context.Background(), "example.com", "a", noop))
+
+	var called bool
+
+	err := q.CleanUp("example.com", "b", func() error { called = true; return nil })
+	require.NoError(t, err)
+
+	assert.False(t, called)
+}
+
+func TestQueue_CleanUp_notHeld(t *testing.T) {
+	q := New()
+
+	var called bool
+
+	err := q.CleanUp("example.com", "a", func() error { called = true; return nil })
+	require.NoError(t, err)
+
+	assert.True(t, called)
+
+	require.NoError(t, q.Present(context.Background(), "example.com", "b", noop))
+}
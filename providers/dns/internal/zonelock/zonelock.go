@@ -0,0 +1,55 @@
+// Package zonelock serializes the updates of a zone across all the providers of the process.
+//
+// Some APIs only allow to replace the whole zone (or a whole record set):
+// the providers read the records, modify them, and write them back.
+// Two challenges of the same zone presented concurrently would lose one of the records.
+package zonelock
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+type entry struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var (
+	mu    sync.Mutex
+	zones = make(map[string]*entry)
+)
+
+// Lock locks the zone and returns the function to unlock it.
+// The zone name is normalized (lower case, trailing dot).
+func Lock(zone string) (unlock func()) {
+	key := dns01.ToFqdn(strings.ToLower(zone))
+
+	mu.Lock()
+
+	e, ok := zones[key]
+	if !ok {
+		e = &entry{}
+		zones[key] = e
+	}
+
+	e.refs++
+
+	mu.Unlock()
+
+	e.mu.Lock()
+
+	return func() {
+		e.mu.Unlock()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		e.refs--
+		if e.refs == 0 {
+			delete(zones, key)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package zonelock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	var (
+		wg      sync.WaitGroup
+		records []int
+	)
+
+	// read-modify-write of the "zone" without any other synchronization.
+	for i := range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			defer Lock("example.com")()
+
+			current := append([]int{}, records...)
+			time.Sleep(time.Millisecond)
+			records = append(current, i)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, records, 20)
+	assert.Empty(t, zones)
+}
+
+func TestLock_normalized(t *testing.T) {
+	unlock := Lock("Example.com")
+
+	locked := make(chan struct{})
+
+	go func() {
+		defer Lock("example.com.")()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("the zone is not locked")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("the zone is not unlocked")
+	}
+}
+
+func TestLock_otherZone(t *testing.T) {
+	unlock := Lock("example.com")
+	defer unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer Lock("example.org")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("another zone is locked")
+	}
+}
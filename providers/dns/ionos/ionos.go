@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,7 +13,9 @@ import (
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/zonelock"
 	"lego-toolbox/providers/dns/ionos/internal"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 300
@@ -28,6 +30,7 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvDisableZoneLock    = envNamespace + "DISABLE_ZONE_LOCK"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -36,6 +39,7 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
+	DisableZoneLock    bool          `yaml:"disableZoneLock"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -45,6 +49,7 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		DisableZoneLock:    env.GetOrDefaultBool(EnvDisableZoneLock, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -68,7 +73,8 @@ func GetYamlTemple() string {
 apiKey: "your_api_key_here"           # API 密钥，用于身份验证和授权
 propagationTimeout: 60s               # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 2s                   # 轮询间隔时间，表示系统定期检查更新的时间间隔
-ttl: 300                              # TTL（Time to Live），表示数据或缓存的有效时间（以秒为单位）`
+ttl: 300                              # TTL（Time to Live），表示数据或缓存的有效时间（以秒为单位）
+disableZoneLock: false                # 关闭进程内同一区域更新的串行化（默认 false）`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -94,7 +100,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +141,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	ctx := context.Background()
 
@@ -150,8 +156,13 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 		return errors.New("ionos: no matching zone found for domain")
 	}
 
+	// the records are replaced as a whole.
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(zone.Name)()
+	}
+
 	filter := &internal.RecordsFilter{
-		Suffix:     dns01.UnFqdn(info.EffectiveFQDN),
+		RecordName: dns01.UnFqdn(info.EffectiveFQDN),
 		RecordType: "TXT",
 	}
 
@@ -160,14 +171,12 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 		return fmt.Errorf("ionos: failed to get records (zone=%s): %w", zone.ID, err)
 	}
 
-	records = append(records, internal.Record{
-		Name:    dns01.UnFqdn(info.EffectiveFQDN),
-		Content: info.Value,
-		TTL:     d.config.TTL,
-		Type:    "TXT",
-	})
+	recordSet, changed := txtRecordSet(records, dns01.UnFqdn(info.EffectiveFQDN), info.Value, d.config.TTL)
+	if !changed {
+		return nil
+	}
 
-	err = d.client.ReplaceRecords(ctx, zone.ID, records)
+	err = d.client.ReplaceRecords(ctx, zone.ID, recordSet)
 	if err != nil {
 		return fmt.Errorf("ionos: failed to create/update records (zone=%s): %w", zone.ID, err)
 	}
@@ -177,7 +186,7 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	ctx := context.Background()
 
@@ -192,8 +201,13 @@ func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 		return errors.New("ionos: no matching zone found for domain")
 	}
 
+	// the records are replaced as a whole.
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(zone.Name)()
+	}
+
 	filter := &internal.RecordsFilter{
-		Suffix:     dns01.UnFqdn(info.EffectiveFQDN),
+		RecordName: dns01.UnFqdn(info.EffectiveFQDN),
 		RecordType: "TXT",
 	}
 
@@ -203,7 +217,7 @@ func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 	}
 
 	for _, record := range records {
-		if record.Name == dns01.UnFqdn(info.EffectiveFQDN) && record.Content == strconv.Quote(info.Value) {
+		if isTXTRecordOf(record, dns01.UnFqdn(info.EffectiveFQDN)) && unquote(record.Content) == info.Value {
 			err = d.client.RemoveRecord(ctx, zone.ID, record.ID)
 			if err != nil {
 				return fmt.Errorf("ionos: failed to remove record (zone=%s, record=%s): %w", zone.ID, record.ID, err)
@@ -215,6 +229,55 @@ func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 	return fmt.Errorf("ionos: failed to remove record, record not found (zone=%s, domain=%s, fqdn=%s, value=%s)", zone.ID, domain, info.EffectiveFQDN, info.Value)
 }
 
+// txtRecordSet returns the TXT recordset of the name with the value added,
+// as expected by ReplaceRecords (which replaces all the records of the same name and type).
+// The contents are unquoted: the API returns them quoted, and would quote them again.
+// The recordset is unchanged when it already holds the value.
+func txtRecordSet(records []internal.Record, name, value string, ttl int) ([]internal.Record, bool) {
+	var recordSet []internal.Record
+
+	for _, record := range records {
+		if !isTXTRecordOf(record, name) {
+			continue
+		}
+
+		content := unquote(record.Content)
+		if content == value {
+			return nil, false
+		}
+
+		recordSet = append(recordSet, internal.Record{
+			Name:     record.Name,
+			Content:  content,
+			TTL:      record.TTL,
+			Type:     record.Type,
+			Disabled: record.Disabled,
+		})
+	}
+
+	recordSet = append(recordSet, internal.Record{
+		Name:    name,
+		Content: value,
+		TTL:     ttl,
+		Type:    "TXT",
+	})
+
+	return recordSet, true
+}
+
+func isTXTRecordOf(record internal.Record, name string) bool {
+	return strings.EqualFold(dns01.UnFqdn(record.Name), name) && strings.EqualFold(record.Type, "TXT")
+}
+
+// unquote returns the content of a TXT record without the quotes added by the API.
+func unquote(content string) string {
+	if v, err := strconv.Unquote(content); err == nil {
+		return v
+	}
+
+	return content
+}
+
 func findZone(zones []internal.Zone, domain string) *internal.Zone {
 	var result *internal.Zone
 
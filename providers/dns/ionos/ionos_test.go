@@ -3,8 +3,12 @@ package ionos
 import (
 	"testing"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/ionos/internal"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -100,6 +104,30 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func Test_txtRecordSet(t *testing.T) {
+	records := []internal.Record{
+		{ID: "1", Name: "_acme-challenge.example.com", Content: `"a"`, TTL: 300, Type: "TXT"},
+		{ID: "2", Name: "_acme-challenge.example.com", Content: "b", TTL: 300, Type: "TXT"},
+		{ID: "3", Name: "sub._acme-challenge.example.com", Content: `"c"`, TTL: 300, Type: "TXT"},
+	}
+
+	recordSet, changed := txtRecordSet(records, "_acme-challenge.example.com", "d", 120)
+	require.True(t, changed)
+
+	expected := []internal.Record{
+		{Name: "_acme-challenge.example.com", Content: "a", TTL: 300, Type: "TXT"},
+		{Name: "_acme-challenge.example.com", Content: "b", TTL: 300, Type: "TXT"},
+		{Name: "_acme-challenge.example.com", Content: "d", TTL: 120, Type: "TXT"},
+	}
+	assert.Equal(t, expected, recordSet)
+
+	_, changed = txtRecordSet(records, "_acme-challenge.example.com", "a", 120)
+	assert.False(t, changed)
+
+	_, changed = txtRecordSet(records, "_acme-challenge.example.com", "b", 120)
+	assert.False(t, changed)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -125,3 +153,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
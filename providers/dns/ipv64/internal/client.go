@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"golang.org/x/oauth2"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -136,18 +135,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errAPI
-}
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 15 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errAPI)
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 const testAPIKey = "secret"
@@ -22,8 +23,7 @@ func setupTest(t *testing.T, handler http.HandlerFunc) *Client {
 
 	server := httptest.NewServer(handler)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), testAPIKey))
-	client.HTTPClient = server.Client()
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), testAPIKey))
 	client.baseURL, _ = url.Parse(server.URL)
 
 	return client
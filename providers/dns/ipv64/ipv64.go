@@ -6,21 +6,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/miekg/dns"
+	"lego-toolbox/providers/dns/internal/credmap"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 	"lego-toolbox/providers/dns/ipv64/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "IPV64_"
 
-	EnvAPIKey = envNamespace + "API_KEY"
+	EnvAPIKey  = envNamespace + "API_KEY"
+	EnvAPIKeys = envNamespace + "API_KEYS"
 
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -30,7 +34,10 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string        `yaml:"apiKey"`
+	APIKey string `yaml:"apiKey"`
+	// APIKeys are the API keys keyed by domain, for the domains of several accounts:
+	// a record uses the API key of its closest domain, or APIKey.
+	APIKeys            credmap.Map   `yaml:"apiKeys"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	HTTPClient         *http.Client  `yaml:"-"`
@@ -62,6 +69,8 @@ func DefaultConfig() *Config {
 func GetYamlTemple() string {
 	return `# YAML 示例
 apiKey: "your_api_key_here"           # API 密钥，用于身份验证和授权
+apiKeys:                              # 按域名区分的 API 密钥（多个账户），记录使用最近域名的密钥，否则使用 apiKey
+  example.ipv64.net: "your_other_api_key"
 propagationTimeout: 60s               # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 2s                   # 轮询间隔时间，表示系统定期检查更新的时间间隔
 # sequenceInterval: 10s               # 序列间隔时间，此字段已弃用，将在 v5 中移除`
@@ -70,18 +79,36 @@ pollingInterval: 2s                   # 轮询间隔时间，表示系统定期
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// client uses APIKey, nil without it.
 	client *internal.Client
+	// clients use the APIKeys, keyed by domain.
+	clients map[string]*internal.Client
+	apiKeys credmap.Map
 }
 
 // NewDNSProvider returns a new DNS provider using
-// environment variable IPV64_TOKEN for adding and removing the DNS record.
+// environment variable IPV64_API_KEY (or IPV64_API_KEYS) for adding and removing the DNS record.
 func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	if raw := env.GetOrFile(EnvAPIKeys); raw != "" {
+		apiKeys, err := credmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ipv64: %w", err)
+		}
+
+		config.APIKeys = apiKeys
+		config.APIKey = env.GetOrFile(EnvAPIKey)
+
+		return NewDNSProviderConfig(config)
+	}
+
 	values, err := env.Get(EnvAPIKey)
 	if err != nil {
 		return nil, fmt.Errorf("ipv64: %w", err)
 	}
 
-	config := NewDefaultConfig()
 	config.APIKey = values[EnvAPIKey]
 
 	return NewDNSProviderConfig(config)
@@ -90,7 +117,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -103,29 +130,60 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("ipv64: the configuration of the DNS provider is nil")
 	}
 
-	if config.APIKey == "" {
+	if config.APIKey == "" && len(config.APIKeys) == 0 {
 		return nil, errors.New("ipv64: credentials missing")
 	}
 
-	client := internal.NewClient(internal.OAuthStaticAccessToken(config.HTTPClient, config.APIKey))
+	apiKeys, err := credmap.Validate(config.APIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("ipv64: %w", err)
+	}
+
+	provider := &DNSProvider{config: config, clients: make(map[string]*internal.Client), apiKeys: apiKeys}
+
+	if config.APIKey != "" {
+		provider.client = newClient(config.HTTPClient, config.APIKey)
+	}
+
+	for domain, apiKey := range apiKeys {
+		provider.clients[domain] = newClient(config.HTTPClient, apiKey)
+	}
+
+	return provider, nil
+}
+
+func newClient(hc *http.Client, apiKey string) *internal.Client {
+	return internal.NewClient(oauthutil.StaticAccessToken(hc, apiKey, oauthutil.WithTimeout(15*time.Second)))
+}
+
+// clientFor returns the client using the API key of the closest domain of the record, or APIKey.
+func (d *DNSProvider) clientFor(fqdn string) (*internal.Client, error) {
+	if _, domain, ok := d.apiKeys.Match(fqdn); ok {
+		return d.clients[domain], nil
+	}
 
-	if config.HTTPClient != nil {
-		client.HTTPClient = config.HTTPClient
+	if d.client == nil {
+		return nil, fmt.Errorf("no API key for %s, check your API keys map", dns01.UnFqdn(fqdn))
 	}
 
-	return &DNSProvider{config: config, client: client}, nil
+	return d.client, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	sub, root, err := splitDomain(dns01.UnFqdn(info.EffectiveFQDN))
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
 
-	err = d.client.AddRecord(context.Background(), root, sub, "TXT", info.Value)
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("ipv64: %w", err)
+	}
+
+	err = client.AddRecord(context.Background(), root, sub, "TXT", info.Value)
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
@@ -135,14 +193,19 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp clears IPv64 TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	sub, root, err := splitDomain(dns01.UnFqdn(info.EffectiveFQDN))
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
 
-	err = d.client.DeleteRecord(context.Background(), root, sub, "TXT", info.Value)
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("ipv64: %w", err)
+	}
+
+	err = client.DeleteRecord(context.Background(), root, sub, "TXT", info.Value)
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
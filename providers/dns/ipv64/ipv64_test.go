@@ -11,7 +11,7 @@ import (
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvAPIKey).WithDomain(envDomain)
+var envTest = tester.NewEnvTest(EnvAPIKey, EnvAPIKeys).WithDomain(envDomain)
 
 func Test_splitDomain(t *testing.T) {
 	type expected struct {
@@ -102,6 +102,12 @@ func TestNewDNSProvider(t *testing.T) {
 				EnvAPIKey: "123",
 			},
 		},
+		{
+			desc: "success api keys",
+			envVars: map[string]string{
+				EnvAPIKeys: "example.ipv64.net:123,other.ipv64.de:456",
+			},
+		},
 		{
 			desc: "missing api key",
 			envVars: map[string]string{
@@ -109,6 +115,13 @@ func TestNewDNSProvider(t *testing.T) {
 			},
 			expected: "ipv64: some credentials information are missing: IPV64_API_KEY",
 		},
+		{
+			desc: "invalid api keys",
+			envVars: map[string]string{
+				EnvAPIKeys: "example.ipv64.net:123,other.ipv64.de",
+			},
+			expected: `ipv64: invalid credential pair: "other.ipv64.de"`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -135,22 +148,33 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
 		desc     string
 		apiKey   string
+		apiKeys  map[string]string
 		expected string
 	}{
 		{
 			desc:   "success",
 			apiKey: "123",
 		},
+		{
+			desc:    "success api keys",
+			apiKeys: map[string]string{"example.ipv64.net": "123"},
+		},
 		{
 			desc:     "missing credentials",
 			expected: "ipv64: credentials missing",
 		},
+		{
+			desc:     "missing api key in the map",
+			apiKeys:  map[string]string{"example.ipv64.net": ""},
+			expected: `ipv64: missing token for "example.ipv64.net"`,
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
 			config := NewDefaultConfig()
 			config.APIKey = test.apiKey
+			config.APIKeys = test.apiKeys
 
 			p, err := NewDNSProviderConfig(config)
 
@@ -165,6 +189,46 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+apiKey: "123"
+apiKeys:
+  example.ipv64.net: "456"
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "123", config.APIKey)
+	assert.Equal(t, map[string]string{"example.ipv64.net": "456"}, map[string]string(config.APIKeys))
+}
+
+func TestDNSProvider_clientFor(t *testing.T) {
+	config := NewDefaultConfig()
+	config.APIKeys = map[string]string{"example.ipv64.net": "456"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err := p.clientFor("_acme-challenge.sub.example.ipv64.net.")
+	require.NoError(t, err)
+
+	assert.Same(t, p.clients["example.ipv64.net"], client)
+
+	_, err = p.clientFor("_acme-challenge.other.ipv64.net.")
+	require.EqualError(t, err, "no API key for _acme-challenge.other.ipv64.net, check your API keys map")
+
+	config.APIKey = "123"
+
+	p, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err = p.clientFor("_acme-challenge.other.ipv64.net.")
+	require.NoError(t, err)
+
+	assert.Same(t, p.client, client)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -2,9 +2,12 @@
 package joker
 
 import (
-	"gopkg.in/yaml.v3"
+	"errors"
+	"fmt"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
@@ -36,11 +39,18 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Debug              bool          `yaml:"-"`
-	APIKey             string        `yaml:"apiKey"`
-	Username           string        `yaml:"username"`
-	Password           string        `yaml:"password"`
-	APIMode            string        `yaml:"apiMode"`
+	Debug    bool   `yaml:"-"`
+	APIKey   string `yaml:"apiKey"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	APIMode  string `yaml:"apiMode"`
+
+	// Mode selects the API (`dmapi` or `svc`) in the YAML config, it takes precedence over APIMode.
+	Mode string `yaml:"mode"`
+	// DMAPI and SVC are the credentials of each mode, the credentials of the selected mode are used.
+	DMAPI DMAPICredentials `yaml:"dmapi"`
+	SVC   SVCCredentials   `yaml:"svc"`
+
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
@@ -48,6 +58,19 @@ type Config struct {
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
+// DMAPICredentials are the credentials of the DMAPI mode: an API key, or a username and a password.
+type DMAPICredentials struct {
+	APIKey   string `yaml:"apiKey"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// SVCCredentials are the Dynamic DNS credentials of the SVC mode.
+type SVCCredentials struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
@@ -80,10 +103,14 @@ func DefaultConfig() *Config {
 
 func GetYamlTemple() string {
 	return `# YAML 示例
-apiKey: "your_api_key_here"           # API 密钥，用于身份验证和授权
-username: "your_username_here"        # 用户名，用于身份验证
-password: "your_password_here"        # 密码，用于身份验证
-apiMode: "DMAPI"                 # API 模式，例如 "DMAPI" 或 "SVC"
+mode: "dmapi"                         # API 模式，"dmapi" 或 "svc"
+dmapi:                                # DMAPI 模式的凭据，API 密钥或用户名和密码
+  apiKey: "your_api_key_here"         # API 密钥，用于身份验证和授权
+  username: "your_username_here"      # 用户名，用于身份验证
+  password: "your_password_here"      # 密码，用于身份验证
+svc:                                  # SVC 模式的凭据，域名控制面板中的 Dynamic DNS 凭据
+  username: "your_username_here"      # 用户名，用于身份验证
+  password: "your_password_here"      # 密码，用于身份验证
 propagationTimeout: 60s               # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 2s                   # 轮询间隔时间，表示系统定期检查更新的时间间隔
 sequenceInterval: 60s                 # 序列间隔时间
@@ -101,15 +128,65 @@ func NewDNSProvider() (challenge.ProviderTimeout, error) {
 }
 
 // ParseConfig parse bytes to config
+// The mode is selected with `mode` (or the legacy `apiMode`),
+// the credentials of the mode (`dmapi` or `svc`) replace the top-level credentials.
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
+
+	err = resolveMode(config)
+	if err != nil {
+		return nil, fmt.Errorf("joker: %w", err)
+	}
+
 	return config, nil
 }
 
+// resolveMode sets the APIMode and the credentials of the config from the mode, and validates the combination.
+func resolveMode(config *Config) error {
+	if config.Mode != "" {
+		config.APIMode = config.Mode
+	}
+
+	config.APIMode = strings.ToUpper(config.APIMode)
+
+	switch config.APIMode {
+	case modeDMAPI:
+		if config.DMAPI != (DMAPICredentials{}) {
+			config.APIKey = config.DMAPI.APIKey
+			config.Username = config.DMAPI.Username
+			config.Password = config.DMAPI.Password
+		}
+
+		if config.APIKey == "" && (config.Username == "" || config.Password == "") {
+			return errors.New("the DMAPI mode requires an API key, or a username and a password")
+		}
+
+	case modeSVC:
+		if config.SVC != (SVCCredentials{}) {
+			config.APIKey = ""
+			config.Username = config.SVC.Username
+			config.Password = config.SVC.Password
+		}
+
+		if config.APIKey != "" {
+			return errors.New("the SVC mode does not support the API key, use the Dynamic DNS username and password")
+		}
+
+		if config.Username == "" || config.Password == "" {
+			return errors.New("the SVC mode requires a username and a password")
+		}
+
+	default:
+		return fmt.Errorf("unknown mode %q, must be dmapi or svc", config.APIMode)
+	}
+
+	return nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for Joker.
 func NewDNSProviderConfig(config *Config) (challenge.ProviderTimeout, error) {
 	if config.APIMode == modeSVC {
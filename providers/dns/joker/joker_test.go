@@ -106,6 +106,93 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		mode     string
+		apiKey   string
+		username string
+		password string
+	}{
+		{
+			desc:     "legacy top-level credentials",
+			raw:      "apiMode: DMAPI\nusername: user\npassword: secret\n",
+			mode:     modeDMAPI,
+			username: "user",
+			password: "secret",
+		},
+		{
+			desc:   "dmapi mode",
+			raw:    "mode: dmapi\ndmapi:\n  apiKey: key\nsvc:\n  username: dyn\n  password: dynsecret\n",
+			mode:   modeDMAPI,
+			apiKey: "key",
+		},
+		{
+			desc:     "svc mode",
+			raw:      "mode: svc\ndmapi:\n  apiKey: key\nsvc:\n  username: dyn\n  password: dynsecret\n",
+			mode:     modeSVC,
+			username: "dyn",
+			password: "dynsecret",
+		},
+		{
+			desc:     "mode takes precedence over apiMode",
+			raw:      "apiMode: DMAPI\nmode: SVC\nusername: dyn\npassword: dynsecret\n",
+			mode:     modeSVC,
+			username: "dyn",
+			password: "dynsecret",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config, err := ParseConfig([]byte(test.raw))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.mode, config.APIMode)
+			assert.Equal(t, test.apiKey, config.APIKey)
+			assert.Equal(t, test.username, config.Username)
+			assert.Equal(t, test.password, config.Password)
+		})
+	}
+}
+
+func TestParseConfig_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected string
+	}{
+		{
+			desc:     "unknown mode",
+			raw:      "mode: rest\n",
+			expected: `joker: unknown mode "REST", must be dmapi or svc`,
+		},
+		{
+			desc:     "dmapi without credentials",
+			raw:      "mode: dmapi\nsvc:\n  username: dyn\n  password: dynsecret\n",
+			expected: "joker: the DMAPI mode requires an API key, or a username and a password",
+		},
+		{
+			desc:     "svc without credentials",
+			raw:      "mode: svc\ndmapi:\n  username: user\n  password: secret\n",
+			expected: "joker: the SVC mode requires a username and a password",
+		},
+		{
+			desc:     "svc with an API key",
+			raw:      "mode: svc\napiKey: key\n",
+			expected: "joker: the SVC mode does not support the API key, use the Dynamic DNS username and password",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseConfig([]byte(test.raw))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
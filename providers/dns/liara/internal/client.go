@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"time"
 
-	"golang.org/x/oauth2"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -192,18 +191,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code: %d] %w", resp.StatusCode, &errAPI)
-}
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
+	return errutils.NewAPIError(req, resp.StatusCode, raw, &errAPI).WithCode(errAPI.ErrorCode)
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 const apiKey = "key"
@@ -166,7 +167,7 @@ func setupTest(t *testing.T) (*Client, *http.ServeMux) {
 	server := httptest.NewServer(mux)
 	t.Cleanup(server.Close)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), apiKey))
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), apiKey))
 	client.baseURL, _ = url.Parse(server.URL)
 
 	return client, mux
@@ -6,14 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"net/http"
+	"lego-toolbox/yamlconfig"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/linode/linodego"
-	"golang.org/x/oauth2"
+	"lego-toolbox/providers/dns/internal/oauthutil"
+	"lego-toolbox/rawrecord"
 )
 
 const (
@@ -59,7 +59,7 @@ func DefaultConfig() *Config {
 		TTL:                minTTL,
 		PropagationTimeout: 60 * time.Second,
 		PollingInterval:    15 * time.Second,
-		HTTPTimeout:        30,
+		HTTPTimeout:        30 * time.Second,
 	}
 }
 
@@ -100,7 +100,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -121,12 +121,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("linode: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
 	}
 
-	oauth2Client := &http.Client{
-		Timeout: config.HTTPTimeout,
-		Transport: &oauth2.Transport{
-			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.Token}),
-		},
-	}
+	oauth2Client := oauthutil.StaticAccessToken(nil, config.Token, oauthutil.WithTimeout(config.HTTPTimeout))
 
 	client := linodego.NewClient(oauth2Client)
 	client.SetUserAgent("go-acme/lego https://github.com/linode/linodego")
@@ -156,7 +151,7 @@ func (d *DNSProvider) Timeout() (time.Duration, time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZoneInfo(info.EffectiveFQDN)
 	if err != nil {
@@ -176,7 +171,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZoneInfo(info.EffectiveFQDN)
 	if err != nil {
@@ -4,17 +4,18 @@ package liquidweb
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	lw "github.com/liquidweb/liquidweb-go/client"
 	"github.com/liquidweb/liquidweb-go/network"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 const defaultBaseURL = "https://api.liquidweb.com"
@@ -83,10 +84,9 @@ httpTimeout: 60s                             # HTTP 超时时间，表示 HTTP 
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	config      *Config
-	client      *lw.API
-	recordIDs   map[string]int
-	recordIDsMu sync.Mutex
+	config    *Config
+	client    *lw.API
+	recordIDs *challengestore.Store[int]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Liquid Web.
@@ -111,7 +111,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +135,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	return &DNSProvider{
 		config:    config,
-		recordIDs: make(map[string]int),
+		recordIDs: challengestore.New[int](),
 		client:    client,
 	}, nil
 }
@@ -146,9 +146,15 @@ func (d *DNSProvider) Timeout() (time.Duration, time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	params := &network.DNSRecordParams{
 		Name:  dns01.UnFqdn(info.EffectiveFQDN),
@@ -172,18 +178,16 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("liquidweb: could not create TXT record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = int(dnsEntry.ID)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, int(dnsEntry.ID))
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 
 	if !ok {
 		return fmt.Errorf("liquidweb: unknown record ID for '%s'", domain)
@@ -195,9 +199,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("liquidweb: could not remove TXT record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
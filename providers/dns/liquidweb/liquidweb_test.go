@@ -6,7 +6,9 @@ import (
 
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/liquidweb/liquidweb-go/network"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/rawrecord"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -177,12 +179,34 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 		ZoneID: 42,
 	})
 
-	provider.recordIDs["123d=="] = 1234567
+	provider.recordIDs.Set("123d==", rawrecord.GetChallengeInfo("tacoman.com.", "").EffectiveFQDN, 1234567)
 
 	err := provider.CleanUp("tacoman.com.", "123d==", "")
 	require.NoError(t, err)
 }
 
+func TestDNSProvider_CleanUp_sameTokenSeveralDomains(t *testing.T) {
+	provider := setupTest(t)
+
+	// one order, the same token for two domains.
+	require.NoError(t, provider.Present("tacoman.com", "token", "keyAuth"))
+	require.NoError(t, provider.Present("banana.com", "token", "keyAuth"))
+
+	tacomanID, ok := provider.recordIDs.Get("token", "_acme-challenge.tacoman.com.")
+	require.True(t, ok)
+
+	bananaID, ok := provider.recordIDs.Get("token", "_acme-challenge.banana.com.")
+	require.True(t, ok)
+
+	assert.NotEqual(t, tacomanID, bananaID)
+
+	// the mock API rejects the deletion of an unknown record.
+	require.NoError(t, provider.CleanUp("tacoman.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("banana.com", "token", "keyAuth"))
+
+	assert.Equal(t, 0, provider.recordIDs.Len())
+}
+
 func TestDNSProvider(t *testing.T) {
 	testCases := []struct {
 		desc          string
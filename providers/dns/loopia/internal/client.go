@@ -106,6 +106,23 @@ func (c *Client) GetTXTRecords(ctx context.Context, domain string, subdomain str
 	return resp.Params, err
 }
 
+// GetSubdomains gets the sub-domains of the domain.
+func (c *Client) GetSubdomains(ctx context.Context, domain string) ([]string, error) {
+	call := &methodCall{
+		MethodName: "getSubdomains",
+		Params: []param{
+			paramString{Value: c.apiUser},
+			paramString{Value: c.apiPassword},
+			paramString{Value: domain},
+		},
+	}
+	resp := &subdomainsResponse{}
+
+	err := c.rpcCall(ctx, call, resp)
+
+	return resp.Params, err
+}
+
 // RemoveSubdomain remove a sub-domain.
 func (c *Client) RemoveSubdomain(ctx context.Context, domain, subdomain string) error {
 	call := &methodCall{
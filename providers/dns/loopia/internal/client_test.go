@@ -209,6 +209,22 @@ func TestClient_GetZoneRecord(t *testing.T) {
 	assert.EqualValues(t, expected, recordObjs)
 }
 
+func TestClient_GetSubdomains(t *testing.T) {
+	serverResponses := map[string]string{
+		getSubdomains: getSubdomainsResponse,
+	}
+
+	serverURL := createFakeServer(t, serverResponses)
+
+	client := NewClient("apiuser", "goodpassword")
+	client.BaseURL = serverURL + "/"
+
+	subdomains, err := client.GetSubdomains(context.Background(), exampleDomain)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"@", "www", exampleSubDomain}, subdomains)
+}
+
 func TestClient_rpcCall_404(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, err := io.ReadAll(r.Body)
@@ -423,6 +423,51 @@ const getZoneRecordsResponse = `<?xml version="1.0" encoding="UTF-8"?>
   </params>
 </methodResponse>`
 
+const getSubdomains = `<?xml version="1.0" encoding="UTF-8"?>
+<methodCall>
+  <methodName>getSubdomains</methodName>
+  <params>
+    <param>
+      <value>
+        <string>apiuser</string>
+      </value>
+    </param>
+    <param>
+      <value>
+        <string>goodpassword</string>
+      </value>
+    </param>
+    <param>
+      <value>
+        <string>example.com</string>
+      </value>
+    </param>
+  </params>
+</methodCall>`
+
+const getSubdomainsResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse>
+  <params>
+    <param>
+      <value>
+        <array>
+          <data>
+            <value>
+              <string>@</string>
+            </value>
+            <value>
+              <string>www</string>
+            </value>
+            <value>
+              <string>_acme-challenge</string>
+            </value>
+          </data>
+        </array>
+      </value>
+    </param>
+  </params>
+</methodResponse>`
+
 const removeRecordGoodAuth = `<?xml version="1.0" encoding="UTF-8"?>
 <methodCall>
   <methodName>removeZoneRecord</methodName>
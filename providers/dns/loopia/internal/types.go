@@ -92,6 +92,12 @@ type recordObjectsResponse struct {
 	Params  []RecordObj `xml:"params>param>value>array>data>value>struct"`
 }
 
+type subdomainsResponse struct {
+	responseFault
+	XMLName xml.Name `xml:"methodResponse"`
+	Params  []string `xml:"params>param>value>array>data>value>string"`
+}
+
 type RecordObj struct {
 	Type     string
 	TTL      int
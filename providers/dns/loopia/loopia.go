@@ -5,14 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
 	"lego-toolbox/providers/dns/loopia/internal"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 300
@@ -29,12 +32,15 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+
+	EnvRemoveSubdomainOnCleanup = envNamespace + "REMOVE_SUBDOMAIN_ON_CLEANUP"
 )
 
 type dnsClient interface {
 	AddTXTRecord(ctx context.Context, domain string, subdomain string, ttl int, value string) error
 	RemoveTXTRecord(ctx context.Context, domain string, subdomain string, recordID int) error
 	GetTXTRecords(ctx context.Context, domain string, subdomain string) ([]internal.RecordObj, error)
+	GetSubdomains(ctx context.Context, domain string) ([]string, error)
 	RemoveSubdomain(ctx context.Context, domain, subdomain string) error
 }
 
@@ -46,15 +52,19 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
-	HTTPClient         *http.Client  `yaml:"-"`
+	// RemoveSubdomainOnCleanup removes the subdomain of the challenge once its last record is removed,
+	// only when the subdomain was created by the provider: the existing subdomains are kept, even when empty.
+	RemoveSubdomainOnCleanup bool         `yaml:"removeSubdomainOnCleanup"`
+	HTTPClient               *http.Client `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 40*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 60*time.Second),
+		TTL:                      env.GetOrDefaultInt(EnvTTL, minTTL),
+		PropagationTimeout:       env.GetOrDefaultSecond(EnvPropagationTimeout, 40*time.Minute),
+		PollingInterval:          env.GetOrDefaultSecond(EnvPollingInterval, 60*time.Second),
+		RemoveSubdomainOnCleanup: env.GetOrDefaultBool(EnvRemoveSubdomainOnCleanup, true),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 60*time.Second),
 		},
@@ -64,9 +74,10 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
-		TTL:                minTTL,
-		PropagationTimeout: 40 * time.Minute,
-		PollingInterval:    60 * time.Second,
+		TTL:                      minTTL,
+		PropagationTimeout:       40 * time.Minute,
+		PollingInterval:          60 * time.Second,
+		RemoveSubdomainOnCleanup: true,
 		HTTPClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
@@ -80,7 +91,8 @@ apiUser: "your_api_user_here"                # API 用户名，用于身份验
 apiPassword: "your_api_password_here"        # API 密码，用于身份验证
 propagationTimeout: 40m                      # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 60s                         # 轮询间隔时间，表示系统定期检查更新的时间间隔
-ttl: 300                                     # TTL（Time to Live），表示数据或缓存的有效时间（以秒为单位）`
+ttl: 300                                     # TTL（Time to Live），表示数据或缓存的有效时间（以秒为单位）
+removeSubdomainOnCleanup: true               # 清理时删除由提供者创建的空子域名（已有的子域名始终保留）`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -88,9 +100,13 @@ type DNSProvider struct {
 	config *Config
 	client dnsClient
 
-	inProgressInfo map[string]int
+	inProgressInfo *challengestore.Store[int]
 	inProgressMu   sync.Mutex
 
+	// createdSubdomains are the FQDNs of the subdomains created by Present, keyed by the FQDN only (empty token):
+	// the challenges of all the tokens share the subdomain.
+	createdSubdomains *challengestore.Store[bool]
+
 	// only for testing purpose.
 	findZoneByFqdn func(fqdn string) (string, error)
 }
@@ -115,7 +131,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +167,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config:         config,
 		client:         client,
 		findZoneByFqdn: dns01.FindZoneByFqdn,
-		inProgressInfo: make(map[string]int),
+		inProgressInfo: challengestore.New[int](),
+
+		createdSubdomains: challengestore.New[bool](),
 	}, nil
 }
 
@@ -161,9 +179,20 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	err := d.inProgressInfo.Persist(path)
+	if err != nil {
+		return err
+	}
+
+	return d.createdSubdomains.Persist(challengestore.Path(path, "subdomains"))
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	subDomain, authZone, err := d.splitDomain(info.EffectiveFQDN)
 	if err != nil {
@@ -172,22 +201,34 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
+	d.inProgressMu.Lock()
+	defer d.inProgressMu.Unlock()
+
+	var created bool
+	if d.config.RemoveSubdomainOnCleanup {
+		created, err = d.isNewSubdomain(ctx, authZone, subDomain)
+		if err != nil {
+			return fmt.Errorf("loopia: failed to get subdomains: %w", err)
+		}
+	}
+
 	err = d.client.AddTXTRecord(ctx, authZone, subDomain, d.config.TTL, info.Value)
 	if err != nil {
 		return fmt.Errorf("loopia: failed to add TXT record: %w", err)
 	}
 
+	if created {
+		d.createdSubdomains.Set("", info.EffectiveFQDN, true)
+	}
+
 	txtRecords, err := d.client.GetTXTRecords(ctx, authZone, subDomain)
 	if err != nil {
 		return fmt.Errorf("loopia: failed to get TXT records: %w", err)
 	}
 
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
-
 	for _, r := range txtRecords {
 		if r.Rdata == info.Value {
-			d.inProgressInfo[token] = r.RecordID
+			d.inProgressInfo.Set(token, info.EffectiveFQDN, r.RecordID)
 			return nil
 		}
 	}
@@ -197,7 +238,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	subDomain, authZone, err := d.splitDomain(info.EffectiveFQDN)
 	if err != nil {
@@ -209,17 +250,28 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	err = d.client.RemoveTXTRecord(ctx, authZone, subDomain, d.inProgressInfo[token])
+	recordID, ok := d.inProgressInfo.Get(token, info.EffectiveFQDN)
+	if !ok {
+		return fmt.Errorf("loopia: unknown record ID for '%s'", info.EffectiveFQDN)
+	}
+
+	err = d.client.RemoveTXTRecord(ctx, authZone, subDomain, recordID)
 	if err != nil {
 		return fmt.Errorf("loopia: failed to remove TXT record: %w", err)
 	}
 
+	d.inProgressInfo.Delete(token, info.EffectiveFQDN)
+
 	records, err := d.client.GetTXTRecords(ctx, authZone, subDomain)
 	if err != nil {
 		return fmt.Errorf("loopia: failed to get TXT records: %w", err)
 	}
 
-	if len(records) > 0 {
+	if len(records) > 0 || !d.config.RemoveSubdomainOnCleanup {
+		return nil
+	}
+
+	if _, ok := d.createdSubdomains.Get("", info.EffectiveFQDN); !ok {
 		return nil
 	}
 
@@ -228,9 +280,21 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("loopia: failed to remove subdomain: %w", err)
 	}
 
+	d.createdSubdomains.Delete("", info.EffectiveFQDN)
+
 	return nil
 }
 
+// isNewSubdomain reports whether the subdomain does not exist yet, and would be created by the first record.
+func (d *DNSProvider) isNewSubdomain(ctx context.Context, authZone, subDomain string) (bool, error) {
+	subdomains, err := d.client.GetSubdomains(ctx, authZone)
+	if err != nil {
+		return false, err
+	}
+
+	return !slices.Contains(subdomains, subDomain), nil
+}
+
 func (d *DNSProvider) splitDomain(fqdn string) (string, string, error) {
 	authZone, err := d.findZoneByFqdn(fqdn)
 	if err != nil {
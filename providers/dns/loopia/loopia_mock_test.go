@@ -3,6 +3,8 @@ package loopia
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,6 +27,8 @@ func TestDNSProvider_Present(t *testing.T) {
 	testCases := []struct {
 		desc string
 
+		getSubdomainsError  error
+		getSubdomainsReturn []string
 		getTXTRecordsError  error
 		getTXTRecordsReturn []internal.RecordObj
 		addTXTRecordError   error
@@ -33,6 +37,7 @@ func TestDNSProvider_Present(t *testing.T) {
 
 		expectedError               string
 		expectedInProgressTokenInfo int
+		expectedCreatedSubdomain    bool
 	}{
 		{
 			desc: "Present OK",
@@ -42,6 +47,24 @@ func TestDNSProvider_Present(t *testing.T) {
 			callGetTXTRecords:   true,
 
 			expectedInProgressTokenInfo: 12345678,
+			expectedCreatedSubdomain:    true,
+		},
+		{
+			desc: "Present OK, existing subdomain",
+
+			getSubdomainsReturn: []string{"www", exampleSubDomain},
+			getTXTRecordsReturn: []internal.RecordObj{{Type: "TXT", Rdata: exampleRdata, RecordID: 12345678}},
+			callAddTXTRecord:    true,
+			callGetTXTRecords:   true,
+
+			expectedInProgressTokenInfo: 12345678,
+		},
+		{
+			desc: "GetSubdomains fails",
+
+			getSubdomainsError: errors.New("authentication error"),
+
+			expectedError: "loopia: failed to get subdomains: authentication error",
 		},
 		{
 			desc: "AddTXTRecord fails",
@@ -84,6 +107,8 @@ func TestDNSProvider_Present(t *testing.T) {
 			provider.findZoneByFqdn = mockedFindZoneByFqdn
 			provider.client = client
 
+			client.On("GetSubdomains", exampleDomain).Return(test.getSubdomainsReturn, test.getSubdomainsError)
+
 			if test.callAddTXTRecord {
 				client.On("AddTXTRecord", exampleDomain, exampleSubDomain, config.TTL, exampleRdata).Return(test.addTXTRecordError)
 			}
@@ -98,7 +123,11 @@ func TestDNSProvider_Present(t *testing.T) {
 
 			if test.expectedError == "" {
 				require.NoError(t, err)
-				assert.Equal(t, test.expectedInProgressTokenInfo, provider.inProgressInfo["token"])
+				recordID, ok := provider.inProgressInfo.Get("token", "_acme-challenge.example.com.")
+				require.True(t, ok)
+				assert.Equal(t, test.expectedInProgressTokenInfo, recordID)
+				_, created := provider.createdSubdomains.Get("", "_acme-challenge.example.com.")
+				assert.Equal(t, test.expectedCreatedSubdomain, created)
 			} else {
 				require.Error(t, err)
 				assert.EqualError(t, err, test.expectedError)
@@ -119,6 +148,8 @@ func TestDNSProvider_Cleanup(t *testing.T) {
 		getTXTRecordsReturn  []internal.RecordObj
 		removeTXTRecordError error
 		removeSubdomainError error
+		existingSubdomain    bool
+		keepSubdomains       bool
 		callAddTXTRecord     bool
 		callGetTXTRecords    bool
 		callRemoveSubdomain  bool
@@ -132,6 +163,22 @@ func TestDNSProvider_Cleanup(t *testing.T) {
 			callGetTXTRecords:   true,
 			callRemoveSubdomain: true,
 		},
+		{
+			desc: "Don't call removeSubdomain when the subdomain existed",
+
+			existingSubdomain:   true,
+			callAddTXTRecord:    true,
+			callGetTXTRecords:   true,
+			callRemoveSubdomain: false,
+		},
+		{
+			desc: "Don't call removeSubdomain when disabled",
+
+			keepSubdomains:      true,
+			callAddTXTRecord:    true,
+			callGetTXTRecords:   true,
+			callRemoveSubdomain: false,
+		},
 		{
 			desc: "removeTXTRecord failed",
 
@@ -175,6 +222,7 @@ func TestDNSProvider_Cleanup(t *testing.T) {
 			config := NewDefaultConfig()
 			config.APIUser = "apiuser"
 			config.APIPassword = "password"
+			config.RemoveSubdomainOnCleanup = !test.keepSubdomains
 
 			client := &mockedClient{}
 
@@ -183,7 +231,11 @@ func TestDNSProvider_Cleanup(t *testing.T) {
 
 			provider.findZoneByFqdn = mockedFindZoneByFqdn
 			provider.client = client
-			provider.inProgressInfo["token"] = 12345678
+			provider.inProgressInfo.Set("token", "_acme-challenge.example.com.", 12345678)
+
+			if !test.existingSubdomain {
+				provider.createdSubdomains.Set("", "_acme-challenge.example.com.", true)
+			}
 
 			if test.callAddTXTRecord {
 				client.On("RemoveTXTRecord", "example.com", "_acme-challenge", 12345678).Return(test.removeTXTRecordError)
@@ -211,6 +263,76 @@ func TestDNSProvider_Cleanup(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_CleanUp_sameTokenSeveralDomains(t *testing.T) {
+	config := NewDefaultConfig()
+	config.APIUser = "apiuser"
+	config.APIPassword = "password"
+
+	client := &mockedClient{}
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.findZoneByFqdn = func(fqdn string) (string, error) {
+		return strings.TrimPrefix(fqdn, "_acme-challenge."), nil
+	}
+	provider.client = client
+
+	for i, zone := range []string{"example.com", "example.org"} {
+		// the record is kept to skip the removal of the subdomain.
+		records := []internal.RecordObj{{Type: "TXT", Rdata: exampleRdata, RecordID: i + 1}}
+
+		client.On("GetSubdomains", zone).Return([]string{}, nil)
+		client.On("AddTXTRecord", zone, exampleSubDomain, config.TTL, exampleRdata).Return(nil)
+		client.On("GetTXTRecords", zone, exampleSubDomain).Return(records, nil)
+		client.On("RemoveTXTRecord", zone, exampleSubDomain, i+1).Return(nil)
+	}
+
+	require.NoError(t, provider.Present("example.com", "token", "key"))
+	require.NoError(t, provider.Present("example.org", "token", "key"))
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "key"))
+	require.NoError(t, provider.CleanUp("example.org", "token", "key"))
+
+	client.AssertExpectations(t)
+	assert.Equal(t, 0, provider.inProgressInfo.Len())
+}
+
+func TestDNSProvider_SetStateFile_createdSubdomain(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "loopia.json")
+
+	config := NewDefaultConfig()
+	config.APIUser = "apiuser"
+	config.APIPassword = "password"
+
+	client := &mockedClient{}
+
+	client.On("GetSubdomains", exampleDomain).Return([]string{}, nil)
+	client.On("AddTXTRecord", exampleDomain, exampleSubDomain, config.TTL, exampleRdata).Return(nil)
+	client.On("GetTXTRecords", exampleDomain, exampleSubDomain).Return([]internal.RecordObj{{Type: "TXT", Rdata: exampleRdata, RecordID: 1}}, nil).Once()
+	client.On("RemoveTXTRecord", exampleDomain, exampleSubDomain, 1).Return(nil)
+	client.On("GetTXTRecords", exampleDomain, exampleSubDomain).Return([]internal.RecordObj{}, nil).Once()
+	client.On("RemoveSubdomain", exampleDomain, exampleSubDomain).Return(nil)
+
+	// each provider has its own memory, as separate processes do.
+	newProvider := func() *DNSProvider {
+		provider, err := NewDNSProviderConfig(config)
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SetStateFile(statePath))
+
+		provider.findZoneByFqdn = func(string) (string, error) { return exampleDomain, nil }
+		provider.client = client
+
+		return provider
+	}
+
+	require.NoError(t, newProvider().Present(exampleDomain, "token", "key"))
+	require.NoError(t, newProvider().CleanUp(exampleDomain, "token", "key"))
+
+	client.AssertExpectations(t)
+}
+
 type mockedClient struct {
 	mock.Mock
 }
@@ -230,6 +352,11 @@ func (c *mockedClient) GetTXTRecords(ctx context.Context, domain string, subdoma
 	return args.Get(0).([]internal.RecordObj), args.Error(1)
 }
 
+func (c *mockedClient) GetSubdomains(ctx context.Context, domain string) ([]string, error) {
+	args := c.Called(domain)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (c *mockedClient) RemoveSubdomain(ctx context.Context, domain, subdomain string) error {
 	args := c.Called(domain, subdomain)
 	return args.Error(0)
@@ -158,5 +158,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("status=%d: %w", resp.StatusCode, errResp)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errResp)
 }
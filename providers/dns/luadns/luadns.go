@@ -5,15 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
 	"lego-toolbox/providers/dns/luadns/internal"
+	"lego-toolbox/rawrecord"
 )
 
 const minTTL = 300
@@ -79,8 +80,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordsMu sync.Mutex
-	records   map[string]*internal.DNSRecord
+	records *challengestore.Store[*internal.DNSRecord]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for LuaDNS.
@@ -102,7 +102,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -130,10 +130,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		config:    config,
-		client:    client,
-		recordsMu: sync.Mutex{},
-		records:   make(map[string]*internal.DNSRecord),
+		config:  config,
+		client:  client,
+		records: challengestore.New[*internal.DNSRecord](),
 	}, nil
 }
 
@@ -143,9 +142,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.records.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	ctx := context.Background()
 
@@ -176,20 +181,16 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("luadns: failed to create record: %w", err)
 	}
 
-	d.recordsMu.Lock()
-	d.records[token] = record
-	d.recordsMu.Unlock()
+	d.records.Set(token, info.EffectiveFQDN, record)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	d.recordsMu.Lock()
-	record, ok := d.records[token]
-	d.recordsMu.Unlock()
+	record, ok := d.records.Get(token, info.EffectiveFQDN)
 
 	if !ok {
 		return fmt.Errorf("luadns: unknown record ID for '%s'", info.EffectiveFQDN)
@@ -201,9 +202,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// Delete record from map
-	d.recordsMu.Lock()
-	delete(d.records, token)
-	d.recordsMu.Unlock()
+	d.records.Delete(token, info.EffectiveFQDN)
 
 	return nil
 }
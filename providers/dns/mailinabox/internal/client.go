@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"lego-toolbox/providers/dns/internal/errutils"
+)
+
+// Client the Mail-in-a-Box admin API client.
+type Client struct {
+	email      string
+	password   string
+	totpSecret string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+
+	// the session of the two-factor authentication.
+	muSession sync.Mutex
+	apiKey    string
+	lastTOTP  time.Time
+}
+
+// NewClient creates a new Client.
+// With a TOTP secret, the client logs in (two-factor authentication),
+// and uses the session key of the login for the subsequent requests.
+func NewClient(baseURL *url.URL, email, password, totpSecret string) *Client {
+	return &Client{
+		email:      email,
+		password:   password,
+		totpSecret: totpSecret,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AddRecord adds a custom DNS record.
+// https://mailinabox.email/api-docs.html#operation/addDnsCustomRecord
+func (c *Client) AddRecord(ctx context.Context, record Record) error {
+	return c.updateRecord(ctx, http.MethodPost, record)
+}
+
+// RemoveRecord removes a custom DNS record.
+// https://mailinabox.email/api-docs.html#operation/removeDnsCustomRecord
+func (c *Client) RemoveRecord(ctx context.Context, record Record) error {
+	return c.updateRecord(ctx, http.MethodDelete, record)
+}
+
+func (c *Client) updateRecord(ctx context.Context, method string, record Record) error {
+	if record.Name == "" || record.Type == "" {
+		return errors.New("qname and rtype are required")
+	}
+
+	endpoint := c.baseURL.JoinPath("admin", "dns", "custom", record.Name, record.Type)
+
+	return c.doAuthenticated(ctx, func() (*http.Request, error) {
+		return newTextRequest(ctx, method, endpoint, record.Value)
+	})
+}
+
+// doAuthenticated sends the request with the credentials,
+// or with the session key when the two-factor authentication is used:
+// an expired session is renewed once.
+func (c *Client) doAuthenticated(ctx context.Context, newRequest func() (*http.Request, error)) error {
+	if c.totpSecret == "" {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		req.SetBasicAuth(c.email, c.password)
+
+		return c.do(req, nil)
+	}
+
+	for attempt := 0; ; attempt++ {
+		apiKey, err := c.sessionKey(ctx)
+		if err != nil {
+			return err
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		req.SetBasicAuth(c.email, apiKey)
+
+		err = c.do(req, nil)
+		if attempt == 0 && isUnauthorized(err) {
+			c.dropSession(apiKey)
+			continue
+		}
+
+		return err
+	}
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newTextRequest(ctx context.Context, method string, endpoint *url.URL, body string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+
+	return req, nil
+}
+
+func isUnauthorized(err error) bool {
+	var statusErr *errutils.UnexpectedStatusCodeError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	return statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden
+}
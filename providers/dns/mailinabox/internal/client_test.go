@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret = "JBSWY3DPEHPK3PXP"
+	testAPIKey = "1234567890abcdef"
+)
+
+func setupTest(t *testing.T, totpSecret string) (*Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	baseURL, _ := url.Parse(server.URL)
+
+	client := NewClient(baseURL, "user@example.com", "secret", totpSecret)
+	client.HTTPClient = server.Client()
+
+	return client, mux
+}
+
+// authenticated checks the basic auth before calling the handler.
+func authenticated(password string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		email, pass, ok := req.BasicAuth()
+		if !ok || email != "user@example.com" || pass != password {
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		handler(rw, req)
+	}
+}
+
+func recordHandler(t *testing.T, password string, calls *int) http.HandlerFunc {
+	t.Helper()
+
+	return authenticated(password, func(rw http.ResponseWriter, req *http.Request) {
+		*calls++
+
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if string(raw) != "txtTXTtxt" {
+			http.Error(rw, "invalid value: "+string(raw), http.StatusBadRequest)
+			return
+		}
+
+		_, _ = rw.Write([]byte("updated DNS: example.com"))
+	})
+}
+
+func writeFixture(rw http.ResponseWriter, filename string) {
+	file, err := os.Open(filepath.Join("fixtures", filename))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() { _ = file.Close() }()
+
+	_, _ = io.Copy(rw, file)
+}
+
+func TestClient_AddRecord(t *testing.T) {
+	client, mux := setupTest(t, "")
+
+	var calls int
+	mux.HandleFunc("POST /admin/dns/custom/_acme-challenge.example.com/TXT", recordHandler(t, "secret", &calls))
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.AddRecord(context.Background(), record)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_RemoveRecord(t *testing.T) {
+	client, mux := setupTest(t, "")
+
+	var calls int
+	mux.HandleFunc("DELETE /admin/dns/custom/_acme-challenge.example.com/TXT", recordHandler(t, "secret", &calls))
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.RemoveRecord(context.Background(), record)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_AddRecord_error(t *testing.T) {
+	client, _ := setupTest(t, "")
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.AddRecord(context.Background(), record)
+	require.Error(t, err)
+}
+
+func TestClient_totp(t *testing.T) {
+	client, mux := setupTest(t, testSecret)
+
+	var logins int
+	mux.HandleFunc("POST /admin/login", authenticated("secret", func(rw http.ResponseWriter, req *http.Request) {
+		logins++
+
+		if req.Header.Get(totpHeader) == "" {
+			writeFixture(rw, "login_missing_totp.json")
+			return
+		}
+
+		writeFixture(rw, "login.json")
+	}))
+
+	var calls int
+	mux.HandleFunc("POST /admin/dns/custom/_acme-challenge.example.com/TXT", recordHandler(t, testAPIKey, &calls))
+	mux.HandleFunc("DELETE /admin/dns/custom/_acme-challenge.example.com/TXT", recordHandler(t, testAPIKey, &calls))
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.AddRecord(context.Background(), record)
+	require.NoError(t, err)
+
+	err = client.RemoveRecord(context.Background(), record)
+	require.NoError(t, err)
+
+	// the session is cached.
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_totp_expiredSession(t *testing.T) {
+	client, mux := setupTest(t, testSecret)
+
+	client.apiKey = "expired"
+
+	var logins int
+	mux.HandleFunc("POST /admin/login", authenticated("secret", func(rw http.ResponseWriter, req *http.Request) {
+		logins++
+
+		writeFixture(rw, "login.json")
+	}))
+
+	var calls int
+	mux.HandleFunc("POST /admin/dns/custom/_acme-challenge.example.com/TXT", recordHandler(t, testAPIKey, &calls))
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.AddRecord(context.Background(), record)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, testAPIKey, client.apiKey)
+}
+
+func TestClient_login_missingTOTP(t *testing.T) {
+	client, mux := setupTest(t, testSecret)
+
+	mux.HandleFunc("POST /admin/login", func(rw http.ResponseWriter, req *http.Request) {
+		writeFixture(rw, "login_missing_totp.json")
+	})
+
+	record := Record{Name: "_acme-challenge.example.com", Type: "TXT", Value: "txtTXTtxt"}
+
+	err := client.AddRecord(context.Background(), record)
+	require.EqualError(t, err, "login: missing-totp-token: Missing TOTP token.")
+}
+
+func TestClient_waitNextTOTPPeriod(t *testing.T) {
+	client, _ := setupTest(t, testSecret)
+
+	now := time.Now()
+
+	// no previous code.
+	require.NoError(t, client.waitNextTOTPPeriod(context.Background(), now))
+
+	// previous code of an old period.
+	client.lastTOTP = now.Add(-time.Minute).Truncate(totpPeriod)
+	require.NoError(t, client.waitNextTOTPPeriod(context.Background(), now))
+
+	// previous code of the current period.
+	client.lastTOTP = now.Truncate(totpPeriod)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, client.waitNextTOTPPeriod(ctx, now), context.Canceled)
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+// totpHeader is the header of the TOTP code of the login.
+const totpHeader = "X-Auth-Token"
+
+const totpPeriod = 30 * time.Second
+
+// sessionKey returns the key of the cached session, or logs in.
+func (c *Client) sessionKey(ctx context.Context) (string, error) {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	if c.apiKey != "" {
+		return c.apiKey, nil
+	}
+
+	apiKey, err := c.login(ctx)
+	if err != nil {
+		return "", fmt.Errorf("login: %w", err)
+	}
+
+	c.apiKey = apiKey
+
+	return apiKey, nil
+}
+
+// dropSession forgets the session of the key, e.g. after its expiration.
+func (c *Client) dropSession(apiKey string) {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	if c.apiKey == apiKey {
+		c.apiKey = ""
+	}
+}
+
+// login authenticates with the credentials and a TOTP code, and returns the session key.
+// https://mailinabox.email/api-docs.html#operation/login
+func (c *Client) login(ctx context.Context) (string, error) {
+	// Mail-in-a-Box forbids the reuse of a TOTP code,
+	// a new login waits until the next TOTP period.
+	err := c.waitNextTOTPPeriod(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	code, err := totp.GenerateCode(c.totpSecret, now)
+	if err != nil {
+		return "", fmt.Errorf("generate TOTP code: %w", err)
+	}
+
+	c.lastTOTP = now.Truncate(totpPeriod)
+
+	endpoint := c.baseURL.JoinPath("admin", "login")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.email, c.password)
+	req.Header.Set(totpHeader, code)
+
+	var result LoginResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if result.Status != "ok" || result.APIKey == "" {
+		return "", fmt.Errorf("%s: %s", result.Status, result.Reason)
+	}
+
+	return result.APIKey, nil
+}
+
+func (c *Client) waitNextTOTPPeriod(ctx context.Context, now time.Time) error {
+	if c.lastTOTP.IsZero() {
+		return nil
+	}
+
+	endPeriod := c.lastTOTP.Add(totpPeriod)
+	if !endPeriod.After(now) {
+		return nil
+	}
+
+	timer := time.NewTimer(endPeriod.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
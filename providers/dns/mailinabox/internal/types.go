@@ -0,0 +1,17 @@
+package internal
+
+// Record a custom DNS record.
+type Record struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// LoginResponse is the response of the login.
+type LoginResponse struct {
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Email      string   `json:"email,omitempty"`
+	Privileges []string `json:"privileges,omitempty"`
+	APIKey     string   `json:"api_key,omitempty"`
+}
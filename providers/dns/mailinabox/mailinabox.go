@@ -5,12 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
-	"github.com/nrdcg/mailinabox"
+	"lego-toolbox/providers/dns/internal/tlsutil"
+	"lego-toolbox/providers/dns/mailinabox/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -21,17 +25,33 @@ const (
 	EnvPassword = envNamespace + "PASSWORD"
 	EnvBaseURL  = envNamespace + "BASE_URL"
 
+	EnvTOTPSecret         = envNamespace + "TOTP_SECRET"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+	EnvCABundle           = envNamespace + "CA_BUNDLE"
+
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Email              string        `yaml:"email"`
-	Password           string        `yaml:"password"`
-	BaseURL            string        `yaml:"baseURL"`
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+	BaseURL  string `yaml:"baseURL"`
+
+	// TOTPSecret is the TOTP secret of the admin with the two-factor authentication:
+	// the provider logs in with a TOTP code, and uses the session of the login.
+	TOTPSecret string `yaml:"totpSecret"`
+
+	// InsecureSkipVerify accepts any server certificate, e.g. the self-signed certificate of the box.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	// CABundle is the PEM file of the CA verifying the server certificate, the system roots are used when empty.
+	CABundle string `yaml:"caBundle"`
+
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -39,6 +59,9 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
 	}
 }
 
@@ -47,6 +70,9 @@ func DefaultConfig() *Config {
 	return &Config{
 		PropagationTimeout: 120 * time.Second,
 		PollingInterval:    4 * time.Second,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
 	}
 }
 
@@ -55,6 +81,9 @@ func GetYamlTemple() string {
 email: "your_email@example.com"               # 电子邮件地址，用于身份验证或通知
 password: "your_password_here"                # 密码，用于身份验证
 baseURL: "https://api.example.com"            # 基础 URL，用于 API 请求
+totpSecret: ""                                # 管理员两步验证（TOTP）的密钥，设置后登录并复用会话
+insecureSkipVerify: false                     # 是否跳过服务器证书验证（自签名证书）
+caBundle: ""                                  # 校验服务器证书的 CA 文件（PEM），为空时使用系统根证书
 propagationTimeout: 120s                      # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 4s                           # 轮询间隔时间，表示系统定期检查更新的时间间隔`
 }
@@ -62,7 +91,7 @@ pollingInterval: 4s                           # 轮询间隔时间，表示系
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
-	client *mailinabox.Client
+	client *internal.Client
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Mail-in-a-Box.
@@ -78,6 +107,9 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config.BaseURL = values[EnvBaseURL]
 	config.Email = values[EnvEmail]
 	config.Password = values[EnvPassword]
+	config.TOTPSecret = env.GetOrFile(EnvTOTPSecret)
+	config.InsecureSkipVerify = env.GetOrDefaultBool(EnvInsecureSkipVerify, false)
+	config.CABundle = env.GetOrFile(EnvCABundle)
 
 	return NewDNSProviderConfig(config)
 }
@@ -85,14 +117,14 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
 	return config, nil
 }
 
-// NewDNSProviderConfig return a DNSProvider instance configured for deSEC.
+// NewDNSProviderConfig return a DNSProvider instance configured for Mail-in-a-Box.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
 		return nil, errors.New("mailinabox: the configuration of the DNS provider is nil")
@@ -106,9 +138,22 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("mailinabox: missing base URL")
 	}
 
-	client, err := mailinabox.New(config.BaseURL, config.Email, config.Password)
+	baseURL, err := url.Parse(config.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("mailinabox: %w", err)
+		return nil, fmt.Errorf("mailinabox: failed to parse base URL (%s): %w", config.BaseURL, err)
+	}
+
+	client := internal.NewClient(baseURL, config.Email, config.Password, config.TOTPSecret)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	if config.InsecureSkipVerify || config.CABundle != "" {
+		err = tlsutil.SetTLSConfig(client.HTTPClient, config.InsecureSkipVerify, config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("mailinabox: %w", err)
+		}
 	}
 
 	return &DNSProvider{config: config, client: client}, nil
@@ -123,15 +168,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	record := mailinabox.Record{
+	record := internal.Record{
 		Name:  dns01.UnFqdn(info.EffectiveFQDN),
 		Type:  "TXT",
 		Value: info.Value,
 	}
 
-	_, err := d.client.DNS.AddRecord(ctx, record)
+	err := d.client.AddRecord(ctx, record)
 	if err != nil {
 		return fmt.Errorf("mailinabox: add record: %w", err)
 	}
@@ -142,15 +187,15 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	record := mailinabox.Record{
+	record := internal.Record{
 		Name:  dns01.UnFqdn(info.EffectiveFQDN),
 		Type:  "TXT",
 		Value: info.Value,
 	}
 
-	_, err := d.client.DNS.RemoveRecord(ctx, record)
+	err := d.client.RemoveRecord(ctx, record)
 	if err != nil {
 		return fmt.Errorf("mailinabox: remove record: %w", err)
 	}
@@ -1,10 +1,13 @@
 package mailinabox
 
 import (
+	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -130,6 +133,41 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+baseURL: https://box.example.com
+email: user@example.com
+password: secret
+totpSecret: JBSWY3DPEHPK3PXP
+insecureSkipVerify: true
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", config.TOTPSecret)
+	assert.True(t, config.InsecureSkipVerify)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	transport, ok := p.client.HTTPClient.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestNewDNSProviderConfig_caBundle(t *testing.T) {
+	config := NewDefaultConfig()
+	config.BaseURL = "https://box.example.com"
+	config.Email = "user@example.com"
+	config.Password = "secret"
+	config.CABundle = filepath.Join(t.TempDir(), "missing.pem")
+
+	_, err := NewDNSProviderConfig(config)
+	require.ErrorContains(t, err, "mailinabox: read CA file:")
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -0,0 +1,293 @@
+// Package memdns implements a DNS provider backed by an embedded authoritative DNS server,
+// which answers TXT queries for the challenges it was asked to present.
+package memdns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/miekg/dns"
+	"golang.org/x/net/idna"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "MEMDNS_"
+
+	EnvListen = envNamespace + "LISTEN"
+	EnvZones  = envNamespace + "ZONES"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+const defaultListen = "127.0.0.1:0"
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// Listen is the UDP and TCP address of the embedded server.
+	Listen string `yaml:"listen"`
+	// Zones are the zones the server is authoritative for.
+	// When empty, the server answers SOA queries for every name not starting with an underscore.
+	Zones []string `yaml:"zones"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Listen:             env.GetOrDefaultString(EnvListen, defaultListen),
+		TTL:                env.GetOrDefaultInt(EnvTTL, 0),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 30*time.Second),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, time.Second),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		Listen:             defaultListen,
+		PropagationTimeout: 30 * time.Second,
+		PollingInterval:    time.Second,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+listen: "127.0.0.1:0"                 # 内置 DNS 服务器监听地址（UDP 和 TCP）
+zones:                                # 权威区域，为空时对所有非下划线开头的名称应答 SOA
+  - "example.com"
+propagationTimeout: 30s               # 传播超时时间
+pollingInterval: 1s                   # 轮询间隔时间
+ttl: 0                                # TXT 记录的 TTL`
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	udp *dns.Server
+	tcp *dns.Server
+
+	mu      sync.RWMutex
+	records map[string][]string
+}
+
+// NewDNSProvider returns a DNSProvider instance configured from the environment.
+func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	if zones := env.GetOrFile(EnvZones); zones != "" {
+		config.Zones = strings.Split(zones, ",")
+	}
+
+	return NewDNSProviderConfig(config)
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for memdns.
+// The embedded server is started immediately and stopped by Close.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("memdns: the configuration of the DNS provider is nil")
+	}
+
+	if config.Listen == "" {
+		config.Listen = defaultListen
+	}
+
+	d := &DNSProvider{
+		config:  config,
+		records: make(map[string][]string),
+	}
+
+	err := d.start()
+	if err != nil {
+		return nil, fmt.Errorf("memdns: %w", err)
+	}
+
+	return d, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	fqdn := normalize(info.EffectiveFQDN)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !slices.Contains(d.records[fqdn], info.Value) {
+		d.records[fqdn] = append(d.records[fqdn], info.Value)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+	fqdn := normalize(info.EffectiveFQDN)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	values := slices.DeleteFunc(d.records[fqdn], func(v string) bool { return v == info.Value })
+	if len(values) == 0 {
+		delete(d.records, fqdn)
+	} else {
+		d.records[fqdn] = values
+	}
+
+	return nil
+}
+
+// Addr returns the address the embedded server listens on, usable as a recursive nameserver.
+func (d *DNSProvider) Addr() string {
+	return d.udp.PacketConn.LocalAddr().String()
+}
+
+// Close stops the embedded server.
+func (d *DNSProvider) Close() error {
+	return errors.Join(d.udp.Shutdown(), d.tcp.Shutdown())
+}
+
+func (d *DNSProvider) start() error {
+	pc, err := net.ListenPacket("udp", d.config.Listen)
+	if err != nil {
+		return err
+	}
+
+	// The TCP listener reuses the port picked for UDP.
+	l, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		_ = pc.Close()
+		return err
+	}
+
+	handler := dns.HandlerFunc(d.serveDNS)
+
+	d.udp = &dns.Server{PacketConn: pc, Handler: handler}
+	d.tcp = &dns.Server{Listener: l, Handler: handler}
+
+	for _, server := range []*dns.Server{d.udp, d.tcp} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+
+		go func() { _ = server.ActivateAndServe() }()
+
+		<-started
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	for _, q := range req.Question {
+		name := strings.ToLower(q.Name)
+
+		switch q.Qtype {
+		case dns.TypeTXT:
+			d.mu.RLock()
+			for _, value := range d.records[name] {
+				m.Answer = append(m.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: uint32(d.config.TTL)},
+					Txt: []string{value},
+				})
+			}
+			d.mu.RUnlock()
+
+		case dns.TypeSOA:
+			if d.isZone(name) {
+				m.Answer = append(m.Answer, soa(q.Name))
+			}
+		}
+
+		if len(m.Answer) == 0 {
+			if zone := d.findZone(name); zone != "" {
+				m.Ns = append(m.Ns, soa(zone))
+			}
+		}
+	}
+
+	_ = w.WriteMsg(m)
+}
+
+func (d *DNSProvider) isZone(name string) bool {
+	if len(d.config.Zones) == 0 {
+		return !strings.HasPrefix(name, "_")
+	}
+
+	for _, zone := range d.config.Zones {
+		if strings.EqualFold(dns01.ToFqdn(zone), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *DNSProvider) findZone(name string) string {
+	for _, index := range dns.Split(name) {
+		candidate := name[index:]
+		if d.isZone(candidate) {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// normalize returns the lower-case ASCII form of the FQDN, as it appears in the queries.
+func normalize(fqdn string) string {
+	ascii, err := idna.ToASCII(dns01.UnFqdn(fqdn))
+	if err != nil {
+		return strings.ToLower(fqdn)
+	}
+
+	return strings.ToLower(dns01.ToFqdn(ascii))
+}
+
+func soa(zone string) *dns.SOA {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 0},
+		Ns:      "ns." + zone,
+		Mbox:    "hostmaster." + zone,
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  0,
+	}
+}
@@ -0,0 +1,94 @@
+package memdns
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProvider(t *testing.T, zones ...string) *DNSProvider {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.Zones = zones
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	return provider
+}
+
+func query(t *testing.T, network, addr, name string, qtype uint16) *dns.Msg {
+	t.Helper()
+
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+
+	client := &dns.Client{Net: network}
+
+	in, _, err := client.Exchange(m, addr)
+	require.NoError(t, err)
+
+	return in
+}
+
+func txtValues(msg *dns.Msg) []string {
+	var values []string
+	for _, rr := range msg.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, txt.Txt...)
+		}
+	}
+	return values
+}
+
+func TestDNSProvider_PresentCleanUp(t *testing.T) {
+	provider := setupProvider(t, "example.com")
+
+	infoA := dns01.GetChallengeInfo("example.com", "a")
+	infoB := dns01.GetChallengeInfo("example.com", "b")
+
+	require.NoError(t, provider.Present("example.com", "token", "a"))
+	require.NoError(t, provider.Present("example.com", "token", "b"))
+
+	for _, network := range []string{"udp", "tcp"} {
+		in := query(t, network, provider.Addr(), infoA.EffectiveFQDN, dns.TypeTXT)
+		assert.ElementsMatch(t, []string{infoA.Value, infoB.Value}, txtValues(in), network)
+		assert.True(t, in.Authoritative)
+	}
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "a"))
+
+	in := query(t, "udp", provider.Addr(), infoA.EffectiveFQDN, dns.TypeTXT)
+	assert.Equal(t, []string{infoB.Value}, txtValues(in))
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "b"))
+
+	in = query(t, "udp", provider.Addr(), infoA.EffectiveFQDN, dns.TypeTXT)
+	assert.Empty(t, in.Answer)
+	require.Len(t, in.Ns, 1)
+	assert.Equal(t, "example.com.", in.Ns[0].Header().Name)
+}
+
+func TestDNSProvider_FindZoneByFqdn(t *testing.T) {
+	provider := setupProvider(t, "example.com")
+
+	info := dns01.GetChallengeInfo("sub.example.com", "a")
+
+	zone, err := dns01.FindZoneByFqdnCustom(info.EffectiveFQDN, []string{provider.Addr()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", zone)
+}
+
+func TestDNSProvider_isZone_noZones(t *testing.T) {
+	provider := setupProvider(t)
+
+	assert.True(t, provider.isZone("example.com."))
+	assert.False(t, provider.isZone("_acme-challenge.example.com."))
+}
@@ -5,13 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"sync"
+	"lego-toolbox/yamlconfig"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nzdjb/go-metaname"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -67,8 +68,7 @@ type DNSProvider struct {
 	config *Config
 	client *metaname.MetanameClient
 
-	records   map[string]string
-	recordsMu sync.Mutex
+	records *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a new DNS provider
@@ -89,7 +89,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -112,12 +112,18 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:  config,
 		client:  metaname.NewMetanameClient(config.AccountReference, config.APIKey),
-		records: make(map[string]string),
+		records: challengestore.New[string](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.records.Persist(path)
+}
+
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -146,15 +152,13 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("metaname: add record: %w", err)
 	}
 
-	d.recordsMu.Lock()
-	d.records[token] = ref
-	d.recordsMu.Unlock()
+	d.records.Set(token, info.EffectiveFQDN, ref)
 
 	return nil
 }
 
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -165,10 +169,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	d.recordsMu.Lock()
-	ref, ok := d.records[token]
-	d.recordsMu.Unlock()
-
+	ref, ok := d.records.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("metaname: unknown ref for %s", info.EffectiveFQDN)
 	}
@@ -178,6 +179,8 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("metaname: delete record: %w", err)
 	}
 
+	d.records.Delete(token, info.EffectiveFQDN)
+
 	return nil
 }
 
@@ -5,13 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/credmap"
 	"lego-toolbox/providers/dns/mydnsjp/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -21,6 +25,9 @@ const (
 	EnvMasterID = envNamespace + "MASTER_ID"
 	EnvPassword = envNamespace + "PASSWORD"
 
+	EnvDelegateIDs       = envNamespace + "DELEGATE_IDS"
+	EnvDelegatePasswords = envNamespace + "DELEGATE_PASSWORDS"
+
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
@@ -28,13 +35,24 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	MasterID           string        `yaml:"masterID"`
-	Password           string        `yaml:"password"`
+	MasterID string `yaml:"masterID"`
+	Password string `yaml:"password"`
+	// Delegates are the credentials of the delegated subdomains (child IDs):
+	// a record uses the credentials of its closest delegated domain, or MasterID.
+	Delegates []Delegate `yaml:"delegates"`
+
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
+// Delegate is the ID of a subdomain delegated to another master ID (child ID).
+type Delegate struct {
+	Domain   string `yaml:"domain"`
+	MasterID string `yaml:"masterID"`
+	Password string `yaml:"password"`
+}
+
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
@@ -61,6 +79,10 @@ func GetYamlTemple() string {
 	return `# YAML 示例
 masterID: "your_master_id_here"                 # 主 ID，用于身份验证
 password: "your_password_here"                  # 密码，用于身份验证
+delegates:                                      # 委派子域名（子 ID）的凭据，记录使用最近的委派域名的凭据，否则使用 masterID
+  - domain: "sub.example.com"
+    masterID: "mydns654321"
+    password: "your_other_password"
 propagationTimeout: 120s                        # 传播超时时间，表示系统等待变化传播的最长时间
 pollingInterval: 2s                             # 轮询间隔时间，表示系统定期检查更新的时间间隔`
 }
@@ -68,12 +90,32 @@ pollingInterval: 2s                             # 轮询间隔时间，表示系
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// client uses MasterID, nil without it.
 	client *internal.Client
+	// clients use the IDs of the delegates, keyed by domain.
+	clients   map[string]*internal.Client
+	delegates credmap.Map
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for MyDNS.jp.
-// Credentials must be passed in the environment variables: MYDNSJP_MASTER_ID and MYDNSJP_PASSWORD.
+// Credentials must be passed in the environment variables: MYDNSJP_MASTER_ID and MYDNSJP_PASSWORD,
+// or MYDNSJP_DELEGATE_IDS and MYDNSJP_DELEGATE_PASSWORDS for the delegated subdomains.
 func NewDNSProvider() (*DNSProvider, error) {
+	if raw := env.GetOrFile(EnvDelegateIDs); raw != "" {
+		delegates, err := parseDelegates(raw, env.GetOrFile(EnvDelegatePasswords))
+		if err != nil {
+			return nil, fmt.Errorf("mydnsjp: %w", err)
+		}
+
+		config := NewDefaultConfig()
+		config.Delegates = delegates
+		config.MasterID = env.GetOrFile(EnvMasterID)
+		config.Password = env.GetOrFile(EnvPassword)
+
+		return NewDNSProviderConfig(config)
+	}
+
 	values, err := env.Get(EnvMasterID, EnvPassword)
 	if err != nil {
 		return nil, fmt.Errorf("mydnsjp: %w", err)
@@ -89,7 +131,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -102,14 +144,62 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("mydnsjp: the configuration of the DNS provider is nil")
 	}
 
-	if config.MasterID == "" || config.Password == "" {
+	if (config.MasterID == "" || config.Password == "") && len(config.Delegates) == 0 {
 		return nil, errors.New("mydnsjp: some credentials information are missing")
 	}
 
-	return &DNSProvider{
-		config: config,
-		client: internal.NewClient(config.MasterID, config.Password),
-	}, nil
+	ids := make(map[string]string, len(config.Delegates))
+	passwords := make(map[string]string, len(config.Delegates))
+
+	for _, delegate := range config.Delegates {
+		ids[delegate.Domain] = normalizeMasterID(delegate.MasterID)
+		passwords[delegate.Domain] = delegate.Password
+	}
+
+	delegates, err := credmap.Validate(ids)
+	if err != nil {
+		return nil, fmt.Errorf("mydnsjp: delegate IDs: %w", err)
+	}
+
+	delegatePasswords, err := credmap.Validate(passwords)
+	if err != nil {
+		return nil, fmt.Errorf("mydnsjp: delegate passwords: %w", err)
+	}
+
+	provider := &DNSProvider{config: config, clients: make(map[string]*internal.Client), delegates: delegates}
+
+	if config.MasterID != "" && config.Password != "" {
+		provider.client = newClient(config.HTTPClient, normalizeMasterID(config.MasterID), config.Password)
+	}
+
+	for domain, masterID := range delegates {
+		provider.clients[domain] = newClient(config.HTTPClient, masterID, delegatePasswords[domain])
+	}
+
+	return provider, nil
+}
+
+func newClient(hc *http.Client, masterID, password string) *internal.Client {
+	client := internal.NewClient(masterID, password)
+
+	if hc != nil {
+		client.HTTPClient = hc
+	}
+
+	return client
+}
+
+// clientFor returns the client using the ID of the closest delegated domain of the record, or MasterID.
+func (d *DNSProvider) clientFor(fqdn string) (*internal.Client, error) {
+	if _, domain, ok := d.delegates.Match(fqdn); ok {
+		return d.clients[domain], nil
+	}
+
+	if d.client == nil {
+		return nil, fmt.Errorf("no master ID for %s, check your delegates", dns01.UnFqdn(fqdn))
+	}
+
+	return d.client, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -120,10 +210,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("mydnsjp: %w", err)
+	}
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
-	err := d.client.AddTXTRecord(context.Background(), domain, info.Value)
+	err = client.AddTXTRecord(context.Background(), domain, info.Value)
 	if err != nil {
 		return fmt.Errorf("mydnsjp: %w", err)
 	}
@@ -132,12 +227,67 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	client, err := d.clientFor(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("mydnsjp: %w", err)
+	}
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
-	err := d.client.DeleteTXTRecord(context.Background(), domain, info.Value)
+	err = client.DeleteTXTRecord(context.Background(), domain, info.Value)
 	if err != nil {
 		return fmt.Errorf("mydnsjp: %w", err)
 	}
 	return nil
 }
+
+// normalizeMasterID accepts the master ID with or without its prefix (mydns123456 or 123456).
+func normalizeMasterID(masterID string) string {
+	masterID = strings.TrimSpace(masterID)
+
+	if masterID != "" && strings.Trim(masterID, "0123456789") == "" {
+		return "mydns" + masterID
+	}
+
+	if len(masterID) > len("mydns") && strings.EqualFold(masterID[:len("mydns")], "mydns") {
+		return "mydns" + masterID[len("mydns"):]
+	}
+
+	return masterID
+}
+
+// parseDelegates parses the comma-separated lists of `domain:ID` and `domain:password` pairs.
+func parseDelegates(rawIDs, rawPasswords string) ([]Delegate, error) {
+	ids, err := credmap.Parse(rawIDs)
+	if err != nil {
+		return nil, fmt.Errorf("delegate IDs: %w", err)
+	}
+
+	passwords, err := credmap.Parse(rawPasswords)
+	if err != nil {
+		return nil, fmt.Errorf("delegate passwords: %w", err)
+	}
+
+	domains := make([]string, 0, len(ids))
+	for domain := range ids {
+		domains = append(domains, domain)
+	}
+
+	slices.Sort(domains)
+
+	var delegates []Delegate
+
+	for _, domain := range domains {
+		masterID := ids[domain]
+
+		password, ok := passwords[domain]
+		if !ok {
+			return nil, fmt.Errorf("missing password for the delegated domain %q", domain)
+		}
+
+		delegates = append(delegates, Delegate{Domain: domain, MasterID: masterID, Password: password})
+	}
+
+	return delegates, nil
+}
@@ -7,11 +7,12 @@ import (
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/credmap"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvMasterID, EnvPassword).
+var envTest = tester.NewEnvTest(EnvMasterID, EnvPassword, EnvDelegateIDs, EnvDelegatePasswords).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -27,6 +28,21 @@ func TestNewDNSProvider(t *testing.T) {
 				EnvPassword: "123",
 			},
 		},
+		{
+			desc: "delegates",
+			envVars: map[string]string{
+				EnvDelegateIDs:       "sub.example.com:mydns654321",
+				EnvDelegatePasswords: "sub.example.com:secret",
+			},
+		},
+		{
+			desc: "missing delegate password",
+			envVars: map[string]string{
+				EnvDelegateIDs:       "sub.example.com:mydns654321,other.example.com:123",
+				EnvDelegatePasswords: "sub.example.com:secret",
+			},
+			expected: `mydnsjp: missing password for the delegated domain "other.example.com"`,
+		},
 		{
 			desc: "missing credentials",
 			envVars: map[string]string{
@@ -118,6 +134,84 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+masterID: "123456"
+password: secret
+delegates:
+  - domain: Sub.Example.com.
+    masterID: mydns654321
+    password: other
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	expected := []Delegate{{Domain: "Sub.Example.com.", MasterID: "mydns654321", Password: "other"}}
+	assert.Equal(t, expected, config.Delegates)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, credmap.Map{"sub.example.com": "mydns654321"}, p.delegates)
+	assert.NotNil(t, p.client)
+	assert.Len(t, p.clients, 1)
+}
+
+func TestDNSProvider_clientFor(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Delegates = []Delegate{{Domain: "sub.example.com", MasterID: "654321", Password: "other"}}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err := p.clientFor("_acme-challenge.www.sub.example.com.")
+	require.NoError(t, err)
+
+	assert.Same(t, p.clients["sub.example.com"], client)
+
+	_, err = p.clientFor("_acme-challenge.example.com.")
+	require.EqualError(t, err, "no master ID for _acme-challenge.example.com, check your delegates")
+
+	config.MasterID = "mydns123456"
+	config.Password = "secret"
+
+	p, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err = p.clientFor("_acme-challenge.example.com.")
+	require.NoError(t, err)
+
+	assert.Same(t, p.client, client)
+}
+
+func TestNewDNSProviderConfig_delegates_error(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Delegates = []Delegate{{Domain: "sub.example.com", MasterID: "654321"}}
+
+	_, err := NewDNSProviderConfig(config)
+	require.EqualError(t, err, `mydnsjp: delegate passwords: missing token for "sub.example.com"`)
+}
+
+func Test_normalizeMasterID(t *testing.T) {
+	testCases := []struct {
+		masterID string
+		expected string
+	}{
+		{masterID: "mydns123456", expected: "mydns123456"},
+		{masterID: " MyDNS123456 ", expected: "mydns123456"},
+		{masterID: "123456", expected: "mydns123456"},
+		{masterID: "test@example.com", expected: "test@example.com"},
+		{masterID: "", expected: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.masterID, func(t *testing.T) {
+			assert.Equal(t, test.expected, normalizeMasterID(test.masterID))
+		})
+	}
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
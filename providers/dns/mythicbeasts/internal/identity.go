@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 type token string
@@ -58,6 +60,8 @@ func (c *Client) obtainToken(ctx context.Context) (*Token, error) {
 	return &tok, nil
 }
 
+// CreateAuthenticatedContext returns a context holding a valid token:
+// the token of the client, the token cached by a previous run (see tokencache.Default), or a new one.
 func (c *Client) CreateAuthenticatedContext(ctx context.Context) (context.Context, error) {
 	c.muToken.Lock()
 	defer c.muToken.Unlock()
@@ -67,11 +71,28 @@ func (c *Client) CreateAuthenticatedContext(ctx context.Context) (context.Contex
 		return context.WithValue(ctx, tokenKey, c.token), nil
 	}
 
+	key := tokencache.Key("mythicbeasts", c.AuthEndpoint.String(), c.username, c.password)
+
+	// the token of a previous run.
+	cached := &Token{}
+	if deadline, ok := c.TokenCache.Get(key, cached); ok {
+		cached.Deadline = deadline
+		c.token = cached
+
+		return context.WithValue(ctx, tokenKey, cached), nil
+	}
+
 	tok, err := c.obtainToken(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	c.token = tok
+
+	if err := c.TokenCache.Set(key, tok, tok.Deadline); err != nil {
+		log.Warnf("mythicbeasts: %v", err)
+	}
+
 	return context.WithValue(ctx, tokenKey, tok), nil
 }
 
@@ -88,7 +109,7 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("%d: %w", resp.StatusCode, errResp)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errResp)
 }
 
 func getToken(ctx context.Context) *Token {
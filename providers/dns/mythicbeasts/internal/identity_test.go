@@ -1,16 +1,19 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 func mockContext() context.Context {
@@ -79,3 +82,48 @@ func TestClient_CreateAuthenticatedContext(t *testing.T) {
 	assert.NotZero(t, tok.Deadline)
 	assert.Equal(t, "xxx", tok.Token)
 }
+
+func TestClient_CreateAuthenticatedContext_tokenCache(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	var calls int
+
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		tokenHandler(rw, req)
+	})
+
+	cache, err := tokencache.New(filepath.Join(t.TempDir(), "tokens"), bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	newClient := func() *Client {
+		client := NewClient("user", "secret")
+		client.HTTPClient = server.Client()
+		client.AuthEndpoint, _ = url.Parse(server.URL)
+		client.TokenCache = cache
+
+		return client
+	}
+
+	client := newClient()
+
+	_, err = client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	_, err = client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	// the next run.
+	ctx, err := newClient().CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+
+	tok := getToken(ctx)
+	require.NotNil(t, tok)
+	assert.Equal(t, "xxx", tok.Token)
+	assert.NotZero(t, tok.Deadline)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"golang.org/x/net/publicsuffix"
 	"lego-toolbox/providers/dns/namecheap/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Notes about namecheap's tool API:
@@ -74,7 +75,7 @@ func newChallenge(domain, keyAuth string) (*challenge, error) {
 		host = strings.Join(parts[:longest-1], ".")
 	}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	return &challenge{
 		domain:   domain,
@@ -10,6 +10,8 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/namedotcom/go/namecom"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 // according to https://www.name.com/api-docs/DNS#CreateRecord
@@ -31,13 +33,13 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Username           string
-	APIToken           string
-	Server             string
-	TTL                int
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	HTTPClient         *http.Client
+	Username           string        `yaml:"username"`
+	APIToken           string        `yaml:"apiToken"`
+	Server             string        `yaml:"server"`
+	TTL                int           `yaml:"ttl"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -52,6 +54,38 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                minTTL,
+		PropagationTimeout: 15 * time.Minute,
+		PollingInterval:    20 * time.Second,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+username: "your_username"             # Name.com 用户名
+apiToken: "your_api_token"            # Name.com API Token
+server: ""                            # API 地址，留空使用默认地址（测试环境：api.dev.name.com）
+ttl: 300                              # TXT 记录的 TTL，最小值为 300
+propagationTimeout: 15m               # 传播超时时间
+pollingInterval: 20s                  # 轮询间隔时间`
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	client *namecom.NameCom
@@ -105,7 +139,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	domainDetails, err := d.client.GetDomain(&namecom.GetDomainRequest{DomainName: domain})
@@ -137,7 +171,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	records, err := d.getRecords(domain)
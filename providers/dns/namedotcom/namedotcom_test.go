@@ -125,6 +125,17 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("username: user\napiToken: secret\nttl: 600\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "user", config.Username)
+	require.Equal(t, "secret", config.APIToken)
+	require.Equal(t, 600, config.TTL)
+	require.Equal(t, 15*time.Minute, config.PropagationTimeout)
+	require.NotNil(t, config.HTTPClient)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
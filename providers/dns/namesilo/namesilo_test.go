@@ -106,6 +106,16 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("apiKey: secret\npropagationTimeout: 30m\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "secret", config.APIKey)
+	require.Equal(t, 30*time.Minute, config.PropagationTimeout)
+	require.Equal(t, defaultPollingInterval, config.PollingInterval)
+	require.Equal(t, defaultTTL, config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
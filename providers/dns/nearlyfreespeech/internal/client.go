@@ -102,7 +102,7 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errAPI
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errAPI)
 }
 
 type Signer struct {
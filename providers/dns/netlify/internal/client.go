@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"time"
 
-	"golang.org/x/oauth2"
 	"lego-toolbox/providers/dns/internal/errutils"
 )
 
@@ -147,16 +146,3 @@ func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, paylo
 
 	return req, nil
 }
-
-func OAuthStaticAccessToken(client *http.Client, accessToken string) *http.Client {
-	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
-	}
-
-	client.Transport = &oauth2.Transport{
-		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
-		Base:   client.Transport,
-	}
-
-	return client
-}
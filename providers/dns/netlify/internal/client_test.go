@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/oauthutil"
 )
 
 func setupTest(t *testing.T, token string) (*Client, *http.ServeMux) {
@@ -21,7 +22,7 @@ func setupTest(t *testing.T, token string) (*Client, *http.ServeMux) {
 	server := httptest.NewServer(mux)
 	t.Cleanup(server.Close)
 
-	client := NewClient(OAuthStaticAccessToken(server.Client(), token))
+	client := NewClient(oauthutil.StaticAccessToken(server.Client(), token))
 	client.baseURL, _ = url.Parse(server.URL)
 
 	return client, mux
@@ -180,5 +180,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errResp.Error
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errResp.Error).WithCode(errResp.Error.Code)
 }
@@ -72,7 +72,7 @@ func TestChangeResourceRecordSetsErrors(t *testing.T) {
 </ErrorResponse>
 `,
 			statusCode: http.StatusUnauthorized,
-			expected:   "Sender(AuthFailed): The request signature we calculated does not match the signature you provided.",
+			expected:   "[status code: 401] Sender(AuthFailed): The request signature we calculated does not match the signature you provided.",
 		},
 		{
 			desc:         "response body error",
@@ -139,7 +139,7 @@ func TestGetChangeErrors(t *testing.T) {
 </ErrorResponse>
 `,
 			statusCode: http.StatusUnauthorized,
-			expected:   "Sender(AuthFailed): The request signature we calculated does not match the signature you provided.",
+			expected:   "[status code: 401] Sender(AuthFailed): The request signature we calculated does not match the signature you provided.",
 		},
 		{
 			desc:         "response body error",
@@ -5,14 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
-	"github.com/go-acme/lego/v4/platform/wait"
+	"lego-toolbox/internal/jobs"
 	"lego-toolbox/providers/dns/nifcloud/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -22,6 +25,7 @@ const (
 	EnvAccessKeyID     = envNamespace + "ACCESS_KEY_ID"
 	EnvSecretAccessKey = envNamespace + "SECRET_ACCESS_KEY"
 	EnvDNSEndpoint     = envNamespace + "DNS_ENDPOINT"
+	EnvZoneName        = envNamespace + "ZONE_NAME"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -31,13 +35,16 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	BaseURL            string
-	AccessKey          string
-	SecretKey          string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	// BaseURL is the DNS API endpoint, https://dns.api.nifcloud.com when empty.
+	BaseURL   string `yaml:"endpoint"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+	// ZoneName is the hosted zone of the records, found with a SOA lookup when empty.
+	ZoneName           string        `yaml:"zoneName"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -52,6 +59,29 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+accessKey: "your_access_key"               # 访问密钥 ID
+secretKey: "your_secret_key"               # 秘密访问密钥
+endpoint: "https://dns.api.nifcloud.com"   # DNS API 端点，为空时使用默认端点
+zoneName: "example.com"                    # 托管区域名称，为空时通过 SOA 查询获取
+propagationTimeout: 60s                    # 传播超时时间
+pollingInterval: 2s                        # 轮询间隔时间
+ttl: 120                                   # TXT 记录的生存时间（秒）`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	client *internal.Client
@@ -69,12 +99,23 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 	config := NewDefaultConfig()
 	config.BaseURL = env.GetOrFile(EnvDNSEndpoint)
+	config.ZoneName = env.GetOrFile(EnvZoneName)
 	config.AccessKey = values[EnvAccessKeyID]
 	config.SecretKey = values[EnvSecretAccessKey]
 
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for NIFCLOUD.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -104,9 +145,15 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record using the specified parameters.
+// The wait for the change to be INSYNC is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	err := d.changeRecord("CREATE", info.EffectiveFQDN, info.Value, d.config.TTL)
+	err := d.changeRecord(ctx, "CREATE", info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("nifcloud: %w", err)
 	}
@@ -115,9 +162,15 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
-	err := d.changeRecord("DELETE", info.EffectiveFQDN, info.Value, d.config.TTL)
+// CleanUpContext removes the TXT record matching the specified parameters.
+// The wait for the change to be INSYNC is canceled with ctx.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.changeRecord(ctx, "DELETE", info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("nifcloud: %w", err)
 	}
@@ -130,10 +183,10 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
-func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
-	authZone, err := dns01.FindZoneByFqdn(fqdn)
+func (d *DNSProvider) changeRecord(ctx context.Context, action, fqdn, value string, ttl int) error {
+	authZone, err := d.findZone(fqdn)
 	if err != nil {
-		return fmt.Errorf("could not find zone: %w", err)
+		return err
 	}
 
 	name := dns01.UnFqdn(fqdn)
@@ -167,8 +220,6 @@ func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 		},
 	}
 
-	ctx := context.Background()
-
 	resp, err := d.client.ChangeResourceRecordSets(ctx, dns01.UnFqdn(authZone), reqParams)
 	if err != nil {
 		return fmt.Errorf("failed to change record set: %w", err)
@@ -176,11 +227,35 @@ func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 
 	statusID := resp.ChangeInfo.ID
 
-	return wait.For("nifcloud", 120*time.Second, 4*time.Second, func() (bool, error) {
+	opts := jobs.DefaultOptions("nifcloud: change "+statusID, 120*time.Second, 4*time.Second)
+
+	_, err = jobs.Poll(ctx, opts, func(ctx context.Context) (jobs.Status, error) {
 		resp, err := d.client.GetChange(ctx, statusID)
 		if err != nil {
-			return false, fmt.Errorf("failed to query change status: %w", err)
+			return jobs.Status{}, fmt.Errorf("failed to query change status: %w", err)
 		}
-		return resp.ChangeInfo.Status == "INSYNC", nil
+
+		return jobs.Status{Done: resp.ChangeInfo.Status == "INSYNC", State: resp.ChangeInfo.Status}, nil
 	})
+
+	return err
+}
+
+// findZone returns the configured zone, or the zone found with a SOA lookup.
+func (d *DNSProvider) findZone(fqdn string) (string, error) {
+	if d.config.ZoneName == "" {
+		authZone, err := dns01.FindZoneByFqdn(fqdn)
+		if err != nil {
+			return "", fmt.Errorf("could not find zone: %w", err)
+		}
+
+		return authZone, nil
+	}
+
+	authZone := dns01.ToFqdn(d.config.ZoneName)
+	if fqdn != authZone && !strings.HasSuffix(fqdn, "."+authZone) {
+		return "", fmt.Errorf("%s is not in the zone %s", fqdn, authZone)
+	}
+
+	return authZone, nil
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +13,9 @@ const envDomain = envNamespace + "DOMAIN"
 
 var envTest = tester.NewEnvTest(
 	EnvAccessKeyID,
-	EnvSecretAccessKey).
+	EnvSecretAccessKey,
+	EnvDNSEndpoint,
+	EnvZoneName).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -150,3 +153,47 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+accessKey: key
+secretKey: secret
+endpoint: https://dns.api.example.com
+zoneName: example.com
+ttl: 300
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "key", config.AccessKey)
+	assert.Equal(t, "secret", config.SecretKey)
+	assert.Equal(t, "https://dns.api.example.com", config.BaseURL)
+	assert.Equal(t, "example.com", config.ZoneName)
+	assert.Equal(t, 300, config.TTL)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://dns.api.example.com", p.client.BaseURL.String())
+}
+
+func TestDNSProvider_findZone(t *testing.T) {
+	config := DefaultConfig()
+	config.ZoneName = "example.com"
+
+	p := &DNSProvider{config: config}
+
+	authZone, err := p.findZone("_acme-challenge.www.example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", authZone)
+
+	authZone, err = p.findZone("example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", authZone)
+
+	_, err = p.findZone("_acme-challenge.example.org.")
+	require.EqualError(t, err, "_acme-challenge.example.org. is not in the zone example.com.")
+}
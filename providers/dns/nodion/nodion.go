@@ -6,12 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/nrdcg/nodion"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -53,8 +54,7 @@ type DNSProvider struct {
 	config *Config
 	client *nodion.Client
 
-	zoneIDs   map[string]string
-	zoneIDsMu sync.Mutex
+	zoneIDs *challengestore.Store[string]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Nodion.
@@ -93,7 +93,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:  config,
 		client:  client,
-		zoneIDs: map[string]string{},
+		zoneIDs: challengestore.New[string](),
 	}, nil
 }
 
@@ -103,9 +103,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.zoneIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -147,25 +153,21 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 			dns01.UnFqdn(authZone), subDomain, err)
 	}
 
-	d.zoneIDsMu.Lock()
-	d.zoneIDs[token] = zoneID
-	d.zoneIDsMu.Unlock()
+	d.zoneIDs.Set(token, info.EffectiveFQDN, zoneID)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("nodion: could not find zone for domain %q: %w", domain, err)
 	}
 
-	d.zoneIDsMu.Lock()
-	zoneID, ok := d.zoneIDs[token]
-	d.zoneIDsMu.Unlock()
+	zoneID, ok := d.zoneIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("nodion: unknown zone ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -201,5 +203,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("regru: failed to remove TXT records [domain: %s]: %w", dns01.UnFqdn(authZone), err)
 	}
 
+	d.zoneIDs.Delete(token, info.EffectiveFQDN)
+
 	return nil
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"gopkg.in/ns1/ns1-go.v2/rest"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 // Environment variables names.
@@ -28,11 +30,13 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	APIKey string `yaml:"apiKey"`
+	// Endpoint overrides the API endpoint (private NS1 deployments).
+	Endpoint           string        `yaml:"endpoint"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -47,6 +51,37 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+apiKey: "your_api_key"                # NS1 API Key
+endpoint: ""                          # API 地址，留空使用默认地址（私有部署时填写）
+ttl: 120                              # TXT 记录的 TTL
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间`
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	client *rest.Client
@@ -77,46 +112,49 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("ns1: credentials missing")
 	}
 
-	client := rest.NewClient(config.HTTPClient, rest.SetAPIKey(config.APIKey))
+	options := []func(*rest.Client){rest.SetAPIKey(config.APIKey)}
+	if config.Endpoint != "" {
+		options = append(options, rest.SetEndpoint(config.Endpoint))
+	}
+
+	client := rest.NewClient(config.HTTPClient, options...)
 
 	return &DNSProvider{client: client, config: config}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
+// The value is added as an answer to the TXT RRset when it already exists.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("ns1: %w", err)
 	}
 
-	record, _, err := d.client.Records.Get(zone.Zone, dns01.UnFqdn(info.EffectiveFQDN), "TXT")
-
-	// Create a new record
-	if errors.Is(err, rest.ErrRecordMissing) || record == nil {
-		log.Infof("Create a new record for [zone: %s, fqdn: %s, domain: %s]", zone.Zone, info.EffectiveFQDN, domain)
+	name := dns01.UnFqdn(info.EffectiveFQDN)
 
-		// Work through a bug in the NS1 API library that causes 400 Input validation failed (Value None for field '<obj>.filters' is not of type ...)
-		// So the `tags` and `blockedTags` parameters should be initialized to empty.
-		record = dns.NewRecord(zone.Zone, dns01.UnFqdn(info.EffectiveFQDN), "TXT", make(map[string]string), make([]string, 0))
-		record.TTL = d.config.TTL
-		record.Answers = []*dns.Answer{{Rdata: []string{info.Value}}}
+	record, _, err := d.client.Records.Get(zone.Zone, name, "TXT")
+	switch {
+	case errors.Is(err, rest.ErrRecordMissing):
+		err = d.createRecord(zone.Zone, name, info.Value)
+		if !errors.Is(err, rest.ErrRecordExists) {
+			return err
+		}
 
-		_, err = d.client.Records.Create(record)
+		// The RRset has been created concurrently (e.g. wildcard and apex of the same domain).
+		record, _, err = d.client.Records.Get(zone.Zone, name, "TXT")
 		if err != nil {
-			return fmt.Errorf("ns1: failed to create record [zone: %q, fqdn: %q]: %w", zone.Zone, info.EffectiveFQDN, err)
+			return fmt.Errorf("ns1: failed to get the existing record: %w", err)
 		}
 
-		return nil
-	}
-
-	if err != nil {
+	case err != nil:
 		return fmt.Errorf("ns1: failed to get the existing record: %w", err)
 	}
 
-	// Update the existing records
-	record.Answers = append(record.Answers, &dns.Answer{Rdata: []string{info.Value}})
+	if !addAnswer(record, info.Value) {
+		return nil
+	}
 
 	log.Infof("Update an existing record for [zone: %s, fqdn: %s, domain: %s]", zone.Zone, info.EffectiveFQDN, domain)
 
@@ -128,9 +166,31 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	return nil
 }
 
+func (d *DNSProvider) createRecord(zone, name, value string) error {
+	log.Infof("Create a new record for [zone: %s, name: %s]", zone, name)
+
+	// Work through a bug in the NS1 API library that causes 400 Input validation failed (Value None for field '<obj>.filters' is not of type ...)
+	// So the `tags` and `blockedTags` parameters should be initialized to empty.
+	record := dns.NewRecord(zone, name, "TXT", make(map[string]string), make([]string, 0))
+	record.TTL = d.config.TTL
+	record.Answers = []*dns.Answer{{Rdata: []string{value}}}
+
+	_, err := d.client.Records.Create(record)
+	if errors.Is(err, rest.ErrRecordExists) {
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("ns1: failed to create record [zone: %q, name: %q]: %w", zone, name, err)
+	}
+
+	return nil
+}
+
 // CleanUp removes the TXT record matching the specified parameters.
+// Only the matching answer is removed, the RRset is deleted when it has no answer left.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -138,10 +198,34 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	name := dns01.UnFqdn(info.EffectiveFQDN)
+
+	record, _, err := d.client.Records.Get(zone.Zone, name, "TXT")
+	if errors.Is(err, rest.ErrRecordMissing) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("ns1: failed to get the existing record: %w", err)
+	}
+
+	if !removeAnswer(record, info.Value) {
+		return nil
+	}
+
+	if len(record.Answers) > 0 {
+		_, err = d.client.Records.Update(record)
+		if err != nil {
+			return fmt.Errorf("ns1: failed to update record [zone: %q, domain: %q]: %w", zone.Zone, name, err)
+		}
+
+		return nil
+	}
+
 	_, err = d.client.Records.Delete(zone.Zone, name, "TXT")
 	if err != nil {
 		return fmt.Errorf("ns1: failed to delete record [zone: %q, domain: %q]: %w", zone.Zone, name, err)
 	}
+
 	return nil
 }
 
@@ -166,3 +250,39 @@ func (d *DNSProvider) getHostedZone(fqdn string) (*dns.Zone, error) {
 
 	return zone, nil
 }
+
+// addAnswer adds the value as an answer of the record, it returns false if the answer already exists.
+func addAnswer(record *dns.Record, value string) bool {
+	for _, answer := range record.Answers {
+		if answerHasValue(answer, value) {
+			return false
+		}
+	}
+
+	record.Answers = append(record.Answers, &dns.Answer{Rdata: []string{value}})
+
+	return true
+}
+
+// removeAnswer removes the answers matching the value, it returns false if there is no matching answer.
+func removeAnswer(record *dns.Record, value string) bool {
+	answers := make([]*dns.Answer, 0, len(record.Answers))
+
+	for _, answer := range record.Answers {
+		if !answerHasValue(answer, value) {
+			answers = append(answers, answer)
+		}
+	}
+
+	if len(answers) == len(record.Answers) {
+		return false
+	}
+
+	record.Answers = answers
+
+	return true
+}
+
+func answerHasValue(answer *dns.Answer, value string) bool {
+	return answer != nil && len(answer.Rdata) == 1 && answer.Rdata[0] == value
+}
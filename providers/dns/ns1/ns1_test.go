@@ -4,8 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -90,6 +94,43 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("apiKey: secret\nendpoint: https://ns1.example.com/v1/\nttl: 300\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", config.APIKey)
+	assert.Equal(t, "https://ns1.example.com/v1/", config.Endpoint)
+	assert.Equal(t, 300, config.TTL)
+	assert.NotNil(t, config.HTTPClient)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://ns1.example.com/v1/", p.client.Endpoint.String())
+}
+
+func Test_addAnswer(t *testing.T) {
+	record := &dns.Record{Answers: []*dns.Answer{{Rdata: []string{"wildcard"}}}}
+
+	assert.True(t, addAnswer(record, "apex"))
+	assert.False(t, addAnswer(record, "apex"))
+
+	assert.Equal(t, []*dns.Answer{{Rdata: []string{"wildcard"}}, {Rdata: []string{"apex"}}}, record.Answers)
+}
+
+func Test_removeAnswer(t *testing.T) {
+	record := &dns.Record{Answers: []*dns.Answer{{Rdata: []string{"wildcard"}}, {Rdata: []string{"apex"}}}}
+
+	assert.False(t, removeAnswer(record, "other"))
+	assert.Len(t, record.Answers, 2)
+
+	assert.True(t, removeAnswer(record, "apex"))
+	assert.Equal(t, []*dns.Answer{{Rdata: []string{"wildcard"}}}, record.Answers)
+
+	assert.True(t, removeAnswer(record, "wildcard"))
+	assert.Empty(t, record.Answers)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -117,3 +158,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
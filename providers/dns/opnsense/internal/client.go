@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"lego-toolbox/providers/dns/internal/errutils"
+)
+
+// Client the OPNsense Unbound API client.
+type Client struct {
+	apiKey    string
+	apiSecret string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(baseURL *url.URL, apiKey, apiSecret string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AddHostOverride adds a host override, and returns its UUID.
+// https://docs.opnsense.org/development/api/core/unbound.html
+func (c *Client) AddHostOverride(ctx context.Context, host HostOverride) (string, error) {
+	endpoint := c.baseURL.JoinPath("api", "unbound", "settings", "addHostOverride")
+
+	req, err := newJSONRequest(ctx, endpoint, hostOverrideRequest{Host: host})
+	if err != nil {
+		return "", err
+	}
+
+	var result Result
+	err = c.do(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if result.Result != "saved" || result.UUID == "" {
+		return "", result
+	}
+
+	return result.UUID, nil
+}
+
+// DeleteHostOverride deletes a host override.
+func (c *Client) DeleteHostOverride(ctx context.Context, uuid string) error {
+	endpoint := c.baseURL.JoinPath("api", "unbound", "settings", "delHostOverride", uuid)
+
+	req, err := newJSONRequest(ctx, endpoint, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	var result Result
+	err = c.do(req, &result)
+	if err != nil {
+		return err
+	}
+
+	if result.Result != "deleted" {
+		return result
+	}
+
+	return nil
+}
+
+// Reconfigure applies the settings to Unbound.
+func (c *Client) Reconfigure(ctx context.Context) error {
+	endpoint := c.baseURL.JoinPath("api", "unbound", "service", "reconfigure")
+
+	req, err := newJSONRequest(ctx, endpoint, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	var status Status
+	err = c.do(req, &status)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(status.Status, "ok") {
+		return fmt.Errorf("reconfigure: status: %s", status.Status)
+	}
+
+	return nil
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	req.SetBasicAuth(c.apiKey, c.apiSecret)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	err := json.NewEncoder(buf).Encode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
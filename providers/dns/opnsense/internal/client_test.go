@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T, pattern string, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc(pattern, func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(rw, fmt.Sprintf("unsupported method: %s", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		key, secret, ok := req.BasicAuth()
+		if !ok || key != "key" || secret != "secret" {
+			http.Error(rw, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		handler(rw, req)
+	})
+
+	baseURL, _ := url.Parse(server.URL)
+
+	client := NewClient(baseURL, "key", "secret")
+	client.HTTPClient = server.Client()
+
+	return client
+}
+
+func writeFixture(rw http.ResponseWriter, filename string) {
+	file, err := os.Open(filepath.Join("fixtures", filename))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() { _ = file.Close() }()
+
+	_, _ = io.Copy(rw, file)
+}
+
+func TestClient_AddHostOverride(t *testing.T) {
+	client := setupTest(t, "/api/unbound/settings/addHostOverride", func(rw http.ResponseWriter, req *http.Request) {
+		var body hostOverrideRequest
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expected := HostOverride{
+			Enabled:     "1",
+			Hostname:    "_acme-challenge",
+			Domain:      "example.com",
+			RR:          "TXT",
+			TXTData:     "txtTXTtxt",
+			Description: "lego",
+		}
+
+		if body.Host != expected {
+			http.Error(rw, fmt.Sprintf("invalid host override: %+v", body.Host), http.StatusBadRequest)
+			return
+		}
+
+		writeFixture(rw, "addHostOverride.json")
+	})
+
+	uuid, err := client.AddHostOverride(context.Background(), HostOverride{
+		Enabled:     "1",
+		Hostname:    "_acme-challenge",
+		Domain:      "example.com",
+		RR:          "TXT",
+		TXTData:     "txtTXTtxt",
+		Description: "lego",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08", uuid)
+}
+
+func TestClient_AddHostOverride_error(t *testing.T) {
+	client := setupTest(t, "/api/unbound/settings/addHostOverride", func(rw http.ResponseWriter, _ *http.Request) {
+		writeFixture(rw, "addHostOverride_error.json")
+	})
+
+	_, err := client.AddHostOverride(context.Background(), HostOverride{RR: "TXT"})
+	require.EqualError(t, err, "result: failed: host.hostname: A valid hostname is required.")
+}
+
+func TestClient_DeleteHostOverride(t *testing.T) {
+	client := setupTest(t, "/api/unbound/settings/delHostOverride/0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08", func(rw http.ResponseWriter, _ *http.Request) {
+		writeFixture(rw, "delHostOverride.json")
+	})
+
+	err := client.DeleteHostOverride(context.Background(), "0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08")
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteHostOverride_error(t *testing.T) {
+	client := setupTest(t, "/api/unbound/settings/delHostOverride/abc", func(rw http.ResponseWriter, _ *http.Request) {
+		writeFixture(rw, "delHostOverride_error.json")
+	})
+
+	err := client.DeleteHostOverride(context.Background(), "abc")
+	require.EqualError(t, err, "result: not found")
+}
+
+func TestClient_Reconfigure(t *testing.T) {
+	client := setupTest(t, "/api/unbound/service/reconfigure", func(rw http.ResponseWriter, _ *http.Request) {
+		writeFixture(rw, "reconfigure.json")
+	})
+
+	err := client.Reconfigure(context.Background())
+	require.NoError(t, err)
+}
+
+func TestClient_unauthorized(t *testing.T) {
+	client := setupTest(t, "/api/unbound/service/reconfigure", func(rw http.ResponseWriter, _ *http.Request) {
+		writeFixture(rw, "reconfigure.json")
+	})
+	client.apiSecret = "wrong"
+
+	err := client.Reconfigure(context.Background())
+	require.EqualError(t, err, "unexpected status code: [status code: 401] body: invalid credentials")
+}
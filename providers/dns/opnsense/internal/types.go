@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HostOverride is an Unbound host override.
+type HostOverride struct {
+	Enabled     string `json:"enabled"`
+	Hostname    string `json:"hostname"`
+	Domain      string `json:"domain"`
+	RR          string `json:"rr"`
+	TXTData     string `json:"txtdata"`
+	Description string `json:"description"`
+}
+
+type hostOverrideRequest struct {
+	Host HostOverride `json:"host"`
+}
+
+// Result is the response of the settings API.
+type Result struct {
+	Result      string            `json:"result"`
+	UUID        string            `json:"uuid,omitempty"`
+	Validations map[string]string `json:"validations,omitempty"`
+}
+
+func (r Result) Error() string {
+	if len(r.Validations) == 0 {
+		return fmt.Sprintf("result: %s", r.Result)
+	}
+
+	var msgs []string
+	for field, msg := range r.Validations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", field, msg))
+	}
+
+	sort.Strings(msgs)
+
+	return fmt.Sprintf("result: %s: %s", r.Result, strings.Join(msgs, ", "))
+}
+
+// Status is the response of the service API.
+type Status struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,235 @@
+// Package opnsense implements a DNS provider for solving the DNS-01 challenge using the Unbound host overrides of OPNsense.
+package opnsense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/providers/dns/internal/tlsutil"
+	"lego-toolbox/providers/dns/opnsense/internal"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "OPNSENSE_"
+
+	EnvBaseURL            = envNamespace + "BASE_URL"
+	EnvAPIKey             = envNamespace + "API_KEY"
+	EnvAPISecret          = envNamespace + "API_SECRET"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+	EnvCAFile             = envNamespace + "CA_FILE"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// description is the description of the host overrides created by the provider.
+const description = "lego-toolbox ACME challenge"
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// BaseURL is the URL of the OPNsense web GUI, e.g. https://192.168.1.1
+	BaseURL   string `yaml:"baseURL"`
+	APIKey    string `yaml:"apiKey"`
+	APISecret string `yaml:"apiSecret"`
+
+	// InsecureSkipVerify accepts any server certificate, e.g. the self-signed certificate of the web GUI.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	// CAFile is the PEM file of the CA verifying the server certificate, the system roots are used when empty.
+	CAFile string `yaml:"caFile"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+baseURL: "https://192.168.1.1"     # OPNsense Web 管理地址
+apiKey: "your_api_key"             # API 密钥（System > Access > Users 创建）
+apiSecret: "your_api_secret"       # API 密钥对应的 Secret
+insecureSkipVerify: false          # 是否跳过服务器证书验证（自签名证书）
+caFile: ""                         # 校验服务器证书的 CA 文件（PEM），为空时使用系统根证书
+propagationTimeout: 60s            # 传播超时时间
+pollingInterval: 2s                # 轮询间隔时间`
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	hostUUIDs *challengestore.Store[string]
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for OPNsense.
+// Credentials must be passed in the environment variables:
+// OPNSENSE_BASE_URL, OPNSENSE_API_KEY and OPNSENSE_API_SECRET.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvBaseURL, EnvAPIKey, EnvAPISecret)
+	if err != nil {
+		return nil, fmt.Errorf("opnsense: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.BaseURL = values[EnvBaseURL]
+	config.APIKey = values[EnvAPIKey]
+	config.APISecret = values[EnvAPISecret]
+	config.InsecureSkipVerify = env.GetOrDefaultBool(EnvInsecureSkipVerify, false)
+	config.CAFile = env.GetOrFile(EnvCAFile)
+
+	return NewDNSProviderConfig(config)
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for OPNsense.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("opnsense: the configuration of the DNS provider is nil")
+	}
+
+	if config.BaseURL == "" {
+		return nil, errors.New("opnsense: missing base URL")
+	}
+
+	if config.APIKey == "" || config.APISecret == "" {
+		return nil, errors.New("opnsense: credentials missing")
+	}
+
+	baseURL, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("opnsense: failed to parse base URL (%s): %w", config.BaseURL, err)
+	}
+
+	client := internal.NewClient(baseURL, config.APIKey, config.APISecret)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	if config.InsecureSkipVerify || config.CAFile != "" {
+		err = tlsutil.SetTLSConfig(client.HTTPClient, config.InsecureSkipVerify, config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("opnsense: %w", err)
+		}
+	}
+
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		hostUUIDs: challengestore.New[string](),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.hostUUIDs.Persist(path)
+}
+
+// Present creates a TXT host override using the specified parameters, and applies it to Unbound.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	hostname, hostDomain, ok := strings.Cut(dns01.UnFqdn(info.EffectiveFQDN), ".")
+	if !ok {
+		return fmt.Errorf("opnsense: unsupported domain: %s", info.EffectiveFQDN)
+	}
+
+	ctx := context.Background()
+
+	uuid, err := d.client.AddHostOverride(ctx, internal.HostOverride{
+		Enabled:     "1",
+		Hostname:    hostname,
+		Domain:      hostDomain,
+		RR:          "TXT",
+		TXTData:     info.Value,
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("opnsense: add host override: %w", err)
+	}
+
+	d.hostUUIDs.Set(token, info.EffectiveFQDN, uuid)
+
+	err = d.client.Reconfigure(ctx)
+	if err != nil {
+		return fmt.Errorf("opnsense: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT host override matching the specified parameters, and applies the removal to Unbound.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	uuid, ok := d.hostUUIDs.Get(token, info.EffectiveFQDN)
+	if !ok {
+		return fmt.Errorf("opnsense: unknown host override UUID for '%s' '%s'", info.EffectiveFQDN, token)
+	}
+
+	ctx := context.Background()
+
+	err := d.client.DeleteHostOverride(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("opnsense: delete host override (%s): %w", uuid, err)
+	}
+
+	d.hostUUIDs.Delete(token, info.EffectiveFQDN)
+
+	err = d.client.Reconfigure(ctx)
+	if err != nil {
+		return fmt.Errorf("opnsense: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package opnsense
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/rawrecord"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvBaseURL, EnvAPIKey, EnvAPISecret).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvBaseURL:   "https://192.168.1.1",
+				EnvAPIKey:    "key",
+				EnvAPISecret: "secret",
+			},
+		},
+		{
+			desc: "missing base URL",
+			envVars: map[string]string{
+				EnvAPIKey:    "key",
+				EnvAPISecret: "secret",
+			},
+			expected: "opnsense: some credentials information are missing: OPNSENSE_BASE_URL",
+		},
+		{
+			desc: "missing API secret",
+			envVars: map[string]string{
+				EnvBaseURL: "https://192.168.1.1",
+				EnvAPIKey:  "key",
+			},
+			expected: "opnsense: some credentials information are missing: OPNSENSE_API_SECRET",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		baseURL   string
+		apiKey    string
+		apiSecret string
+		expected  string
+	}{
+		{
+			desc:      "success",
+			baseURL:   "https://192.168.1.1",
+			apiKey:    "key",
+			apiSecret: "secret",
+		},
+		{
+			desc:      "missing base URL",
+			apiKey:    "key",
+			apiSecret: "secret",
+			expected:  "opnsense: missing base URL",
+		},
+		{
+			desc:     "missing credentials",
+			baseURL:  "https://192.168.1.1",
+			apiKey:   "key",
+			expected: "opnsense: credentials missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.BaseURL = test.baseURL
+			config.APIKey = test.apiKey
+			config.APISecret = test.apiSecret
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig_insecureSkipVerify(t *testing.T) {
+	config := NewDefaultConfig()
+	config.BaseURL = "https://192.168.1.1"
+	config.APIKey = "key"
+	config.APISecret = "secret"
+	config.InsecureSkipVerify = true
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	transport, ok := p.client.HTTPClient.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestDNSProvider_PresentCleanUp(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		overrides = map[string]map[string]string{}
+		applied   int
+	)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("POST /api/unbound/settings/addHostOverride", func(rw http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Host map[string]string `json:"host"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		uuid := body.Host["txtdata"]
+		overrides[uuid] = body.Host
+
+		_ = json.NewEncoder(rw).Encode(map[string]string{"result": "saved", "uuid": uuid})
+	})
+
+	mux.HandleFunc("POST /api/unbound/settings/delHostOverride/{uuid}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if _, ok := overrides[req.PathValue("uuid")]; !ok {
+			_, _ = rw.Write([]byte(`{"result":"not found"}`))
+			return
+		}
+
+		delete(overrides, req.PathValue("uuid"))
+
+		_, _ = rw.Write([]byte(`{"result":"deleted"}`))
+	})
+
+	mux.HandleFunc("POST /api/unbound/service/reconfigure", func(rw http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		applied++
+		mu.Unlock()
+
+		_, _ = rw.Write([]byte(`{"status":"ok"}`))
+	})
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.APIKey = "key"
+	config.APISecret = "secret"
+	config.HTTPClient = server.Client()
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	keyAuth := rawrecord.KeyAuth("_acme-challenge.home.example.com.", "value")
+
+	err = p.Present("home.example.com", "token", keyAuth)
+	require.NoError(t, err)
+
+	require.Len(t, overrides, 1)
+	assert.Equal(t, map[string]string{
+		"enabled":     "1",
+		"hostname":    "_acme-challenge",
+		"domain":      "home.example.com",
+		"rr":          "TXT",
+		"txtdata":     "value",
+		"description": description,
+	}, overrides["value"])
+
+	err = p.CleanUp("home.example.com", "token", keyAuth)
+	require.NoError(t, err)
+
+	assert.Empty(t, overrides)
+	assert.Equal(t, 2, applied)
+
+	err = p.CleanUp("home.example.com", "token", keyAuth)
+	require.EqualError(t, err, "opnsense: unknown host override UUID for '_acme-challenge.home.example.com.' 'token'")
+}
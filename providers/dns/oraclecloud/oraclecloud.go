@@ -12,6 +12,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/dns"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -103,7 +104,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneNameOrID, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -138,7 +139,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zoneNameOrID, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
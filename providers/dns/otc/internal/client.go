@@ -12,7 +12,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 type Client struct {
@@ -22,8 +24,15 @@ type Client struct {
 	projectName string
 
 	IdentityEndpoint string
-	token            string
-	muToken          sync.Mutex
+	// Region selects the DNS endpoint of the catalog, the first one is used when empty.
+	Region string
+
+	// TokenCache persists the token across the runs, disabled when nil.
+	TokenCache *tokencache.Cache
+
+	token          string
+	tokenExpiresAt time.Time
+	muToken        sync.Mutex
 
 	baseURL   *url.URL
 	muBaseURL sync.Mutex
@@ -39,6 +48,7 @@ func NewClient(username string, password string, domainName string, projectName
 		projectName:      projectName,
 		IdentityEndpoint: DefaultIdentityEndpoint,
 		HTTPClient:       &http.Client{Timeout: 5 * time.Second},
+		TokenCache:       tokencache.Default(),
 	}
 }
 
@@ -175,6 +185,17 @@ func (c *Client) do(req *http.Request, result any) error {
 
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		// the token has been revoked: the next Login authenticates again.
+		c.muToken.Lock()
+		c.tokenExpiresAt = time.Time{}
+		c.muToken.Unlock()
+
+		if err := c.TokenCache.Delete(c.tokenCacheKey()); err != nil {
+			log.Warnf("otc: %v", err)
+		}
+	}
+
 	if resp.StatusCode >= http.StatusBadRequest {
 		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
 	}
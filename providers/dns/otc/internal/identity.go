@@ -4,19 +4,54 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 // DefaultIdentityEndpoint the default API identity endpoint.
 const DefaultIdentityEndpoint = "https://iam.eu-de.otc.t-systems.com:443/v3/auth/tokens"
 
+// tokenExpiryMargin is the remaining validity under which a cached token is renewed,
+// so a token does not expire during a challenge.
+const tokenExpiryMargin = 5 * time.Minute
+
 // Login Starts a new OTC API Session. Authenticates using userName, password
 // and receives a token to be used in for subsequent requests.
+// The project-scoped token is cached until its expiry: Login does not authenticate again while it is valid,
+// nor in the next runs when the token cache is enabled (see tokencache.Default).
 func (c *Client) Login(ctx context.Context) error {
+	c.muToken.Lock()
+	defer c.muToken.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenExpiryMargin).Before(c.tokenExpiresAt) {
+		return nil
+	}
+
+	key := c.tokenCacheKey()
+
+	// the token of a previous run.
+	var cached cachedToken
+	if expiresAt, ok := c.TokenCache.Get(key, &cached); ok {
+		baseURL, err := url.Parse(cached.BaseURL)
+		if err == nil {
+			c.muBaseURL.Lock()
+			c.baseURL = baseURL
+			c.muBaseURL.Unlock()
+
+			c.token = cached.Token
+			c.tokenExpiresAt = expiresAt
+
+			return nil
+		}
+	}
+
 	payload := LoginRequest{
 		Auth: Auth{
 			Identity: Identity{
@@ -44,15 +79,7 @@ func (c *Client) Login(ctx context.Context) error {
 		return err
 	}
 
-	c.muToken.Lock()
-	defer c.muToken.Unlock()
-	c.token = token
-
-	if c.token == "" {
-		return errors.New("unable to get auth token")
-	}
-
-	baseURL, err := getBaseURL(tokenResp)
+	baseURL, err := getBaseURL(tokenResp, c.Region)
 	if err != nil {
 		return err
 	}
@@ -61,9 +88,29 @@ func (c *Client) Login(ctx context.Context) error {
 	c.baseURL = baseURL
 	c.muBaseURL.Unlock()
 
+	c.token = token
+
+	// a token without a known expiry is not cached.
+	c.tokenExpiresAt, _ = time.Parse(time.RFC3339Nano, tokenResp.Token.ExpiresAt)
+
+	err = c.TokenCache.Set(key, cachedToken{Token: token, BaseURL: baseURL.String()}, c.tokenExpiresAt)
+	if err != nil {
+		log.Warnf("otc: %v", err)
+	}
+
 	return nil
 }
 
+// cachedToken is the project-scoped token persisted across the runs, with the DNS endpoint of its catalog.
+type cachedToken struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"baseURL"`
+}
+
+func (c *Client) tokenCacheKey() string {
+	return tokencache.Key("otc", c.IdentityEndpoint, c.Region, c.domainName, c.projectName, c.username, c.password)
+}
+
 // https://docs.otc.t-systems.com/identity-access-management/api-ref/apis/token_management/obtaining_a_user_token.html
 func (c *Client) obtainUserToken(ctx context.Context, payload LoginRequest) (*TokenResponse, string, error) {
 	req, err := newJSONRequest(ctx, http.MethodPost, c.IdentityEndpoint, payload)
@@ -104,15 +151,25 @@ func (c *Client) obtainUserToken(ctx context.Context, payload LoginRequest) (*To
 	return &newToken, token, nil
 }
 
-func getBaseURL(tokenResp *TokenResponse) (*url.URL, error) {
+func getBaseURL(tokenResp *TokenResponse, region string) (*url.URL, error) {
 	var endpoints []Endpoint
 	for _, v := range tokenResp.Token.Catalog {
-		if v.Type == "dns" {
-			endpoints = append(endpoints, v.Endpoints...)
+		if v.Type != "dns" {
+			continue
+		}
+
+		for _, endpoint := range v.Endpoints {
+			if region == "" || endpoint.Region == region || endpoint.RegionID == region {
+				endpoints = append(endpoints, endpoint)
+			}
 		}
 	}
 
 	if len(endpoints) == 0 {
+		if region != "" {
+			return nil, fmt.Errorf("unable to get dns endpoint for the region %s", region)
+		}
+
 		return nil, errors.New("unable to get dns endpoint")
 	}
 
@@ -1,12 +1,18 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"net/http"
 	"net/url"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/internal/tokencache"
 )
 
 func TestClient_Login(t *testing.T) {
@@ -23,3 +29,117 @@ func TestClient_Login(t *testing.T) {
 	assert.Equal(t, serverURL.JoinPath("v2").String(), client.baseURL.String())
 	assert.Equal(t, fakeOTCToken, client.token)
 }
+
+func TestClient_Login_cached(t *testing.T) {
+	mock := NewDNSServerMock(t)
+
+	var calls int
+
+	mock.mux.HandleFunc("/v3/auth/token", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("X-Subject-Token", fakeOTCToken)
+
+		_, _ = fmt.Fprintf(w, `{"token": {"expires_at": %q, "catalog": [{"type": "dns", "endpoints": [{"url": %q, "region": "eu-de"}]}]}}`,
+			time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339Nano), mock.GetServerURL())
+	})
+
+	client := NewClient("user", "secret", "example.com", "test")
+	client.IdentityEndpoint, _ = url.JoinPath(mock.GetServerURL(), "/v3/auth/token")
+
+	require.NoError(t, client.Login(context.Background()))
+	require.NoError(t, client.Login(context.Background()))
+
+	assert.Equal(t, 1, calls)
+
+	// a revoked token is renewed.
+	client.tokenExpiresAt = time.Time{}
+
+	require.NoError(t, client.Login(context.Background()))
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_Login_tokenCache(t *testing.T) {
+	mock := NewDNSServerMock(t)
+
+	var calls int
+
+	mock.mux.HandleFunc("/v3/auth/token", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("X-Subject-Token", fakeOTCToken)
+
+		_, _ = fmt.Fprintf(w, `{"token": {"expires_at": %q, "catalog": [{"type": "dns", "endpoints": [{"url": %q, "region": "eu-de"}]}]}}`,
+			time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339Nano), mock.GetServerURL())
+	})
+
+	cache, err := tokencache.New(filepath.Join(t.TempDir(), "tokens"), bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	newClient := func() *Client {
+		client := NewClient("user", "secret", "example.com", "test")
+		client.IdentityEndpoint, _ = url.JoinPath(mock.GetServerURL(), "/v3/auth/token")
+		client.TokenCache = cache
+
+		return client
+	}
+
+	require.NoError(t, newClient().Login(context.Background()))
+
+	// the next run.
+	client := newClient()
+	require.NoError(t, client.Login(context.Background()))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, fakeOTCToken, client.token)
+
+	serverURL, _ := url.Parse(mock.GetServerURL())
+	assert.Equal(t, serverURL.JoinPath("v2").String(), client.baseURL.String())
+}
+
+func TestClient_Login_expiring(t *testing.T) {
+	mock := NewDNSServerMock(t)
+
+	var calls int
+
+	mock.mux.HandleFunc("/v3/auth/token", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("X-Subject-Token", fakeOTCToken)
+
+		_, _ = fmt.Fprintf(w, `{"token": {"expires_at": %q, "catalog": [{"type": "dns", "endpoints": [{"url": %q, "region": "eu-de"}]}]}}`,
+			time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano), mock.GetServerURL())
+	})
+
+	client := NewClient("user", "secret", "example.com", "test")
+	client.IdentityEndpoint, _ = url.JoinPath(mock.GetServerURL(), "/v3/auth/token")
+
+	require.NoError(t, client.Login(context.Background()))
+	require.NoError(t, client.Login(context.Background()))
+
+	assert.Equal(t, 2, calls)
+}
+
+func Test_getBaseURL_region(t *testing.T) {
+	tokenResp := &TokenResponse{Token: Token{Catalog: []Catalog{{
+		Type: "dns",
+		Endpoints: []Endpoint{
+			{URL: "https://dns.eu-de.otc.t-systems.com", Region: "eu-de"},
+			{URL: "https://dns.eu-nl.otc.t-systems.com", Region: "eu-nl"},
+		},
+	}}}}
+
+	baseURL, err := getBaseURL(tokenResp, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://dns.eu-de.otc.t-systems.com/v2", baseURL.String())
+
+	baseURL, err = getBaseURL(tokenResp, "eu-nl")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://dns.eu-nl.otc.t-systems.com/v2", baseURL.String())
+
+	_, err = getBaseURL(tokenResp, "eu-ch2")
+	require.EqualError(t, err, "unable to get dns endpoint for the region eu-ch2")
+}
@@ -12,10 +12,19 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/otc/internal"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 const defaultIdentityEndpoint = "https://iam.eu-de.otc.t-systems.com:443/v3/auth/tokens"
 
+// identityEndpoints are the IAM endpoints of the OTC regions.
+var identityEndpoints = map[string]string{
+	"eu-de":  defaultIdentityEndpoint,
+	"eu-nl":  "https://iam.eu-nl.otc.t-systems.com:443/v3/auth/tokens",
+	"eu-ch2": "https://iam-pub.eu-ch2.sc.otc.t-systems.com:443/v3/auth/tokens",
+}
+
 // minTTL 300 is otc minimum value for TTL.
 const minTTL = 300
 
@@ -28,6 +37,7 @@ const (
 	EnvPassword         = envNamespace + "PASSWORD"
 	EnvProjectName      = envNamespace + "PROJECT_NAME"
 	EnvIdentityEndpoint = envNamespace + "IDENTITY_ENDPOINT"
+	EnvRegion           = envNamespace + "REGION"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -38,16 +48,17 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	IdentityEndpoint   string
-	DomainName         string
-	ProjectName        string
-	UserName           string
-	Password           string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	SequenceInterval   time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	IdentityEndpoint   string        `yaml:"identityEndpoint"`
+	Region             string        `yaml:"region"`
+	DomainName         string        `yaml:"domainName"`
+	ProjectName        string        `yaml:"projectName"`
+	UserName           string        `yaml:"userName"`
+	Password           string        `yaml:"password"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -56,24 +67,54 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		IdentityEndpoint:   env.GetOrDefaultString(EnvIdentityEndpoint, defaultIdentityEndpoint),
+		IdentityEndpoint:   env.GetOrFile(EnvIdentityEndpoint),
+		Region:             env.GetOrFile(EnvRegion),
 		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-
-				// Workaround for keep alive bug in otc api
-				DisableKeepAlives: true,
-			},
+		HTTPClient:         newHTTPClient(env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second)),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                minTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		SequenceInterval:   dns01.DefaultPropagationTimeout,
+		HTTPClient:         newHTTPClient(10 * time.Second),
+	}
+}
+
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+userName: "your_user_name"                 # IAM 用户名
+password: "your_password"                  # IAM 用户密码
+domainName: "your_domain_name"             # 租户（域）名称
+projectName: "eu-de_project"               # 项目名称，令牌的作用域
+region: "eu-de"                            # 区域（eu-de、eu-nl、eu-ch2），用于选择 IAM 和 DNS 端点
+identityEndpoint: ""                       # IAM 令牌端点，为空时根据区域推导
+propagationTimeout: 60s                    # 传播超时时间
+pollingInterval: 2s                        # 轮询间隔时间
+sequenceInterval: 60s                      # 顺序请求之间的间隔时间
+ttl: 300                                   # TXT 记录的生存时间（秒），最小值为 300`
+}
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+
+			// Workaround for keep alive bug in otc api
+			DisableKeepAlives: true,
 		},
 	}
 }
@@ -86,7 +127,7 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for OTC DNS.
 // Credentials must be passed in the environment variables: OTC_USER_NAME,
-// OTC_DOMAIN_NAME, OTC_PASSWORD OTC_PROJECT_NAME and OTC_IDENTITY_ENDPOINT.
+// OTC_DOMAIN_NAME, OTC_PASSWORD OTC_PROJECT_NAME and OTC_IDENTITY_ENDPOINT (or OTC_REGION).
 func NewDNSProvider() (*DNSProvider, error) {
 	values, err := env.Get(EnvDomainName, EnvUserName, EnvPassword, EnvProjectName)
 	if err != nil {
@@ -102,6 +143,16 @@ func NewDNSProvider() (*DNSProvider, error) {
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for OTC DNS.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -116,12 +167,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("otc: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
 	}
 
-	client := internal.NewClient(config.UserName, config.Password, config.DomainName, config.ProjectName)
-
-	if config.IdentityEndpoint != "" {
-		client.IdentityEndpoint = config.IdentityEndpoint
+	if config.IdentityEndpoint == "" {
+		endpoint, ok := identityEndpoints[config.Region]
+		switch {
+		case ok:
+			config.IdentityEndpoint = endpoint
+		case config.Region == "":
+			config.IdentityEndpoint = defaultIdentityEndpoint
+		default:
+			return nil, fmt.Errorf("otc: unknown region %q, the identity endpoint must be set", config.Region)
+		}
 	}
 
+	// the client, and its cached token, is shared by the Present and CleanUp calls.
+	client := internal.NewClient(config.UserName, config.Password, config.DomainName, config.ProjectName)
+	client.IdentityEndpoint = config.IdentityEndpoint
+	client.Region = config.Region
+
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
 	}
@@ -131,7 +193,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -168,7 +230,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -26,6 +26,7 @@ func (s *OTCSuite) SetupTest() {
 		EnvPassword,
 		EnvProjectName,
 		EnvIdentityEndpoint,
+		EnvRegion,
 	)
 }
 
@@ -77,6 +78,55 @@ func (s *OTCSuite) TestLoginEnv() {
 	s.Equal("https://iam.eu-de.otc.t-systems.com:443/v3/auth/tokens", provider.config.IdentityEndpoint)
 }
 
+func (s *OTCSuite) TestLoginEnvRegion() {
+	s.envTest.ClearEnv()
+
+	s.envTest.Apply(map[string]string{
+		EnvDomainName:  "unittest1",
+		EnvUserName:    "unittest2",
+		EnvPassword:    "unittest3",
+		EnvProjectName: "unittest4",
+		EnvRegion:      "eu-ch2",
+	})
+
+	provider, err := NewDNSProvider()
+	s.Require().NoError(err)
+
+	s.Equal("https://iam-pub.eu-ch2.sc.otc.t-systems.com:443/v3/auth/tokens", provider.config.IdentityEndpoint)
+	s.Equal("eu-ch2", provider.client.Region)
+
+	os.Setenv(EnvRegion, "eu-xx")
+
+	_, err = NewDNSProvider()
+	s.EqualError(err, `otc: unknown region "eu-xx", the identity endpoint must be set`)
+}
+
+func (s *OTCSuite) TestParseConfig() {
+	rawConfig := []byte(`
+userName: user
+password: secret
+domainName: domain
+projectName: eu-nl_project
+region: eu-nl
+ttl: 600
+`)
+
+	config, err := ParseConfig(rawConfig)
+	s.Require().NoError(err)
+
+	s.Equal("user", config.UserName)
+	s.Equal("secret", config.Password)
+	s.Equal("domain", config.DomainName)
+	s.Equal("eu-nl_project", config.ProjectName)
+	s.Equal("eu-nl", config.Region)
+	s.Equal(600, config.TTL)
+
+	provider, err := NewDNSProviderConfig(config)
+	s.Require().NoError(err)
+
+	s.Equal("https://iam.eu-nl.otc.t-systems.com:443/v3/auth/tokens", provider.client.IdentityEndpoint)
+}
+
 func (s *OTCSuite) TestLoginEnvEmpty() {
 	s.envTest.ClearEnv()
 
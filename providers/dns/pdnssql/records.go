@@ -0,0 +1,96 @@
+package pdnssql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// recordName returns the name as stored by PowerDNS: lower case, without the trailing dot.
+func recordName(fqdn string) string {
+	return strings.ToLower(dns01.UnFqdn(fqdn))
+}
+
+// parentNames returns the name and its parents, from the longest to the shortest (without the TLD).
+func parentNames(fqdn string) []string {
+	labels := strings.Split(recordName(fqdn), ".")
+
+	var names []string
+	for i := range len(labels) - 1 {
+		names = append(names, strings.Join(labels[i:], "."))
+	}
+
+	return names
+}
+
+// quoteTXT returns the content of a TXT record as stored by PowerDNS.
+func quoteTXT(value string) string {
+	return strconv.Quote(value)
+}
+
+// rebindDollar replaces the `?` placeholders with `$1`, `$2`, ... (PostgreSQL).
+// The queries don't contain `?` in literals.
+func rebindDollar(query string) string {
+	var b strings.Builder
+
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return b.String()
+}
+
+// bumpSOASerial increments the serial of the SOA content: `mname rname serial refresh retry expire minimum`.
+// A serial of 0 is left as is: PowerDNS computes it from the change dates of the records.
+func bumpSOASerial(content string, now time.Time) (string, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 3 {
+		return "", fmt.Errorf("invalid SOA content %q", content)
+	}
+
+	serial, err := strconv.ParseUint(fields[2], 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid SOA serial %q: %w", fields[2], err)
+	}
+
+	if serial == 0 {
+		return content, nil
+	}
+
+	fields[2] = strconv.FormatUint(uint64(nextSerial(uint32(serial), now)), 10)
+
+	return strings.Join(fields, " "), nil
+}
+
+// nextSerial returns the next serial.
+// A date based serial (YYYYMMDDnn) moves to the current date when it is older.
+func nextSerial(serial uint32, now time.Time) uint32 {
+	next := serial + 1
+
+	today, err := strconv.ParseUint(now.UTC().Format("20060102")+"00", 10, 32)
+	if err != nil {
+		return next
+	}
+
+	if isDateSerial(serial) && uint32(today) > next {
+		return uint32(today)
+	}
+
+	return next
+}
+
+func isDateSerial(serial uint32) bool {
+	_, err := time.Parse("20060102", strconv.FormatUint(uint64(serial/100), 10))
+
+	return err == nil
+}
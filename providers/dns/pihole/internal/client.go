@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"lego-toolbox/providers/dns/internal/errutils"
+)
+
+// DefaultBaseURL is the URL of the web interface of Pi-hole on the local network.
+const DefaultBaseURL = "http://pi.hole"
+
+const sidHeader = "X-FTL-SID"
+
+// dnsmasqLines is the config element holding the custom dnsmasq lines.
+const dnsmasqLines = "misc/dnsmasq_lines"
+
+// Client the Pi-hole v6 API client.
+type Client struct {
+	password string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(baseURL *url.URL, password string) *Client {
+	return &Client{
+		password:   password,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AddDnsmasqLine adds a line to the custom dnsmasq config (misc.dnsmasq_lines), Pi-hole restarts its resolver.
+// https://docs.pi-hole.net/api/config/
+func (c *Client) AddDnsmasqLine(ctx context.Context, line string) error {
+	return c.updateDnsmasqLines(ctx, http.MethodPut, line)
+}
+
+// DeleteDnsmasqLine removes a line from the custom dnsmasq config (misc.dnsmasq_lines), Pi-hole restarts its resolver.
+func (c *Client) DeleteDnsmasqLine(ctx context.Context, line string) error {
+	return c.updateDnsmasqLines(ctx, http.MethodDelete, line)
+}
+
+func (c *Client) updateDnsmasqLines(ctx context.Context, method, line string) error {
+	endpoint := c.baseURL.JoinPath("api", "config", dnsmasqLines, line)
+
+	req, err := newJSONRequest(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	if sid := getSID(req.Context()); sid != "" {
+		req.Header.Set(sidHeader, sid)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		return parseError(req, resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func parseError(req *http.Request, resp *http.Response) error {
+	raw, _ := io.ReadAll(resp.Body)
+
+	var apiErr APIError
+	err := json.Unmarshal(raw, &apiErr)
+	if err != nil || apiErr.Err.Key == "" {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	return errutils.NewAPIError(req, resp.StatusCode, raw, apiErr).WithCode(apiErr.Err.Key)
+}
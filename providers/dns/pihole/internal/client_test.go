@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSID  = "vFA+EP4MQ5JJvJg+3Q2Jnw="
+	testLine = `txt-record=_acme-challenge.example.com,"txtTXTtxt"`
+)
+
+func setupTest(t *testing.T) (*Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("POST /api/auth", func(rw http.ResponseWriter, req *http.Request) {
+		var body authRequest
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if body.Password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			writeFixture(rw, "auth_error.json")
+			return
+		}
+
+		writeFixture(rw, "auth.json")
+	})
+
+	baseURL, _ := url.Parse(server.URL)
+
+	client := NewClient(baseURL, "secret")
+	client.HTTPClient = server.Client()
+
+	return client, mux
+}
+
+// authenticated checks the session ID before calling the handler.
+func authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(sidHeader) != testSID {
+			rw.WriteHeader(http.StatusUnauthorized)
+			writeFixture(rw, "auth_error.json")
+			return
+		}
+
+		handler(rw, req)
+	}
+}
+
+func writeFixture(rw http.ResponseWriter, filename string) {
+	file, err := os.Open(filepath.Join("fixtures", filename))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() { _ = file.Close() }()
+
+	_, _ = io.Copy(rw, file)
+}
+
+func TestClient_CreateAuthenticatedContext(t *testing.T) {
+	client, _ := setupTest(t)
+
+	ctx, err := client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, testSID, getSID(ctx))
+}
+
+func TestClient_CreateAuthenticatedContext_error(t *testing.T) {
+	client, _ := setupTest(t)
+	client.password = "wrong"
+
+	_, err := client.CreateAuthenticatedContext(context.Background())
+	require.ErrorContains(t, err, "[status code: 401]")
+	require.ErrorContains(t, err, "unauthorized: Unauthorized")
+}
+
+func TestClient_Logout(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("DELETE /api/auth", authenticated(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	ctx, err := client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	err = client.Logout(ctx)
+	require.NoError(t, err)
+}
+
+func TestClient_AddDnsmasqLine(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("PUT /api/config/misc/dnsmasq_lines/{line}", authenticated(func(rw http.ResponseWriter, req *http.Request) {
+		if req.PathValue("line") != testLine {
+			http.Error(rw, "invalid line: "+req.PathValue("line"), http.StatusBadRequest)
+			return
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+	}))
+
+	ctx, err := client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	err = client.AddDnsmasqLine(ctx, testLine)
+	require.NoError(t, err)
+}
+
+func TestClient_AddDnsmasqLine_error(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("PUT /api/config/misc/dnsmasq_lines/{line}", authenticated(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+		writeFixture(rw, "config_error.json")
+	}))
+
+	ctx, err := client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	err = client.AddDnsmasqLine(ctx, testLine)
+	require.ErrorContains(t, err, "[status code: 403]")
+	require.ErrorContains(t, err, "forbidden: Unable to change configuration (read-only) (The current app session is not allowed")
+}
+
+func TestClient_DeleteDnsmasqLine(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("DELETE /api/config/misc/dnsmasq_lines/{line}", authenticated(func(rw http.ResponseWriter, req *http.Request) {
+		if req.PathValue("line") != testLine {
+			http.Error(rw, "invalid line: "+req.PathValue("line"), http.StatusBadRequest)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	ctx, err := client.CreateAuthenticatedContext(context.Background())
+	require.NoError(t, err)
+
+	err = client.DeleteDnsmasqLine(ctx, testLine)
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteDnsmasqLine_unauthenticated(t *testing.T) {
+	client, mux := setupTest(t)
+
+	mux.HandleFunc("DELETE /api/config/misc/dnsmasq_lines/{line}", authenticated(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	err := client.DeleteDnsmasqLine(context.Background(), testLine)
+	require.ErrorContains(t, err, "[status code: 401]")
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type token string
+
+const sidKey token = "sid"
+
+// login authenticates with the password (web interface or application password),
+// and returns the session ID used by the subsequent requests.
+// https://docs.pi-hole.net/api/auth/
+func (c *Client) login(ctx context.Context) (string, error) {
+	endpoint := c.baseURL.JoinPath("api", "auth")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, authRequest{Password: c.password})
+	if err != nil {
+		return "", err
+	}
+
+	var result AuthResponse
+	err = c.do(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if !result.Session.Valid || result.Session.SID == "" {
+		return "", errors.New(result.Session.Message)
+	}
+
+	return result.Session.SID, nil
+}
+
+// Logout deletes the current session, the number of sessions of the API is limited.
+func (c *Client) Logout(ctx context.Context) error {
+	if getSID(ctx) == "" {
+		// nothing to do
+		return nil
+	}
+
+	endpoint := c.baseURL.JoinPath("api", "auth")
+
+	req, err := newJSONRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+// CreateAuthenticatedContext logs in, and returns a context holding the session ID.
+func (c *Client) CreateAuthenticatedContext(ctx context.Context) (context.Context, error) {
+	sid, err := c.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return context.WithValue(ctx, sidKey, sid), nil
+}
+
+func getSID(ctx context.Context) string {
+	sid, ok := ctx.Value(sidKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return sid
+}
@@ -0,0 +1,42 @@
+package internal
+
+import "fmt"
+
+type authRequest struct {
+	Password string `json:"password"`
+}
+
+// AuthResponse is the response of the authentication.
+type AuthResponse struct {
+	Session Session `json:"session"`
+}
+
+// Session is a session of the API.
+type Session struct {
+	Valid    bool   `json:"valid"`
+	SID      string `json:"sid"`
+	Validity int    `json:"validity"`
+	Message  string `json:"message"`
+}
+
+// APIError is the error of the API.
+type APIError struct {
+	Err ErrorDetail `json:"error"`
+}
+
+// ErrorDetail is the detail of an API error.
+type ErrorDetail struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+	Hint    string `json:"hint"`
+}
+
+func (a APIError) Error() string {
+	msg := fmt.Sprintf("%s: %s", a.Err.Key, a.Err.Message)
+
+	if a.Err.Hint != "" {
+		msg += fmt.Sprintf(" (%s)", a.Err.Hint)
+	}
+
+	return msg
+}
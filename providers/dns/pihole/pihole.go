@@ -0,0 +1,211 @@
+// Package pihole implements a DNS provider for solving the DNS-01 challenge using the local DNS of Pi-hole v6.
+package pihole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/tlsutil"
+	"lego-toolbox/providers/dns/pihole/internal"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "PIHOLE_"
+
+	EnvBaseURL            = envNamespace + "BASE_URL"
+	EnvPassword           = envNamespace + "PASSWORD"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+	EnvCAFile             = envNamespace + "CA_FILE"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// BaseURL is the URL of the Pi-hole web interface, e.g. https://pi.hole
+	BaseURL string `yaml:"baseURL"`
+	// Password is the password of the web interface, or an application password.
+	Password string `yaml:"password"`
+
+	// InsecureSkipVerify accepts any server certificate, e.g. the self-signed certificate of the web interface.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	// CAFile is the PEM file of the CA verifying the server certificate, the system roots are used when empty.
+	CAFile string `yaml:"caFile"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	HTTPClient         *http.Client  `yaml:"-"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		BaseURL:            env.GetOrDefaultString(EnvBaseURL, internal.DefaultBaseURL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		BaseURL:            internal.DefaultBaseURL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+baseURL: "http://pi.hole"          # Pi-hole Web 管理地址
+password: "your_password"          # Web 管理密码或应用密码（app password）
+insecureSkipVerify: false          # 是否跳过服务器证书验证（自签名证书）
+caFile: ""                         # 校验服务器证书的 CA 文件（PEM），为空时使用系统根证书
+propagationTimeout: 60s            # 传播超时时间
+pollingInterval: 2s                # 轮询间隔时间`
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Pi-hole.
+// Credentials must be passed in the environment variable: PIHOLE_PASSWORD.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvPassword)
+	if err != nil {
+		return nil, fmt.Errorf("pihole: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Password = values[EnvPassword]
+	config.InsecureSkipVerify = env.GetOrDefaultBool(EnvInsecureSkipVerify, false)
+	config.CAFile = env.GetOrFile(EnvCAFile)
+
+	return NewDNSProviderConfig(config)
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Pi-hole.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("pihole: the configuration of the DNS provider is nil")
+	}
+
+	if config.BaseURL == "" {
+		return nil, errors.New("pihole: missing base URL")
+	}
+
+	if config.Password == "" {
+		return nil, errors.New("pihole: credentials missing")
+	}
+
+	baseURL, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("pihole: failed to parse base URL (%s): %w", config.BaseURL, err)
+	}
+
+	client := internal.NewClient(baseURL, config.Password)
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	if config.InsecureSkipVerify || config.CAFile != "" {
+		err = tlsutil.SetTLSConfig(client.HTTPClient, config.InsecureSkipVerify, config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("pihole: %w", err)
+		}
+	}
+
+	return &DNSProvider{
+		config: config,
+		client: client,
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record (custom dnsmasq line) using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.withSession(func(ctx context.Context) error {
+		return d.client.AddDnsmasqLine(ctx, txtRecordLine(info.EffectiveFQDN, info.Value))
+	})
+	if err != nil {
+		return fmt.Errorf("pihole: add TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record (custom dnsmasq line) matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	err := d.withSession(func(ctx context.Context) error {
+		return d.client.DeleteDnsmasqLine(ctx, txtRecordLine(info.EffectiveFQDN, info.Value))
+	})
+	if err != nil {
+		return fmt.Errorf("pihole: delete TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// withSession calls fn with an authenticated context, and closes the session:
+// the number of concurrent sessions of Pi-hole is limited (webserver.api.max_sessions).
+func (d *DNSProvider) withSession(fn func(ctx context.Context) error) error {
+	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	defer func() {
+		if errL := d.client.Logout(ctx); errL != nil {
+			log.Warnf("pihole: logout: %v", errL)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// txtRecordLine returns the dnsmasq option declaring the TXT record.
+func txtRecordLine(fqdn, value string) string {
+	return "txt-record=" + dns01.UnFqdn(fqdn) + "," + strconv.Quote(value)
+}
@@ -0,0 +1,196 @@
+package pihole
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/rawrecord"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvBaseURL, EnvPassword).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvBaseURL:  "https://192.168.1.2",
+				EnvPassword: "secret",
+			},
+		},
+		{
+			desc: "default base URL",
+			envVars: map[string]string{
+				EnvPassword: "secret",
+			},
+		},
+		{
+			desc:     "missing password",
+			envVars:  map[string]string{},
+			expected: "pihole: some credentials information are missing: PIHOLE_PASSWORD",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		baseURL  string
+		password string
+		expected string
+	}{
+		{
+			desc:     "success",
+			baseURL:  "https://192.168.1.2",
+			password: "secret",
+		},
+		{
+			desc:     "missing base URL",
+			password: "secret",
+			expected: "pihole: missing base URL",
+		},
+		{
+			desc:     "missing password",
+			baseURL:  "https://192.168.1.2",
+			expected: "pihole: credentials missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.BaseURL = test.baseURL
+			config.Password = test.password
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("password: secret\ninsecureSkipVerify: true\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://pi.hole", config.BaseURL)
+	assert.Equal(t, "secret", config.Password)
+	assert.True(t, config.InsecureSkipVerify)
+}
+
+func TestDNSProvider_PresentCleanUp(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		lines    = map[string]bool{}
+		sessions int
+	)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("POST /api/auth", func(rw http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		sessions++
+		mu.Unlock()
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{"session": map[string]any{"valid": true, "sid": "sid"}})
+	})
+
+	mux.HandleFunc("DELETE /api/auth", func(rw http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		sessions--
+		mu.Unlock()
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("PUT /api/config/misc/dnsmasq_lines/{line}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		lines[req.PathValue("line")] = true
+		mu.Unlock()
+
+		rw.WriteHeader(http.StatusCreated)
+	})
+
+	mux.HandleFunc("DELETE /api/config/misc/dnsmasq_lines/{line}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !lines[req.PathValue("line")] {
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"error":{"key":"not_found","message":"Item not found"}}`))
+			return
+		}
+
+		delete(lines, req.PathValue("line"))
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.Password = "secret"
+	config.HTTPClient = server.Client()
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	keyAuth := rawrecord.KeyAuth("_acme-challenge.home.example.com.", "value")
+
+	err = p.Present("home.example.com", "token", keyAuth)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{`txt-record=_acme-challenge.home.example.com,"value"`: true}, lines)
+	assert.Equal(t, 0, sessions)
+
+	err = p.CleanUp("home.example.com", "token", keyAuth)
+	require.NoError(t, err)
+
+	assert.Empty(t, lines)
+	assert.Equal(t, 0, sessions)
+
+	err = p.CleanUp("home.example.com", "token", keyAuth)
+	require.ErrorContains(t, err, "pihole: delete TXT record: ")
+	require.ErrorContains(t, err, "not_found: Item not found")
+	assert.Equal(t, 0, sessions)
+}
@@ -18,6 +18,7 @@ import (
 type Client struct {
 	login    string
 	password string
+	apiKey   string
 
 	baseURL    *url.URL
 	HTTPClient *http.Client
@@ -33,6 +34,16 @@ func NewClient(baseURL *url.URL, login string, password string) *Client {
 	}
 }
 
+// NewAPIKeyClient created a new Client authenticated with a secret key.
+// https://docs.plesk.com/en-US/obsidian/api-rpc/about-xml-api/xml-api-packets-structure/authentication.79669/
+func NewAPIKeyClient(baseURL *url.URL, apiKey string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
 // GetSite gets a site.
 // https://docs.plesk.com/en-US/obsidian/api-rpc/about-xml-api/reference/managing-sites-domains/getting-information-about-sites.66583/
 func (c Client) GetSite(ctx context.Context, domain string) (int, error) {
@@ -60,15 +71,57 @@ func (c Client) GetSite(ctx context.Context, domain string) (int, error) {
 	return response.Site.Get.Result.ID, nil
 }
 
+// GetSiteAlias gets a site alias (a domain alias of a site).
+// https://docs.plesk.com/en-US/obsidian/api-rpc/about-xml-api/reference/managing-site-aliases/retrieving-information-about-site-aliases.39947/
+func (c Client) GetSiteAlias(ctx context.Context, domain string) (int, error) {
+	payload := RequestPacketType{SiteAlias: &SiteAliasTypeRequest{Get: SiteAliasGetRequest{Filter: &SiteFilterType{
+		Name: domain,
+	}}}}
+
+	response, err := c.doRequest(ctx, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	if response.System != nil {
+		return 0, response.System
+	}
+
+	if response.SiteAlias.Get.Result == nil {
+		return 0, errors.New("unexpected empty result")
+	}
+
+	if response.SiteAlias.Get.Result.Status != StatusOK {
+		return 0, response.SiteAlias.Get.Result
+	}
+
+	return response.SiteAlias.Get.Result.ID, nil
+}
+
 // AddRecord adds a TXT record.
 // https://docs.plesk.com/en-US/obsidian/api-rpc/about-xml-api/reference/managing-dns/managing-dns-records/adding-dns-record.34798/
 func (c Client) AddRecord(ctx context.Context, siteID int, host, value string) (int, error) {
-	payload := RequestPacketType{DNS: &DNSInputType{AddRec: []AddRecRequest{{
+	return c.addRecord(ctx, AddRecRequest{
 		SiteID: siteID,
 		Type:   "TXT",
 		Host:   host,
 		Value:  value,
-	}}}}
+	})
+}
+
+// AddAliasRecord adds a TXT record to the zone of a site alias.
+// https://docs.plesk.com/en-US/obsidian/api-rpc/about-xml-api/reference/managing-dns/managing-dns-records/adding-dns-record.34798/
+func (c Client) AddAliasRecord(ctx context.Context, siteAliasID int, host, value string) (int, error) {
+	return c.addRecord(ctx, AddRecRequest{
+		SiteAliasID: siteAliasID,
+		Type:        "TXT",
+		Host:        host,
+		Value:       value,
+	})
+}
+
+func (c Client) addRecord(ctx context.Context, record AddRecRequest) (int, error) {
+	payload := RequestPacketType{DNS: &DNSInputType{AddRec: []AddRecRequest{record}}}
 
 	response, err := c.doRequest(ctx, payload)
 	if err != nil {
@@ -133,8 +186,12 @@ func (c Client) doRequest(ctx context.Context, payload RequestPacketType) (*Resp
 
 	req.Header.Set("Content-Type", "text/xml")
 
-	req.Header.Set("Http_auth_login", c.login)
-	req.Header.Set("Http_auth_passwd", c.password)
+	if c.apiKey != "" {
+		req.Header.Set("Key", c.apiKey)
+	} else {
+		req.Header.Set("Http_auth_login", c.login)
+		req.Header.Set("Http_auth_passwd", c.password)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
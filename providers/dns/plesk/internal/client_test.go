@@ -34,16 +34,23 @@ func setupTest(t *testing.T, filename string) *Client {
 			return
 		}
 
-		login := req.Header.Get("Http_auth_login")
-		if login != "user" {
-			http.Error(rw, fmt.Sprintf("invalid login: %s", login), http.StatusUnauthorized)
-			return
-		}
-
-		password := req.Header.Get("Http_auth_passwd")
-		if password != "secret" {
-			http.Error(rw, fmt.Sprintf("invalid password: %s", password), http.StatusUnauthorized)
-			return
+		if key := req.Header.Get("Key"); key != "" {
+			if key != "secret-key" {
+				http.Error(rw, fmt.Sprintf("invalid key: %s", key), http.StatusUnauthorized)
+				return
+			}
+		} else {
+			login := req.Header.Get("Http_auth_login")
+			if login != "user" {
+				http.Error(rw, fmt.Sprintf("invalid login: %s", login), http.StatusUnauthorized)
+				return
+			}
+
+			password := req.Header.Get("Http_auth_passwd")
+			if password != "secret" {
+				http.Error(rw, fmt.Sprintf("invalid password: %s", password), http.StatusUnauthorized)
+				return
+			}
 		}
 
 		file, err := os.Open(filepath.Join("fixtures", filename))
@@ -90,6 +97,48 @@ func TestClient_GetSite_system_error(t *testing.T) {
 	assert.Equal(t, 0, siteID)
 }
 
+func TestClient_GetSite_apiKey(t *testing.T) {
+	client := setupTest(t, "get-site.xml")
+	client.apiKey = "secret-key"
+
+	siteID, err := client.GetSite(context.Background(), "example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, 82, siteID)
+
+	client.apiKey = "invalid"
+
+	_, err = client.GetSite(context.Background(), "example.com")
+	require.Error(t, err)
+}
+
+func TestClient_GetSiteAlias(t *testing.T) {
+	client := setupTest(t, "get-site-alias.xml")
+
+	aliasID, err := client.GetSiteAlias(context.Background(), "alias.example.org")
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, aliasID)
+}
+
+func TestClient_GetSiteAlias_error(t *testing.T) {
+	client := setupTest(t, "get-site-alias-error.xml")
+
+	aliasID, err := client.GetSiteAlias(context.Background(), "alias.example.org")
+	require.ErrorAs(t, err, new(*SiteAliasResult))
+
+	assert.Equal(t, 0, aliasID)
+}
+
+func TestClient_AddAliasRecord(t *testing.T) {
+	client := setupTest(t, "add-record.xml")
+
+	recordID, err := client.AddAliasRecord(context.Background(), 7, "_acme-challenge", "txtTXTtxt")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4537, recordID)
+}
+
 func TestClient_AddRecord(t *testing.T) {
 	client := setupTest(t, "add-record.xml")
 
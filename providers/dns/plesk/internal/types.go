@@ -17,8 +17,9 @@ type RequestPacketType struct {
 	XMLName xml.Name `xml:"packet"`
 	Text    string   `xml:",chardata"`
 
-	DNS  *DNSInputType    `xml:"dns,omitempty"`
-	Site *SiteTypeRequest `xml:"site,omitempty"`
+	DNS       *DNSInputType         `xml:"dns,omitempty"`
+	Site      *SiteTypeRequest      `xml:"site,omitempty"`
+	SiteAlias *SiteAliasTypeRequest `xml:"site-alias,omitempty"`
 }
 
 type DNSInputType struct {
@@ -31,10 +32,11 @@ type DNSInputType struct {
 type AddRecRequest struct {
 	Text string `xml:",chardata"`
 
-	SiteID int    `xml:"site-id,omitempty"`
-	Type   string `xml:"type,omitempty"`
-	Host   string `xml:"host,omitempty"`
-	Value  string `xml:"value,omitempty"`
+	SiteID      int    `xml:"site-id,omitempty"`
+	SiteAliasID int    `xml:"site-alias-id,omitempty"`
+	Type        string `xml:"type,omitempty"`
+	Host        string `xml:"host,omitempty"`
+	Value       string `xml:"value,omitempty"`
 }
 
 type DelRecRequest struct {
@@ -68,6 +70,18 @@ type SiteFilterType struct {
 	Name string `xml:"name"`
 }
 
+type SiteAliasTypeRequest struct {
+	Text string `xml:",chardata"`
+
+	Get SiteAliasGetRequest `xml:"get"`
+}
+
+type SiteAliasGetRequest struct {
+	Text string `xml:",chardata"`
+
+	Filter *SiteFilterType `xml:"filter,omitempty"`
+}
+
 type SiteDatasetType struct {
 	Text string `xml:",chardata"`
 
@@ -97,9 +111,10 @@ type ResponsePacketType struct {
 	XMLName xml.Name `xml:"packet"`
 	Text    string   `xml:",chardata"`
 
-	DNS    DNSResponseType  `xml:"dns,omitempty"`
-	Site   SiteResponseType `xml:"site,omitempty"`
-	System *System          `xml:"system,omitempty"`
+	DNS       DNSResponseType       `xml:"dns,omitempty"`
+	Site      SiteResponseType      `xml:"site,omitempty"`
+	SiteAlias SiteAliasResponseType `xml:"site-alias,omitempty"`
+	System    *System               `xml:"system,omitempty"`
 }
 
 type System struct {
@@ -181,3 +196,40 @@ type SiteResultData struct {
 
 	GenInfo *SiteGenInfoType `xml:"gen_info"`
 }
+
+type SiteAliasResponseType struct {
+	Text string `xml:",chardata"`
+
+	Get SiteAliasGetResponse `xml:"get"`
+}
+
+type SiteAliasGetResponse struct {
+	Text string `xml:",chardata"`
+
+	Result *SiteAliasResult `xml:"result,omitempty"`
+}
+
+type SiteAliasResult struct {
+	Text string `xml:",chardata"`
+
+	ID       int    `xml:"id"`
+	FilterID string `xml:"filter-id"`
+
+	Status  string `xml:"status"`
+	ErrCode string `xml:"errcode"`
+	ErrText string `xml:"errtext"`
+
+	Info *SiteAliasInfo `xml:"info"`
+}
+
+func (s SiteAliasResult) Error() string {
+	return fmt.Sprintf("%s: %s - %s", s.Status, s.ErrCode, s.ErrText)
+}
+
+type SiteAliasInfo struct {
+	Text string `xml:",chardata"`
+
+	SiteID    int    `xml:"site-id"`
+	Name      string `xml:"name"`
+	ASCIIName string `xml:"ascii-name"`
+}
@@ -7,21 +7,27 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
+	"lego-toolbox/providers/dns/internal/tlsutil"
 	"lego-toolbox/providers/dns/plesk/internal"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "PLESK_"
 
-	EnvServerBaseURL = envNamespace + "SERVER_BASE_URL"
-	EnvUsername      = envNamespace + "USERNAME"
-	EnvPassword      = envNamespace + "PASSWORD"
+	EnvServerBaseURL      = envNamespace + "SERVER_BASE_URL"
+	EnvUsername           = envNamespace + "USERNAME"
+	EnvPassword           = envNamespace + "PASSWORD"
+	EnvAPIKey             = envNamespace + "API_KEY"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+	EnvCAFile             = envNamespace + "CA_FILE"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -31,14 +37,21 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	baseURL  string
-	Username string
-	Password string
-
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	BaseURL string `yaml:"serverBaseURL"`
+	// APIKey is a secret key created with `plesk bin secret_key`, it replaces Username and Password.
+	APIKey   string `yaml:"apiKey"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// InsecureSkipVerify accepts any server certificate, e.g. the self-signed certificate of a Plesk panel.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	// CAFile is the PEM file of the CA verifying the server certificate, the system roots are used when empty.
+	CAFile string `yaml:"caFile"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -53,61 +66,117 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                300,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+serverBaseURL: "https://plesk.myserver.com:8443"  # Plesk 服务器地址
+apiKey: ""                                        # API 密钥（plesk bin secret_key 创建），设置后替代用户名和密码
+username: "your_username"                         # API 用户名
+password: "your_password"                         # API 密码
+insecureSkipVerify: false                         # 是否跳过服务器证书验证（自签名证书）
+caFile: ""                                        # 校验服务器证书的 CA 文件（PEM），为空时使用系统根证书
+propagationTimeout: 60s                           # 传播超时时间
+pollingInterval: 2s                               # 轮询间隔时间
+ttl: 300                                          # TXT 记录的 TTL`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]int
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[int]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Plesk.
 // Credentials must be passed in the environment variables:
-// PLESK_USERNAME and PLESK_PASSWORD.
+// PLESK_API_KEY, or PLESK_USERNAME and PLESK_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvServerBaseURL, EnvUsername, EnvPassword)
+	apiKey := env.GetOrFile(EnvAPIKey)
+
+	names := []string{EnvServerBaseURL}
+	if apiKey == "" {
+		names = append(names, EnvUsername, EnvPassword)
+	}
+
+	values, err := env.Get(names...)
 	if err != nil {
 		return nil, fmt.Errorf("plesk: %w", err)
 	}
 
 	config := NewDefaultConfig()
-	config.baseURL = values[EnvServerBaseURL]
+	config.BaseURL = values[EnvServerBaseURL]
+	config.APIKey = apiKey
 	config.Username = values[EnvUsername]
 	config.Password = values[EnvPassword]
+	config.InsecureSkipVerify = env.GetOrDefaultBool(EnvInsecureSkipVerify, false)
+	config.CAFile = env.GetOrFile(EnvCAFile)
 
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for Plesk.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
 		return nil, errors.New("plesk: the configuration of the DNS provider is nil")
 	}
 
-	if config.baseURL == "" {
+	if config.BaseURL == "" {
 		return nil, errors.New("plesk: missing server base URL")
 	}
 
-	baseURL, err := url.Parse(config.baseURL)
+	baseURL, err := url.Parse(config.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("plesk: failed to parse base URL (%s): %w", config.baseURL, err)
+		return nil, fmt.Errorf("plesk: failed to parse base URL (%s): %w", config.BaseURL, err)
 	}
 
-	if config.Username == "" || config.Password == "" {
+	var client *internal.Client
+
+	switch {
+	case config.APIKey != "":
+		client = internal.NewAPIKeyClient(baseURL, config.APIKey)
+	case config.Username == "" || config.Password == "":
 		return nil, errors.New("plesk: incomplete credentials, missing username and/or password")
+	default:
+		client = internal.NewClient(baseURL, config.Username, config.Password)
 	}
 
-	client := internal.NewClient(baseURL, config.Username, config.Password)
-
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
 	}
 
+	if config.InsecureSkipVerify || config.CAFile != "" {
+		err = tlsutil.SetTLSConfig(client.HTTPClient, config.InsecureSkipVerify, config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("plesk: %w", err)
+		}
+	}
+
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: map[string]int{},
+		recordIDs: challengestore.New[int](),
 	}, nil
 }
 
@@ -117,9 +186,15 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -128,35 +203,26 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	siteID, err := d.client.GetSite(ctx, dns01.UnFqdn(authZone))
-	if err != nil {
-		return fmt.Errorf("plesk: failed to get site: %w", err)
-	}
-
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
 	if err != nil {
-		return fmt.Errorf("nodion: %w", err)
+		return fmt.Errorf("plesk: %w", err)
 	}
 
-	recordID, err := d.client.AddRecord(ctx, siteID, subDomain, info.Value)
+	recordID, err := d.addRecord(ctx, dns01.UnFqdn(authZone), subDomain, info.Value)
 	if err != nil {
-		return fmt.Errorf("plesk: failed to add record: %w", err)
+		return fmt.Errorf("plesk: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = recordID
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, recordID)
 
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("plesk: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
@@ -166,5 +232,38 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("plesk: failed to delete record (%d): %w", recordID, err)
 	}
 
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+
 	return nil
 }
+
+// addRecord adds the record to the site of the zone,
+// or to the site alias of the zone when no site matches (domain aliases have their own DNS zone).
+func (d *DNSProvider) addRecord(ctx context.Context, zone, subDomain, value string) (int, error) {
+	siteID, err := d.client.GetSite(ctx, zone)
+	if err == nil {
+		recordID, err := d.client.AddRecord(ctx, siteID, subDomain, value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add record: %w", err)
+		}
+
+		return recordID, nil
+	}
+
+	var siteErr *internal.SiteResult
+	if !errors.As(err, &siteErr) {
+		return 0, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	aliasID, aliasErr := d.client.GetSiteAlias(ctx, zone)
+	if aliasErr != nil {
+		return 0, fmt.Errorf("failed to get site: %w", errors.Join(err, aliasErr))
+	}
+
+	recordID, err := d.client.AddAliasRecord(ctx, aliasID, subDomain, value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add record to site alias: %w", err)
+	}
+
+	return recordID, nil
+}
@@ -1,10 +1,17 @@
 package plesk
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,7 +20,8 @@ const envDomain = envNamespace + "DOMAIN"
 var envTest = tester.NewEnvTest(
 	EnvServerBaseURL,
 	EnvUsername,
-	EnvPassword).
+	EnvPassword,
+	EnvAPIKey).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -30,6 +38,13 @@ func TestNewDNSProvider(t *testing.T) {
 				EnvPassword:      "secret",
 			},
 		},
+		{
+			desc: "success with API key",
+			envVars: map[string]string{
+				EnvServerBaseURL: "https//example.com",
+				EnvAPIKey:        "key",
+			},
+		},
 		{
 			desc: "missing server base URL",
 			envVars: map[string]string{
@@ -90,6 +105,8 @@ func TestNewDNSProviderConfig(t *testing.T) {
 		baseURL  string
 		username string
 		password string
+		apiKey   string
+		caFile   string
 		expected string
 	}{
 		{
@@ -98,6 +115,11 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			username: "user",
 			password: "secret",
 		},
+		{
+			desc:    "success with API key",
+			baseURL: "https://example.com",
+			apiKey:  "key",
+		},
 		{
 			desc:     "missing base URL",
 			username: "user",
@@ -121,14 +143,23 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			baseURL:  "https://example.com",
 			expected: "plesk: incomplete credentials, missing username and/or password",
 		},
+		{
+			desc:     "missing CA file",
+			baseURL:  "https://example.com",
+			apiKey:   "key",
+			caFile:   "missing.pem",
+			expected: "plesk: read CA file: open missing.pem: no such file or directory",
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
 			config := NewDefaultConfig()
-			config.baseURL = test.baseURL
+			config.BaseURL = test.baseURL
 			config.Username = test.username
 			config.Password = test.password
+			config.APIKey = test.apiKey
+			config.CAFile = test.caFile
 
 			p, err := NewDNSProviderConfig(config)
 
@@ -143,6 +174,97 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("serverBaseURL: https://plesk.example.com:8443\napiKey: key\ninsecureSkipVerify: true\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://plesk.example.com:8443", config.BaseURL)
+	assert.Equal(t, "key", config.APIKey)
+	assert.True(t, config.InsecureSkipVerify)
+	assert.Equal(t, 300, config.TTL)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	transport, ok := p.client.HTTPClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestDNSProvider_addRecord_siteAlias(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	var added string
+
+	mux.HandleFunc("POST /enterprise/control/agent.php", func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		switch {
+		case strings.Contains(string(body), "<site-alias>"):
+			_, _ = fmt.Fprint(rw, `<packet><site-alias><get><result><status>ok</status><id>7</id></result></get></site-alias></packet>`)
+		case strings.Contains(string(body), "<site>"):
+			_, _ = fmt.Fprint(rw, `<packet><site><get><result><status>error</status><errcode>1013</errcode><errtext>Site does not exist</errtext></result></get></site></packet>`)
+		case strings.Contains(string(body), "<add_rec>"):
+			added = string(body)
+			_, _ = fmt.Fprint(rw, `<packet><dns><add_rec><result><status>ok</status><id>4537</id></result></add_rec></dns></packet>`)
+		default:
+			http.Error(rw, "unexpected request", http.StatusBadRequest)
+		}
+	})
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.APIKey = "key"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	recordID, err := p.addRecord(context.Background(), "alias.example.org", "_acme-challenge", "value")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4537, recordID)
+	assert.Contains(t, added, "<site-alias-id>7</site-alias-id>")
+	assert.NotContains(t, added, "<site-id>")
+}
+
+func TestDNSProvider_CleanUp_sameTokenSeveralDomains(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	var deleted []string
+
+	mux.HandleFunc("POST /enterprise/control/agent.php", func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		deleted = append(deleted, string(body))
+
+		_, _ = fmt.Fprint(rw, `<packet><dns><del_rec><result><status>ok</status><id>1</id></result></del_rec></dns></packet>`)
+	})
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.APIKey = "key"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.recordIDs.Set("token", "_acme-challenge.example.com.", 1)
+	p.recordIDs.Set("token", "_acme-challenge.example.org.", 2)
+
+	err = p.CleanUp("example.org", "token", "")
+	require.NoError(t, err)
+
+	require.Len(t, deleted, 1)
+	assert.Contains(t, deleted[0], "<id>2</id>")
+
+	recordID, ok := p.recordIDs.Get("token", "_acme-challenge.example.com.")
+	require.True(t, ok)
+	assert.Equal(t, 1, recordID)
+	assert.Equal(t, 1, p.recordIDs.Len())
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
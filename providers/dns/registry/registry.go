@@ -0,0 +1,101 @@
+// Package registry is the single list of the DNS providers,
+// used by the YAML factory (legotoolbox.NewDNSChallengeProviderByName) and the environment factory (dns.NewDNSChallengeProviderByName).
+//
+// The providers are registered by genregistry from the provider packages:
+// adding a provider package (with its TOML description) and running `go generate ./providers/dns/registry` registers it.
+package registry
+
+//go:generate go run ../../../internal/cmd/genregistry -providers .. -out registry_gen.go
+
+import (
+	"sort"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"lego-toolbox/providers/dns/dnspod"
+	"lego-toolbox/providers/dns/tencentcloud"
+)
+
+// Provider is a DNS provider of the registry.
+type Provider struct {
+	// Name is the provider name, the Code of its TOML description.
+	Name string
+	// NewFromEnv creates the provider configured from the environment.
+	NewFromEnv func() (challenge.Provider, error)
+	// NewFromConfig creates the provider from its YAML config,
+	// configure is called with the parsed config (a pointer to the Config of the package) before the creation.
+	// It is nil for the providers configured only from the environment.
+	NewFromConfig func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error)
+	// ParseConfig parses the YAML config of the provider, nil for the providers configured only from the environment.
+	ParseConfig func(rawConfig []byte) (any, error)
+	// DefaultConfig returns the default YAML config of the provider, nil for the providers configured only from the environment.
+	DefaultConfig func() any
+	// Template returns the hand-written YAML config template of the provider,
+	// nil when the template is generated from the config.
+	Template func() string
+}
+
+// YAMLConfig reports whether the provider is configured from YAML.
+func (p Provider) YAMLConfig() bool {
+	return p.NewFromConfig != nil
+}
+
+// builtin are the providers without a provider package.
+var builtin = []Provider{
+	{
+		Name:       "manual",
+		NewFromEnv: func() (challenge.Provider, error) { return dns01.NewDNSProviderManual() },
+	},
+}
+
+// overrides replace the generated constructors of the providers built by another package.
+var overrides = map[string]func(p *Provider){
+	// the dnspod YAML config is routed through the Tencent Cloud provider.
+	"dnspod": func(p *Provider) {
+		p.NewFromConfig = func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dnspod.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return tencentcloud.NewDNSProviderFromDNSPod(cfg)
+		}
+	},
+}
+
+// providers holds the providers keyed by name.
+var providers = index(generated, builtin)
+
+func index(lists ...[]Provider) map[string]Provider {
+	m := map[string]Provider{}
+
+	for _, list := range lists {
+		for _, p := range list {
+			if override, ok := overrides[p.Name]; ok {
+				override(&p)
+			}
+
+			m[p.Name] = p
+		}
+	}
+
+	return m
+}
+
+// Get returns the provider registered under the name, the aliases are not resolved.
+func Get(name string) (Provider, bool) {
+	p, ok := providers[name]
+	return p, ok
+}
+
+// Names returns the sorted names of the providers.
+func Names() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,1939 @@
+// Code generated by genregistry; DO NOT EDIT.
+
+package registry
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/providers/dns/acmedns"
+	"lego-toolbox/providers/dns/alidns"
+	"lego-toolbox/providers/dns/allinkl"
+	"lego-toolbox/providers/dns/arvancloud"
+	"lego-toolbox/providers/dns/auroradns"
+	"lego-toolbox/providers/dns/autodns"
+	"lego-toolbox/providers/dns/azure"
+	"lego-toolbox/providers/dns/azuredns"
+	"lego-toolbox/providers/dns/bindman"
+	"lego-toolbox/providers/dns/bluecat"
+	"lego-toolbox/providers/dns/bookmyname"
+	"lego-toolbox/providers/dns/brandit"
+	"lego-toolbox/providers/dns/bunny"
+	"lego-toolbox/providers/dns/checkdomain"
+	"lego-toolbox/providers/dns/civo"
+	"lego-toolbox/providers/dns/clouddns"
+	"lego-toolbox/providers/dns/cloudflare"
+	"lego-toolbox/providers/dns/cloudns"
+	"lego-toolbox/providers/dns/cloudru"
+	"lego-toolbox/providers/dns/cloudxns"
+	"lego-toolbox/providers/dns/conoha"
+	"lego-toolbox/providers/dns/constellix"
+	"lego-toolbox/providers/dns/cpanel"
+	"lego-toolbox/providers/dns/derak"
+	"lego-toolbox/providers/dns/desec"
+	"lego-toolbox/providers/dns/designate"
+	"lego-toolbox/providers/dns/digitalocean"
+	"lego-toolbox/providers/dns/directadmin"
+	"lego-toolbox/providers/dns/dnshomede"
+	"lego-toolbox/providers/dns/dnsimple"
+	"lego-toolbox/providers/dns/dnsmadeeasy"
+	"lego-toolbox/providers/dns/dnspod"
+	"lego-toolbox/providers/dns/dode"
+	"lego-toolbox/providers/dns/domeneshop"
+	"lego-toolbox/providers/dns/dreamhost"
+	"lego-toolbox/providers/dns/duckdns"
+	"lego-toolbox/providers/dns/dyn"
+	"lego-toolbox/providers/dns/dynu"
+	"lego-toolbox/providers/dns/easydns"
+	"lego-toolbox/providers/dns/edgedns"
+	"lego-toolbox/providers/dns/efficientip"
+	"lego-toolbox/providers/dns/epik"
+	"lego-toolbox/providers/dns/exec"
+	"lego-toolbox/providers/dns/exoscale"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/providers/dns/freemyip"
+	"lego-toolbox/providers/dns/gandi"
+	"lego-toolbox/providers/dns/gandiv5"
+	"lego-toolbox/providers/dns/gcloud"
+	"lego-toolbox/providers/dns/gcore"
+	"lego-toolbox/providers/dns/glesys"
+	"lego-toolbox/providers/dns/godaddy"
+	"lego-toolbox/providers/dns/googledomains"
+	"lego-toolbox/providers/dns/hetzner"
+	"lego-toolbox/providers/dns/hostingde"
+	"lego-toolbox/providers/dns/hosttech"
+	"lego-toolbox/providers/dns/httpnet"
+	"lego-toolbox/providers/dns/httpreq"
+	"lego-toolbox/providers/dns/hurricane"
+	"lego-toolbox/providers/dns/hyperone"
+	"lego-toolbox/providers/dns/ibmcloud"
+	"lego-toolbox/providers/dns/iij"
+	"lego-toolbox/providers/dns/iijdpf"
+	"lego-toolbox/providers/dns/infoblox"
+	"lego-toolbox/providers/dns/infomaniak"
+	"lego-toolbox/providers/dns/internetbs"
+	"lego-toolbox/providers/dns/inwx"
+	"lego-toolbox/providers/dns/ionos"
+	"lego-toolbox/providers/dns/ipv64"
+	"lego-toolbox/providers/dns/iwantmyname"
+	"lego-toolbox/providers/dns/joker"
+	"lego-toolbox/providers/dns/liara"
+	"lego-toolbox/providers/dns/lightsail"
+	"lego-toolbox/providers/dns/linode"
+	"lego-toolbox/providers/dns/liquidweb"
+	"lego-toolbox/providers/dns/loopia"
+	"lego-toolbox/providers/dns/luadns"
+	"lego-toolbox/providers/dns/mailinabox"
+	"lego-toolbox/providers/dns/memdns"
+	"lego-toolbox/providers/dns/metaname"
+	"lego-toolbox/providers/dns/mydnsjp"
+	"lego-toolbox/providers/dns/mythicbeasts"
+	"lego-toolbox/providers/dns/namecheap"
+	"lego-toolbox/providers/dns/namedotcom"
+	"lego-toolbox/providers/dns/namesilo"
+	"lego-toolbox/providers/dns/nearlyfreespeech"
+	"lego-toolbox/providers/dns/netcup"
+	"lego-toolbox/providers/dns/netlify"
+	"lego-toolbox/providers/dns/nicmanager"
+	"lego-toolbox/providers/dns/nifcloud"
+	"lego-toolbox/providers/dns/njalla"
+	"lego-toolbox/providers/dns/nodion"
+	"lego-toolbox/providers/dns/ns1"
+	"lego-toolbox/providers/dns/opnsense"
+	"lego-toolbox/providers/dns/oraclecloud"
+	"lego-toolbox/providers/dns/otc"
+	"lego-toolbox/providers/dns/ovh"
+	"lego-toolbox/providers/dns/pdns"
+	"lego-toolbox/providers/dns/pdnssql"
+	"lego-toolbox/providers/dns/pihole"
+	"lego-toolbox/providers/dns/plesk"
+	"lego-toolbox/providers/dns/porkbun"
+	"lego-toolbox/providers/dns/rackspace"
+	"lego-toolbox/providers/dns/rcodezero"
+	"lego-toolbox/providers/dns/regru"
+	"lego-toolbox/providers/dns/remote"
+	"lego-toolbox/providers/dns/rfc2136"
+	"lego-toolbox/providers/dns/rimuhosting"
+	"lego-toolbox/providers/dns/route53"
+	"lego-toolbox/providers/dns/safedns"
+	"lego-toolbox/providers/dns/sakuracloud"
+	"lego-toolbox/providers/dns/scaleway"
+	"lego-toolbox/providers/dns/selectel"
+	"lego-toolbox/providers/dns/selectelv2"
+	"lego-toolbox/providers/dns/servercow"
+	"lego-toolbox/providers/dns/shellrent"
+	"lego-toolbox/providers/dns/simply"
+	"lego-toolbox/providers/dns/sonic"
+	"lego-toolbox/providers/dns/sshnsupdate"
+	"lego-toolbox/providers/dns/stackpath"
+	"lego-toolbox/providers/dns/tencentcloud"
+	"lego-toolbox/providers/dns/transip"
+	"lego-toolbox/providers/dns/ultradns"
+	"lego-toolbox/providers/dns/variomedia"
+	"lego-toolbox/providers/dns/vegadns"
+	"lego-toolbox/providers/dns/vercel"
+	"lego-toolbox/providers/dns/versio"
+	"lego-toolbox/providers/dns/vinyldns"
+	"lego-toolbox/providers/dns/vkcloud"
+	"lego-toolbox/providers/dns/vscale"
+	"lego-toolbox/providers/dns/vultr"
+	"lego-toolbox/providers/dns/webnames"
+	"lego-toolbox/providers/dns/websupport"
+	"lego-toolbox/providers/dns/wedos"
+	"lego-toolbox/providers/dns/yandex"
+	"lego-toolbox/providers/dns/yandex360"
+	"lego-toolbox/providers/dns/yandexcloud"
+	"lego-toolbox/providers/dns/zoneee"
+	"lego-toolbox/providers/dns/zonefile"
+	"lego-toolbox/providers/dns/zonomi"
+)
+
+// generated are the providers of the provider packages.
+var generated = []Provider{
+	{
+		Name:       "acme-dns",
+		NewFromEnv: func() (challenge.Provider, error) { return acmedns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := acmedns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return acmedns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return acmedns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return acmedns.DefaultConfig() },
+	},
+	{
+		Name:       "alidns",
+		NewFromEnv: func() (challenge.Provider, error) { return alidns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := alidns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return alidns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return alidns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return alidns.DefaultConfig() },
+		Template:      alidns.GetYamlTemple,
+	},
+	{
+		Name:       "allinkl",
+		NewFromEnv: func() (challenge.Provider, error) { return allinkl.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := allinkl.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return allinkl.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return allinkl.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return allinkl.DefaultConfig() },
+	},
+	{
+		Name:       "arvancloud",
+		NewFromEnv: func() (challenge.Provider, error) { return arvancloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := arvancloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return arvancloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return arvancloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return arvancloud.DefaultConfig() },
+		Template:      arvancloud.GetYamlTemple,
+	},
+	{
+		Name:       "auroradns",
+		NewFromEnv: func() (challenge.Provider, error) { return auroradns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := auroradns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return auroradns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return auroradns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return auroradns.DefaultConfig() },
+	},
+	{
+		Name:       "autodns",
+		NewFromEnv: func() (challenge.Provider, error) { return autodns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := autodns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return autodns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return autodns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return autodns.DefaultConfig() },
+	},
+	{
+		Name:       "azure",
+		NewFromEnv: func() (challenge.Provider, error) { return azure.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := azure.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return azure.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return azure.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return azure.DefaultConfig() },
+	},
+	{
+		Name:       "azuredns",
+		NewFromEnv: func() (challenge.Provider, error) { return azuredns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := azuredns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return azuredns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return azuredns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return azuredns.DefaultConfig() },
+	},
+	{
+		Name:       "bindman",
+		NewFromEnv: func() (challenge.Provider, error) { return bindman.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := bindman.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return bindman.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return bindman.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return bindman.DefaultConfig() },
+	},
+	{
+		Name:       "bluecat",
+		NewFromEnv: func() (challenge.Provider, error) { return bluecat.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := bluecat.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return bluecat.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return bluecat.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return bluecat.DefaultConfig() },
+	},
+	{
+		Name:       "bookmyname",
+		NewFromEnv: func() (challenge.Provider, error) { return bookmyname.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := bookmyname.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return bookmyname.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return bookmyname.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return bookmyname.DefaultConfig() },
+		Template:      bookmyname.GetYamlTemple,
+	},
+	{
+		Name:       "brandit",
+		NewFromEnv: func() (challenge.Provider, error) { return brandit.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := brandit.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return brandit.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return brandit.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return brandit.DefaultConfig() },
+	},
+	{
+		Name:       "bunny",
+		NewFromEnv: func() (challenge.Provider, error) { return bunny.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := bunny.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return bunny.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return bunny.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return bunny.DefaultConfig() },
+		Template:      bunny.GetYamlTemple,
+	},
+	{
+		Name:       "checkdomain",
+		NewFromEnv: func() (challenge.Provider, error) { return checkdomain.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := checkdomain.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return checkdomain.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return checkdomain.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return checkdomain.DefaultConfig() },
+	},
+	{
+		Name:       "civo",
+		NewFromEnv: func() (challenge.Provider, error) { return civo.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := civo.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return civo.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return civo.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return civo.DefaultConfig() },
+		Template:      civo.GetYamlTemple,
+	},
+	{
+		Name:       "clouddns",
+		NewFromEnv: func() (challenge.Provider, error) { return clouddns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := clouddns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return clouddns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return clouddns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return clouddns.DefaultConfig() },
+	},
+	{
+		Name:       "cloudflare",
+		NewFromEnv: func() (challenge.Provider, error) { return cloudflare.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := cloudflare.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return cloudflare.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return cloudflare.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return cloudflare.DefaultConfig() },
+	},
+	{
+		Name:       "cloudns",
+		NewFromEnv: func() (challenge.Provider, error) { return cloudns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := cloudns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return cloudns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return cloudns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return cloudns.DefaultConfig() },
+	},
+	{
+		Name:       "cloudru",
+		NewFromEnv: func() (challenge.Provider, error) { return cloudru.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := cloudru.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return cloudru.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return cloudru.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return cloudru.DefaultConfig() },
+	},
+	{
+		Name:       "cloudxns",
+		NewFromEnv: func() (challenge.Provider, error) { return cloudxns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := cloudxns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return cloudxns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return cloudxns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return cloudxns.DefaultConfig() },
+	},
+	{
+		Name:       "conoha",
+		NewFromEnv: func() (challenge.Provider, error) { return conoha.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := conoha.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return conoha.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return conoha.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return conoha.DefaultConfig() },
+	},
+	{
+		Name:       "constellix",
+		NewFromEnv: func() (challenge.Provider, error) { return constellix.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := constellix.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return constellix.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return constellix.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return constellix.DefaultConfig() },
+		Template:      constellix.GetYamlTemple,
+	},
+	{
+		Name:       "cpanel",
+		NewFromEnv: func() (challenge.Provider, error) { return cpanel.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := cpanel.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return cpanel.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return cpanel.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return cpanel.DefaultConfig() },
+		Template:      cpanel.GetYamlTemple,
+	},
+	{
+		Name:       "derak",
+		NewFromEnv: func() (challenge.Provider, error) { return derak.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := derak.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return derak.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return derak.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return derak.DefaultConfig() },
+		Template:      derak.GetYamlTemple,
+	},
+	{
+		Name:       "desec",
+		NewFromEnv: func() (challenge.Provider, error) { return desec.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := desec.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return desec.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return desec.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return desec.DefaultConfig() },
+	},
+	{
+		Name:       "designate",
+		NewFromEnv: func() (challenge.Provider, error) { return designate.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := designate.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return designate.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return designate.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return designate.DefaultConfig() },
+	},
+	{
+		Name:       "digitalocean",
+		NewFromEnv: func() (challenge.Provider, error) { return digitalocean.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := digitalocean.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return digitalocean.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return digitalocean.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return digitalocean.DefaultConfig() },
+	},
+	{
+		Name:       "directadmin",
+		NewFromEnv: func() (challenge.Provider, error) { return directadmin.NewDNSProvider() },
+	},
+	{
+		Name:       "dnshomede",
+		NewFromEnv: func() (challenge.Provider, error) { return dnshomede.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dnshomede.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dnshomede.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dnshomede.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dnshomede.DefaultConfig() },
+		Template:      dnshomede.GetYamlTemple,
+	},
+	{
+		Name:       "dnsimple",
+		NewFromEnv: func() (challenge.Provider, error) { return dnsimple.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dnsimple.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dnsimple.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dnsimple.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dnsimple.DefaultConfig() },
+	},
+	{
+		Name:       "dnsmadeeasy",
+		NewFromEnv: func() (challenge.Provider, error) { return dnsmadeeasy.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dnsmadeeasy.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dnsmadeeasy.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dnsmadeeasy.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dnsmadeeasy.DefaultConfig() },
+	},
+	{
+		Name:       "dnspod",
+		NewFromEnv: func() (challenge.Provider, error) { return dnspod.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dnspod.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dnspod.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dnspod.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dnspod.DefaultConfig() },
+		Template:      dnspod.GetYamlTemple,
+	},
+	{
+		Name:       "dode",
+		NewFromEnv: func() (challenge.Provider, error) { return dode.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dode.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dode.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dode.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dode.DefaultConfig() },
+		Template:      dode.GetYamlTemple,
+	},
+	{
+		Name:       "domeneshop",
+		NewFromEnv: func() (challenge.Provider, error) { return domeneshop.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := domeneshop.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return domeneshop.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return domeneshop.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return domeneshop.DefaultConfig() },
+	},
+	{
+		Name:       "dreamhost",
+		NewFromEnv: func() (challenge.Provider, error) { return dreamhost.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dreamhost.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dreamhost.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dreamhost.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dreamhost.DefaultConfig() },
+	},
+	{
+		Name:       "duckdns",
+		NewFromEnv: func() (challenge.Provider, error) { return duckdns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := duckdns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return duckdns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return duckdns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return duckdns.DefaultConfig() },
+	},
+	{
+		Name:       "dyn",
+		NewFromEnv: func() (challenge.Provider, error) { return dyn.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dyn.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dyn.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dyn.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dyn.DefaultConfig() },
+		Template:      dyn.GetYamlTemple,
+	},
+	{
+		Name:       "dynu",
+		NewFromEnv: func() (challenge.Provider, error) { return dynu.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := dynu.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return dynu.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return dynu.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return dynu.DefaultConfig() },
+	},
+	{
+		Name:       "easydns",
+		NewFromEnv: func() (challenge.Provider, error) { return easydns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := easydns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return easydns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return easydns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return easydns.DefaultConfig() },
+	},
+	{
+		Name:       "edgedns",
+		NewFromEnv: func() (challenge.Provider, error) { return edgedns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := edgedns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return edgedns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return edgedns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return edgedns.DefaultConfig() },
+		Template:      edgedns.GetYamlTemple,
+	},
+	{
+		Name:       "efficientip",
+		NewFromEnv: func() (challenge.Provider, error) { return efficientip.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := efficientip.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return efficientip.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return efficientip.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return efficientip.DefaultConfig() },
+		Template:      efficientip.GetYamlTemple,
+	},
+	{
+		Name:       "epik",
+		NewFromEnv: func() (challenge.Provider, error) { return epik.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := epik.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return epik.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return epik.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return epik.DefaultConfig() },
+		Template:      epik.GetYamlTemple,
+	},
+	{
+		Name:       "exec",
+		NewFromEnv: func() (challenge.Provider, error) { return exec.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := exec.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return exec.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return exec.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return exec.DefaultConfig() },
+		Template:      exec.GetYamlTemple,
+	},
+	{
+		Name:       "exoscale",
+		NewFromEnv: func() (challenge.Provider, error) { return exoscale.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := exoscale.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return exoscale.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return exoscale.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return exoscale.DefaultConfig() },
+		Template:      exoscale.GetYamlTemple,
+	},
+	{
+		Name:       "fake",
+		NewFromEnv: func() (challenge.Provider, error) { return fake.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := fake.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return fake.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return fake.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return fake.DefaultConfig() },
+		Template:      fake.GetYamlTemple,
+	},
+	{
+		Name:       "freemyip",
+		NewFromEnv: func() (challenge.Provider, error) { return freemyip.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := freemyip.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return freemyip.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return freemyip.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return freemyip.DefaultConfig() },
+		Template:      freemyip.GetYamlTemple,
+	},
+	{
+		Name:       "gandi",
+		NewFromEnv: func() (challenge.Provider, error) { return gandi.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := gandi.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return gandi.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return gandi.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return gandi.DefaultConfig() },
+		Template:      gandi.GetYamlTemple,
+	},
+	{
+		Name:       "gandiv5",
+		NewFromEnv: func() (challenge.Provider, error) { return gandiv5.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := gandiv5.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return gandiv5.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return gandiv5.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return gandiv5.DefaultConfig() },
+		Template:      gandiv5.GetYamlTemple,
+	},
+	{
+		Name:       "gcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return gcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := gcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return gcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return gcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return gcloud.DefaultConfig() },
+		Template:      gcloud.GetYamlTemple,
+	},
+	{
+		Name:       "gcore",
+		NewFromEnv: func() (challenge.Provider, error) { return gcore.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := gcore.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return gcore.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return gcore.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return gcore.DefaultConfig() },
+		Template:      gcore.GetYamlTemple,
+	},
+	{
+		Name:       "glesys",
+		NewFromEnv: func() (challenge.Provider, error) { return glesys.NewDNSProvider() },
+	},
+	{
+		Name:       "godaddy",
+		NewFromEnv: func() (challenge.Provider, error) { return godaddy.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := godaddy.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return godaddy.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return godaddy.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return godaddy.DefaultConfig() },
+		Template:      godaddy.GetYamlTemple,
+	},
+	{
+		Name:       "googledomains",
+		NewFromEnv: func() (challenge.Provider, error) { return googledomains.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := googledomains.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return googledomains.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return googledomains.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return googledomains.DefaultConfig() },
+		Template:      googledomains.GetYamlTemple,
+	},
+	{
+		Name:       "hetzner",
+		NewFromEnv: func() (challenge.Provider, error) { return hetzner.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := hetzner.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return hetzner.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return hetzner.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return hetzner.DefaultConfig() },
+		Template:      hetzner.GetYamlTemple,
+	},
+	{
+		Name:       "hostingde",
+		NewFromEnv: func() (challenge.Provider, error) { return hostingde.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := hostingde.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return hostingde.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return hostingde.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return hostingde.DefaultConfig() },
+		Template:      hostingde.GetYamlTemple,
+	},
+	{
+		Name:       "hosttech",
+		NewFromEnv: func() (challenge.Provider, error) { return hosttech.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := hosttech.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return hosttech.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return hosttech.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return hosttech.DefaultConfig() },
+		Template:      hosttech.GetYamlTemple,
+	},
+	{
+		Name:       "httpnet",
+		NewFromEnv: func() (challenge.Provider, error) { return httpnet.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := httpnet.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return httpnet.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return httpnet.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return httpnet.DefaultConfig() },
+		Template:      httpnet.GetYamlTemple,
+	},
+	{
+		Name:       "httpreq",
+		NewFromEnv: func() (challenge.Provider, error) { return httpreq.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := httpreq.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return httpreq.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return httpreq.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return httpreq.DefaultConfig() },
+		Template:      httpreq.GetYamlTemple,
+	},
+	{
+		Name:       "hurricane",
+		NewFromEnv: func() (challenge.Provider, error) { return hurricane.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := hurricane.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return hurricane.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return hurricane.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return hurricane.DefaultConfig() },
+		Template:      hurricane.GetYamlTemple,
+	},
+	{
+		Name:       "hyperone",
+		NewFromEnv: func() (challenge.Provider, error) { return hyperone.NewDNSProvider() },
+	},
+	{
+		Name:       "ibmcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return ibmcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := ibmcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return ibmcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return ibmcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return ibmcloud.DefaultConfig() },
+		Template:      ibmcloud.GetYamlTemple,
+	},
+	{
+		Name:       "iij",
+		NewFromEnv: func() (challenge.Provider, error) { return iij.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := iij.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return iij.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return iij.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return iij.DefaultConfig() },
+		Template:      iij.GetYamlTemple,
+	},
+	{
+		Name:       "iijdpf",
+		NewFromEnv: func() (challenge.Provider, error) { return iijdpf.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := iijdpf.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return iijdpf.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return iijdpf.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return iijdpf.DefaultConfig() },
+		Template:      iijdpf.GetYamlTemple,
+	},
+	{
+		Name:       "infoblox",
+		NewFromEnv: func() (challenge.Provider, error) { return infoblox.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := infoblox.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return infoblox.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return infoblox.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return infoblox.DefaultConfig() },
+		Template:      infoblox.GetYamlTemple,
+	},
+	{
+		Name:       "infomaniak",
+		NewFromEnv: func() (challenge.Provider, error) { return infomaniak.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := infomaniak.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return infomaniak.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return infomaniak.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return infomaniak.DefaultConfig() },
+		Template:      infomaniak.GetYamlTemple,
+	},
+	{
+		Name:       "internetbs",
+		NewFromEnv: func() (challenge.Provider, error) { return internetbs.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := internetbs.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return internetbs.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return internetbs.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return internetbs.DefaultConfig() },
+		Template:      internetbs.GetYamlTemple,
+	},
+	{
+		Name:       "inwx",
+		NewFromEnv: func() (challenge.Provider, error) { return inwx.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := inwx.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return inwx.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return inwx.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return inwx.DefaultConfig() },
+		Template:      inwx.GetYamlTemple,
+	},
+	{
+		Name:       "ionos",
+		NewFromEnv: func() (challenge.Provider, error) { return ionos.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := ionos.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return ionos.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return ionos.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return ionos.DefaultConfig() },
+		Template:      ionos.GetYamlTemple,
+	},
+	{
+		Name:       "ipv64",
+		NewFromEnv: func() (challenge.Provider, error) { return ipv64.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := ipv64.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return ipv64.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return ipv64.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return ipv64.DefaultConfig() },
+		Template:      ipv64.GetYamlTemple,
+	},
+	{
+		Name:       "iwantmyname",
+		NewFromEnv: func() (challenge.Provider, error) { return iwantmyname.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := iwantmyname.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return iwantmyname.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return iwantmyname.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return iwantmyname.DefaultConfig() },
+		Template:      iwantmyname.GetYamlTemple,
+	},
+	{
+		Name:       "joker",
+		NewFromEnv: func() (challenge.Provider, error) { return joker.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := joker.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return joker.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return joker.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return joker.DefaultConfig() },
+		Template:      joker.GetYamlTemple,
+	},
+	{
+		Name:       "liara",
+		NewFromEnv: func() (challenge.Provider, error) { return liara.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := liara.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return liara.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return liara.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return liara.DefaultConfig() },
+		Template:      liara.GetYamlTemple,
+	},
+	{
+		Name:       "lightsail",
+		NewFromEnv: func() (challenge.Provider, error) { return lightsail.NewDNSProvider() },
+	},
+	{
+		Name:       "linode",
+		NewFromEnv: func() (challenge.Provider, error) { return linode.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := linode.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return linode.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return linode.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return linode.DefaultConfig() },
+		Template:      linode.GetYamlTemple,
+	},
+	{
+		Name:       "liquidweb",
+		NewFromEnv: func() (challenge.Provider, error) { return liquidweb.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := liquidweb.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return liquidweb.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return liquidweb.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return liquidweb.DefaultConfig() },
+		Template:      liquidweb.GetYamlTemple,
+	},
+	{
+		Name:       "loopia",
+		NewFromEnv: func() (challenge.Provider, error) { return loopia.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := loopia.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return loopia.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return loopia.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return loopia.DefaultConfig() },
+		Template:      loopia.GetYamlTemple,
+	},
+	{
+		Name:       "luadns",
+		NewFromEnv: func() (challenge.Provider, error) { return luadns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := luadns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return luadns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return luadns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return luadns.DefaultConfig() },
+		Template:      luadns.GetYamlTemple,
+	},
+	{
+		Name:       "mailinabox",
+		NewFromEnv: func() (challenge.Provider, error) { return mailinabox.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := mailinabox.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return mailinabox.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return mailinabox.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return mailinabox.DefaultConfig() },
+		Template:      mailinabox.GetYamlTemple,
+	},
+	{
+		Name:       "memdns",
+		NewFromEnv: func() (challenge.Provider, error) { return memdns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := memdns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return memdns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return memdns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return memdns.DefaultConfig() },
+		Template:      memdns.GetYamlTemple,
+	},
+	{
+		Name:       "metaname",
+		NewFromEnv: func() (challenge.Provider, error) { return metaname.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := metaname.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return metaname.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return metaname.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return metaname.DefaultConfig() },
+		Template:      metaname.GetYamlTemple,
+	},
+	{
+		Name:       "mydnsjp",
+		NewFromEnv: func() (challenge.Provider, error) { return mydnsjp.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := mydnsjp.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return mydnsjp.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return mydnsjp.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return mydnsjp.DefaultConfig() },
+		Template:      mydnsjp.GetYamlTemple,
+	},
+	{
+		Name:       "mythicbeasts",
+		NewFromEnv: func() (challenge.Provider, error) { return mythicbeasts.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := mythicbeasts.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return mythicbeasts.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return mythicbeasts.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return mythicbeasts.DefaultConfig() },
+		Template:      mythicbeasts.GetYamlTemple,
+	},
+	{
+		Name:       "namecheap",
+		NewFromEnv: func() (challenge.Provider, error) { return namecheap.NewDNSProvider() },
+	},
+	{
+		Name:       "namedotcom",
+		NewFromEnv: func() (challenge.Provider, error) { return namedotcom.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := namedotcom.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return namedotcom.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return namedotcom.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return namedotcom.DefaultConfig() },
+		Template:      namedotcom.GetYamlTemple,
+	},
+	{
+		Name:       "namesilo",
+		NewFromEnv: func() (challenge.Provider, error) { return namesilo.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := namesilo.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return namesilo.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return namesilo.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return namesilo.DefaultConfig() },
+		Template:      namesilo.GetYamlTemple,
+	},
+	{
+		Name:       "nearlyfreespeech",
+		NewFromEnv: func() (challenge.Provider, error) { return nearlyfreespeech.NewDNSProvider() },
+	},
+	{
+		Name:       "netcup",
+		NewFromEnv: func() (challenge.Provider, error) { return netcup.NewDNSProvider() },
+	},
+	{
+		Name:       "netlify",
+		NewFromEnv: func() (challenge.Provider, error) { return netlify.NewDNSProvider() },
+	},
+	{
+		Name:       "nicmanager",
+		NewFromEnv: func() (challenge.Provider, error) { return nicmanager.NewDNSProvider() },
+	},
+	{
+		Name:       "nifcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return nifcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := nifcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return nifcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return nifcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return nifcloud.DefaultConfig() },
+		Template:      nifcloud.GetYamlTemple,
+	},
+	{
+		Name:       "njalla",
+		NewFromEnv: func() (challenge.Provider, error) { return njalla.NewDNSProvider() },
+	},
+	{
+		Name:       "nodion",
+		NewFromEnv: func() (challenge.Provider, error) { return nodion.NewDNSProvider() },
+	},
+	{
+		Name:       "ns1",
+		NewFromEnv: func() (challenge.Provider, error) { return ns1.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := ns1.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return ns1.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return ns1.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return ns1.DefaultConfig() },
+		Template:      ns1.GetYamlTemple,
+	},
+	{
+		Name:       "opnsense",
+		NewFromEnv: func() (challenge.Provider, error) { return opnsense.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := opnsense.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return opnsense.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return opnsense.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return opnsense.DefaultConfig() },
+		Template:      opnsense.GetYamlTemple,
+	},
+	{
+		Name:       "oraclecloud",
+		NewFromEnv: func() (challenge.Provider, error) { return oraclecloud.NewDNSProvider() },
+	},
+	{
+		Name:       "otc",
+		NewFromEnv: func() (challenge.Provider, error) { return otc.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := otc.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return otc.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return otc.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return otc.DefaultConfig() },
+		Template:      otc.GetYamlTemple,
+	},
+	{
+		Name:       "ovh",
+		NewFromEnv: func() (challenge.Provider, error) { return ovh.NewDNSProvider() },
+	},
+	{
+		Name:       "pdns",
+		NewFromEnv: func() (challenge.Provider, error) { return pdns.NewDNSProvider() },
+	},
+	{
+		Name:       "pdnssql",
+		NewFromEnv: func() (challenge.Provider, error) { return pdnssql.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := pdnssql.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return pdnssql.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return pdnssql.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return pdnssql.DefaultConfig() },
+		Template:      pdnssql.GetYamlTemple,
+	},
+	{
+		Name:       "pihole",
+		NewFromEnv: func() (challenge.Provider, error) { return pihole.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := pihole.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return pihole.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return pihole.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return pihole.DefaultConfig() },
+		Template:      pihole.GetYamlTemple,
+	},
+	{
+		Name:       "plesk",
+		NewFromEnv: func() (challenge.Provider, error) { return plesk.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := plesk.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return plesk.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return plesk.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return plesk.DefaultConfig() },
+		Template:      plesk.GetYamlTemple,
+	},
+	{
+		Name:       "porkbun",
+		NewFromEnv: func() (challenge.Provider, error) { return porkbun.NewDNSProvider() },
+	},
+	{
+		Name:       "rackspace",
+		NewFromEnv: func() (challenge.Provider, error) { return rackspace.NewDNSProvider() },
+	},
+	{
+		Name:       "rcodezero",
+		NewFromEnv: func() (challenge.Provider, error) { return rcodezero.NewDNSProvider() },
+	},
+	{
+		Name:       "regru",
+		NewFromEnv: func() (challenge.Provider, error) { return regru.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := regru.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return regru.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return regru.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return regru.DefaultConfig() },
+		Template:      regru.GetYamlTemple,
+	},
+	{
+		Name:       "remote",
+		NewFromEnv: func() (challenge.Provider, error) { return remote.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := remote.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return remote.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return remote.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return remote.DefaultConfig() },
+		Template:      remote.GetYamlTemple,
+	},
+	{
+		Name:       "rfc2136",
+		NewFromEnv: func() (challenge.Provider, error) { return rfc2136.NewDNSProvider() },
+	},
+	{
+		Name:       "rimuhosting",
+		NewFromEnv: func() (challenge.Provider, error) { return rimuhosting.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := rimuhosting.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return rimuhosting.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return rimuhosting.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return rimuhosting.DefaultConfig() },
+		Template:      rimuhosting.GetYamlTemple,
+	},
+	{
+		Name:       "route53",
+		NewFromEnv: func() (challenge.Provider, error) { return route53.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := route53.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return route53.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return route53.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return route53.DefaultConfig() },
+		Template:      route53.GetYamlTemple,
+	},
+	{
+		Name:       "safedns",
+		NewFromEnv: func() (challenge.Provider, error) { return safedns.NewDNSProvider() },
+	},
+	{
+		Name:       "sakuracloud",
+		NewFromEnv: func() (challenge.Provider, error) { return sakuracloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := sakuracloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return sakuracloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return sakuracloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return sakuracloud.DefaultConfig() },
+		Template:      sakuracloud.GetYamlTemple,
+	},
+	{
+		Name:       "scaleway",
+		NewFromEnv: func() (challenge.Provider, error) { return scaleway.NewDNSProvider() },
+	},
+	{
+		Name:       "selectel",
+		NewFromEnv: func() (challenge.Provider, error) { return selectel.NewDNSProvider() },
+	},
+	{
+		Name:       "selectelv2",
+		NewFromEnv: func() (challenge.Provider, error) { return selectelv2.NewDNSProvider() },
+	},
+	{
+		Name:       "servercow",
+		NewFromEnv: func() (challenge.Provider, error) { return servercow.NewDNSProvider() },
+	},
+	{
+		Name:       "shellrent",
+		NewFromEnv: func() (challenge.Provider, error) { return shellrent.NewDNSProvider() },
+	},
+	{
+		Name:       "simply",
+		NewFromEnv: func() (challenge.Provider, error) { return simply.NewDNSProvider() },
+	},
+	{
+		Name:       "sonic",
+		NewFromEnv: func() (challenge.Provider, error) { return sonic.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := sonic.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return sonic.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return sonic.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return sonic.DefaultConfig() },
+		Template:      sonic.GetYamlTemple,
+	},
+	{
+		Name:       "sshnsupdate",
+		NewFromEnv: func() (challenge.Provider, error) { return sshnsupdate.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := sshnsupdate.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return sshnsupdate.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return sshnsupdate.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return sshnsupdate.DefaultConfig() },
+		Template:      sshnsupdate.GetYamlTemple,
+	},
+	{
+		Name:       "stackpath",
+		NewFromEnv: func() (challenge.Provider, error) { return stackpath.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := stackpath.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return stackpath.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return stackpath.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return stackpath.DefaultConfig() },
+		Template:      stackpath.GetYamlTemple,
+	},
+	{
+		Name:       "tencentcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return tencentcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := tencentcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return tencentcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return tencentcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return tencentcloud.DefaultConfig() },
+		Template:      tencentcloud.GetYamlTemple,
+	},
+	{
+		Name:       "transip",
+		NewFromEnv: func() (challenge.Provider, error) { return transip.NewDNSProvider() },
+	},
+	{
+		Name:       "ultradns",
+		NewFromEnv: func() (challenge.Provider, error) { return ultradns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := ultradns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return ultradns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return ultradns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return ultradns.DefaultConfig() },
+		Template:      ultradns.GetYamlTemple,
+	},
+	{
+		Name:       "variomedia",
+		NewFromEnv: func() (challenge.Provider, error) { return variomedia.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := variomedia.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return variomedia.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return variomedia.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return variomedia.DefaultConfig() },
+		Template:      variomedia.GetYamlTemple,
+	},
+	{
+		Name:       "vegadns",
+		NewFromEnv: func() (challenge.Provider, error) { return vegadns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vegadns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vegadns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vegadns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vegadns.DefaultConfig() },
+		Template:      vegadns.GetYamlTemple,
+	},
+	{
+		Name:       "vercel",
+		NewFromEnv: func() (challenge.Provider, error) { return vercel.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vercel.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vercel.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vercel.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vercel.DefaultConfig() },
+		Template:      vercel.GetYamlTemple,
+	},
+	{
+		Name:       "versio",
+		NewFromEnv: func() (challenge.Provider, error) { return versio.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := versio.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return versio.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return versio.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return versio.DefaultConfig() },
+		Template:      versio.GetYamlTemple,
+	},
+	{
+		Name:       "vinyldns",
+		NewFromEnv: func() (challenge.Provider, error) { return vinyldns.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vinyldns.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vinyldns.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vinyldns.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vinyldns.DefaultConfig() },
+		Template:      vinyldns.GetYamlTemple,
+	},
+	{
+		Name:       "vkcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return vkcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vkcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vkcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vkcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vkcloud.DefaultConfig() },
+		Template:      vkcloud.GetYamlTemple,
+	},
+	{
+		Name:       "vscale",
+		NewFromEnv: func() (challenge.Provider, error) { return vscale.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vscale.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vscale.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vscale.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vscale.DefaultConfig() },
+		Template:      vscale.GetYamlTemple,
+	},
+	{
+		Name:       "vultr",
+		NewFromEnv: func() (challenge.Provider, error) { return vultr.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := vultr.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return vultr.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return vultr.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return vultr.DefaultConfig() },
+		Template:      vultr.GetYamlTemple,
+	},
+	{
+		Name:       "webnames",
+		NewFromEnv: func() (challenge.Provider, error) { return webnames.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := webnames.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return webnames.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return webnames.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return webnames.DefaultConfig() },
+		Template:      webnames.GetYamlTemple,
+	},
+	{
+		Name:       "websupport",
+		NewFromEnv: func() (challenge.Provider, error) { return websupport.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := websupport.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return websupport.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return websupport.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return websupport.DefaultConfig() },
+		Template:      websupport.GetYamlTemple,
+	},
+	{
+		Name:       "wedos",
+		NewFromEnv: func() (challenge.Provider, error) { return wedos.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := wedos.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return wedos.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return wedos.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return wedos.DefaultConfig() },
+		Template:      wedos.GetYamlTemple,
+	},
+	{
+		Name:       "yandex",
+		NewFromEnv: func() (challenge.Provider, error) { return yandex.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := yandex.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return yandex.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return yandex.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return yandex.DefaultConfig() },
+		Template:      yandex.GetYamlTemple,
+	},
+	{
+		Name:       "yandex360",
+		NewFromEnv: func() (challenge.Provider, error) { return yandex360.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := yandex360.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return yandex360.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return yandex360.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return yandex360.DefaultConfig() },
+		Template:      yandex360.GetYamlTemple,
+	},
+	{
+		Name:       "yandexcloud",
+		NewFromEnv: func() (challenge.Provider, error) { return yandexcloud.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := yandexcloud.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return yandexcloud.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return yandexcloud.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return yandexcloud.DefaultConfig() },
+		Template:      yandexcloud.GetYamlTemple,
+	},
+	{
+		Name:       "zoneee",
+		NewFromEnv: func() (challenge.Provider, error) { return zoneee.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := zoneee.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return zoneee.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return zoneee.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return zoneee.DefaultConfig() },
+		Template:      zoneee.GetYamlTemple,
+	},
+	{
+		Name:       "zonefile",
+		NewFromEnv: func() (challenge.Provider, error) { return zonefile.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := zonefile.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return zonefile.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return zonefile.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return zonefile.DefaultConfig() },
+		Template:      zonefile.GetYamlTemple,
+	},
+	{
+		Name:       "zonomi",
+		NewFromEnv: func() (challenge.Provider, error) { return zonomi.NewDNSProvider() },
+		NewFromConfig: func(rawConfig []byte, configure func(cfg any)) (challenge.Provider, error) {
+			cfg, err := zonomi.ParseConfig(rawConfig)
+			if err != nil {
+				return nil, err
+			}
+			configure(cfg)
+			return zonomi.NewDNSProviderConfig(cfg)
+		},
+		ParseConfig:   func(rawConfig []byte) (any, error) { return zonomi.ParseConfig(rawConfig) },
+		DefaultConfig: func() any { return zonomi.DefaultConfig() },
+		Template:      zonomi.GetYamlTemple,
+	},
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNames(t *testing.T) {
+	names := Names()
+
+	assert.IsIncreasing(t, names)
+	assert.Contains(t, names, "acme-dns")
+	assert.Contains(t, names, "directadmin")
+	assert.Contains(t, names, "manual")
+}
+
+func TestGet(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			p, ok := Get(name)
+			require.True(t, ok)
+
+			assert.Equal(t, name, p.Name)
+			assert.NotNil(t, p.NewFromEnv)
+
+			if !p.YAMLConfig() {
+				assert.Nil(t, p.ParseConfig)
+				assert.Nil(t, p.DefaultConfig)
+				assert.Nil(t, p.Template)
+
+				return
+			}
+
+			assert.NotNil(t, p.ParseConfig)
+			assert.NotNil(t, p.DefaultConfig())
+		})
+	}
+}
+
+func TestGet_unknown(t *testing.T) {
+	_, ok := Get("fastdns")
+	assert.False(t, ok)
+}
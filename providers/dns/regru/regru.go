@@ -6,12 +6,17 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"lego-toolbox/yamlconfig"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/tlsutil"
 	"lego-toolbox/providers/dns/regru/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -31,15 +36,17 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Username string
-	Password string
-	TLSCert  string
-	TLSKey   string
-
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// TLSCert and TLSKey are the client certificate allowed to use the API, PEM contents or PEM file paths.
+	// The API requires an allow-listed IP or a client certificate.
+	TLSCert string `yaml:"tlsCert"`
+	TLSKey  string `yaml:"tlsKey"`
+
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -54,6 +61,29 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                300,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+username: "your_username"               # API 用户名
+password: "your_password"               # API 密码
+tlsCert: "/etc/lego-toolbox/regru.crt"  # 客户端证书（PEM 内容或文件路径），API 要求白名单 IP 或客户端证书
+tlsKey: "/etc/lego-toolbox/regru.key"   # 客户端证书私钥（PEM 内容或文件路径）
+propagationTimeout: 60s                 # 传播超时时间
+pollingInterval: 2s                     # 轮询间隔时间
+ttl: 300                                # TXT 记录的生存时间（秒）`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -72,12 +102,22 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config := NewDefaultConfig()
 	config.Username = values[EnvUsername]
 	config.Password = values[EnvPassword]
-	config.TLSCert = env.GetOrDefaultString(EnvTLSCert, "")
-	config.TLSKey = env.GetOrDefaultString(EnvTLSKey, "")
+	config.TLSCert = env.GetOrFile(EnvTLSCert)
+	config.TLSKey = env.GetOrFile(EnvTLSKey)
 
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for reg.ru.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -103,16 +143,22 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 			return nil, errors.New("regru: TLS key is missing")
 		}
 
-		tlsCert, err := tls.X509KeyPair([]byte(config.TLSCert), []byte(config.TLSKey))
+		certPEM, err := readPEM(config.TLSCert)
 		if err != nil {
-			return nil, fmt.Errorf("regru: %w", err)
+			return nil, fmt.Errorf("regru: TLS certificate: %w", err)
+		}
+
+		keyPEM, err := readPEM(config.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("regru: TLS key: %w", err)
 		}
 
-		client.HTTPClient.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				Certificates: []tls.Certificate{tlsCert},
-			},
+		tlsCert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("regru: %w", err)
 		}
+
+		tlsutil.SetClientCertificate(client.HTTPClient, tlsCert)
 	}
 
 	return &DNSProvider{config: config, client: client}, nil
@@ -126,7 +172,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -149,7 +195,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -169,3 +215,12 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// readPEM returns the PEM content, or the content of the PEM file.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN ") {
+		return []byte(value), nil
+	}
+
+	return os.ReadFile(value)
+}
@@ -1,10 +1,21 @@
 package regru
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +23,9 @@ const envDomain = envNamespace + "DOMAIN"
 
 var envTest = tester.NewEnvTest(
 	EnvUsername,
-	EnvPassword).
+	EnvPassword,
+	EnvTLSCert,
+	EnvTLSKey).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -150,3 +163,80 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestParseConfig(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	rawConfig := []byte("username: user\npassword: secret\ntlsCert: " + certFile + "\ntlsKey: " + keyFile + "\nttl: 600\n")
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user", config.Username)
+	assert.Equal(t, "secret", config.Password)
+	assert.Equal(t, 600, config.TTL)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	transport, ok := p.client.HTTPClient.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.Len(t, transport.TLSClientConfig.Certificates, 1)
+}
+
+func TestNewDNSProviderConfig_tlsContent(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+
+	keyPEM, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	config.Username = "user"
+	config.Password = "secret"
+	config.TLSCert = string(certPEM)
+	config.TLSKey = string(keyPEM)
+
+	_, err = NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	config.TLSKey = filepath.Join(t.TempDir(), "missing.key")
+
+	_, err = NewDNSProviderConfig(config)
+	require.ErrorContains(t, err, "regru: TLS key: ")
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key.
+func writeClientCertificate(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
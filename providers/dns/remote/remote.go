@@ -0,0 +1,239 @@
+// Package remote implements a DNS provider calling a lego-toolbox gRPC server (see grpcremote),
+// which holds the credentials of the actual provider.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"lego-toolbox/grpcremote"
+	"lego-toolbox/yamlconfig"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "REMOTE_"
+
+	EnvAddress    = envNamespace + "ADDRESS"
+	EnvProvider   = envNamespace + "PROVIDER"
+	EnvTLSCert    = envNamespace + "TLS_CERT"
+	EnvTLSKey     = envNamespace + "TLS_KEY"
+	EnvTLSCA      = envNamespace + "TLS_CA"
+	EnvServerName = envNamespace + "SERVER_NAME"
+	EnvInsecure   = envNamespace + "INSECURE"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvRequestTimeout     = envNamespace + "REQUEST_TIMEOUT"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// Address is the gRPC target of the server.
+	Address string `yaml:"address"`
+	// Provider is the name of the provider on the server, empty for its default provider.
+	Provider string `yaml:"provider"`
+
+	// TLSCert and TLSKey are the client certificate used for mTLS.
+	TLSCert string `yaml:"tlsCert"`
+	TLSKey  string `yaml:"tlsKey"`
+	// TLSCA is the CA verifying the server certificate, the system roots are used when empty.
+	TLSCA      string `yaml:"tlsCA"`
+	ServerName string `yaml:"serverName"`
+	// Insecure disables TLS, only for local connections.
+	Insecure bool `yaml:"insecure"`
+
+	// PropagationTimeout and PollingInterval are fetched from the server when zero.
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	RequestTimeout     time.Duration `yaml:"requestTimeout"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 0),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 0),
+		RequestTimeout:     env.GetOrDefaultSecond(EnvRequestTimeout, 30*time.Second),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+address: "dns-credentials.example.com:9443"  # gRPC 服务端地址
+provider: ""                                  # 服务端上的 provider 名称，为空时使用默认 provider
+tlsCert: "/etc/lego-toolbox/client.crt"       # mTLS 客户端证书
+tlsKey: "/etc/lego-toolbox/client.key"        # mTLS 客户端私钥
+tlsCA: "/etc/lego-toolbox/ca.crt"             # 校验服务端证书的 CA，为空时使用系统根证书
+serverName: ""                                # 服务端证书名称，为空时使用地址中的主机名
+insecure: false                               # 禁用 TLS，仅用于本地连接
+propagationTimeout: 0s                        # 传播超时时间，为 0 时使用服务端的值
+pollingInterval: 0s                           # 轮询间隔时间，为 0 时使用服务端的值
+requestTimeout: 30s                           # 请求超时时间`
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	conn   *grpc.ClientConn
+	client *grpcremote.Client
+
+	timeoutOnce sync.Once
+	timeout     time.Duration
+	interval    time.Duration
+}
+
+// NewDNSProvider returns a DNSProvider instance configured from the environment.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvAddress)
+	if err != nil {
+		return nil, fmt.Errorf("remote: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Address = values[EnvAddress]
+	config.Provider = env.GetOrFile(EnvProvider)
+	config.TLSCert = env.GetOrFile(EnvTLSCert)
+	config.TLSKey = env.GetOrFile(EnvTLSKey)
+	config.TLSCA = env.GetOrFile(EnvTLSCA)
+	config.ServerName = env.GetOrFile(EnvServerName)
+	config.Insecure = env.GetOrDefaultBool(EnvInsecure, false)
+
+	return NewDNSProviderConfig(config)
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for remote.
+// The connection is established lazily and released by Close.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("remote: the configuration of the DNS provider is nil")
+	}
+
+	if config.Address == "" {
+		return nil, errors.New("remote: the address is missing")
+	}
+
+	creds := insecure.NewCredentials()
+	if !config.Insecure {
+		tlsConfig, err := grpcremote.ClientTLSConfig(config.TLSCert, config.TLSKey, config.TLSCA, config.ServerName)
+		if err != nil {
+			return nil, fmt.Errorf("remote: %w", err)
+		}
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(config.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("remote: %w", err)
+	}
+
+	return &DNSProvider{
+		config: config,
+		conn:   conn,
+		client: grpcremote.NewClient(conn),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// The values missing from the configuration are fetched from the server once,
+// the lego defaults are used if the server is unreachable.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	d.timeoutOnce.Do(func() {
+		d.timeout, d.interval = d.config.PropagationTimeout, d.config.PollingInterval
+		if d.timeout > 0 && d.interval > 0 {
+			return
+		}
+
+		ctx, cancel := d.context()
+		defer cancel()
+
+		timeout, interval, err := d.client.Timeout(ctx, d.config.Provider)
+		if err != nil {
+			timeout, interval = dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+		}
+
+		if d.timeout <= 0 {
+			d.timeout = timeout
+		}
+
+		if d.interval <= 0 {
+			d.interval = interval
+		}
+	})
+
+	return d.timeout, d.interval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	err := d.client.Present(ctx, d.request(domain, token, keyAuth))
+	if err != nil {
+		return fmt.Errorf("remote: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	err := d.client.CleanUp(ctx, d.request(domain, token, keyAuth))
+	if err != nil {
+		return fmt.Errorf("remote: %w", err)
+	}
+
+	return nil
+}
+
+// Close releases the connection to the server.
+func (d *DNSProvider) Close() error {
+	return d.conn.Close()
+}
+
+func (d *DNSProvider) request(domain, token, keyAuth string) *grpcremote.ChallengeRequest {
+	return &grpcremote.ChallengeRequest{
+		Provider: d.config.Provider,
+		Domain:   domain,
+		Token:    token,
+		KeyAuth:  keyAuth,
+	}
+}
+
+func (d *DNSProvider) context() (context.Context, context.CancelFunc) {
+	if d.config.RequestTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), d.config.RequestTimeout)
+}
@@ -0,0 +1,223 @@
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"lego-toolbox/grpcremote"
+	"lego-toolbox/providers/dns/fake"
+)
+
+var envTest = tester.NewEnvTest(EnvAddress, EnvProvider, EnvTLSCert, EnvTLSKey, EnvTLSCA, EnvServerName, EnvInsecure)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvAddress:  "localhost:9443",
+				EnvInsecure: "true",
+			},
+		},
+		{
+			desc: "missing address",
+			envVars: map[string]string{
+				EnvAddress: "",
+			},
+			expected: "remote: some credentials information are missing: REMOTE_ADDRESS",
+		},
+		{
+			desc: "missing client key",
+			envVars: map[string]string{
+				EnvAddress: "localhost:9443",
+				EnvTLSCert: "client.crt",
+			},
+			expected: "remote: load certificate: open client.crt: no such file or directory",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NoError(t, p.Close())
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("address: localhost:9443\nprovider: prod\npollingInterval: 5s\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost:9443", config.Address)
+	assert.Equal(t, "prod", config.Provider)
+	assert.Equal(t, 5*time.Second, config.PollingInterval)
+	assert.Equal(t, 30*time.Second, config.RequestTimeout)
+}
+
+func TestDNSProvider_mTLS(t *testing.T) {
+	dir := t.TempDir()
+	writeCertificates(t, dir)
+
+	serverTLS, err := grpcremote.ServerTLSConfig(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	fakeConfig := fake.DefaultConfig()
+	fakeConfig.PropagationTimeout = 42 * time.Second
+
+	inner, err := fake.NewDNSProviderConfig(fakeConfig)
+	require.NoError(t, err)
+
+	address := serve(t, serverTLS, map[string]challenge.Provider{"prod": inner})
+
+	config := DefaultConfig()
+	config.Address = address
+	config.Provider = "prod"
+	config.TLSCert = filepath.Join(dir, "client.crt")
+	config.TLSKey = filepath.Join(dir, "client.key")
+	config.TLSCA = filepath.Join(dir, "ca.crt")
+	config.ServerName = "localhost"
+	config.PollingInterval = 3 * time.Second
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	assert.Len(t, inner.TXT("_acme-challenge.example.com."), 1)
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+	assert.Empty(t, inner.TXT("_acme-challenge.example.com."))
+
+	timeout, interval := provider.Timeout()
+	assert.Equal(t, 42*time.Second, timeout)
+	assert.Equal(t, 3*time.Second, interval)
+}
+
+func TestDNSProvider_clientCertificateRequired(t *testing.T) {
+	dir := t.TempDir()
+	writeCertificates(t, dir)
+
+	serverTLS, err := grpcremote.ServerTLSConfig(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	config.Address = serve(t, serverTLS, map[string]challenge.Provider{"": inner})
+	config.TLSCA = filepath.Join(dir, "ca.crt")
+	config.ServerName = "localhost"
+	config.RequestTimeout = 5 * time.Second
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	require.Error(t, provider.Present("example.com", "token", "keyAuth"))
+	inner.AssertCallCount(t, fake.CallPresent, 0)
+}
+
+func serve(t *testing.T, tlsConfig *tls.Config, providers map[string]challenge.Provider) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	grpcremote.NewServer(grpcremote.Providers(providers)).Register(server)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// writeCertificates writes a CA, a server certificate for localhost and a client certificate.
+func writeCertificates(t *testing.T, dir string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", caDER)
+
+	for i, name := range []string{"server", "client"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		writePEM(t, filepath.Join(dir, name+".crt"), "CERTIFICATE", der)
+		writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+	}
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+
+	err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+}
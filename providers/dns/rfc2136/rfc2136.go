@@ -11,6 +11,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/miekg/dns"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -127,7 +128,7 @@ func (d *DNSProvider) Sequential() time.Duration {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.changeRecord("INSERT", info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
@@ -138,7 +139,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.changeRecord("REMOVE", info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
@@ -174,6 +175,38 @@ func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 		return fmt.Errorf("unexpected action: %s", action)
 	}
 
+	return d.send(m)
+}
+
+// SetCNAME creates the CNAME record from fqdn to target, replacing the existing one.
+func (d *DNSProvider) SetCNAME(fqdn, target string) error {
+	fqdn = dns.Fqdn(fqdn)
+
+	zone, err := dns01.FindZoneByFqdnCustom(fqdn, []string{d.config.Nameserver})
+	if err != nil {
+		return fmt.Errorf("rfc2136: %w", err)
+	}
+
+	rr := &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: fqdn, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: uint32(d.config.TTL)},
+		Target: dns.Fqdn(target),
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(zone)
+	m.RemoveRRset([]dns.RR{rr})
+	m.Insert([]dns.RR{rr})
+
+	err = d.send(m)
+	if err != nil {
+		return fmt.Errorf("rfc2136: %w", err)
+	}
+
+	return nil
+}
+
+// send sends the dynamic update packet, signed when a TSIG key is configured.
+func (d *DNSProvider) send(m *dns.Msg) error {
 	// Setup client
 	c := &dns.Client{Timeout: d.config.DNSTimeout}
 
@@ -157,6 +157,38 @@ func TestValidUpdatePacket(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_SetCNAME(t *testing.T) {
+	reqChan := make(chan *dns.Msg, 10)
+
+	dns01.ClearFqdnCache()
+	dns.HandleFunc(fakeZone, serverHandlerPassBackRequest(reqChan))
+	defer dns.HandleRemove(fakeZone)
+
+	server, addr, err := runLocalDNSTestServer(false)
+	require.NoError(t, err, "Failed to start test server")
+	defer func() { _ = server.Shutdown() }()
+
+	config := NewDefaultConfig()
+	config.Nameserver = addr
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.SetCNAME(fakeFqdn, "123456789.www.example.com.acme.example.net")
+	require.NoError(t, err)
+
+	rcvMsg := <-reqChan
+
+	require.Len(t, rcvMsg.Ns, 2)
+	assert.Equal(t, uint16(dns.ClassANY), rcvMsg.Ns[0].Header().Class)
+	assert.Equal(t, dns.TypeCNAME, rcvMsg.Ns[0].Header().Rrtype)
+
+	cname, ok := rcvMsg.Ns[1].(*dns.CNAME)
+	require.True(t, ok)
+	assert.Equal(t, fakeFqdn, cname.Hdr.Name)
+	assert.Equal(t, "123456789.www.example.com.acme.example.net.", cname.Target)
+}
+
 func runLocalDNSTestServer(tsig bool) (*dns.Server, string, error) {
 	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
 	if err != nil {
@@ -5,19 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/internal/rimuhosting"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "RIMUHOSTING_"
 
-	EnvAPIKey = envNamespace + "API_KEY"
+	EnvAPIKey  = envNamespace + "API_KEY"
+	EnvBaseURL = envNamespace + "BASE_URL"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -27,17 +30,18 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey string
-
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	APIKey             string        `yaml:"apiKey"`
+	BaseURL            string        `yaml:"baseURL"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		BaseURL:            env.GetOrDefaultString(EnvBaseURL, rimuhosting.DefaultRimuHostingBaseURL),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 3600),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -47,6 +51,28 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		BaseURL:            rimuhosting.DefaultRimuHostingBaseURL,
+		TTL:                3600,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+apiKey: "your_api_key"                      # API 密钥
+baseURL: "https://rimuhosting.com/dns/dyndns.jsp"  # API 地址（兼容 Zonomi API 的服务商）
+propagationTimeout: 60s                     # 传播超时时间，单位为秒
+pollingInterval: 2s                         # 轮询间隔时间，单位为秒
+ttl: 3600                                   # TTL 值`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -67,6 +93,16 @@ func NewDNSProvider() (*DNSProvider, error) {
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for RimuHosting.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -78,7 +114,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	client := rimuhosting.NewClient(config.APIKey)
-	client.BaseURL = rimuhosting.DefaultRimuHostingBaseURL
+
+	if config.BaseURL != "" {
+		client.BaseURL = config.BaseURL
+	}
 
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
@@ -94,25 +133,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 }
 
 // Present creates a TXT record using the specified parameters.
+// The other values of the record (e.g. the challenges of a wildcard and its domain) are kept.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	ctx := context.Background()
-
-	records, err := d.client.FindTXTRecords(ctx, dns01.UnFqdn(info.EffectiveFQDN))
-	if err != nil {
-		return fmt.Errorf("rimuhosting: failed to find record(s) for %s: %w", domain, err)
-	}
-
-	actions := []rimuhosting.ActionParameter{
-		rimuhosting.NewAddRecordAction(dns01.UnFqdn(info.EffectiveFQDN), info.Value, d.config.TTL),
-	}
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	for _, record := range records {
-		actions = append(actions, rimuhosting.NewAddRecordAction(record.Name, record.Content, d.config.TTL))
-	}
-
-	_, err = d.client.DoActions(ctx, actions...)
+	err := d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("rimuhosting: failed to add record(s) for %s: %w", domain, err)
 	}
@@ -122,11 +147,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	action := rimuhosting.NewDeleteRecordAction(dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	_, err := d.client.DoActions(context.Background(), action)
+	err := d.client.DeleteTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
 	if err != nil {
 		return fmt.Errorf("rimuhosting: failed to delete record for %s: %w", domain, err)
 	}
@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"math/rand"
 	"strings"
 	"time"
@@ -20,7 +20,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
-	"github.com/go-acme/lego/v4/platform/wait"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -133,7 +134,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -170,8 +171,13 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record using the specified parameters.
+// The wait for the change to be INSYNC is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -213,8 +219,13 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+// The wait for the change to be INSYNC is canceled with ctx.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -281,18 +292,21 @@ func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAc
 	changeID := resp.ChangeInfo.Id
 
 	if d.config.WaitForRecordSetsChanged {
-		return wait.For("route53", d.config.PropagationTimeout, d.config.PollingInterval, func() (bool, error) {
+		opts := jobs.DefaultOptions("route53: change "+deref(changeID), d.config.PropagationTimeout, d.config.PollingInterval)
+
+		_, err = jobs.Poll(ctx, opts, func(ctx context.Context) (jobs.Status, error) {
 			resp, err := d.client.GetChange(ctx, &route53.GetChangeInput{Id: changeID})
 			if err != nil {
-				return false, fmt.Errorf("failed to query change status: %w", err)
+				return jobs.Status{}, fmt.Errorf("failed to query change status: %w", err)
 			}
 
-			if resp.ChangeInfo.Status == awstypes.ChangeStatusInsync {
-				return true, nil
-			}
-
-			return false, fmt.Errorf("unable to retrieve change: ID=%s", deref(changeID))
+			return jobs.Status{
+				Done:  resp.ChangeInfo.Status == awstypes.ChangeStatusInsync,
+				State: string(resp.ChangeInfo.Status),
+			}, nil
 		})
+
+		return err
 	}
 
 	return nil
@@ -4,6 +4,7 @@ package sakuracloud
 import (
 	"errors"
 	"fmt"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	client "github.com/sacloud/api-client-go"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/api"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -20,6 +22,8 @@ const (
 
 	EnvAccessToken       = envNamespace + "ACCESS_TOKEN"
 	EnvAccessTokenSecret = envNamespace + "ACCESS_TOKEN_SECRET"
+	EnvAPIRootURL        = envNamespace + "API_ROOT_URL"
+	EnvZone              = envNamespace + "ZONE"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -29,12 +33,16 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Token              string
-	Secret             string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	Token  string `yaml:"accessToken"`
+	Secret string `yaml:"accessTokenSecret"`
+	// APIRootURL and Zone override the API root URL and the default zone (e.g. is1a, tk1a) of the API calls,
+	// the values of the usacloud profile (or the defaults) are used when empty.
+	APIRootURL         string        `yaml:"apiRootURL"`
+	Zone               string        `yaml:"zone"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	TTL                int           `yaml:"ttl"`
+	HTTPClient         *http.Client  `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -49,6 +57,29 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func GetYamlTemple() string {
+	return `# Config 用于配置 DNSProvider 的创建。
+accessToken: "your_access_token"                # 访问令牌
+accessTokenSecret: "your_access_token_secret"   # 访问令牌密钥
+apiRootURL: ""                                  # API 根 URL，为空时使用 usacloud 配置文件或默认值
+zone: ""                                        # API 调用的默认区域（例如 is1a、tk1a），为空时使用 usacloud 配置文件或默认值
+propagationTimeout: 60s                         # 传播超时时间
+pollingInterval: 2s                             # 轮询间隔时间
+ttl: 120                                        # TXT 记录的生存时间（秒）`
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
@@ -67,10 +98,22 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config := NewDefaultConfig()
 	config.Token = values[EnvAccessToken]
 	config.Secret = values[EnvAccessTokenSecret]
+	config.APIRootURL = env.GetOrFile(EnvAPIRootURL)
+	config.Zone = env.GetOrFile(EnvZone)
 
 	return NewDNSProviderConfig(config)
 }
 
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewDNSProviderConfig return a DNSProvider instance configured for SakuraCloud.
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
@@ -97,6 +140,8 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 			HttpClient:        config.HTTPClient,
 			UserAgent:         fmt.Sprintf("go-acme/lego %s", iaas.DefaultUserAgent),
 		},
+		APIRootURL:  config.APIRootURL,
+		DefaultZone: config.Zone,
 	}
 
 	return &DNSProvider{
@@ -107,7 +152,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.addTXTRecord(info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
@@ -119,7 +164,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	err := d.cleanupTXTRecord(info.EffectiveFQDN, info.Value)
 	if err != nil {
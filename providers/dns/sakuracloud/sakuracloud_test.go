@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +13,9 @@ const envDomain = envNamespace + "DOMAIN"
 
 var envTest = tester.NewEnvTest(
 	EnvAccessToken,
-	EnvAccessTokenSecret).
+	EnvAccessTokenSecret,
+	EnvAPIRootURL,
+	EnvZone).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -150,3 +153,25 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestParseConfig(t *testing.T) {
+	rawConfig := []byte(`
+accessToken: token
+accessTokenSecret: secret
+zone: tk1a
+ttl: 300
+`)
+
+	config, err := ParseConfig(rawConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "token", config.Token)
+	assert.Equal(t, "secret", config.Secret)
+	assert.Equal(t, "tk1a", config.Zone)
+	assert.Empty(t, config.APIRootURL)
+	assert.Equal(t, 300, config.TTL)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+}
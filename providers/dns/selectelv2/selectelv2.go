@@ -14,6 +14,7 @@ import (
 	selectelapi "github.com/selectel/domains-go/pkg/v2"
 	"github.com/selectel/go-selvpcclient/v3/selvpcclient"
 	"lego-toolbox/providers/dns/internal/selectel"
+	"lego-toolbox/rawrecord"
 )
 
 const tokenHeader = "X-Auth-Token"
@@ -136,7 +137,7 @@ func (p *DNSProvider) Present(domain, _, keyAuth string) error {
 		return fmt.Errorf("selectelv2: authorize: %w", err)
 	}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := client.getZone(ctx, domain)
 	if err != nil {
@@ -183,7 +184,7 @@ func (p *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 		return fmt.Errorf("selectelv2: authorize: %w", err)
 	}
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := client.getZone(ctx, domain)
 	if err != nil {
@@ -224,7 +224,7 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return response
+	return errutils.NewAPIError(req, resp.StatusCode, raw, response).WithCode(strconv.Itoa(response.Code))
 }
 
 // TTLRounder rounds the given TTL in seconds to the next accepted value.
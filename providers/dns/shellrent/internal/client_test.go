@@ -84,7 +84,7 @@ func TestClient_ListServices_error_status(t *testing.T) {
 	client := setupTest(t, http.MethodGet, "/purchase", http.StatusUnauthorized, "error.json")
 
 	_, err := client.ListServices(context.Background())
-	require.EqualError(t, err, "code 2: Token di autorizzazione non valido")
+	require.EqualError(t, err, "[status code: 401] code 2: Token di autorizzazione non valido")
 }
 
 func TestClient_GetServiceDetails(t *testing.T) {
@@ -109,7 +109,7 @@ func TestClient_GetServiceDetails_error_status(t *testing.T) {
 	client := setupTest(t, http.MethodGet, "/purchase/details/123", http.StatusUnauthorized, "error.json")
 
 	_, err := client.GetServiceDetails(context.Background(), 123)
-	require.EqualError(t, err, "code 2: Token di autorizzazione non valido")
+	require.EqualError(t, err, "[status code: 401] code 2: Token di autorizzazione non valido")
 }
 
 func TestClient_GetDomainDetails(t *testing.T) {
@@ -134,7 +134,7 @@ func TestClient_GetDomainDetails_error_status(t *testing.T) {
 	client := setupTest(t, http.MethodGet, "/domain/details/123", http.StatusUnauthorized, "error.json")
 
 	_, err := client.GetDomainDetails(context.Background(), 123)
-	require.EqualError(t, err, "code 2: Token di autorizzazione non valido")
+	require.EqualError(t, err, "[status code: 401] code 2: Token di autorizzazione non valido")
 }
 
 func TestClient_CreateRecord(t *testing.T) {
@@ -159,7 +159,7 @@ func TestClient_CreateRecord_error_status(t *testing.T) {
 	client := setupTest(t, http.MethodPost, "/dns_record/store/123", http.StatusUnauthorized, "error.json")
 
 	_, err := client.CreateRecord(context.Background(), 123, Record{})
-	require.EqualError(t, err, "code 2: Token di autorizzazione non valido")
+	require.EqualError(t, err, "[status code: 401] code 2: Token di autorizzazione non valido")
 }
 
 func TestClient_DeleteRecord(t *testing.T) {
@@ -180,7 +180,7 @@ func TestClient_DeleteRecord_error_status(t *testing.T) {
 	client := setupTest(t, http.MethodDelete, "/dns_record/remove/123/456", http.StatusUnauthorized, "error.json")
 
 	err := client.DeleteRecord(context.Background(), 123, 456)
-	require.EqualError(t, err, "code 2: Token di autorizzazione non valido")
+	require.EqualError(t, err, "[status code: 401] code 2: Token di autorizzazione non valido")
 }
 
 func TestTTLRounder(t *testing.T) {
@@ -6,12 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
 	"lego-toolbox/providers/dns/shellrent/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -30,8 +31,8 @@ const (
 const defaultTTL = 3600
 
 type reqKey struct {
-	domainID int
-	recordID int
+	DomainID int `json:"domainID"`
+	RecordID int `json:"recordID"`
 }
 
 // Config is used to configure the creation of the DNSProvider.
@@ -61,8 +62,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]reqKey
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[reqKey]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Shellrent.
@@ -103,7 +103,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]reqKey),
+		recordIDs: challengestore.New[reqKey](),
 	}, nil
 }
 
@@ -113,10 +113,16 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.findZone(ctx, dns01.UnFqdn(info.EffectiveFQDN))
 	if err != nil {
@@ -142,9 +148,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("shellrent: create record: %w", err)
 	}
 
-	d.recordIDsMu.Lock()
-	d.recordIDs[token] = reqKey{domainID: zone.ID, recordID: recordID}
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Set(token, info.EffectiveFQDN, reqKey{DomainID: zone.ID, RecordID: recordID})
 
 	return nil
 }
@@ -152,21 +156,21 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	// gets the record's unique ID from when we created it
-	d.recordIDsMu.Lock()
-	key, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	key, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
 		return fmt.Errorf("shellrent: unknown request key for '%s' '%s'", info.EffectiveFQDN, token)
 	}
 
-	err := d.client.DeleteRecord(ctx, key.domainID, key.recordID)
+	err := d.client.DeleteRecord(ctx, key.DomainID, key.RecordID)
 	if err != nil {
 		return fmt.Errorf("shellrent: delete record: %w", err)
 	}
 
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
+
 	return nil
 }
 
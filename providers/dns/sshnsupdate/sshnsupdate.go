@@ -0,0 +1,404 @@
+// Package sshnsupdate implements a DNS provider for solving the DNS-01 challenge
+// by running nsupdate (or another CLI) on a remote host over SSH.
+package sshnsupdate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// Modes.
+const (
+	// ModeNsupdate sends an nsupdate script on the standard input of NsupdateCommand.
+	ModeNsupdate = "nsupdate"
+	// ModeCommand runs PresentCommand and CleanUpCommand.
+	ModeCommand = "command"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "SSH_NSUPDATE_"
+
+	EnvHost                  = envNamespace + "HOST"
+	EnvUser                  = envNamespace + "USER"
+	EnvPrivateKey            = envNamespace + "PRIVATE_KEY"
+	EnvPrivateKeyFile        = envNamespace + "PRIVATE_KEY_FILE"
+	EnvPassphrase            = envNamespace + "PASSPHRASE"
+	EnvKnownHostsFile        = envNamespace + "KNOWN_HOSTS_FILE"
+	EnvHostKey               = envNamespace + "HOST_KEY"
+	EnvInsecureIgnoreHostKey = envNamespace + "INSECURE_IGNORE_HOST_KEY"
+	EnvMode                  = envNamespace + "MODE"
+	EnvNsupdateCommand       = envNamespace + "NSUPDATE_COMMAND"
+	EnvNameserver            = envNamespace + "NAMESERVER"
+	EnvZone                  = envNamespace + "ZONE"
+	EnvPresentCommand        = envNamespace + "PRESENT_COMMAND"
+	EnvCleanUpCommand        = envNamespace + "CLEANUP_COMMAND"
+	EnvCommandTimeout        = envNamespace + "COMMAND_TIMEOUT"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// Host is the SSH server, in the form "host" or "host:port".
+	Host string `yaml:"host"`
+	User string `yaml:"user"`
+	// PrivateKey is the content of the private key (PEM/OpenSSH format).
+	PrivateKey     string `yaml:"privateKey"`
+	PrivateKeyFile string `yaml:"privateKeyFile"`
+	Passphrase     string `yaml:"passphrase"`
+	// KnownHostsFile or HostKey (authorized_keys format) are used to verify the server.
+	KnownHostsFile        string `yaml:"knownHostsFile"`
+	HostKey               string `yaml:"hostKey"`
+	InsecureIgnoreHostKey bool   `yaml:"insecureIgnoreHostKey"`
+
+	Mode string `yaml:"mode"`
+	// NsupdateCommand is the remote command reading the nsupdate script (e.g. "nsupdate -k /etc/bind/acme.key").
+	NsupdateCommand string `yaml:"nsupdateCommand"`
+	// Nameserver and Zone are optional, nsupdate discovers them otherwise.
+	Nameserver string `yaml:"nameserver"`
+	Zone       string `yaml:"zone"`
+	// PresentCommand and CleanUpCommand are the remote commands of the command mode.
+	// The placeholders {fqdn}, {value} and {ttl} are replaced by the shell-quoted values.
+	PresentCommand string `yaml:"presentCommand"`
+	CleanUpCommand string `yaml:"cleanUpCommand"`
+
+	CommandTimeout     time.Duration `yaml:"commandTimeout"`
+	TTL                int           `yaml:"ttl"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Mode:               env.GetOrDefaultString(EnvMode, ModeNsupdate),
+		NsupdateCommand:    env.GetOrDefaultString(EnvNsupdateCommand, "nsupdate"),
+		CommandTimeout:     env.GetOrDefaultSecond(EnvCommandTimeout, 30*time.Second),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		Mode:               ModeNsupdate,
+		NsupdateCommand:    "nsupdate",
+		CommandTimeout:     30 * time.Second,
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+host: "bastion.example.com:22"        # SSH 服务器地址
+user: "acme"                          # SSH 用户名
+privateKeyFile: "/etc/lego/id_ed25519" # SSH 私钥文件路径
+privateKey: ""                        # SSH 私钥内容，与 privateKeyFile 二选一
+passphrase: ""                        # 私钥密码
+knownHostsFile: "/etc/lego/known_hosts" # known_hosts 文件路径，用于校验服务器
+hostKey: ""                           # 服务器公钥（authorized_keys 格式），与 knownHostsFile 二选一
+insecureIgnoreHostKey: false          # 是否跳过服务器公钥校验（不安全）
+mode: "nsupdate"                      # 模式：nsupdate 或 command
+nsupdateCommand: "nsupdate"           # 远程 nsupdate 命令，例如 "nsupdate -k /etc/bind/acme.key"
+nameserver: ""                        # nsupdate 的 server，留空则自动发现
+zone: ""                              # nsupdate 的 zone，留空则自动发现
+presentCommand: ""                    # command 模式下添加记录的命令，支持 {fqdn}、{value}、{ttl}
+cleanUpCommand: ""                    # command 模式下删除记录的命令，支持 {fqdn}、{value}、{ttl}
+commandTimeout: 30s                   # SSH 连接和命令执行超时时间
+ttl: 120                              # TXT 记录的 TTL
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间`
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config    *Config
+	address   string
+	sshConfig *ssh.ClientConfig
+}
+
+// NewDNSProvider returns a DNSProvider instance configured to run nsupdate over SSH.
+// Credentials must be passed in the environment variables:
+// SSH_NSUPDATE_HOST, SSH_NSUPDATE_USER, and SSH_NSUPDATE_PRIVATE_KEY or SSH_NSUPDATE_PRIVATE_KEY_FILE.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvHost, EnvUser)
+	if err != nil {
+		return nil, fmt.Errorf("sshnsupdate: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Host = values[EnvHost]
+	config.User = values[EnvUser]
+	config.PrivateKey = env.GetOrFile(EnvPrivateKey)
+	config.PrivateKeyFile = env.GetOrFile(EnvPrivateKeyFile)
+	config.Passphrase = env.GetOrFile(EnvPassphrase)
+	config.KnownHostsFile = env.GetOrFile(EnvKnownHostsFile)
+	config.HostKey = env.GetOrFile(EnvHostKey)
+	config.InsecureIgnoreHostKey = env.GetOrDefaultBool(EnvInsecureIgnoreHostKey, false)
+	config.Nameserver = env.GetOrFile(EnvNameserver)
+	config.Zone = env.GetOrFile(EnvZone)
+	config.PresentCommand = env.GetOrFile(EnvPresentCommand)
+	config.CleanUpCommand = env.GetOrFile(EnvCleanUpCommand)
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured to run nsupdate over SSH.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("sshnsupdate: the configuration of the DNS provider is nil")
+	}
+
+	if config.Host == "" || config.User == "" {
+		return nil, errors.New("sshnsupdate: host and user are required")
+	}
+
+	switch config.Mode {
+	case ModeNsupdate:
+		if config.NsupdateCommand == "" {
+			return nil, errors.New("sshnsupdate: nsupdate command is required")
+		}
+	case ModeCommand:
+		if config.PresentCommand == "" || config.CleanUpCommand == "" {
+			return nil, errors.New("sshnsupdate: present and clean up commands are required with the command mode")
+		}
+	default:
+		return nil, fmt.Errorf("sshnsupdate: unsupported mode %q, must be %q or %q", config.Mode, ModeNsupdate, ModeCommand)
+	}
+
+	signer, err := newSigner(config)
+	if err != nil {
+		return nil, fmt.Errorf("sshnsupdate: %w", err)
+	}
+
+	hostKeyCallback, err := newHostKeyCallback(config)
+	if err != nil {
+		return nil, fmt.Errorf("sshnsupdate: %w", err)
+	}
+
+	address := config.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "22")
+	}
+
+	return &DNSProvider{
+		config:  config,
+		address: address,
+		sshConfig: &ssh.ClientConfig{
+			User:            config.User,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         config.CommandTimeout,
+		},
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	var err error
+	if d.config.Mode == ModeCommand {
+		err = d.run(d.command(d.config.PresentCommand, info.EffectiveFQDN, info.Value), "")
+	} else {
+		err = d.run(d.config.NsupdateCommand, d.nsupdateScript("add", info.EffectiveFQDN, info.Value))
+	}
+
+	if err != nil {
+		return fmt.Errorf("sshnsupdate: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	var err error
+	if d.config.Mode == ModeCommand {
+		err = d.run(d.command(d.config.CleanUpCommand, info.EffectiveFQDN, info.Value), "")
+	} else {
+		err = d.run(d.config.NsupdateCommand, d.nsupdateScript("delete", info.EffectiveFQDN, info.Value))
+	}
+
+	if err != nil {
+		return fmt.Errorf("sshnsupdate: %w", err)
+	}
+
+	return nil
+}
+
+// nsupdateScript returns the nsupdate script adding or deleting the TXT record.
+func (d *DNSProvider) nsupdateScript(action, fqdn, value string) string {
+	var b strings.Builder
+
+	if d.config.Nameserver != "" {
+		_, _ = fmt.Fprintf(&b, "server %s\n", d.config.Nameserver)
+	}
+
+	if d.config.Zone != "" {
+		_, _ = fmt.Fprintf(&b, "zone %s\n", dns01.ToFqdn(d.config.Zone))
+	}
+
+	if action == "add" {
+		_, _ = fmt.Fprintf(&b, "update add %s %d TXT %q\n", fqdn, d.config.TTL, value)
+	} else {
+		_, _ = fmt.Fprintf(&b, "update delete %s TXT %q\n", fqdn, value)
+	}
+
+	b.WriteString("send\n")
+
+	return b.String()
+}
+
+// command replaces the placeholders of the command template.
+func (d *DNSProvider) command(template, fqdn, value string) string {
+	return strings.NewReplacer(
+		"{fqdn}", shellQuote(fqdn),
+		"{value}", shellQuote(value),
+		"{ttl}", strconv.Itoa(d.config.TTL),
+	).Replace(template)
+}
+
+// run runs the command on the remote host, with the input on its standard input.
+func (d *DNSProvider) run(command, input string) error {
+	client, err := ssh.Dial("tcp", d.address, d.sshConfig)
+	if err != nil {
+		return fmt.Errorf("ssh dial %s: %w", d.address, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("ssh session: %w", err)
+	}
+
+	defer func() { _ = session.Close() }()
+
+	session.Stdin = strings.NewReader(input)
+
+	type result struct {
+		output []byte
+		err    error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		output, err := session.CombinedOutput(command)
+		done <- result{output: output, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return fmt.Errorf("remote command %q: %w: %s", command, res.err, strings.TrimSpace(string(res.output)))
+		}
+
+		return nil
+
+	case <-time.After(d.config.CommandTimeout):
+		_ = client.Close()
+
+		return fmt.Errorf("remote command %q: timeout after %s", command, d.config.CommandTimeout)
+	}
+}
+
+func newSigner(config *Config) (ssh.Signer, error) {
+	key := []byte(config.PrivateKey)
+
+	if len(key) == 0 && config.PrivateKeyFile != "" {
+		var err error
+		key, err = os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("private key is required")
+	}
+
+	var signer ssh.Signer
+	var err error
+
+	if config.Passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(config.Passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	return signer, nil
+}
+
+func newHostKeyCallback(config *Config) (ssh.HostKeyCallback, error) {
+	switch {
+	case config.HostKey != "":
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("parse host key: %w", err)
+		}
+
+		return ssh.FixedHostKey(key), nil
+
+	case config.KnownHostsFile != "":
+		callback, err := knownhosts.New(config.KnownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf("known hosts: %w", err)
+		}
+
+		return callback, nil
+
+	case config.InsecureIgnoreHostKey:
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // explicitly requested.
+
+	default:
+		return nil, errors.New("host key verification is required: set the host key or the known hosts file")
+	}
+}
+
+// shellQuote quotes the value for a POSIX shell.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,340 @@
+package sshnsupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"lego-toolbox/rawrecord"
+)
+
+var envTest = tester.NewEnvTest(EnvHost, EnvUser, EnvPrivateKey, EnvHostKey)
+
+type execution struct {
+	command string
+	input   string
+}
+
+type sshServer struct {
+	address string
+	hostKey string
+
+	mu         sync.Mutex
+	executions []execution
+	exitStatus uint32
+}
+
+func (s *sshServer) Executions() []execution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]execution(nil), s.executions...)
+}
+
+func setupSSHServer(t *testing.T, clientKey ssh.PublicKey, exitStatus uint32) *sshServer {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, io.EOF
+			}
+
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &sshServer{
+		address:    listener.Addr().String(),
+		hostKey:    string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey())),
+		exitStatus: exitStatus,
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.handle(conn, config)
+		}
+	}()
+
+	return server
+}
+
+func (s *sshServer) handle(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			defer func() { _ = channel.Close() }()
+
+			for req := range requests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+
+				var payload struct{ Command string }
+				_ = ssh.Unmarshal(req.Payload, &payload)
+				_ = req.Reply(true, nil)
+
+				input, _ := io.ReadAll(channel)
+
+				s.mu.Lock()
+				s.executions = append(s.executions, execution{command: payload.Command, input: string(input)})
+				s.mu.Unlock()
+
+				if s.exitStatus != 0 {
+					_, _ = channel.Stderr().Write([]byte("update failed: REFUSED"))
+				}
+
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, s.exitStatus)
+				_, _ = channel.SendRequest("exit-status", false, status)
+
+				return
+			}
+		}()
+	}
+}
+
+func generateClientKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(block)), signer.PublicKey()
+}
+
+func TestNewDNSProvider(t *testing.T) {
+	privateKey, _ := generateClientKey(t)
+
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvHost:       "bastion.example.com",
+				EnvUser:       "acme",
+				EnvPrivateKey: privateKey,
+				EnvHostKey:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl",
+			},
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "sshnsupdate: some credentials information are missing: SSH_NSUPDATE_HOST,SSH_NSUPDATE_USER",
+		},
+		{
+			desc: "missing private key",
+			envVars: map[string]string{
+				EnvHost: "bastion.example.com",
+				EnvUser: "acme",
+			},
+			expected: "sshnsupdate: private key is required",
+		},
+		{
+			desc: "missing host key",
+			envVars: map[string]string{
+				EnvHost:       "bastion.example.com",
+				EnvUser:       "acme",
+				EnvPrivateKey: privateKey,
+			},
+			expected: "sshnsupdate: host key verification is required: set the host key or the known hosts file",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				assert.Equal(t, "bastion.example.com:22", p.address)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig_mode(t *testing.T) {
+	privateKey, _ := generateClientKey(t)
+
+	config := DefaultConfig()
+	config.Host = "bastion.example.com"
+	config.User = "acme"
+	config.PrivateKey = privateKey
+	config.InsecureIgnoreHostKey = true
+	config.Mode = ModeCommand
+
+	_, err := NewDNSProviderConfig(config)
+	require.EqualError(t, err, "sshnsupdate: present and clean up commands are required with the command mode")
+
+	config.Mode = "api"
+
+	_, err = NewDNSProviderConfig(config)
+	require.EqualError(t, err, `sshnsupdate: unsupported mode "api", must be "nsupdate" or "command"`)
+}
+
+func TestDNSProvider_nsupdate(t *testing.T) {
+	privateKey, publicKey := generateClientKey(t)
+	server := setupSSHServer(t, publicKey, 0)
+
+	config, err := ParseConfig([]byte("nsupdateCommand: nsupdate -k /etc/bind/acme.key\nnameserver: 127.0.0.1\nzone: example.com\n"))
+	require.NoError(t, err)
+
+	config.Host = server.address
+	config.User = "acme"
+	config.PrivateKey = privateKey
+	config.HostKey = server.hostKey
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	info := rawrecord.GetChallengeInfo("example.com", "123d==")
+
+	expected := []execution{
+		{
+			command: "nsupdate -k /etc/bind/acme.key",
+			input:   "server 127.0.0.1\nzone example.com.\nupdate add _acme-challenge.example.com. 120 TXT \"" + info.Value + "\"\nsend\n",
+		},
+		{
+			command: "nsupdate -k /etc/bind/acme.key",
+			input:   "server 127.0.0.1\nzone example.com.\nupdate delete _acme-challenge.example.com. TXT \"" + info.Value + "\"\nsend\n",
+		},
+	}
+
+	assert.Equal(t, expected, server.Executions())
+}
+
+func TestDNSProvider_command(t *testing.T) {
+	privateKey, publicKey := generateClientKey(t)
+	server := setupSSHServer(t, publicKey, 0)
+
+	config := DefaultConfig()
+	config.Host = server.address
+	config.User = "acme"
+	config.PrivateKey = privateKey
+	config.HostKey = server.hostKey
+	config.Mode = ModeCommand
+	config.PresentCommand = "dnscli add {fqdn} {value} --ttl {ttl}"
+	config.CleanUpCommand = "dnscli rm {fqdn} {value}"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	info := rawrecord.GetChallengeInfo("example.com", "123d==")
+
+	expected := []execution{
+		{command: "dnscli add '_acme-challenge.example.com.' '" + info.Value + "' --ttl 120"},
+		{command: "dnscli rm '_acme-challenge.example.com.' '" + info.Value + "'"},
+	}
+
+	assert.Equal(t, expected, server.Executions())
+}
+
+func TestDNSProvider_commandError(t *testing.T) {
+	privateKey, publicKey := generateClientKey(t)
+	server := setupSSHServer(t, publicKey, 2)
+
+	config := DefaultConfig()
+	config.Host = server.address
+	config.User = "acme"
+	config.PrivateKey = privateKey
+	config.HostKey = server.hostKey
+	config.CommandTimeout = 5 * time.Second
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.EqualError(t, err, `sshnsupdate: remote command "nsupdate": Process exited with status 2: update failed: REFUSED`)
+}
+
+func TestDNSProvider_hostKeyMismatch(t *testing.T) {
+	privateKey, publicKey := generateClientKey(t)
+	server := setupSSHServer(t, publicKey, 0)
+	other := setupSSHServer(t, publicKey, 0)
+
+	config := DefaultConfig()
+	config.Host = server.address
+	config.User = "acme"
+	config.PrivateKey = privateKey
+	config.HostKey = other.hostKey
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.ErrorContains(t, err, "ssh: host key mismatch")
+	assert.Empty(t, server.Executions())
+}
+
+func Test_shellQuote(t *testing.T) {
+	assert.Equal(t, `'value'`, shellQuote("value"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"golang.org/x/net/publicsuffix"
@@ -182,5 +183,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errResp
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errResp).WithCode(strconv.Itoa(errResp.Code))
 }
@@ -75,8 +75,11 @@ func TestClient_GetZoneRecords_apiError(t *testing.T) {
 
 	_, err := client.GetZoneRecords(context.Background(), "foo1", &Zone{ID: "A", Domain: "test"})
 
+	var apiErr *ErrorResponse
+	require.ErrorAs(t, err, &apiErr)
+
 	expected := &ErrorResponse{Code: 401, Message: "an unauthorized request is attempted."}
-	assert.Equal(t, expected, err)
+	assert.Equal(t, expected, apiErr)
 }
 
 func TestClient_GetZones(t *testing.T) {
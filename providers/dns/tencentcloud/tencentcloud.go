@@ -4,8 +4,8 @@ package tencentcloud
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"math"
+	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -13,6 +13,15 @@ import (
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	dnspod "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/dnspod/v20210323"
+	dnspodlegacy "lego-toolbox/providers/dns/dnspod"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// API endpoints.
+const (
+	defaultEndpoint = "dnspod.tencentcloudapi.com"
+	intlEndpoint    = "dnspod.intl.tencentcloudapi.com"
 )
 
 // Environment variables names.
@@ -23,6 +32,8 @@ const (
 	EnvSecretKey    = envNamespace + "SECRET_KEY"
 	EnvRegion       = envNamespace + "REGION"
 	EnvSessionToken = envNamespace + "SESSION_TOKEN"
+	EnvIntl         = envNamespace + "INTL"
+	EnvDNSPodToken  = envNamespace + "DNSPOD_TOKEN"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -32,10 +43,15 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	SecretID           string        `yaml:"secretID"`
-	SecretKey          string        `yaml:"secretKey"`
-	Region             string        `yaml:"region"`
-	SessionToken       string        `yaml:"sessionToken"`
+	SecretID     string `yaml:"secretID"`
+	SecretKey    string `yaml:"secretKey"`
+	Region       string `yaml:"region"`
+	SessionToken string `yaml:"sessionToken"`
+	// Intl uses the international endpoints (tencentcloud.com / dnspod.com).
+	Intl bool `yaml:"intl"`
+	// DNSPodToken is a legacy DNSPod Token ("ID,Token"),
+	// used through the DNSPod API when no SecretID/SecretKey is set.
+	DNSPodToken        string        `yaml:"dnspodToken"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
@@ -68,6 +84,8 @@ secretID: "your_secret_id"                  # 密钥 ID
 secretKey: "your_secret_key"                # 密钥 Key
 region: "your_region"                       # 区域
 sessionToken: "your_session_token"          # 会话令牌
+intl: false                                 # 是否使用国际版接口（tencentcloud.com / dnspod.com）
+dnspodToken: ""                             # DNSPod Token（格式 "ID,Token"），未配置 secretID/secretKey 时使用
 propagationTimeout: 600s                    # 传播超时时间，单位为秒
 pollingInterval: 30s                        # 轮询间隔时间，单位为秒
 ttl: 3600                                   # TTL 值，单位为秒
@@ -78,19 +96,29 @@ httpTimeout: 30s                            # HTTP 超时时间，单位为秒`
 type DNSProvider struct {
 	config *Config
 	client *dnspod.Client
+
+	// legacy is set when using a DNSPod Token instead of API 3.0 credentials.
+	legacy *dnspodlegacy.DNSProvider
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Tencent Cloud DNS.
-// Credentials must be passed in the environment variable: TENCENTCLOUD_SECRET_ID, TENCENTCLOUD_SECRET_KEY.
+// Credentials must be passed in the environment variable: TENCENTCLOUD_SECRET_ID, TENCENTCLOUD_SECRET_KEY,
+// or TENCENTCLOUD_DNSPOD_TOKEN.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvSecretID, EnvSecretKey)
-	if err != nil {
-		return nil, fmt.Errorf("tencentcloud: %w", err)
+	config := NewDefaultConfig()
+	config.Intl = env.GetOrDefaultBool(EnvIntl, false)
+	config.DNSPodToken = env.GetOrDefaultString(EnvDNSPodToken, "")
+
+	if config.DNSPodToken == "" {
+		values, err := env.Get(EnvSecretID, EnvSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("tencentcloud: %w", err)
+		}
+
+		config.SecretID = values[EnvSecretID]
+		config.SecretKey = values[EnvSecretKey]
 	}
 
-	config := NewDefaultConfig()
-	config.SecretID = values[EnvSecretID]
-	config.SecretKey = values[EnvSecretKey]
 	config.Region = env.GetOrDefaultString(EnvRegion, "")
 	config.SessionToken = env.GetOrDefaultString(EnvSessionToken, "")
 
@@ -100,7 +128,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +141,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("tencentcloud: the configuration of the DNS provider is nil")
 	}
 
+	if (config.SecretID == "" || config.SecretKey == "") && config.DNSPodToken != "" {
+		return newLegacyProvider(config)
+	}
+
 	var credential *common.Credential
 
 	switch {
@@ -125,7 +157,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	cpf := profile.NewClientProfile()
-	cpf.HttpProfile.Endpoint = "dnspod.tencentcloudapi.com"
+	cpf.HttpProfile.Endpoint = defaultEndpoint
+	if config.Intl {
+		cpf.HttpProfile.Endpoint = intlEndpoint
+	}
 	cpf.HttpProfile.ReqTimeout = int(math.Round(config.HTTPTimeout.Seconds()))
 
 	client, err := dnspod.NewClient(credential, config.Region, cpf)
@@ -136,6 +171,49 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{config: config, client: client}, nil
 }
 
+// NewDNSProviderFromDNSPod returns a DNSProvider instance from a legacy DNSPod configuration.
+// The DNSPod Token is used through the DNSPod API.
+func NewDNSProviderFromDNSPod(legacy *dnspodlegacy.Config) (*DNSProvider, error) {
+	if legacy == nil {
+		return nil, errors.New("tencentcloud: the configuration of the DNS provider is nil")
+	}
+
+	config := DefaultConfig()
+	config.DNSPodToken = legacy.LoginToken
+	config.Intl = legacy.Intl
+	config.TTL = legacy.TTL
+	config.PropagationTimeout = legacy.PropagationTimeout
+	config.PollingInterval = legacy.PollingInterval
+
+	if config.DNSPodToken == "" {
+		return nil, errors.New("tencentcloud: credentials missing")
+	}
+
+	provider, err := dnspodlegacy.NewDNSProviderConfig(legacy)
+	if err != nil {
+		return nil, fmt.Errorf("tencentcloud: %w", err)
+	}
+
+	return &DNSProvider{config: config, legacy: provider}, nil
+}
+
+func newLegacyProvider(config *Config) (*DNSProvider, error) {
+	legacy := dnspodlegacy.DefaultConfig()
+	legacy.LoginToken = config.DNSPodToken
+	legacy.Intl = config.Intl
+	legacy.TTL = config.TTL
+	legacy.PropagationTimeout = config.PropagationTimeout
+	legacy.PollingInterval = config.PollingInterval
+	legacy.HTTPClient = &http.Client{Timeout: config.HTTPTimeout}
+
+	provider, err := dnspodlegacy.NewDNSProviderConfig(legacy)
+	if err != nil {
+		return nil, fmt.Errorf("tencentcloud: %w", err)
+	}
+
+	return &DNSProvider{config: config, legacy: provider}, nil
+}
+
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
 // Adjusting here to cope with spikes in propagation times.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -144,7 +222,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	if d.legacy != nil {
+		return d.legacy.Present(domain, token, keyAuth)
+	}
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
@@ -175,7 +257,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	if d.legacy != nil {
+		return d.legacy.CleanUp(domain, token, keyAuth)
+	}
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
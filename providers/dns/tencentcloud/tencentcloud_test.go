@@ -4,13 +4,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/dnspod"
+	"lego-toolbox/providertest"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvSecretID, EnvSecretKey).
+var envTest = tester.NewEnvTest(EnvSecretID, EnvSecretKey, EnvDNSPodToken, EnvIntl).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -73,6 +76,22 @@ func TestNewDNSProvider(t *testing.T) {
 	}
 }
 
+func TestNewDNSProvider_dnspodToken(t *testing.T) {
+	defer envTest.RestoreEnv()
+	envTest.ClearEnv()
+
+	envTest.Apply(map[string]string{
+		EnvDNSPodToken: "123,456",
+		EnvIntl:        "true",
+	})
+
+	p, err := NewDNSProvider()
+	require.NoError(t, err)
+	require.NotNil(t, p.legacy)
+	require.Nil(t, p.client)
+	require.True(t, p.config.Intl)
+}
+
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
 		desc      string
@@ -121,6 +140,51 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_intl(t *testing.T) {
+	config := NewDefaultConfig()
+	config.SecretID = "123"
+	config.SecretKey = "456"
+	config.Intl = true
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+	require.NotNil(t, p.client)
+	require.Nil(t, p.legacy)
+}
+
+func TestNewDNSProviderConfig_dnspodToken(t *testing.T) {
+	config := NewDefaultConfig()
+	config.DNSPodToken = "123,456"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+	require.NotNil(t, p.legacy)
+	require.Nil(t, p.client)
+}
+
+func TestNewDNSProviderFromDNSPod(t *testing.T) {
+	legacy := dnspod.DefaultConfig()
+	legacy.LoginToken = "123,456"
+	legacy.TTL = 120
+
+	p, err := NewDNSProviderFromDNSPod(legacy)
+	require.NoError(t, err)
+	require.NotNil(t, p.legacy)
+	require.Equal(t, "123,456", p.config.DNSPodToken)
+	require.Equal(t, 120, p.config.TTL)
+
+	_, err = NewDNSProviderFromDNSPod(dnspod.DefaultConfig())
+	require.EqualError(t, err, "tencentcloud: credentials missing")
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("intl: true\ndnspodToken: \"123,456\"\n"))
+	require.NoError(t, err)
+	require.True(t, config.Intl)
+	require.Equal(t, "123,456", config.DNSPodToken)
+	require.Equal(t, 600, config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -148,3 +212,18 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestLiveConformance(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	providertest.Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := NewDNSProvider()
+		require.NoError(t, err)
+
+		return provider
+	}, providertest.Options{Domain: envTest.GetDomain(), Nameservers: providertest.NameserversFromEnv()})
+}
@@ -158,5 +158,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return errAPI
+	return errutils.NewAPIError(req, resp.StatusCode, raw, errAPI)
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"lego-toolbox/providers/dns/internal/errutils"
@@ -145,5 +146,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code: %d] %w", resp.StatusCode, response.Message)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, response.Message).WithCode(strconv.Itoa(response.Message.Code))
 }
@@ -5,15 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/zonelock"
 	"lego-toolbox/providers/dns/versio/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -29,6 +30,7 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvSequenceInterval   = envNamespace + "SEQUENCE_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvDisableZoneLock    = envNamespace + "DISABLE_ZONE_LOCK"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -41,6 +43,7 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+	DisableZoneLock    bool          `yaml:"disableZoneLock"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -57,6 +60,7 @@ func NewDefaultConfig() *Config {
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 60*time.Second),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
 		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		DisableZoneLock:    env.GetOrDefaultBool(EnvDisableZoneLock, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -86,15 +90,14 @@ username: "your_username"             # 用户名，用于身份验证
 password: "your_password"             # 密码，用于身份验证
 propagationTimeout: 60s               # PropagationTimeout，传播超时时间，指定更新记录后等待传播的最大时间，单位为秒（s）
 pollingInterval: 5s                   # PollingInterval，轮询间隔时间，指定系统检查 DNS 记录状态的频率，单位为秒（s）
-sequenceInterval: 60s                 # SequenceInterval，顺序间隔时间，指定系统在处理连续请求时的间隔时间，单位为秒（s）`
+sequenceInterval: 60s                 # SequenceInterval，顺序间隔时间，指定系统在处理连续请求时的间隔时间，单位为秒（s）
+disableZoneLock: false                # DisableZoneLock，关闭进程内同一区域更新的串行化（默认 false）`
 }
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
-
-	dnsEntriesMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance.
@@ -114,7 +117,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -160,16 +163,17 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("versio: could not find zone for domain %q: %w", domain, err)
 	}
 
-	// use mutex to prevent race condition from getDNSRecords until postDNSRecords
-	d.dnsEntriesMu.Lock()
-	defer d.dnsEntriesMu.Unlock()
+	// lock the zone to prevent race condition from getDNSRecords until postDNSRecords
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(authZone)()
+	}
 
 	ctx := context.Background()
 
@@ -201,16 +205,17 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("versio: could not find zone for domain %q: %w", domain, err)
 	}
 
-	// use mutex to prevent race condition from getDNSRecords until postDNSRecords
-	d.dnsEntriesMu.Lock()
-	defer d.dnsEntriesMu.Unlock()
+	// lock the zone to prevent race condition from getDNSRecords until postDNSRecords
+	if !d.config.DisableZoneLock {
+		defer zonelock.Lock(authZone)()
+	}
 
 	ctx := context.Background()
 
@@ -2,14 +2,15 @@
 package vinyldns
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"time"
 
-	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -20,6 +21,9 @@ const (
 	EnvSecretKey = envNamespace + "SECRET_KEY"
 	EnvHost      = envNamespace + "HOST"
 
+	EnvOwnerGroupID  = envNamespace + "OWNER_GROUP_ID"
+	EnvReviewTimeout = envNamespace + "REVIEW_TIMEOUT"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -33,6 +37,11 @@ type Config struct {
 	TTL                int           `yaml:"ttl"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	// OwnerGroupID is the ID of the group owning the created record sets, required by the shared zones.
+	OwnerGroupID string `yaml:"ownerGroupID"`
+	// ReviewTimeout is the maximum waiting time for the manual review of a change pending review,
+	// the changes pending review fail immediately when zero.
+	ReviewTimeout time.Duration `yaml:"reviewTimeout"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -41,6 +50,8 @@ func NewDefaultConfig() *Config {
 		TTL:                env.GetOrDefaultInt(EnvTTL, 30),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
+		OwnerGroupID:       env.GetOrFile(EnvOwnerGroupID),
+		ReviewTimeout:      env.GetOrDefaultSecond(EnvReviewTimeout, 0),
 	}
 }
 
@@ -60,7 +71,9 @@ secretKey: "your_secret_key"           # SecretKey，秘密密钥，用于身份
 host: "dns.api.example.com"            # Host，DNS 服务主机名，用于与 DNS 服务提供商通信的主机名
 ttl: 30                                # TTL，DNS 记录的生存时间（秒）
 propagationTimeout: 120s               # PropagationTimeout，传播超时时间，指定更新记录后等待传播的最大时间，单位为秒（s）
-pollingInterval: 4s                    # PollingInterval，轮询间隔时间，指定系统检查 DNS 记录状态的频率，单位为秒（s）`
+pollingInterval: 4s                    # PollingInterval，轮询间隔时间，指定系统检查 DNS 记录状态的频率，单位为秒（s）
+ownerGroupID: ""                       # OwnerGroupID，创建的记录集所属的组 ID（共享区域必填）
+reviewTimeout: 0s                      # ReviewTimeout，等待人工审核变更的最长时间，为 0 时待审核的变更立即失败`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -89,7 +102,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +137,13 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+// The wait for the change to be complete is canceled with ctx.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	existingRecord, err := d.getRecordSet(info.EffectiveFQDN)
 	if err != nil {
@@ -134,7 +153,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	record := vinyldns.Record{Text: info.Value}
 
 	if existingRecord == nil || existingRecord.ID == "" {
-		err = d.createRecordSet(info.EffectiveFQDN, []vinyldns.Record{record})
+		err = d.createRecordSet(ctx, info.EffectiveFQDN, []vinyldns.Record{record})
 		if err != nil {
 			return fmt.Errorf("vinyldns: %w", err)
 		}
@@ -151,7 +170,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	records := existingRecord.Records
 	records = append(records, record)
 
-	err = d.updateRecordSet(existingRecord, records)
+	err = d.updateRecordSet(ctx, existingRecord, records)
 	if err != nil {
 		return fmt.Errorf("vinyldns: %w", err)
 	}
@@ -161,7 +180,13 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+// The wait for the change to be complete is canceled with ctx.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	existingRecord, err := d.getRecordSet(info.EffectiveFQDN)
 	if err != nil {
@@ -180,7 +205,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	if len(records) == 0 {
-		err = d.deleteRecordSet(existingRecord)
+		err = d.deleteRecordSet(ctx, existingRecord)
 		if err != nil {
 			return fmt.Errorf("vinyldns: %w", err)
 		}
@@ -188,7 +213,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return nil
 	}
 
-	err = d.updateRecordSet(existingRecord, records)
+	err = d.updateRecordSet(ctx, existingRecord, records)
 	if err != nil {
 		return fmt.Errorf("vinyldns: %w", err)
 	}
@@ -1,12 +1,19 @@
 package vinyldns
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vinyldns/go-vinyldns/vinyldns"
+	"lego-toolbox/internal/jobs"
 )
 
 const envDomain = envNamespace + "DOMAIN"
@@ -23,7 +30,9 @@ const (
 var envTest = tester.NewEnvTest(
 	EnvAccessKey,
 	EnvSecretKey,
-	EnvHost).
+	EnvHost,
+	EnvOwnerGroupID,
+	EnvReviewTimeout).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -200,6 +209,109 @@ func TestDNSProvider_Present(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_Present_ownerGroup(t *testing.T) {
+	mux, p := setupTest(t)
+
+	p.config.OwnerGroupID = "60000000-0000-0000-0000-000000000000"
+
+	mux.Handle("/", newMockRouter().
+		Get("/zones/name/"+targetRootDomain+".", http.StatusOK, "zoneByName").
+		Get("/zones/"+zoneID+"/recordsets", http.StatusOK, "recordSetsListAll-empty").
+		Get("/zones/"+zoneID+"/recordsets/"+newRecordSetID+"/changes/"+newCreateChangeID, http.StatusOK, "recordSetChange-create"),
+	)
+
+	var ownerGroupID string
+
+	mux.HandleFunc("POST /zones/"+zoneID+"/recordsets", func(rw http.ResponseWriter, req *http.Request) {
+		recordSet := vinyldns.RecordSet{}
+
+		err := json.NewDecoder(req.Body).Decode(&recordSet)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ownerGroupID = recordSet.OwnerGroupID
+
+		writeFixture(rw, http.StatusAccepted, "recordSetUpdate-create")
+	})
+
+	err := p.Present(targetDomain, "token", "123456d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, "60000000-0000-0000-0000-000000000000", ownerGroupID)
+}
+
+func TestDNSProvider_waitForChanges_pendingReview(t *testing.T) {
+	mux, p := setupTest(t)
+
+	mux.Handle("/", newMockRouter().
+		Get("/zones/"+zoneID+"/recordsets/"+newRecordSetID+"/changes/"+newCreateChangeID, http.StatusOK, "recordSetChange-pendingReview"),
+	)
+
+	err := p.waitForChanges(context.Background(), "CreateRS", newUpdateResponse())
+	require.ErrorIs(t, err, ErrPendingReview)
+}
+
+func TestDNSProvider_waitForChanges_awaitReview(t *testing.T) {
+	mux, p := setupTest(t)
+
+	p.config.PollingInterval = 10 * time.Millisecond
+	p.config.ReviewTimeout = 5 * time.Second
+
+	var calls atomic.Int32
+
+	mux.HandleFunc("GET /zones/"+zoneID+"/recordsets/"+newRecordSetID+"/changes/"+newCreateChangeID, func(rw http.ResponseWriter, _ *http.Request) {
+		// approved after the third query.
+		if calls.Add(1) <= 3 {
+			writeFixture(rw, http.StatusOK, "recordSetChange-pendingReview")
+			return
+		}
+
+		writeFixture(rw, http.StatusOK, "recordSetChange-create")
+	})
+
+	err := p.waitForChanges(context.Background(), "CreateRS", newUpdateResponse())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 4, calls.Load())
+}
+
+func TestDNSProvider_waitForChanges_reviewTimeout(t *testing.T) {
+	mux, p := setupTest(t)
+
+	p.config.PollingInterval = 10 * time.Millisecond
+	p.config.ReviewTimeout = 50 * time.Millisecond
+
+	mux.Handle("/", newMockRouter().
+		Get("/zones/"+zoneID+"/recordsets/"+newRecordSetID+"/changes/"+newCreateChangeID, http.StatusOK, "recordSetChange-pendingReview"),
+	)
+
+	err := p.waitForChanges(context.Background(), "CreateRS", newUpdateResponse())
+	require.ErrorIs(t, err, ErrPendingReview)
+	require.ErrorIs(t, err, jobs.ErrTimeout)
+}
+
+func newUpdateResponse() *vinyldns.RecordSetUpdateResponse {
+	return &vinyldns.RecordSetUpdateResponse{
+		Zone:      vinyldns.Zone{ID: zoneID},
+		RecordSet: vinyldns.RecordSet{ID: newRecordSetID},
+		ChangeID:  newCreateChangeID,
+	}
+}
+
+func writeFixture(rw http.ResponseWriter, statusCode int, filename string) {
+	data, err := os.ReadFile("./fixtures/" + filename + ".json")
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	_, _ = rw.Write(data)
+}
+
 func TestDNSProvider_CleanUp(t *testing.T) {
 	mux, p := setupTest(t)
 
@@ -1,11 +1,25 @@
 package vinyldns
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
-	"github.com/go-acme/lego/v4/platform/wait"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
+	"lego-toolbox/internal/jobs"
+)
+
+// ErrPendingReview is returned when a change is pending a manual review (e.g. a zone enforcing ACLs),
+// and the review is not awaited or not done in time (see Config.ReviewTimeout).
+var ErrPendingReview = errors.New("change pending review")
+
+// Statuses of the record set changes.
+const (
+	statusComplete      = "Complete"
+	statusFailed        = "Failed"
+	statusPendingReview = "PendingReview"
 )
 
 func (d *DNSProvider) getRecordSet(fqdn string) (*vinyldns.RecordSet, error) {
@@ -41,7 +55,7 @@ func (d *DNSProvider) getRecordSet(fqdn string) (*vinyldns.RecordSet, error) {
 	}
 }
 
-func (d *DNSProvider) createRecordSet(fqdn string, records []vinyldns.Record) error {
+func (d *DNSProvider) createRecordSet(ctx context.Context, fqdn string, records []vinyldns.Record) error {
 	zoneName, hostName, err := splitDomain(fqdn)
 	if err != nil {
 		return err
@@ -53,11 +67,12 @@ func (d *DNSProvider) createRecordSet(fqdn string, records []vinyldns.Record) er
 	}
 
 	recordSet := vinyldns.RecordSet{
-		Name:    hostName,
-		ZoneID:  zone.ID,
-		Type:    "TXT",
-		TTL:     d.config.TTL,
-		Records: records,
+		Name:         hostName,
+		ZoneID:       zone.ID,
+		OwnerGroupID: d.config.OwnerGroupID,
+		Type:         "TXT",
+		TTL:          d.config.TTL,
+		Records:      records,
 	}
 
 	resp, err := d.client.RecordSetCreate(&recordSet)
@@ -65,10 +80,10 @@ func (d *DNSProvider) createRecordSet(fqdn string, records []vinyldns.Record) er
 		return err
 	}
 
-	return d.waitForChanges("CreateRS", resp)
+	return d.waitForChanges(ctx, "CreateRS", resp)
 }
 
-func (d *DNSProvider) updateRecordSet(recordSet *vinyldns.RecordSet, newRecords []vinyldns.Record) error {
+func (d *DNSProvider) updateRecordSet(ctx context.Context, recordSet *vinyldns.RecordSet, newRecords []vinyldns.Record) error {
 	operation := "delete"
 	if len(recordSet.Records) < len(newRecords) {
 		operation = "add"
@@ -82,34 +97,57 @@ func (d *DNSProvider) updateRecordSet(recordSet *vinyldns.RecordSet, newRecords
 		return err
 	}
 
-	return d.waitForChanges("UpdateRS - "+operation, resp)
+	return d.waitForChanges(ctx, "UpdateRS - "+operation, resp)
 }
 
-func (d *DNSProvider) deleteRecordSet(existingRecord *vinyldns.RecordSet) error {
+func (d *DNSProvider) deleteRecordSet(ctx context.Context, existingRecord *vinyldns.RecordSet) error {
 	resp, err := d.client.RecordSetDelete(existingRecord.ZoneID, existingRecord.ID)
 	if err != nil {
 		return err
 	}
 
-	return d.waitForChanges("DeleteRS", resp)
+	return d.waitForChanges(ctx, "DeleteRS", resp)
 }
 
-func (d *DNSProvider) waitForChanges(operation string, resp *vinyldns.RecordSetUpdateResponse) error {
-	return wait.For("vinyldns", d.config.PropagationTimeout, d.config.PollingInterval,
-		func() (bool, error) {
-			change, err := d.client.RecordSetChange(resp.Zone.ID, resp.RecordSet.ID, resp.ChangeID)
-			if err != nil {
-				return false, fmt.Errorf("failed to query change status: %w", err)
-			}
-
-			if change.Status == "Complete" {
-				return true, nil
-			}
-
-			return false, fmt.Errorf("waiting operation: %s, zoneID: %s, recordsetID: %s, changeID: %s",
-				operation, resp.Zone.ID, resp.RecordSet.ID, resp.ChangeID)
-		},
-	)
+// waitForChanges waits for the change to be complete.
+// A change pending review fails with ErrPendingReview, unless the review is awaited (see Config.ReviewTimeout).
+func (d *DNSProvider) waitForChanges(ctx context.Context, operation string, resp *vinyldns.RecordSetUpdateResponse) error {
+	name := fmt.Sprintf("vinyldns: %s (zoneID: %s, recordsetID: %s, changeID: %s)", operation, resp.Zone.ID, resp.RecordSet.ID, resp.ChangeID)
+
+	_, err := jobs.Poll(ctx, jobs.DefaultOptions(name, d.config.PropagationTimeout, d.config.PollingInterval), d.changeStatus(resp, false))
+	if err == nil || !errors.Is(err, ErrPendingReview) || d.config.ReviewTimeout <= 0 {
+		return err
+	}
+
+	opts := jobs.DefaultOptions(name+" review", d.config.ReviewTimeout, d.config.PollingInterval)
+
+	_, err = jobs.Poll(ctx, opts, d.changeStatus(resp, true))
+	if errors.Is(err, jobs.ErrTimeout) {
+		return fmt.Errorf("%w: %w", ErrPendingReview, err)
+	}
+
+	return err
+}
+
+func (d *DNSProvider) changeStatus(resp *vinyldns.RecordSetUpdateResponse, awaitReview bool) func(context.Context) (jobs.Status, error) {
+	return func(_ context.Context) (jobs.Status, error) {
+		change, err := d.client.RecordSetChange(resp.Zone.ID, resp.RecordSet.ID, resp.ChangeID)
+		if err != nil {
+			return jobs.Status{}, fmt.Errorf("failed to query change status: %w", err)
+		}
+
+		status := jobs.Status{
+			Done:   strings.EqualFold(change.Status, statusComplete),
+			Failed: strings.EqualFold(change.Status, statusFailed),
+			State:  change.Status,
+		}
+
+		if !awaitReview && strings.EqualFold(change.Status, statusPendingReview) {
+			return status, jobs.Permanent(fmt.Errorf("%w: the zone requires an approval, set a review timeout to wait for it", ErrPendingReview))
+		}
+
+		return status, nil
+	}
 }
 
 // splitDomain splits the hostname from the authoritative zone, and returns both parts.
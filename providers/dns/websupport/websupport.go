@@ -5,14 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"lego-toolbox/providers/dns/internal/challengestore"
 	"lego-toolbox/providers/dns/websupport/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -27,6 +29,7 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 	EnvSequenceInterval   = envNamespace + "SEQUENCE_INTERVAL"
+	EnvAggressiveCleanup  = envNamespace + "AGGRESSIVE_CLEANUP"
 )
 
 // Config is used to configure the creation of the DNSProvider.
@@ -37,6 +40,7 @@ type Config struct {
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
 	TTL                int           `yaml:"ttl"`
+	AggressiveCleanup  bool          `yaml:"aggressiveCleanup"`
 	HTTPClient         *http.Client  `yaml:"-"`
 }
 
@@ -47,6 +51,7 @@ func NewDefaultConfig() *Config {
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
 		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		AggressiveCleanup:  env.GetOrDefaultBool(EnvAggressiveCleanup, false),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -73,7 +78,8 @@ secret: "your_secret"                # 机密信息
 propagationTimeout: 60s              # 传播超时时间，单位为秒
 pollingInterval: 2s                  # 轮询间隔时间，单位为秒
 sequenceInterval: 60s                # 序列间隔时间，单位为秒
-ttl: 600                             # 生存时间，单位为秒`
+ttl: 600                             # 生存时间，单位为秒
+aggressiveCleanup: false             # 记录 ID 未知时（如重启后）在区域中搜索并删除匹配的 TXT 记录`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -81,8 +87,7 @@ type DNSProvider struct {
 	config *Config
 	client *internal.Client
 
-	recordIDs   map[string]int
-	recordIDsMu sync.Mutex
+	recordIDs *challengestore.Store[int]
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Websupport.
@@ -103,7 +108,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -128,13 +133,19 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{
 		config:    config,
 		client:    client,
-		recordIDs: make(map[string]int),
+		recordIDs: challengestore.New[int](),
 	}, nil
 }
 
+// SetStateFile persists the state of the presented challenges to path,
+// so they can be cleaned up by another process, or after a restart.
+func (d *DNSProvider) SetStateFile(path string) error {
+	return d.recordIDs.Persist(path)
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -159,9 +170,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	if resp.Status == internal.StatusSuccess {
-		d.recordIDsMu.Lock()
-		d.recordIDs[token] = resp.Item.ID
-		d.recordIDsMu.Unlock()
+		d.recordIDs.Set(token, info.EffectiveFQDN, resp.Item.ID)
 
 		return nil
 	}
@@ -171,7 +180,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -179,10 +188,12 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	// gets the record's unique ID
-	d.recordIDsMu.Lock()
-	recordID, ok := d.recordIDs[token]
-	d.recordIDsMu.Unlock()
+	recordID, ok := d.recordIDs.Get(token, info.EffectiveFQDN)
 	if !ok {
+		if d.config.AggressiveCleanup {
+			return d.cleanUpByValue(context.Background(), authZone, info)
+		}
+
 		return fmt.Errorf("websupport: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
 
@@ -191,10 +202,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("websupport: delete record: %w", err)
 	}
 
-	// deletes record ID from map
-	d.recordIDsMu.Lock()
-	delete(d.recordIDs, token)
-	d.recordIDsMu.Unlock()
+	d.recordIDs.Delete(token, info.EffectiveFQDN)
 
 	if resp.Status == internal.StatusSuccess {
 		return nil
@@ -203,6 +211,44 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return fmt.Errorf("websupport: %w", internal.ParseError(resp))
 }
 
+// cleanUpByValue deletes the TXT records of the zone matching the challenge, used when the record ID is unknown.
+func (d *DNSProvider) cleanUpByValue(ctx context.Context, authZone string, info dns01.ChallengeInfo) error {
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return fmt.Errorf("websupport: %w", err)
+	}
+
+	records, err := d.client.ListRecords(ctx, dns01.UnFqdn(authZone))
+	if err != nil {
+		return fmt.Errorf("websupport: list records: %w", err)
+	}
+
+	var found bool
+
+	for _, record := range records.Items {
+		if record.Type != "TXT" || record.Name != subDomain || record.Content != info.Value {
+			continue
+		}
+
+		found = true
+
+		resp, err := d.client.DeleteRecord(ctx, dns01.UnFqdn(authZone), record.ID)
+		if err != nil {
+			return fmt.Errorf("websupport: delete record: %w", err)
+		}
+
+		if resp.Status != internal.StatusSuccess {
+			return fmt.Errorf("websupport: %w", internal.ParseError(resp))
+		}
+	}
+
+	if !found {
+		log.Infof("websupport: no TXT record found for %s", info.EffectiveFQDN)
+	}
+
+	return nil
+}
+
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
 // Adjusting here to cope with spikes in propagation times.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,11 +12,35 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"golang.org/x/time/rate"
 	"lego-toolbox/providers/dns/internal/errutils"
+	"lego-toolbox/quota"
 )
 
 const baseURL = "https://api.wedos.com/wapi/json"
 
+// DailyWindow is the window of the daily limit of the WAPI requests.
+const DailyWindow = 24 * time.Hour
+
+// DailyLimitError is returned when a WAPI request would exceed the daily limit of the account:
+// the request is not sent, the renewals should be postponed by RetryAfter.
+type DailyLimitError struct {
+	// Limit is the number of WAPI requests allowed per DailyWindow.
+	Limit int
+	// RetryAfter is the time before a request fits in the daily limit.
+	RetryAfter time.Duration
+
+	err *quota.QuotaExceededError
+}
+
+func (e *DailyLimitError) Error() string {
+	return fmt.Sprintf("daily limit of %d WAPI requests reached, retry after %s", e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+func (e *DailyLimitError) Unwrap() error {
+	return e.err
+}
+
 // Client the API client for Webos.
 type Client struct {
 	username string
@@ -23,6 +48,13 @@ type Client struct {
 
 	baseURL    string
 	HTTPClient *http.Client
+
+	// Limiter throttles the WAPI requests, unlimited when nil.
+	Limiter *rate.Limiter
+
+	// Counter counts the WAPI requests of the account over DailyWindow, and enforces the daily limit if any.
+	// The requests are not counted when nil.
+	Counter *quota.Tracker
 }
 
 // NewClient creates a new Client.
@@ -35,6 +67,28 @@ func NewClient(username string, password string) *Client {
 	}
 }
 
+// NewCounter creates a counter of the WAPI requests, persisted to path (in memory when empty).
+// The requests exceeding dailyLimit fail with a *DailyLimitError, the requests are only counted when zero.
+func NewCounter(path, username string, dailyLimit int) (*quota.Tracker, error) {
+	return quota.NewTracker(path, map[string]quota.Limit{
+		counterKey(username): {Requests: dailyLimit, Window: DailyWindow},
+	})
+}
+
+// DailyUsage returns the number of WAPI requests of the account over the last DailyWindow.
+func (c *Client) DailyUsage() (int, error) {
+	if c.Counter == nil {
+		return 0, errors.New("the requests are not counted")
+	}
+
+	return c.Counter.Usage(counterKey(c.username))
+}
+
+// counterKey keys the counter by account: the limit is the one of the account.
+func counterKey(username string) string {
+	return "wedos:" + username
+}
+
 // GetRecords lists all the records in the zone.
 // https://kb.wedos.com/en/wapi-api-interface/wapi-command-dns-rows-list/
 func (c *Client) GetRecords(ctx context.Context, zone string) ([]DNSRow, error) {
@@ -126,6 +180,11 @@ func (c *Client) Ping(ctx context.Context) error {
 }
 
 func (c *Client) do(req *http.Request, result Response) error {
+	err := c.throttle(req.Context())
+	if err != nil {
+		return err
+	}
+
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return errutils.NewHTTPDoError(req, err)
@@ -152,6 +211,29 @@ func (c *Client) do(req *http.Request, result Response) error {
 	return err
 }
 
+// throttle waits for the Limiter, then counts the request.
+func (c *Client) throttle(ctx context.Context) error {
+	if c.Limiter != nil {
+		err := c.Limiter.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("throttling: %w", err)
+		}
+	}
+
+	if c.Counter == nil {
+		return nil
+	}
+
+	err := c.Counter.Reserve(counterKey(c.username), 1)
+
+	var exceeded *quota.QuotaExceededError
+	if errors.As(err, &exceeded) {
+		return &DailyLimitError{Limit: exceeded.Limit.Requests, RetryAfter: exceeded.RetryAfter, err: exceeded}
+	}
+
+	return err
+}
+
 func (c *Client) newRequest(ctx context.Context, command string, payload any) (*http.Request, error) {
 	requestObject := map[string]any{
 		"request": APIRequest{
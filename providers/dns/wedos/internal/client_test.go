@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"lego-toolbox/quota"
 )
 
 func setupNew(t *testing.T, expectedForm string, filename string) *Client {
@@ -145,3 +149,61 @@ func TestClient_Commit(t *testing.T) {
 	err := client.Commit(context.Background(), "example.com.")
 	require.NoError(t, err)
 }
+
+func TestClient_dailyLimit(t *testing.T) {
+	expectedForm := `{"request":{"user":"user","auth":"xxx","command":"dns-domain-commit","data":{"name":"example.com"}}}`
+	client := setupNew(t, expectedForm, commandDNSDomainCommit)
+
+	path := filepath.Join(t.TempDir(), "wedos.json")
+
+	counter, err := NewCounter(path, "user", 2)
+	require.NoError(t, err)
+
+	client.Counter = counter
+
+	require.NoError(t, client.Commit(context.Background(), "example.com"))
+	require.NoError(t, client.Commit(context.Background(), "example.com"))
+
+	err = client.Commit(context.Background(), "example.com")
+	require.ErrorContains(t, err, "daily limit of 2 WAPI requests reached, retry after ")
+
+	var limitErr *DailyLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 2, limitErr.Limit)
+	assert.InDelta(t, DailyWindow, limitErr.RetryAfter, float64(time.Minute))
+
+	var exceeded *quota.QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+
+	// the counter of the previous runs.
+	counter, err = NewCounter(path, "user", 2)
+	require.NoError(t, err)
+
+	client.Counter = counter
+
+	usage, err := client.DailyUsage()
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage)
+
+	// the limit is the one of the account.
+	client.username = "other"
+
+	usage, err = client.DailyUsage()
+	require.NoError(t, err)
+	assert.Equal(t, 0, usage)
+}
+
+func TestClient_throttle(t *testing.T) {
+	expectedForm := `{"request":{"user":"user","auth":"xxx","command":"dns-domain-commit","data":{"name":"example.com"}}}`
+	client := setupNew(t, expectedForm, commandDNSDomainCommit)
+
+	client.Limiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+
+	start := time.Now()
+
+	for range 3 {
+		require.NoError(t, client.Commit(context.Background(), "example.com"))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
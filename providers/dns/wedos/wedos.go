@@ -5,14 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"golang.org/x/time/rate"
 	"lego-toolbox/providers/dns/wedos/internal"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
@@ -26,8 +28,20 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+
+	EnvRequestInterval = envNamespace + "REQUEST_INTERVAL"
+	EnvDailyLimit      = envNamespace + "DAILY_LIMIT"
+	EnvCounterFile     = envNamespace + "COUNTER_FILE"
 )
 
+// defaultRequestInterval spaces the WAPI requests.
+const defaultRequestInterval = time.Second
+
+// DailyLimitError is returned when the daily limit of WAPI requests is reached (see Config.DailyLimit):
+// the request is not sent, the renewals should be postponed by RetryAfter.
+// It wraps a *quota.QuotaExceededError.
+type DailyLimitError = internal.DailyLimitError
+
 const minTTL = 5 * 60 // 5 minutes
 
 // Config is used to configure the creation of the DNSProvider.
@@ -37,7 +51,13 @@ type Config struct {
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
-	HTTPClient         *http.Client  `yaml:"-"`
+	// RequestInterval is the minimum time between two WAPI requests, the requests are not throttled when zero.
+	RequestInterval time.Duration `yaml:"requestInterval"`
+	// DailyLimit is the number of WAPI requests allowed per 24 hours, the requests are only counted when zero.
+	DailyLimit int `yaml:"dailyLimit"`
+	// CounterFile persists the counter of the WAPI requests, shared by the successive runs (in memory when empty).
+	CounterFile string       `yaml:"counterFile"`
+	HTTPClient  *http.Client `yaml:"-"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -46,6 +66,9 @@ func NewDefaultConfig() *Config {
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 10*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
+		RequestInterval:    env.GetOrDefaultSecond(EnvRequestInterval, defaultRequestInterval),
+		DailyLimit:         env.GetOrDefaultInt(EnvDailyLimit, 0),
+		CounterFile:        env.GetOrFile(EnvCounterFile),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
 		},
@@ -58,6 +81,7 @@ func DefaultConfig() *Config {
 		PropagationTimeout: 10 * time.Minute,
 		PollingInterval:    10 * time.Second,
 		TTL:                minTTL,
+		RequestInterval:    defaultRequestInterval,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -70,7 +94,10 @@ username: "your_username"                # 用户名
 password: "your_password"                # 密码
 propagationTimeout: 10m                  # 传播超时时间，单位为秒
 pollingInterval: 10s                     # 轮询间隔时间，单位为秒
-ttl: 300                                 # TTL 值，单位为秒`
+ttl: 300                                 # TTL 值，单位为秒
+requestInterval: 1s                      # 两次 WAPI 请求之间的最短间隔，为 0 时不限速
+dailyLimit: 0                            # 每 24 小时允许的 WAPI 请求数，为 0 时仅计数
+counterFile: ""                          # WAPI 请求计数器的持久化文件，为空时仅保存在内存中`
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -96,7 +123,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -117,12 +144,31 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("wedos: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
 	}
 
+	if config.RequestInterval < 0 {
+		return nil, fmt.Errorf("wedos: invalid request interval: %s", config.RequestInterval)
+	}
+
+	if config.DailyLimit < 0 {
+		return nil, fmt.Errorf("wedos: invalid daily limit: %d", config.DailyLimit)
+	}
+
 	client := internal.NewClient(config.Username, config.Password)
 
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
 	}
 
+	if config.RequestInterval > 0 {
+		client.Limiter = rate.NewLimiter(rate.Every(config.RequestInterval), 1)
+	}
+
+	counter, err := internal.NewCounter(config.CounterFile, config.Username, config.DailyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("wedos: %w", err)
+	}
+
+	client.Counter = counter
+
 	return &DNSProvider{config: config, client: client}, nil
 }
 
@@ -132,11 +178,22 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// DailyUsage returns the number of WAPI requests over the last 24 hours,
+// including the requests of the previous runs when the counter is persisted (see Config.CounterFile).
+func (d *DNSProvider) DailyUsage() (int, error) {
+	usage, err := d.client.DailyUsage()
+	if err != nil {
+		return 0, fmt.Errorf("wedos: %w", err)
+	}
+
+	return usage, nil
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
@@ -184,7 +241,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
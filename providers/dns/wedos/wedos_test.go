@@ -2,6 +2,7 @@ package wedos
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
@@ -9,7 +10,7 @@ import (
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvUsername, EnvPassword).
+var envTest = tester.NewEnvTest(EnvUsername, EnvPassword, EnvRequestInterval, EnvDailyLimit, EnvCounterFile).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -73,16 +74,38 @@ func TestNewDNSProvider(t *testing.T) {
 
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		username string
-		password string
-		expected string
+		desc            string
+		username        string
+		password        string
+		requestInterval time.Duration
+		dailyLimit      int
+		expected        string
 	}{
 		{
 			desc:     "success",
 			username: "admin@example.com",
 			password: "secret",
 		},
+		{
+			desc:       "success: daily limit",
+			username:   "admin@example.com",
+			password:   "secret",
+			dailyLimit: 1000,
+		},
+		{
+			desc:            "invalid request interval",
+			username:        "admin@example.com",
+			password:        "secret",
+			requestInterval: -time.Second,
+			expected:        "wedos: invalid request interval: -1s",
+		},
+		{
+			desc:       "invalid daily limit",
+			username:   "admin@example.com",
+			password:   "secret",
+			dailyLimit: -1,
+			expected:   "wedos: invalid daily limit: -1",
+		},
 		{
 			desc:     "missing username",
 			password: "secret",
@@ -100,6 +123,11 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			config := NewDefaultConfig()
 			config.Username = test.username
 			config.Password = test.password
+			config.DailyLimit = test.dailyLimit
+
+			if test.requestInterval != 0 {
+				config.RequestInterval = test.requestInterval
+			}
 
 			p, err := NewDNSProviderConfig(config)
 
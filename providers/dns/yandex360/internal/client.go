@@ -143,5 +143,5 @@ func parseError(req *http.Request, resp *http.Response) error {
 		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
 	}
 
-	return fmt.Errorf("[status code: %d] %w", resp.StatusCode, apiErr)
+	return errutils.NewAPIError(req, resp.StatusCode, raw, apiErr).WithCode(strconv.Itoa(int(apiErr.Code)))
 }
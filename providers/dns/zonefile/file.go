@@ -0,0 +1,222 @@
+package zonefile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+func readFile(path string) ([]byte, os.FileMode, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read file: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read file: %w", err)
+	}
+
+	return content, info.Mode().Perm(), nil
+}
+
+// writeFile replaces the file atomically (temporary file then rename), keeping its permissions.
+func writeFile(path string, content []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+func hasLine(content []byte, line string) bool {
+	for _, l := range bytes.Split(content, []byte("\n")) {
+		if string(bytes.TrimSpace(l)) == line {
+			return true
+		}
+	}
+
+	return false
+}
+
+func appendLine(content []byte, line string) []byte {
+	result := bytes.Clone(content)
+
+	if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+		result = append(result, '\n')
+	}
+
+	result = append(result, line...)
+
+	return append(result, '\n')
+}
+
+// removeLine removes the lines matching the line, it returns false if there is no matching line.
+func removeLine(content []byte, line string) ([]byte, bool) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+
+	var result []byte
+
+	var removed bool
+
+	for _, l := range lines {
+		if string(bytes.TrimSpace(l)) == line {
+			removed = true
+			continue
+		}
+
+		result = append(result, l...)
+	}
+
+	return result, removed
+}
+
+// bumpSerial increments the serial of the SOA record of the zone file.
+func bumpSerial(content []byte, now time.Time) ([]byte, error) {
+	start, end, err := findSerial(content)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := strconv.ParseUint(string(content[start:end]), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOA serial %q: %w", content[start:end], err)
+	}
+
+	next := strconv.FormatUint(uint64(nextSerial(uint32(serial), now)), 10)
+
+	result := make([]byte, 0, len(content)+len(next))
+	result = append(result, content[:start]...)
+	result = append(result, next...)
+	result = append(result, content[end:]...)
+
+	return result, nil
+}
+
+// nextSerial returns the next serial.
+// A date based serial (YYYYMMDDnn) moves to the current date when it is older.
+func nextSerial(serial uint32, now time.Time) uint32 {
+	next := serial + 1
+
+	today, err := strconv.ParseUint(now.UTC().Format("20060102")+"00", 10, 32)
+	if err != nil {
+		return next
+	}
+
+	if isDateSerial(serial) && uint32(today) > next {
+		return uint32(today)
+	}
+
+	return next
+}
+
+func isDateSerial(serial uint32) bool {
+	_, err := time.Parse("20060102", strconv.FormatUint(uint64(serial/100), 10))
+
+	return err == nil
+}
+
+// findSerial returns the position of the serial of the SOA record.
+// The SOA record is: [owner] [ttl] [class] SOA mname rname ( serial refresh retry expire minimum ).
+func findSerial(content []byte) (int, int, error) {
+	soa := -1
+
+	for pos := 0; pos < len(content); {
+		start, end := nextToken(content, pos)
+		if start < 0 {
+			break
+		}
+
+		if bytes.EqualFold(content[start:end], []byte("SOA")) {
+			soa = end
+			break
+		}
+
+		pos = end
+	}
+
+	if soa < 0 {
+		return 0, 0, errors.New("SOA record not found")
+	}
+
+	pos := soa
+
+	// skip mname and rname.
+	for range 2 {
+		start, end := nextToken(content, pos)
+		if start < 0 {
+			return 0, 0, errors.New("incomplete SOA record")
+		}
+
+		pos = end
+	}
+
+	start, end := nextToken(content, pos)
+	if start < 0 {
+		return 0, 0, errors.New("incomplete SOA record")
+	}
+
+	return start, end, nil
+}
+
+// nextToken returns the position of the next token, skipping spaces, parentheses, and comments.
+func nextToken(content []byte, pos int) (int, int) {
+	for pos < len(content) {
+		switch c := content[pos]; {
+		case c == ';':
+			for pos < len(content) && content[pos] != '\n' {
+				pos++
+			}
+
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '(' || c == ')':
+			pos++
+
+		default:
+			start := pos
+			if c == '"' {
+				pos++
+				for pos < len(content) && content[pos] != '"' {
+					pos++
+				}
+
+				return start, min(pos+1, len(content))
+			}
+
+			for pos < len(content) && !bytes.ContainsRune([]byte(" \t\r\n();"), rune(content[pos])) {
+				pos++
+			}
+
+			return start, pos
+		}
+	}
+
+	return -1, -1
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package zonefile
+
+// lockFile is a no-op: the changes are only serialized inside the process.
+func lockFile(_ string) (func(), error) {
+	return func() {}, nil
+}
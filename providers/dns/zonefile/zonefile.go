@@ -0,0 +1,312 @@
+// Package zonefile implements a DNS provider for solving the DNS-01 challenge
+// by editing a local zone file (NSD, BIND) or Unbound local-data entries, then reloading the daemon.
+package zonefile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/miekg/dns"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// File formats.
+const (
+	// FormatZone is a RFC 1035 zone file (NSD, BIND, Knot).
+	FormatZone = "zone"
+	// FormatUnbound is a file of Unbound local-data entries, usually included from unbound.conf.
+	FormatUnbound = "unbound"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "ZONEFILE_"
+
+	EnvFormat         = envNamespace + "FORMAT"
+	EnvPath           = envNamespace + "PATH"
+	EnvZone           = envNamespace + "ZONE"
+	EnvReloadCommand  = envNamespace + "RELOAD_COMMAND"
+	EnvCommandTimeout = envNamespace + "COMMAND_TIMEOUT"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Format string `yaml:"format"`
+	Path   string `yaml:"path"`
+	// Zone is the origin of the zone file, required with the zone format.
+	Zone string `yaml:"zone"`
+	// ReloadCommand is run after each change of the file.
+	// Defaults to `nsd-control reload <zone>` (zone format) or `unbound-control reload` (unbound format).
+	ReloadCommand      []string      `yaml:"reloadCommand"`
+	CommandTimeout     time.Duration `yaml:"commandTimeout"`
+	TTL                int           `yaml:"ttl"`
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Format:             env.GetOrDefaultString(EnvFormat, FormatZone),
+		CommandTimeout:     env.GetOrDefaultSecond(EnvCommandTimeout, 30*time.Second),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+	}
+}
+
+// DefaultConfig returns a default configuration for the DNSProvider.
+func DefaultConfig() *Config {
+	return &Config{
+		Format:             FormatZone,
+		CommandTimeout:     30 * time.Second,
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
+	}
+}
+
+func GetYamlTemple() string {
+	return `# config.yaml
+format: "zone"                        # 文件格式：zone（RFC 1035 区域文件，NSD/BIND）或 unbound（local-data 条目）
+path: "/etc/nsd/zones/example.com.zone" # 文件路径
+zone: "example.com"                   # 区域名称，zone 格式时必填
+reloadCommand: []                     # 修改后执行的重载命令，默认 nsd-control reload <zone> 或 unbound-control reload
+commandTimeout: 30s                   # 重载命令超时时间
+ttl: 120                              # TXT 记录的 TTL
+propagationTimeout: 60s               # 传播超时时间
+pollingInterval: 2s                   # 轮询间隔时间`
+}
+
+// ParseConfig parse bytes to config
+func ParseConfig(rawConfig []byte) (*Config, error) {
+	config := DefaultConfig()
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	zone   string
+
+	// mu serializes the changes inside the process, the file lock serializes them between processes.
+	mu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for a local zone file.
+// The file must be passed in the environment variable: ZONEFILE_PATH.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvPath)
+	if err != nil {
+		return nil, fmt.Errorf("zonefile: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Path = values[EnvPath]
+	config.Zone = env.GetOrFile(EnvZone)
+	config.ReloadCommand = strings.Fields(env.GetOrFile(EnvReloadCommand))
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for a local zone file.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("zonefile: the configuration of the DNS provider is nil")
+	}
+
+	if config.Path == "" {
+		return nil, errors.New("zonefile: path is required")
+	}
+
+	var zone string
+
+	switch config.Format {
+	case FormatZone:
+		if config.Zone == "" {
+			return nil, errors.New("zonefile: zone is required with the zone format")
+		}
+
+		zone = dns.CanonicalName(config.Zone)
+
+		if len(config.ReloadCommand) == 0 {
+			config.ReloadCommand = []string{"nsd-control", "reload", dns01.UnFqdn(zone)}
+		}
+
+	case FormatUnbound:
+		if config.Zone != "" {
+			zone = dns.CanonicalName(config.Zone)
+		}
+
+		if len(config.ReloadCommand) == 0 {
+			config.ReloadCommand = []string{"unbound-control", "reload"}
+		}
+
+	default:
+		return nil, fmt.Errorf("zonefile: unsupported format %q, must be %q or %q", config.Format, FormatZone, FormatUnbound)
+	}
+
+	return &DNSProvider{config: config, zone: zone}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	line, err := d.recordLine(info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return fmt.Errorf("zonefile: %w", err)
+	}
+
+	err = d.update(func(content []byte) ([]byte, bool) {
+		if hasLine(content, line) {
+			return content, false
+		}
+
+		return appendLine(content, line), true
+	})
+	if err != nil {
+		return fmt.Errorf("zonefile: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	line, err := d.recordLine(info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return fmt.Errorf("zonefile: %w", err)
+	}
+
+	err = d.update(func(content []byte) ([]byte, bool) {
+		return removeLine(content, line)
+	})
+	if err != nil {
+		return fmt.Errorf("zonefile: %w", err)
+	}
+
+	return nil
+}
+
+// recordLine returns the line of the TXT record in the format of the file.
+func (d *DNSProvider) recordLine(fqdn, value string) (string, error) {
+	fqdn = dns.CanonicalName(fqdn)
+
+	if d.zone != "" && !dns.IsSubDomain(d.zone, fqdn) {
+		return "", fmt.Errorf("%s is not in the zone %s", fqdn, d.zone)
+	}
+
+	rr := &dns.TXT{
+		Hdr: dns.RR_Header{Name: fqdn, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: uint32(d.config.TTL)},
+		Txt: []string{value},
+	}
+
+	record := strings.Join(strings.Fields(rr.String()), " ")
+
+	if d.config.Format == FormatUnbound {
+		return fmt.Sprintf("local-data: '%s'", record), nil
+	}
+
+	return record, nil
+}
+
+// update applies the change to the file under lock, bumps the serial of the zone, and reloads the daemon.
+func (d *DNSProvider) update(change func(content []byte) ([]byte, bool)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	unlock, err := lockFile(d.config.Path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock: %w", err)
+	}
+
+	defer unlock()
+
+	content, mode, err := readFile(d.config.Path)
+	if err != nil {
+		return err
+	}
+
+	updated, changed := change(content)
+	if !changed {
+		return nil
+	}
+
+	if d.config.Format == FormatZone {
+		updated, err = bumpSerial(updated, time.Now())
+		if err != nil {
+			return err
+		}
+
+		err = checkZone(updated, d.zone, d.config.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeFile(d.config.Path, updated, mode)
+	if err != nil {
+		return err
+	}
+
+	return d.reload()
+}
+
+func (d *DNSProvider) reload() error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.CommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.config.ReloadCommand[0], d.config.ReloadCommand[1:]...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("reload command %q: %w: %s", strings.Join(d.config.ReloadCommand, " "), err, strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
+
+// checkZone parses the zone content to avoid writing a broken zone file.
+func checkZone(content []byte, origin, path string) error {
+	zp := dns.NewZoneParser(bytes.NewReader(content), origin, path)
+	zp.SetIncludeAllowed(true)
+
+	for _, ok := zp.Next(); ok; _, ok = zp.Next() {
+	}
+
+	if err := zp.Err(); err != nil {
+		return fmt.Errorf("invalid zone file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package zonefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/rawrecord"
+)
+
+var envTest = tester.NewEnvTest(EnvPath, EnvZone, EnvFormat, EnvReloadCommand)
+
+const zoneContent = `$ORIGIN example.com.
+$TTL 3600
+@ IN SOA ns1.example.com. hostmaster.example.com. (
+        42         ; serial
+        3600       ; refresh
+        900        ; retry
+        604800     ; expire
+        300 )      ; minimum
+@ IN NS ns1.example.com.
+ns1 IN A 192.0.2.1
+`
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvPath: "/etc/nsd/example.com.zone",
+				EnvZone: "example.com",
+			},
+		},
+		{
+			desc: "success (unbound)",
+			envVars: map[string]string{
+				EnvPath:   "/etc/unbound/acme.conf",
+				EnvFormat: FormatUnbound,
+			},
+		},
+		{
+			desc:     "missing path",
+			envVars:  map[string]string{},
+			expected: "zonefile: some credentials information are missing: ZONEFILE_PATH",
+		},
+		{
+			desc: "missing zone",
+			envVars: map[string]string{
+				EnvPath: "/etc/nsd/example.com.zone",
+			},
+			expected: "zonefile: zone is required with the zone format",
+		},
+		{
+			desc: "unsupported format",
+			envVars: map[string]string{
+				EnvPath:   "/etc/nsd/example.com.zone",
+				EnvFormat: "bind",
+			},
+			expected: `zonefile: unsupported format "bind", must be "zone" or "unbound"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotEmpty(t, p.config.ReloadCommand)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("format: unbound\npath: /etc/unbound/acme.conf\nreloadCommand: [unbound-control, reload]\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatUnbound, config.Format)
+	assert.Equal(t, []string{"unbound-control", "reload"}, config.ReloadCommand)
+	assert.Equal(t, 30*time.Second, config.CommandTimeout)
+}
+
+func TestDNSProvider_zone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	require.NoError(t, os.WriteFile(path, []byte(zoneContent), 0o640))
+
+	config := DefaultConfig()
+	config.Path = path
+	config.Zone = "example.com"
+	config.ReloadCommand = []string{"true"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	// idempotent
+	err = p.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "43         ; serial")
+	assert.Contains(t, string(content), "\n_acme-challenge.example.com. 120 IN TXT \"")
+	assert.Equal(t, 1, strings.Count(string(content), "_acme-challenge"))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	err = p.CleanUp("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(content), "_acme-challenge")
+	assert.Contains(t, string(content), "44         ; serial")
+}
+
+func TestDNSProvider_zone_outside(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	require.NoError(t, os.WriteFile(path, []byte(zoneContent), 0o640))
+
+	config := DefaultConfig()
+	config.Path = path
+	config.Zone = "example.com"
+	config.ReloadCommand = []string{"true"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.org", "", "123d==")
+	require.EqualError(t, err, "zonefile: _acme-challenge.example.org. is not in the zone example.com.")
+}
+
+func TestDNSProvider_unbound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acme.conf")
+	require.NoError(t, os.WriteFile(path, []byte("# managed by lego-toolbox"), 0o600))
+
+	config := DefaultConfig()
+	config.Format = FormatUnbound
+	config.Path = path
+	config.ReloadCommand = []string{"true"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	info := rawrecord.GetChallengeInfo("example.com", "123d==")
+
+	assert.Equal(t, "# managed by lego-toolbox\nlocal-data: '_acme-challenge.example.com. 120 IN TXT \""+info.Value+"\"'\n", string(content))
+
+	err = p.CleanUp("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "# managed by lego-toolbox\n", string(content))
+}
+
+func TestDNSProvider_reloadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acme.conf")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	config := DefaultConfig()
+	config.Format = FormatUnbound
+	config.Path = path
+	config.ReloadCommand = []string{"false"}
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "", "123d==")
+	require.EqualError(t, err, `zonefile: reload command "false": exit status 1: `)
+}
+
+func Test_bumpSerial(t *testing.T) {
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+		err      string
+	}{
+		{
+			desc:     "multi-line",
+			content:  "@ IN SOA ns1 hostmaster (\n 2024010101 ; serial\n 3600 900 604800 300 )\n",
+			expected: " 2024031500 ; serial",
+		},
+		{
+			desc:     "single line",
+			content:  "@ 3600 IN SOA ns1 hostmaster 42 3600 900 604800 300\n",
+			expected: "SOA ns1 hostmaster 43 3600",
+		},
+		{
+			desc:     "comment before serial",
+			content:  "@ IN SOA ns1 hostmaster ( ; comment\n 2024031507 3600 900 604800 300 )\n",
+			expected: " 2024031508 3600",
+		},
+		{
+			desc:    "no SOA",
+			content: "@ IN NS ns1\n",
+			err:     "SOA record not found",
+		},
+		{
+			desc:    "invalid serial",
+			content: "@ IN SOA ns1 hostmaster foo 3600 900 604800 300\n",
+			err:     `invalid SOA serial "foo": strconv.ParseUint: parsing "foo": invalid syntax`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			content, err := bumpSerial([]byte(test.content), now)
+
+			if test.err == "" {
+				require.NoError(t, err)
+				assert.Contains(t, string(content), test.expected)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func Test_nextSerial(t *testing.T) {
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, uint32(43), nextSerial(42, now))
+	assert.Equal(t, uint32(2024031500), nextSerial(2024010101, now))
+	assert.Equal(t, uint32(2024031508), nextSerial(2024031507, now))
+	assert.Equal(t, uint32(0), nextSerial(4294967295, now))
+}
@@ -5,20 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"lego-toolbox/yamlconfig"
 	"net/http"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"lego-toolbox/providers/dns/internal/rimuhosting"
+	"lego-toolbox/rawrecord"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "ZONOMI_"
 
-	EnvAPIKey = envNamespace + "API_KEY"
+	EnvAPIKey  = envNamespace + "API_KEY"
+	EnvBaseURL = envNamespace + "BASE_URL"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -29,6 +31,7 @@ const (
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	APIKey             string        `yaml:"apiKey"`
+	BaseURL            string        `yaml:"baseURL"`
 	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
 	PollingInterval    time.Duration `yaml:"pollingInterval"`
 	TTL                int           `yaml:"ttl"`
@@ -38,6 +41,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		BaseURL:            env.GetOrDefaultString(EnvBaseURL, rimuhosting.DefaultZonomiBaseURL),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 3600),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -50,6 +54,7 @@ func NewDefaultConfig() *Config {
 // DefaultConfig returns a default configuration for the DNSProvider.
 func DefaultConfig() *Config {
 	return &Config{
+		BaseURL:            rimuhosting.DefaultZonomiBaseURL,
 		TTL:                3600,
 		PropagationTimeout: dns01.DefaultPropagationTimeout,
 		PollingInterval:    dns01.DefaultPollingInterval,
@@ -62,6 +67,7 @@ func DefaultConfig() *Config {
 func GetYamlTemple() string {
 	return `# config.yaml
 apiKey: "your_api_key"                      # API 密钥
+baseURL: "https://zonomi.com/app/dns/dyndns.jsp" # API 地址（兼容 Zonomi API 的服务商）
 propagationTimeout: 60s                     # 传播超时时间，单位为秒
 pollingInterval: 2s                         # 轮询间隔时间，单位为秒
 ttl: 3600                                   # TTL 值`
@@ -90,7 +96,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 // ParseConfig parse bytes to config
 func ParseConfig(rawConfig []byte) (*Config, error) {
 	config := DefaultConfig()
-	err := yaml.Unmarshal(rawConfig, &config)
+	err := yamlconfig.Unmarshal(rawConfig, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +114,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	client := rimuhosting.NewClient(config.APIKey)
-	client.BaseURL = rimuhosting.DefaultZonomiBaseURL
+
+	if config.BaseURL != "" {
+		client.BaseURL = config.BaseURL
+	}
 
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
@@ -124,25 +133,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 }
 
 // Present creates a TXT record using the specified parameters.
+// The other values of the record (e.g. the challenges of a wildcard and its domain) are kept.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	ctx := context.Background()
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	records, err := d.client.FindTXTRecords(ctx, dns01.UnFqdn(info.EffectiveFQDN))
-	if err != nil {
-		return fmt.Errorf("zonomi: failed to find record(s) for %s: %w", domain, err)
-	}
-
-	actions := []rimuhosting.ActionParameter{
-		rimuhosting.NewAddRecordAction(dns01.UnFqdn(info.EffectiveFQDN), info.Value, d.config.TTL),
-	}
-
-	for _, record := range records {
-		actions = append(actions, rimuhosting.NewAddRecordAction(record.Name, record.Content, d.config.TTL))
-	}
-
-	_, err = d.client.DoActions(ctx, actions...)
+	err := d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("zonomi: failed to add record(s) for %s: %w", domain, err)
 	}
@@ -152,11 +147,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	action := rimuhosting.NewDeleteRecordAction(dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
 
-	_, err := d.client.DoActions(context.Background(), action)
+	err := d.client.DeleteTXTRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
 	if err != nil {
 		return fmt.Errorf("zonomi: failed to delete record for %s: %w", domain, err)
 	}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,6 +92,27 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`apiKey: A`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://zonomi.com/app/dns/dyndns.jsp", config.BaseURL)
+	assert.Equal(t, 3600, config.TTL)
+
+	config, err = ParseConfig([]byte(`
+apiKey: A
+baseURL: https://dns.example.com/dyndns.jsp
+ttl: 300
+`))
+	require.NoError(t, err)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://dns.example.com/dyndns.jsp", p.client.BaseURL)
+	assert.Equal(t, 300, p.config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
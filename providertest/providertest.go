@@ -0,0 +1,345 @@
+// Package providertest is a conformance suite for DNS-01 challenge providers.
+// It checks the behaviors orchestrators rely on and that vary the most across providers.
+package providertest
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"golang.org/x/net/idna"
+)
+
+// Conformance cases.
+const (
+	CaseMultipleValues        = "MultipleValues"
+	CaseCleanUpIdempotent     = "CleanUpIdempotent"
+	CaseCleanUpWithoutPresent = "CleanUpWithoutPresent"
+	CaseLongName              = "LongName"
+	CaseIDN                   = "IDN"
+	CaseConcurrentPresent     = "ConcurrentPresent"
+)
+
+const defaultConcurrency = 5
+
+// EnvNameservers is the environment variable listing the nameservers (host[:port], comma separated)
+// verifying the records of the live conformance tests.
+const EnvNameservers = "LEGO_TOOLBOX_CONFORMANCE_NAMESERVERS"
+
+// Factory creates the provider under test.
+// It is called once per case, the same instance is used for every call of a case.
+type Factory func(t *testing.T) challenge.Provider
+
+// Options configures the suite.
+type Options struct {
+	// Domain is the domain under which the challenge records are created.
+	Domain string
+	// Nameservers (host:port) are queried to verify that the records are published and removed,
+	// see NameserversFromEnv.
+	Nameservers []string
+	// SkipLookups runs the suite without Nameservers, for the providers publishing no records (e.g. fake):
+	// only the results of the calls are checked.
+	SkipLookups bool
+	// Skip lists the cases to skip, for documented provider limitations.
+	Skip []string
+	// Concurrency is the number of concurrent Present calls, defaults to 5.
+	Concurrency int
+}
+
+// Run runs the conformance suite against the provider created by the factory.
+func Run(t *testing.T, factory Factory, opts Options) {
+	t.Helper()
+
+	if opts.Domain == "" {
+		t.Fatal("providertest: domain is required")
+	}
+
+	if len(opts.Nameservers) == 0 && !opts.SkipLookups {
+		t.Fatalf("providertest: nameservers are required to verify the records, set %s", EnvNameservers)
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, s *suite)
+	}{
+		{name: CaseMultipleValues, run: testMultipleValues},
+		{name: CaseCleanUpIdempotent, run: testCleanUpIdempotent},
+		{name: CaseCleanUpWithoutPresent, run: testCleanUpWithoutPresent},
+		{name: CaseLongName, run: testLongName},
+		{name: CaseIDN, run: testIDN},
+		{name: CaseConcurrentPresent, run: testConcurrentPresent},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if slices.Contains(opts.Skip, c.name) {
+				t.Skip("skipped by provider options")
+			}
+
+			c.run(t, &suite{provider: factory(t), opts: opts})
+		})
+	}
+}
+
+type suite struct {
+	provider challenge.Provider
+	opts     Options
+}
+
+func testMultipleValues(t *testing.T, s *suite) {
+	domain := s.opts.Domain
+
+	s.present(t, domain, "multiple-a")
+	s.present(t, domain, "multiple-b")
+
+	s.expectValues(t, domain, "multiple-a", "multiple-b")
+
+	s.cleanUp(t, domain, "multiple-a")
+	s.expectValues(t, domain, "multiple-b")
+	s.expectNoValues(t, domain, "multiple-a")
+
+	s.cleanUp(t, domain, "multiple-b")
+	s.expectNoValues(t, domain, "multiple-b")
+}
+
+func testCleanUpIdempotent(t *testing.T, s *suite) {
+	domain := s.opts.Domain
+
+	s.present(t, domain, "idempotent")
+	s.expectValues(t, domain, "idempotent")
+
+	s.cleanUp(t, domain, "idempotent")
+	s.cleanUp(t, domain, "idempotent")
+
+	s.expectNoValues(t, domain, "idempotent")
+}
+
+func testCleanUpWithoutPresent(t *testing.T, s *suite) {
+	s.cleanUp(t, s.opts.Domain, "never-presented")
+}
+
+func testLongName(t *testing.T, s *suite) {
+	// 63 bytes is the maximum length of a label.
+	domain := strings.Repeat("l", 63) + "." + s.opts.Domain
+
+	s.present(t, domain, "long")
+	s.expectValues(t, domain, "long")
+
+	s.cleanUp(t, domain, "long")
+	s.expectNoValues(t, domain, "long")
+}
+
+func testIDN(t *testing.T, s *suite) {
+	domain := "tëst-ïdn." + s.opts.Domain
+
+	s.present(t, domain, "idn")
+	s.expectValues(t, domain, "idn")
+
+	s.cleanUp(t, domain, "idn")
+	s.expectNoValues(t, domain, "idn")
+}
+
+func testConcurrentPresent(t *testing.T, s *suite) {
+	domains := make([]string, s.opts.Concurrency)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("concurrent-%d.%s", i, s.opts.Domain)
+	}
+
+	errs := make([]error, len(domains))
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.provider.Present(domain, token("concurrent"), "concurrent")
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("present %s: %v", domains[i], err)
+		}
+	}
+
+	for _, domain := range domains {
+		s.expectValues(t, domain, "concurrent")
+		s.cleanUp(t, domain, "concurrent")
+	}
+}
+
+// token returns the challenge token of the key authorization:
+// each value has its own token, like the challenges of `example.com` and `*.example.com` in one order,
+// so the providers keying their state by token and FQDN keep the values apart.
+func token(keyAuth string) string {
+	return "token-" + keyAuth
+}
+
+func (s *suite) present(t *testing.T, domain, keyAuth string) {
+	t.Helper()
+
+	err := s.provider.Present(domain, token(keyAuth), keyAuth)
+	if err != nil {
+		t.Fatalf("present %s: %v", domain, err)
+	}
+
+	t.Cleanup(func() { _ = s.provider.CleanUp(domain, token(keyAuth), keyAuth) })
+}
+
+func (s *suite) cleanUp(t *testing.T, domain, keyAuth string) {
+	t.Helper()
+
+	err := s.provider.CleanUp(domain, token(keyAuth), keyAuth)
+	if err != nil {
+		t.Fatalf("cleanup %s: %v", domain, err)
+	}
+}
+
+// expectValues waits until the values of the key authorizations are published.
+func (s *suite) expectValues(t *testing.T, domain string, keyAuths ...string) {
+	t.Helper()
+
+	s.wait(t, domain, keyAuths, true)
+}
+
+// expectNoValues waits until the values of the key authorizations are removed.
+func (s *suite) expectNoValues(t *testing.T, domain string, keyAuths ...string) {
+	t.Helper()
+
+	s.wait(t, domain, keyAuths, false)
+}
+
+func (s *suite) wait(t *testing.T, domain string, keyAuths []string, published bool) {
+	t.Helper()
+
+	if s.opts.SkipLookups {
+		return
+	}
+
+	fqdn := challengeFQDN(domain)
+
+	var expected []string
+	for _, keyAuth := range keyAuths {
+		expected = append(expected, dns01.GetChallengeInfo(domain, keyAuth).Value)
+	}
+
+	timeout, interval := s.timeout()
+
+	var values []string
+	var err error
+
+	deadline := time.Now().Add(timeout)
+	for {
+		values, err = LookupTXT(fqdn, s.opts.Nameservers)
+		if err == nil && matches(values, expected, published) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			break
+		}
+
+		time.Sleep(interval)
+	}
+
+	if err != nil {
+		t.Fatalf("lookup %s: %v", fqdn, err)
+	}
+
+	if published {
+		t.Fatalf("%s: expected values %v to be published, got %v", fqdn, expected, values)
+	}
+
+	t.Fatalf("%s: expected values %v to be removed, got %v", fqdn, expected, values)
+}
+
+func (s *suite) timeout() (timeout, interval time.Duration) {
+	if p, ok := s.provider.(challenge.ProviderTimeout); ok {
+		return p.Timeout()
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+func matches(values, expected []string, published bool) bool {
+	for _, v := range expected {
+		if slices.Contains(values, v) != published {
+			return false
+		}
+	}
+
+	return true
+}
+
+// challengeFQDN returns the ASCII challenge FQDN of a possibly internationalized domain.
+func challengeFQDN(domain string) string {
+	fqdn := dns01.GetChallengeInfo(domain, "").EffectiveFQDN
+
+	ascii, err := idna.ToASCII(dns01.UnFqdn(fqdn))
+	if err != nil {
+		return fqdn
+	}
+
+	return dns01.ToFqdn(ascii)
+}
+
+// NameserversFromEnv returns the nameservers of EnvNameservers, the port defaults to 53.
+func NameserversFromEnv() []string {
+	var nameservers []string
+
+	for _, ns := range strings.Split(os.Getenv(EnvNameservers), ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			ns = net.JoinHostPort(strings.Trim(ns, "[]"), "53")
+		}
+
+		nameservers = append(nameservers, ns)
+	}
+
+	return nameservers
+}
+
+// LookupTXT queries the nameservers in order and returns the TXT values of the first answer.
+func LookupTXT(fqdn string, nameservers []string) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(fqdn), dns.TypeTXT)
+
+	client := &dns.Client{Timeout: 5 * time.Second}
+
+	var lastErr error
+	for _, ns := range nameservers {
+		in, _, err := client.Exchange(m, ns)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var values []string
+		for _, rr := range in.Answer {
+			if txt, ok := rr.(*dns.TXT); ok {
+				values = append(values, strings.Join(txt.Txt, ""))
+			}
+		}
+
+		return values, nil
+	}
+
+	return nil, lastErr
+}
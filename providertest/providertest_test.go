@@ -0,0 +1,95 @@
+package providertest
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/providers/dns/memdns"
+)
+
+func TestRun_fake(t *testing.T) {
+	Run(t, func(t *testing.T) challenge.Provider {
+		provider, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return provider
+	}, Options{Domain: "example.com", SkipLookups: true})
+}
+
+func TestRun_memdns(t *testing.T) {
+	// The challenge FQDNs only exist on the embedded server.
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	var nameservers []string
+
+	provider, err := memdns.NewDNSProviderConfig(memdns.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = provider.Close() })
+
+	nameservers = append(nameservers, provider.Addr())
+
+	Run(t, func(t *testing.T) challenge.Provider {
+		return provider
+	}, Options{Domain: "example.com", Nameservers: nameservers})
+}
+
+func TestNameserversFromEnv(t *testing.T) {
+	t.Setenv(EnvNameservers, "ns1.example.com, 192.0.2.1:5353,,[2001:db8::1]")
+
+	expected := []string{"ns1.example.com:53", "192.0.2.1:5353", "[2001:db8::1]:53"}
+
+	if got := NameserversFromEnv(); !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// recordIDProvider keeps the value presented for each token and FQDN, like the providers keeping record IDs,
+// and removes the kept value on cleanup.
+type recordIDProvider struct {
+	*memdns.DNSProvider
+
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func (p *recordIDProvider) Present(domain, token, keyAuth string) error {
+	p.mu.Lock()
+	p.records[token+"|"+domain] = keyAuth
+	p.mu.Unlock()
+
+	return p.DNSProvider.Present(domain, token, keyAuth)
+}
+
+func (p *recordIDProvider) CleanUp(domain, token, _ string) error {
+	p.mu.Lock()
+	keyAuth, ok := p.records[token+"|"+domain]
+	delete(p.records, token+"|"+domain)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return p.DNSProvider.CleanUp(domain, token, keyAuth)
+}
+
+func TestRun_recordIDs(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := memdns.NewDNSProviderConfig(memdns.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = provider.Close() })
+
+	Run(t, func(t *testing.T) challenge.Provider {
+		return &recordIDProvider{DNSProvider: provider, records: map[string]string{}}
+	}, Options{Domain: "example.com", Nameservers: []string{provider.Addr()}})
+}
@@ -0,0 +1,102 @@
+package legotoolbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/quota"
+)
+
+var (
+	quotaTrackerMu sync.RWMutex
+	quotaTracker   *quota.Tracker
+)
+
+// SetQuotaTracker sets the tracker counting the API calls of the providers created by NewDNSChallengeProviderByName.
+// A nil tracker disables the quotas.
+func SetQuotaTracker(tracker *quota.Tracker) {
+	quotaTrackerMu.Lock()
+	defer quotaTrackerMu.Unlock()
+
+	quotaTracker = tracker
+}
+
+// QuotaTracker returns the tracker set with SetQuotaTracker.
+func QuotaTracker() *quota.Tracker {
+	quotaTrackerMu.RLock()
+	defer quotaTrackerMu.RUnlock()
+
+	return quotaTracker
+}
+
+// WithQuota counts the Present and CleanUp calls of the provider as API calls of name.
+// A call exceeding the quota fails with a *quota.QuotaExceededError, or waits if the limit delays the calls.
+// The timing and sequential behavior of the provider are preserved.
+func WithQuota(provider challenge.Provider, name string, tracker *quota.Tracker) challenge.Provider {
+	return decorate(&quotaProvider{Provider: provider, name: name, tracker: tracker})
+}
+
+type quotaProvider struct {
+	challenge.Provider
+
+	name    string
+	tracker *quota.Tracker
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *quotaProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *quotaProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *quotaProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *quotaProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *quotaProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *quotaProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
+
+func (p *quotaProvider) acquire(ctx context.Context) error {
+	// The delayed calls cannot wait longer than the propagation timeout of the provider.
+	timeout, _ := providerTimeout(p.Provider)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := p.tracker.Acquire(ctx, p.name, 1)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	return nil
+}
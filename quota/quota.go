@@ -0,0 +1,249 @@
+// Package quota counts the API calls of the DNS providers per time window,
+// and refuses or delays the calls exceeding the quota of the provider.
+// The counters are persisted to disk, so they are shared by successive runs.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultRetention is how long the calls of a provider without limit, nor window, are kept.
+const defaultRetention = time.Hour
+
+// Limit is the quota of a provider.
+type Limit struct {
+	// Requests is the number of API calls allowed per Window.
+	// Without requests, the calls are only counted (see Usage) over Window.
+	Requests int           `yaml:"requests" json:"requests"`
+	Window   time.Duration `yaml:"window" json:"window"`
+	// Delay waits for the quota to be available instead of refusing the calls.
+	Delay bool `yaml:"delay" json:"delay"`
+}
+
+func (l Limit) enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// DefaultLimits are the documented quotas of some provider APIs.
+var DefaultLimits = map[string]Limit{
+	"namecheap": {Requests: 50, Window: time.Minute},
+	"godaddy":   {Requests: 60, Window: time.Minute},
+}
+
+// QuotaExceededError is returned when a call would exceed the quota of the provider.
+type QuotaExceededError struct {
+	Provider string
+	Limit    Limit
+	// RetryAfter is the time before enough calls leave the window.
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota: %s: %d requests per %s exceeded, retry after %s",
+		e.Provider, e.Limit.Requests, e.Limit.Window, e.RetryAfter.Round(time.Second))
+}
+
+// state is the content of the counters file.
+type state struct {
+	Calls map[string][]time.Time `json:"calls"`
+}
+
+// Tracker counts the API calls of the providers.
+type Tracker struct {
+	path   string
+	limits map[string]Limit
+
+	mu    sync.Mutex
+	calls map[string][]time.Time
+
+	now func() time.Time
+}
+
+// NewTracker returns a Tracker enforcing the limits, keyed by provider.
+// The counters are persisted to path (0600), they are kept in memory only when path is empty.
+func NewTracker(path string, limits map[string]Limit) (*Tracker, error) {
+	t := &Tracker{
+		path:   path,
+		limits: limits,
+		calls:  make(map[string][]time.Time),
+		now:    time.Now,
+	}
+
+	err := t.load()
+	if err != nil {
+		return nil, fmt.Errorf("quota: %w", err)
+	}
+
+	return t, nil
+}
+
+// Limit returns the limit of the provider.
+func (t *Tracker) Limit(provider string) (Limit, bool) {
+	limit, ok := t.limits[provider]
+	return limit, ok && limit.enabled()
+}
+
+// Usage returns the number of calls of the provider in the current window.
+func (t *Tracker) Usage(provider string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.load()
+	if err != nil {
+		return 0, fmt.Errorf("quota: %w", err)
+	}
+
+	return len(t.prune(provider)), nil
+}
+
+// Reserve records n calls of the provider, or returns a *QuotaExceededError if they would exceed its quota.
+func (t *Tracker) Reserve(provider string, n int) error {
+	return t.update(provider, n, true)
+}
+
+// Record records n calls of the provider, regardless of its quota.
+func (t *Tracker) Record(provider string, n int) error {
+	return t.update(provider, n, false)
+}
+
+// Acquire reserves n calls of the provider, following the policy of its limit:
+// if the limit delays the calls, Acquire waits for the quota until ctx is done,
+// otherwise it returns the *QuotaExceededError.
+func (t *Tracker) Acquire(ctx context.Context, provider string, n int) error {
+	for {
+		err := t.Reserve(provider, n)
+
+		var exceeded *QuotaExceededError
+		if !errors.As(err, &exceeded) || !exceeded.Limit.Delay {
+			return err
+		}
+
+		timer := time.NewTimer(exceeded.RetryAfter)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(exceeded, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *Tracker) update(provider string, n int, check bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.load()
+	if err != nil {
+		return fmt.Errorf("quota: %w", err)
+	}
+
+	calls := t.prune(provider)
+	now := t.now()
+
+	if limit, ok := t.Limit(provider); ok && check && len(calls)+n > limit.Requests {
+		return &QuotaExceededError{Provider: provider, Limit: limit, RetryAfter: retryAfter(calls, n, limit, now)}
+	}
+
+	for range n {
+		calls = append(calls, now)
+	}
+
+	t.calls[provider] = calls
+
+	err = t.save()
+	if err != nil {
+		return fmt.Errorf("quota: %w", err)
+	}
+
+	return nil
+}
+
+// prune drops the calls of the provider out of its window.
+func (t *Tracker) prune(provider string) []time.Time {
+	retention := defaultRetention
+	if limit := t.limits[provider]; limit.Window > 0 {
+		retention = limit.Window
+	}
+
+	since := t.now().Add(-retention)
+
+	calls := slices.DeleteFunc(t.calls[provider], func(at time.Time) bool { return !at.After(since) })
+	if len(calls) == 0 {
+		delete(t.calls, provider)
+		return nil
+	}
+
+	t.calls[provider] = calls
+
+	return calls
+}
+
+// retryAfter returns the time before n calls fit in the window.
+func retryAfter(calls []time.Time, n int, limit Limit, now time.Time) time.Duration {
+	if n > limit.Requests {
+		// Never fits, retrying after a whole window is the best hint.
+		return limit.Window
+	}
+
+	// The calls are sorted, the oldest ones leave the window first.
+	oldest := calls[len(calls)+n-limit.Requests-1]
+
+	return oldest.Add(limit.Window).Sub(now)
+}
+
+// load reads the counters file before each update, so the calls of the other processes are counted.
+func (t *Tracker) load() error {
+	if t.path == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s state
+
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", t.path, err)
+	}
+
+	t.calls = s.Calls
+	if t.calls == nil {
+		t.calls = make(map[string][]time.Time)
+	}
+
+	return nil
+}
+
+func (t *Tracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(state{Calls: t.calls}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(t.path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(t.path, raw, 0o600)
+}
@@ -0,0 +1,117 @@
+package quota
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tracker, err := NewTracker("", map[string]Limit{"namecheap": {Requests: 3, Window: time.Minute}})
+	require.NoError(t, err)
+
+	tracker.now = func() time.Time { return now }
+
+	require.NoError(t, tracker.Reserve("namecheap", 2))
+
+	now = now.Add(20 * time.Second)
+	require.NoError(t, tracker.Reserve("namecheap", 1))
+
+	err = tracker.Reserve("namecheap", 2)
+	require.EqualError(t, err, "quota: namecheap: 3 requests per 1m0s exceeded, retry after 40s")
+
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, 40*time.Second, exceeded.RetryAfter)
+
+	// The first calls leave the window.
+	now = now.Add(40 * time.Second)
+	require.NoError(t, tracker.Reserve("namecheap", 2))
+
+	usage, err := tracker.Usage("namecheap")
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage)
+
+	// The providers without limit are only counted.
+	require.NoError(t, tracker.Reserve("route53", 100))
+	require.NoError(t, tracker.Record("namecheap", 10))
+}
+
+func TestTracker_Usage_window(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a window without requests: the calls are only counted, over the window.
+	tracker, err := NewTracker("", map[string]Limit{"wedos": {Window: 24 * time.Hour}})
+	require.NoError(t, err)
+
+	tracker.now = func() time.Time { return now }
+
+	require.NoError(t, tracker.Reserve("wedos", 100))
+
+	now = now.Add(2 * time.Hour)
+
+	usage, err := tracker.Usage("wedos")
+	require.NoError(t, err)
+	assert.Equal(t, 100, usage)
+
+	now = now.Add(22 * time.Hour)
+
+	usage, err = tracker.Usage("wedos")
+	require.NoError(t, err)
+	assert.Equal(t, 0, usage)
+}
+
+func TestTracker_persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota", "counters.json")
+	limits := map[string]Limit{"godaddy": {Requests: 2, Window: time.Minute}}
+
+	tracker, err := NewTracker(path, limits)
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Reserve("godaddy", 2))
+
+	other, err := NewTracker(path, limits)
+	require.NoError(t, err)
+
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, other.Reserve("godaddy", 1), &exceeded)
+
+	// The calls of the other tracker are seen on the next update.
+	require.NoError(t, other.Record("godaddy", 1))
+
+	usage, err := tracker.Usage("godaddy")
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage)
+}
+
+func TestTracker_Acquire(t *testing.T) {
+	tracker, err := NewTracker("", map[string]Limit{
+		"refused": {Requests: 1, Window: time.Hour},
+		"delayed": {Requests: 1, Window: 50 * time.Millisecond, Delay: true},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Acquire(context.Background(), "refused", 1))
+
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, tracker.Acquire(context.Background(), "refused", 1), &exceeded)
+
+	require.NoError(t, tracker.Acquire(context.Background(), "delayed", 1))
+
+	start := time.Now()
+	require.NoError(t, tracker.Acquire(context.Background(), "delayed", 1))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = tracker.Acquire(ctx, "delayed", 1)
+	require.ErrorAs(t, err, &exceeded)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
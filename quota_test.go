@@ -0,0 +1,56 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/quota"
+)
+
+func TestWithQuota(t *testing.T) {
+	inner, err := NewDNSChallengeProviderByName("fake", []byte("sequenceInterval: 5s\n"))
+	require.NoError(t, err)
+
+	tracker, err := quota.NewTracker("", map[string]quota.Limit{"fake": {Requests: 2, Window: time.Hour}})
+	require.NoError(t, err)
+
+	provider := WithQuota(inner, "fake", tracker)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	err = provider.Present("example.org", "token", "keyAuth")
+
+	var exceeded *quota.QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "fake", exceeded.Provider)
+
+	inner.(*fake.DNSProvider).AssertCallCount(t, fake.CallPresent, 1)
+
+	s, ok := provider.(sequential)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, s.Sequential())
+
+	assert.Same(t, inner, Unwrap(provider))
+}
+
+func TestNewDNSChallengeProviderByName_quotaTracker(t *testing.T) {
+	tracker, err := quota.NewTracker("", map[string]quota.Limit{"fake": {Requests: 1, Window: time.Hour}})
+	require.NoError(t, err)
+
+	SetQuotaTracker(tracker)
+	t.Cleanup(func() { SetQuotaTracker(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("fake@quota", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.Error(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	usage, err := tracker.Usage("fake")
+	require.NoError(t, err)
+	assert.Equal(t, 1, usage)
+}
@@ -0,0 +1,398 @@
+// Package ratelimit tracks the rate limits of Let's Encrypt locally (certificates per registered domain,
+// duplicate certificates, failed validations), and warns, delays or refuses the orders which would exceed them.
+// The issuances and failures are persisted to disk, so they are shared by successive runs.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"golang.org/x/net/publicsuffix"
+)
+
+// renewalRetention is how long the issued certificates are remembered to detect the renewals.
+const renewalRetention = 90 * 24 * time.Hour
+
+// Kind is the kind of a rate limit.
+type Kind string
+
+// Kinds of rate limits.
+const (
+	CertificatesPerRegisteredDomain Kind = "certificatesPerRegisteredDomain"
+	DuplicateCertificate            Kind = "duplicateCertificate"
+	FailedValidations               Kind = "failedValidations"
+)
+
+// Mode is the behavior when an order would exceed a limit.
+type Mode string
+
+// Modes.
+const (
+	// Refuse returns the *ExceededError.
+	Refuse Mode = "refuse"
+	// Delay waits for the limit to be available.
+	Delay Mode = "delay"
+	// Warn logs the *ExceededError and lets the order go.
+	Warn Mode = "warn"
+)
+
+// Limit is a number of events allowed per window.
+type Limit struct {
+	Count  int           `yaml:"count" json:"count"`
+	Window time.Duration `yaml:"window" json:"window"`
+}
+
+func (l Limit) enabled() bool {
+	return l.Count > 0 && l.Window > 0
+}
+
+// Limits are the tracked rate limits, a zero limit is not enforced.
+type Limits struct {
+	// CertificatesPerRegisteredDomain limits the new certificates per registered domain (`example.com`, `example.co.uk`).
+	// The renewals (the same set of names as an issued certificate) are exempt.
+	CertificatesPerRegisteredDomain Limit `yaml:"certificatesPerRegisteredDomain" json:"certificatesPerRegisteredDomain"`
+	// DuplicateCertificate limits the certificates for the exact same set of names.
+	DuplicateCertificate Limit `yaml:"duplicateCertificate" json:"duplicateCertificate"`
+	// FailedValidations limits the failed validations per hostname.
+	FailedValidations Limit `yaml:"failedValidations" json:"failedValidations"`
+}
+
+// DefaultLimits are the production limits of Let's Encrypt.
+var DefaultLimits = Limits{
+	CertificatesPerRegisteredDomain: Limit{Count: 50, Window: 7 * 24 * time.Hour},
+	DuplicateCertificate:            Limit{Count: 5, Window: 7 * 24 * time.Hour},
+	FailedValidations:               Limit{Count: 5, Window: time.Hour},
+}
+
+// ExceededError is returned when an order would exceed a limit.
+type ExceededError struct {
+	Kind Kind
+	// Key is the registered domain, the set of names, or the hostname.
+	Key   string
+	Limit Limit
+	// RetryAfter is the time before the order fits in the limit.
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("ratelimit: %s: %s: %d per %s exceeded, retry after %s",
+		e.Kind, e.Key, e.Limit.Count, e.Limit.Window, e.RetryAfter.Round(time.Second))
+}
+
+// state is the content of the state file.
+type state struct {
+	RegisteredDomains map[string][]time.Time `json:"registeredDomains,omitempty"`
+	Certificates      map[string][]time.Time `json:"certificates,omitempty"`
+	FailedValidations map[string][]time.Time `json:"failedValidations,omitempty"`
+}
+
+// Budget tracks the rate limits of an ACME account.
+type Budget struct {
+	path   string
+	limits Limits
+	mode   Mode
+
+	mu    sync.Mutex
+	state state
+
+	now func() time.Time
+}
+
+// NewBudget returns a Budget enforcing the limits with the mode (Refuse when empty).
+// The state is persisted to path (0600), it is kept in memory only when path is empty.
+func NewBudget(path string, limits Limits, mode Mode) (*Budget, error) {
+	switch mode {
+	case "":
+		mode = Refuse
+	case Refuse, Delay, Warn:
+	default:
+		return nil, fmt.Errorf("ratelimit: unsupported mode %q", mode)
+	}
+
+	b := &Budget{
+		path:   path,
+		limits: limits,
+		mode:   mode,
+		now:    time.Now,
+	}
+
+	err := b.load()
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: %w", err)
+	}
+
+	return b, nil
+}
+
+// Check returns the *ExceededError of the first limit an order of the domains would exceed.
+func (b *Budget) Check(domains []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.load()
+	if err != nil {
+		return fmt.Errorf("ratelimit: %w", err)
+	}
+
+	b.prune()
+
+	return b.check(domains)
+}
+
+// Acquire checks an order of the domains, following the mode of the budget:
+// Refuse returns the *ExceededError, Delay waits for the limits until ctx is done, Warn logs the *ExceededError.
+func (b *Budget) Acquire(ctx context.Context, domains []string) error {
+	for {
+		err := b.Check(domains)
+
+		var exceeded *ExceededError
+		if !errors.As(err, &exceeded) {
+			return err
+		}
+
+		switch b.mode {
+		case Warn:
+			log.Warnf("%v", exceeded)
+			return nil
+
+		case Delay:
+			timer := time.NewTimer(exceeded.RetryAfter)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Join(exceeded, ctx.Err())
+			case <-timer.C:
+			}
+
+		default:
+			return exceeded
+		}
+	}
+}
+
+// RecordIssued records the issuance of a certificate for the domains.
+func (b *Budget) RecordIssued(domains []string) error {
+	return b.update(func(now time.Time) {
+		key := certificateKey(domains)
+
+		if !b.renewal(key) {
+			for _, domain := range registeredDomains(domains) {
+				b.state.RegisteredDomains = appendTime(b.state.RegisteredDomains, domain, now)
+			}
+		}
+
+		b.state.Certificates = appendTime(b.state.Certificates, key, now)
+	})
+}
+
+// RecordFailedValidation records a failed validation of the hostname.
+func (b *Budget) RecordFailedValidation(hostname string) error {
+	return b.update(func(now time.Time) {
+		b.state.FailedValidations = appendTime(b.state.FailedValidations, normalize(hostname), now)
+	})
+}
+
+func (b *Budget) check(domains []string) error {
+	now := b.now()
+	key := certificateKey(domains)
+
+	if limit := b.limits.DuplicateCertificate; limit.enabled() {
+		issued := within(b.state.Certificates[key], now.Add(-limit.Window))
+		if len(issued) >= limit.Count {
+			return newExceededError(DuplicateCertificate, key, limit, issued, now)
+		}
+	}
+
+	if limit := b.limits.CertificatesPerRegisteredDomain; limit.enabled() && !b.renewal(key) {
+		for _, domain := range registeredDomains(domains) {
+			issued := within(b.state.RegisteredDomains[domain], now.Add(-limit.Window))
+			if len(issued) >= limit.Count {
+				return newExceededError(CertificatesPerRegisteredDomain, domain, limit, issued, now)
+			}
+		}
+	}
+
+	if limit := b.limits.FailedValidations; limit.enabled() {
+		for _, domain := range domains {
+			hostname := normalize(domain)
+
+			failed := within(b.state.FailedValidations[hostname], now.Add(-limit.Window))
+			if len(failed) >= limit.Count {
+				return newExceededError(FailedValidations, hostname, limit, failed, now)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renewal reports whether a certificate for the same set of names was issued.
+func (b *Budget) renewal(key string) bool {
+	return len(b.state.Certificates[key]) > 0
+}
+
+func (b *Budget) update(fn func(now time.Time)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.load()
+	if err != nil {
+		return fmt.Errorf("ratelimit: %w", err)
+	}
+
+	b.prune()
+
+	fn(b.now())
+
+	err = b.save()
+	if err != nil {
+		return fmt.Errorf("ratelimit: %w", err)
+	}
+
+	return nil
+}
+
+// prune drops the events out of every window.
+func (b *Budget) prune() {
+	now := b.now()
+
+	pruneTimes(b.state.RegisteredDomains, now.Add(-b.limits.CertificatesPerRegisteredDomain.Window))
+	pruneTimes(b.state.Certificates, now.Add(-max(b.limits.DuplicateCertificate.Window, renewalRetention)))
+	pruneTimes(b.state.FailedValidations, now.Add(-b.limits.FailedValidations.Window))
+}
+
+// load reads the state file before each update, so the orders of the other processes are counted.
+func (b *Budget) load() error {
+	if b.path == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s state
+
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", b.path, err)
+	}
+
+	b.state = s
+
+	return nil
+}
+
+func (b *Budget) save() error {
+	if b.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(b.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(b.path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(b.path, raw, 0o600)
+}
+
+// newExceededError returns the error of a limit reached by the events (sorted, in the window).
+func newExceededError(kind Kind, key string, limit Limit, events []time.Time, now time.Time) *ExceededError {
+	// The oldest events leave the window first.
+	oldest := events[len(events)-limit.Count]
+
+	return &ExceededError{Kind: kind, Key: key, Limit: limit, RetryAfter: oldest.Add(limit.Window).Sub(now)}
+}
+
+// certificateKey identifies a set of names, regardless of the order and the case.
+func certificateKey(domains []string) string {
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		names = append(names, normalize(domain))
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(slices.Compact(names), ",")
+}
+
+// registeredDomains returns the distinct registered domains of the names.
+// A name without registered domain (a public suffix, an IP address) is its own registered domain.
+func registeredDomains(domains []string) []string {
+	var registered []string
+
+	for _, domain := range domains {
+		name := strings.TrimPrefix(normalize(domain), "*.")
+
+		etldPlusOne, err := publicsuffix.EffectiveTLDPlusOne(name)
+		if err != nil {
+			etldPlusOne = name
+		}
+
+		if !slices.Contains(registered, etldPlusOne) {
+			registered = append(registered, etldPlusOne)
+		}
+	}
+
+	return registered
+}
+
+func normalize(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+func appendTime(m map[string][]time.Time, key string, at time.Time) map[string][]time.Time {
+	if m == nil {
+		m = make(map[string][]time.Time)
+	}
+
+	m[key] = append(m[key], at)
+
+	return m
+}
+
+// within returns the times after since, the times are sorted.
+func within(times []time.Time, since time.Time) []time.Time {
+	index, _ := slices.BinarySearchFunc(times, since, func(at, target time.Time) int {
+		if at.After(target) {
+			return 1
+		}
+
+		return -1
+	})
+
+	return times[index:]
+}
+
+func pruneTimes(m map[string][]time.Time, since time.Time) {
+	for key, times := range m {
+		times = within(times, since)
+		if len(times) == 0 {
+			delete(m, key)
+			continue
+		}
+
+		m[key] = times
+	}
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBudget(t *testing.T, path string, limits Limits, mode Mode, now *time.Time) *Budget {
+	t.Helper()
+
+	budget, err := NewBudget(path, limits, mode)
+	require.NoError(t, err)
+
+	budget.now = func() time.Time { return *now }
+
+	return budget
+}
+
+func TestBudget_duplicateCertificate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	budget := newTestBudget(t, "", DefaultLimits, "", &now)
+
+	domains := []string{"www.example.com", "example.com"}
+
+	for range 5 {
+		require.NoError(t, budget.Check(domains))
+		require.NoError(t, budget.RecordIssued(domains))
+
+		now = now.Add(time.Hour)
+	}
+
+	// The order and the case of the names do not matter.
+	err := budget.Check([]string{"Example.com", "www.example.com."})
+	require.EqualError(t, err, "ratelimit: duplicateCertificate: example.com,www.example.com: 5 per 168h0m0s exceeded, retry after 163h0m0s")
+
+	var exceeded *ExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, DuplicateCertificate, exceeded.Kind)
+
+	// Another set of names is not a duplicate.
+	require.NoError(t, budget.Check([]string{"example.com"}))
+
+	now = now.Add(163 * time.Hour)
+	require.NoError(t, budget.Check(domains))
+}
+
+func TestBudget_certificatesPerRegisteredDomain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limits := Limits{CertificatesPerRegisteredDomain: Limit{Count: 2, Window: 24 * time.Hour}}
+
+	budget := newTestBudget(t, "", limits, Refuse, &now)
+
+	require.NoError(t, budget.RecordIssued([]string{"a.example.co.uk"}))
+	require.NoError(t, budget.RecordIssued([]string{"*.b.example.co.uk", "b.example.co.uk"}))
+
+	err := budget.Check([]string{"c.example.co.uk"})
+	require.EqualError(t, err, "ratelimit: certificatesPerRegisteredDomain: example.co.uk: 2 per 24h0m0s exceeded, retry after 24h0m0s")
+
+	// The renewals are exempt, and not counted.
+	require.NoError(t, budget.Check([]string{"a.example.co.uk"}))
+	require.NoError(t, budget.RecordIssued([]string{"a.example.co.uk"}))
+
+	// The other registered domains have their own limit.
+	require.NoError(t, budget.Check([]string{"example.org"}))
+}
+
+func TestBudget_failedValidations(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	budget := newTestBudget(t, "", DefaultLimits, Refuse, &now)
+
+	for range 5 {
+		require.NoError(t, budget.RecordFailedValidation("www.example.com"))
+		now = now.Add(time.Minute)
+	}
+
+	err := budget.Check([]string{"example.com", "www.example.com"})
+	require.EqualError(t, err, "ratelimit: failedValidations: www.example.com: 5 per 1h0m0s exceeded, retry after 55m0s")
+
+	now = now.Add(55 * time.Minute)
+	require.NoError(t, budget.Check([]string{"example.com", "www.example.com"}))
+}
+
+func TestBudget_Acquire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limits := Limits{DuplicateCertificate: Limit{Count: 1, Window: time.Hour}}
+	domains := []string{"example.com"}
+
+	warn := newTestBudget(t, "", limits, Warn, &now)
+	require.NoError(t, warn.RecordIssued(domains))
+	require.NoError(t, warn.Acquire(context.Background(), domains))
+
+	refuse := newTestBudget(t, "", limits, Refuse, &now)
+	require.NoError(t, refuse.RecordIssued(domains))
+
+	var exceeded *ExceededError
+	require.ErrorAs(t, refuse.Acquire(context.Background(), domains), &exceeded)
+
+	delay := newTestBudget(t, "", limits, Delay, &now)
+	require.NoError(t, delay.RecordIssued(domains))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := delay.Acquire(ctx, domains)
+	require.ErrorAs(t, err, &exceeded)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBudget_persisted(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	path := filepath.Join(t.TempDir(), "ratelimit", "state.json")
+	limits := Limits{DuplicateCertificate: Limit{Count: 1, Window: time.Hour}}
+
+	budget := newTestBudget(t, path, limits, Refuse, &now)
+	require.NoError(t, budget.RecordIssued([]string{"example.com"}))
+
+	other := newTestBudget(t, path, limits, Refuse, &now)
+
+	var exceeded *ExceededError
+	require.ErrorAs(t, other.Check([]string{"example.com"}), &exceeded)
+}
+
+func TestNewBudget_errors(t *testing.T) {
+	_, err := NewBudget("", DefaultLimits, "ignore")
+	require.EqualError(t, err, `ratelimit: unsupported mode "ignore"`)
+}
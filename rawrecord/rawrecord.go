@@ -0,0 +1,62 @@
+// Package rawrecord passes a precomputed TXT record through the Present and CleanUp methods of the providers.
+//
+// The providers compute the TXT record from the ACME key authorization.
+// Adapters that receive the record itself (cert-manager webhook, libdns) encode it
+// as a key authorization with KeyAuth, the providers decode it with GetChallengeInfo.
+// ACME key authorizations only contain base64url characters and dots, so they never collide with the encoding.
+package rawrecord
+
+import (
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+const (
+	prefix    = "\x00rawrecord\x00"
+	separator = "\x00"
+)
+
+// KeyAuth encodes the TXT record as a key authorization.
+// An empty fqdn keeps the record name computed from the domain (`_acme-challenge.<domain>.`, CNAME followed).
+func KeyAuth(fqdn, value string) string {
+	if fqdn != "" {
+		fqdn = dns01.ToFqdn(fqdn)
+	}
+
+	return prefix + fqdn + separator + value
+}
+
+// Parse decodes a key authorization created by KeyAuth.
+func Parse(keyAuth string) (fqdn, value string, ok bool) {
+	rest, found := strings.CutPrefix(keyAuth, prefix)
+	if !found {
+		return "", "", false
+	}
+
+	fqdn, value, ok = strings.Cut(rest, separator)
+
+	return fqdn, value, ok
+}
+
+// GetChallengeInfo returns information used to create a DNS record, like dns01.GetChallengeInfo,
+// with the record of the key authorizations created by KeyAuth.
+func GetChallengeInfo(domain, keyAuth string) dns01.ChallengeInfo {
+	fqdn, value, ok := Parse(keyAuth)
+	if !ok {
+		return dns01.GetChallengeInfo(domain, keyAuth)
+	}
+
+	if fqdn == "" {
+		info := dns01.GetChallengeInfo(domain, "")
+		info.Value = value
+
+		return info
+	}
+
+	return dns01.ChallengeInfo{
+		Value:         value,
+		FQDN:          fqdn,
+		EffectiveFQDN: fqdn,
+	}
+}
@@ -0,0 +1,58 @@
+package rawrecord
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChallengeInfo(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	testCases := []struct {
+		desc     string
+		keyAuth  string
+		expected dns01.ChallengeInfo
+	}{
+		{
+			desc:     "key authorization",
+			keyAuth:  "token.thumbprint",
+			expected: dns01.GetChallengeInfo("example.com", "token.thumbprint"),
+		},
+		{
+			desc:    "raw value",
+			keyAuth: KeyAuth("", "value"),
+			expected: dns01.ChallengeInfo{
+				Value:         "value",
+				FQDN:          "_acme-challenge.example.com.",
+				EffectiveFQDN: "_acme-challenge.example.com.",
+			},
+		},
+		{
+			desc:    "raw record",
+			keyAuth: KeyAuth("_acme-challenge.delegated.example.net", "value"),
+			expected: dns01.ChallengeInfo{
+				Value:         "value",
+				FQDN:          "_acme-challenge.delegated.example.net.",
+				EffectiveFQDN: "_acme-challenge.delegated.example.net.",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, GetChallengeInfo("example.com", test.keyAuth))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	fqdn, value, ok := Parse(KeyAuth("a.example.com", "v"))
+	assert.True(t, ok)
+	assert.Equal(t, "a.example.com.", fqdn)
+	assert.Equal(t, "v", value)
+
+	_, _, ok = Parse("token.thumbprint")
+	assert.False(t, ok)
+}
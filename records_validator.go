@@ -0,0 +1,59 @@
+package legotoolbox
+
+import (
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// RecordsValidator is implemented by the providers able to update only some records
+// (e.g. the per-record tokens of Hurricane Electric),
+// so that an order with a record out of reach fails before it is created.
+type RecordsValidator interface {
+	// ValidateRecords returns an error when one of the TXT records (FQDNs) can't be updated.
+	ValidateRecords(fqdns []string) error
+}
+
+// ValidateDomains checks that the provider can publish the challenges of the domains,
+// with the first provider implementing RecordsValidator (decorator or wrapped provider).
+// The records are the `_acme-challenge` records of the domains, following their CNAME,
+// or their alias when the provider is wrapped with WithDomainAliases.
+// The providers not implementing RecordsValidator accept every domain.
+func ValidateDomains(provider challenge.Provider, domains []string) error {
+	records := make(map[string]string, len(domains))
+
+	for {
+		if v, ok := provider.(RecordsValidator); ok {
+			fqdns := make([]string, 0, len(domains))
+			for _, domain := range domains {
+				fqdn, found := records[domain]
+				if !found {
+					// the challenge of a wildcard domain is published at its base domain.
+					fqdn = dns01.GetChallengeInfo(strings.TrimPrefix(domain, "*."), "").EffectiveFQDN
+				}
+
+				fqdns = append(fqdns, fqdn)
+			}
+
+			return v.ValidateRecords(fqdns)
+		}
+
+		if a, ok := provider.(*aliasProvider); ok {
+			for _, domain := range domains {
+				if alias, found := a.aliases[normalizeAliasDomain(domain)]; found {
+					if _, done := records[domain]; !done {
+						records[domain] = alias
+					}
+				}
+			}
+		}
+
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return nil
+		}
+
+		provider = u.Unwrap()
+	}
+}
@@ -0,0 +1,48 @@
+package legotoolbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validatingProvider records the TXT records to validate.
+type validatingProvider struct {
+	recordingProvider
+
+	records []string
+}
+
+func (p *validatingProvider) ValidateRecords(fqdns []string) error {
+	p.records = fqdns
+
+	return errors.New("invalid")
+}
+
+func TestValidateDomains(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	inner := &validatingProvider{}
+
+	provider := WithTimeouts(WithDomainAliases(inner, map[string]string{
+		"*.example.org": "_acme-challenge.validation.example.net.",
+	}), time.Minute, time.Second)
+
+	err := ValidateDomains(provider, []string{"example.com", "*.example.com", "*.example.org", "example.org"})
+	require.EqualError(t, err, "invalid")
+
+	assert.Equal(t, []string{
+		"_acme-challenge.example.com.",
+		"_acme-challenge.example.com.",
+		"_acme-challenge.validation.example.net.",
+		"_acme-challenge.validation.example.net.",
+	}, inner.records)
+}
+
+func TestValidateDomains_notValidator(t *testing.T) {
+	err := ValidateDomains(WithTimeouts(&recordingProvider{}, time.Minute, time.Second), []string{"example.com"})
+	require.NoError(t, err)
+}
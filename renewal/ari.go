@@ -0,0 +1,58 @@
+package renewal
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certificate"
+)
+
+// Window is a renewal window suggested by the CA, see draft-ietf-acme-ari.
+// A CA revoking certificates (e.g. after an incident) suggests a window in the past to get them renewed at once,
+// and spreads the renewals otherwise.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// RenewalInfoSource returns the renewal window suggested by the CA for a certificate (ACME Renewal Information).
+// ErrNoRenewalInfo means the CA does not support ARI.
+type RenewalInfoSource interface {
+	RenewalInfo(ctx context.Context, cert *x509.Certificate) (*Window, error)
+}
+
+// ErrNoRenewalInfo is returned by the RenewalInfoSource when the CA does not advertise a renewalInfo endpoint.
+var ErrNoRenewalInfo = api.ErrNoARI
+
+// NewLegoRenewalInfo returns the RenewalInfoSource querying the renewalInfo endpoint of the CA with the lego certifier
+// (lego.Client.Certificate).
+func NewLegoRenewalInfo(certifier *certificate.Certifier) RenewalInfoSource {
+	return &legoRenewalInfo{certifier: certifier}
+}
+
+type legoRenewalInfo struct {
+	certifier *certificate.Certifier
+}
+
+// RenewalInfo queries the renewalInfo endpoint of the CA.
+func (l *legoRenewalInfo) RenewalInfo(_ context.Context, cert *x509.Certificate) (*Window, error) {
+	info, err := l.certifier.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Window{Start: info.SuggestedWindow.Start, End: info.SuggestedWindow.End}, nil
+}
+
+// renewAt returns the time the certificate identified by id must be renewed in the window.
+// The time is derived from the ID, so it is stable across runs, unlike the random time of the draft.
+func (w Window) renewAt(id string) (time.Time, error) {
+	if !w.End.After(w.Start) {
+		return time.Time{}, errors.New("invalid suggested window")
+	}
+
+	return w.Start.Add(Policy{Jitter: w.End.Sub(w.Start)}.jitter(id)), nil
+}
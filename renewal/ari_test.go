@@ -0,0 +1,148 @@
+package renewal
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type renewalInfoFunc func(ctx context.Context, cert *x509.Certificate) (*Window, error)
+
+func (f renewalInfoFunc) RenewalInfo(ctx context.Context, cert *x509.Certificate) (*Window, error) {
+	return f(ctx, cert)
+}
+
+func TestScheduler_Due_renewalInfo(t *testing.T) {
+	store := memoryStore{
+		newCertificate(t, "revoked", now.Add(60*24*time.Hour)),
+		newCertificate(t, "spread", now.Add(10*24*time.Hour)),
+		newCertificate(t, "no-ari", now.Add(10*24*time.Hour)),
+		newCertificate(t, "failure", now.Add(10*24*time.Hour)),
+	}
+
+	source := renewalInfoFunc(func(_ context.Context, cert *x509.Certificate) (*Window, error) {
+		switch cert.Subject.CommonName {
+		case "revoked.example.com":
+			// the CA asks for an immediate renewal.
+			return &Window{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}, nil
+		case "spread.example.com":
+			// the CA spreads the renewals later than the policy.
+			return &Window{Start: now.Add(24 * time.Hour), End: now.Add(48 * time.Hour)}, nil
+		case "no-ari.example.com":
+			return nil, ErrNoRenewalInfo
+		default:
+			return nil, errors.New("unavailable")
+		}
+	})
+
+	scheduler, err := New(store, func(_ context.Context, _ Certificate, _ challenge.Provider) error { return nil }, Config{
+		RenewalInfo: source,
+		Now:         func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	due, err := scheduler.Due(context.Background())
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+
+	assert.Equal(t, "revoked", due[0].Certificate.ID)
+	assert.True(t, due[0].Suggested)
+	assert.False(t, due[0].RenewAt.Before(now.Add(-2*time.Hour)))
+	assert.True(t, due[0].RenewAt.Before(now.Add(-time.Hour)))
+
+	assert.Equal(t, "no-ari", due[1].Certificate.ID)
+	assert.False(t, due[1].Suggested)
+
+	assert.Equal(t, "failure", due[2].Certificate.ID)
+	assert.False(t, due[2].Suggested)
+}
+
+func TestWindow_renewAt(t *testing.T) {
+	window := Window{Start: now, End: now.Add(time.Hour)}
+
+	a, err := window.renewAt("a")
+	require.NoError(t, err)
+
+	b, err := window.renewAt("a")
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	assert.False(t, a.Before(window.Start))
+	assert.True(t, a.Before(window.End))
+
+	_, err = Window{Start: now, End: now}.renewAt("a")
+	require.EqualError(t, err, "invalid suggested window")
+}
+
+type testUser struct {
+	key crypto.PrivateKey
+}
+
+func (u testUser) GetEmail() string                        { return "test@example.com" }
+func (u testUser) GetRegistration() *registration.Resource { return nil }
+func (u testUser) GetPrivateKey() crypto.PrivateKey        { return u.key }
+
+func TestNewLegoRenewalInfo(t *testing.T) {
+	cert := newCertificate(t, "example", now.Add(30*24*time.Hour))
+
+	x509Cert, err := certcrypto.ParsePEMCertificate(cert.Certificate)
+	require.NoError(t, err)
+
+	certID, err := certificate.MakeARICertID(x509Cert)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /directory", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{
+  "newNonce": "` + server.URL + `/new-nonce",
+  "newAccount": "` + server.URL + `/new-account",
+  "newOrder": "` + server.URL + `/new-order",
+  "revokeCert": "` + server.URL + `/revoke-cert",
+  "keyChange": "` + server.URL + `/key-change",
+  "renewalInfo": "` + server.URL + `/renewal-info"
+}`))
+	})
+
+	mux.HandleFunc("GET /renewal-info/{certID}", func(rw http.ResponseWriter, req *http.Request) {
+		if req.PathValue("certID") != certID {
+			http.NotFound(rw, req)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"suggestedWindow":{"start":"2026-06-01T00:00:00Z","end":"2026-06-02T00:00:00Z"}}`))
+	})
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	config := lego.NewConfig(testUser{key: key})
+	config.CADirURL = server.URL + "/directory"
+	config.HTTPClient = server.Client()
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	window, err := NewLegoRenewalInfo(client.Certificate).RenewalInfo(context.Background(), x509Cert)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Window{Start: now, End: now.Add(24 * time.Hour)}, window)
+}
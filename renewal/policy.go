@@ -0,0 +1,50 @@
+package renewal
+
+import (
+	"crypto/x509"
+	"hash/fnv"
+	"time"
+)
+
+// DefaultBefore is the renewal window of the certificates when Policy.Before is zero.
+const DefaultBefore = 30 * 24 * time.Hour
+
+// Policy decides when the certificates are renewed.
+type Policy struct {
+	// Before renews the certificates expiring within Before, DefaultBefore when zero.
+	Before time.Duration `yaml:"before"`
+	// Fraction renews the certificates when their remaining lifetime is below the fraction of their lifetime
+	// (e.g. 0.33 for the short-lived certificates of step-ca), ignored when zero.
+	// The earliest of Before and Fraction applies.
+	Fraction float64 `yaml:"fraction"`
+	// Jitter advances the renewal of each certificate by up to Jitter, to spread the renewals.
+	// The jitter of a certificate is derived from its ID, so it is stable across runs.
+	Jitter time.Duration `yaml:"jitter"`
+}
+
+// RenewAt returns the time the certificate identified by id must be renewed.
+func (p Policy) RenewAt(id string, cert *x509.Certificate) time.Time {
+	before := p.Before
+	if before <= 0 {
+		before = DefaultBefore
+	}
+
+	if p.Fraction > 0 {
+		lifetime := cert.NotAfter.Sub(cert.NotBefore)
+		before = max(before, time.Duration(float64(lifetime)*p.Fraction))
+	}
+
+	return cert.NotAfter.Add(-before - p.jitter(id))
+}
+
+// jitter returns the jitter of the certificate, in [0, Jitter).
+func (p Policy) jitter(id string) time.Duration {
+	if p.Jitter <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+
+	return time.Duration(h.Sum64() % uint64(p.Jitter))
+}
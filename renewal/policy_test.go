@@ -0,0 +1,73 @@
+package renewal
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_RenewAt(t *testing.T) {
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		policy   Policy
+		lifetime time.Duration
+		expected time.Duration
+	}{
+		{
+			desc:     "default",
+			lifetime: 90 * 24 * time.Hour,
+			expected: 60 * 24 * time.Hour,
+		},
+		{
+			desc:     "before",
+			policy:   Policy{Before: 10 * 24 * time.Hour},
+			lifetime: 90 * 24 * time.Hour,
+			expected: 80 * 24 * time.Hour,
+		},
+		{
+			desc:     "fraction of a short-lived certificate",
+			policy:   Policy{Before: time.Hour, Fraction: 0.25},
+			lifetime: 24 * time.Hour,
+			expected: 18 * time.Hour,
+		},
+		{
+			desc:     "before earlier than fraction",
+			policy:   Policy{Fraction: 0.1},
+			lifetime: 90 * 24 * time.Hour,
+			expected: 60 * 24 * time.Hour,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(test.lifetime)}
+
+			assert.Equal(t, notBefore.Add(test.expected), test.policy.RenewAt("example", cert))
+		})
+	}
+}
+
+func TestPolicy_RenewAt_jitter(t *testing.T) {
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(90 * 24 * time.Hour)}
+
+	policy := Policy{Jitter: 24 * time.Hour}
+	base := Policy{}.RenewAt("a", cert)
+
+	a := policy.RenewAt("a", cert)
+	b := policy.RenewAt("b", cert)
+
+	// stable across runs.
+	assert.Equal(t, a, policy.RenewAt("a", cert))
+	// spread across certificates.
+	assert.NotEqual(t, a, b)
+
+	for _, renewAt := range []time.Time{a, b} {
+		assert.False(t, renewAt.After(base))
+		assert.True(t, renewAt.After(base.Add(-24*time.Hour)))
+	}
+}
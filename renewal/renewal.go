@@ -0,0 +1,239 @@
+// Package renewal renews the stored certificates before they expire, to build renewal daemons.
+//
+// The Scheduler lists the certificates of a Store, computes the due renewals with the window suggested by the CA (ARI)
+// or, when the CA does not suggest any, with a Policy, runs them with the batch planner (in parallel across the providers), and publishes the renewal events.
+package renewal
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	legotoolbox "lego-toolbox"
+	"lego-toolbox/batch"
+	"lego-toolbox/events"
+)
+
+// Certificate is a stored certificate.
+type Certificate struct {
+	// ID identifies the certificate in the store.
+	ID string
+	// Domains are the domains of the certificate.
+	Domains []string
+	// Provider is the identifier of the provider solving the challenges (`cloudflare@personal`),
+	// an empty identifier selects the default provider.
+	Provider string
+	// Certificate is the PEM encoded certificate, the leaf first.
+	Certificate []byte
+}
+
+// Store lists the stored certificates.
+type Store interface {
+	List(ctx context.Context) ([]Certificate, error)
+}
+
+// RenewFunc issues the certificate again with the provider, and stores it.
+type RenewFunc func(ctx context.Context, cert Certificate, provider challenge.Provider) error
+
+// Config is used to configure the Scheduler.
+type Config struct {
+	// Policy decides when the certificates are renewed, when the CA suggests no renewal window.
+	Policy Policy
+	// RenewalInfo returns the renewal windows suggested by the CA (ARI), ignored when nil.
+	// The Policy applies to the certificates without a suggested window (the CA does not support ARI, or the query failed).
+	RenewalInfo RenewalInfoSource
+	// Batch configures the execution of the renewals.
+	Batch batch.Config
+	// Bus receives the renewal events, legotoolbox.EventBus when nil.
+	Bus *events.Bus
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+}
+
+// Renewal is a due renewal.
+type Renewal struct {
+	Certificate Certificate
+	// RenewAt is the time the certificate had to be renewed, the zero time when the certificate cannot be parsed.
+	RenewAt time.Time
+	// NotAfter is the expiration of the certificate, the zero time when the certificate cannot be parsed.
+	NotAfter time.Time
+	// Suggested reports whether RenewAt is in the renewal window suggested by the CA.
+	Suggested bool
+}
+
+// Scheduler renews the certificates of a store.
+type Scheduler struct {
+	store  Store
+	renew  RenewFunc
+	config Config
+}
+
+// New returns a Scheduler renewing the certificates of the store with renew.
+func New(store Store, renew RenewFunc, config Config) (*Scheduler, error) {
+	if store == nil {
+		return nil, errors.New("renewal: the store is nil")
+	}
+
+	if renew == nil {
+		return nil, errors.New("renewal: the renew function is nil")
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Scheduler{store: store, renew: renew, config: config}, nil
+}
+
+// Due returns the certificates to renew now.
+// The window suggested by the CA takes precedence over the Policy.
+// A certificate which cannot be parsed is due: renewing it replaces it.
+func (s *Scheduler) Due(ctx context.Context) ([]Renewal, error) {
+	certs, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("renewal: list certificates: %w", err)
+	}
+
+	now := s.config.Now()
+
+	var due []Renewal
+
+	for _, cert := range certs {
+		x509Cert, err := certcrypto.ParsePEMCertificate(cert.Certificate)
+		if err != nil {
+			log.Warnf("renewal: certificate %s: %v", cert.ID, err)
+
+			due = append(due, Renewal{Certificate: cert})
+
+			continue
+		}
+
+		renewAt, suggested := s.renewAt(ctx, cert.ID, x509Cert)
+		if !now.Before(renewAt) {
+			due = append(due, Renewal{Certificate: cert, RenewAt: renewAt, NotAfter: x509Cert.NotAfter, Suggested: suggested})
+		}
+	}
+
+	return due, nil
+}
+
+// renewAt returns the renewal time of the certificate in the window suggested by the CA,
+// or from the Policy when the CA suggests no window.
+func (s *Scheduler) renewAt(ctx context.Context, id string, cert *x509.Certificate) (time.Time, bool) {
+	if s.config.RenewalInfo == nil {
+		return s.config.Policy.RenewAt(id, cert), false
+	}
+
+	window, err := s.config.RenewalInfo.RenewalInfo(ctx, cert)
+	if err == nil && window == nil {
+		err = ErrNoRenewalInfo
+	}
+
+	if err == nil {
+		var renewAt time.Time
+		renewAt, err = window.renewAt(id)
+		if err == nil {
+			return renewAt, true
+		}
+	}
+
+	if !errors.Is(err, ErrNoRenewalInfo) {
+		log.Warnf("renewal: certificate %s: renewal info: %v, falling back to the renewal policy", id, err)
+	}
+
+	return s.config.Policy.RenewAt(id, cert), false
+}
+
+// RunOnce renews the due certificates, and returns the results of the renewals.
+func (s *Scheduler) RunOnce(ctx context.Context) ([]batch.Result, error) {
+	due, err := s.Due(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	certs := make(map[string]Certificate, len(due))
+	jobs := make([]batch.Job, 0, len(due))
+
+	for _, renewal := range due {
+		cert := renewal.Certificate
+		certs[cert.ID] = cert
+		jobs = append(jobs, batch.Job{ID: cert.ID, Domains: cert.Domains, Provider: cert.Provider})
+
+		s.publish(events.Event{Type: events.RenewalDue, Provider: cert.Provider, Domain: firstDomain(cert)})
+	}
+
+	plan, err := batch.NewPlan(jobs, s.config.Batch)
+	if err != nil {
+		return nil, fmt.Errorf("renewal: %w", err)
+	}
+
+	results := plan.Run(ctx, func(ctx context.Context, job batch.Job, provider challenge.Provider) error {
+		return s.renew(ctx, certs[job.ID], provider)
+	})
+
+	for _, result := range results {
+		event := events.Event{
+			Type:     events.RenewalSuccess,
+			Provider: result.Job.Provider,
+			Domain:   firstDomain(certs[result.Job.ID]),
+			Duration: result.Duration,
+		}
+
+		if result.Err != nil {
+			event.Type = events.RenewalFailure
+			event.Error = result.Err.Error()
+		}
+
+		s.publish(event)
+	}
+
+	return results, nil
+}
+
+// Run renews the due certificates every interval, until the context is canceled.
+// The failures are published as events, and logged.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := s.RunOnce(ctx)
+		if err != nil {
+			log.Warnf("%v", err)
+		} else if err = batch.Err(results); err != nil {
+			log.Warnf("renewal: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) publish(event events.Event) {
+	bus := s.config.Bus
+	if bus == nil {
+		bus = legotoolbox.EventBus()
+	}
+
+	bus.Publish(event)
+}
+
+func firstDomain(cert Certificate) string {
+	if len(cert.Domains) == 0 {
+		return ""
+	}
+
+	return cert.Domains[0]
+}
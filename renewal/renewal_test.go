@@ -0,0 +1,157 @@
+package renewal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/batch"
+	"lego-toolbox/events"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+type fakeProvider struct{}
+
+func (fakeProvider) Present(_, _, _ string) error { return nil }
+func (fakeProvider) CleanUp(_, _, _ string) error { return nil }
+
+type memoryStore []Certificate
+
+func (m memoryStore) List(_ context.Context) ([]Certificate, error) {
+	return m, nil
+}
+
+func newCertificate(t *testing.T, id string, notAfter time.Time) Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: id + ".example.com"},
+		DNSNames:     []string{id + ".example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return Certificate{
+		ID:          id,
+		Domains:     []string{id + ".example.com"},
+		Provider:    "fake",
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func newScheduler(t *testing.T, store Store, renew RenewFunc, bus *events.Bus) *Scheduler {
+	t.Helper()
+
+	scheduler, err := New(store, renew, Config{
+		Batch: batch.Config{
+			Providers: func(_ string) (challenge.Provider, error) { return fakeProvider{}, nil },
+		},
+		Bus: bus,
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	return scheduler
+}
+
+func TestScheduler_Due(t *testing.T) {
+	store := memoryStore{
+		newCertificate(t, "expiring", now.Add(10*24*time.Hour)),
+		newCertificate(t, "fresh", now.Add(60*24*time.Hour)),
+		newCertificate(t, "expired", now.Add(-time.Hour)),
+		{ID: "broken", Domains: []string{"broken.example.com"}, Certificate: []byte("not a certificate")},
+	}
+
+	scheduler := newScheduler(t, store, func(_ context.Context, _ Certificate, _ challenge.Provider) error { return nil }, nil)
+
+	due, err := scheduler.Due(context.Background())
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+
+	assert.Equal(t, "expiring", due[0].Certificate.ID)
+	assert.Equal(t, now.Add(10*24*time.Hour), due[0].NotAfter)
+	assert.Equal(t, now.Add(-20*24*time.Hour), due[0].RenewAt)
+
+	assert.Equal(t, "expired", due[1].Certificate.ID)
+
+	assert.Equal(t, "broken", due[2].Certificate.ID)
+	assert.True(t, due[2].NotAfter.IsZero())
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	store := memoryStore{
+		newCertificate(t, "a", now.Add(24*time.Hour)),
+		newCertificate(t, "b", now.Add(24*time.Hour)),
+		newCertificate(t, "fresh", now.Add(60*24*time.Hour)),
+	}
+
+	var (
+		mu      sync.Mutex
+		renewed []string
+		types   []events.Type
+	)
+
+	bus := events.NewBus(events.SinkFunc(func(event events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		types = append(types, event.Type)
+	}))
+
+	scheduler := newScheduler(t, store, func(_ context.Context, cert Certificate, provider challenge.Provider) error {
+		assert.Equal(t, fakeProvider{}, provider)
+
+		mu.Lock()
+		renewed = append(renewed, cert.ID)
+		mu.Unlock()
+
+		if cert.ID == "b" {
+			return errors.New("boom")
+		}
+
+		return nil
+	}, bus)
+
+	results, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	sort.Strings(renewed)
+	assert.Equal(t, []string{"a", "b"}, renewed)
+
+	assert.Equal(t, []events.Type{
+		events.RenewalDue, events.RenewalDue,
+		events.RenewalSuccess, events.RenewalFailure,
+	}, types)
+
+	require.EqualError(t, batch.Err(results), "b: boom")
+}
+
+func TestNew_errors(t *testing.T) {
+	_, err := New(nil, func(_ context.Context, _ Certificate, _ challenge.Provider) error { return nil }, Config{})
+	require.EqualError(t, err, "renewal: the store is nil")
+
+	_, err = New(memoryStore{}, nil, Config{})
+	require.EqualError(t, err, "renewal: the renew function is nil")
+}
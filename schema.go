@@ -0,0 +1,298 @@
+package legotoolbox
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"lego-toolbox/internal/ipfamily"
+	"lego-toolbox/internal/provideralias"
+	"lego-toolbox/yamlconfig"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// ConfigSchema returns the JSON Schema of the YAML config of the provider,
+// generated from its Config struct (yaml tags, types and default values).
+// The optional `description` struct tag documents a field.
+func ConfigSchema(name string) ([]byte, error) {
+	providerName, _ := SplitProviderID(name)
+	providerName = provideralias.Resolve(providerName)
+
+	cfg, err := defaultDNSChallengeProviderConfig(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := structSchema(reflect.ValueOf(cfg))
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = providerName
+
+	if props, ok := schema["properties"].(map[string]any); ok {
+		if _, exists := props["debugHTTP"]; !exists {
+			props["debugHTTP"] = map[string]any{
+				"type":        "boolean",
+				"description": "Logs the HTTP calls to the provider API, with the secrets redacted.",
+			}
+		}
+
+		if _, exists := props["ipFamily"]; !exists {
+			props["ipFamily"] = map[string]any{
+				"type":        "string",
+				"enum":        []string{string(ipfamily.Auto), string(ipfamily.IPv4), string(ipfamily.IPv6)},
+				"description": "Restricts the connections to the provider API to an IP family, e.g. ipv6 on the IPv6-only hosts.",
+			}
+		}
+
+		if _, exists := props["waitNameservers"]; !exists {
+			wait := structSchema(reflect.ValueOf(NameserversWait{}))
+			wait["description"] = "Waits for the authoritative nameservers of the zone to serve the record."
+			props["waitNameservers"] = wait
+		}
+
+		if _, exists := props["secondaries"]; !exists {
+			secondaries := structSchema(reflect.ValueOf(StaticSecondaries{}))
+			secondaries["description"] = "Waits for the static secondary nameservers, transferring the zone from the primary, to serve the record."
+			props["secondaries"] = secondaries
+		}
+
+		if _, exists := props["adaptiveTimeout"]; !exists {
+			adaptive := structSchema(reflect.ValueOf(AdaptiveTimeout{}))
+			adaptive["description"] = "Derives the propagation timeout from the p95 of the measured propagation latencies, bounded by min and max."
+			props["adaptiveTimeout"] = adaptive
+		}
+
+		if _, exists := props["domainAliases"]; !exists {
+			props["domainAliases"] = map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+				"description":          "Publishes the challenges of the domains at their alias, the target of the existing `_acme-challenge` CNAME.",
+			}
+		}
+
+		if _, exists := props["stateFile"]; !exists {
+			props["stateFile"] = map[string]any{
+				"type":        "string",
+				"description": "Persists the state of the presented challenges (e.g. the record IDs), so they can be cleaned up by another process or after a restart.",
+			}
+		}
+
+		if _, exists := props["skipCleanup"]; !exists {
+			props["skipCleanup"] = map[string]any{
+				"type":        "boolean",
+				"description": "Leaves the TXT records in place after the validation, for the providers with very slow APIs.",
+			}
+		}
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func structSchema(v reflect.Value) map[string]any {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+
+		v = v.Elem()
+	}
+
+	props := map[string]any{}
+	addStructProperties(props, v)
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+func addStructProperties(props map[string]any, v reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, inline, skip := yamlconfig.FieldName(field)
+		if skip {
+			continue
+		}
+
+		value := v.Field(i)
+
+		if inline {
+			for value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					value = reflect.Zero(value.Type().Elem())
+					continue
+				}
+				value = value.Elem()
+			}
+
+			if value.Kind() == reflect.Struct {
+				addStructProperties(props, value)
+			}
+
+			continue
+		}
+
+		schema := valueSchema(field.Type, value)
+		if schema == nil {
+			continue
+		}
+
+		if desc := field.Tag.Get("description"); desc != "" {
+			schema["description"] = desc
+		}
+
+		props[name] = schema
+	}
+}
+
+func valueSchema(t reflect.Type, v reflect.Value) map[string]any {
+	if t == durationType {
+		schema := map[string]any{
+			"type":        []string{"string", "integer"},
+			"pattern":     `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`,
+			"description": "Duration string (10m, 90s) or number of seconds.",
+		}
+		if v.IsValid() && v.Int() != 0 {
+			schema["default"] = time.Duration(v.Int()).String()
+		}
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		var elem reflect.Value
+		if v.IsValid() && !v.IsNil() {
+			elem = v.Elem()
+		}
+		return valueSchema(t.Elem(), elem)
+
+	case reflect.String:
+		return withDefault(map[string]any{"type": "string"}, v, v.IsValid() && v.String() != "")
+
+	case reflect.Bool:
+		return withDefault(map[string]any{"type": "boolean"}, v, v.IsValid() && v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return withDefault(map[string]any{"type": "integer"}, v, v.IsValid() && !v.IsZero())
+
+	case reflect.Float32, reflect.Float64:
+		return withDefault(map[string]any{"type": "number"}, v, v.IsValid() && !v.IsZero())
+
+	case reflect.Slice, reflect.Array:
+		items := valueSchema(t.Elem(), reflect.Value{})
+		if items == nil {
+			return nil
+		}
+		return map[string]any{"type": "array", "items": items}
+
+	case reflect.Map:
+		values := valueSchema(t.Elem(), reflect.Value{})
+		if values == nil {
+			return nil
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}
+
+	case reflect.Struct:
+		if t == urlType {
+			return map[string]any{"type": "string", "format": "uri"}
+		}
+
+		if !v.IsValid() {
+			v = reflect.Zero(t)
+		}
+		return structSchema(v)
+
+	case reflect.Interface:
+		return map[string]any{}
+
+	default:
+		// Functions, channels: not representable in YAML.
+		return nil
+	}
+}
+
+func withDefault(schema map[string]any, v reflect.Value, set bool) map[string]any {
+	if set {
+		schema["default"] = v.Interface()
+	}
+
+	return schema
+}
+
+// defaultConfigTemple generates the YAML template of the providers without a hand-written one,
+// from the schema of their config.
+func defaultConfigTemple(name string) ([]byte, error) {
+	cfg, err := defaultDNSChallengeProviderConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	node := templeNode(structSchema(reflect.ValueOf(cfg)))
+	node.HeadComment = "config.yaml"
+
+	return yaml.Marshal(node)
+}
+
+func templeNode(schema map[string]any) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	props, _ := schema["properties"].(map[string]any)
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, _ := props[name].(map[string]any)
+
+		value := templeValue(prop)
+		if desc, ok := prop["description"].(string); ok {
+			value.LineComment = desc
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+
+	return node
+}
+
+func templeValue(prop map[string]any) *yaml.Node {
+	if def, ok := prop["default"]; ok {
+		value := &yaml.Node{}
+		_ = value.Encode(def)
+
+		return value
+	}
+
+	switch prop["type"] {
+	case "object":
+		if _, ok := prop["properties"]; ok {
+			return templeNode(prop)
+		}
+		return &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	case "array":
+		return &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	case "integer", "number":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+	case "boolean":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "", Style: yaml.DoubleQuotedStyle}
+	}
+}
@@ -0,0 +1,59 @@
+package legotoolbox
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSchema(t *testing.T) {
+	raw, err := ConfigSchema("cloudflare@personal")
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(raw, &schema))
+
+	assert.Equal(t, "cloudflare", schema["title"])
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	props, ok := schema["properties"].(map[string]any)
+	require.True(t, ok)
+
+	assert.Contains(t, props, "authToken")
+	assert.Contains(t, props, "debugHTTP")
+	assert.NotContains(t, props, "HTTPClient")
+	assert.NotContains(t, props, "httpclient")
+
+	ttl, ok := props["ttl"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "integer", ttl["type"])
+	assert.InDelta(t, 120, ttl["default"], 0)
+
+	timeout, ok := props["propagationTimeout"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{"string", "integer"}, timeout["type"])
+	assert.Equal(t, "2m0s", timeout["default"])
+}
+
+func TestConfigSchema_allProviders(t *testing.T) {
+	for _, info := range ListProviders(WithYAMLConfigSupport()) {
+		name := info.Name
+
+		t.Run(name, func(t *testing.T) {
+			raw, err := ConfigSchema(name)
+			require.NoError(t, err)
+			assert.True(t, json.Valid(raw))
+		})
+	}
+}
+
+func TestConfigSchema_errors(t *testing.T) {
+	_, err := ConfigSchema("unknown")
+	require.EqualError(t, err, "unrecognized DNS provider: unknown")
+
+	_, err = ConfigSchema("lightsail")
+	require.EqualError(t, err, "lightsail: the provider is only configured from the environment")
+}
@@ -0,0 +1,145 @@
+package legotoolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"lego-toolbox/internal/jobs"
+	"lego-toolbox/propagation"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+const defaultSecondariesQueryTimeout = 5 * time.Second
+
+// secondariesConfig is the part of the provider configs waiting for static secondary nameservers.
+//
+//	secondaries:
+//	  primary: 192.0.2.53
+//	  servers: [198.51.100.53, ns2.example.net:5353]
+//	  notify: true
+type secondariesConfig struct {
+	Secondaries *StaticSecondaries `yaml:"secondaries"`
+}
+
+// StaticSecondaries configures WithSecondaries.
+type StaticSecondaries struct {
+	// Servers are the secondary nameservers (host[:port]).
+	Servers []string `yaml:"servers"`
+	// Primary is the primary nameserver (host[:port]) of the provider:
+	// the secondaries must reach its SOA serial, the serials are not compared when empty.
+	Primary string `yaml:"primary"`
+	// Notify sends a DNS NOTIFY of the zone to the secondaries once the record is created,
+	// to trigger the zone transfer without waiting for the refresh interval of the zone.
+	Notify bool `yaml:"notify"`
+	// QueryTimeout bounds each query, 5s when zero.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+}
+
+// WithSecondaries makes Present wait until the static secondary nameservers serve the record:
+// the provider manages the primary, and the secondaries (e.g. of another vendor) transfer the zone from it.
+// A secondary is up to date once its SOA serial reaches the serial of the primary (AXFR or IXFR done) and it serves the record,
+// so the validations of the CA do not hit a secondary which is not up to date.
+// The wait is bounded by the propagation timeout of the provider.
+// The timing and sequential behavior of the provider are preserved.
+func WithSecondaries(provider challenge.Provider, config StaticSecondaries) challenge.Provider {
+	timeout := config.QueryTimeout
+	if timeout <= 0 {
+		timeout = defaultSecondariesQueryTimeout
+	}
+
+	return decorate(&secondariesProvider{
+		Provider: provider,
+		secondaries: &propagation.Secondaries{
+			Servers: config.Servers,
+			Primary: config.Primary,
+			Timeout: timeout,
+		},
+		notify: config.Notify,
+	})
+}
+
+// withSecondaries wraps the provider with WithSecondaries when `secondaries` is set in the provider config.
+func withSecondaries(rawConfig []byte, provider challenge.Provider) (challenge.Provider, error) {
+	var config secondariesConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("secondaries: %w", err)
+	}
+
+	if config.Secondaries == nil {
+		return provider, nil
+	}
+
+	if len(config.Secondaries.Servers) == 0 {
+		return nil, errors.New("secondaries: missing servers")
+	}
+
+	return WithSecondaries(provider, *config.Secondaries), nil
+}
+
+type secondariesProvider struct {
+	challenge.Provider
+
+	secondaries *propagation.Secondaries
+	notify      bool
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge,
+// and waits for the secondary nameservers to serve it.
+func (p *secondariesProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge,
+// and waits for the secondary nameservers to serve it, until ctx is done.
+func (p *secondariesProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := PresentContext(ctx, p.Provider, domain, token, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	info := rawrecord.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := p.secondaries.Zone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("secondaries: zone of %s: %w", info.EffectiveFQDN, err)
+	}
+
+	if p.notify {
+		// best effort: the secondaries transfer the zone at its refresh interval anyway.
+		if err := p.secondaries.Notify(ctx, zone); err != nil {
+			log.Warnf("secondaries: notify %s: %v", zone, err)
+		}
+	}
+
+	timeout, interval := providerTimeout(p.Provider)
+
+	_, err = jobs.Poll(ctx, jobs.DefaultOptions("secondary nameservers of "+info.EffectiveFQDN, timeout, interval), func(ctx context.Context) (jobs.Status, error) {
+		ok, err := p.secondaries.Check(ctx, zone, info.EffectiveFQDN, info.Value)
+
+		return jobs.Status{Done: ok}, err
+	})
+
+	return err
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *secondariesProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *secondariesProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *secondariesProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
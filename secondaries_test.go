@@ -0,0 +1,110 @@
+package legotoolbox
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/rawrecord"
+)
+
+func TestWithSecondaries(t *testing.T) {
+	inner, err := fake.NewDNSProviderConfig(fake.DefaultConfig())
+	require.NoError(t, err)
+
+	provider, err := withSecondaries([]byte("propagationTimeout: 10s\n"), inner)
+	require.NoError(t, err)
+	assert.Same(t, inner, provider)
+
+	provider, err = withSecondaries([]byte("secondaries:\n  primary: 192.0.2.1\n  servers: [192.0.2.53]\n  notify: true\n"), inner)
+	require.NoError(t, err)
+
+	s, ok := provider.(*sequentialDecorator)
+	require.True(t, ok)
+
+	wrapped, ok := s.decorator.(*secondariesProvider)
+	require.True(t, ok)
+	assert.Equal(t, []string{"192.0.2.53"}, wrapped.secondaries.Servers)
+	assert.Equal(t, "192.0.2.1", wrapped.secondaries.Primary)
+	assert.Equal(t, defaultSecondariesQueryTimeout, wrapped.secondaries.Timeout)
+	assert.True(t, wrapped.notify)
+	assert.Same(t, inner, Unwrap(provider))
+
+	_, err = withSecondaries([]byte("secondaries:\n  primary: 192.0.2.1\n"), inner)
+	require.EqualError(t, err, "secondaries: missing servers")
+
+	_, err = withSecondaries([]byte("secondaries: true\n"), inner)
+	require.Error(t, err)
+}
+
+func TestParseConfigStrict_secondaries(t *testing.T) {
+	_, err := ParseConfigStrict("fake", []byte("secondaries:\n  servers: [192.0.2.53]\n"))
+	require.NoError(t, err)
+}
+
+func TestSecondariesProvider_Present(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	config := fake.DefaultConfig()
+	config.PropagationTimeout = 5 * time.Second
+	config.PollingInterval = 50 * time.Millisecond
+
+	inner, err := fake.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	// the secondary serves the records of the fake provider.
+	server := startFakeZoneServer(t, inner)
+
+	provider := WithSecondaries(inner, StaticSecondaries{Servers: []string{server}, QueryTimeout: time.Second})
+
+	err = provider.Present("example.com", "token", rawrecord.KeyAuth("", "value"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"value"}, inner.TXT("_acme-challenge.example.com."))
+}
+
+// startFakeZoneServer starts a DNS server authoritative for example.com, serving the TXT records of the provider.
+func startFakeZoneServer(t *testing.T, provider *fake.DNSProvider) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		q := req.Question[0]
+
+		soa := &dns.SOA{
+			Hdr:  dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET},
+			Ns:   "ns1.example.com.",
+			Mbox: "admin.example.com.",
+		}
+
+		if values := provider.TXT(q.Name); q.Qtype == dns.TypeTXT && len(values) > 0 {
+			m.Answer = append(m.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET},
+				Txt: values,
+			})
+		} else {
+			m.Ns = append(m.Ns, soa)
+		}
+
+		_ = w.WriteMsg(m)
+	})}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	go func() { _ = server.ActivateAndServe() }()
+	<-started
+
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
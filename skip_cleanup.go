@@ -0,0 +1,75 @@
+package legotoolbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"lego-toolbox/rawrecord"
+	"lego-toolbox/yamlconfig"
+)
+
+// skipCleanupConfig is the part of the provider configs leaving the TXT records in place.
+//
+//	skipCleanup: true
+type skipCleanupConfig struct {
+	SkipCleanup bool `yaml:"skipCleanup"`
+}
+
+// WithSkipCleanup makes CleanUp a no-op: the TXT records of the challenges are left in place.
+// It halves the runtime of the renewal batches with the providers having very slow APIs (e.g. Loopia),
+// the stale `_acme-challenge` records are removed later, out of the renewals.
+// The validation of the next challenges is not affected: the CA accepts any of the TXT records of the name.
+// The timing and sequential behavior of the provider are preserved.
+func WithSkipCleanup(provider challenge.Provider) challenge.Provider {
+	return decorate(&skipCleanupProvider{Provider: provider})
+}
+
+// withSkipCleanup wraps the provider with WithSkipCleanup when `skipCleanup: true` is set in the provider config.
+func withSkipCleanup(rawConfig []byte, provider challenge.Provider) (challenge.Provider, error) {
+	var config skipCleanupConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("skipCleanup: %w", err)
+	}
+
+	if !config.SkipCleanup {
+		return provider, nil
+	}
+
+	return WithSkipCleanup(provider), nil
+}
+
+type skipCleanupProvider struct {
+	challenge.Provider
+}
+
+// CleanUp leaves the TXT record in place.
+func (p *skipCleanupProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *skipCleanupProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUpContext leaves the TXT record in place.
+func (p *skipCleanupProvider) CleanUpContext(_ context.Context, domain, _, keyAuth string) error {
+	log.Infof("[%s] skipCleanup: the TXT record %s is left in place", domain, rawrecord.GetChallengeInfo(domain, keyAuth).EffectiveFQDN)
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *skipCleanupProvider) Timeout() (timeout, interval time.Duration) {
+	return providerTimeout(p.Provider)
+}
+
+// Unwrap returns the wrapped provider.
+func (p *skipCleanupProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
@@ -0,0 +1,56 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/fake"
+	"lego-toolbox/quota"
+	"lego-toolbox/rawrecord"
+)
+
+func TestWithSkipCleanup(t *testing.T) {
+	inner := &recordingProvider{}
+
+	provider := WithSkipCleanup(WithTimeouts(inner, time.Minute, time.Second))
+
+	require.NoError(t, provider.Present("example.com", "token", rawrecord.KeyAuth("", "a")))
+	require.NoError(t, provider.CleanUp("example.com", "token", rawrecord.KeyAuth("", "a")))
+
+	assert.Equal(t, []string{"_acme-challenge.example.com. a"}, inner.presented)
+	assert.Empty(t, inner.cleaned)
+
+	timeout, interval := providerTimeout(provider)
+	assert.Equal(t, time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+
+	assert.Same(t, inner, Unwrap(provider))
+}
+
+func TestNewDNSChallengeProviderByName_skipCleanup(t *testing.T) {
+	tracker, err := quota.NewTracker("", map[string]quota.Limit{"fake": {Requests: 1, Window: time.Hour}})
+	require.NoError(t, err)
+
+	SetQuotaTracker(tracker)
+	t.Cleanup(func() { SetQuotaTracker(nil) })
+
+	provider, err := NewDNSChallengeProviderByName("fake", []byte("skipCleanup: true\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	// the skipped cleanup does not exceed the quota.
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	inner, ok := Unwrap(provider).(*fake.DNSProvider)
+	require.True(t, ok)
+
+	inner.AssertCallCount(t, fake.CallPresent, 1)
+	inner.AssertCallCount(t, fake.CallCleanUp, 0)
+}
+
+func TestParseConfigStrict_skipCleanup(t *testing.T) {
+	_, err := ParseConfigStrict("fake", []byte("skipCleanup: true\n"))
+	require.NoError(t, err)
+}
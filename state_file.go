@@ -0,0 +1,82 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"lego-toolbox/yamlconfig"
+)
+
+// ErrStateless is returned by SetStateFile for the providers without state:
+// their CleanUp finds the records by name and value, from any process.
+var ErrStateless = errors.New("the provider keeps no state of the challenges")
+
+// stateFileConfig is the part of the provider configs persisting the state of the challenges.
+//
+//	stateFile: /var/lib/lego-toolbox/cloudflare.json
+type stateFileConfig struct {
+	StateFile string `yaml:"stateFile"`
+}
+
+// StatefulProvider is implemented by the providers keeping a state of the presented challenges for their cleanup
+// (e.g. the IDs of the created records), in memory by default.
+type StatefulProvider interface {
+	// SetStateFile persists the state to path,
+	// so the challenges can be cleaned up by another process, or after a restart.
+	SetStateFile(path string) error
+}
+
+// SetStateFile persists the state of the challenges of the provider, possibly decorated, to path.
+// It returns ErrStateless when the provider keeps no state.
+func SetStateFile(provider challenge.Provider, path string) error {
+	for {
+		if p, ok := provider.(StatefulProvider); ok {
+			return p.SetStateFile(path)
+		}
+
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return ErrStateless
+		}
+
+		provider = u.Unwrap()
+	}
+}
+
+// IsStateful reports whether the provider, possibly decorated, keeps a state of the challenges.
+func IsStateful(provider challenge.Provider) bool {
+	for {
+		if _, ok := provider.(StatefulProvider); ok {
+			return true
+		}
+
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return false
+		}
+
+		provider = u.Unwrap()
+	}
+}
+
+// withStateFile persists the state of the challenges of the provider when `stateFile` is set in the provider config.
+func withStateFile(rawConfig []byte, provider challenge.Provider) error {
+	var config stateFileConfig
+
+	err := yamlconfig.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return fmt.Errorf("stateFile: %w", err)
+	}
+
+	if config.StateFile == "" {
+		return nil
+	}
+
+	err = SetStateFile(provider, config.StateFile)
+	if err != nil {
+		return fmt.Errorf("stateFile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package legotoolbox
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOPNsenseServer returns the base URL of a fake OPNsense API, and the UUIDs of the deleted host overrides.
+func newOPNsenseServer(t *testing.T) (string, func() []string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/unbound/settings/addHostOverride", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"result":"saved","uuid":"0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08"}`)
+	})
+
+	mux.HandleFunc("POST /api/unbound/settings/delHostOverride/{uuid}", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		deleted = append(deleted, req.PathValue("uuid"))
+		mu.Unlock()
+
+		_, _ = fmt.Fprint(rw, `{"result":"deleted"}`)
+	})
+
+	mux.HandleFunc("POST /api/unbound/service/reconfigure", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"status":"ok"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return deleted
+	}
+}
+
+func TestNewDNSChallengeProviderByName_stateFile(t *testing.T) {
+	baseURL, deleted := newOPNsenseServer(t)
+
+	rawConfig := []byte(fmt.Sprintf("baseURL: %s\napiKey: key\napiSecret: secret\nstateFile: %s\n",
+		baseURL, filepath.Join(t.TempDir(), "opnsense.json")))
+
+	presenter, err := NewDNSChallengeProviderByName("opnsense", rawConfig)
+	require.NoError(t, err)
+
+	require.NoError(t, presenter.Present("example.com", "token", "keyAuth"))
+
+	// another process.
+	cleaner, err := NewDNSChallengeProviderByName("opnsense", rawConfig)
+	require.NoError(t, err)
+
+	require.NoError(t, cleaner.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, []string{"0f1d4c2e-8a5b-4d6e-9c3a-2b7e5f1a9d08"}, deleted())
+}
+
+func TestNewDNSChallengeProviderByName_stateFile_stateless(t *testing.T) {
+	_, err := NewDNSChallengeProviderByName("fake", []byte("stateFile: /tmp/fake.json\n"))
+	require.ErrorIs(t, err, ErrStateless)
+}
+
+func TestParseConfigStrict_stateFile(t *testing.T) {
+	_, err := ParseConfigStrict("opnsense", []byte("baseURL: https://opnsense.example.com\napiKey: key\napiSecret: secret\nstateFile: /tmp/opnsense.json\n"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,59 @@
+package legotoolbox
+
+import (
+	"errors"
+	"fmt"
+
+	"lego-toolbox/internal/provideralias"
+	"lego-toolbox/yamlconfig"
+)
+
+// commonConfigKeys are the keys accepted by every provider config,
+// see withHTTPClient, withStateFile, withAdaptiveTimeout, withWaitNameservers, withSecondaries, withDomainAliases and withSkipCleanup.
+var commonConfigKeys = []string{"adaptiveTimeout", "debugHTTP", "domainAliases", "ipFamily", "secondaries", "skipCleanup", "stateFile", "waitNameservers"}
+
+// ParseConfigStrict parses the YAML config of the provider like its ParseConfig,
+// but rejects the keys unknown to the provider (e.g. `apikey:` instead of `apiKey:`),
+// and the HTTP timeouts shorter than 1s (see normalizeDurations).
+// The name may carry an instance suffix.
+// The config of the providers configured only from the environment is nil, and must be empty.
+func ParseConfigStrict(name string, rawConfig []byte) (any, error) {
+	providerName, _ := SplitProviderID(name)
+	providerName = provideralias.Resolve(providerName)
+
+	cfg, err := defaultDNSChallengeProviderConfig(providerName)
+	if errors.Is(err, errEnvOnlyProvider) {
+		err = yamlconfig.UnmarshalStrict(rawConfig, &struct{}{}, commonConfigKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", providerName, errEnvOnlyProvider, err)
+		}
+
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = yamlconfig.UnmarshalStrict(rawConfig, cfg, commonConfigKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", providerName, err)
+	}
+
+	cfg, err = newDNSChallengeProviderConfig(providerName, rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, issue := range normalizeDurations(cfg) {
+		if issue.Normalized == 0 {
+			errs = append(errs, errors.New(issue.String()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %w", providerName, errors.Join(errs...))
+	}
+
+	return cfg, nil
+}
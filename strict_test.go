@@ -0,0 +1,87 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/cloudflare"
+)
+
+func TestParseConfigStrict(t *testing.T) {
+	raw := `
+authToken: secret
+ttl: 300
+propagationTimeout: 5m
+debugHTTP: true
+`
+
+	cfg, err := ParseConfigStrict("cloudflare@personal", []byte(raw))
+	require.NoError(t, err)
+
+	config, ok := cfg.(*cloudflare.Config)
+	require.True(t, ok)
+
+	assert.Equal(t, "secret", config.AuthToken)
+	assert.Equal(t, 300, config.TTL)
+	assert.Equal(t, 5*time.Minute, config.PropagationTimeout)
+}
+
+func TestParseConfigStrict_errors(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		name      string
+		rawConfig string
+		expected  string
+	}{
+		{
+			desc:      "unknown key",
+			name:      "cloudflare",
+			rawConfig: "apitoken: secret\n",
+			expected:  "cloudflare: yaml: unmarshal errors:\n  line 1: field apitoken not found in type cloudflare.Config",
+		},
+		{
+			desc:      "env only provider with config",
+			name:      "lightsail",
+			rawConfig: "project: foo\n",
+			expected:  "lightsail: the provider is only configured from the environment: yaml: unmarshal errors:\n  line 1: field project not found in type struct {}",
+		},
+		{
+			desc:     "unknown provider",
+			name:     "unknown",
+			expected: "unrecognized DNS provider: unknown",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseConfigStrict(test.name, []byte(test.rawConfig))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestParseConfigStrict_envOnly(t *testing.T) {
+	cfg, err := ParseConfigStrict("lightsail", nil)
+	require.NoError(t, err)
+
+	assert.Nil(t, cfg)
+}
+
+func TestParseBundleEntries_strict(t *testing.T) {
+	raw := `
+strict: true
+providers:
+  cf:
+    type: cloudflare
+    apikey: secret
+`
+
+	_, err := ParseBundleEntries([]byte(raw))
+	require.ErrorContains(t, err, `bundle: provider "cf": cloudflare: yaml: unmarshal errors:`)
+	require.ErrorContains(t, err, "field apikey not found in type cloudflare.Config")
+
+	_, err = ParseBundleEntries([]byte(raw[len("\nstrict: true"):]))
+	require.NoError(t, err)
+}
@@ -0,0 +1,161 @@
+package toolboxtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+	"lego-toolbox/providers/dns/memdns"
+)
+
+// Options configures an issuance.
+type Options struct {
+	// Provider solves the DNS-01 challenges.
+	// When nil, an in-memory memdns provider is started and used as the Pebble resolver.
+	Provider challenge.Provider
+	// Resolver is the DNS server used by Pebble to validate the challenges (host:port).
+	// Defaults to the memdns address when Provider is nil, to the system resolver otherwise.
+	Resolver string
+	// Nameservers are the recursive nameservers used by the propagation pre-check.
+	// Defaults to Resolver.
+	Nameservers []string
+	// Domains to issue the certificate for, defaults to example.com and *.example.com.
+	Domains []string
+	// KeyType is the certificate key type, defaults to EC256.
+	KeyType certcrypto.KeyType
+	// Timeout bounds the whole issuance, defaults to 2 minutes.
+	Timeout time.Duration
+	// Pebble configures the Pebble server, its Resolver is set from Resolver.
+	Pebble PebbleOptions
+}
+
+// Result is the outcome of a successful issuance.
+type Result struct {
+	Resource    *certificate.Resource
+	Certificate *x509.Certificate
+	Pebble      *Pebble
+}
+
+type user struct {
+	key          crypto.PrivateKey
+	registration *registration.Resource
+}
+
+func (u *user) GetEmail() string                        { return "toolboxtest@example.com" }
+func (u *user) GetRegistration() *registration.Resource { return u.registration }
+func (u *user) GetPrivateKey() crypto.PrivateKey        { return u.key }
+
+// Issue starts Pebble, wires the provider and runs a full DNS-01 issuance.
+// The test fails if the issuance does not succeed.
+func Issue(t testing.TB, opts Options) *Result {
+	t.Helper()
+
+	if opts.Provider == nil {
+		provider, err := memdns.NewDNSProviderConfig(memdns.DefaultConfig())
+		if err != nil {
+			t.Fatalf("toolboxtest: %v", err)
+		}
+		t.Cleanup(func() { _ = provider.Close() })
+
+		opts.Provider = provider
+		if opts.Resolver == "" {
+			opts.Resolver = provider.Addr()
+		}
+	}
+
+	if len(opts.Nameservers) == 0 && opts.Resolver != "" {
+		opts.Nameservers = []string{opts.Resolver}
+	}
+
+	if len(opts.Domains) == 0 {
+		opts.Domains = []string{"example.com", "*.example.com"}
+	}
+
+	if opts.KeyType == "" {
+		opts.KeyType = certcrypto.EC256
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+
+	opts.Pebble.Resolver = opts.Resolver
+	pebble := StartPebble(t, opts.Pebble)
+
+	client := newClient(t, pebble, opts)
+
+	err := client.Challenge.SetDNS01Provider(opts.Provider,
+		dns01.CondOption(len(opts.Nameservers) > 0, dns01.AddRecursiveNameservers(opts.Nameservers)),
+		dns01.CondOption(len(opts.Nameservers) > 0, dns01.DisableCompletePropagationRequirement()),
+	)
+	if err != nil {
+		t.Fatalf("toolboxtest: set DNS-01 provider: %v", err)
+	}
+
+	type outcome struct {
+		resource *certificate.Resource
+		err      error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		resource, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: opts.Domains, Bundle: true})
+		done <- outcome{resource: resource, err: err}
+	}()
+
+	var res outcome
+	select {
+	case res = <-done:
+	case <-time.After(opts.Timeout):
+		t.Fatalf("toolboxtest: issuance timed out after %s", opts.Timeout)
+	}
+
+	if res.err != nil {
+		t.Fatalf("toolboxtest: obtain certificate: %v", res.err)
+	}
+
+	cert, err := certcrypto.ParsePEMCertificate(res.resource.Certificate)
+	if err != nil {
+		t.Fatalf("toolboxtest: parse certificate: %v", err)
+	}
+
+	return &Result{Resource: res.resource, Certificate: cert, Pebble: pebble}
+}
+
+func newClient(t testing.TB, pebble *Pebble, opts Options) *lego.Client {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("toolboxtest: generate account key: %v", err)
+	}
+
+	u := &user{key: key}
+
+	config := lego.NewConfig(u)
+	config.CADirURL = pebble.DirectoryURL()
+	config.HTTPClient = pebble.HTTPClient()
+	config.Certificate.KeyType = opts.KeyType
+
+	client, err := lego.NewClient(config)
+	if err != nil {
+		t.Fatalf("toolboxtest: create ACME client: %v", err)
+	}
+
+	u.registration, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	if err != nil {
+		t.Fatalf("toolboxtest: register account: %v", err)
+	}
+
+	return client
+}
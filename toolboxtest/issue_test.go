@@ -0,0 +1,15 @@
+package toolboxtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue(t *testing.T) {
+	result := Issue(t, Options{})
+
+	assert.ElementsMatch(t, []string{"example.com", "*.example.com"}, result.Certificate.DNSNames)
+	assert.NotEmpty(t, result.Resource.PrivateKey)
+	assert.NotEmpty(t, result.Pebble.RootCertificate())
+}
@@ -0,0 +1,104 @@
+// Package toolboxtest runs an in-process Pebble ACME server and performs full DNS-01 issuances against it,
+// so providers can be validated end-to-end with one function call.
+package toolboxtest
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/letsencrypt/pebble/v2/ca"
+	"github.com/letsencrypt/pebble/v2/db"
+	"github.com/letsencrypt/pebble/v2/va"
+	"github.com/letsencrypt/pebble/v2/wfe"
+)
+
+// Pebble environment variables names.
+const (
+	envPebbleNoSleep     = "PEBBLE_VA_NOSLEEP"
+	envPebbleNonceReject = "PEBBLE_WFE_NONCEREJECT"
+)
+
+// PebbleOptions configures the Pebble server.
+type PebbleOptions struct {
+	// Resolver is the DNS server used by the Pebble VA to check the challenges.
+	// The system resolver is used when empty.
+	Resolver string
+	// Logger receives the Pebble logs, discarded when nil.
+	Logger *log.Logger
+	// RequireEAB enables the external account binding requirement.
+	RequireEAB bool
+	// EABKeys are the external account binding keys (key ID to base64url HMAC).
+	EABKeys map[string]string
+}
+
+// Pebble is an in-process Pebble ACME server.
+type Pebble struct {
+	server *httptest.Server
+	ca     *ca.CAImpl
+}
+
+// StartPebble starts an in-process Pebble server, stopped at the end of the test.
+func StartPebble(t testing.TB, opts PebbleOptions) *Pebble {
+	t.Helper()
+
+	// Random VA sleeps and bad nonces only slow the tests down.
+	setDefaultEnv(t, envPebbleNoSleep, "1")
+	setDefaultEnv(t, envPebbleNonceReject, "0")
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+
+	store := db.NewMemoryStore()
+
+	for keyID, key := range opts.EABKeys {
+		err := store.AddExternalAccountKeyByID(keyID, key)
+		if err != nil {
+			t.Fatalf("toolboxtest: add EAB key %q: %v", keyID, err)
+		}
+	}
+
+	authority := ca.New(logger, store, "", 0, 1, 0)
+	validation := va.New(logger, 0, 0, false, opts.Resolver, store)
+
+	frontEnd := wfe.New(logger, store, validation, authority, false, opts.RequireEAB, 0, 0)
+
+	server := httptest.NewTLSServer(frontEnd.Handler())
+	t.Cleanup(server.Close)
+
+	return &Pebble{server: server, ca: authority}
+}
+
+// DirectoryURL returns the ACME directory URL.
+func (p *Pebble) DirectoryURL() string {
+	return p.server.URL + wfe.DirectoryPath
+}
+
+// HTTPClient returns an HTTP client trusting the Pebble TLS certificate.
+func (p *Pebble) HTTPClient() *http.Client {
+	return p.server.Client()
+}
+
+// TLSCertificate returns the PEM encoded certificate of the Pebble TLS server, e.g. for a CA file.
+func (p *Pebble) TLSCertificate() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.server.Certificate().Raw})
+}
+
+// RootCertificate returns the PEM encoded root certificate of the Pebble CA.
+func (p *Pebble) RootCertificate() []byte {
+	return p.ca.GetRootCert(0).PEM()
+}
+
+func setDefaultEnv(t testing.TB, key, value string) {
+	if _, ok := os.LookupEnv(key); ok {
+		return
+	}
+
+	t.Setenv(key, value)
+}
@@ -7,8 +7,10 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/base64"
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/registration"
 	jsoniter "github.com/json-iterator/go"
@@ -49,6 +51,12 @@ type CertificateConfig struct {
 type LegoUser struct {
 	Account *LegoAccount
 	Client  *lego.Client
+	// CA is the ACME certificate authority, Let's Encrypt production when nil.
+	CA *CAConfig
+	// KeyPolicy configures the private keys of the certificates, a new key of CertificateConfig.EncType when nil.
+	KeyPolicy *KeyPolicy
+	// DNS01Options are the options of the DNS-01 challenge (e.g. the recursive nameservers of the propagation check).
+	DNS01Options []dns01.ChallengeOption
 }
 
 func NewUserFromAccount(acc *LegoAccount) *LegoUser {
@@ -67,6 +75,10 @@ func (l *LegoUser) GetRegistration() *registration.Resource {
 	}
 	return &reg
 }
+
+// GetPrivateKey returns the account key stored in Account.PrivateKey.
+// lego signs the ACME requests with in-memory *ecdsa.PrivateKey and *rsa.PrivateKey keys only,
+// an account key can't be held by a PKCS#11 token or a KMS.
 func (l *LegoUser) GetPrivateKey() crypto.PrivateKey {
 	decBytes, err := base64.StdEncoding.DecodeString(l.Account.PrivateKey)
 	if err != nil {
@@ -95,7 +107,13 @@ func (l *LegoUser) RegisterNewUser() error {
 	}
 
 	// New users will need to register
-	reg, err := l.Client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	var reg *registration.Resource
+	var err error
+	if l.CA != nil && l.CA.EAB != nil {
+		reg, err = l.Client.Registration.RegisterWithExternalAccountBinding(l.CA.EAB.RegisterOptions())
+	} else {
+		reg, err = l.Client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	}
 	if err != nil {
 		return err
 	}
@@ -116,25 +134,69 @@ func (l *LegoUser) QueryUserRegistration() (*registration.Resource, error) {
 }
 
 func (l *LegoUser) ObtainCertificate(certCfg *CertificateConfig, provider challenge.Provider) error {
+	encType, err := l.KeyPolicy.encType(certCfg.EncType)
+	if err != nil {
+		return err
+	}
+
 	if l.Client == nil {
-		err := l.NewClient(certCfg.EncType)
+		err = l.NewClient(encType)
 		if err != nil {
 			return err
 		}
 	}
 
-	err := l.Client.Challenge.SetDNS01Provider(provider)
+	err = ValidateDomains(provider, certCfg.SAN)
 	if err != nil {
 		return err
 	}
-	request := certificate.ObtainRequest{
-		Domains: certCfg.SAN,
-		Bundle:  true,
-	}
-	certificates, err := l.Client.Certificate.Obtain(request)
+
+	err = l.Client.Challenge.SetDNS01Provider(provider, l.DNS01Options...)
 	if err != nil {
 		return err
 	}
+
+	mustStaple := l.CA != nil && l.CA.MustStaple
+
+	var certificates *certificate.Resource
+	if l.KeyPolicy != nil && l.KeyPolicy.Signer != nil {
+		request, err := l.KeyPolicy.csrRequest(certCfg.SAN, mustStaple)
+		if err != nil {
+			return err
+		}
+
+		certificates, err = l.Client.Certificate.ObtainForCSR(request)
+		if err != nil {
+			return err
+		}
+	} else {
+		privateKey, err := l.KeyPolicy.privateKey(certCfg)
+		if err != nil {
+			return err
+		}
+
+		if privateKey == nil {
+			// the key type of the client is the one of its creation (e.g. EC384 by RegisterNewUser),
+			// the key is generated here to follow the key type of the certificate.
+			privateKey, err = certcrypto.GeneratePrivateKey(ConvertKeyType(encType))
+			if err != nil {
+				return err
+			}
+		}
+
+		request := certificate.ObtainRequest{
+			Domains:    certCfg.SAN,
+			Bundle:     true,
+			PrivateKey: privateKey,
+			MustStaple: mustStaple,
+		}
+
+		certificates, err = l.Client.Certificate.Obtain(request)
+		if err != nil {
+			return err
+		}
+	}
+
 	certCfg.CommonDomain = certificates.Domain
 	certCfg.CertURL = certificates.CertURL
 	certCfg.CertStableURL = certificates.CertStableURL
@@ -159,7 +221,19 @@ func (l *LegoUser) GeneratePrivateKey() error {
 }
 
 func (l *LegoUser) NewClient(KeyType EncType) error {
+	err := l.CA.validate()
+	if err != nil {
+		return err
+	}
+	httpClient, err := l.CA.httpClient()
+	if err != nil {
+		return err
+	}
 	config := lego.NewConfig(l)
+	config.CADirURL = l.CA.directoryURL()
+	if httpClient != nil {
+		config.HTTPClient = httpClient
+	}
 	config.Certificate.KeyType = ConvertKeyType(KeyType)
 	client, err := lego.NewClient(config)
 	if err != nil {
@@ -0,0 +1,84 @@
+package legotoolbox
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/memdns"
+	"lego-toolbox/toolboxtest"
+)
+
+func TestLegoUser_ObtainCertificate_keyPolicy(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		policy    *KeyPolicy
+		encType   EncType
+		assertKey func(t *testing.T, key any)
+	}{
+		{
+			desc:    "rsa 4096",
+			policy:  &KeyPolicy{Algorithm: KeyAlgorithmRSA, Size: 4096},
+			encType: EC256,
+			assertKey: func(t *testing.T, key any) {
+				t.Helper()
+
+				require.IsType(t, &rsa.PublicKey{}, key)
+				assert.Equal(t, 4096, key.(*rsa.PublicKey).N.BitLen())
+			},
+		},
+		{
+			desc:    "enc type without policy",
+			encType: EC256,
+			assertKey: func(t *testing.T, key any) {
+				t.Helper()
+
+				require.IsType(t, &ecdsa.PublicKey{}, key)
+				assert.Equal(t, elliptic.P256(), key.(*ecdsa.PublicKey).Curve)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider, err := memdns.NewDNSProviderConfig(memdns.DefaultConfig())
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = provider.Close() })
+
+			pebble := toolboxtest.StartPebble(t, toolboxtest.PebbleOptions{Resolver: provider.Addr()})
+
+			caFile := filepath.Join(t.TempDir(), "pebble.pem")
+			require.NoError(t, os.WriteFile(caFile, pebble.TLSCertificate(), 0o600))
+
+			user := &LegoUser{
+				Account:   &LegoAccount{Email: "test@example.com"},
+				CA:        &CAConfig{DirectoryURL: pebble.DirectoryURL(), CAFile: caFile},
+				KeyPolicy: test.policy,
+				DNS01Options: []dns01.ChallengeOption{
+					dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+					dns01.DisableCompletePropagationRequirement(),
+				},
+			}
+
+			// the client is created with EC384 by the registration.
+			require.NoError(t, user.RegisterNewUser())
+
+			certCfg := &CertificateConfig{SAN: []string{"example.com"}, EncType: test.encType}
+
+			require.NoError(t, user.ObtainCertificate(certCfg, provider))
+
+			cert, err := certcrypto.ParsePEMCertificate(certCfg.Certificate)
+			require.NoError(t, err)
+
+			test.assertKey(t, cert.PublicKey)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package legotoolbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// sequential is implemented by the providers resolving their challenges one at a time.
+type sequential interface {
+	Sequential() time.Duration
+}
+
+// Timing overrides the propagation timing of a provider.
+// Zero values keep the timing of the provider.
+type Timing struct {
+	PropagationTimeout time.Duration `yaml:"propagationTimeout"`
+	PollingInterval    time.Duration `yaml:"pollingInterval"`
+	SequenceInterval   time.Duration `yaml:"sequenceInterval"`
+}
+
+// IsZero reports whether the timing overrides nothing.
+func (t Timing) IsZero() bool {
+	return t == Timing{}
+}
+
+// ApplyTiming wraps the provider with the overridden timing.
+func ApplyTiming(provider challenge.Provider, timing Timing) challenge.Provider {
+	if timing.PropagationTimeout > 0 || timing.PollingInterval > 0 {
+		provider = WithTimeouts(provider, timing.PropagationTimeout, timing.PollingInterval)
+	}
+
+	if timing.SequenceInterval > 0 {
+		provider = WithSequential(provider, timing.SequenceInterval)
+	}
+
+	return provider
+}
+
+// WithTimeouts overrides the propagation timeout and polling interval of the provider,
+// even if it does not implement challenge.ProviderTimeout.
+// A zero value keeps the value of the provider (or the lego default).
+// The sequential behavior of the provider is preserved.
+func WithTimeouts(provider challenge.Provider, timeout, interval time.Duration) challenge.Provider {
+	currentTimeout, currentInterval := providerTimeout(provider)
+
+	if timeout <= 0 {
+		timeout = currentTimeout
+	}
+
+	if interval <= 0 {
+		interval = currentInterval
+	}
+
+	return decorate(&timingProvider{Provider: provider, timeout: timeout, interval: interval})
+}
+
+// WithSequential makes the provider resolve its challenges one at a time,
+// waiting interval between each of them.
+// The propagation timeout and polling interval of the provider are preserved.
+func WithSequential(provider challenge.Provider, interval time.Duration) challenge.Provider {
+	timeout, polling := providerTimeout(provider)
+
+	return &sequentialDecorator{
+		decorator: &timingProvider{Provider: provider, timeout: timeout, interval: polling},
+		sequence:  interval,
+	}
+}
+
+// Unwrap returns the provider wrapped by the decorators of this package, or the provider itself.
+func Unwrap(provider challenge.Provider) challenge.Provider {
+	for {
+		u, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return provider
+		}
+
+		provider = u.Unwrap()
+	}
+}
+
+func providerTimeout(provider challenge.Provider) (timeout, interval time.Duration) {
+	if p, ok := provider.(challenge.ProviderTimeout); ok {
+		return p.Timeout()
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+// decorator is a provider wrapping another one.
+// The context of the PresentContext and CleanUpContext calls is passed to the wrapped provider.
+type decorator interface {
+	challenge.ProviderTimeout
+	ContextProvider
+	Unwrap() challenge.Provider
+}
+
+// decorate returns the decorator, made sequential when the provider it wraps is sequential.
+func decorate(d decorator) challenge.Provider {
+	if s, ok := d.Unwrap().(sequential); ok {
+		return &sequentialDecorator{decorator: d, sequence: s.Sequential()}
+	}
+
+	return d
+}
+
+// sequentialDecorator adds the Sequential method to a decorator.
+type sequentialDecorator struct {
+	decorator
+
+	sequence time.Duration
+}
+
+// Sequential All DNS challenges for this provider will be resolved sequentially.
+// Returns the interval between each iteration.
+func (p *sequentialDecorator) Sequential() time.Duration {
+	return p.sequence
+}
+
+type timingProvider struct {
+	challenge.Provider
+
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// PresentContext creates a TXT record to fulfill the dns-01 challenge.
+func (p *timingProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	return PresentContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// CleanUpContext removes the TXT record matching the specified parameters.
+func (p *timingProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	return CleanUpContext(ctx, p.Provider, domain, token, keyAuth)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *timingProvider) Timeout() (timeout, interval time.Duration) {
+	return p.timeout, p.interval
+}
+
+// Unwrap returns the wrapped provider.
+func (p *timingProvider) Unwrap() challenge.Provider {
+	return p.Provider
+}
@@ -0,0 +1,92 @@
+package legotoolbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lego-toolbox/providers/dns/exec"
+)
+
+type basicProvider struct{}
+
+func (basicProvider) Present(_, _, _ string) error { return nil }
+func (basicProvider) CleanUp(_, _, _ string) error { return nil }
+
+func TestWithTimeouts(t *testing.T) {
+	provider := WithTimeouts(basicProvider{}, 10*time.Minute, 0)
+
+	timeout, interval := provider.(challenge.ProviderTimeout).Timeout()
+	assert.Equal(t, 10*time.Minute, timeout)
+	assert.Equal(t, dns01.DefaultPollingInterval, interval)
+
+	_, ok := provider.(sequential)
+	assert.False(t, ok)
+
+	assert.Equal(t, basicProvider{}, Unwrap(provider))
+}
+
+func TestWithTimeouts_preserveSequential(t *testing.T) {
+	config := exec.DefaultConfig()
+	config.SequenceInterval = 42 * time.Second
+
+	inner, err := exec.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider := WithTimeouts(inner, time.Minute, time.Second)
+
+	timeout, interval := provider.(challenge.ProviderTimeout).Timeout()
+	assert.Equal(t, time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+
+	s, ok := provider.(sequential)
+	require.True(t, ok)
+	assert.Equal(t, 42*time.Second, s.Sequential())
+
+	assert.Same(t, inner, Unwrap(provider))
+}
+
+func TestWithSequential(t *testing.T) {
+	provider := WithSequential(WithTimeouts(basicProvider{}, time.Hour, 0), 30*time.Second)
+
+	s, ok := provider.(sequential)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, s.Sequential())
+
+	timeout, _ := provider.(challenge.ProviderTimeout).Timeout()
+	assert.Equal(t, time.Hour, timeout)
+
+	assert.Equal(t, basicProvider{}, Unwrap(provider))
+}
+
+func TestParseBundle_timing(t *testing.T) {
+	raw := `
+providers:
+  slow:
+    type: exec
+    program: /usr/bin/true
+    timing:
+      propagationTimeout: 40m
+      sequenceInterval: 60
+`
+
+	entries, err := ParseBundleEntries([]byte(raw))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.NotContains(t, string(entries[0].RawConfig), "timing")
+
+	providers, err := ParseBundle([]byte(raw))
+	require.NoError(t, err)
+
+	provider := providers["slow"]
+
+	timeout, _ := provider.(challenge.ProviderTimeout).Timeout()
+	assert.Equal(t, 40*time.Minute, timeout)
+
+	s, ok := provider.(sequential)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, s.Sequential())
+}
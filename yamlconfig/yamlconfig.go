@@ -0,0 +1,305 @@
+// Package yamlconfig decodes the YAML configs of the DNS providers.
+//
+// The durations (time.Duration fields) accept Go duration strings ("10m", "90s", "1h30m")
+// and plain integers, read as seconds.
+package yamlconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyNanoseconds is the smallest integer read as nanoseconds instead of seconds.
+// Older configs used raw nanoseconds (600000000000 for 10 minutes),
+// no sane timeout is longer than 11 days or shorter than 1ms.
+const legacyNanoseconds = 1_000_000
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Duration is a time.Duration decoded from a duration string ("10m", "90s") or a number of seconds.
+type Duration time.Duration
+
+// Duration returns the time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	duration, err := ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+// ParseDuration parses a duration string ("10m", "90s") or a number of seconds ("600").
+// Integers from 1000000 are read as nanoseconds, for compatibility with older configs.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n >= legacyNanoseconds || n <= -legacyNanoseconds {
+			log.Warnf("yamlconfig: the duration %d is read as nanoseconds (%s), use a duration string instead", n, time.Duration(n))
+			return time.Duration(n), nil
+		}
+
+		return time.Duration(n) * time.Second, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: use a duration string (10m, 90s) or a number of seconds", s)
+	}
+
+	return d, nil
+}
+
+// Unmarshal decodes the YAML config into out, like yaml.Unmarshal,
+// with the durations parsed by ParseDuration.
+func Unmarshal(raw []byte, out any) error {
+	var node yaml.Node
+
+	err := yaml.Unmarshal(raw, &node)
+	if err != nil {
+		return err
+	}
+
+	if node.Kind == 0 {
+		// empty document
+		return nil
+	}
+
+	return Decode(&node, out)
+}
+
+// UnmarshalStrict decodes the YAML config into out like Unmarshal,
+// but fails on the keys not matching a field of out (yaml.v3 KnownFields).
+// The top-level ignored keys are allowed and skipped.
+func UnmarshalStrict(raw []byte, out any, ignored ...string) error {
+	var node yaml.Node
+
+	err := yaml.Unmarshal(raw, &node)
+	if err != nil {
+		return err
+	}
+
+	if node.Kind == 0 {
+		// empty document
+		return nil
+	}
+
+	removeKeys(&node, ignored)
+
+	err = normalizeDurations(&node, reflect.TypeOf(out))
+	if err != nil {
+		return err
+	}
+
+	normalized, err := yaml.Marshal(&node)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(normalized))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(out)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+// Decode decodes the YAML node into out, like (*yaml.Node).Decode,
+// with the durations parsed by ParseDuration.
+func Decode(node *yaml.Node, out any) error {
+	err := normalizeDurations(node, reflect.TypeOf(out))
+	if err != nil {
+		return err
+	}
+
+	return node.Decode(out)
+}
+
+// FieldName returns the key of the struct field in YAML, as yaml.v3 does.
+func FieldName(field reflect.StructField) (name string, inline, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			return "", true, false
+		}
+	}
+
+	if parts[0] != "" {
+		return parts[0], false, false
+	}
+
+	return strings.ToLower(field.Name), false, false
+}
+
+// normalizeDurations rewrites the scalars decoded into time.Duration values as duration strings.
+func normalizeDurations(node *yaml.Node, t reflect.Type) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if node == nil || t == nil {
+		return nil
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			if err := normalizeDurations(child, t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if t == durationType {
+		if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return nil
+		}
+
+		d, err := ParseDuration(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+
+		node.Value = d.String()
+		node.Tag = "!!str"
+		node.Style = 0
+
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		fields := structFields(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			ft, ok := fields[node.Content[i].Value]
+			if !ok {
+				continue
+			}
+
+			if err := normalizeDurations(node.Content[i+1], ft); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		for _, child := range node.Content {
+			if err := normalizeDurations(child, t.Elem()); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := normalizeDurations(node.Content[i], t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeKeys removes the keys from the top-level mapping of the document.
+func removeKeys(node *yaml.Node, keys []string) {
+	if len(keys) == 0 || node.Kind != yaml.DocumentNode || len(node.Content) == 0 {
+		return
+	}
+
+	mapping := node.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return
+	}
+
+	content := make([]*yaml.Node, 0, len(mapping.Content))
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if slices.Contains(keys, mapping.Content[i].Value) {
+			continue
+		}
+
+		content = append(content, mapping.Content[i], mapping.Content[i+1])
+	}
+
+	mapping.Content = content
+}
+
+// structFields returns the types of the fields of the struct by YAML key, inline fields included.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, inline, skip := FieldName(field)
+		if skip {
+			continue
+		}
+
+		if inline {
+			ft := field.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				for k, v := range structFields(ft) {
+					fields[k] = v
+				}
+			}
+
+			continue
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
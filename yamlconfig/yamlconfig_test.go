@@ -0,0 +1,135 @@
+package yamlconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "10m", expected: 10 * time.Minute},
+		{value: "90s", expected: 90 * time.Second},
+		{value: "1h30m", expected: 90 * time.Minute},
+		{value: "60", expected: time.Minute},
+		{value: " 0 ", expected: 0},
+		{value: "600000000000", expected: 10 * time.Minute},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			d, err := ParseDuration(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, d)
+		})
+	}
+}
+
+func TestParseDuration_error(t *testing.T) {
+	_, err := ParseDuration("ten minutes")
+	require.EqualError(t, err, `invalid duration "ten minutes": use a duration string (10m, 90s) or a number of seconds`)
+}
+
+type nested struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type inlined struct {
+	SequenceInterval time.Duration `yaml:"sequenceInterval"`
+}
+
+type config struct {
+	inlined `yaml:",inline"`
+
+	Name               string                   `yaml:"name"`
+	TTL                int                      `yaml:"ttl"`
+	PropagationTimeout time.Duration            `yaml:"propagationTimeout"`
+	PollingInterval    *time.Duration           `yaml:"pollingInterval"`
+	Retries            []time.Duration          `yaml:"retries"`
+	Zones              map[string]time.Duration `yaml:"zones"`
+	Nested             nested                   `yaml:"nested"`
+	Custom             Duration                 `yaml:"custom"`
+	Ignored            time.Duration            `yaml:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	raw := `
+name: "120"
+ttl: 120
+propagationTimeout: 120
+pollingInterval: 2s
+sequenceInterval: "30"
+retries: [1, 2m]
+zones:
+  example.com: 5
+nested:
+  timeout: 600000000000
+custom: 45
+`
+
+	cfg := &config{PropagationTimeout: time.Hour}
+
+	err := Unmarshal([]byte(raw), &cfg)
+	require.NoError(t, err)
+
+	pollingInterval := 2 * time.Second
+
+	expected := &config{
+		inlined:            inlined{SequenceInterval: 30 * time.Second},
+		Name:               "120",
+		TTL:                120,
+		PropagationTimeout: 2 * time.Minute,
+		PollingInterval:    &pollingInterval,
+		Retries:            []time.Duration{time.Second, 2 * time.Minute},
+		Zones:              map[string]time.Duration{"example.com": 5 * time.Second},
+		Nested:             nested{Timeout: 10 * time.Minute},
+		Custom:             Duration(45 * time.Second),
+	}
+
+	assert.Equal(t, expected, cfg)
+}
+
+func TestUnmarshal_empty(t *testing.T) {
+	cfg := config{PropagationTimeout: time.Hour}
+
+	err := Unmarshal(nil, &cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Hour, cfg.PropagationTimeout)
+}
+
+func TestUnmarshal_invalid(t *testing.T) {
+	var cfg config
+
+	err := Unmarshal([]byte("propagationTimeout: soon"), &cfg)
+	require.EqualError(t, err, `line 1: invalid duration "soon": use a duration string (10m, 90s) or a number of seconds`)
+}
+
+func TestDuration_MarshalYAML(t *testing.T) {
+	raw, err := yaml.Marshal(map[string]Duration{"timeout": Duration(90 * time.Second)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "timeout: 1m30s\n", string(raw))
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	var cfg config
+
+	err := UnmarshalStrict([]byte("propagationTimeout: 60\ndebug: true\n"), &cfg, "debug")
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Minute, cfg.PropagationTimeout)
+
+	err = UnmarshalStrict([]byte("propagationTimout: 60\n"), &cfg)
+	require.EqualError(t, err, "yaml: unmarshal errors:\n  line 1: field propagationTimout not found in type yamlconfig.config")
+
+	err = UnmarshalStrict(nil, &cfg)
+	require.NoError(t, err)
+}